@@ -6,6 +6,8 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v2"
 
+	"exusiai.dev/backend-next/cmd/app/cli/loadgen"
+	"exusiai.dev/backend-next/cmd/app/cli/migrate"
 	"exusiai.dev/backend-next/cmd/app/cli/runscript"
 	"exusiai.dev/backend-next/cmd/app/server"
 	"exusiai.dev/backend-next/internal/pkg/bininfo"
@@ -19,6 +21,8 @@ func Run() {
 		Commands: []*cli.Command{
 			server.Command(),
 			runscript.Command(),
+			migrate.Command(),
+			loadgen.Command(),
 		},
 	}
 	if err := app.Run(os.Args); err != nil {
@@ -0,0 +1,202 @@
+// Package loadgen generates synthetic reports for measuring the performance of the aggregations and the ingestion.
+// The reports are drawn from the drop infos of a server with a seeded generator, so that the same seed reproduces the
+// same data set and the same traffic: the stages are picked by a zipfian popularity, and each stage drops a few
+// patterns of its own, most often the first ones, within the bounds of its drop infos.
+package loadgen
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/fx"
+
+	cliapp "exusiai.dev/backend-next/cmd/app/cli"
+	"exusiai.dev/backend-next/internal/repo"
+	"exusiai.dev/backend-next/internal/service"
+)
+
+type CommandDeps struct {
+	fx.In
+
+	DB              *bun.DB
+	AccountService  *service.Account
+	StageService    *service.Stage
+	ItemService     *service.Item
+	DropInfoService *service.DropInfo
+	DropPatternRepo *repo.DropPattern
+}
+
+func newDeps() CommandDeps {
+	var deps CommandDeps
+	cliapp.Start(fx.Populate(&deps))
+	return deps
+}
+
+// the flags of the distribution of the reports, shared by the subcommands
+var generatorFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "server",
+		Usage: "server whose current drop infos the reports are drawn from",
+		Value: "CN",
+	},
+	&cli.Int64Flag{
+		Name:  "seed",
+		Usage: "seed of the generator. The same seed draws the same reports from the same drop infos",
+		Value: 1,
+	},
+	&cli.IntFlag{
+		Name:  "accounts",
+		Usage: "number of accounts the reports are spread over, a few of which submit most of the reports",
+		Value: 1000,
+	},
+	&cli.IntFlag{
+		Name:  "patterns-per-stage",
+		Usage: "number of distinct patterns dropped in a single run of each stage",
+		Value: 16,
+	},
+	&cli.Float64Flag{
+		Name:  "stage-skew",
+		Usage: "exponent of the zipfian popularity of the stages, greater than 1. The greater, the fewer stages get most of the reports",
+		Value: 1.1,
+	},
+	&cli.Float64Flag{
+		Name:  "multi-times-ratio",
+		Usage: "ratio of the automated reports of 2 to 6 runs",
+		Value: 0.2,
+	},
+	&cli.Float64Flag{
+		Name:  "manual-ratio",
+		Usage: "ratio of the reports submitted by hand",
+		Value: 0.3,
+	},
+}
+
+func generatorOptions(ctx *cli.Context) (GeneratorOptions, error) {
+	opts := GeneratorOptions{
+		Seed:             ctx.Int64("seed"),
+		Accounts:         ctx.Int("accounts"),
+		PatternsPerStage: ctx.Int("patterns-per-stage"),
+		StageSkew:        ctx.Float64("stage-skew"),
+		MultiTimesRatio:  ctx.Float64("multi-times-ratio"),
+		ManualRatio:      ctx.Float64("manual-ratio"),
+	}
+	if opts.Accounts < 1 {
+		return opts, errors.New("accounts must be positive")
+	}
+	if opts.PatternsPerStage < 1 {
+		return opts, errors.New("patterns-per-stage must be positive")
+	}
+	if opts.StageSkew <= 1 {
+		return opts, errors.New("stage-skew must be greater than 1")
+	}
+	return opts, nil
+}
+
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:        "loadgen",
+		Description: "generate synthetic reports for load testing. Never run it against the production database or server",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "seed",
+				Usage: "write synthetic accounts and accepted reports into the database",
+				Flags: append([]cli.Flag{
+					&cli.IntFlag{
+						Name:  "reports",
+						Usage: "number of reports to write",
+						Value: 100000,
+					},
+					&cli.IntFlag{
+						Name:  "batch-size",
+						Usage: "number of reports written in each transaction",
+						Value: 1000,
+					},
+					&cli.TimestampFlag{
+						Name:   "end",
+						Usage:  "time before which the reports are created, in RFC 3339. Defaults to now; set it for the data set to be reproducible",
+						Layout: time.RFC3339,
+					},
+					&cli.DurationFlag{
+						Name:  "span",
+						Usage: "duration before end over which the reports are created evenly",
+						Value: 30 * 24 * time.Hour,
+					},
+				}, generatorFlags...),
+				Action: func(ctx *cli.Context) error {
+					generatorOpts, err := generatorOptions(ctx)
+					if err != nil {
+						return err
+					}
+					end := time.Now()
+					if t := ctx.Timestamp("end"); t != nil {
+						end = *t
+					}
+					opts := seedOptions{
+						GeneratorOptions: generatorOpts,
+						Server:           ctx.String("server"),
+						Reports:          ctx.Int("reports"),
+						BatchSize:        ctx.Int("batch-size"),
+						End:              end,
+						Span:             ctx.Duration("span"),
+					}
+					if opts.BatchSize < 1 || opts.Span <= 0 {
+						return errors.New("batch-size and span must be positive")
+					}
+					return seed(ctx.Context, newDeps(), opts)
+				},
+			},
+			{
+				Name:  "replay",
+				Usage: "submit synthetic reports to a running backend at a steady rate, and summarize the latencies",
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:     "target",
+						Usage:    "base URL of the backend, such as http://localhost:9010",
+						Required: true,
+					},
+					&cli.Float64Flag{
+						Name:  "rate",
+						Usage: "submissions per second",
+						Value: 50,
+					},
+					&cli.DurationFlag{
+						Name:  "duration",
+						Usage: "duration of the replay",
+						Value: time.Minute,
+					},
+					&cli.IntFlag{
+						Name:  "concurrency",
+						Usage: "maximum number of submissions in flight. The submissions due while all of them are in flight are skipped",
+						Value: 32,
+					},
+					&cli.DurationFlag{
+						Name:  "timeout",
+						Usage: "timeout of each submission",
+						Value: 10 * time.Second,
+					},
+				}, generatorFlags...),
+				Action: func(ctx *cli.Context) error {
+					generatorOpts, err := generatorOptions(ctx)
+					if err != nil {
+						return err
+					}
+					opts := replayOptions{
+						GeneratorOptions: generatorOpts,
+						Server:           ctx.String("server"),
+						Target:           ctx.String("target"),
+						Rate:             ctx.Float64("rate"),
+						Duration:         ctx.Duration("duration"),
+						Concurrency:      ctx.Int("concurrency"),
+						Timeout:          ctx.Duration("timeout"),
+					}
+					if opts.Rate <= 0 || opts.Concurrency < 1 {
+						return errors.New("rate and concurrency must be positive")
+					}
+					return replay(ctx.Context, newDeps(), opts)
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,257 @@
+package loadgen
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+)
+
+const (
+	// automatedSource is the source of the reports not submitted by hand
+	automatedSource = "MeoAssistant"
+	// syntheticVersion is the version of all the synthetic reports, which tells them apart from the real ones
+	syntheticVersion = "v0.0.0+loadgen"
+)
+
+// the drops of a single item in a pattern, along with the ark ID of the item the submissions are made with
+type drop struct {
+	types.Drop
+	ArkItemID string
+}
+
+type stageProfile struct {
+	stage    *model.Stage
+	patterns [][]*drop
+	// pick chooses the index of the pattern dropped in a run, so that a few patterns make up most of the runs
+	pick *rand.Zipf
+}
+
+// Report is a synthetic report of Times runs of a stage, whose drops are the sum of the drops of the runs
+type Report struct {
+	Stage   *model.Stage
+	Drops   []*drop
+	Times   int
+	Account int
+	Source  string
+}
+
+// GeneratorOptions are the knobs of the distribution the reports are drawn from
+type GeneratorOptions struct {
+	Seed int64
+	// Accounts is the number of distinct accounts the reports are spread over
+	Accounts int
+	// PatternsPerStage is the number of distinct single run patterns of each stage
+	PatternsPerStage int
+	// StageSkew is the exponent of the zipfian popularity of the stages, which is greater than 1
+	StageSkew float64
+	// MultiTimesRatio is the ratio of the reports of more than one run, as submitted by the automated sources
+	MultiTimesRatio float64
+	// ManualRatio is the ratio of the reports submitted by hand rather than by the automated sources
+	ManualRatio float64
+}
+
+// Generator draws synthetic reports from the stages currently dropping items. The same seed and the same drop infos
+// give the same sequence of reports.
+type Generator struct {
+	opts     GeneratorOptions
+	rand     *rand.Rand
+	stages   []*stageProfile
+	stage    *rand.Zipf
+	accounts *rand.Zipf
+}
+
+func NewGenerator(ctx context.Context, deps CommandDeps, server string, opts GeneratorOptions) (*Generator, error) {
+	dropInfos, err := deps.DropInfoService.GetCurrentDropInfosByServer(ctx, server)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get drop infos")
+	}
+	stagesMap, err := deps.StageService.GetStagesMapById(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get stages")
+	}
+	itemsMap, err := deps.ItemService.GetItemsMapById(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get items")
+	}
+
+	g := &Generator{
+		opts: opts,
+		rand: rand.New(rand.NewSource(opts.Seed)),
+	}
+
+	dropInfosByStage := lo.GroupBy(dropInfos, func(dropInfo *model.DropInfo) int { return dropInfo.StageID })
+	stageIds := lo.Keys(dropInfosByStage)
+	// map iteration is random, while the popularity of the stages is to be decided by the seed alone
+	sort.Ints(stageIds)
+	for _, stageId := range stageIds {
+		stage, ok := stagesMap[stageId]
+		if !ok || stage.ExtraProcessType.Valid {
+			// the gacha boxes are reported in a way of their own
+			continue
+		}
+		profile := &stageProfile{stage: stage}
+		for i := 0; i < opts.PatternsPerStage; i++ {
+			profile.patterns = append(profile.patterns, g.run(dropInfosByStage[stageId], itemsMap))
+		}
+		profile.patterns = uniqPatterns(profile.patterns)
+		profile.pick = rand.NewZipf(g.rand, 1.5, 1, uint64(len(profile.patterns)-1))
+		g.stages = append(g.stages, profile)
+	}
+	if len(g.stages) == 0 {
+		return nil, errors.Errorf("no stage drops any item in server %s", server)
+	}
+
+	g.rand.Shuffle(len(g.stages), func(i, j int) { g.stages[i], g.stages[j] = g.stages[j], g.stages[i] })
+	g.stage = rand.NewZipf(g.rand, opts.StageSkew, 1, uint64(len(g.stages)-1))
+	g.accounts = rand.NewZipf(g.rand, 1.2, 1, uint64(opts.Accounts-1))
+	return g, nil
+}
+
+// Stages is the number of the stages the reports are drawn from
+func (g *Generator) Stages() int {
+	return len(g.stages)
+}
+
+// Next draws the next report. The account of the report is an index in [0, Accounts), for the caller to map to the
+// accounts it has.
+func (g *Generator) Next() *Report {
+	profile := g.stages[g.stage.Uint64()]
+	report := &Report{
+		Stage:   profile.stage,
+		Times:   1,
+		Account: int(g.accounts.Uint64()),
+		Source:  automatedSource,
+	}
+	if g.rand.Float64() < g.opts.ManualRatio && len(constant.ManualSources) > 0 {
+		report.Source = constant.ManualSources[0]
+	} else if g.rand.Float64() < g.opts.MultiTimesRatio {
+		report.Times = 2 + g.rand.Intn(5)
+	}
+
+	var runs [][]*drop
+	for i := 0; i < report.Times; i++ {
+		runs = append(runs, profile.patterns[profile.pick.Uint64()])
+	}
+	report.Drops = sumPatterns(runs)
+	return report
+}
+
+// run draws the drops of a single run from the drop infos of a stage. The number of kinds of the items of each drop
+// type is drawn within the bounds of the type first, then the quantity of each item within the bounds of the item.
+func (g *Generator) run(dropInfos []*model.DropInfo, itemsMap map[int]*model.Item) []*drop {
+	typeBounds := make(map[string]*model.Bounds)
+	itemDropInfos := make(map[string][]*model.DropInfo)
+	for _, dropInfo := range dropInfos {
+		if dropInfo.DropType == constant.DropTypeRecognitionOnly || dropInfo.Bounds == nil {
+			continue
+		}
+		if !dropInfo.ItemID.Valid {
+			typeBounds[dropInfo.DropType] = dropInfo.Bounds
+			continue
+		}
+		if _, ok := itemsMap[int(dropInfo.ItemID.Int64)]; ok && dropInfo.Bounds.Upper > 0 {
+			itemDropInfos[dropInfo.DropType] = append(itemDropInfos[dropInfo.DropType], dropInfo)
+		}
+	}
+
+	dropTypes := lo.Keys(itemDropInfos)
+	sort.Strings(dropTypes)
+	drops := make([]*drop, 0)
+	for _, dropType := range dropTypes {
+		candidates := itemDropInfos[dropType]
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].ItemID.Int64 < candidates[j].ItemID.Int64 })
+		g.rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+
+		kinds := len(candidates)
+		if bounds, ok := typeBounds[dropType]; ok {
+			upper := bounds.Upper
+			if upper > len(candidates) {
+				upper = len(candidates)
+			}
+			kinds = g.within(bounds.Lower, upper, bounds.Exceptions)
+		}
+		for i, dropInfo := range candidates {
+			// the items which always drop are dropped regardless of the kinds drawn
+			if i >= kinds && dropInfo.Bounds.Lower == 0 {
+				continue
+			}
+			lower := dropInfo.Bounds.Lower
+			if lower < 1 {
+				lower = 1
+			}
+			drops = append(drops, &drop{
+				Drop: types.Drop{
+					DropType: dropType,
+					ItemID:   int(dropInfo.ItemID.Int64),
+					Quantity: g.within(lower, dropInfo.Bounds.Upper, dropInfo.Bounds.Exceptions),
+				},
+				ArkItemID: itemsMap[int(dropInfo.ItemID.Int64)].ArkItemID,
+			})
+		}
+	}
+	return drops
+}
+
+// within draws an integer in [lower, upper] which is not one of the exceptions, or lower when there is none
+func (g *Generator) within(lower, upper int, exceptions []int) int {
+	if upper < lower {
+		return lower
+	}
+	for i := 0; i < 8; i++ {
+		n := lower + g.rand.Intn(upper-lower+1)
+		if !lo.Contains(exceptions, n) {
+			return n
+		}
+	}
+	return lower
+}
+
+// patternFingerprint identifies the drops regardless of their order, as the fingerprints of the drop patterns do
+func patternFingerprint(drops []*drop) string {
+	segments := make([]string, 0, len(drops))
+	for _, d := range drops {
+		segments = append(segments, d.DropType+":"+strconv.Itoa(d.ItemID)+":"+strconv.Itoa(d.Quantity))
+	}
+	sort.Strings(segments)
+	return strings.Join(segments, "|")
+}
+
+func uniqPatterns(patterns [][]*drop) [][]*drop {
+	return lo.UniqBy(patterns, patternFingerprint)
+}
+
+// sumPatterns adds up the drops of the runs of a report
+func sumPatterns(runs [][]*drop) []*drop {
+	type key struct {
+		dropType string
+		itemId   int
+	}
+	sums := make(map[key]*drop)
+	keys := make([]key, 0)
+	for _, run := range runs {
+		for _, d := range run {
+			k := key{dropType: d.DropType, itemId: d.ItemID}
+			if sum, ok := sums[k]; ok {
+				sum.Quantity += d.Quantity
+				continue
+			}
+			sum := *d
+			sums[k] = &sum
+			keys = append(keys, k)
+		}
+	}
+	drops := make([]*drop, 0, len(keys))
+	for _, k := range keys {
+		drops = append(drops, sums[k])
+	}
+	return drops
+}
@@ -0,0 +1,208 @@
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model/types"
+)
+
+type replayOptions struct {
+	GeneratorOptions
+	Server string
+	// Target is the base URL of the backend the reports are submitted to
+	Target      string
+	Rate        float64
+	Duration    time.Duration
+	Concurrency int
+	Timeout     time.Duration
+}
+
+// replayStats collects the outcome of the submissions
+type replayStats struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	statuses  map[int]int
+	failures  int
+	// skipped counts the submissions due while all the workers were busy, which tells the target was saturated
+	skipped atomic.Int64
+}
+
+func (s *replayStats) record(latency time.Duration, status int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.failures++
+		return
+	}
+	s.latencies = append(s.latencies, latency)
+	s.statuses[status]++
+}
+
+func (s *replayStats) percentile(p float64) time.Duration {
+	if len(s.latencies) == 0 {
+		return 0
+	}
+	return s.latencies[int(float64(len(s.latencies)-1)*p)]
+}
+
+// replayer submits the synthetic reports as the v2 single report requests of the clients
+type replayer struct {
+	opts   replayOptions
+	client *http.Client
+	url    string
+
+	// the PenguinIDs of the accounts, which are created by the first submission of each account
+	mu         sync.Mutex
+	penguinIds map[int]string
+}
+
+func replay(ctx context.Context, deps CommandDeps, opts replayOptions) error {
+	g, err := NewGenerator(ctx, deps, opts.Server, opts.GeneratorOptions)
+	if err != nil {
+		return err
+	}
+	target, err := url.JoinPath(opts.Target, "/PenguinStats/api/v2/report")
+	if err != nil {
+		return err
+	}
+	r := &replayer{
+		opts:       opts,
+		client:     &http.Client{Timeout: opts.Timeout},
+		url:        target,
+		penguinIds: make(map[int]string),
+	}
+	stats := &replayStats{statuses: make(map[int]int)}
+
+	log.Info().
+		Str("target", target).
+		Int("stages", g.Stages()).
+		Float64("rate", opts.Rate).
+		Dur("duration", opts.Duration).
+		Msg("replaying synthetic submissions")
+
+	// the submissions in flight when the duration is over are waited for rather than canceled
+	scheduleCtx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	// the reports are drawn by the scheduler alone, for the sequence of reports to be decided by the seed
+	jobs := make(chan *Report)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for report := range jobs {
+				startedAt := time.Now()
+				status, err := r.submit(ctx, report)
+				stats.record(time.Since(startedAt), status, err)
+			}
+		}()
+	}
+
+	startedAt := time.Now()
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+	defer ticker.Stop()
+	next := g.Next()
+loop:
+	for {
+		select {
+		case <-scheduleCtx.Done():
+			break loop
+		case <-ticker.C:
+			select {
+			case jobs <- next:
+				next = g.Next()
+			default:
+				stats.skipped.Add(1)
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	elapsed := time.Since(startedAt)
+
+	sort.Slice(stats.latencies, func(i, j int) bool { return stats.latencies[i] < stats.latencies[j] })
+	statuses := make(map[string]int, len(stats.statuses))
+	for status, count := range stats.statuses {
+		statuses[strconv.Itoa(status)] = count
+	}
+	log.Info().
+		Int("completed", len(stats.latencies)).
+		Int("failed", stats.failures).
+		Int64("skipped", stats.skipped.Load()).
+		Interface("statuses", statuses).
+		Float64("throughput", float64(len(stats.latencies))/elapsed.Seconds()).
+		Dur("p50", stats.percentile(0.5)).
+		Dur("p95", stats.percentile(0.95)).
+		Dur("p99", stats.percentile(0.99)).
+		Dur("max", stats.percentile(1)).
+		Msg("replayed synthetic submissions")
+	return nil
+}
+
+func (r *replayer) submit(ctx context.Context, report *Report) (int, error) {
+	req := types.SingularReportRequest{
+		FragmentStageID: types.FragmentStageID{StageID: report.Stage.ArkStageID},
+		FragmentReportCommon: types.FragmentReportCommon{
+			Server:  r.opts.Server,
+			Source:  report.Source,
+			Version: syntheticVersion,
+		},
+		Drops: make([]types.ArkDrop, 0, len(report.Drops)),
+		Times: report.Times,
+	}
+	for _, d := range report.Drops {
+		req.Drops = append(req.Drops, types.ArkDrop{
+			DropType: d.DropType,
+			ItemID:   d.ArkItemID,
+			Quantity: d.Quantity,
+		})
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return 0, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	r.mu.Lock()
+	penguinId, ok := r.penguinIds[report.Account]
+	r.mu.Unlock()
+	if ok {
+		httpReq.Header.Set("Authorization", constant.PenguinIDAuthorizationRealm+" "+penguinId)
+	}
+
+	resp, err := r.client.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// the body is drained for the connection to be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	// an account submitting again before its first submission returns is created twice, as a client would do
+	if created := resp.Header.Get(constant.PenguinIDSetHeader); created != "" && !ok {
+		if penguinId, err := url.QueryUnescape(created); err == nil {
+			r.mu.Lock()
+			r.penguinIds[report.Account] = penguinId
+			r.mu.Unlock()
+		}
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,148 @@
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/util/reportutil"
+)
+
+type seedOptions struct {
+	GeneratorOptions
+	Server    string
+	Reports   int
+	BatchSize int
+	// the reports are created evenly over [End - Span, End)
+	End  time.Time
+	Span time.Duration
+}
+
+// seed writes the synthetic reports into the database the way the report worker does, bypassing the queue and the
+// verification of the reports, which are all accepted
+func seed(ctx context.Context, deps CommandDeps, opts seedOptions) error {
+	g, err := NewGenerator(ctx, deps, opts.Server, opts.GeneratorOptions)
+	if err != nil {
+		return err
+	}
+
+	accountIds := make([]int, 0, opts.Accounts)
+	for i := 0; i < opts.Accounts; i++ {
+		account, err := deps.AccountService.CreateAccountWithRandomPenguinId(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to create account")
+		}
+		accountIds = append(accountIds, account.AccountID)
+	}
+	log.Info().
+		Str("server", opts.Server).
+		Int("stages", g.Stages()).
+		Int("accounts", len(accountIds)).
+		Msg("seeding synthetic reports")
+
+	// the creation times are drawn apart from the reports, for the reports to be the same whatever the span is
+	times := rand.New(rand.NewSource(opts.Seed))
+	patternIds := make(map[string]int)
+	startedAt := time.Now()
+	for seeded := 0; seeded < opts.Reports; {
+		n := opts.BatchSize
+		if n > opts.Reports-seeded {
+			n = opts.Reports - seeded
+		}
+		batch := make([]*Report, 0, n)
+		createdAt := make([]time.Time, 0, n)
+		for i := 0; i < n; i++ {
+			batch = append(batch, g.Next())
+			createdAt = append(createdAt, opts.End.Add(-time.Duration(times.Int63n(int64(opts.Span)))))
+		}
+		if err := seedBatch(ctx, deps, opts.Server, accountIds, patternIds, batch, createdAt); err != nil {
+			return err
+		}
+		seeded += n
+
+		log.Info().
+			Int("seeded", seeded).
+			Int("patterns", len(patternIds)).
+			Dur("elapsed", time.Since(startedAt)).
+			Msg("seeded a batch of synthetic reports")
+	}
+	return nil
+}
+
+func seedBatch(ctx context.Context, deps CommandDeps, server string, accountIds []int, patternIds map[string]int, batch []*Report, createdAt []time.Time) error {
+	tx, err := deps.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// the patterns created within the transaction are only remembered once it has been committed
+	created := make(map[string]int)
+	reports := make([]*model.DropReport, 0, len(batch))
+	for i, report := range batch {
+		fingerprint := patternFingerprint(report.Drops)
+		patternId, ok := patternIds[fingerprint]
+		if !ok {
+			patternId, ok = created[fingerprint]
+		}
+		if !ok {
+			drops := make([]*types.Drop, 0, len(report.Drops))
+			for _, d := range report.Drops {
+				drop := d.Drop
+				drops = append(drops, &drop)
+			}
+			drops = reportutil.MergeDropsByItemID(drops)
+			dropPattern, _, err := deps.DropPatternRepo.GetOrCreateDropPatternFromDrops(ctx, tx, drops)
+			if err != nil {
+				return errors.Wrap(err, "failed to get or create drop pattern")
+			}
+			patternId = dropPattern.PatternID
+			created[fingerprint] = patternId
+		}
+
+		reports = append(reports, &model.DropReport{
+			StageID:     report.Stage.StageID,
+			PatternID:   patternId,
+			Times:       report.Times,
+			CreatedAt:   &createdAt[i],
+			Reliability: 0,
+			Server:      server,
+			AccountID:   accountIds[report.Account],
+			SourceName:  report.Source,
+			Version:     syntheticVersion,
+		})
+	}
+	if _, err := tx.NewInsert().Model(&reports).Exec(ctx); err != nil {
+		return errors.Wrap(err, "failed to create drop reports")
+	}
+
+	extras := make([]*model.DropReportExtra, 0, len(reports))
+	for i, report := range reports {
+		extras = append(extras, &model.DropReportExtra{
+			ReportID: report.ReportID,
+			IP:       syntheticIP(batch[i].Account),
+		})
+	}
+	if _, err := tx.NewInsert().Model(&extras).Exec(ctx); err != nil {
+		return errors.Wrap(err, "failed to create drop report extras")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for fingerprint, patternId := range created {
+		patternIds[fingerprint] = patternId
+	}
+	return nil
+}
+
+// syntheticIP is the address the account reports from, within the private 10.0.0.0/8 block
+func syntheticIP(account int) string {
+	return fmt.Sprintf("10.%d.%d.%d", (account>>16)&0xff, (account>>8)&0xff, account&0xff)
+}
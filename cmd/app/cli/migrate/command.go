@@ -0,0 +1,107 @@
+package migrate
+
+import (
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/fx"
+
+	cliapp "exusiai.dev/backend-next/cmd/app/cli"
+	"exusiai.dev/backend-next/internal/migrations"
+)
+
+type CommandDeps struct {
+	fx.In
+
+	DB *bun.DB
+}
+
+func newMigrator() *migrate.Migrator {
+	var deps CommandDeps
+	cliapp.Start(fx.Populate(&deps))
+	return migrate.NewMigrator(deps.DB, migrations.Migrations)
+}
+
+func unlock(ctx *cli.Context, migrator *migrate.Migrator) {
+	if err := migrator.Unlock(ctx.Context); err != nil {
+		log.Error().Err(err).Msg("failed to unlock migrations")
+	}
+}
+
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:        "migrate",
+		Description: "apply or roll back the schema migrations of the database",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "up",
+				Usage: "apply all the migrations that have not been applied yet, as a group",
+				Action: func(ctx *cli.Context) error {
+					migrator := newMigrator()
+					// the tables tracking the applied migrations are created on the first run
+					if err := migrator.Init(ctx.Context); err != nil {
+						return errors.Wrap(err, "failed to create migration tables")
+					}
+					if err := migrator.Lock(ctx.Context); err != nil {
+						return errors.Wrap(err, "failed to lock migrations")
+					}
+					defer unlock(ctx, migrator)
+
+					group, err := migrator.Migrate(ctx.Context)
+					if err != nil {
+						return errors.Wrap(err, "failed to apply migrations")
+					}
+					if group.IsZero() {
+						log.Info().Msg("there are no new migrations to apply")
+						return nil
+					}
+					log.Info().Str("group", group.String()).Msg("applied migrations")
+					return nil
+				},
+			},
+			{
+				Name:  "down",
+				Usage: "roll back the last group of applied migrations",
+				Action: func(ctx *cli.Context) error {
+					migrator := newMigrator()
+					if err := migrator.Lock(ctx.Context); err != nil {
+						return errors.Wrap(err, "failed to lock migrations")
+					}
+					defer unlock(ctx, migrator)
+
+					group, err := migrator.Rollback(ctx.Context)
+					if err != nil {
+						return errors.Wrap(err, "failed to roll back migrations")
+					}
+					if group.IsZero() {
+						log.Info().Msg("there are no applied migrations to roll back")
+						return nil
+					}
+					log.Info().Str("group", group.String()).Msg("rolled back migrations")
+					return nil
+				},
+			},
+			{
+				Name:  "status",
+				Usage: "list the applied and the pending migrations",
+				Action: func(ctx *cli.Context) error {
+					migrator := newMigrator()
+					if err := migrator.Init(ctx.Context); err != nil {
+						return errors.Wrap(err, "failed to create migration tables")
+					}
+					ms, err := migrator.MigrationsWithStatus(ctx.Context)
+					if err != nil {
+						return errors.Wrap(err, "failed to get migrations")
+					}
+					log.Info().
+						Str("applied", ms.Applied().String()).
+						Str("pending", ms.Unapplied().String()).
+						Msg("migrations status")
+					return nil
+				},
+			},
+		},
+	}
+}
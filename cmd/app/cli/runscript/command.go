@@ -7,6 +7,18 @@ import (
 	cliapp "exusiai.dev/backend-next/cmd/app/cli"
 	script_archive_drop_reports "exusiai.dev/backend-next/cmd/app/cli/runscript/scripts/archive_drop_reports"
 	script_migrate_drop_report_extras_cols "exusiai.dev/backend-next/cmd/app/cli/runscript/scripts/at20230110-migrate_drop_report_extras_cols"
+	script_add_account_roles "exusiai.dev/backend-next/cmd/app/cli/runscript/scripts/at20261017-add_account_roles"
+	script_add_api_key_quotas "exusiai.dev/backend-next/cmd/app/cli/runscript/scripts/at20261017-add_api_key_quotas"
+	script_add_drop_matrix_elements_uniq_idx "exusiai.dev/backend-next/cmd/app/cli/runscript/scripts/at20261017-add_drop_matrix_elements_uniq_idx"
+	script_backfill_drop_report_daily_rollups "exusiai.dev/backend-next/cmd/app/cli/runscript/scripts/at20261017-backfill_drop_report_daily_rollups"
+	script_backfill_event_summaries "exusiai.dev/backend-next/cmd/app/cli/runscript/scripts/at20261017-backfill_event_summaries"
+	script_create_account_appeals_table "exusiai.dev/backend-next/cmd/app/cli/runscript/scripts/at20261017-create_account_appeals_table"
+	script_create_api_keys_and_webhooks_tables "exusiai.dev/backend-next/cmd/app/cli/runscript/scripts/at20261017-create_api_keys_and_webhooks_tables"
+	script_create_drop_matrix_views "exusiai.dev/backend-next/cmd/app/cli/runscript/scripts/at20261017-create_drop_matrix_views"
+	script_create_item_search_index "exusiai.dev/backend-next/cmd/app/cli/runscript/scripts/at20261017-create_item_search_index"
+	script_create_jobs_table "exusiai.dev/backend-next/cmd/app/cli/runscript/scripts/at20261017-create_jobs_table"
+	script_partition_drop_reports "exusiai.dev/backend-next/cmd/app/cli/runscript/scripts/at20261017-partition_drop_reports"
+	script_seed_site_counters "exusiai.dev/backend-next/cmd/app/cli/runscript/scripts/at20261017-seed_site_counters"
 )
 
 func depsFn[T any]() func() T {
@@ -24,6 +36,18 @@ func Command() *cli.Command {
 		Subcommands: []*cli.Command{
 			script_migrate_drop_report_extras_cols.Command(depsFn[script_migrate_drop_report_extras_cols.CommandDeps]()),
 			script_archive_drop_reports.Command(depsFn[script_archive_drop_reports.CommandDeps]()),
+			script_create_account_appeals_table.Command(depsFn[script_create_account_appeals_table.CommandDeps]()),
+			script_add_account_roles.Command(depsFn[script_add_account_roles.CommandDeps]()),
+			script_create_item_search_index.Command(depsFn[script_create_item_search_index.CommandDeps]()),
+			script_seed_site_counters.Command(depsFn[script_seed_site_counters.CommandDeps]()),
+			script_create_api_keys_and_webhooks_tables.Command(depsFn[script_create_api_keys_and_webhooks_tables.CommandDeps]()),
+			script_add_api_key_quotas.Command(depsFn[script_add_api_key_quotas.CommandDeps]()),
+			script_create_jobs_table.Command(depsFn[script_create_jobs_table.CommandDeps]()),
+			script_create_drop_matrix_views.Command(depsFn[script_create_drop_matrix_views.CommandDeps]()),
+			script_partition_drop_reports.Command(depsFn[script_partition_drop_reports.CommandDeps]()),
+			script_add_drop_matrix_elements_uniq_idx.Command(depsFn[script_add_drop_matrix_elements_uniq_idx.CommandDeps]()),
+			script_backfill_drop_report_daily_rollups.Command(depsFn[script_backfill_drop_report_daily_rollups.CommandDeps]()),
+			script_backfill_event_summaries.Command(depsFn[script_backfill_event_summaries.CommandDeps]()),
 		},
 	}
 }
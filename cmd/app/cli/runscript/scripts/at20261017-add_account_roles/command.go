@@ -0,0 +1,32 @@
+package script_add_account_roles
+
+import (
+	"github.com/uptrace/bun"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/service"
+)
+
+type CommandDeps struct {
+	fx.In
+
+	DB                 *bun.DB
+	AccountRoleService *service.AccountRole
+}
+
+func Command(depsFn func() CommandDeps) *cli.Command {
+	return &cli.Command{
+		Name:        "add_account_roles",
+		Description: "add (roles, admin_token_hash) columns to `accounts` table, and optionally bootstrap the first admin account",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "grant-admin",
+				Usage: "PenguinID of the account to grant the admin role to. The issued admin token is printed to stdout",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			return run(ctx.Context, depsFn(), ctx.String("grant-admin"))
+		},
+	}
+}
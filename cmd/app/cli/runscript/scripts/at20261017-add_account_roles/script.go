@@ -0,0 +1,51 @@
+package script_add_account_roles
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model"
+)
+
+func run(ctx context.Context, deps CommandDeps, grantAdminPenguinId string) error {
+	db := deps.DB
+
+	log.Info().Msg("running script")
+
+	_, err := db.ExecContext(ctx, `ALTER TABLE accounts ADD COLUMN IF NOT EXISTS roles TEXT[] NOT NULL DEFAULT '{}'`)
+	if err != nil {
+		return errors.Wrap(err, "failed to add roles column to accounts table")
+	}
+
+	_, err = db.ExecContext(ctx, `ALTER TABLE accounts ADD COLUMN IF NOT EXISTS admin_token_hash TEXT NULL`)
+	if err != nil {
+		return errors.Wrap(err, "failed to add admin_token_hash column to accounts table")
+	}
+
+	_, err = db.ExecContext(ctx, `CREATE UNIQUE INDEX IF NOT EXISTS accounts_admin_token_hash_idx ON accounts (admin_token_hash) WHERE admin_token_hash IS NOT NULL`)
+	if err != nil {
+		return errors.Wrap(err, "failed to create index on admin_token_hash column")
+	}
+
+	log.Info().Msg("roles and admin_token_hash columns added to accounts table")
+
+	if grantAdminPenguinId != "" {
+		result, err := deps.AccountRoleService.GrantRole(ctx, grantAdminPenguinId, model.RoleAdmin)
+		if err != nil {
+			return errors.Wrap(err, "failed to grant admin role")
+		}
+
+		if result.Token == "" {
+			log.Info().Msg("account already has an admin token; role granted without issuing a new token")
+		} else {
+			fmt.Println(result.Token)
+		}
+	}
+
+	log.Info().Msg("script finished")
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package script_add_api_key_quotas
+
+import (
+	"github.com/uptrace/bun"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/fx"
+)
+
+type CommandDeps struct {
+	fx.In
+
+	DB *bun.DB
+}
+
+func Command(depsFn func() CommandDeps) *cli.Command {
+	return &cli.Command{
+		Name:        "add_api_key_quotas",
+		Description: "add the `daily_request_quota` and `daily_byte_quota` columns to the `api_keys` table",
+		Action: func(ctx *cli.Context) error {
+			return run(ctx.Context, depsFn())
+		},
+	}
+}
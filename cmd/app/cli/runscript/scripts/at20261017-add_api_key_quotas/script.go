@@ -0,0 +1,29 @@
+package script_add_api_key_quotas
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+func run(ctx context.Context, deps CommandDeps) error {
+	db := deps.DB
+
+	log.Info().Msg("running script")
+
+	// existing keys are left unlimited, as they were issued before quotas were enforced
+	_, err := db.ExecContext(ctx, `ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS daily_request_quota INTEGER NOT NULL DEFAULT 0`)
+	if err != nil {
+		return errors.Wrap(err, "failed to add daily_request_quota column to api_keys table")
+	}
+
+	_, err = db.ExecContext(ctx, `ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS daily_byte_quota BIGINT NOT NULL DEFAULT 0`)
+	if err != nil {
+		return errors.Wrap(err, "failed to add daily_byte_quota column to api_keys table")
+	}
+
+	log.Info().Msg("script finished")
+
+	return nil
+}
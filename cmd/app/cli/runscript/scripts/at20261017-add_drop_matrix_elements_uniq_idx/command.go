@@ -0,0 +1,23 @@
+package script_add_drop_matrix_elements_uniq_idx
+
+import (
+	"github.com/uptrace/bun"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/fx"
+)
+
+type CommandDeps struct {
+	fx.In
+
+	DB *bun.DB
+}
+
+func Command(depsFn func() CommandDeps) *cli.Command {
+	return &cli.Command{
+		Name:        "add_drop_matrix_elements_uniq_idx",
+		Description: "add the unique index of drop_matrix_elements which the elements of a day are upserted against, removing the duplicated elements first",
+		Action: func(ctx *cli.Context) error {
+			return run(ctx.Context, depsFn())
+		},
+	}
+}
@@ -0,0 +1,35 @@
+package script_add_drop_matrix_elements_uniq_idx
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+var statements = []string{
+	// the elements used to be deleted and inserted again without a transaction, which may have left duplicates behind
+	// when two refreshes raced. The latest one of them is kept.
+	`DELETE FROM drop_matrix_elements AS a USING drop_matrix_elements AS b
+	WHERE a.server = b.server AND a.source_category = b.source_category AND a.day_num = b.day_num
+		AND a.stage_id = b.stage_id AND a.item_id = b.item_id AND a.start_time = b.start_time
+		AND a.element_id < b.element_id`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS drop_matrix_elements_uniq_idx
+	ON drop_matrix_elements (server, source_category, day_num, stage_id, item_id, start_time)`,
+}
+
+func run(ctx context.Context, deps CommandDeps) error {
+	db := deps.DB
+
+	log.Info().Msg("running script")
+
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return errors.Wrap(err, "failed to add unique index of drop_matrix_elements")
+		}
+	}
+
+	log.Info().Msg("script finished")
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package script_backfill_drop_report_daily_rollups
+
+import (
+	"github.com/urfave/cli/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+type CommandDeps struct {
+	fx.In
+
+	DropReportRepo            *repo.DropReport
+	DropReportDailyRollupRepo *repo.DropReportDailyRollup
+}
+
+func Command(depsFn func() CommandDeps) *cli.Command {
+	return &cli.Command{
+		Name:        "backfill_drop_report_daily_rollups",
+		Description: "rebuild the daily rollups of every game day from the oldest drop report on. Run it with DropReportDailyRollupsEnabled on, before turning DropReportDailyRollupsServing on",
+		Action: func(ctx *cli.Context) error {
+			return run(ctx.Context, depsFn())
+		},
+	}
+}
@@ -0,0 +1,41 @@
+package script_backfill_drop_report_daily_rollups
+
+import (
+	"context"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/pkg/gameday"
+)
+
+func run(ctx context.Context, deps CommandDeps) error {
+	log.Info().Msg("running script")
+
+	oldest, err := deps.DropReportRepo.GetOldestDropReportTime(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the oldest drop report time")
+	}
+	if !oldest.Valid {
+		log.Info().Msg("no drop report to roll up, script finished")
+		return nil
+	}
+
+	now := time.Now()
+	for _, server := range constant.Servers {
+		days := 0
+		for dayStart := gameday.StartTime(server, oldest.Time); dayStart.Before(now); dayStart = gameday.NextStartTime(server, dayStart) {
+			if err := deps.DropReportDailyRollupRepo.RebuildDay(ctx, server, dayStart); err != nil {
+				return errors.Wrapf(err, "failed to rebuild the day of server %s starting at %s", server, dayStart.Format(time.RFC3339))
+			}
+			days++
+		}
+		log.Info().Str("server", server).Int("days", days).Msg("rebuilt daily rollups")
+	}
+
+	log.Info().Msg("script finished")
+
+	return nil
+}
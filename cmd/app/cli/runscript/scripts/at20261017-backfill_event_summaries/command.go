@@ -0,0 +1,24 @@
+package script_backfill_event_summaries
+
+import (
+	"github.com/urfave/cli/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/service"
+)
+
+type CommandDeps struct {
+	fx.In
+
+	EventSummaryService *service.EventSummary
+}
+
+func Command(depsFn func() CommandDeps) *cli.Command {
+	return &cli.Command{
+		Name:        "backfill_event_summaries",
+		Description: "summarize the past events closed before the event summary job first ran, which the job leaves out. The events with reports already purged from the database are skipped",
+		Action: func(ctx *cli.Context) error {
+			return run(ctx.Context, depsFn())
+		},
+	}
+}
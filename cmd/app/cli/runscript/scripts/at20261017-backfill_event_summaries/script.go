@@ -0,0 +1,22 @@
+package script_backfill_event_summaries
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+func run(ctx context.Context, deps CommandDeps) error {
+	log.Info().Msg("running script")
+
+	created, err := deps.EventSummaryService.Backfill(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to backfill event summaries")
+	}
+	log.Info().Int("created", created).Msg("backfilled event summaries")
+
+	log.Info().Msg("script finished")
+
+	return nil
+}
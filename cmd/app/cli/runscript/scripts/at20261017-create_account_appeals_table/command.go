@@ -0,0 +1,23 @@
+package script_create_account_appeals_table
+
+import (
+	"github.com/uptrace/bun"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/fx"
+)
+
+type CommandDeps struct {
+	fx.In
+
+	DB *bun.DB
+}
+
+func Command(depsFn func() CommandDeps) *cli.Command {
+	return &cli.Command{
+		Name:        "create_account_appeals_table",
+		Description: "create the `account_appeals` table used by the account standing appeal flow",
+		Action: func(ctx *cli.Context) error {
+			return run(ctx.Context, depsFn())
+		},
+	}
+}
@@ -0,0 +1,33 @@
+package script_create_account_appeals_table
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model"
+)
+
+func run(ctx context.Context, deps CommandDeps) error {
+	db := deps.DB
+
+	log.Info().Msg("running script")
+
+	_, err := db.NewCreateTable().
+		Model((*model.AccountAppeal)(nil)).
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to create account_appeals table")
+	}
+
+	_, err = db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS account_appeals_account_id_created_at_idx ON account_appeals (account_id, created_at DESC)`)
+	if err != nil {
+		return errors.Wrap(err, "failed to create index on account_appeals table")
+	}
+
+	log.Info().Msg("script finished")
+
+	return nil
+}
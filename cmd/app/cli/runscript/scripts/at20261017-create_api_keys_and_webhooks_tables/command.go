@@ -0,0 +1,23 @@
+package script_create_api_keys_and_webhooks_tables
+
+import (
+	"github.com/uptrace/bun"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/fx"
+)
+
+type CommandDeps struct {
+	fx.In
+
+	DB *bun.DB
+}
+
+func Command(depsFn func() CommandDeps) *cli.Command {
+	return &cli.Command{
+		Name:        "create_api_keys_and_webhooks_tables",
+		Description: "create the `api_keys` and `webhooks` tables used by the webhook subscriptions",
+		Action: func(ctx *cli.Context) error {
+			return run(ctx.Context, depsFn())
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package script_create_api_keys_and_webhooks_tables
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model"
+)
+
+func run(ctx context.Context, deps CommandDeps) error {
+	db := deps.DB
+
+	log.Info().Msg("running script")
+
+	_, err := db.NewCreateTable().
+		Model((*model.APIKey)(nil)).
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to create api_keys table")
+	}
+
+	_, err = db.NewCreateTable().
+		Model((*model.Webhook)(nil)).
+		IfNotExists().
+		ForeignKey(`("api_key_id") REFERENCES "api_keys" ("api_key_id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to create webhooks table")
+	}
+
+	_, err = db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS webhooks_api_key_id_idx ON webhooks (api_key_id)`)
+	if err != nil {
+		return errors.Wrap(err, "failed to create index on webhooks table")
+	}
+
+	_, err = db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS webhooks_events_idx ON webhooks USING GIN (events)`)
+	if err != nil {
+		return errors.Wrap(err, "failed to create index on webhooks table")
+	}
+
+	log.Info().Msg("script finished")
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package script_create_drop_matrix_views
+
+import (
+	"context"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// the source names are coalesced for the unique indexes, which are required to refresh the views concurrently, to
+// cover every row. An empty source name is not a manual source, which is how the missing ones are categorized too.
+// The views are dropped first to pick up the changes of their definitions; they are derived from the reports only.
+var statements = []string{
+	`DROP MATERIALIZED VIEW IF EXISTS drop_matrix_range_elements`,
+	`DROP MATERIALIZED VIEW IF EXISTS drop_matrix_range_times`,
+	`DROP MATERIALIZED VIEW IF EXISTS drop_matrix_range_reporters`,
+
+	`CREATE MATERIALIZED VIEW IF NOT EXISTS drop_matrix_range_elements AS
+	SELECT tr.range_id, dr.server, dr.stage_id, dpe.item_id, dpe.quantity, COALESCE(dr.source_name, '') AS source_name, COUNT(*) AS count
+	FROM time_ranges AS tr
+	JOIN drop_reports AS dr ON dr.server = tr.server AND dr.created_at >= tr.start_time AND dr.created_at < tr.end_time
+	JOIN drop_pattern_elements AS dpe ON dpe.drop_pattern_id = dr.pattern_id
+	WHERE dr.reliability = 0 AND ` + repo.NotExcludedByRules + `
+	GROUP BY tr.range_id, dr.server, dr.stage_id, dpe.item_id, dpe.quantity, COALESCE(dr.source_name, '')
+	WITH NO DATA`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS drop_matrix_range_elements_uniq_idx ON drop_matrix_range_elements (range_id, stage_id, item_id, quantity, source_name)`,
+
+	`CREATE MATERIALIZED VIEW IF NOT EXISTS drop_matrix_range_times AS
+	SELECT tr.range_id, dr.server, dr.stage_id, COALESCE(dr.source_name, '') AS source_name, SUM(dr.times) AS times
+	FROM time_ranges AS tr
+	JOIN drop_reports AS dr ON dr.server = tr.server AND dr.created_at >= tr.start_time AND dr.created_at < tr.end_time
+	WHERE dr.reliability = 0 AND ` + repo.NotExcludedByRules + `
+	GROUP BY tr.range_id, dr.server, dr.stage_id, COALESCE(dr.source_name, '')
+	WITH NO DATA`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS drop_matrix_range_times_uniq_idx ON drop_matrix_range_times (range_id, stage_id, source_name)`,
+
+	// the distinct accounts are not additive across the source names, so they are counted per source category instead
+	`CREATE MATERIALIZED VIEW IF NOT EXISTS drop_matrix_range_reporters AS
+	SELECT tr.range_id, dr.server, dr.stage_id,
+		COUNT(DISTINCT dr.account_id) AS reporters,
+		COUNT(DISTINCT dr.account_id) FILTER (WHERE dr.source_name IN (?)) AS manual_reporters,
+		COUNT(DISTINCT dr.account_id) FILTER (WHERE COALESCE(dr.source_name, '') NOT IN (?)) AS automated_reporters
+	FROM time_ranges AS tr
+	JOIN drop_reports AS dr ON dr.server = tr.server AND dr.created_at >= tr.start_time AND dr.created_at < tr.end_time
+	WHERE dr.reliability = 0 AND ` + repo.NotExcludedByRules + `
+	GROUP BY tr.range_id, dr.server, dr.stage_id
+	WITH NO DATA`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS drop_matrix_range_reporters_uniq_idx ON drop_matrix_range_reporters (range_id, stage_id)`,
+
+	// the views are populated once here, since a concurrent refresh requires them to be populated already
+	`REFRESH MATERIALIZED VIEW drop_matrix_range_elements`,
+	`REFRESH MATERIALIZED VIEW drop_matrix_range_times`,
+	`REFRESH MATERIALIZED VIEW drop_matrix_range_reporters`,
+}
+
+func run(ctx context.Context, deps CommandDeps) error {
+	db := deps.DB
+
+	log.Info().Msg("running script")
+
+	// the manual sources are the only placeholders of the statements
+	manualSources := bun.In(constant.ManualSources)
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement, manualSources, manualSources); err != nil {
+			return errors.Wrap(err, "failed to create drop matrix views")
+		}
+	}
+
+	log.Info().Msg("script finished")
+
+	return nil
+}
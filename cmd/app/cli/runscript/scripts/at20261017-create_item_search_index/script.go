@@ -0,0 +1,29 @@
+package script_create_item_search_index
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+func run(ctx context.Context, deps CommandDeps) error {
+	db := deps.DB
+
+	log.Info().Msg("running script")
+
+	_, err := db.ExecContext(ctx, `CREATE EXTENSION IF NOT EXISTS pg_trgm`)
+	if err != nil {
+		return errors.Wrap(err, "failed to enable pg_trgm extension")
+	}
+
+	// the indexed expression must match the search document used by the item repo
+	_, err = db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS items_search_trgm_idx ON items USING GIN (("name"::TEXT || ' ' || COALESCE("keywords"::TEXT, '')) gin_trgm_ops)`)
+	if err != nil {
+		return errors.Wrap(err, "failed to create trigram index on items")
+	}
+
+	log.Info().Msg("script finished")
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package script_create_jobs_table
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model"
+)
+
+func run(ctx context.Context, deps CommandDeps) error {
+	db := deps.DB
+
+	log.Info().Msg("running script")
+
+	_, err := db.NewCreateTable().
+		Model((*model.Job)(nil)).
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to create jobs table")
+	}
+
+	// due jobs are claimed by kind, status and run_at
+	_, err = db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS jobs_kind_status_run_at_idx ON jobs (kind, status, run_at)`)
+	if err != nil {
+		return errors.Wrap(err, "failed to create index on jobs table")
+	}
+
+	_, err = db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS jobs_created_at_idx ON jobs (created_at DESC)`)
+	if err != nil {
+		return errors.Wrap(err, "failed to create index on jobs table")
+	}
+
+	log.Info().Msg("script finished")
+
+	return nil
+}
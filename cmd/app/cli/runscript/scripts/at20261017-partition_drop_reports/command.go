@@ -0,0 +1,23 @@
+package script_partition_drop_reports
+
+import (
+	"github.com/uptrace/bun"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/fx"
+)
+
+type CommandDeps struct {
+	fx.In
+
+	DB *bun.DB
+}
+
+func Command(depsFn func() CommandDeps) *cli.Command {
+	return &cli.Command{
+		Name:        "partition_drop_reports",
+		Description: "partition the `drop_reports` table by month and then by server, copying the existing reports over. Writes to the table are blocked until it finishes.",
+		Action: func(ctx *cli.Context) error {
+			return run(ctx.Context, depsFn())
+		},
+	}
+}
@@ -0,0 +1,137 @@
+package script_partition_drop_reports
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/repo"
+	"exusiai.dev/backend-next/internal/service"
+)
+
+// unpartitionedTable is where the original table is kept, to be dropped by hand once the partitioned one is verified
+const unpartitionedTable = "drop_reports_unpartitioned"
+
+func run(ctx context.Context, deps CommandDeps) error {
+	db := deps.DB
+	partitionRepo := repo.NewDropReportPartition(db)
+
+	log.Info().Msg("running script")
+
+	partitioned, err := partitionRepo.IsPartitioned(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to check whether drop_reports is partitioned")
+	}
+	if partitioned {
+		log.Info().Msg("drop_reports is already partitioned, nothing to do")
+		return nil
+	}
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// the primary key has to include the partition keys, hence only the non-unique indexes are recreated as is
+		var indexNames, indexDefs []string
+		if err := tx.NewRaw("SELECT indexname FROM pg_indexes WHERE schemaname = current_schema() AND tablename = 'drop_reports'").
+			Scan(ctx, &indexNames); err != nil {
+			return errors.Wrap(err, "failed to get indexes")
+		}
+		if err := tx.NewRaw("SELECT indexdef FROM pg_indexes WHERE schemaname = current_schema() AND tablename = 'drop_reports' AND indexdef NOT LIKE 'CREATE UNIQUE %'").
+			Scan(ctx, &indexDefs); err != nil {
+			return errors.Wrap(err, "failed to get index definitions")
+		}
+		var sequence string
+		if err := tx.NewRaw("SELECT pg_get_serial_sequence('drop_reports', 'report_id')").Scan(ctx, &sequence); err != nil {
+			return errors.Wrap(err, "failed to get report_id sequence")
+		}
+
+		// a partitioned table cannot be referenced by foreign keys on report_id alone, and the tables referencing the
+		// reports have no created_at and server to reference the primary key of the partitioned one. The foreign keys
+		// are therefore only dropped for the tables of which the references are kept by repo.DropReportPartition,
+		// which deletes the rows referencing the reports of a partition along with it.
+		var foreignKeys []struct {
+			Table string `bun:"tbl"`
+			Name  string `bun:"name"`
+		}
+		if err := tx.NewRaw("SELECT conrelid::regclass::text AS tbl, conname AS name FROM pg_constraint WHERE contype = 'f' AND confrelid = 'drop_reports'::regclass").
+			Scan(ctx, &foreignKeys); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return errors.Wrap(err, "failed to get foreign keys")
+		}
+		for _, fk := range foreignKeys {
+			if !repo.IsDropReportReferrer(fk.Table) {
+				return errors.Errorf("foreign key %s of %s references drop_reports, but its rows would not be deleted along with the partitions of their reports", fk.Name, fk.Table)
+			}
+		}
+		for _, fk := range foreignKeys {
+			if _, err := tx.ExecContext(ctx, "ALTER TABLE ? DROP CONSTRAINT ?", bun.Safe(fk.Table), bun.Ident(fk.Name)); err != nil {
+				return errors.Wrap(err, "failed to drop foreign key")
+			}
+			log.Info().Str("table", fk.Table).Str("constraint", fk.Name).Msg("dropped foreign key referencing drop_reports")
+		}
+
+		// the materialized views would otherwise keep reading the unpartitioned table
+		if _, err := tx.ExecContext(ctx, "DROP MATERIALIZED VIEW IF EXISTS drop_matrix_range_elements, drop_matrix_range_times"); err != nil {
+			return errors.Wrap(err, "failed to drop drop matrix views")
+		}
+
+		if _, err := tx.ExecContext(ctx, "ALTER TABLE drop_reports RENAME TO ?", bun.Ident(unpartitionedTable)); err != nil {
+			return errors.Wrap(err, "failed to rename drop_reports")
+		}
+		for _, name := range indexNames {
+			if _, err := tx.ExecContext(ctx, "ALTER INDEX ? RENAME TO ?", bun.Ident(name), bun.Ident(name+"_unpartitioned")); err != nil {
+				return errors.Wrap(err, "failed to rename index")
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, "CREATE TABLE drop_reports (LIKE ? INCLUDING DEFAULTS) PARTITION BY RANGE (created_at)", bun.Ident(unpartitionedTable)); err != nil {
+			return errors.Wrap(err, "failed to create partitioned drop_reports")
+		}
+		if _, err := tx.ExecContext(ctx, "ALTER TABLE drop_reports ADD PRIMARY KEY (report_id, created_at, server)"); err != nil {
+			return errors.Wrap(err, "failed to add primary key")
+		}
+		if sequence != "" {
+			if _, err := tx.ExecContext(ctx, "ALTER SEQUENCE ? OWNED BY drop_reports.report_id", bun.Safe(sequence)); err != nil {
+				return errors.Wrap(err, "failed to move report_id sequence")
+			}
+		}
+
+		var oldest sql.NullTime
+		if err := tx.NewRaw("SELECT MIN(created_at) FROM ?", bun.Ident(unpartitionedTable)).Scan(ctx, &oldest); err != nil {
+			return errors.Wrap(err, "failed to get oldest report")
+		}
+		now := time.Now()
+		from := now
+		if oldest.Valid {
+			from = oldest.Time
+		}
+		if err := partitionRepo.CreatePartitions(ctx, tx, from, now.AddDate(0, service.DropReportPartitionsAhead, 0), constant.Servers); err != nil {
+			return errors.Wrap(err, "failed to create partitions")
+		}
+
+		res, err := tx.ExecContext(ctx, "INSERT INTO drop_reports SELECT * FROM ?", bun.Ident(unpartitionedTable))
+		if err != nil {
+			return errors.Wrap(err, "failed to copy drop reports")
+		}
+		copied, _ := res.RowsAffected()
+		log.Info().Int64("rows", copied).Msg("copied drop reports into partitions")
+
+		for _, def := range indexDefs {
+			if _, err := tx.ExecContext(ctx, def); err != nil {
+				return errors.Wrap(err, "failed to recreate index")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("table", unpartitionedTable).
+		Msg("script finished. The original table has been kept; drop it once the partitioned one is verified, and run create_drop_matrix_views again should the drop matrix views be in use")
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package script_seed_site_counters
+
+import (
+	"github.com/uptrace/bun"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/service"
+)
+
+type CommandDeps struct {
+	fx.In
+
+	DB                 *bun.DB
+	SiteCounterService *service.SiteCounter
+}
+
+func Command(depsFn func() CommandDeps) *cli.Command {
+	return &cli.Command{
+		Name:        "seed_site_counters",
+		Description: "seed the redis counters backing the sitewide stats from the database. Reports persisted while seeding may be counted twice or not at all",
+		Action: func(ctx *cli.Context) error {
+			return run(ctx.Context, depsFn())
+		},
+	}
+}
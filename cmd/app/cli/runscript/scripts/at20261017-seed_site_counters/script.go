@@ -0,0 +1,59 @@
+package script_seed_site_counters
+
+import (
+	"context"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const reportersBatchSize = 10000
+
+func run(ctx context.Context, deps CommandDeps) error {
+	db := deps.DB
+
+	log.Info().Msg("running script")
+
+	var accounts int64
+	if err := db.NewRaw(`SELECT COUNT(*) FROM accounts`).Scan(ctx, &accounts); err != nil {
+		return errors.Wrap(err, "failed to count accounts")
+	}
+	if err := deps.SiteCounterService.SeedAccounts(ctx, accounts); err != nil {
+		return errors.Wrap(err, "failed to seed account counter")
+	}
+	log.Info().Int64("accounts", accounts).Msg("seeded account counter")
+
+	for _, server := range constant.Servers {
+		var reports int64
+		if err := db.NewRaw(`SELECT COUNT(*) FROM drop_reports WHERE server = ?`, server).Scan(ctx, &reports); err != nil {
+			return errors.Wrapf(err, "failed to count drop reports of server %s", server)
+		}
+		if err := deps.SiteCounterService.SeedReports(ctx, server, reports, nil); err != nil {
+			return errors.Wrapf(err, "failed to seed report counter of server %s", server)
+		}
+
+		lastAccountId := 0
+		for {
+			var accountIds []int
+			err := db.NewRaw(`SELECT DISTINCT account_id FROM drop_reports WHERE server = ? AND account_id > ? ORDER BY account_id LIMIT ?`,
+				server, lastAccountId, reportersBatchSize).Scan(ctx, &accountIds)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get reporters of server %s", server)
+			}
+			if len(accountIds) == 0 {
+				break
+			}
+			if err := deps.SiteCounterService.AddReporters(ctx, server, accountIds); err != nil {
+				return errors.Wrapf(err, "failed to seed reporters of server %s", server)
+			}
+			lastAccountId = accountIds[len(accountIds)-1]
+		}
+
+		log.Info().Str("server", server).Int64("reports", reports).Msg("seeded report counters")
+	}
+
+	log.Info().Msg("script finished")
+
+	return nil
+}
@@ -10,19 +10,21 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"go.uber.org/fx"
+	"google.golang.org/grpc"
 
 	"exusiai.dev/backend-next/internal/app"
 	"exusiai.dev/backend-next/internal/app/appconfig"
 	"exusiai.dev/backend-next/internal/app/appcontext"
 	"exusiai.dev/backend-next/internal/pkg/async"
 	"exusiai.dev/backend-next/internal/server/httpserver"
+	"exusiai.dev/backend-next/internal/service"
 )
 
 func Run() {
 	app.New(appcontext.Declare(appcontext.EnvServer), fx.Invoke(run)).Run()
 }
 
-func run(serviceApp *fiber.App, devOpsApp httpserver.DevOpsApp, conf *appconfig.Config, lc fx.Lifecycle) {
+func run(serviceApp *fiber.App, devOpsApp httpserver.DevOpsApp, grpcServer *grpc.Server, healthService *service.Health, conf *appconfig.Config, lc fx.Lifecycle) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			serviceLn, err := net.Listen("tcp", conf.ServiceAddress)
@@ -53,6 +55,23 @@ func run(serviceApp *fiber.App, devOpsApp httpserver.DevOpsApp, conf *appconfig.
 				}()
 			}
 
+			if conf.GRPCAddress == "" {
+				log.Info().
+					Str("evt.name", "infra.grpc.disabled").
+					Msg("gRPC server is disabled")
+			} else {
+				grpcLn, err := net.Listen("tcp", conf.GRPCAddress)
+				if err != nil {
+					return err
+				}
+
+				go func() {
+					if err := grpcServer.Serve(grpcLn); err != nil {
+						log.Error().Err(err).Msg("server terminated unexpectedly")
+					}
+				}()
+			}
+
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
@@ -60,9 +79,26 @@ func run(serviceApp *fiber.App, devOpsApp httpserver.DevOpsApp, conf *appconfig.
 				return nil
 			}
 
+			// fail the readiness probes first and keep serving in the meantime, so that no request is
+			// refused by the closed listeners before the load balancers notice
+			healthService.MarkDraining()
+			log.Info().
+				Str("evt.name", "server.draining").
+				Dur("delay", conf.ShutdownDrainDelay).
+				Msg("draining traffic before shutting down")
+			select {
+			case <-time.After(conf.ShutdownDrainDelay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
 			return async.WaitAll(
 				async.Errable(serviceApp.Shutdown),
 				async.Errable(devOpsApp.Shutdown),
+				async.Errable(func() error {
+					grpcServer.GracefulStop()
+					return nil
+				}),
 				async.Errable(func() error {
 					flushed := sentry.Flush(time.Second * 30)
 					if !flushed {
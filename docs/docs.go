@@ -1,5 +1,4 @@
-// Code generated by swaggo/swag. DO NOT EDIT.
-
+// Package docs Code generated by swaggo/swag. DO NOT EDIT
 package docs
 
 import "github.com/swaggo/swag"
@@ -40,7 +39,7 @@ const docTemplate = `{
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -62,7 +61,7 @@ const docTemplate = `{
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -105,7 +104,7 @@ const docTemplate = `{
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -154,13 +153,13 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid or missing itemId. Notice that this shall be the **string ID** of the item, instead of the internally used numerical ID of the item.",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     },
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -188,7 +187,7 @@ const docTemplate = `{
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -231,7 +230,7 @@ const docTemplate = `{
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -276,13 +275,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid request",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "426": {
+                        "description": "The version of the client is no longer accepted and has to be upgraded",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "The account has never submitted a report and a solved challenge is required, see /api/v3alpha/report/challenge",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     },
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -319,13 +330,13 @@ const docTemplate = `{
                     "400": {
                         "description": "` + "`" + `reportHash` + "`" + ` is missing, invalid, or already been recalled.",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     },
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -367,13 +378,13 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid request",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     },
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -419,6 +430,12 @@ const docTemplate = `{
                                     }
                                 }
                             ]
+                        },
+                        "headers": {
+                            "X-Penguin-Purged-Before": {
+                                "type": "integer",
+                                "description": "Set on personal results that leave out the reports moved to the archive before this time, in unix milliseconds. Those may be requested as an export instead"
+                            }
                         }
                     },
                     "202": {
@@ -442,10 +459,16 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Invalid request, or a query is too costly to run: its time range, stages and items shall be narrowed down",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -521,6 +544,57 @@ const docTemplate = `{
                         "description": "Comma separated list of item IDs to filter",
                         "name": "itemFilter",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "Respond with a spreadsheet instead of JSON",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "zh",
+                            "en",
+                            "ja",
+                            "ko"
+                        ],
+                        "type": "string",
+                        "description": "Language of the stage codes and item names; default to the best match of the Accept-Language header, or zh. Implies ` + "`" + `localized` + "`" + ` for JSON responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Embed the stage and item names in the language selected by ` + "`" + `lang` + "`" + ` or the Accept-Language header into the JSON response, as ` + "`" + `stageName` + "`" + ` and ` + "`" + `itemName` + "`" + ` fields",
+                        "name": "localized",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated list of the fields of the matrix elements to respond with, e.g. ` + "`" + `times,quantity` + "`" + `; ` + "`" + `stageId` + "`" + ` and ` + "`" + `itemId` + "`" + ` are always included",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Respond with the global matrix as it was at the end of this date in the server, e.g. ` + "`" + `2023-01-31` + "`" + `, including closed stages. Not available for personal results or categories other than all",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "7d",
+                            "30d",
+                            "90d"
+                        ],
+                        "type": "string",
+                        "description": "Respond with the matrix of the reports of the last 7, 30 or 90 days, up to the start of the current hour, rather than of the time ranges of the stages. Not available along with ` + "`" + `as_of` + "`" + `",
+                        "name": "range",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -528,12 +602,42 @@ const docTemplate = `{
                         "description": "Drop Matrix response",
                         "schema": {
                             "$ref": "#/definitions/v2.DropMatrixQueryResult"
+                        },
+                        "headers": {
+                            "X-Penguin-Cache-Age": {
+                                "type": "integer",
+                                "description": "Set on the cached global results, to the seconds elapsed since the result has been calculated"
+                            },
+                            "X-Penguin-Purged-Before": {
+                                "type": "integer",
+                                "description": "Set on personal results that leave out the reports moved to the archive before this time, in unix milliseconds. Those may be requested as an export instead"
+                            },
+                            "X-Penguin-Refresh-After": {
+                                "type": "integer",
+                                "description": "Set on the cached global results, to the seconds after which the result is due to be calculated again"
+                            },
+                            "X-Penguin-Snapshot-Recorded-At": {
+                                "type": "integer",
+                                "description": "Set on results queried with ` + "`" + `as_of` + "`" + `, to the time the snapshot has been recorded at, in unix milliseconds"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "No snapshot has been recorded as of ` + "`" + `as_of` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     },
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -578,6 +682,51 @@ const docTemplate = `{
                         "description": "Show all patterns; default to false",
                         "name": "showAllPatterns",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "Respond with a spreadsheet instead of JSON",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "zh",
+                            "en",
+                            "ja",
+                            "ko"
+                        ],
+                        "type": "string",
+                        "description": "Language of the stage codes and item names; default to the best match of the Accept-Language header, or zh. Implies ` + "`" + `localized` + "`" + ` for JSON responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Embed the stage and item names in the language selected by ` + "`" + `lang` + "`" + ` or the Accept-Language header into the JSON response, as ` + "`" + `stageName` + "`" + ` and ` + "`" + `itemName` + "`" + ` fields",
+                        "name": "localized",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated list of the fields of the pattern matrix elements to respond with, e.g. ` + "`" + `times,quantity` + "`" + `; ` + "`" + `stageId` + "`" + ` and ` + "`" + `pattern` + "`" + ` are always included",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "7d",
+                            "30d",
+                            "90d"
+                        ],
+                        "type": "string",
+                        "description": "Respond with the pattern matrix of the reports of the last 7, 30 or 90 days, up to the start of the current hour, rather than of the latest time ranges of the stages",
+                        "name": "range",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -585,12 +734,32 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/v2.PatternMatrixQueryResult"
+                        },
+                        "headers": {
+                            "X-Penguin-Cache-Age": {
+                                "type": "integer",
+                                "description": "Set on the cached global results, to the seconds elapsed since the result has been calculated"
+                            },
+                            "X-Penguin-Purged-Before": {
+                                "type": "integer",
+                                "description": "Set on personal results that leave out the reports moved to the archive before this time, in unix milliseconds. Those may be requested as an export instead"
+                            },
+                            "X-Penguin-Refresh-After": {
+                                "type": "integer",
+                                "description": "Set on the cached global results, to the seconds after which the result is due to be calculated again"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     },
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -618,6 +787,40 @@ const docTemplate = `{
                         "name": "server",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "Respond with a spreadsheet instead of JSON",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "zh",
+                            "en",
+                            "ja",
+                            "ko"
+                        ],
+                        "type": "string",
+                        "description": "Language of the stage codes and item names; default to the best match of the Accept-Language header, or zh. Implies ` + "`" + `localized` + "`" + ` for JSON responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Embed the stage and item names in the language selected by ` + "`" + `lang` + "`" + ` or the Accept-Language header into the JSON response, as ` + "`" + `stageName` + "`" + ` and ` + "`" + `itemName` + "`" + ` fields",
+                        "name": "localized",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated list of the fields of the item trends to respond with, e.g. ` + "`" + `quantity` + "`" + `",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -625,12 +828,22 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/v2.TrendQueryResult"
+                        },
+                        "headers": {
+                            "X-Penguin-Cache-Age": {
+                                "type": "integer",
+                                "description": "Set to the seconds elapsed since the trends have been calculated"
+                            },
+                            "X-Penguin-Refresh-After": {
+                                "type": "integer",
+                                "description": "Set to the seconds after which the trends are due to be calculated again"
+                            }
                         }
                     },
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -673,7 +886,7 @@ const docTemplate = `{
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -722,13 +935,13 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid or missing stageId. Notice that this shall be the **string ID** of the stage, instead of the internally used numerical ID of the stage.",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     },
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -771,7 +984,7 @@ const docTemplate = `{
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -815,7 +1028,7 @@ const docTemplate = `{
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -858,7 +1071,7 @@ const docTemplate = `{
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
@@ -907,719 +1120,5191 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid or missing zoneId. Notice that this shall be the **string ID** of the zone, instead of the v3 API internally used numerical ID of the zone.",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     },
                     "500": {
                         "description": "An unexpected error occurred",
                         "schema": {
-                            "$ref": "#/definitions/pgerr.PenguinError"
+                            "$ref": "#/definitions/pgerr.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "exusiai_dev_backend-next_internal_model_v2.Item": {
-            "type": "object",
-            "properties": {
-                "alias": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        },
+        "/api/v3alpha/account/api-usage": {
+            "get": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
                     }
-                },
-                "existence": {
-                    "type": "object"
-                },
-                "groupID": {
-                    "type": "string"
-                },
-                "itemId": {
-                    "type": "string"
-                },
-                "itemType": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "name_i18n": {
-                    "type": "object"
-                },
-                "pron": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+                ],
+                "description": "Get the daily quotas of the API key, along with its usage of the recent days (in UTC). Requests made with\nan API key carry X-RateLimit-Limit and X-RateLimit-Remaining headers of the daily request quota as well.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Get API Usage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of recent days to include, up to 31; default to 7",
+                        "name": "days",
+                        "in": "query"
                     }
-                },
-                "rarity": {
-                    "type": "integer"
-                },
-                "sortId": {
-                    "type": "integer"
-                },
-                "spriteCoord": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.APIUsage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API key"
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
                     }
                 }
             }
         },
-        "exusiai_dev_backend-next_internal_model_v2.SiteStats": {
-            "type": "object",
-            "properties": {
-                "totalApCost": {
-                    "type": "integer"
-                },
-                "totalItemQuantities": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v2.TotalItemQuantity"
+        "/api/v3alpha/account/appeals": {
+            "post": {
+                "security": [
+                    {
+                        "PenguinIDAuth": []
                     }
-                },
-                "totalStageTimes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v2.TotalStageTime"
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Submit an Account Appeal",
+                "parameters": [
+                    {
+                        "description": "Appeal request",
+                        "name": "appeal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v3.SubmitAppealRequest"
+                        }
                     }
-                },
-                "totalStageTimes_24h": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v2.TotalStageTime"
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountAppeal"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "An appeal is already pending",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
                     }
                 }
             }
         },
-        "exusiai_dev_backend-next_internal_model_v2.Stage": {
-            "type": "object",
-            "properties": {
-                "apCost": {
-                    "type": "integer",
-                    "example": 6
-                },
-                "code": {
-                    "type": "string",
-                    "example": "1-7"
-                },
-                "code_i18n": {
-                    "type": "object"
-                },
-                "dropInfos": {
+        "/api/v3alpha/account/leaderboard-settings": {
+            "get": {
+                "security": [
+                    {
+                        "PenguinIDAuth": []
+                    }
+                ],
+                "description": "Get whether and under which name the account is listed on the contributor leaderboards of the events.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Get Leaderboard Settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.AccountLeaderboardSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "PenguinID is missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "PenguinIDAuth": []
+                    }
+                ],
+                "description": "Opt the account in or out of the contributor leaderboards of the events, and set the name it is listed under. Accounts are never listed unless opted in. The leaderboards pick the changes up within a few minutes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Update Leaderboard Settings",
+                "parameters": [
+                    {
+                        "description": "Leaderboard settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v3.UpdateLeaderboardSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.AccountLeaderboardSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/account/standing": {
+            "get": {
+                "security": [
+                    {
+                        "PenguinIDAuth": []
+                    }
+                ],
+                "description": "Get the standing of the account, i.e. how its recent reports have been treated by the verifiers, along with its latest appeal.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Get Account Standing",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.AccountStanding"
+                        }
+                    },
+                    "400": {
+                        "description": "PenguinID is missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/dataset/aggregated/{source}/{category}/{server}/item/{itemId}": {
+            "get": {
+                "description": "Get the drop matrix and trends of an item across stages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dataset"
+                ],
+                "summary": "Get Aggregated Stats of an Item",
+                "parameters": [
+                    {
+                        "enum": [
+                            "global",
+                            "personal"
+                        ],
+                        "type": "string",
+                        "description": "Source; ` + "`" + `personal` + "`" + ` requires PenguinIDAuth",
+                        "name": "source",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "all",
+                            "automated",
+                            "manual"
+                        ],
+                        "type": "string",
+                        "description": "Source category",
+                        "name": "category",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.AggregatedItemStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/dataset/aggregated/{source}/{category}/{server}/stage/{stageId}": {
+            "get": {
+                "description": "Get the drop matrix, trends and patterns of a stage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dataset"
+                ],
+                "summary": "Get Aggregated Stats of a Stage",
+                "parameters": [
+                    {
+                        "enum": [
+                            "global",
+                            "personal"
+                        ],
+                        "type": "string",
+                        "description": "Source; ` + "`" + `personal` + "`" + ` requires PenguinIDAuth",
+                        "name": "source",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "all",
+                            "automated",
+                            "manual"
+                        ],
+                        "type": "string",
+                        "description": "Source category",
+                        "name": "category",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stage ID",
+                        "name": "stageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.AggregatedStageStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/events": {
+            "get": {
+                "description": "Get the events as scheduled in each server, along with whether they are upcoming, active or past right now. The time ranges of the events and the existence of their zones are derived from these schedules.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Get All Events",
+                "parameters": [
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "UPCOMING",
+                            "ACTIVE",
+                            "PAST"
+                        ],
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v3.EventListing"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/events/past/{eventId}/summary": {
+            "get": {
+                "description": "Get the final statistics of the stages of a past event in the server: the drop matrix, the total number of accepted reports and the most reported drop patterns of each stage, up to 5 of each. The summary is frozen about an hour after the event closes, and never changes afterwards even as the reports of the event are archived.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Get Past Event Summary",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "eventId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.EventSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "The event has not been summarized in the server, as it is not past yet",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/events/{eventId}/leaderboard": {
+            "get": {
+                "description": "Get the top contributors of the event in the server, ranked by the number of their accepted reports for the stages of the event, or by the sanity estimated to be spent on them. Only the accounts opted in are listed. The leaderboard is cached for 5 minutes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Get Event Leaderboard",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "eventId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "reports",
+                            "sanity"
+                        ],
+                        "type": "string",
+                        "description": "Ranking metric; default to sanity",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of contributors to list, up to 100; default to 20",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.EventLeaderboard"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, or the event is not scheduled in the server",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/incremental/{server}/{realm}/latest": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incremental"
+                ],
+                "summary": "Get Latest Incremental Version",
+                "parameters": [
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Realm of the snapshots",
+                        "name": "realm",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtov3.GetLatestIncrementalVersionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No snapshot found",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/incremental/{server}/{realm}/patch/{versions}": {
+            "get": {
+                "description": "Get a bsdiff patch between two versions of a snapshot. Responds with 204 when the versions are identical.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Incremental"
+                ],
+                "summary": "Get Diff between Versions",
+                "parameters": [
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Realm of the snapshots",
+                        "name": "realm",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `from` + "`" + ` and ` + "`" + `to` + "`" + ` versions, separated by three dots",
+                        "name": "versions",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "204": {
+                        "description": "The versions are identical"
+                    },
+                    "400": {
+                        "description": "Invalid versions",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/init": {
+            "get": {
+                "description": "Get all items, stages and zones in one request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Init"
+                ],
+                "summary": "Get Init Bundle",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.Init"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/item-values": {
+            "get": {
+                "description": "Get the sanity value of the items in a server, in every preset such as ` + "`" + `market` + "`" + ` for the prices in the shops or ` + "`" + `efficiency` + "`" + ` for the sanity spent on the most efficient stages to farm them. The values are maintained from the community calculators.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Item"
+                ],
+                "summary": "Get Item Values",
+                "parameters": [
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "market",
+                        "description": "Only return the values of the preset",
+                        "name": "preset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v3.ItemValueTable"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/items": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Item"
+                ],
+                "summary": "Get All Items",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Item"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/items/icons": {
+            "get": {
+                "description": "Get where the icons of the items are, on the sprite sheet or standalone. The version changes whenever any icon does, so that clients may use it to cache-bust.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Item"
+                ],
+                "summary": "Get Item Icons",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.ItemIcons"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/items/search": {
+            "get": {
+                "description": "Fuzzy match items by their localized names and community aliases, so that clients can resolve item names to item IDs. Best matches come first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Item"
+                ],
+                "summary": "Search Items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query, up to 64 characters",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results, from 1 to 50; default to 10",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ItemSearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/items/{itemId}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Item"
+                ],
+                "summary": "Get an Item with ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Item"
+                        }
+                    },
+                    "404": {
+                        "description": "Item not found",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/meta/bundle": {
+            "get": {
+                "description": "Get all items, stages and zones in one response, along with the version of them. Pass the version you have as ` + "`" + `since` + "`" + ` to receive only the items, stages and zones changed since then, or 304 when nothing has changed. The full bundle is responded when ` + "`" + `since` + "`" + ` is missing or too old.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Meta"
+                ],
+                "summary": "Get Metadata Bundle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The version of the metadata bundle the client has",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.MetaBundle"
+                        }
+                    },
+                    "304": {
+                        "description": "Nothing has changed since the given version"
+                    },
+                    "400": {
+                        "description": "Invalid version",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/meta/freshness": {
+            "get": {
+                "description": "Get when the matrix, the trends and the pattern matrix of every server have last been refreshed, along with when the last report accepted in the server has been submitted, to detect stalled pipelines: reports being accepted while an aggregation is not refreshed for long tells the aggregation has stalled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Meta"
+                ],
+                "summary": "Get Data Freshness",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.Freshness"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/meta/ui-config": {
+            "get": {
+                "description": "Get what the frontend shows in a server: the zones and their stages existing in it, the events active or upcoming in it, and whether it is open. Pass the version you have as ` + "`" + `version` + "`" + ` to receive 304 when nothing has changed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Meta"
+                ],
+                "summary": "Get UI Config",
+                "parameters": [
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The version of the UI config the client has",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.UIConfig"
+                        }
+                    },
+                    "304": {
+                        "description": "Nothing has changed since the given version"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/notices": {
+            "get": {
+                "description": "Get the announcements to show now, such as maintenances or data issues, with their content in every language available. Notices are only returned within their display windows.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notice"
+                ],
+                "summary": "Get Active Notices",
+                "parameters": [
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Only return the notices shown in the server",
+                        "name": "server",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v3.Notice"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/recognition/stage-hashes": {
+            "get": {
+                "description": "Get the perceptual hashes of the result screens of the stages in a server, which the recognition clients identify the stages by. The version changes whenever any hash does, so that clients may only refetch the registry then.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recognition"
+                ],
+                "summary": "Get Recognition Stage Hashes",
+                "parameters": [
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.RecognitionStageHashes"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/report": {
+            "post": {
+                "security": [
+                    {
+                        "PenguinIDAuth": []
+                    }
+                ],
+                "description": "Submit a Drop Report with ark stage and item IDs. The report is rejected with an ` + "`" + `INVALID_REPORT` + "`" + ` error listing the ` + "`" + `issues` + "`" + ` found when the stage is not open, or when any drop is unknown, duplicated or not expected from the stage. Unusual drops that do not reject the report are responded as ` + "`" + `warnings` + "`" + `. You can use the ` + "`" + `reportHash` + "`" + ` in the response to recall the report with the v2 API in 24 hours after it has been submitted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Submit a Drop Report",
+                "parameters": [
+                    {
+                        "description": "Report request",
+                        "name": "report",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.V3ReportRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "The solution to a challenge as ` + "`" + `\u003cnonce\u003e:\u003ccounter\u003e` + "`" + `, required from the accounts that have never submitted a report should the challenges be enabled",
+                        "name": "X-Penguin-Challenge-Solution",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Report has been successfully submitted",
+                        "schema": {
+                            "$ref": "#/definitions/v3.ReportResponse"
+                        },
+                        "headers": {
+                            "X-Penguin-Challenge": {
+                                "type": "string",
+                                "description": "Set to ` + "`" + `required` + "`" + ` on the reports submitted from the networks flagged for having hosted spam, which are held to a stricter rate limit. The client shall present a challenge should it keep on submitting"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Report has been rejected",
+                        "schema": {
+                            "$ref": "#/definitions/v3.ReportRejection"
+                        }
+                    },
+                    "426": {
+                        "description": "The version of the client is no longer accepted and has to be upgraded",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "The account has never submitted a report and a solved challenge is required, see /api/v3alpha/report/challenge",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many reports submitted from a flagged network",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/report/challenge": {
+            "post": {
+                "description": "Issue a proof of work challenge to solve before submitting a report. A counter is to be found such that the SHA-256 digest of ` + "`" + `\u003cnonce\u003e:\u003ccounter\u003e` + "`" + ` starts with at least ` + "`" + `difficulty` + "`" + ` zero bits, and presented as ` + "`" + `\u003cnonce\u003e:\u003ccounter\u003e` + "`" + ` in the ` + "`" + `X-Penguin-Challenge-Solution` + "`" + ` header of the submission before ` + "`" + `expiresAt` + "`" + `. Each challenge can only be redeemed once. Only the accounts that have never submitted a report are required a challenge, and only while the challenges are enabled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Issue a Submission Challenge",
+                "responses": {
+                    "200": {
+                        "description": "Challenge to solve",
+                        "schema": {
+                            "$ref": "#/definitions/v3.SubmissionChallenge"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many challenges requested",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/report/recognition-defect": {
+            "post": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Upload a case the recognition of an official client was unsure about, for the algorithm team to triage. Only the metadata of the case is accepted: the recognition result must not exceed 64 KiB nor embed any image.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Submit a Recognition Defect",
+                "parameters": [
+                    {
+                        "description": "Recognition defect",
+                        "name": "defect",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.V3RecognitionDefectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Defect has been stored",
+                        "schema": {
+                            "$ref": "#/definitions/v3.RecognitionDefectReceipt"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API key"
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/result/advanced": {
+            "post": {
+                "security": [
+                    {
+                        "PenguinIDAuth": []
+                    }
+                ],
+                "description": "Run a batch of drop matrix, trend and pattern matrix queries of custom time ranges concurrently. The results are keyed by the indices of the queries, each being either the result of the query, in the format of the v2 API, or the error it has failed with. Drop matrices with an ` + "`" + `interval` + "`" + ` are split into segments of the interval, aligned to the days of the server as trends are, the elements of each carrying the start and end of their segment. The queries not completed within the time budget of the batch fail with a ` + "`" + `TIMEOUT` + "`" + ` error, leaving the others intact. The queries estimated to be too costly, by the days spanned times the items filtered, are rejected with a ` + "`" + `QUERY_TOO_COSTLY` + "`" + ` error before being run. Personal queries require a PenguinID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Result"
+                ],
+                "summary": "Execute Advanced Queries",
+                "parameters": [
+                    {
+                        "description": "Queries",
+                        "name": "query",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.AdvancedQueryV3Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.AdvancedQueryResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, or PenguinID is missing or invalid for personal queries",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many advanced queries sent",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/result/matrix/{server}/delta": {
+            "get": {
+                "description": "Get the elements of the global drop matrix, including closed zones, that have changed since the given time. Pass the ` + "`" + `until` + "`" + ` of the previous response as ` + "`" + `since` + "`" + ` to keep a local copy of the matrix up to date; the whole matrix is responded when ` + "`" + `since` + "`" + ` is missing or older than the snapshots kept. Responds with 304 when the matrix has not been refreshed since then.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Result"
+                ],
+                "summary": "Get Drop Matrix Delta",
+                "parameters": [
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Time of the matrix the client has, in milliseconds",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.DropMatrixDelta"
+                        }
+                    },
+                    "304": {
+                        "description": "The matrix has not been refreshed since the given time"
+                    },
+                    "400": {
+                        "description": "Invalid server or time",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/result/stage-drops/{server}": {
+            "get": {
+                "description": "Get the average and the standard deviation of the number of items dropped per run of each stage, computed from the global pattern matrix of all patterns, so that the variance of the drops of a run may be shown along with the drop rates of the drop matrix.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Result"
+                ],
+                "summary": "Get Stage Drop Stats",
+                "parameters": [
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.StageDropStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid server",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/result/times-distribution/{server}/{stageId}": {
+            "get": {
+                "description": "Get how many distinct accounts have reported the stage, and a histogram of the number of reliable reports per account in powers of two, as a hint of whether the sample of the stage is dominated by a few accounts. The distribution is refreshed hourly.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Result"
+                ],
+                "summary": "Get Times Distribution of a Stage",
+                "parameters": [
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stage ID",
+                        "name": "stageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.TimesDistribution"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid server",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Stage not found",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/shortlinks": {
+            "post": {
+                "description": "Store an advanced query under a short code, so that the matrices and trends filtered by it can be shared as a permalink. The stages and items queried must exist, and the query is normalized before being stored, so that sharing the same query again returns the same code with its expiry extended. Creations are rate limited per client.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShortLink"
+                ],
+                "summary": "Create Short Link",
+                "parameters": [
+                    {
+                        "description": "Advanced query to share",
+                        "name": "query",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.AdvancedQueryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v3.ShortLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many short links created",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/shortlinks/{code}": {
+            "get": {
+                "description": "Get the advanced query stored under a short code, to be sent as is to the advanced query API. Expired links are not found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShortLink"
+                ],
+                "summary": "Resolve Short Link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Code of the short link",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.ShortLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, or no short link found under the code",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/stages": {
+            "get": {
+                "description": "Get all stages. When ` + "`" + `server` + "`" + ` is given, only the stages existing in that server are listed, along with whether they are open right now and the time range of their drop infos currently in effect; ` + "`" + `open=true` + "`" + ` further limits the list to open stages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stage"
+                ],
+                "summary": "Get All Stages",
+                "parameters": [
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only list open stages; requires ` + "`" + `server` + "`" + `",
+                        "name": "open",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v3.StageListing"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/stages/{stageId}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stage"
+                ],
+                "summary": "Get a Stage with ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stage ID",
+                        "name": "stageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Stage"
+                        }
+                    },
+                    "404": {
+                        "description": "Stage not found",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/stages/{stageId}/dropinfos": {
+            "get": {
+                "description": "Get the drop set, drop types and bounds of a stage per time range, latest first, so that recognition clients are able to validate results locally before submitting them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stage"
+                ],
+                "summary": "Get Drop Infos of a Stage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stage ID",
+                        "name": "stageId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server; default to CN",
+                        "name": "server",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.StageDropInfos"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Stage not found",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/stages/{stageId}/forecast": {
+            "get": {
+                "description": "Estimate when the sample of a stage in its time range currently in effect reaches ` + "`" + `target` + "`" + ` times, by extrapolating the rate the stage has been reported at over a rolling window. ` + "`" + `estimatedAt` + "`" + ` is null when the target has been reached already, no reports have come in over the window, or the time range is estimated to end before then.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stage"
+                ],
+                "summary": "Get Sample Forecast of a Stage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stage ID",
+                        "name": "stageId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server; default to CN",
+                        "name": "server",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of times to forecast; default to the configured target",
+                        "name": "target",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.SampleForecast"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Stage not found, or has no drop infos in effect in the server",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/stats/heatmap/{server}": {
+            "get": {
+                "description": "Get the number of reports accepted in the server within the last days, by the weekday and the hour they were submitted at in the time zone of the server. The current hour is left out as it is incomplete.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SiteStats"
+                ],
+                "summary": "Get Report Heatmap",
+                "parameters": [
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of recent days to include, up to 90; default to 28",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.ReportHeatmap"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/stats/site": {
+            "get": {
+                "description": "Get the headline numbers of the site, with a per-server breakdown.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SiteStats"
+                ],
+                "summary": "Get Site Stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.SiteStats"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/tools/plan": {
+            "post": {
+                "description": "Plan the runs of the open stages spending the least sanity for the requested items to be expected to drop, by solving a linear program over the sanity of the stages and their drop rates in the global drop matrix. Only the stages reported enough times are planned on; the items none of them drops are listed as ` + "`" + `unreachable` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tool"
+                ],
+                "summary": "Plan Farming",
+                "parameters": [
+                    {
+                        "description": "Items to farm",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.Plan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/tools/simulate": {
+            "get": {
+                "description": "Get the expected quantity of each item dropped from clearing a stage a number of times, along with its variance, from the global drop matrix of the stage in its latest time range. When the pattern matrix of the stage is available, the probability of each pattern and of each item dropping at least once are given too. The runs are assumed to be independent.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tool"
+                ],
+                "summary": "Simulate Drops",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "main_01-07",
+                        "description": "Stage ID",
+                        "name": "stageId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of runs, up to 1000000",
+                        "name": "runs",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server; default to CN",
+                        "name": "server",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v3.Simulation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Stage not found, or has no drop matrix in the server",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get the webhooks subscribed with the API key. Secrets are not included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get Webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Webhook"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API key"
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Subscribe a webhook to events. Events are POSTed as JSON, signed in the X-Penguin-Signature header\nas ` + "`" + `t=\u003cunix seconds\u003e,v1=\u003chex HMAC-SHA256 of \"\u003ct\u003e.\u003cbody\u003e\" keyed with the secret\u003e` + "`" + `. The secret is only\nincluded in this response. Failed deliveries (non-2xx or timeouts) are retried with exponential backoff.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Create Webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API key"
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/webhooks/{webhookId}": {
+            "delete": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Delete Webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid request or webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API key"
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/webhooks/{webhookId}/ping": {
+            "post": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Deliver a ` + "`" + `ping` + "`" + ` event to the webhook, to test the receiving endpoint.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Ping Webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API key"
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/zones": {
+            "get": {
+                "description": "Get all zones. When ` + "`" + `server` + "`" + ` is given, only the zones existing in that server are listed, along with whether they are open right now; ` + "`" + `open=true` + "`" + ` further limits the list to open zones.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Zone"
+                ],
+                "summary": "Get All Zones",
+                "parameters": [
+                    {
+                        "enum": [
+                            "CN",
+                            "US",
+                            "JP",
+                            "KR"
+                        ],
+                        "type": "string",
+                        "description": "Server",
+                        "name": "server",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only list open zones; requires ` + "`" + `server` + "`" + `",
+                        "name": "open",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v3.ZoneListing"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v3alpha/zones/{zoneId}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Zone"
+                ],
+                "summary": "Get a Zone with ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "zoneId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Zone"
+                        }
+                    },
+                    "404": {
+                        "description": "Zone not found",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred",
+                        "schema": {
+                            "$ref": "#/definitions/pgerr.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dtov3.GetLatestIncrementalVersionResponse": {
+            "type": "object",
+            "properties": {
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "exusiai_dev_backend-next_internal_model_v2.Item": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "existence": {
+                    "type": "object"
+                },
+                "groupID": {
+                    "type": "string"
+                },
+                "itemId": {
+                    "type": "string"
+                },
+                "itemType": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "name_i18n": {
+                    "type": "object"
+                },
+                "pron": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rarity": {
+                    "type": "integer"
+                },
+                "sortId": {
+                    "type": "integer"
+                },
+                "spriteCoord": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "exusiai_dev_backend-next_internal_model_v2.SiteStats": {
+            "type": "object",
+            "properties": {
+                "totalApCost": {
+                    "type": "integer"
+                },
+                "totalItemQuantities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.TotalItemQuantity"
+                    }
+                },
+                "totalStageTimes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.TotalStageTime"
+                    }
+                },
+                "totalStageTimes_24h": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.TotalStageTime"
+                    }
+                }
+            }
+        },
+        "exusiai_dev_backend-next_internal_model_v2.Stage": {
+            "type": "object",
+            "properties": {
+                "apCost": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "code": {
+                    "type": "string",
+                    "example": "1-7"
+                },
+                "code_i18n": {
+                    "type": "object"
+                },
+                "dropInfos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.DropInfo"
+                    }
+                },
+                "existence": {
+                    "type": "object"
+                },
+                "isGacha": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "minClearTime": {
+                    "type": "integer",
+                    "example": 118000
+                },
+                "recognitionOnly": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "stageId": {
+                    "type": "string",
+                    "example": "main_01-07"
+                },
+                "stageType": {
+                    "description": "StageType is the type of the stage\n* MAIN - Mainline Stages\n* SUB - Sub Stages\n* ACTIVITY - Activity Stages\n* DAILY - Daily Stages",
+                    "type": "string",
+                    "enum": [
+                        "MAIN",
+                        "SUB",
+                        "ACTIVITY",
+                        "DAILY"
+                    ],
+                    "example": "MAIN"
+                },
+                "zoneId": {
+                    "type": "string",
+                    "example": "main_1"
+                }
+            }
+        },
+        "exusiai_dev_backend-next_internal_model_v2.Zone": {
+            "type": "object",
+            "properties": {
+                "background": {
+                    "type": "string"
+                },
+                "existence": {
+                    "type": "object"
+                },
+                "stages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "subType": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "zoneId": {
+                    "type": "string"
+                },
+                "zoneIndex": {
+                    "type": "integer"
+                },
+                "zoneName": {
+                    "type": "string"
+                },
+                "zoneName_i18n": {
+                    "type": "object"
+                }
+            }
+        },
+        "model.AccountAppeal": {
+            "type": "object",
+            "properties": {
+                "accountId": {
+                    "type": "integer"
+                },
+                "contact": {
+                    "description": "Contact is an optional way for moderators to reach the appellant, e.g. an email address",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "resolution": {
+                    "description": "Resolution is the note left by the moderator when resolving the appeal",
+                    "type": "string"
+                },
+                "resolvedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is one of PENDING, APPROVED or REJECTED",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Bounds": {
+            "type": "object",
+            "properties": {
+                "exceptions": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "lower": {
+                    "type": "integer"
+                },
+                "upper": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Existence": {
+            "type": "object",
+            "required": [
+                "CN",
+                "JP",
+                "KR",
+                "US"
+            ],
+            "properties": {
+                "CN": {
+                    "description": "CN: 国服 Mainland China Server (maintained by Hypergryph Network Technology Co., Ltd.)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ServerExistence"
+                        }
+                    ]
+                },
+                "JP": {
+                    "description": "JP: 日服 Japan Server (maintained by Yostar Inc,.)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ServerExistence"
+                        }
+                    ]
+                },
+                "KR": {
+                    "description": "KR: 韩服 Korea Server (maintained by Yostar Limited)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ServerExistence"
+                        }
+                    ]
+                },
+                "US": {
+                    "description": "US: 美服/国际服 Global Server (maintained by Yostar Limited)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ServerExistence"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.I18nString": {
+            "type": "object",
+            "required": [
+                "en",
+                "ja",
+                "ko",
+                "zh"
+            ],
+            "properties": {
+                "en": {
+                    "description": "EN: English (en)",
+                    "type": "string"
+                },
+                "ja": {
+                    "description": "JP: 日本語 (ja)",
+                    "type": "string"
+                },
+                "ko": {
+                    "description": "KR: 한국어 (ko)",
+                    "type": "string"
+                },
+                "zh": {
+                    "description": "ZH: 中文 (zh-CN)",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Item": {
+            "type": "object",
+            "properties": {
+                "deletedAt": {
+                    "description": "DeletedAt is when the item has been deleted. Deleted items are left out of every query unless asked for,\nand may be restored.",
+                    "type": "string"
+                },
+                "existence": {
+                    "description": "Existence is a map with server code as key and the existence of the item in that server as value.",
+                    "type": "object"
+                },
+                "game": {
+                    "description": "Game is the game the item is from, which defaults to arknights.",
+                    "type": "string"
+                },
+                "group": {
+                    "description": "Group is an identifier of what the item actually is. For example, both orirock and orirock cube would have the same group, ` + "`" + `orirock` + "`" + `.",
+                    "type": "string"
+                },
+                "iconUrl": {
+                    "description": "IconURL is the URL of the standalone icon of the item, which changes whenever the icon does.",
+                    "type": "string"
+                },
+                "itemId": {
+                    "description": "ArkItemID (itemId) is the previously used, string form ID of the item; in JSON-representation ` + "`" + `itemId` + "`" + ` is used as key.",
+                    "type": "string"
+                },
+                "keywords": {
+                    "description": "Keywords is an arbitrary JSON object containing the keywords of the item, for optimizing the results of the frontend built-in search engine.",
+                    "type": "object"
+                },
+                "name": {
+                    "description": "Name is a map with language code as key and the name of the item in that language as value.",
+                    "type": "object"
+                },
+                "penguinItemId": {
+                    "description": "ItemID (penguinItemId) is the numerical ID of the item.",
+                    "type": "integer"
+                },
+                "rarity": {
+                    "type": "integer"
+                },
+                "sortId": {
+                    "description": "SortID is the sort position of the item.",
+                    "type": "integer"
+                },
+                "sprite": {
+                    "description": "Sprite describes the location of the item's sprite on the sprite image, in a form of Y:X.",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ItemSearchResult": {
+            "type": "object",
+            "properties": {
+                "deletedAt": {
+                    "description": "DeletedAt is when the item has been deleted. Deleted items are left out of every query unless asked for,\nand may be restored.",
+                    "type": "string"
+                },
+                "existence": {
+                    "description": "Existence is a map with server code as key and the existence of the item in that server as value.",
+                    "type": "object"
+                },
+                "game": {
+                    "description": "Game is the game the item is from, which defaults to arknights.",
+                    "type": "string"
+                },
+                "group": {
+                    "description": "Group is an identifier of what the item actually is. For example, both orirock and orirock cube would have the same group, ` + "`" + `orirock` + "`" + `.",
+                    "type": "string"
+                },
+                "iconUrl": {
+                    "description": "IconURL is the URL of the standalone icon of the item, which changes whenever the icon does.",
+                    "type": "string"
+                },
+                "itemId": {
+                    "description": "ArkItemID (itemId) is the previously used, string form ID of the item; in JSON-representation ` + "`" + `itemId` + "`" + ` is used as key.",
+                    "type": "string"
+                },
+                "keywords": {
+                    "description": "Keywords is an arbitrary JSON object containing the keywords of the item, for optimizing the results of the frontend built-in search engine.",
+                    "type": "object"
+                },
+                "name": {
+                    "description": "Name is a map with language code as key and the name of the item in that language as value.",
+                    "type": "object"
+                },
+                "penguinItemId": {
+                    "description": "ItemID (penguinItemId) is the numerical ID of the item.",
+                    "type": "integer"
+                },
+                "rarity": {
+                    "type": "integer"
+                },
+                "score": {
+                    "description": "Score is the trigram word similarity between the query and the names and keywords of the item, from 0 to 1.",
+                    "type": "number"
+                },
+                "sortId": {
+                    "description": "SortID is the sort position of the item.",
+                    "type": "integer"
+                },
+                "sprite": {
+                    "description": "Sprite describes the location of the item's sprite on the sprite image, in a form of Y:X.",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Notice": {
+            "type": "object",
+            "properties": {
+                "content_i18n": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "endTime": {
+                    "type": "string"
+                },
+                "existence": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "Kind is one of general, maintenance and data_issue.",
+                    "type": "string"
+                },
+                "severity": {
+                    "type": "integer"
+                },
+                "startTime": {
+                    "description": "StartTime and EndTime are the display window of the notice, a bound left null being open.",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ServerExistence": {
+            "type": "object",
+            "required": [
+                "exist"
+            ],
+            "properties": {
+                "closeTime": {
+                    "type": "integer",
+                    "example": 1635966000000
+                },
+                "exist": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "openTime": {
+                    "type": "integer",
+                    "example": 1634799600000
+                }
+            }
+        },
+        "model.Stage": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is a map with language code as key and the code of the stage in that language as value.",
+                    "type": "object"
+                },
+                "deletedAt": {
+                    "description": "DeletedAt is when the stage has been deleted. Deleted stages are left out of every query unless asked for,\nand may be restored.",
+                    "type": "string"
+                },
+                "existence": {
+                    "description": "Existence is a map with server code as key and the existence of the item in that server as value.",
+                    "type": "object"
+                },
+                "extraProcessType": {
+                    "description": "ExtraProcessType is the type of extra process that is used in the stage, e.g. \"GACHABOX\".",
+                    "type": "string"
+                },
+                "game": {
+                    "description": "Game is the game the stage is from, which defaults to arknights.",
+                    "type": "string"
+                },
+                "minClearTime": {
+                    "description": "MinClearTime is the minimum time (in milliseconds as a duration) it takes to clear the stage, referencing from prts.wiki",
+                    "type": "integer"
+                },
+                "penguinStageId": {
+                    "description": "StageID (penguinStageId) is the numerical ID of the stage.",
+                    "type": "integer"
+                },
+                "sanity": {
+                    "description": "Sanity is the sanity requirement for a full clear of the stage.",
+                    "type": "integer"
+                },
+                "stageId": {
+                    "description": "ArkStageID (stageId) is the previously used, string form ID of the stage; in JSON-representation ` + "`" + `stageId` + "`" + ` is used as key.",
+                    "type": "string"
+                },
+                "stageType": {
+                    "description": "StageType is the type of the stage, e.g. \"MAIN\", \"SUB\", \"ACTIVITY\" and \"DAILY\".",
+                    "type": "string"
+                },
+                "zoneId": {
+                    "description": "ZoneID is the numerical ID of the zone the stage is in.",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TimeRange": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "endTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "server": {
+                    "type": "string"
+                },
+                "startTime": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Webhook": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "events": {
+                    "description": "Events are the event types the webhook is subscribed to",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "secret": {
+                    "description": "Secret is used to sign the payloads delivered to the webhook. It is only revealed upon subscription.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookEvent": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "data": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Zone": {
+            "type": "object",
+            "properties": {
+                "background": {
+                    "description": "Background is the path of the background image of the zone, relative to the CDN endpoint.",
+                    "type": "string"
+                },
+                "category": {
+                    "description": "Category of the zone.",
+                    "type": "string",
+                    "example": "MAINLINE"
+                },
+                "existence": {
+                    "description": "Existence is a map with server code as key and the existence of the item in that server as value.",
+                    "type": "object"
+                },
+                "game": {
+                    "description": "Game is the game the zone is from, which defaults to arknights.",
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name is a map with language code as key and the name of the item in that language as value.",
+                    "type": "object"
+                },
+                "penguinZoneId": {
+                    "description": "ZoneID is the numerical ID of the zone.",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Type of the zone, e.g. \"AWAKENING_HOUR\" or \"VISION_SHATTER\". Optional and only occurs when ` + "`" + `category` + "`" + ` is \"MAINLINE\".",
+                    "type": "string",
+                    "example": "AWAKENING_HOUR"
+                },
+                "zoneId": {
+                    "type": "string"
+                }
+            }
+        },
+        "pgerr.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "INVALID_REQUEST"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "invalid request: some or all request parameters are invalid"
+                },
+                "errorId": {
+                    "description": "ErrorID identifies the occurrence of the problem, which is the request ID the logs of the request carry",
+                    "type": "string",
+                    "example": "cn0pqe2s1f2g1bq6cm0g"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/api/v3alpha/items/search"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid request: some or all request parameters are invalid"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Invalid Request"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "urn:penguin-stats:problem:invalid-request"
+                }
+            }
+        },
+        "types.AdvancedQuery": {
+            "type": "object",
+            "required": [
+                "server",
+                "stageId"
+            ],
+            "properties": {
+                "end": {
+                    "type": "integer"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "isPersonal": {
+                    "type": "boolean"
+                },
+                "itemIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "server": {
+                    "type": "string"
+                },
+                "sourceCategory": {
+                    "type": "string"
+                },
+                "stageId": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.AdvancedQueryRequest": {
+            "type": "object",
+            "required": [
+                "queries"
+            ],
+            "properties": {
+                "queries": {
+                    "type": "array",
+                    "maxItems": 5,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.AdvancedQuery"
+                    }
+                }
+            }
+        },
+        "types.AdvancedQueryV3": {
+            "type": "object",
+            "required": [
+                "server",
+                "stageId",
+                "type"
+            ],
+            "properties": {
+                "end": {
+                    "type": "integer"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "isPersonal": {
+                    "type": "boolean"
+                },
+                "itemIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "server": {
+                    "type": "string"
+                },
+                "sourceCategory": {
+                    "type": "string"
+                },
+                "stageId": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Type is one of matrix, trend and pattern. Trends require Interval, which splits matrices into segments of\nthe interval as well, and patterns accept neither Interval nor ItemIDs.",
+                    "type": "string",
+                    "example": "trend"
+                }
+            }
+        },
+        "types.AdvancedQueryV3Request": {
+            "type": "object",
+            "required": [
+                "queries"
+            ],
+            "properties": {
+                "queries": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.AdvancedQueryV3"
+                    }
+                }
+            }
+        },
+        "types.ArkDrop": {
+            "type": "object",
+            "required": [
+                "dropType",
+                "itemId",
+                "quantity"
+            ],
+            "properties": {
+                "dropType": {
+                    "type": "string",
+                    "enum": [
+                        "REGULAR_DROP",
+                        "NORMAL_DROP",
+                        "SPECIAL_DROP",
+                        "EXTRA_DROP",
+                        "FURNITURE"
+                    ]
+                },
+                "itemId": {
+                    "type": "string",
+                    "example": "30013"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 0
+                }
+            }
+        },
+        "types.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "matrix.refreshed"
+                    ]
+                },
+                "url": {
+                    "description": "URL is where the events are posted to, which must be https and resolve to public addresses only",
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://example.com/penguin-webhook"
+                }
+            }
+        },
+        "types.PlanRequest": {
+            "type": "object",
+            "required": [
+                "items",
+                "server"
+            ],
+            "properties": {
+                "excludeStageIds": {
+                    "description": "ExcludeStageIDs are the stages not to be farmed, e.g. those not unlocked yet",
+                    "type": "array",
+                    "maxItems": 1024,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "main_01-07"
+                    ]
+                },
+                "items": {
+                    "type": "array",
+                    "maxItems": 256,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.PlanRequestItem"
+                    }
+                },
+                "server": {
+                    "type": "string",
+                    "example": "CN"
+                }
+            }
+        },
+        "types.PlanRequestItem": {
+            "type": "object",
+            "required": [
+                "itemId",
+                "quantity"
+            ],
+            "properties": {
+                "itemId": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "30012"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 1000000,
+                    "minimum": 1,
+                    "example": 100
+                }
+            }
+        },
+        "types.ReportRequestMetadata": {
+            "type": "object",
+            "properties": {
+                "fileName": {
+                    "type": "string",
+                    "maxLength": 512
+                },
+                "fingerprint": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "lastModified": {
+                    "type": "integer"
+                },
+                "md5": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "recognizerAssetsVersion": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "recognizerVersion": {
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "types.SingularReportRecallRequest": {
+            "type": "object",
+            "required": [
+                "reportHash"
+            ],
+            "properties": {
+                "reportHash": {
+                    "type": "string",
+                    "example": "cahbuch1eqliv7dopen0-5ejlUrfzNMXNHY6Q"
+                }
+            }
+        },
+        "types.SingularReportRequest": {
+            "type": "object",
+            "required": [
+                "server",
+                "source",
+                "stageId",
+                "version"
+            ],
+            "properties": {
+                "drops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ArkDrop"
+                    }
+                },
+                "metadata": {
+                    "$ref": "#/definitions/types.ReportRequestMetadata"
+                },
+                "server": {
+                    "type": "string",
+                    "example": "CN"
+                },
+                "source": {
+                    "description": "Source describes a source of the report. Third-party API consumers should change this to their own name.",
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "your-app-name"
+                },
+                "stageId": {
+                    "type": "string",
+                    "example": "main_01-07"
+                },
+                "times": {
+                    "type": "integer",
+                    "maximum": 6,
+                    "minimum": 0
+                },
+                "version": {
+                    "description": "Version describes the version of the source app used to submit this report. Third-party API consumers should change this to their own app version.",
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "v0.0.0+0000000"
+                }
+            }
+        },
+        "types.V3RecognitionDefectRequest": {
+            "type": "object",
+            "required": [
+                "client",
+                "reason",
+                "recognitionResult",
+                "server",
+                "sessionId"
+            ],
+            "properties": {
+                "client": {
+                    "$ref": "#/definitions/types.V3ReportClient"
+                },
+                "metadata": {
+                    "description": "Metadata are further details of the environment, e.g. the versions of the recognizer and its assets",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "reason": {
+                    "description": "Reason describes what the recognition was unsure about",
+                    "type": "string",
+                    "maxLength": 256,
+                    "example": "low confidence on item quantity"
+                },
+                "recognitionResult": {
+                    "description": "RecognitionResult is the raw result of the recognizer",
+                    "type": "object"
+                },
+                "server": {
+                    "type": "string",
+                    "example": "CN"
+                },
+                "sessionId": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "stageId": {
+                    "description": "StageID is the ark stage id recognized, if any",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "main_01-07"
+                }
+            }
+        },
+        "types.V3ReportClient": {
+            "type": "object",
+            "required": [
+                "source",
+                "version"
+            ],
+            "properties": {
+                "source": {
+                    "description": "Source is the name of the app. Third-party API consumers should change this to their own name.",
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "your-app-name"
+                },
+                "version": {
+                    "description": "Version is the version of the app",
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "v0.0.0+0000000"
+                }
+            }
+        },
+        "types.V3ReportDrop": {
+            "type": "object",
+            "required": [
+                "dropType",
+                "itemId"
+            ],
+            "properties": {
+                "dropType": {
+                    "type": "string",
+                    "enum": [
+                        "REGULAR_DROP",
+                        "SPECIAL_DROP",
+                        "EXTRA_DROP",
+                        "FURNITURE"
+                    ],
+                    "example": "REGULAR_DROP"
+                },
+                "itemId": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "30013"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1,
+                    "example": 1
+                }
+            }
+        },
+        "types.V3ReportRequest": {
+            "type": "object",
+            "required": [
+                "client",
+                "server",
+                "stageId",
+                "times"
+            ],
+            "properties": {
+                "client": {
+                    "$ref": "#/definitions/types.V3ReportClient"
+                },
+                "drops": {
+                    "type": "array",
+                    "maxItems": 64,
+                    "items": {
+                        "$ref": "#/definitions/types.V3ReportDrop"
+                    }
+                },
+                "metadata": {
+                    "$ref": "#/definitions/types.ReportRequestMetadata"
+                },
+                "server": {
+                    "type": "string",
+                    "example": "CN"
+                },
+                "stageId": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "main_01-07"
+                },
+                "times": {
+                    "description": "Times is the number of times the stage has been cleared with the drops reported",
+                    "type": "integer",
+                    "maximum": 6,
+                    "minimum": 1,
+                    "example": 1
+                }
+            }
+        },
+        "v2.Activity": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "integer"
+                },
+                "existence": {
+                    "type": "object"
+                },
+                "label_i18n": {
+                    "type": "object"
+                },
+                "start": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v2.AdvancedQueryResult": {
+            "type": "object",
+            "properties": {
+                "advanced_results": {
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "v2.DropInfo": {
+            "type": "object",
+            "properties": {
+                "bounds": {
+                    "type": "object"
+                },
+                "dropType": {
+                    "type": "string"
+                },
+                "itemId": {
+                    "type": "string"
+                }
+            }
+        },
+        "v2.DropMatrixQueryResult": {
+            "type": "object",
+            "properties": {
+                "matrix": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.OneDropMatrixElement"
+                    }
+                }
+            }
+        },
+        "v2.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "v2.OneDrop": {
+            "type": "object",
+            "properties": {
+                "itemId": {
+                    "type": "string",
+                    "example": "30012"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "v2.OneDropMatrixElement": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "integer"
+                },
+                "itemId": {
+                    "type": "string",
+                    "example": "30012"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 1322056
+                },
+                "rate": {
+                    "description": "Rate is quantity / times rounded to 6 decimal places, or 0 when times is 0",
+                    "type": "number",
+                    "example": 1.245645
+                },
+                "reporters": {
+                    "description": "Reporters is the number of distinct accounts having reported the stage within the time range of the element,\nas a hint of how widely the sample is spread. It is left out of personal results and where unknown.",
+                    "type": "integer",
+                    "example": 12345
+                },
+                "stageId": {
+                    "type": "string",
+                    "example": "main_01-07"
+                },
+                "start": {
+                    "type": "integer",
+                    "example": 1556676000000
+                },
+                "stdDev": {
+                    "type": "number",
+                    "example": 0.114514
+                },
+                "times": {
+                    "type": "integer",
+                    "example": 1061347
+                }
+            }
+        },
+        "v2.OneItemTrend": {
+            "type": "object",
+            "properties": {
+                "quantity": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "rates": {
+                    "description": "Rates are the quantity / times of each of the intervals rounded to 6 decimal places, or 0 when times is 0",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "reporters": {
+                    "description": "Reporters are the numbers of distinct accounts having reported the stage in each of the intervals. They are\nleft out of personal results.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "times": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "v2.OnePatternMatrixElement": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "integer",
+                    "x-nullable": true
+                },
+                "pattern": {
+                    "$ref": "#/definitions/v2.Pattern"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 159486
+                },
+                "rate": {
+                    "description": "Rate is quantity / times rounded to 6 decimal places, or 0 when times is 0",
+                    "type": "number",
+                    "example": 0.248523
+                },
+                "stageId": {
+                    "type": "string",
+                    "example": "main_01-07"
+                },
+                "start": {
+                    "type": "integer",
+                    "example": 1633032000000
+                },
+                "times": {
+                    "type": "integer",
+                    "example": 641734
+                }
+            }
+        },
+        "v2.Pattern": {
+            "type": "object",
+            "properties": {
+                "drops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.OneDrop"
+                    }
+                }
+            }
+        },
+        "v2.PatternMatrixQueryResult": {
+            "type": "object",
+            "properties": {
+                "pattern_matrix": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.OnePatternMatrixElement"
+                    }
+                }
+            }
+        },
+        "v2.RecognitionReportResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "taskId": {
+                    "type": "string",
+                    "example": "0522ce0083000000-1wE2I9dvMFXXzBMpSCYM81rJ0T3tLrAQ"
+                }
+            }
+        },
+        "v2.ReportResponse": {
+            "type": "object",
+            "properties": {
+                "reportHash": {
+                    "type": "string",
+                    "example": "0522ce0083000000-1wE2I9dvMFXXzBMpSCYM81rJ0T3tLrAQ"
+                }
+            }
+        },
+        "v2.StageTrend": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "description": "Buckets are the boundaries of the intervals of the item trends in unix milliseconds, i.e. the start of each\ninterval followed by the end of the last one. Daily intervals start at the start of the days of the server.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "results": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/v2.OneItemTrend"
+                    }
+                },
+                "startTime": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v2.TotalItemQuantity": {
+            "type": "object",
+            "properties": {
+                "itemId": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v2.TotalStageTime": {
+            "type": "object",
+            "properties": {
+                "stageId": {
+                    "type": "string"
+                },
+                "times": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v2.TrendQueryResult": {
+            "type": "object",
+            "properties": {
+                "trend": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/v2.StageTrend"
+                    }
+                }
+            }
+        },
+        "v3.APIUsage": {
+            "type": "object",
+            "properties": {
+                "apiKeyId": {
+                    "type": "integer"
+                },
+                "dailyByteQuota": {
+                    "type": "integer"
+                },
+                "dailyRequestQuota": {
+                    "type": "integer"
+                },
+                "days": {
+                    "description": "Days are the usage of the recent days, with today coming first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.DailyAPIUsage"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                }
+            }
+        },
+        "v3.AccountLeaderboardSettings": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Name is the name the account is listed under, instead of an anonymous one",
+                    "type": "string"
+                },
+                "optIn": {
+                    "description": "OptIn lists the account on the leaderboards. Accounts are never listed unless opted in.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "v3.AccountStanding": {
+            "type": "object",
+            "properties": {
+                "appeal": {
+                    "description": "Appeal is the latest appeal submitted by the account, if any",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.AccountAppeal"
+                        }
+                    ]
+                },
+                "penguinId": {
+                    "type": "string"
+                },
+                "reports": {
+                    "description": "Reports is the breakdown of the reports submitted by the account within the evaluation window",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v3.AccountStandingReports"
+                        }
+                    ]
+                },
+                "standing": {
+                    "type": "string",
+                    "enum": [
+                        "GOOD",
+                        "LIMITED",
+                        "EXCLUDED"
+                    ]
+                },
+                "weight": {
+                    "type": "number"
+                }
+            }
+        },
+        "v3.AccountStandingReports": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "windowDays": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v3.AdvancedQueryResult": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/v3.OneAdvancedQueryResult"
+                    }
+                }
+            }
+        },
+        "v3.AggregatedItemStats": {
+            "type": "object",
+            "properties": {
+                "matrix": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.OneDropMatrixElement"
+                    }
+                },
+                "trends": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/v2.StageTrend"
+                    }
+                }
+            }
+        },
+        "v3.AggregatedStageStats": {
+            "type": "object",
+            "properties": {
+                "matrix": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.OneDropMatrixElement"
+                    }
+                },
+                "patterns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.OnePatternMatrixElement"
+                    }
+                },
+                "trends": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/v2.StageTrend"
+                    }
+                }
+            }
+        },
+        "v3.DailyAPIUsage": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2026-10-17"
+                },
+                "requests": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v3.DropMatrixDelta": {
+            "type": "object",
+            "properties": {
+                "full": {
+                    "description": "Full tells whether the delta contains the whole matrix, as no snapshot old enough is available",
+                    "type": "boolean"
+                },
+                "matrix": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.OneDropMatrixElement"
+                    }
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.DropMatrixElementKey"
+                    }
+                },
+                "server": {
+                    "type": "string",
+                    "example": "CN"
+                },
+                "since": {
+                    "description": "Since is the time of the snapshot of the matrix the delta has been computed against, in milliseconds.\nIt is zero when the delta is full.",
+                    "type": "integer",
+                    "example": 1697500800000
+                },
+                "until": {
+                    "description": "Until is the time of the latest snapshot of the matrix, in milliseconds. Pass it as ` + "`" + `since` + "`" + ` on the next request.",
+                    "type": "integer",
+                    "example": 1697501400000
+                }
+            }
+        },
+        "v3.DropMatrixElementKey": {
+            "type": "object",
+            "properties": {
+                "itemId": {
+                    "type": "string",
+                    "example": "30012"
+                },
+                "stageId": {
+                    "type": "string",
+                    "example": "main_01-07"
+                }
+            }
+        },
+        "v3.DropTypeDropInfo": {
+            "type": "object",
+            "properties": {
+                "bounds": {
+                    "$ref": "#/definitions/model.Bounds"
+                },
+                "dropType": {
+                    "type": "string"
+                }
+            }
+        },
+        "v3.EventLeaderboard": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.LeaderboardEntry"
+                    }
+                },
+                "eventId": {
+                    "type": "integer"
+                },
+                "generatedAt": {
+                    "description": "GeneratedAt is when the leaderboard was calculated; it is cached for a few minutes",
+                    "type": "string"
+                },
+                "server": {
+                    "type": "string"
+                },
+                "sortBy": {
+                    "type": "string",
+                    "enum": [
+                        "reports",
+                        "sanity"
+                    ]
+                }
+            }
+        },
+        "v3.EventListing": {
+            "type": "object",
+            "properties": {
+                "closeTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "openTime": {
+                    "type": "string"
+                },
+                "rangeId": {
+                    "description": "RangeID is the time range derived from the schedule of the event in the server",
+                    "type": "integer"
+                },
+                "server": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is one of UPCOMING, ACTIVE and PAST at the time of the request",
+                    "type": "string",
+                    "example": "ACTIVE"
+                },
+                "zones": {
+                    "description": "Zones are the ark zone ids of the zones of the event",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "v3.EventSummary": {
+            "type": "object",
+            "properties": {
+                "closeTime": {
+                    "type": "string"
+                },
+                "eventId": {
+                    "type": "integer"
+                },
+                "generatedAt": {
+                    "description": "GeneratedAt is when the summary was frozen",
+                    "type": "string"
+                },
+                "matrix": {
+                    "description": "Matrix is the drop matrix of the stages of the event while it was open",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.OneDropMatrixElement"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "openTime": {
+                    "type": "string"
+                },
+                "server": {
+                    "type": "string",
+                    "example": "CN"
+                },
+                "topPatterns": {
+                    "description": "TopPatterns are the most reported drop patterns of each stage of the event while it was open",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.OnePatternMatrixElement"
+                    }
+                },
+                "totalReports": {
+                    "description": "TotalReports is the number of the reports accepted for the stages of the event while it was open",
+                    "type": "integer"
+                }
+            }
+        },
+        "v3.Freshness": {
+            "type": "object",
+            "properties": {
+                "generatedAt": {
+                    "type": "string"
+                },
+                "realms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.RealmFreshness"
+                    }
+                }
+            }
+        },
+        "v3.Init": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.Item"
+                    }
+                },
+                "stages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.Stage"
+                    }
+                },
+                "zones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.Zone"
+                    }
+                }
+            }
+        },
+        "v3.Item": {
+            "type": "object",
+            "properties": {
+                "arkItemId": {
+                    "type": "string"
+                },
+                "existence": {
+                    "type": "object"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "keywords": {
+                    "type": "object"
+                },
+                "name": {
+                    "type": "object"
+                },
+                "pgItemId": {
+                    "type": "integer"
+                },
+                "rarity": {
+                    "type": "integer"
+                },
+                "sortId": {
+                    "type": "integer"
+                },
+                "sprite": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "v3.ItemDropInfo": {
+            "type": "object",
+            "properties": {
+                "accumulable": {
+                    "type": "boolean"
+                },
+                "bounds": {
+                    "$ref": "#/definitions/model.Bounds"
+                },
+                "dropType": {
+                    "type": "string"
+                },
+                "itemId": {
+                    "type": "string"
+                }
+            }
+        },
+        "v3.ItemIcon": {
+            "type": "object",
+            "properties": {
+                "arkItemId": {
+                    "type": "string",
+                    "example": "30013"
+                },
+                "iconUrl": {
+                    "type": "string"
+                },
+                "sprite": {
+                    "description": "Sprite is the location of the item's sprite on the sprite image, in a form of Y:X",
+                    "type": "string",
+                    "example": "3:5"
+                }
+            }
+        },
+        "v3.ItemIcons": {
+            "type": "object",
+            "properties": {
+                "icons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.ItemIcon"
+                    }
+                },
+                "version": {
+                    "description": "Version is a hash of the icons, which changes whenever any of them does so that clients may cache-bust with it",
+                    "type": "string",
+                    "example": "5f2b8c1d9e3a7b46"
+                }
+            }
+        },
+        "v3.ItemValueTable": {
+            "type": "object",
+            "properties": {
+                "preset": {
+                    "type": "string",
+                    "example": "market"
+                },
+                "server": {
+                    "type": "string",
+                    "example": "CN"
+                },
+                "updatedAt": {
+                    "description": "UpdatedAt is when the values of the preset were last replaced",
+                    "type": "string"
+                },
+                "values": {
+                    "description": "Values maps the ark item IDs to their sanity values",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "v3.LeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Name is the name chosen by the contributor, or null for the anonymous ones",
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "reports": {
+                    "type": "integer"
+                },
+                "sanity": {
+                    "description": "Sanity is estimated from the sanity cost of the stages times the times they are reported to be cleared",
+                    "type": "integer"
+                },
+                "times": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v3.MetaBundle": {
+            "type": "object",
+            "properties": {
+                "full": {
+                    "description": "Full tells whether the bundle contains everything, rather than the changes since the version of the client",
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.Item"
+                    }
+                },
+                "removed": {
+                    "$ref": "#/definitions/v3.MetaBundleRemoved"
+                },
+                "stages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.Stage"
+                    }
+                },
+                "version": {
+                    "description": "Version increases monotonically whenever any item, stage or zone changes",
+                    "type": "integer",
+                    "example": 42
+                },
+                "zones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.Zone"
+                    }
+                }
+            }
+        },
+        "v3.MetaBundleRemoved": {
+            "type": "object",
+            "properties": {
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v2.DropInfo"
+                        "type": "string"
                     }
                 },
-                "existence": {
-                    "type": "object"
-                },
-                "isGacha": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "minClearTime": {
-                    "type": "integer",
-                    "example": 118000
+                "stages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "recognitionOnly": {
+                "zones": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
+                }
+            }
+        },
+        "v3.Notice": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "Content maps the languages to the content of the notice in them",
+                    "type": "object"
+                },
+                "endTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "maintenance"
+                },
+                "severity": {
+                    "type": "integer"
+                },
+                "startTime": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "v3.OneAdvancedQueryResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/pgerr.Problem"
+                },
+                "result": {
+                    "description": "Result is a v2 drop matrix, trend or pattern matrix result by Type",
+                    "type": "object"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "trend"
+                }
+            }
+        },
+        "v3.OneDrop": {
+            "type": "object",
+            "properties": {
+                "itemId": {
+                    "type": "string",
+                    "example": "30012"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "v3.OnePatternMatrixElement": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "integer",
+                    "x-nullable": true
+                },
+                "pattern": {
+                    "$ref": "#/definitions/v3.Pattern"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 159486
+                },
+                "rate": {
+                    "description": "Rate is quantity / times rounded to 6 decimal places, or 0 when times is 0",
+                    "type": "number",
+                    "example": 0.248523
                 },
                 "stageId": {
                     "type": "string",
                     "example": "main_01-07"
                 },
-                "stageType": {
-                    "description": "StageType is the type of the stage\n* MAIN - Mainline Stages\n* SUB - Sub Stages\n* ACTIVITY - Activity Stages\n* DAILY - Daily Stages",
-                    "type": "string",
-                    "enum": [
-                        "MAIN",
-                        "SUB",
-                        "ACTIVITY",
-                        "DAILY"
-                    ],
-                    "example": "MAIN"
+                "start": {
+                    "type": "integer",
+                    "example": 1633032000000
                 },
-                "zoneId": {
+                "times": {
+                    "type": "integer",
+                    "example": 641734
+                }
+            }
+        },
+        "v3.OneStageDropStats": {
+            "type": "object",
+            "properties": {
+                "avg": {
+                    "description": "Avg is the average number of items dropped per run, counting every item of the drop patterns",
+                    "type": "number",
+                    "example": 1.812504
+                },
+                "end": {
+                    "type": "integer",
+                    "x-nullable": true
+                },
+                "stageId": {
                     "type": "string",
-                    "example": "main_1"
+                    "example": "main_01-07"
+                },
+                "start": {
+                    "type": "integer",
+                    "example": 1633032000000
+                },
+                "stdDev": {
+                    "description": "StdDev is the standard deviation of the number of items dropped per run",
+                    "type": "number",
+                    "example": 0.543217
+                },
+                "times": {
+                    "type": "integer",
+                    "example": 641734
                 }
             }
         },
-        "exusiai_dev_backend-next_internal_model_v2.Zone": {
+        "v3.Pattern": {
             "type": "object",
             "properties": {
-                "background": {
-                    "type": "string"
+                "drops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.OneDrop"
+                    }
                 },
-                "existence": {
-                    "type": "object"
+                "patternId": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "v3.Plan": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.PlannedItem"
+                    }
+                },
+                "sanity": {
+                    "description": "Sanity is the sanity spent on the runs of the stages",
+                    "type": "integer"
+                },
+                "server": {
+                    "type": "string"
                 },
                 "stages": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/v3.PlannedStage"
                     }
                 },
-                "subType": {
-                    "type": "string"
+                "unreachable": {
+                    "description": "Unreachable are the items requested that no open stage has been reported enough to drop",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "v3.PlannedItem": {
+            "type": "object",
+            "properties": {
+                "expected": {
+                    "description": "Expected is the quantity expected to drop from the planned runs, byproducts of other items included",
+                    "type": "number"
                 },
-                "type": {
+                "itemId": {
                     "type": "string"
                 },
-                "zoneId": {
-                    "type": "string"
+                "quantity": {
+                    "description": "Quantity is the quantity requested",
+                    "type": "integer"
+                }
+            }
+        },
+        "v3.PlannedStage": {
+            "type": "object",
+            "properties": {
+                "runs": {
+                    "description": "Runs is the number of times the stage is to be cleared, rounded up from the solution",
+                    "type": "integer"
                 },
-                "zoneIndex": {
+                "sanity": {
                     "type": "integer"
                 },
-                "zoneName": {
+                "stageId": {
                     "type": "string"
-                },
-                "zoneName_i18n": {
-                    "type": "object"
                 }
             }
         },
-        "model.Existence": {
+        "v3.RealmFreshness": {
             "type": "object",
-            "required": [
-                "CN",
-                "JP",
-                "KR",
-                "US"
-            ],
             "properties": {
-                "CN": {
-                    "description": "CN: 国服 Mainland China Server (maintained by Hypergryph Network Technology Co., Ltd.)",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.ServerExistence"
-                        }
-                    ]
+                "lastAcceptedReportAt": {
+                    "description": "LastAcceptedReportAt is when the last report accepted in the server has been submitted, null when none has been\nsince the freshness has started being recorded",
+                    "type": "string"
                 },
-                "JP": {
-                    "description": "JP: 日服 Japan Server (maintained by Yostar Inc,.)",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.ServerExistence"
-                        }
-                    ]
+                "lastRefreshedAt": {
+                    "description": "LastRefreshedAt is when the aggregation has last been refreshed, null when it has not been since the freshness\nhas started being recorded",
+                    "type": "string"
                 },
-                "KR": {
-                    "description": "KR: 韩服 Korea Server (maintained by Yostar Limited)",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.ServerExistence"
-                        }
-                    ]
+                "realm": {
+                    "description": "Realm is one of matrix, trend and pattern",
+                    "type": "string",
+                    "example": "matrix"
                 },
-                "US": {
-                    "description": "US: 美服/国际服 Global Server (maintained by Yostar Limited)",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.ServerExistence"
-                        }
-                    ]
+                "server": {
+                    "type": "string",
+                    "example": "CN"
                 }
             }
         },
-        "model.I18nString": {
+        "v3.RecognitionDefectReceipt": {
             "type": "object",
-            "required": [
-                "en",
-                "ja",
-                "ko",
-                "zh"
-            ],
             "properties": {
-                "en": {
-                    "description": "EN: English (en)",
-                    "type": "string"
+                "defectId": {
+                    "type": "string",
+                    "example": "01hcz4g2q4d4b9s7m6yv3e8k5x"
+                }
+            }
+        },
+        "v3.RecognitionStageHash": {
+            "type": "object",
+            "properties": {
+                "hash": {
+                    "type": "string",
+                    "example": "f0e1d2c3b4a59687"
                 },
-                "ja": {
-                    "description": "JP: 日本語 (ja)",
-                    "type": "string"
+                "stageId": {
+                    "type": "string",
+                    "example": "main_01-07"
+                }
+            }
+        },
+        "v3.RecognitionStageHashes": {
+            "type": "object",
+            "properties": {
+                "hashes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.RecognitionStageHash"
+                    }
                 },
-                "ko": {
-                    "description": "KR: 한국어 (ko)",
-                    "type": "string"
+                "server": {
+                    "type": "string",
+                    "example": "CN"
                 },
-                "zh": {
-                    "description": "ZH: 中文 (zh-CN)",
-                    "type": "string"
+                "version": {
+                    "description": "Version is a hash of the registry, which changes whenever any of the hashes does",
+                    "type": "string",
+                    "example": "5f2b8c1d9e3a7b46"
                 }
             }
         },
-        "model.Notice": {
+        "v3.ReportHeatmap": {
             "type": "object",
             "properties": {
-                "content_i18n": {
+                "days": {
+                    "type": "integer"
+                },
+                "generatedAt": {
+                    "type": "string"
+                },
+                "matrix": {
+                    "description": "Matrix is indexed by the weekday, starting from Sunday, then by the hour",
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        }
                     }
                 },
-                "existence": {
-                    "type": "object"
+                "server": {
+                    "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Shanghai"
                 },
-                "severity": {
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "model.ServerExistence": {
+        "v3.ReportIssue": {
             "type": "object",
-            "required": [
-                "exist"
-            ],
             "properties": {
-                "closeTime": {
-                    "type": "integer",
-                    "example": 1635966000000
+                "code": {
+                    "type": "string",
+                    "example": "ITEM_RARELY_DROPS"
                 },
-                "exist": {
-                    "type": "boolean",
-                    "example": true
+                "field": {
+                    "description": "Field is the path to the offending field of the request, if any",
+                    "type": "string",
+                    "example": "drops[0].itemId"
                 },
-                "openTime": {
-                    "type": "integer",
-                    "example": 1634799600000
+                "message": {
+                    "type": "string",
+                    "example": "item 30013 has rarely been reported to drop from main_01-07"
                 }
             }
         },
-        "pgerr.PenguinError": {
+        "v3.ReportRejection": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "string",
-                    "example": "INVALID_REQUEST"
+                    "example": "INVALID_REPORT"
+                },
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.ReportIssue"
+                    }
                 },
                 "message": {
                     "type": "string",
-                    "example": "invalid request: some or all request parameters are invalid"
+                    "example": "report has been rejected"
                 }
             }
         },
-        "types.AdvancedQuery": {
+        "v3.ReportResponse": {
             "type": "object",
-            "required": [
-                "server",
-                "stageId"
-            ],
             "properties": {
-                "end": {
-                    "type": "integer"
-                },
-                "interval": {
-                    "type": "integer"
-                },
-                "isPersonal": {
-                    "type": "boolean"
+                "reportHash": {
+                    "type": "string",
+                    "example": "0522ce0083000000-1wE2I9dvMFXXzBMpSCYM81rJ0T3tLrAQ"
                 },
-                "itemIds": {
+                "warnings": {
+                    "description": "Warnings are the issues found in the report that did not cause it to be rejected",
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/v3.ReportIssue"
                     }
+                }
+            }
+        },
+        "v3.SampleForecast": {
+            "type": "object",
+            "properties": {
+                "estimatedAt": {
+                    "description": "EstimatedAt is when the sample is estimated to reach the target, or null when it has been reached already, no\nreports have come in over the window, or the time range is estimated to end before then",
+                    "type": "string"
                 },
-                "server": {
+                "generatedAt": {
                     "type": "string"
                 },
-                "sourceCategory": {
+                "ratePerHour": {
+                    "description": "RatePerHour is the number of times reported per hour over the window",
+                    "type": "number"
+                },
+                "reached": {
+                    "description": "Reached is whether the sample has reached the target already",
+                    "type": "boolean"
+                },
+                "server": {
                     "type": "string"
                 },
                 "stageId": {
                     "type": "string"
                 },
-                "start": {
+                "target": {
+                    "type": "integer"
+                },
+                "timeRange": {
+                    "$ref": "#/definitions/model.TimeRange"
+                },
+                "times": {
+                    "description": "Times is the number of times the stage has been reported to be cleared within the time range so far",
                     "type": "integer"
+                },
+                "windowHours": {
+                    "type": "number"
                 }
             }
         },
-        "types.AdvancedQueryRequest": {
+        "v3.ServerSiteStats": {
             "type": "object",
-            "required": [
-                "queries"
-            ],
             "properties": {
-                "queries": {
-                    "type": "array",
-                    "maxItems": 5,
-                    "minItems": 1,
-                    "items": {
-                        "$ref": "#/definitions/types.AdvancedQuery"
-                    }
+                "reports24h": {
+                    "type": "integer"
+                },
+                "totalReports": {
+                    "type": "integer"
+                },
+                "uniqueReporters": {
+                    "type": "integer"
                 }
             }
         },
-        "types.ArkDrop": {
+        "v3.ShortLink": {
             "type": "object",
-            "required": [
-                "dropType",
-                "itemId",
-                "quantity"
-            ],
             "properties": {
-                "dropType": {
+                "code": {
                     "type": "string",
-                    "enum": [
-                        "REGULAR_DROP",
-                        "NORMAL_DROP",
-                        "SPECIAL_DROP",
-                        "EXTRA_DROP",
-                        "FURNITURE"
-                    ]
+                    "example": "x7Kq2LmP"
                 },
-                "itemId": {
-                    "type": "string",
-                    "example": "30013"
+                "createdAt": {
+                    "type": "string"
                 },
-                "quantity": {
-                    "type": "integer",
-                    "maximum": 1000,
-                    "minimum": 0
+                "expiresAt": {
+                    "type": "string"
+                },
+                "query": {
+                    "description": "Query is the normalized advanced query, to be sent as is to the advanced query API",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.AdvancedQueryRequest"
+                        }
+                    ]
                 }
             }
         },
-        "types.ReportRequestMetadata": {
+        "v3.SimulatedDrop": {
             "type": "object",
             "properties": {
-                "fileName": {
-                    "type": "string",
-                    "maxLength": 512
-                },
-                "fingerprint": {
-                    "type": "string",
-                    "maxLength": 128
+                "itemId": {
+                    "type": "string"
                 },
-                "lastModified": {
+                "quantity": {
                     "type": "integer"
+                }
+            }
+        },
+        "v3.SimulatedDrops": {
+            "type": "object",
+            "properties": {
+                "drops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.SimulatedDrop"
+                    }
                 },
-                "md5": {
-                    "type": "string",
-                    "maxLength": 32
-                },
-                "recognizerAssetsVersion": {
-                    "type": "string",
-                    "maxLength": 32
+                "expected": {
+                    "description": "Expected is the expected number of runs of the pattern over the runs",
+                    "type": "number"
                 },
-                "recognizerVersion": {
-                    "type": "string",
-                    "maxLength": 32
+                "probability": {
+                    "description": "Probability is the probability of the pattern in a single run",
+                    "type": "number"
                 }
             }
         },
-        "types.SingularReportRecallRequest": {
+        "v3.SimulatedItem": {
             "type": "object",
-            "required": [
-                "reportHash"
-            ],
             "properties": {
-                "reportHash": {
-                    "type": "string",
-                    "example": "cahbuch1eqliv7dopen0-5ejlUrfzNMXNHY6Q"
+                "atLeastOnce": {
+                    "description": "AtLeastOnce is the probability of the item dropping at least once over the runs, which is only known for the\nitems of the drop patterns",
+                    "type": "number"
+                },
+                "expected": {
+                    "description": "Expected is the expected total quantity of the item dropped over the runs",
+                    "type": "number"
+                },
+                "itemId": {
+                    "type": "string"
+                },
+                "stdDev": {
+                    "type": "number"
+                },
+                "variance": {
+                    "description": "Variance is the variance of the total quantity over the runs, assuming the runs are independent",
+                    "type": "number"
                 }
             }
         },
-        "types.SingularReportRequest": {
+        "v3.Simulation": {
             "type": "object",
-            "required": [
-                "server",
-                "source",
-                "stageId",
-                "version"
-            ],
             "properties": {
-                "drops": {
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/types.ArkDrop"
+                        "$ref": "#/definitions/v3.SimulatedItem"
                     }
                 },
-                "metadata": {
-                    "$ref": "#/definitions/types.ReportRequestMetadata"
+                "patterns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.SimulatedDrops"
+                    }
                 },
-                "server": {
-                    "type": "string",
-                    "example": "CN"
+                "runs": {
+                    "type": "integer"
                 },
-                "source": {
-                    "description": "Source describes a source of the report. Third-party API consumers should change this to their own name.",
-                    "type": "string",
-                    "maxLength": 128,
-                    "example": "your-app-name"
+                "sampleTimes": {
+                    "description": "SampleTimes is the number of times the stage has been reported to be cleared, which the rates are based on",
+                    "type": "integer"
+                },
+                "server": {
+                    "type": "string"
                 },
                 "stageId": {
-                    "type": "string",
-                    "example": "main_01-07"
+                    "type": "string"
+                }
+            }
+        },
+        "v3.SiteStats": {
+            "type": "object",
+            "properties": {
+                "reports24h": {
+                    "type": "integer"
                 },
-                "times": {
-                    "type": "integer",
-                    "maximum": 6,
-                    "minimum": 0
+                "servers": {
+                    "description": "Servers is the per-server breakdown, keyed by server code",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/v3.ServerSiteStats"
+                    }
                 },
-                "version": {
-                    "description": "Version describes the version of the source app used to submit this report. Third-party API consumers should change this to their own app version.",
-                    "type": "string",
-                    "maxLength": 128,
-                    "example": "v0.0.0+0000000"
+                "totalAccounts": {
+                    "type": "integer"
+                },
+                "totalReports": {
+                    "type": "integer"
+                },
+                "uniqueReporters": {
+                    "type": "integer"
                 }
             }
         },
-        "v2.Activity": {
+        "v3.Stage": {
             "type": "object",
             "properties": {
-                "end": {
-                    "type": "integer"
+                "arkStageId": {
+                    "type": "string"
                 },
-                "existence": {
+                "code": {
                     "type": "object"
                 },
-                "label_i18n": {
+                "existence": {
                     "type": "object"
                 },
-                "start": {
+                "extraProcessType": {
+                    "type": "string"
+                },
+                "minClearTime": {
+                    "type": "integer"
+                },
+                "pgStageId": {
+                    "type": "integer"
+                },
+                "sanity": {
+                    "type": "integer"
+                },
+                "stageType": {
+                    "type": "string"
+                },
+                "zoneId": {
                     "type": "integer"
                 }
             }
         },
-        "v2.AdvancedQueryResult": {
+        "v3.StageDropInfos": {
             "type": "object",
             "properties": {
-                "advanced_results": {
+                "server": {
+                    "type": "string"
+                },
+                "stageId": {
+                    "type": "string"
+                },
+                "timeRanges": {
                     "type": "array",
-                    "items": {}
+                    "items": {
+                        "$ref": "#/definitions/v3.StageDropInfosInTimeRange"
+                    }
                 }
             }
         },
-        "v2.DropInfo": {
+        "v3.StageDropInfosInTimeRange": {
             "type": "object",
             "properties": {
-                "bounds": {
-                    "type": "object"
+                "active": {
+                    "description": "Active reports whether the time range is in effect at the time of the request",
+                    "type": "boolean"
                 },
-                "dropType": {
-                    "type": "string"
+                "dropSet": {
+                    "description": "DropSet is the item IDs that may drop from the stage within the time range",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "itemId": {
-                    "type": "string"
+                "dropTypes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.DropTypeDropInfo"
+                    }
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v3.ItemDropInfo"
+                    }
+                },
+                "timeRange": {
+                    "$ref": "#/definitions/model.TimeRange"
                 }
             }
         },
-        "v2.DropMatrixQueryResult": {
+        "v3.StageDropStats": {
             "type": "object",
             "properties": {
-                "matrix": {
+                "stages": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v2.OneDropMatrixElement"
+                        "$ref": "#/definitions/v3.OneStageDropStats"
                     }
                 }
             }
         },
-        "v2.LoginResponse": {
+        "v3.StageListing": {
             "type": "object",
             "properties": {
-                "userID": {
+                "code": {
+                    "description": "Code is a map with language code as key and the code of the stage in that language as value.",
+                    "type": "object"
+                },
+                "deletedAt": {
+                    "description": "DeletedAt is when the stage has been deleted. Deleted stages are left out of every query unless asked for,\nand may be restored.",
+                    "type": "string"
+                },
+                "existence": {
+                    "description": "Existence is a map with server code as key and the existence of the item in that server as value.",
+                    "type": "object"
+                },
+                "extraProcessType": {
+                    "description": "ExtraProcessType is the type of extra process that is used in the stage, e.g. \"GACHABOX\".",
+                    "type": "string"
+                },
+                "game": {
+                    "description": "Game is the game the stage is from, which defaults to arknights.",
+                    "type": "string"
+                },
+                "minClearTime": {
+                    "description": "MinClearTime is the minimum time (in milliseconds as a duration) it takes to clear the stage, referencing from prts.wiki",
+                    "type": "integer"
+                },
+                "open": {
+                    "description": "Open reports whether the stage is open in the server at the time of the request",
+                    "type": "boolean"
+                },
+                "penguinStageId": {
+                    "description": "StageID (penguinStageId) is the numerical ID of the stage.",
+                    "type": "integer"
+                },
+                "sanity": {
+                    "description": "Sanity is the sanity requirement for a full clear of the stage.",
+                    "type": "integer"
+                },
+                "stageId": {
+                    "description": "ArkStageID (stageId) is the previously used, string form ID of the stage; in JSON-representation ` + "`" + `stageId` + "`" + ` is used as key.",
+                    "type": "string"
+                },
+                "stageType": {
+                    "description": "StageType is the type of the stage, e.g. \"MAIN\", \"SUB\", \"ACTIVITY\" and \"DAILY\".",
                     "type": "string"
+                },
+                "timeRange": {
+                    "description": "TimeRange is the time range of the drop infos of the stage currently in effect in the server, if any",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.TimeRange"
+                        }
+                    ]
+                },
+                "zoneId": {
+                    "description": "ZoneID is the numerical ID of the zone the stage is in.",
+                    "type": "integer"
                 }
             }
         },
-        "v2.OneDrop": {
+        "v3.SubmissionChallenge": {
             "type": "object",
             "properties": {
-                "itemId": {
+                "algorithm": {
                     "type": "string",
-                    "example": "30012"
+                    "example": "sha256"
                 },
-                "quantity": {
+                "difficulty": {
                     "type": "integer",
-                    "example": 1
+                    "example": 16
+                },
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2026-10-17T08:05:00Z"
+                },
+                "nonce": {
+                    "type": "string",
+                    "example": "9b1c3f0d7e2a4c6b8d0f1e3a5c7b9d2e"
                 }
             }
         },
-        "v2.OneDropMatrixElement": {
+        "v3.SubmitAppealRequest": {
             "type": "object",
+            "required": [
+                "reason"
+            ],
             "properties": {
-                "end": {
-                    "type": "integer"
-                },
-                "itemId": {
+                "contact": {
                     "type": "string",
-                    "example": "30012"
-                },
-                "quantity": {
-                    "type": "integer",
-                    "example": 1322056
+                    "maxLength": 256
                 },
-                "stageId": {
+                "reason": {
                     "type": "string",
-                    "example": "main_01-07"
-                },
-                "start": {
-                    "type": "integer",
-                    "example": 1556676000000
-                },
-                "stdDev": {
-                    "type": "number",
-                    "example": 0.114514
-                },
-                "times": {
-                    "type": "integer",
-                    "example": 1061347
+                    "maxLength": 2000
                 }
             }
         },
-        "v2.OneItemTrend": {
+        "v3.TimesDistribution": {
             "type": "object",
             "properties": {
-                "quantity": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "accounts": {
+                    "description": "Accounts is the number of distinct accounts having reported the stage",
+                    "type": "integer",
+                    "example": 12345
                 },
-                "times": {
+                "generatedAt": {
+                    "type": "string"
+                },
+                "histogram": {
+                    "description": "Histogram buckets the accounts by their number of reports, in powers of two, i.e. 1, 2-3, 4-7 and so on.\nEmpty buckets are left out.",
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/v3.TimesDistributionBucket"
                     }
+                },
+                "reports": {
+                    "type": "integer",
+                    "example": 456789
+                },
+                "server": {
+                    "type": "string",
+                    "example": "CN"
+                },
+                "stageId": {
+                    "type": "string",
+                    "example": "main_01-07"
+                },
+                "topAccountShare": {
+                    "description": "TopAccountShare is the share of the reports of the account having reported the stage the most",
+                    "type": "number",
+                    "example": 0.012345
                 }
             }
         },
-        "v2.OnePatternMatrixElement": {
+        "v3.TimesDistributionBucket": {
             "type": "object",
             "properties": {
-                "end": {
+                "accounts": {
                     "type": "integer",
-                    "x-nullable": true
-                },
-                "pattern": {
-                    "$ref": "#/definitions/v2.Pattern"
+                    "example": 1234
                 },
-                "quantity": {
+                "max": {
                     "type": "integer",
-                    "example": 159486
-                },
-                "stageId": {
-                    "type": "string",
-                    "example": "main_01-07"
+                    "example": 7
                 },
-                "start": {
+                "min": {
+                    "description": "Min and Max are the bounds of the number of reports per account of the bucket, both inclusive",
                     "type": "integer",
-                    "example": 1633032000000
+                    "example": 4
                 },
-                "times": {
+                "reports": {
                     "type": "integer",
-                    "example": 641734
+                    "example": 5678
                 }
             }
         },
-        "v2.Pattern": {
+        "v3.UIConfig": {
             "type": "object",
             "properties": {
-                "drops": {
+                "config": {
+                    "description": "Config is the frontend config property, passed through as is",
+                    "type": "object"
+                },
+                "generatedAt": {
+                    "type": "string"
+                },
+                "highlightedEvents": {
+                    "description": "HighlightedEvents are the events active or upcoming in the server",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v2.OneDrop"
+                        "$ref": "#/definitions/v3.EventListing"
                     }
-                }
-            }
-        },
-        "v2.PatternMatrixQueryResult": {
-            "type": "object",
-            "properties": {
-                "pattern_matrix": {
+                },
+                "open": {
+                    "description": "Open is false while a maintenance notice is active in the server",
+                    "type": "boolean"
+                },
+                "server": {
+                    "type": "string",
+                    "example": "CN"
+                },
+                "version": {
+                    "description": "Version is a hash of the config, which changes whenever anything in it does",
+                    "type": "string",
+                    "example": "5f2b8c1d9e3a7b46"
+                },
+                "zones": {
+                    "description": "Zones are the zones existing in the server, along with the stages of them to show",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v2.OnePatternMatrixElement"
+                        "$ref": "#/definitions/v3.UIConfigZone"
                     }
                 }
             }
         },
-        "v2.RecognitionReportResponse": {
+        "v3.UIConfigZone": {
             "type": "object",
             "properties": {
-                "errors": {
+                "category": {
+                    "type": "string",
+                    "example": "MAINLINE"
+                },
+                "closeTime": {
+                    "type": "string"
+                },
+                "open": {
+                    "description": "Open reports whether the zone is open in the server at the time the config is generated",
+                    "type": "boolean"
+                },
+                "openTime": {
+                    "type": "string"
+                },
+                "stages": {
+                    "description": "Stages are the ark stage ids of the stages of the zone existing in the server",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "taskId": {
+                "zoneId": {
                     "type": "string",
-                    "example": "0522ce0083000000-1wE2I9dvMFXXzBMpSCYM81rJ0T3tLrAQ"
+                    "example": "main_1"
                 }
             }
         },
-        "v2.ReportResponse": {
+        "v3.UpdateLeaderboardSettingsRequest": {
             "type": "object",
             "properties": {
-                "reportHash": {
+                "name": {
+                    "description": "Name is listed instead of an anonymous name; the PenguinID is never listed",
                     "type": "string",
-                    "example": "0522ce0083000000-1wE2I9dvMFXXzBMpSCYM81rJ0T3tLrAQ"
+                    "maxLength": 32
+                },
+                "optIn": {
+                    "type": "boolean"
                 }
             }
         },
-        "v2.StageTrend": {
+        "v3.Zone": {
             "type": "object",
             "properties": {
-                "results": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/v2.OneItemTrend"
-                    }
+                "arkZoneId": {
+                    "type": "string"
                 },
-                "startTime": {
+                "background": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string",
+                    "example": "MAINLINE"
+                },
+                "existence": {
+                    "type": "object"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "object"
+                },
+                "pgZoneId": {
                     "type": "integer"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "AWAKENING_HOUR"
                 }
             }
         },
-        "v2.TotalItemQuantity": {
+        "v3.ZoneListing": {
             "type": "object",
             "properties": {
-                "itemId": {
+                "background": {
+                    "description": "Background is the path of the background image of the zone, relative to the CDN endpoint.",
                     "type": "string"
                 },
-                "quantity": {
+                "category": {
+                    "description": "Category of the zone.",
+                    "type": "string",
+                    "example": "MAINLINE"
+                },
+                "closeTime": {
+                    "type": "string"
+                },
+                "existence": {
+                    "description": "Existence is a map with server code as key and the existence of the item in that server as value.",
+                    "type": "object"
+                },
+                "game": {
+                    "description": "Game is the game the zone is from, which defaults to arknights.",
+                    "type": "string"
+                },
+                "index": {
                     "type": "integer"
-                }
-            }
-        },
-        "v2.TotalStageTime": {
-            "type": "object",
-            "properties": {
-                "stageId": {
+                },
+                "name": {
+                    "description": "Name is a map with language code as key and the name of the item in that language as value.",
+                    "type": "object"
+                },
+                "open": {
+                    "description": "Open reports whether the zone is open in the server at the time of the request",
+                    "type": "boolean"
+                },
+                "openTime": {
+                    "description": "OpenTime and CloseTime are the boundaries of the zone in the server, if any",
                     "type": "string"
                 },
-                "times": {
+                "penguinZoneId": {
+                    "description": "ZoneID is the numerical ID of the zone.",
                     "type": "integer"
-                }
-            }
-        },
-        "v2.TrendQueryResult": {
-            "type": "object",
-            "properties": {
-                "trend": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/v2.StageTrend"
-                    }
+                },
+                "type": {
+                    "description": "Type of the zone, e.g. \"AWAKENING_HOUR\" or \"VISION_SHATTER\". Optional and only occurs when ` + "`" + `category` + "`" + ` is \"MAINLINE\".",
+                    "type": "string",
+                    "example": "AWAKENING_HOUR"
+                },
+                "zoneId": {
+                    "type": "string"
                 }
             }
         }
     },
     "securityDefinitions": {
+        "APIKeyAuth": {
+            "type": "apiKey",
+            "name": "X-Penguin-API-Key",
+            "in": "header"
+        },
         "PenguinIDAuth": {
             "type": "apiKey",
             "name": "Authorization",
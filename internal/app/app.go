@@ -17,7 +17,11 @@ import (
 	"exusiai.dev/backend-next/internal/service"
 	"exusiai.dev/backend-next/internal/util/reportverifs"
 	"exusiai.dev/backend-next/internal/workers/calcwkr"
+	"exusiai.dev/backend-next/internal/workers/jobwkr"
 	"exusiai.dev/backend-next/internal/workers/reportwkr"
+	"exusiai.dev/backend-next/internal/workers/schedwkr"
+	"exusiai.dev/backend-next/internal/workers/warmwkr"
+	"exusiai.dev/backend-next/internal/workers/webhookwkr"
 )
 
 func Options(ctx appcontext.Ctx, additionalOpts ...fx.Option) []fx.Option {
@@ -58,13 +62,18 @@ func Options(ctx appcontext.Ctx, additionalOpts ...fx.Option) []fx.Option {
 		// are called in the order of their registration.
 		fx.Invoke(infra.SentryInit),
 		fx.Invoke(cache.Initialize),
+		fx.Invoke(service.PersistLastModifiedTime),
 
 		// Controllers
 		controller.Module(controller.OptIncludeSwagger),
 
 		// Workers
 		fx.Invoke(calcwkr.Start),
+		fx.Invoke(jobwkr.Start),
 		fx.Invoke(reportwkr.Start),
+		fx.Invoke(schedwkr.Start),
+		fx.Invoke(webhookwkr.Start),
+		fx.Invoke(warmwkr.Start),
 
 		// fx Extra Options
 		fx.StartTimeout(1 * time.Second),
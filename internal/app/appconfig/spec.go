@@ -15,6 +15,11 @@ type ConfigSpec struct {
 	// This address is only intended to be used in intra-cluster devops requests, and is not intended to be exposed to the public.
 	DevOpsAddress string `split_words:"true"`
 
+	// GRPCAddress is the listen address would listen on for serving gRPC requests from internal consumers,
+	// such as the recognition backend. Leaving this empty will disable the gRPC server.
+	// Like DevOpsAddress, this address is not intended to be exposed to the public.
+	GRPCAddress string `split_words:"true"`
+
 	// LogJsonStdout is whether to log JSON logs (instead of pretty-print logs) to stdout for the ease of log collection.
 	LogJsonStdout bool `split_words:"true" default:"false"`
 
@@ -30,9 +35,16 @@ type ConfigSpec struct {
 	TracingEnabled bool `split_words:"true"`
 
 	// TracingExporters to indicate which exporters to use for tracing.
-	// Valid values are: jaeger, otlp, stdout (for debug).
+	// Valid values are: jaeger, otlpgrpc, stdout (for debug).
 	TracingExporters []string `split_words:"true" default:"jaeger"`
 
+	// TracingOTLPEndpoint is the host:port of the collector the otlpgrpc exporter exports to. When left empty,
+	// the OTEL_EXPORTER_OTLP_ENDPOINT environment variable or the default localhost:4317 is used.
+	TracingOTLPEndpoint string `split_words:"true"`
+
+	// TracingOTLPInsecure disables TLS for the otlpgrpc exporter, e.g. for a collector running as a sidecar.
+	TracingOTLPInsecure bool `split_words:"true" default:"false"`
+
 	// TracingSampleRate to indicate the sampling rate for tracing.
 	// Valid values are: 0.0 (disabled), 1.0 (all traces), or a value between 0.0 and 1.0 (sampling rate).
 	TracingSampleRate float64 `split_words:"true" default:"1.0"`
@@ -48,8 +60,21 @@ type ConfigSpec struct {
 	PostgresConnMaxLifeTime time.Duration `split_words:"true" default:"5m"`
 	PostgresConnMaxIdleTime time.Duration `split_words:"true" default:"5m"`
 
+	// PostgresBackgroundMaxOpenConns is the size of the separate pool of connections of the background jobs, such as
+	// the refreshes of the views, the archives and the calculations of the worker, for them not to exhaust the pool
+	// serving the requests. 0 runs the background jobs on the pool of the requests.
+	PostgresBackgroundMaxOpenConns int `split_words:"true" default:"4"`
+	PostgresBackgroundMaxIdleConns int `split_words:"true" default:"1"`
+
 	BunDebugVerbose bool `split_words:"true"`
 
+	// PostgresSlowQueryThreshold is the duration above which a query is logged with its bound parameters. 0 disables
+	// the logging of slow queries.
+	PostgresSlowQueryThreshold time.Duration `split_words:"true" default:"0"`
+
+	// PostgresSlowQueryExplain captures the plan of the slow SELECT queries with EXPLAIN, logged along with them.
+	PostgresSlowQueryExplain bool `split_words:"true"`
+
 	// NatsURL is the URL of the NATS server. See https://pkg.go.dev/github.com/nats-io/nats.go#Connect
 	// for more information on how to construct a NATS URL.
 	NatsURL string `required:"true" split_words:"true" default:"nats://127.0.0.1:4222"`
@@ -97,6 +122,82 @@ type ConfigSpec struct {
 	// HTTPServerShutdownTimeout is the timeout for the HTTP server to shut down gracefully.
 	HTTPServerShutdownTimeout time.Duration `required:"true" split_words:"true" default:"60s"`
 
+	// ShutdownDrainDelay is the time in-between the instance reporting itself not ready upon shutdown and it closing
+	// its listeners, which shall be long enough for the load balancers to stop routing traffic to it.
+	ShutdownDrainDelay time.Duration `split_words:"true" default:"5s"`
+
+	// HTTPQueryTimeout is the time limit of the API requests, beyond which their database queries are cancelled and
+	// the requests fail. Zero disables the limit.
+	HTTPQueryTimeout time.Duration `split_words:"true" default:"30s"`
+
+	// HTTPAdminQueryTimeout is HTTPQueryTimeout for the admin API, which may run the refreshes of the results.
+	HTTPAdminQueryTimeout time.Duration `split_words:"true" default:"10m"`
+
+	// HTTPWriteQueryTimeout is HTTPQueryTimeout for the requests other than GET and HEAD, e.g. the reports, which
+	// touch a few rows each and shall not be held for as long as the reads of the results may be.
+	HTTPWriteQueryTimeout time.Duration `split_words:"true" default:"10s"`
+
+	// HTTPDegradedQueryTimeout is the time limit of the API requests while the circuit around the database is open.
+	// The requests served from the caches complete well within it, while those left waiting on the database give up
+	// early instead of piling up.
+	HTTPDegradedQueryTimeout time.Duration `split_words:"true" default:"2s"`
+
+	// DBCircuitSlowThreshold is the latency beyond which a query of the requests counts as slow for the circuit
+	// around the database. 0 disables the circuit.
+	DBCircuitSlowThreshold time.Duration `split_words:"true" default:"0"`
+
+	// DBCircuitSlowRatio is the share of the slow queries within DBCircuitWindow beyond which the circuit opens.
+	DBCircuitSlowRatio float64 `split_words:"true" default:"0.5"`
+
+	// DBCircuitMinQueries is the number of the queries a window shall have seen for the circuit to open.
+	DBCircuitMinQueries int `split_words:"true" default:"20"`
+
+	// DBCircuitWindow is the length of the windows the queries are counted within.
+	DBCircuitWindow time.Duration `split_words:"true" default:"10s"`
+
+	// DBCircuitCooldown is how long the circuit stays open before the database is given another try.
+	DBCircuitCooldown time.Duration `split_words:"true" default:"30s"`
+
+	// AdvancedQueryBudget is the time limit of a batch of advanced queries of the v3 API. The queries not completed
+	// within it fail on their own, leaving the results of the others intact. Keep it below HTTPQueryTimeout.
+	AdvancedQueryBudget time.Duration `split_words:"true" default:"20s"`
+
+	// AdvancedQueryConcurrency is the maximum number of the queries of a batch run at once.
+	AdvancedQueryConcurrency int `split_words:"true" default:"3"`
+
+	// HTTPCompressionEnabled enables encoding the responses with zstd, brotli or gzip. Disable it when
+	// the responses are already compressed by a reverse proxy in front of the server.
+	HTTPCompressionEnabled bool `split_words:"true" default:"true"`
+
+	// HTTPCompressionCacheExpiration is how long the compressed variants of publicly cacheable responses are kept for.
+	HTTPCompressionCacheExpiration time.Duration `split_words:"true" default:"10m"`
+
+	// MaintenanceMode switches the maintenance mode on for the instances regardless of the admin API, during which the
+	// writes to the API are rejected while the reads keep being served. MaintenanceReason and MaintenanceETA, in
+	// RFC 3339, are told to the clients whose writes are rejected.
+	MaintenanceMode   bool      `split_words:"true"`
+	MaintenanceReason string    `split_words:"true"`
+	MaintenanceETA    time.Time `split_words:"true"`
+
+	// TunablesRefreshInterval is the interval in-between reloads of the tunables overridden at runtime, e.g. rate limits
+	// and cache TTLs. See internal/service/tunables.go for the available tunables. Zero disables the reloads.
+	TunablesRefreshInterval time.Duration `split_words:"true" default:"30s"`
+
+	// FeatureFlagsRefreshInterval is the interval in-between reloads of the feature flags overridden at runtime.
+	// See internal/service/feature_flags.go for the available flags. Zero disables the reloads.
+	FeatureFlagsRefreshInterval time.Duration `split_words:"true" default:"30s"`
+
+	// HealthS3ProbeEnabled enables probing the reachability of the drop report archive bucket upon readiness checks.
+	HealthS3ProbeEnabled bool `split_words:"true" default:"false"`
+
+	// CacheWarmUpEnabled enables warming up the caches of the global results upon startup. The instance only reports
+	// itself ready after the warm up, so that no traffic is routed to it while the first requests would be slow.
+	CacheWarmUpEnabled bool `split_words:"true" default:"true"`
+
+	// CacheWarmUpTimeout is the timeout for the caches to be warmed up. The instance reports itself ready after it
+	// regardless, and the caches left cold are filled upon the first requests instead.
+	CacheWarmUpTimeout time.Duration `split_words:"true" default:"5m"`
+
 	// WorkerInterval describes the interval in-between different batches
 	WorkerInterval time.Duration `required:"true" split_words:"true" default:"10m"`
 
@@ -111,20 +212,117 @@ type ConfigSpec struct {
 	// Possible keys are: "main", "trend"
 	WorkerHeartbeatURL WorkerHeartbeatURLMap `split_words:"true"`
 
+	// TimeRangeActivationInterval is the interval in-between checks for time ranges that start or end,
+	// upon which the caches depending on the current time ranges are purged.
+	TimeRangeActivationInterval time.Duration `split_words:"true" default:"15s"`
+
+	// JobPollInterval is the interval in-between the polls of the due background jobs, e.g. refreshes and archives.
+	// Zero disables the job worker on this instance, in which case the jobs are run by the other instances.
+	JobPollInterval time.Duration `split_words:"true" default:"5s"`
+
+	// JobConcurrency is the maximum number of jobs of each kind claimed by this instance upon every poll.
+	JobConcurrency int `split_words:"true" default:"2"`
+
+	// LeaderLeaseDuration is how long the leadership of an instance lasts without being renewed. The leader runs the
+	// singleton jobs, e.g. refreshes and archives; another instance takes over within this duration should it die.
+	LeaderLeaseDuration time.Duration `split_words:"true" default:"30s"`
+
+	// DropMatrixViewsRefreshInterval is the interval in-between the refreshes of the materialized views of the drop
+	// matrix, from which the drop matrix of the ended time ranges is aggregated. Zero disables the views, in which case
+	// the matrix is aggregated from the reports. The views are created by the create_drop_matrix_views script.
+	DropMatrixViewsRefreshInterval time.Duration `split_words:"true" default:"0"`
+
+	// DropReportDailyRollupsEnabled maintains the daily rollups of the reports upon ingestion and recall, and rebuilds
+	// the days of which the reliability or the exclusion of the reports changes in bulk. The tables are created by the
+	// 20261017001600 migration.
+	DropReportDailyRollupsEnabled bool `split_words:"true" default:"false"`
+
+	// DropReportDailyRollupsServing serves the global trends and the recent drop matrices from the daily rollups
+	// rather than the reports. Only turn it on once the backfill_drop_report_daily_rollups script has run, as the
+	// days before the rollups were enabled are otherwise missing. Requires DropReportDailyRollupsEnabled.
+	DropReportDailyRollupsServing bool `split_words:"true" default:"false"`
+
+	// ReportQuarantineWindow is how long after a stage opens in a server its reports are quarantined for, as the
+	// recognition of the new items is noisy in the first hours of an event. Quarantined reports are accepted and
+	// stored, but only aggregated once revalidated against the drop infos after the window. Zero disables it.
+	ReportQuarantineWindow time.Duration `split_words:"true" default:"0"`
+
+	// ReportQuarantineRevalidationInterval is the interval in-between the revalidations of the quarantined reports
+	// of which the window has passed.
+	ReportQuarantineRevalidationInterval time.Duration `split_words:"true" default:"10m"`
+
+	// ReportBatchSize is the most report tasks a report consumer writes within one transaction, up to 128. Under load,
+	// the tasks received in quick succession share the round trips and the commit; 1 writes every task on its own.
+	ReportBatchSize int `split_words:"true" default:"32"`
+
+	// ReportBatchWindow is how long a report consumer waits for more tasks after the first one of a batch before
+	// writing the batch, should it not get full in the meantime.
+	ReportBatchWindow time.Duration `split_words:"true" default:"20ms"`
+
+	// OutlierDetectionInterval is the interval in-between the screenings of the high-volume reporters of every stage
+	// for drop rates diverging from those of the other reporters of the stage, by a chi-square test. The reporters
+	// diverging beyond OutlierSignificance are excluded from the aggregations of the stage for
+	// OutlierExclusionDuration by an exclusion rule, which the moderators review. Zero disables it.
+	OutlierDetectionInterval time.Duration `split_words:"true" default:"0"`
+
+	// OutlierDetectionWindow is how far back the reports are screened for the outlier reporters.
+	OutlierDetectionWindow time.Duration `split_words:"true" default:"168h"`
+
+	// OutlierMinTimes is the number of times a reporter has to have cleared a stage within OutlierDetectionWindow to
+	// be screened, below which the test has too little power.
+	OutlierMinTimes int `split_words:"true" default:"300"`
+
+	// OutlierSignificance is the probability of any reporter screened in a run being wrongly excluded, which is split
+	// evenly among them.
+	OutlierSignificance float64 `split_words:"true" default:"0.001"`
+
+	// OutlierExclusionDuration is how long the outlier reporters are excluded for, unless their exclusion rules are
+	// changed in the meantime.
+	OutlierExclusionDuration time.Duration `split_words:"true" default:"168h"`
+
+	// GeoIPASNDatabasePath is the path of the GeoLite2-ASN or GeoIP2-ISP database of MaxMind, which tells the autonomous
+	// systems the reports are submitted from. Unlike the country database, it is not embedded; without it, the
+	// submissions are only flagged by SubmissionFlaggedNetworks and SubmissionFlaggedCountries.
+	GeoIPASNDatabasePath string `split_words:"true"`
+
+	// SubmissionFlaggedASNs are the autonomous systems, e.g. of the datacenters that have hosted waves of spam, the
+	// reports submitted from which are flagged: they are limited by the report.flagged rate limit tunable and told to
+	// present a challenge.
+	SubmissionFlaggedASNs []int `split_words:"true"`
+
+	// SubmissionFlaggedNetworks are the networks in CIDR notation the reports submitted from which are flagged.
+	SubmissionFlaggedNetworks []string `split_words:"true"`
+
+	// SubmissionFlaggedCountries are the ISO 3166-1 codes of the countries the reports submitted from which are flagged.
+	SubmissionFlaggedCountries []string `split_words:"true"`
+
+	// SubmissionChallengeEnabled requires the reports submitted from the accounts that have never submitted one to
+	// come with the solution to a proof of work challenge, raising the cost of submitting fake reports in bulk from
+	// throwaway accounts. The difficulty is the challenge.difficulty tunable.
+	SubmissionChallengeEnabled bool `split_words:"true"`
+
+	// SubmissionChallengeLifetime is how long a challenge can be solved and redeemed within since it has been issued.
+	SubmissionChallengeLifetime time.Duration `split_words:"true" default:"5m"`
+
 	// WorkerEnabled is a flag to indicate whether to enable the worker.
 	WorkerEnabled bool `split_words:"true"`
 
-	// AdminKey is the key used to authenticate the admin API.
-	AdminKey string `split_words:"true"`
-
 	// MatrixWorkerSourceCategories is a list of categories that the matrix worker will run for.
 	// Available categories are: all, automated, manual.
 	MatrixWorkerSourceCategories []string `required:"true" split_words:"true" default:"all"`
 
+	// MatrixWorkerConcurrency is the maximum number of time ranges the matrix worker calculates the drop matrix
+	// of at once.
+	MatrixWorkerConcurrency int `split_words:"true" default:"4"`
+
 	// For PatternMatrix query api, if showAllPatterns is false, then only show the top 50 patterns for all stages
 	// We don't want to show all patterns because it will be too many. So we set a limit here (default 19)
 	PatternMatrixLimit int `split_words:"true" default:"19"`
 
+	// MatrixSnapshotRetention is how long the snapshots of the drop matrix recorded after each refresh are kept for,
+	// to compute the deltas of the matrix against. Clients having a matrix older than that receive the full matrix.
+	MatrixSnapshotRetention time.Duration `split_words:"true" default:"24h"`
+
 	DropReportArchiveEnabled   bool `split_words:"true" default:"false"`
 	DropReportArchiveBatchSize int  `split_words:"true" default:"1000"`
 
@@ -133,9 +331,53 @@ type ConfigSpec struct {
 	AWSAccessKey              string `required:"true" split_words:"true"`
 	AWSSecretKey              string `required:"true" split_words:"true"`
 
+	// ItemIconS3Bucket is the bucket the item icons are uploaded to, which is the drop report archive bucket when empty.
+	ItemIconS3Bucket string `split_words:"true"`
+
+	// ItemIconS3Prefix is the prefix of the keys of the item icons in the bucket.
+	ItemIconS3Prefix string `split_words:"true" default:"item-icons/"`
+
+	// ItemIconBaseURL is the public URL the item icon bucket is served under, such as a CDN in front of it. The icons
+	// cannot be uploaded until it is set.
+	ItemIconBaseURL string `split_words:"true"`
+
 	NoArchiveDays int `split_words:"true" default:"60"`
 
+	// DropReportPartitionPruneEnabled drops the monthly partitions of drop_reports of which every day has been
+	// archived, along with the extras of their reports. It only applies once drop_reports has been partitioned.
+	DropReportPartitionPruneEnabled bool `split_words:"true" default:"false"`
+
 	DeleteDropReportAfterArchive bool `split_words:"true" default:"false"`
+
+	// DropReportRetentionDays is the number of days the drop reports are kept in the database for. Older days are
+	// purged once their archives have been verified, and read back from the archives upon exports. It must be no less
+	// than NoArchiveDays; zero keeps the reports in the database.
+	DropReportRetentionDays int `split_words:"true" default:"0"`
+
+	// ArchiveReadbackMaxDays is the maximum number of purged days read back from the archives for a single query.
+	// Queries spanning more of them are refused, hinting at narrowing the time range down.
+	ArchiveReadbackMaxDays int `split_words:"true" default:"31"`
+
+	// GameDataSyncURL is the base URL of the community-extracted game data excel tables (item_table.json, stage_table.json),
+	// used by the game data sync importer when no tables are uploaded.
+	GameDataSyncURL string `split_words:"true" default:"https://raw.githubusercontent.com/Kengxxiao/ArknightsGameData/master/zh_CN/gamedata/excel"`
+
+	// GameDataSyncTimeout is the timeout for fetching a single game data table.
+	GameDataSyncTimeout time.Duration `split_words:"true" default:"60s"`
+
+	// WebhookDeliveryTimeout is the timeout for a single delivery attempt of a webhook event.
+	WebhookDeliveryTimeout time.Duration `split_words:"true" default:"10s"`
+
+	// WebhookDeliveryMaxAttempts is the number of attempts to deliver a webhook event before giving up.
+	// Retries are backed off exponentially, starting from 30 seconds.
+	WebhookDeliveryMaxAttempts int `split_words:"true" default:"8"`
+
+	// APIKeyDefaultDailyRequestQuota is the daily request quota of newly issued API keys, unless specified otherwise.
+	APIKeyDefaultDailyRequestQuota int `split_words:"true" default:"100000"`
+
+	// APIKeyDefaultDailyByteQuota is the daily response bytes quota of newly issued API keys, unless specified otherwise.
+	// Defaults to 10 GiB.
+	APIKeyDefaultDailyByteQuota int64 `split_words:"true" default:"10737418240"`
 }
 
 type Config struct {
@@ -28,7 +28,7 @@ func Module(o ...opt) fx.Option {
 	for _, opt := range o {
 		switch opt {
 		case OptIncludeSwagger:
-			opts = append(opts, fx.Invoke(controllermeta.RegisterSwagger))
+			opts = append(opts, fx.Invoke(controllermeta.RegisterSwagger), fx.Invoke(controllermeta.RegisterV3Swagger))
 		}
 	}
 
@@ -9,5 +9,22 @@ func Module() fx.Option {
 		RegisterMeta,
 		RegisterIndex,
 		RegisterAdmin,
+		RegisterAdminItem,
+		RegisterAdminStage,
+		RegisterAdminEvent,
+		RegisterAdminDropInfo,
+		RegisterAdminExclusionRule,
+		RegisterAdminReportReview,
+		RegisterAdminGameData,
+		RegisterAdminMetadata,
+		RegisterAdminJob,
+		RegisterAdminAPIKey,
+		RegisterAdminTunable,
+		RegisterAdminMaintenance,
+		RegisterAdminCacheTTLOverride,
+		RegisterAdminFeatureFlag,
+		RegisterAdminRecognition,
+		RegisterAdminClientVersion,
+		RegisterAdminNotice,
 	))
 }
@@ -27,6 +27,7 @@ import (
 	"exusiai.dev/backend-next/internal/model/gamedata"
 	"exusiai.dev/backend-next/internal/model/types"
 	"exusiai.dev/backend-next/internal/pkg/flog"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
 	"exusiai.dev/backend-next/internal/pkg/pgerr"
 	"exusiai.dev/backend-next/internal/repo"
 	"exusiai.dev/backend-next/internal/server/svr"
@@ -41,6 +42,7 @@ type AdminController struct {
 	PatternRepo              *repo.DropPattern
 	PatternElementRepo       *repo.DropPatternElement
 	RecognitionDefectRepo    *repo.RecognitionDefect
+	RecognitionDefectService *service.RecognitionDefect
 	AdminService             *service.Admin
 	ItemService              *service.Item
 	StageService             *service.Stage
@@ -59,38 +61,57 @@ type AdminController struct {
 	ExportService            *service.Export
 	AccountService           *service.Account
 	ArchiveService           *service.Archive
+	AccountStandingService   *service.AccountStanding
+	AccountRoleService       *service.AccountRole
+	ShadowBanService         *service.ShadowBan
 }
 
 func RegisterAdmin(admin *svr.Admin, c AdminController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+	moderator := middlewares.RequireRoles(model.RoleModerator)
+	staff := middlewares.RequireRoles(model.RoleModerator, model.RoleMaintainer)
+	administrator := middlewares.RequireRoles(model.RoleAdmin)
+
 	admin.Get("/bonjour", c.Bonjour)
-	admin.Post("/save", c.SaveRenderedObjects)
-	admin.Post("/purge", c.PurgeCache)
+	admin.Post("/save", maintainer, c.SaveRenderedObjects)
+	admin.Post("/purge", maintainer, c.PurgeCache)
+
+	admin.Post("/clone", maintainer, c.CloneFromCN)
+
+	admin.Post("/rejections/reject-rules/reevaluation/preview", moderator, c.RejectRulesReevaluationPreview)
+	admin.Post("/rejections/reject-rules/reevaluation/apply", moderator, c.RejectRulesReevaluationApply)
 
-	admin.Post("/clone", c.CloneFromCN)
+	admin.Get("/cli/gamedata/seed", maintainer, c.GetCliGameDataSeed)
+	admin.Get("/internal/time-faked/stages", maintainer, c.GetFakeTimeStages)
+	admin.Get("/_temp/pattern/merging", maintainer, c.FindPatterns)
+	admin.Get("/_temp/pattern/disambiguation", maintainer, c.DisambiguatePatterns)
 
-	admin.Post("/rejections/reject-rules/reevaluation/preview", c.RejectRulesReevaluationPreview)
-	admin.Post("/rejections/reject-rules/reevaluation/apply", c.RejectRulesReevaluationApply)
+	admin.Get("/analytics/report-unique-users/by-source", staff, c.GetRecentUniqueUserCountBySource)
 
-	admin.Get("/cli/gamedata/seed", c.GetCliGameDataSeed)
-	admin.Get("/internal/time-faked/stages", c.GetFakeTimeStages)
-	admin.Get("/_temp/pattern/merging", c.FindPatterns)
-	admin.Get("/_temp/pattern/disambiguation", c.DisambiguatePatterns)
+	admin.Post("/refresh/matrix", maintainer, c.CalcDropMatrixElements)
+	admin.Post("/refresh/pattern", maintainer, c.CalcPatternMatrixElements)
+	admin.Get("/refresh/sitestats/:server", maintainer, c.RefreshAllSiteStats)
 
-	admin.Get("/analytics/report-unique-users/by-source", c.GetRecentUniqueUserCountBySource)
+	admin.Get("/recognition/defects", moderator, c.GetRecognitionDefects)
+	admin.Get("/recognition/defects/:defectId", moderator, c.GetRecognitionDefect)
+	admin.Get("/v3/recognition/defects", moderator, c.GetRecognitionDefectsPage)
+	admin.Post("/recognition/items-resources/updated", maintainer, c.RecognitionItemsResourcesUpdated)
 
-	admin.Post("/refresh/matrix", c.CalcDropMatrixElements)
-	admin.Post("/refresh/pattern", c.CalcPatternMatrixElements)
-	admin.Get("/refresh/sitestats/:server", c.RefreshAllSiteStats)
+	admin.Post("/export/drop-report", staff, c.ExportDropReport)
 
-	admin.Get("/recognition/defects", c.GetRecognitionDefects)
-	admin.Get("/recognition/defects/:defectId", c.GetRecognitionDefect)
-	admin.Post("/recognition/items-resources/updated", c.RecognitionItemsResourcesUpdated)
+	admin.Post("/snapshots", maintainer, c.CreateSnapshot)
 
-	admin.Post("/export/drop-report", c.ExportDropReport)
+	admin.Post("/archive", maintainer, c.ArchiveDropReports)
 
-	admin.Post("/snapshots", c.CreateSnapshot)
+	admin.Get("/accounts/appeals", moderator, c.GetPendingAccountAppeals)
+	admin.Post("/accounts/appeals/:appealId/resolve", moderator, c.ResolveAccountAppeal)
 
-	admin.Post("/archive", c.ArchiveDropReports)
+	admin.Post("/accounts/:penguinId/roles", administrator, c.GrantAccountRole)
+	admin.Delete("/accounts/:penguinId/roles/:role", administrator, c.RevokeAccountRole)
+
+	admin.Get("/accounts/shadow-bans", moderator, c.GetShadowBannedAccounts)
+	admin.Put("/accounts/:penguinId/shadow-ban", moderator, c.ShadowBanAccount)
+	admin.Delete("/accounts/:penguinId/shadow-ban", moderator, c.LiftShadowBan)
 }
 
 type CliGameDataSeedResponse struct {
@@ -356,6 +377,7 @@ type RecognitionDefectsResponse struct {
 	Image             *RecognitionDefectsResponseImage `json:"image,omitempty"`
 	RecognitionResult json.RawMessage                  `json:"recognitionResult"`
 	Environment       json.RawMessage                  `json:"environment"`
+	APIKeyID          int                              `json:"apiKeyId,omitempty"`
 }
 
 func (c *AdminController) GetRecognitionDefects(ctx *fiber.Ctx) error {
@@ -385,6 +407,40 @@ func (c *AdminController) GetRecognitionDefects(ctx *fiber.Ctx) error {
 	return ctx.JSON(responses)
 }
 
+type RecognitionDefectsPageResponse struct {
+	Defects []*RecognitionDefectsResponse `json:"defects"`
+	Total   int                           `json:"total"`
+	Page    int                           `json:"page"`
+	Limit   int                           `json:"limit"`
+}
+
+// GetRecognitionDefectsPage lists the defects for triage, optionally only those of an API key or a server
+func (c *AdminController) GetRecognitionDefectsPage(ctx *fiber.Ctx) error {
+	var query types.RecognitionDefectsQuery
+	if err := rekuest.ValidQuery(ctx, &query); err != nil {
+		return err
+	}
+
+	defects, total, err := c.RecognitionDefectService.GetDefectsPage(ctx.UserContext(), &query)
+	if err != nil {
+		return err
+	}
+
+	response := &RecognitionDefectsPageResponse{
+		Defects: make([]*RecognitionDefectsResponse, len(defects)),
+		Total:   total,
+		Page:    query.Page,
+		Limit:   query.Limit,
+	}
+	for i, defect := range defects {
+		response.Defects[i], err = c.transformRecognitionDefect(defect)
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.JSON(response)
+}
+
 func (c *AdminController) transformRecognitionDefect(defect *model.RecognitionDefect) (*RecognitionDefectsResponse, error) {
 	response := &RecognitionDefectsResponse{
 		DefectID:          defect.DefectID,
@@ -394,6 +450,7 @@ func (c *AdminController) transformRecognitionDefect(defect *model.RecognitionDe
 		AccountID:         defect.AccountID,
 		RecognitionResult: defect.RecognitionResult,
 		Environment:       defect.Environment,
+		APIKeyID:          defect.APIKeyID,
 	}
 
 	if defect.ImageURI != "" {
@@ -586,7 +643,7 @@ func (c *AdminController) CalcDropMatrixElements(ctx *fiber.Ctx) error {
 		if err != nil {
 			return err
 		}
-		err = c.DropMatrixService.UpdateDropMatrixByGivenDate(ctx.UserContext(), request.Server, &date)
+		err = c.DropMatrixService.UpdateDropMatrixByGivenDate(ctx.UserContext(), request.Server, &date, nil)
 		if err != nil {
 			return err
 		}
@@ -609,7 +666,7 @@ func (c *AdminController) CalcPatternMatrixElements(ctx *fiber.Ctx) error {
 		if err != nil {
 			return err
 		}
-		err = c.PatternMatrixService.UpdatePatternMatrixByGivenDate(ctx.UserContext(), request.Server, &date)
+		err = c.PatternMatrixService.UpdatePatternMatrixByGivenDate(ctx.UserContext(), request.Server, &date, nil)
 		if err != nil {
 			return err
 		}
@@ -666,11 +723,15 @@ func (c *AdminController) ExportDropReport(ctx *fiber.Ctx) error {
 	}
 
 	// handle item ids
-	itemIds := make([]int, 0)
+	itemsMapByArkId, err := c.ItemService.GetItemsByArkIds(ctx.UserContext(), request.ItemIDs)
+	if err != nil {
+		return err
+	}
+	itemIds := make([]int, 0, len(request.ItemIDs))
 	for _, arkItemID := range request.ItemIDs {
-		item, err := c.ItemService.GetItemByArkId(ctx.UserContext(), arkItemID)
-		if err != nil {
-			return err
+		item, ok := itemsMapByArkId[arkItemID]
+		if !ok {
+			return pgerr.ErrNotFound
 		}
 		itemIds = append(itemIds, item.ItemID)
 	}
@@ -732,3 +793,96 @@ func (c *AdminController) ArchiveDropReports(ctx *fiber.Ctx) error {
 	}
 	return ctx.SendStatus(fiber.StatusOK)
 }
+
+func (c *AdminController) GetPendingAccountAppeals(ctx *fiber.Ctx) error {
+	type getPendingAccountAppealsRequest struct {
+		Limit int `query:"limit"`
+		Page  int `query:"page"`
+	}
+	var request getPendingAccountAppealsRequest
+	if err := rekuest.ValidQuery(ctx, &request); err != nil {
+		return err
+	}
+	if request.Limit <= 0 {
+		request.Limit = 50
+	}
+
+	appeals, err := c.AccountStandingService.GetPendingAppeals(ctx.UserContext(), request.Limit, request.Page)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(appeals)
+}
+
+func (c *AdminController) ResolveAccountAppeal(ctx *fiber.Ctx) error {
+	appealId, err := strconv.Atoi(ctx.Params("appealId"))
+	if err != nil {
+		return pgerr.ErrInvalidReq.Msg("invalid appealId")
+	}
+
+	var request types.ResolveAccountAppealRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	appeal, err := c.AccountStandingService.ResolveAppeal(ctx.UserContext(), appealId, *request.Approve, request.Resolution)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(appeal)
+}
+
+func (c *AdminController) GrantAccountRole(ctx *fiber.Ctx) error {
+	var request types.GrantAccountRoleRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	result, err := c.AccountRoleService.GrantRole(ctx.UserContext(), ctx.Params("penguinId"), request.Role)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(result)
+}
+
+func (c *AdminController) RevokeAccountRole(ctx *fiber.Ctx) error {
+	role := ctx.Params("role")
+	if !lo.Contains(model.Roles, role) {
+		return pgerr.ErrInvalidReq.Msg("invalid role: %s", role)
+	}
+
+	account, err := c.AccountRoleService.RevokeRole(ctx.UserContext(), ctx.Params("penguinId"), role)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(account)
+}
+
+func (c *AdminController) GetShadowBannedAccounts(ctx *fiber.Ctx) error {
+	accounts, err := c.ShadowBanService.GetShadowBannedAccounts(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(accounts)
+}
+
+func (c *AdminController) ShadowBanAccount(ctx *fiber.Ctx) error {
+	var request types.ShadowBanAccountRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	result, err := c.ShadowBanService.ShadowBanAccount(ctx.UserContext(), ctx.Params("penguinId"), &request)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(result)
+}
+
+func (c *AdminController) LiftShadowBan(ctx *fiber.Ctx) error {
+	result, err := c.ShadowBanService.LiftShadowBan(ctx.UserContext(), ctx.Params("penguinId"))
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(result)
+}
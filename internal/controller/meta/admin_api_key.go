@@ -0,0 +1,123 @@
+package meta
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+type AdminAPIKeyController struct {
+	fx.In
+
+	APIKeyService   *service.APIKey
+	APIUsageService *service.APIUsage
+	WebhookService  *service.Webhook
+}
+
+func RegisterAdminAPIKey(admin *svr.Admin, c AdminAPIKeyController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+	moderator := middlewares.RequireRoles(model.RoleModerator, model.RoleMaintainer)
+
+	admin.Get("/v3/api-keys", maintainer, c.GetAPIKeys)
+	admin.Post("/v3/api-keys", maintainer, c.CreateAPIKey)
+	admin.Delete("/v3/api-keys/:apiKeyId", maintainer, c.RevokeAPIKey)
+	admin.Patch("/v3/api-keys/:apiKeyId/quota", maintainer, c.UpdateAPIKeyQuota)
+	admin.Get("/v3/api-keys/:apiKeyId/usage", maintainer, c.GetAPIKeyUsage)
+
+	// events without an automated source, e.g. anomaly.detected, are published by moderators
+	admin.Post("/v3/webhooks/events", moderator, c.PublishWebhookEvent)
+}
+
+func (c *AdminAPIKeyController) GetAPIKeys(ctx *fiber.Ctx) error {
+	apiKeys, err := c.APIKeyService.GetAPIKeys(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(apiKeys)
+}
+
+// CreateAPIKey issues a new API key. The key is only included in this response.
+func (c *AdminAPIKeyController) CreateAPIKey(ctx *fiber.Ctx) error {
+	var request types.CreateAPIKeyRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	result, err := c.APIKeyService.CreateAPIKey(ctx.UserContext(), &request)
+	if err != nil {
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(result)
+}
+
+func (c *AdminAPIKeyController) RevokeAPIKey(ctx *fiber.Ctx) error {
+	apiKeyId, err := strconv.Atoi(ctx.Params("apiKeyId"))
+	if err != nil {
+		return pgerr.ErrInvalidReq.Msg("invalid apiKeyId")
+	}
+
+	apiKey, err := c.APIKeyService.RevokeAPIKey(ctx.UserContext(), apiKeyId)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(apiKey)
+}
+
+func (c *AdminAPIKeyController) UpdateAPIKeyQuota(ctx *fiber.Ctx) error {
+	apiKeyId, err := strconv.Atoi(ctx.Params("apiKeyId"))
+	if err != nil {
+		return pgerr.ErrInvalidReq.Msg("invalid apiKeyId")
+	}
+
+	var request types.UpdateAPIKeyQuotaRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	apiKey, err := c.APIKeyService.UpdateAPIKeyQuota(ctx.UserContext(), apiKeyId, &request)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(apiKey)
+}
+
+func (c *AdminAPIKeyController) GetAPIKeyUsage(ctx *fiber.Ctx) error {
+	apiKeyId, err := strconv.Atoi(ctx.Params("apiKeyId"))
+	if err != nil {
+		return pgerr.ErrInvalidReq.Msg("invalid apiKeyId")
+	}
+
+	apiKey, err := c.APIKeyService.GetAPIKeyById(ctx.UserContext(), apiKeyId)
+	if err != nil {
+		return err
+	}
+
+	usage, err := c.APIUsageService.GetAPIUsage(ctx.UserContext(), apiKey, ctx.QueryInt("days", service.APIUsageRetentionDays))
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(usage)
+}
+
+func (c *AdminAPIKeyController) PublishWebhookEvent(ctx *fiber.Ctx) error {
+	var request types.PublishWebhookEventRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	// manually published events are never deduplicated
+	if err := c.WebhookService.Publish(ctx.UserContext(), request.Type, strconv.FormatInt(time.Now().UnixNano(), 10), request.Data); err != nil {
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusAccepted)
+}
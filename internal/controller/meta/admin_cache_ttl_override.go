@@ -0,0 +1,61 @@
+package meta
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+type AdminCacheTTLOverrideController struct {
+	fx.In
+
+	CacheTTLOverrides *service.CacheTTLOverrides
+}
+
+func RegisterAdminCacheTTLOverride(admin *svr.Admin, c AdminCacheTTLOverrideController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+
+	admin.Get("/v3/cache-ttl-overrides", maintainer, c.GetCacheTTLOverrides)
+	admin.Put("/v3/cache-ttl-overrides/:scope/:arkId", maintainer, c.SetCacheTTLOverride)
+	admin.Delete("/v3/cache-ttl-overrides/:scope/:arkId", maintainer, c.DeleteCacheTTLOverride)
+}
+
+func (c *AdminCacheTTLOverrideController) GetCacheTTLOverrides(ctx *fiber.Ctx) error {
+	return ctx.JSON(c.CacheTTLOverrides.GetCacheTTLOverrides())
+}
+
+// SetCacheTTLOverride overrides the cache TTL of the stages of a zone, or of a stage. Other instances pick it up within
+// the tunables refresh interval.
+func (c *AdminCacheTTLOverrideController) SetCacheTTLOverride(ctx *fiber.Ctx) error {
+	var request types.SetCacheTTLOverrideRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	override, err := c.CacheTTLOverrides.SetCacheTTLOverride(ctx.UserContext(), ctx.Params("scope"), ctx.Params("arkId"), &request)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(override)
+}
+
+// DeleteCacheTTLOverride deletes the override of the server in the query, or the one of every server without it
+func (c *AdminCacheTTLOverrideController) DeleteCacheTTLOverride(ctx *fiber.Ctx) error {
+	server := ctx.Query("server")
+	if server != "" {
+		if err := rekuest.ValidServer(ctx, server); err != nil {
+			return err
+		}
+	}
+
+	if err := c.CacheTTLOverrides.DeleteCacheTTLOverride(ctx.UserContext(), ctx.Params("scope"), ctx.Params("arkId"), server); err != nil {
+		return err
+	}
+	return ctx.JSON(c.CacheTTLOverrides.GetCacheTTLOverrides())
+}
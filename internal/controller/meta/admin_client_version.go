@@ -0,0 +1,61 @@
+package meta
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+type AdminClientVersionController struct {
+	fx.In
+
+	ClientVersionService *service.ClientVersion
+}
+
+func RegisterAdminClientVersion(admin *svr.Admin, c AdminClientVersionController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+
+	admin.Get("/v3/client-versions", maintainer, c.GetClientVersions)
+	admin.Put("/v3/client-versions/:source", maintainer, c.SaveClientVersion)
+	admin.Delete("/v3/client-versions/:source", maintainer, c.DeleteClientVersion)
+}
+
+func (c *AdminClientVersionController) GetClientVersions(ctx *fiber.Ctx) error {
+	clientVersions, err := c.ClientVersionService.GetClientVersions(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(clientVersions)
+}
+
+func (c *AdminClientVersionController) SaveClientVersion(ctx *fiber.Ctx) error {
+	source := ctx.Params("source")
+	if err := rekuest.Validate.Var(source, "required,printascii,max=128"); err != nil {
+		return pgerr.ErrInvalidReq.Msg("invalid source")
+	}
+
+	var request types.SaveClientVersionRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	clientVersion, err := c.ClientVersionService.SaveClientVersion(ctx.UserContext(), source, &request)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(clientVersion)
+}
+
+func (c *AdminClientVersionController) DeleteClientVersion(ctx *fiber.Ctx) error {
+	if err := c.ClientVersionService.DeleteClientVersion(ctx.UserContext(), ctx.Params("source")); err != nil {
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
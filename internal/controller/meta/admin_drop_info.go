@@ -0,0 +1,90 @@
+package meta
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+type AdminDropInfoController struct {
+	fx.In
+
+	AdminService          *service.Admin
+	DropInfoBoundsService *service.DropInfoBounds
+}
+
+func RegisterAdminDropInfo(admin *svr.Admin, c AdminDropInfoController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+
+	admin.Post("/v3/dropinfos/cutover", maintainer, c.CutOverDropInfos)
+	admin.Get("/v3/dropinfos/bounds/suggestions", maintainer, c.SuggestDropInfoBounds)
+	admin.Put("/v3/dropinfos/bounds", maintainer, c.ApplyDropInfoBounds)
+}
+
+func (c *AdminDropInfoController) CutOverDropInfos(ctx *fiber.Ctx) error {
+	var request types.CutOverDropInfosRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	result, err := c.AdminService.CutOverDropInfos(ctx.UserContext(), &request)
+	if err != nil {
+		return err
+	}
+
+	if result.DryRun {
+		return ctx.JSON(result)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(result)
+}
+
+// SuggestDropInfoBounds suggests the bounds of the drop infos of the stage given by the stageId query in the server,
+// from the patterns reported over the window query (a duration such as 168h), or the tunable default when omitted
+func (c *AdminDropInfoController) SuggestDropInfoBounds(ctx *fiber.Ctx) error {
+	server := ctx.Query("server", "CN")
+	if err := rekuest.ValidServer(ctx, server); err != nil {
+		return err
+	}
+	stageId := ctx.Query("stageId")
+	if stageId == "" {
+		return pgerr.ErrInvalidReq.Msg("stageId is required")
+	}
+	var window time.Duration
+	if s := ctx.Query("window"); s != "" {
+		var err error
+		window, err = time.ParseDuration(s)
+		if err != nil || window <= 0 {
+			return pgerr.ErrInvalidReq.Msg("window must be a positive duration, such as 168h")
+		}
+	}
+
+	suggestions, err := c.DropInfoBoundsService.SuggestDropInfoBounds(ctx.UserContext(), server, stageId, window)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(suggestions)
+}
+
+func (c *AdminDropInfoController) ApplyDropInfoBounds(ctx *fiber.Ctx) error {
+	var request types.ApplyDropInfoBoundsRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	dropInfos, err := c.DropInfoBoundsService.ApplyDropInfoBounds(ctx.UserContext(), &request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(dropInfos)
+}
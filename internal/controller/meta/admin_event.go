@@ -0,0 +1,106 @@
+package meta
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+type AdminEventController struct {
+	fx.In
+
+	AdminService *service.Admin
+	EventService *service.Event
+}
+
+func RegisterAdminEvent(admin *svr.Admin, c AdminEventController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+
+	admin.Post("/v3/events/onboard", maintainer, c.OnboardEvent)
+	admin.Post("/v3/events/clone", maintainer, c.CloneRerunEvent)
+	admin.Get("/v3/events", maintainer, c.GetEvents)
+	admin.Post("/v3/events", maintainer, c.CreateEvent)
+	admin.Put("/v3/events/:eventId", maintainer, c.UpdateEvent)
+}
+
+func (c *AdminEventController) OnboardEvent(ctx *fiber.Ctx) error {
+	var request types.OnboardEventRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	result, err := c.AdminService.OnboardEvent(ctx.UserContext(), &request)
+	if err != nil {
+		return err
+	}
+
+	if result.DryRun {
+		return ctx.JSON(result)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(result)
+}
+
+func (c *AdminEventController) CloneRerunEvent(ctx *fiber.Ctx) error {
+	var request types.CloneRerunEventRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	result, err := c.AdminService.CloneRerunEvent(ctx.UserContext(), &request)
+	if err != nil {
+		return err
+	}
+
+	if result.DryRun {
+		return ctx.JSON(result)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(result)
+}
+
+func (c *AdminEventController) GetEvents(ctx *fiber.Ctx) error {
+	events, err := c.EventService.GetEvents(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(events)
+}
+
+func (c *AdminEventController) CreateEvent(ctx *fiber.Ctx) error {
+	var request types.SaveEventRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	event, err := c.EventService.SaveEvent(ctx.UserContext(), 0, &request)
+	if err != nil {
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(event)
+}
+
+func (c *AdminEventController) UpdateEvent(ctx *fiber.Ctx) error {
+	eventId, err := strconv.Atoi(ctx.Params("eventId"))
+	if err != nil || eventId <= 0 {
+		return pgerr.ErrInvalidReq.Msg("invalid eventId")
+	}
+
+	var request types.SaveEventRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	event, err := c.EventService.SaveEvent(ctx.UserContext(), eventId, &request)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(event)
+}
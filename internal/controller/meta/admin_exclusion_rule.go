@@ -0,0 +1,83 @@
+package meta
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+type AdminExclusionRuleController struct {
+	fx.In
+
+	ExclusionRuleService *service.ExclusionRule
+}
+
+func RegisterAdminExclusionRule(admin *svr.Admin, c AdminExclusionRuleController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+
+	admin.Get("/v3/exclusion-rules", maintainer, c.GetExclusionRules)
+	admin.Post("/v3/exclusion-rules", maintainer, c.CreateExclusionRule)
+	admin.Put("/v3/exclusion-rules/:ruleId", maintainer, c.UpdateExclusionRule)
+	admin.Delete("/v3/exclusion-rules/:ruleId", maintainer, c.DeleteExclusionRule)
+}
+
+func (c *AdminExclusionRuleController) GetExclusionRules(ctx *fiber.Ctx) error {
+	rules, err := c.ExclusionRuleService.GetExclusionRules(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(rules)
+}
+
+func (c *AdminExclusionRuleController) CreateExclusionRule(ctx *fiber.Ctx) error {
+	var request types.SaveExclusionRuleRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	result, err := c.ExclusionRuleService.SaveExclusionRule(ctx.UserContext(), 0, &request)
+	if err != nil {
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(result)
+}
+
+func (c *AdminExclusionRuleController) UpdateExclusionRule(ctx *fiber.Ctx) error {
+	ruleId, err := strconv.Atoi(ctx.Params("ruleId"))
+	if err != nil || ruleId <= 0 {
+		return pgerr.ErrInvalidReq.Msg("invalid ruleId")
+	}
+
+	var request types.SaveExclusionRuleRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	result, err := c.ExclusionRuleService.SaveExclusionRule(ctx.UserContext(), ruleId, &request)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(result)
+}
+
+func (c *AdminExclusionRuleController) DeleteExclusionRule(ctx *fiber.Ctx) error {
+	ruleId, err := strconv.Atoi(ctx.Params("ruleId"))
+	if err != nil || ruleId <= 0 {
+		return pgerr.ErrInvalidReq.Msg("invalid ruleId")
+	}
+
+	result, err := c.ExclusionRuleService.DeleteExclusionRule(ctx.UserContext(), ruleId)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(result)
+}
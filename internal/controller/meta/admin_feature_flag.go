@@ -0,0 +1,52 @@
+package meta
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+type AdminFeatureFlagController struct {
+	fx.In
+
+	FeatureFlags *service.FeatureFlags
+}
+
+func RegisterAdminFeatureFlag(admin *svr.Admin, c AdminFeatureFlagController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+
+	admin.Get("/v3/flags", maintainer, c.GetFeatureFlags)
+	admin.Put("/v3/flags/:key", maintainer, c.SetFeatureFlag)
+	admin.Delete("/v3/flags/:key", maintainer, c.ResetFeatureFlag)
+}
+
+func (c *AdminFeatureFlagController) GetFeatureFlags(ctx *fiber.Ctx) error {
+	return ctx.JSON(c.FeatureFlags.GetFeatureFlags())
+}
+
+// SetFeatureFlag overrides the rollout of a feature flag. Other instances pick up the new rollout within
+// the feature flags refresh interval.
+func (c *AdminFeatureFlagController) SetFeatureFlag(ctx *fiber.Ctx) error {
+	var request types.SetFeatureFlagRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	if err := c.FeatureFlags.SetFeatureFlag(ctx.UserContext(), ctx.Params("key"), &request); err != nil {
+		return err
+	}
+	return ctx.JSON(c.FeatureFlags.GetFeatureFlags())
+}
+
+func (c *AdminFeatureFlagController) ResetFeatureFlag(ctx *fiber.Ctx) error {
+	if err := c.FeatureFlags.ResetFeatureFlag(ctx.UserContext(), ctx.Params("key")); err != nil {
+		return err
+	}
+	return ctx.JSON(c.FeatureFlags.GetFeatureFlags())
+}
@@ -0,0 +1,77 @@
+package meta
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/gamedata"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+type AdminGameDataController struct {
+	fx.In
+
+	GameDataSyncService *service.GameDataSync
+}
+
+func RegisterAdminGameData(admin *svr.Admin, c AdminGameDataController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+
+	admin.Post("/v3/gamedata/sync", maintainer, c.CreateChangeSet)
+	admin.Get("/v3/gamedata/changesets/:changeSetId", maintainer, c.GetChangeSet)
+	admin.Post("/v3/gamedata/changesets/:changeSetId/apply", maintainer, c.ApplyChangeSet)
+}
+
+type GameDataSyncRequest struct {
+	// ItemTable and StageTable are the uploaded contents of item_table.json and stage_table.json.
+	// When both are omitted, the tables are fetched from the configured game data URL.
+	// Uploads are subject to the server body limit, so trimmed tables should be uploaded when needed.
+	ItemTable  *gamedata.ItemTable  `json:"itemTable"`
+	StageTable *gamedata.StageTable `json:"stageTable"`
+}
+
+func (c *AdminGameDataController) CreateChangeSet(ctx *fiber.Ctx) error {
+	var request GameDataSyncRequest
+	if len(ctx.Body()) > 0 {
+		if err := rekuest.ValidBody(ctx, &request); err != nil {
+			return err
+		}
+	}
+
+	source := "upload"
+	itemTable, stageTable := request.ItemTable, request.StageTable
+	if itemTable == nil && stageTable == nil {
+		var err error
+		itemTable, stageTable, err = c.GameDataSyncService.FetchTables(ctx.UserContext())
+		if err != nil {
+			return err
+		}
+		source = "remote"
+	}
+
+	changeSet, err := c.GameDataSyncService.CreateChangeSet(ctx.UserContext(), source, itemTable, stageTable)
+	if err != nil {
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(changeSet)
+}
+
+func (c *AdminGameDataController) GetChangeSet(ctx *fiber.Ctx) error {
+	changeSet, err := c.GameDataSyncService.GetChangeSet(ctx.UserContext(), ctx.Params("changeSetId"))
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(changeSet)
+}
+
+func (c *AdminGameDataController) ApplyChangeSet(ctx *fiber.Ctx) error {
+	changeSet, err := c.GameDataSyncService.ApplyChangeSet(ctx.UserContext(), ctx.Params("changeSetId"))
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(changeSet)
+}
@@ -0,0 +1,183 @@
+package meta
+
+import (
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+type AdminItemController struct {
+	fx.In
+
+	ItemService      *service.Item
+	ItemValueService *service.ItemValue
+	ItemIconService  *service.ItemIcon
+}
+
+func RegisterAdminItem(admin *svr.Admin, c AdminItemController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+
+	admin.Get("/v3/items", maintainer, c.GetItems)
+	admin.Post("/v3/items", maintainer, c.CreateItem)
+	admin.Put("/v3/items/:itemId", maintainer, c.UpdateItem)
+	admin.Get("/v3/deleted-items", maintainer, c.GetDeletedItems)
+	admin.Delete("/v3/items/:itemId", maintainer, c.DeleteItem)
+	admin.Post("/v3/items/:itemId/restore", maintainer, c.RestoreItem)
+	admin.Put("/v3/items/:itemId/icon", maintainer, c.UploadItemIcon)
+	admin.Put("/v3/item-icons", maintainer, c.UpdateItemIcons)
+	admin.Get("/v3/item-values", maintainer, c.GetItemValues)
+	admin.Put("/v3/item-values/:preset", maintainer, c.ReplaceItemValues)
+	admin.Delete("/v3/item-values/:preset", maintainer, c.DeleteItemValues)
+}
+
+func (c *AdminItemController) GetItems(ctx *fiber.Ctx) error {
+	items, err := c.ItemService.GetItems(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(items)
+}
+
+func (c *AdminItemController) CreateItem(ctx *fiber.Ctx) error {
+	var request types.AdminCreateItemRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	item, err := c.ItemService.CreateItem(ctx.UserContext(), &request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(item)
+}
+
+func (c *AdminItemController) UpdateItem(ctx *fiber.Ctx) error {
+	var request types.AdminUpdateItemRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	item, err := c.ItemService.UpdateItem(ctx.UserContext(), ctx.Params("itemId"), &request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(item)
+}
+
+func (c *AdminItemController) GetDeletedItems(ctx *fiber.Ctx) error {
+	items, err := c.ItemService.GetDeletedItems(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(items)
+}
+
+func (c *AdminItemController) DeleteItem(ctx *fiber.Ctx) error {
+	if err := c.ItemService.DeleteItem(ctx.UserContext(), ctx.Params("itemId")); err != nil {
+		return err
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+func (c *AdminItemController) RestoreItem(ctx *fiber.Ctx) error {
+	item, err := c.ItemService.RestoreItem(ctx.UserContext(), ctx.Params("itemId"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(item)
+}
+
+// UploadItemIcon uploads the standalone icon of the item, sent as the icon field of a multipart form
+func (c *AdminItemController) UploadItemIcon(ctx *fiber.Ctx) error {
+	header, err := ctx.FormFile("icon")
+	if err != nil {
+		return pgerr.ErrInvalidReq.Msg("icon must be sent as the icon field of a multipart form")
+	}
+	if header.Size > service.ItemIconMaxSize {
+		return pgerr.ErrInvalidReq.Msg("icon must be at most %d bytes", service.ItemIconMaxSize)
+	}
+	file, err := header.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+
+	item, err := c.ItemIconService.UploadItemIcon(ctx.UserContext(), ctx.Params("itemId"), content)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(item)
+}
+
+func (c *AdminItemController) UpdateItemIcons(ctx *fiber.Ctx) error {
+	var request types.UpdateItemIconsRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	icons, err := c.ItemIconService.UpdateItemIcons(ctx.UserContext(), &request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(icons)
+}
+
+func (c *AdminItemController) GetItemValues(ctx *fiber.Ctx) error {
+	tables, err := c.ItemValueService.GetAllItemValueTables(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(tables)
+}
+
+func (c *AdminItemController) ReplaceItemValues(ctx *fiber.Ctx) error {
+	var request types.ReplaceItemValuesRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	table, err := c.ItemValueService.ReplaceItemValues(ctx.UserContext(), ctx.Params("preset"), &request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(table)
+}
+
+// DeleteItemValues deletes the preset in the server given by the server query, or in every server when omitted
+func (c *AdminItemController) DeleteItemValues(ctx *fiber.Ctx) error {
+	server := ctx.Query("server")
+	if server != "" {
+		if err := rekuest.ValidServer(ctx, server); err != nil {
+			return err
+		}
+	}
+
+	if err := c.ItemValueService.DeleteItemValues(ctx.UserContext(), ctx.Params("preset"), server); err != nil {
+		return err
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
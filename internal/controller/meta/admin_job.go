@@ -0,0 +1,146 @@
+package meta
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+const (
+	refreshJobStreamInterval = time.Second
+	// refreshJobStreamMaxDuration bounds the stream of a job, for which the client may reconnect to keep following it
+	refreshJobStreamMaxDuration = time.Hour
+	// refreshJobStreamStaleAfter ends the stream of a job making no progress, e.g. as its worker has gone away
+	refreshJobStreamStaleAfter = 10 * time.Minute
+)
+
+type AdminJobController struct {
+	fx.In
+
+	RefreshJobService *service.RefreshJob
+	Jobs              *service.Jobs
+}
+
+func RegisterAdminJob(admin *svr.Admin, c AdminJobController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+
+	admin.Get("/v3/jobs", maintainer, c.GetJobs)
+	admin.Post("/v3/jobs/refresh", maintainer, c.CreateRefreshJob)
+	admin.Get("/v3/jobs/:jobId", maintainer, c.GetJob)
+}
+
+// GetJobs lists the most recent background jobs, optionally filtered by kind and status
+func (c *AdminJobController) GetJobs(ctx *fiber.Ctx) error {
+	limit := ctx.QueryInt("limit", 50)
+	if limit <= 0 || limit > 500 {
+		return pgerr.ErrInvalidReq.Msg("limit must be between 1 and 500")
+	}
+
+	jobs, err := c.Jobs.GetJobs(ctx.UserContext(), ctx.Query("kind"), strings.ToUpper(ctx.Query("status")), limit)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(jobs)
+}
+
+func (c *AdminJobController) CreateRefreshJob(ctx *fiber.Ctx) error {
+	var request types.CreateRefreshJobRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	job, err := c.RefreshJobService.StartRefreshJob(ctx.UserContext(), &request)
+	if err != nil {
+		return err
+	}
+	return ctx.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// GetJob responds with the current progress of a job. When the client accepts text/event-stream,
+// the progress is instead streamed as server-sent events until the job finishes, the client disconnects, the server
+// shuts down, the job makes no progress for refreshJobStreamStaleAfter or the stream lasts refreshJobStreamMaxDuration.
+func (c *AdminJobController) GetJob(ctx *fiber.Ctx) error {
+	jobId := ctx.Params("jobId")
+	job, err := c.RefreshJobService.GetRefreshJob(ctx.UserContext(), jobId)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(ctx.Get(fiber.HeaderAccept), "text/event-stream") {
+		return ctx.JSON(job)
+	}
+
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set(fiber.HeaderConnection, "keep-alive")
+	// closed as the server shuts down; the request context itself is no longer usable once the handler returns
+	shutdown := ctx.Context().Done()
+	middlewares.SetBodyStreamWriter(ctx, func(w *bufio.Writer) {
+		streamCtx, cancel := context.WithTimeout(context.Background(), refreshJobStreamMaxDuration)
+		defer cancel()
+		go func() {
+			select {
+			case <-shutdown:
+				cancel()
+			case <-streamCtx.Done():
+			}
+		}()
+
+		ticker := time.NewTicker(refreshJobStreamInterval)
+		defer ticker.Stop()
+		progress, progressedAt := jobProgress(job), time.Now()
+		for {
+			b, err := json.Marshal(job)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "event: progress\ndata: %s\n\n", b)
+			if err := w.Flush(); err != nil {
+				// client disconnected
+				return
+			}
+			if job.Finished() {
+				return
+			}
+			if time.Since(progressedAt) > refreshJobStreamStaleAfter {
+				log.Warn().Str("jobId", jobId).Msg("refresh job made no progress, ending its progress stream")
+				return
+			}
+
+			select {
+			case <-streamCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			job, err = c.RefreshJobService.GetRefreshJob(streamCtx, jobId)
+			if err != nil {
+				log.Warn().Err(err).Str("jobId", jobId).Msg("failed to get refresh job progress")
+				return
+			}
+			if p := jobProgress(job); p != progress {
+				progress, progressedAt = p, time.Now()
+			}
+		}
+	})
+	return nil
+}
+
+// jobProgress summarizes the progress of a job, which is stale as long as it does not change
+func jobProgress(job *model.RefreshJob) string {
+	return fmt.Sprintf("%s:%d:%d", job.Status, job.Done, len(job.Errors))
+}
@@ -0,0 +1,53 @@
+package meta
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+type AdminMaintenanceController struct {
+	fx.In
+
+	MaintenanceService *service.Maintenance
+}
+
+func RegisterAdminMaintenance(admin *svr.Admin, c AdminMaintenanceController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+
+	admin.Get("/v3/maintenance", maintainer, c.GetMaintenance)
+	admin.Put("/v3/maintenance", maintainer, c.StartMaintenance)
+	admin.Delete("/v3/maintenance", maintainer, c.StopMaintenance)
+}
+
+func (c *AdminMaintenanceController) GetMaintenance(ctx *fiber.Ctx) error {
+	return ctx.JSON(c.MaintenanceService.Current())
+}
+
+// StartMaintenance switches the maintenance mode on. Other instances pick it up within the tunables refresh interval.
+func (c *AdminMaintenanceController) StartMaintenance(ctx *fiber.Ctx) error {
+	var request types.StartMaintenanceRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	maintenance, err := c.MaintenanceService.StartMaintenance(ctx.UserContext(), &request)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(maintenance)
+}
+
+func (c *AdminMaintenanceController) StopMaintenance(ctx *fiber.Ctx) error {
+	maintenance, err := c.MaintenanceService.StopMaintenance(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(maintenance)
+}
@@ -0,0 +1,70 @@
+package meta
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+)
+
+type AdminMetadataController struct {
+	fx.In
+
+	MetadataSnapshotService *service.MetadataSnapshot
+}
+
+func RegisterAdminMetadata(admin *svr.Admin, c AdminMetadataController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+
+	admin.Get("/v3/metadata/snapshots", maintainer, c.GetSnapshots)
+	admin.Post("/v3/metadata/snapshots", maintainer, c.CaptureSnapshot)
+	admin.Post("/v3/metadata/snapshots/:snapshotId/rollback", maintainer, c.Rollback)
+}
+
+// GetSnapshots lists the latest metadata snapshots, without their contents
+func (c *AdminMetadataController) GetSnapshots(ctx *fiber.Ctx) error {
+	limit := ctx.QueryInt("limit", 50)
+	if limit <= 0 || limit > 500 {
+		return pgerr.ErrInvalidReq.Msg("limit must be between 1 and 500")
+	}
+
+	snapshots, err := c.MetadataSnapshotService.GetSnapshots(ctx.UserContext(), limit)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(snapshots)
+}
+
+func (c *AdminMetadataController) CaptureSnapshot(ctx *fiber.Ctx) error {
+	snapshot, err := c.MetadataSnapshotService.Capture(ctx.UserContext(), "manual")
+	if err != nil {
+		return err
+	}
+	snapshot.Content = ""
+	return ctx.Status(fiber.StatusCreated).JSON(snapshot)
+}
+
+// Rollback restores the metadata to the given snapshot, and responds with the snapshot
+// recorded right before the rollback, which can be used to undo it.
+func (c *AdminMetadataController) Rollback(ctx *fiber.Ctx) error {
+	snapshotId, err := strconv.Atoi(ctx.Params("snapshotId"))
+	if err != nil {
+		return pgerr.ErrInvalidReq.Msg("invalid snapshotId")
+	}
+
+	previous, err := c.MetadataSnapshotService.Rollback(ctx.UserContext(), snapshotId)
+	if err != nil {
+		return err
+	}
+	previous.Content = ""
+	return ctx.JSON(fiber.Map{
+		"rolledBackTo":     snapshotId,
+		"previousSnapshot": previous,
+	})
+}
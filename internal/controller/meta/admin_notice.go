@@ -0,0 +1,82 @@
+package meta
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+type AdminNoticeController struct {
+	fx.In
+
+	NoticeService *service.Notice
+}
+
+func RegisterAdminNotice(admin *svr.Admin, c AdminNoticeController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+
+	admin.Get("/v3/notices", maintainer, c.GetNotices)
+	admin.Post("/v3/notices", maintainer, c.CreateNotice)
+	admin.Put("/v3/notices/:noticeId", maintainer, c.UpdateNotice)
+	admin.Delete("/v3/notices/:noticeId", maintainer, c.DeleteNotice)
+}
+
+func (c *AdminNoticeController) GetNotices(ctx *fiber.Ctx) error {
+	notices, err := c.NoticeService.GetAllNotices(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(notices)
+}
+
+func (c *AdminNoticeController) CreateNotice(ctx *fiber.Ctx) error {
+	var request types.SaveNoticeRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	notice, err := c.NoticeService.SaveNotice(ctx.UserContext(), 0, &request)
+	if err != nil {
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(notice)
+}
+
+func (c *AdminNoticeController) UpdateNotice(ctx *fiber.Ctx) error {
+	noticeId, err := strconv.Atoi(ctx.Params("noticeId"))
+	if err != nil || noticeId <= 0 {
+		return pgerr.ErrInvalidReq.Msg("invalid noticeId")
+	}
+
+	var request types.SaveNoticeRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	notice, err := c.NoticeService.SaveNotice(ctx.UserContext(), noticeId, &request)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(notice)
+}
+
+func (c *AdminNoticeController) DeleteNotice(ctx *fiber.Ctx) error {
+	noticeId, err := strconv.Atoi(ctx.Params("noticeId"))
+	if err != nil || noticeId <= 0 {
+		return pgerr.ErrInvalidReq.Msg("invalid noticeId")
+	}
+
+	if err := c.NoticeService.DeleteNotice(ctx.UserContext(), noticeId); err != nil {
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
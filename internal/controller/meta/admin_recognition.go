@@ -0,0 +1,91 @@
+package meta
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+type AdminRecognitionController struct {
+	fx.In
+
+	RecognitionStageHashService *service.RecognitionStageHash
+}
+
+func RegisterAdminRecognition(admin *svr.Admin, c AdminRecognitionController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+
+	admin.Get("/v3/recognition/stage-hashes", maintainer, c.GetRecognitionStageHashes)
+	admin.Post("/v3/recognition/stage-hashes", maintainer, c.CreateRecognitionStageHash)
+	admin.Put("/v3/recognition/stage-hashes/:hashId", maintainer, c.UpdateRecognitionStageHash)
+	admin.Delete("/v3/recognition/stage-hashes/:hashId", maintainer, c.DeleteRecognitionStageHash)
+}
+
+// GetRecognitionStageHashes returns the hashes of the server given by the server query, or of every server when
+// omitted
+func (c *AdminRecognitionController) GetRecognitionStageHashes(ctx *fiber.Ctx) error {
+	server := ctx.Query("server")
+	if server != "" {
+		if err := rekuest.ValidServer(ctx, server); err != nil {
+			return err
+		}
+	}
+
+	stageHashes, err := c.RecognitionStageHashService.GetRecognitionStageHashes(ctx.UserContext(), server)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(stageHashes)
+}
+
+func (c *AdminRecognitionController) CreateRecognitionStageHash(ctx *fiber.Ctx) error {
+	var request types.SaveRecognitionStageHashRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	stageHash, err := c.RecognitionStageHashService.SaveRecognitionStageHash(ctx.UserContext(), 0, &request)
+	if err != nil {
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(stageHash)
+}
+
+func (c *AdminRecognitionController) UpdateRecognitionStageHash(ctx *fiber.Ctx) error {
+	hashId, err := strconv.Atoi(ctx.Params("hashId"))
+	if err != nil || hashId <= 0 {
+		return pgerr.ErrInvalidReq.Msg("invalid hashId")
+	}
+
+	var request types.SaveRecognitionStageHashRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	stageHash, err := c.RecognitionStageHashService.SaveRecognitionStageHash(ctx.UserContext(), hashId, &request)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(stageHash)
+}
+
+func (c *AdminRecognitionController) DeleteRecognitionStageHash(ctx *fiber.Ctx) error {
+	hashId, err := strconv.Atoi(ctx.Params("hashId"))
+	if err != nil || hashId <= 0 {
+		return pgerr.ErrInvalidReq.Msg("invalid hashId")
+	}
+
+	if err := c.RecognitionStageHashService.DeleteRecognitionStageHash(ctx.UserContext(), hashId); err != nil {
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,71 @@
+package meta
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+type AdminReportReviewController struct {
+	fx.In
+
+	ReportReviewService *service.ReportReview
+}
+
+func RegisterAdminReportReview(admin *svr.Admin, c AdminReportReviewController) {
+	moderator := middlewares.RequireRoles(model.RoleModerator)
+
+	admin.Get("/v3/reports/samples", moderator, c.SampleReports)
+	admin.Get("/v3/reports/risk-model", moderator, c.GetRiskModel)
+	admin.Put("/v3/reports/:reportId/review", moderator, c.ReviewReport)
+}
+
+// SampleReports picks recent accepted reports of a stage not reviewed yet at random for inspection, riskiest first
+func (c *AdminReportReviewController) SampleReports(ctx *fiber.Ctx) error {
+	var query types.ReportSamplesQuery
+	if err := rekuest.ValidQuery(ctx, &query); err != nil {
+		return err
+	}
+
+	samples, err := c.ReportReviewService.SampleReports(ctx.UserContext(), &query)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(samples)
+}
+
+func (c *AdminReportReviewController) GetRiskModel(ctx *fiber.Ctx) error {
+	riskModel, err := c.ReportReviewService.GetRiskModel(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(riskModel)
+}
+
+// ReviewReport marks a report as verified or invalid, the latter of which excludes it from the aggregations
+func (c *AdminReportReviewController) ReviewReport(ctx *fiber.Ctx) error {
+	reportId, err := strconv.Atoi(ctx.Params("reportId"))
+	if err != nil || reportId <= 0 {
+		return pgerr.ErrInvalidReq.Msg("invalid reportId")
+	}
+
+	var request types.ReviewReportRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	result, err := c.ReportReviewService.ReviewReport(ctx.UserContext(), ctx.Locals(middlewares.LocalsAdminAccountKey).(*model.Account), reportId, &request)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(result)
+}
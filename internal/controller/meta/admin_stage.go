@@ -0,0 +1,68 @@
+package meta
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+type AdminStageController struct {
+	fx.In
+
+	StageService *service.Stage
+}
+
+func RegisterAdminStage(admin *svr.Admin, c AdminStageController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+
+	admin.Put("/v3/stages/:stageId/min-clear-time", maintainer, c.UpdateMinClearTime)
+	admin.Get("/v3/deleted-stages", maintainer, c.GetDeletedStages)
+	admin.Delete("/v3/stages/:stageId", maintainer, c.DeleteStage)
+	admin.Post("/v3/stages/:stageId/restore", maintainer, c.RestoreStage)
+}
+
+func (c *AdminStageController) UpdateMinClearTime(ctx *fiber.Ctx) error {
+	var request types.AdminUpdateStageMinClearTimeRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	stage, err := c.StageService.UpdateMinClearTime(ctx.UserContext(), ctx.Params("stageId"), request.MinClearTime)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(stage)
+}
+
+func (c *AdminStageController) GetDeletedStages(ctx *fiber.Ctx) error {
+	stages, err := c.StageService.GetDeletedStages(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(stages)
+}
+
+func (c *AdminStageController) DeleteStage(ctx *fiber.Ctx) error {
+	if err := c.StageService.DeleteStage(ctx.UserContext(), ctx.Params("stageId")); err != nil {
+		return err
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+func (c *AdminStageController) RestoreStage(ctx *fiber.Ctx) error {
+	stage, err := c.StageService.RestoreStage(ctx.UserContext(), ctx.Params("stageId"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(stage)
+}
@@ -0,0 +1,51 @@
+package meta
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+type AdminTunableController struct {
+	fx.In
+
+	Tunables *service.Tunables
+}
+
+func RegisterAdminTunable(admin *svr.Admin, c AdminTunableController) {
+	maintainer := middlewares.RequireRoles(model.RoleMaintainer)
+
+	admin.Get("/v3/tunables", maintainer, c.GetTunables)
+	admin.Put("/v3/tunables/:key", maintainer, c.SetTunable)
+	admin.Delete("/v3/tunables/:key", maintainer, c.ResetTunable)
+}
+
+func (c *AdminTunableController) GetTunables(ctx *fiber.Ctx) error {
+	return ctx.JSON(c.Tunables.GetTunables())
+}
+
+// SetTunable overrides a tunable. Other instances pick up the new value within the tunables refresh interval.
+func (c *AdminTunableController) SetTunable(ctx *fiber.Ctx) error {
+	var request types.SetTunableRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	if err := c.Tunables.SetTunable(ctx.UserContext(), ctx.Params("key"), request.Value); err != nil {
+		return err
+	}
+	return ctx.JSON(c.Tunables.GetTunables())
+}
+
+func (c *AdminTunableController) ResetTunable(ctx *fiber.Ctx) error {
+	if err := c.Tunables.ResetTunable(ctx.UserContext(), ctx.Params("key")); err != nil {
+		return err
+	}
+	return ctx.JSON(c.Tunables.GetTunables())
+}
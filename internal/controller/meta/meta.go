@@ -7,6 +7,7 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cache"
 	"go.uber.org/fx"
 
+	"exusiai.dev/backend-next/internal/model"
 	"exusiai.dev/backend-next/internal/pkg/bininfo"
 	"exusiai.dev/backend-next/internal/server/svr"
 	"exusiai.dev/backend-next/internal/service"
@@ -28,6 +29,14 @@ func RegisterMeta(meta *svr.Meta, c Meta) {
 		CacheHeader: constant.CacheHeader,
 	}), c.Health)
 
+	// probes for orchestrators: liveness only tells the process is serving, while readiness also requires
+	// the dependencies to be reachable and the caches to be warm
+	meta.Get("/health/live", c.Live)
+	meta.Get("/health/ready", cache.New(cache.Config{
+		Expiration:  time.Second,
+		CacheHeader: constant.CacheHeader,
+	}), c.Ready)
+
 	meta.Get("/ping", func(c *fiber.Ctx) error {
 		// only allow intranet access to prevent abuse
 		return c.SendString("pong")
@@ -41,6 +50,20 @@ func (c *Meta) BinInfo(ctx *fiber.Ctx) error {
 	})
 }
 
+func (c *Meta) Live(ctx *fiber.Ctx) error {
+	return ctx.JSON(fiber.Map{
+		"status": model.HealthStatusOK,
+	})
+}
+
+func (c *Meta) Ready(ctx *fiber.Ctx) error {
+	report := c.HealthService.Readiness(ctx.UserContext())
+	if report.Status != model.HealthStatusOK {
+		ctx.Status(fiber.StatusServiceUnavailable)
+	}
+	return ctx.JSON(report)
+}
+
 func (c *Meta) Health(ctx *fiber.Ctx) error {
 	if err := c.HealthService.Ping(ctx.UserContext()); err != nil {
 		return err
@@ -1,14 +1,51 @@
 package meta
 
 import (
+	"sync"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/swagger"
 
 	"exusiai.dev/backend-next/docs"
 	"exusiai.dev/backend-next/internal/pkg/bininfo"
+	"exusiai.dev/backend-next/internal/pkg/openapi"
+	"exusiai.dev/backend-next/internal/server/svr"
 )
 
+const v3SwaggerDescription = "The Penguin Statistics v3 API. The v3 API is in alpha: every request shall carry " +
+	"the `Accept: application/vnd.penguin.v3+json` header to explicitly opt-in to it."
+
 func RegisterSwagger(app *fiber.App) {
 	docs.SwaggerInfo.Version = bininfo.Version
 	app.Get("/swagger/*", swagger.HandlerDefault) // default
 }
+
+// RegisterV3Swagger serves the OpenAPI 3 document of the v3 API, converted from the generated
+// Swagger 2.0 document, along with a bundled Swagger UI.
+// Both are exempted from the Accept header requirement of the v3 API.
+func RegisterV3Swagger(v3 *svr.V3) {
+	var (
+		once sync.Once
+		doc  []byte
+		err  error
+	)
+	v3.Get("/swagger.json", func(ctx *fiber.Ctx) error {
+		once.Do(func() {
+			docs.SwaggerInfo.Version = bininfo.Version
+			doc, err = openapi.Convert([]byte(docs.SwaggerInfo.ReadDoc()), openapi.ConvertOptions{
+				PathPrefix:  svr.V3Prefix,
+				MediaType:   svr.V3MediaType,
+				Description: v3SwaggerDescription,
+			})
+		})
+		if err != nil {
+			return err
+		}
+		ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+		return ctx.Send(doc)
+	})
+	v3.Get("/swagger/*", swagger.New(swagger.Config{
+		Title: "Penguin Statistics v3 API",
+		URL:   svr.V3Prefix + "/swagger.json",
+	}))
+}
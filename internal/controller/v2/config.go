@@ -22,7 +22,7 @@ func RegisterFrontendConfig(v2 *svr.V2, c FrontendConfig) {
 //	@Tags		FrontendConfig
 //	@Produce	json
 //	@Success	200
-//	@Failure	500	{object}	pgerr.PenguinError	"An unexpected error occurred"
+//	@Failure	500	{object}	pgerr.Problem	"An unexpected error occurred"
 //	@Router		/PenguinStats/api/v2/config [GET]
 func (c *FrontendConfig) GetFrontendConfig(ctx *fiber.Ctx) error {
 	formula, err := c.FrontendConfigService.GetFrontendConfig(ctx.UserContext())
@@ -27,7 +27,7 @@ func RegisterEventPeriod(v2 *svr.V2, c EventPeriod) {
 //	@Tags		EventPeriod
 //	@Produce	json
 //	@Success	200	{array}		modelv2.Activity{label_i18n=model.I18nString,existence=model.Existence}
-//	@Failure	500	{object}	pgerr.PenguinError	"An unexpected error occurred"
+//	@Failure	500	{object}	pgerr.Problem	"An unexpected error occurred"
 //	@Router		/PenguinStats/api/v2/period [GET]
 func (c *EventPeriod) GetEventPeriods(ctx *fiber.Ctx) (err error) {
 	var activities []*modelv2.Activity
@@ -22,7 +22,7 @@ func RegisterFormula(v2 *svr.V2, c Formula) {
 //	@Tags		Formula
 //	@Produce	json
 //	@Success	200
-//	@Failure	500	{object}	pgerr.PenguinError	"An unexpected error occurred"
+//	@Failure	500	{object}	pgerr.Problem	"An unexpected error occurred"
 //	@Router		/PenguinStats/api/v2/formula [GET]
 func (c *Formula) GetFormula(ctx *fiber.Ctx) error {
 	formula, err := c.FormulaService.GetFormula(ctx.UserContext())
@@ -30,7 +30,7 @@ func RegisterItem(v2 *svr.V2, c Item) {
 //	@Tags		Item
 //	@Produce	json
 //	@Success	200	{array}		modelv2.Item{name_i18n=model.I18nString,existence=model.Existence}
-//	@Failure	500	{object}	pgerr.PenguinError	"An unexpected error occurred"
+//	@Failure	500	{object}	pgerr.Problem	"An unexpected error occurred"
 //	@Router		/PenguinStats/api/v2/items [GET]
 func (c *Item) GetItems(ctx *fiber.Ctx) error {
 	items, err := c.ItemService.GetShimItems(ctx.UserContext())
@@ -42,6 +42,10 @@ func (c *Item) GetItems(ctx *fiber.Ctx) error {
 		lastModifiedTime = time.Now()
 	}
 	cachectrl.OptIn(ctx, lastModifiedTime)
+	var contentHash string
+	if err := cache.ContentHash.Get("[shimItems]", &contentHash); err == nil && cachectrl.ETag(ctx, contentHash) {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
 	return ctx.JSON(items)
 }
 
@@ -50,8 +54,8 @@ func (c *Item) GetItems(ctx *fiber.Ctx) error {
 //	@Produce	json
 //	@Param		itemId	path		string	true	"Item ID"
 //	@Success	200		{object}	modelv2.Item{name_i18n=model.I18nString,existence=model.Existence}
-//	@Failure	400		{object}	pgerr.PenguinError	"Invalid or missing itemId. Notice that this shall be the **string ID** of the item, instead of the internally used numerical ID of the item."
-//	@Failure	500		{object}	pgerr.PenguinError	"An unexpected error occurred"
+//	@Failure	400		{object}	pgerr.Problem	"Invalid or missing itemId. Notice that this shall be the **string ID** of the item, instead of the internally used numerical ID of the item."
+//	@Failure	500		{object}	pgerr.Problem	"An unexpected error occurred"
 //	@Router		/PenguinStats/api/v2/items/{itemId} [GET]
 func (c *Item) GetItemByArkId(ctx *fiber.Ctx) error {
 	itemId := ctx.Params("itemId")
@@ -26,7 +26,7 @@ func RegisterNotice(v2 *svr.V2, c Notice) {
 //	@Tags		Notice
 //	@Produce	json
 //	@Success	200	{array}		model.Notice
-//	@Failure	500	{object}	pgerr.PenguinError	"An unexpected error occurred"
+//	@Failure	500	{object}	pgerr.Problem	"An unexpected error occurred"
 //	@Router		/PenguinStats/api/v2/notice [GET]
 func (c *Notice) GetNotices(ctx *fiber.Ctx) error {
 	notices, err := c.NoticeService.GetNotices(ctx.UserContext())
@@ -13,6 +13,7 @@ import (
 	modelv2 "exusiai.dev/backend-next/internal/model/v2"
 	"exusiai.dev/backend-next/internal/pkg/cachectrl"
 	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgid"
 	"exusiai.dev/backend-next/internal/server/svr"
 	"exusiai.dev/backend-next/internal/service"
 )
@@ -44,7 +45,7 @@ func (c *Private) GetDropMatrix(ctx *fiber.Ctx) error {
 
 	accountId := null.NewInt(0, false)
 	if isPersonal {
-		account, err := c.AccountService.GetAccountFromRequest(ctx)
+		account, err := c.AccountService.GetAccountFromPenguinId(ctx.UserContext(), pgid.Extract(ctx))
 		if err != nil {
 			return err
 		}
@@ -64,6 +65,7 @@ func (c *Private) GetDropMatrix(ctx *fiber.Ctx) error {
 			lastModifiedTime = time.Now()
 		}
 		cachectrl.OptIn(ctx, lastModifiedTime)
+		cachectrl.Freshness(ctx, lastModifiedTime, c.DropMatrixService.GlobalCacheTTL(server))
 	}
 
 	return ctx.JSON(shimResult)
@@ -77,7 +79,7 @@ func (c *Private) GetPatternMatrix(ctx *fiber.Ctx) error {
 
 	accountId := null.NewInt(0, false)
 	if isPersonal {
-		account, err := c.AccountService.GetAccountFromRequest(ctx)
+		account, err := c.AccountService.GetAccountFromPenguinId(ctx.UserContext(), pgid.Extract(ctx))
 		if err != nil {
 			return err
 		}
@@ -97,6 +99,7 @@ func (c *Private) GetPatternMatrix(ctx *fiber.Ctx) error {
 			lastModifiedTime = time.Now()
 		}
 		cachectrl.OptIn(ctx, lastModifiedTime)
+		cachectrl.Freshness(ctx, lastModifiedTime, c.PatternMatrixService.GlobalCacheTTL(server))
 	}
 
 	return ctx.JSON(shimResult)
@@ -114,6 +117,7 @@ func (c *Private) GetTrends(ctx *fiber.Ctx) error {
 		lastModifiedTime = time.Now()
 	}
 	cachectrl.OptIn(ctx, lastModifiedTime)
+	cachectrl.Freshness(ctx, lastModifiedTime, c.TrendService.GlobalCacheTTL(server))
 
 	return ctx.JSON(shimResult)
 }
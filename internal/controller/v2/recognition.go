@@ -9,6 +9,7 @@ import (
 
 	"exusiai.dev/backend-next/internal/model"
 	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/pkg/pgid"
 	"exusiai.dev/backend-next/internal/repo"
 	"exusiai.dev/backend-next/internal/server/svr"
 	"exusiai.dev/backend-next/internal/service"
@@ -61,7 +62,7 @@ func (c *Recognition) InitDefectReport(ctx *fiber.Ctx) error {
 	}
 
 	var accountId int
-	account, _ := c.AccountService.GetAccountFromRequest(ctx)
+	account, _ := c.AccountService.GetAccountFromPenguinId(ctx.UserContext(), pgid.Extract(ctx))
 	if account != nil {
 		accountId = account.AccountID
 	}
@@ -79,7 +80,7 @@ func (c *Recognition) InitDefectReport(ctx *fiber.Ctx) error {
 		Environment:       json.RawMessage(environment),
 	}
 
-	err = c.RecognitionDefectRepo.CreateDefectReportDraft(ctx.Context(), &defect)
+	err = c.RecognitionDefectRepo.CreateDefectReportDraft(ctx.UserContext(), &defect)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to create defect report draft")
 		return err
@@ -99,17 +100,17 @@ func (c *Recognition) InitDefectReport(ctx *fiber.Ctx) error {
 
 func (c *Recognition) RetrieveDefectReportImageCallback(ctx *fiber.Ctx) error {
 	defectId := ctx.Params("defectId")
-	if defectId == "" {
-		return pgerr.ErrInvalidReq.Msg("defectId is required")
+	if err := rekuest.ValidVar(ctx, "defectId", defectId, "required"); err != nil {
+		return err
 	}
 
-	path, err := c.UpyunService.VerifyImageUploadCallback(ctx)
+	path, err := c.UpyunService.VerifyImageUploadCallback(ctx.Path(), ctx.Get(fiber.HeaderAuthorization), ctx.Get(fiber.HeaderDate), ctx.Body())
 	if err != nil {
 		log.Error().Err(err).Msg("failed to verify image upload callback")
-		return pgerr.ErrInvalidReq.Msg("failed to verify image upload callback")
+		return rekuest.Violation("", rekuest.ViolationUnverified, "failed to verify image upload callback")
 	}
 
-	err = c.RecognitionDefectRepo.FinalizeDefectReport(ctx.Context(), defectId, c.UpyunService.MarshalImageURI(path))
+	err = c.RecognitionDefectRepo.FinalizeDefectReport(ctx.UserContext(), defectId, c.UpyunService.MarshalImageURI(path))
 	if err != nil {
 		log.Error().Err(err).Msg("failed to finalize defect report")
 		return pgerr.ErrInternalError.Msg("failed to finalize defect report")
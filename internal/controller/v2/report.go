@@ -17,10 +17,10 @@ import (
 	"exusiai.dev/backend-next/internal/pkg/fiberstore"
 	"exusiai.dev/backend-next/internal/pkg/flog"
 	"exusiai.dev/backend-next/internal/pkg/middlewares"
-	"exusiai.dev/backend-next/internal/pkg/pgerr"
 	"exusiai.dev/backend-next/internal/pkg/pgid"
 	"exusiai.dev/backend-next/internal/server/svr"
 	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util"
 	"exusiai.dev/backend-next/internal/util/rekuest"
 )
 
@@ -32,10 +32,18 @@ type Report struct {
 	Crypto         *crypto.Crypto
 	ReportService  *service.Report
 	AccountService *service.Account
+
+	SubmissionPolicy    *service.SubmissionPolicy
+	SubmissionChallenge *service.SubmissionChallenge
+	Tunables            *service.Tunables
 }
 
 func RegisterReport(v2 *svr.V2, c Report) {
-	v2.Post("/report", middlewares.Idempotency(&middlewares.IdempotencyConfig{
+	submissionPolicy := middlewares.SubmissionPolicy(c.SubmissionPolicy.Flag, func() int {
+		return c.Tunables.Int(service.TunableFlaggedReportRateLimit)
+	})
+
+	v2.Post("/report", submissionPolicy, middlewares.Idempotency(&middlewares.IdempotencyConfig{
 		Lifetime:  constant.ReportIdempotencyLifetime,
 		KeyHeader: constant.IdempotencyKeyHeader,
 		KeepResponseHeaders: []string{
@@ -49,28 +57,45 @@ func RegisterReport(v2 *svr.V2, c Report) {
 		RedSync: c.RedSync,
 	}), middlewares.InjectValidBody[types.SingularReportRequest](), c.MiddlewareGetOrCreateAccount, c.SingularReport)
 	v2.Post("/report/recall", middlewares.InjectValidBody[types.SingularReportRecallRequest](), c.RecallSingularReport)
-	v2.Post("/report/recognition", c.MiddlewareGetOrCreateAccount, c.RecognitionReport)
+	v2.Post("/report/recognition", submissionPolicy, c.MiddlewareGetOrCreateAccount, c.RecognitionReport)
 }
 
 func (c *Report) MiddlewareGetOrCreateAccount(ctx *fiber.Ctx) error {
 	var accountId int
+	var created bool
 
-	account, err := c.AccountService.GetAccountFromRequest(ctx)
+	account, err := c.AccountService.GetAccountFromPenguinId(ctx.UserContext(), pgid.Extract(ctx))
 	if err != nil {
 		createdAccount, err := c.AccountService.CreateAccountWithRandomPenguinId(ctx.UserContext())
 		if err != nil {
 			return err
 		}
 		accountId = createdAccount.AccountID
+		created = true
 		pgid.Inject(ctx, createdAccount.PenguinID)
 	} else {
 		accountId = account.AccountID
 	}
 
+	if err := c.SubmissionChallenge.VerifySubmission(ctx.UserContext(), accountId, created, ctx.Get(middlewares.HeaderChallengeSolution)); err != nil {
+		ctx.Set(middlewares.HeaderChallenge, "required")
+		return err
+	}
+
 	ctx.Locals(constant.LocalsAccountIDKey, accountId)
 	return ctx.Next()
 }
 
+// reportOrigin collects the specifics of the request the report service queues reports with.
+func reportOrigin(ctx *fiber.Ctx) *types.ReportOrigin {
+	accountId, _ := ctx.Locals(constant.LocalsAccountIDKey).(int)
+	return &types.ReportOrigin{
+		AccountID: accountId,
+		IP:        util.ExtractIP(ctx),
+		RequestID: middlewares.RequestIDFrom(ctx),
+	}
+}
+
 //	@Summary		Submit a Drop Report
 //	@Description	Submit a Drop Report. You can use the `reportHash` in the response to recall the report in 24 hours after it has been submitted.
 //	@Tags			Report
@@ -78,14 +103,16 @@ func (c *Report) MiddlewareGetOrCreateAccount(ctx *fiber.Ctx) error {
 //	@Produce		json
 //	@Param			report	body		types.SingularReportRequest	true	"Report request"
 //	@Success		200		{object}	modelv2.ReportResponse		"Report has been successfully submitted"
-//	@Failure		400		{object}	pgerr.PenguinError			"Invalid request"
-//	@Failure		500		{object}	pgerr.PenguinError			"An unexpected error occurred"
+//	@Failure		400		{object}	pgerr.Problem			"Invalid request"
+//	@Failure		426		{object}	pgerr.Problem			"The version of the client is no longer accepted and has to be upgraded"
+//	@Failure		428		{object}	pgerr.Problem			"The account has never submitted a report and a solved challenge is required, see /api/v3alpha/report/challenge"
+//	@Failure		500		{object}	pgerr.Problem			"An unexpected error occurred"
 //	@Security		PenguinIDAuth
 //	@Router			/PenguinStats/api/v2/report [POST]
 func (c *Report) SingularReport(ctx *fiber.Ctx) error {
 	req := ctx.Locals("body").(types.SingularReportRequest)
 
-	taskId, err := c.ReportService.PreprocessAndQueueSingularReport(ctx, &req)
+	taskId, err := c.ReportService.PreprocessAndQueueSingularReport(ctx.UserContext(), reportOrigin(ctx), &req)
 	if err != nil {
 		return err
 	}
@@ -99,8 +126,8 @@ func (c *Report) SingularReport(ctx *fiber.Ctx) error {
 //	@Produce		json
 //	@Param			report	body	types.SingularReportRecallRequest	true	"Report Recall request"
 //	@Success		204		"Report has been successfully recalled"
-//	@Failure		400		{object}	pgerr.PenguinError	"`reportHash` is missing, invalid, or already been recalled."
-//	@Failure		500		{object}	pgerr.PenguinError	"An unexpected error occurred"
+//	@Failure		400		{object}	pgerr.Problem	"`reportHash` is missing, invalid, or already been recalled."
+//	@Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
 //	@Router			/PenguinStats/api/v2/report/recall [POST]
 func (c *Report) RecallSingularReport(ctx *fiber.Ctx) error {
 	req := ctx.Locals("body").(types.SingularReportRecallRequest)
@@ -123,8 +150,8 @@ func (c *Report) RecallSingularReport(ctx *fiber.Ctx) error {
 //	@Produce		json
 //	@Param			report	body		string								true	"Recognition Report Request"
 //	@Success		200		{object}	modelv2.RecognitionReportResponse	"Report has been successfully submitted for queue processing"
-//	@Failure		400		{object}	pgerr.PenguinError					"Invalid request"
-//	@Failure		500		{object}	pgerr.PenguinError					"An unexpected error occurred"
+//	@Failure		400		{object}	pgerr.Problem					"Invalid request"
+//	@Failure		500		{object}	pgerr.Problem					"An unexpected error occurred"
 //	@Security		PenguinIDAuth
 //	@Router			/PenguinStats/api/v2/report/recognition [POST]
 func (c *Report) RecognitionReport(ctx *fiber.Ctx) error {
@@ -136,7 +163,7 @@ func (c *Report) RecognitionReport(ctx *fiber.Ctx) error {
 		flog.WarnFrom(ctx, "report.recognition.request.invalid.segments").
 			Err(err).
 			Msg("failed to decrypt recognition request")
-		return pgerr.ErrInvalidReq
+		return rekuest.Violation("", rekuest.ViolationMalformed, "the body shall be the key and the content separated by a colon")
 	}
 
 	privateKey := segments[0]
@@ -147,7 +174,7 @@ func (c *Report) RecognitionReport(ctx *fiber.Ctx) error {
 		flog.WarnFrom(ctx, "report.recognition.request.invalid.decryption").
 			Err(err).
 			Msg("failed to decrypt recognition request")
-		return pgerr.ErrInvalidReq
+		return rekuest.Violation("", rekuest.ViolationUnverified, "the body fails to be decrypted")
 	}
 
 	var request types.BatchReportRequest
@@ -155,7 +182,7 @@ func (c *Report) RecognitionReport(ctx *fiber.Ctx) error {
 		flog.WarnFrom(ctx, "report.recognition.request.invalid.json").
 			Err(err).
 			Msg("failed to unmarshal recognition request")
-		return pgerr.ErrInvalidReq
+		return rekuest.Violation("", rekuest.ViolationMalformed, "invalid body: "+err.Error())
 	}
 
 	if e := log.Trace(); e.Enabled() {
@@ -163,7 +190,7 @@ func (c *Report) RecognitionReport(ctx *fiber.Ctx) error {
 			Msg("received recognition report request")
 	}
 
-	taskId, err := c.ReportService.PreprocessAndQueueBatchReport(ctx, &request)
+	taskId, err := c.ReportService.PreprocessAndQueueBatchReport(ctx.UserContext(), reportOrigin(ctx), &request)
 	if err != nil {
 		return err
 	}
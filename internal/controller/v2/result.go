@@ -1,7 +1,12 @@
 package v2
 
 import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"exusiai.dev/gommon/constant"
@@ -10,6 +15,7 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/gofiber/fiber/v2/utils"
 	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
 	"go.uber.org/fx"
 	"gopkg.in/guregu/null.v3"
 
@@ -18,25 +24,62 @@ import (
 	"exusiai.dev/backend-next/internal/model/types"
 	modelv2 "exusiai.dev/backend-next/internal/model/v2"
 	"exusiai.dev/backend-next/internal/pkg/cachectrl"
+	"exusiai.dev/backend-next/internal/pkg/coalesce"
+	"exusiai.dev/backend-next/internal/pkg/jsonstream"
 	"exusiai.dev/backend-next/internal/pkg/middlewares"
 	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/pkg/pgid"
+	"exusiai.dev/backend-next/internal/pkg/tabular"
 	"exusiai.dev/backend-next/internal/server/svr"
 	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util"
+	"exusiai.dev/backend-next/internal/util/i18n"
 	"exusiai.dev/backend-next/internal/util/rekuest"
 )
 
+// HeaderPurgedBefore is set on the personal results which leave out the reports purged from the database,
+// to the time in unix milliseconds before which the reports have been moved to the archive
+const HeaderPurgedBefore = "X-Penguin-Purged-Before"
+
+// HeaderSnapshotRecordedAt is set on the matrices queried as of a past date, to the time in unix milliseconds the
+// snapshot responded with has been recorded at
+const HeaderSnapshotRecordedAt = "X-Penguin-Snapshot-Recorded-At"
+
 // ErrIntervalLengthTooSmall is returned when the interval length is invalid
-var ErrIntervalLengthTooSmall = pgerr.ErrInvalidReq.Msg("interval length must be greater than 1 hour")
+var ErrIntervalLengthTooSmall = rekuest.Violation("interval", rekuest.ViolationRange, "interval length must be greater than 1 hour")
+
+// the concurrent heavy queries with identical normalized parameters, of which the account is one for the personal
+// ones, share one computation. The global matrices without filters are already computed once per cache fill.
+var (
+	dropMatrixQueries    coalesce.Group[*modelv2.DropMatrixQueryResult]
+	patternMatrixQueries coalesce.Group[*modelv2.PatternMatrixQueryResult]
+)
+
+// recentRangeDays maps the accepted values of `range` to the number of days of their rolling windows
+var recentRangeDays = map[string]int{
+	"7d":  7,
+	"30d": 30,
+	"90d": 90,
+}
 
 type Result struct {
 	fx.In
 
 	DropMatrixService    *service.DropMatrix
+	DropMatrixDelta      *service.DropMatrixDelta
 	PatternMatrixService *service.PatternMatrix
 	TrendService         *service.Trend
 	AccountService       *service.Account
 	ItemService          *service.Item
 	StageService         *service.Stage
+	ExportService        *service.Export
+	LocalizationService  *service.Localization
+	Tunables             *service.Tunables
+	CacheTTLOverrides    *service.CacheTTLOverrides
+	FeatureFlags         *service.FeatureFlags
+	RetentionService     *service.Retention
+	QueryCostService     *service.QueryCost
+	AdvancedQueryService *service.AdvancedQuery
 }
 
 func RegisterResult(v2 *svr.V2, c Result) {
@@ -46,21 +89,23 @@ func RegisterResult(v2 *svr.V2, c Result) {
 	// with such behaviors very eagerly, causing a relatively high load on the database.
 	log.Info().Msg("enabling fiber-level cache & limiter for requests under /result group which contain itemFilter or stageFilter query params.")
 
-	group.Use(limiter.New(limiter.Config{
+	group.Use(middlewares.TunableLimiter(limiter.Config{
 		Next: func(c *fiber.Ctx) bool {
+			// API key holders are limited by their own quotas instead
+			if middlewares.APIKeyFrom(c) != nil {
+				return true
+			}
 			if c.Query("itemFilter") != "" || c.Query("stageFilter") != "" {
 				return false
 			}
 			return true
 		},
 		LimitReached: func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"code":    "TOO_MANY_REQUESTS",
-				"message": "Your client is sending requests too frequently. The Penguin Stats result matrix are updated periodically and should not be requested too frequently.",
-			})
+			return pgerr.ErrTooManyRequests.Msg("Your client is sending requests too frequently. The Penguin Stats result matrix are updated periodically and should not be requested too frequently.")
 		},
-		Max:        300,
 		Expiration: time.Minute * 5,
+	}, func() int {
+		return c.Tunables.Int(service.TunableResultRateLimit)
 	}))
 
 	group.Use(cachemiddleware.New(cachemiddleware.Config{
@@ -73,24 +118,35 @@ func RegisterResult(v2 *svr.V2, c Result) {
 		},
 		CacheHeader:  constant.CacheHeader,
 		CacheControl: true,
-		Expiration:   time.Minute * 5,
-		KeyGenerator: func(c *fiber.Ctx) string {
-			return utils.CopyString(c.OriginalURL())
+		ExpirationGenerator: func(ctx *fiber.Ctx, _ *cachemiddleware.Config) time.Duration {
+			// the results of the stages of which the cache TTL is overridden, e.g. of an event being launched, are
+			// cached for the TTL overridden should it be shorter
+			var arkStageIds []string
+			if stageFilter := ctx.Query("stageFilter"); stageFilter != "" {
+				arkStageIds = strings.Split(stageFilter, ",")
+			}
+			return c.CacheTTLOverrides.TTLByArkStageIds(ctx.UserContext(), ctx.Query("server", "CN"), arkStageIds, c.Tunables.Duration(service.TunableResultCacheTTL))
+		},
+		KeyGenerator: func(ctx *fiber.Ctx) string {
+			params, err := c.parseResultParams(ctx)
+			if err != nil {
+				// let the handler respond with the error
+				return utils.CopyString(ctx.OriginalURL())
+			}
+			return utils.CopyString(ctx.OriginalURL()) + constant.CacheSep + params.cacheKey()
 		},
 	}))
 
 	group.Get("/matrix", middlewares.ValidateServerAsQuery, c.GetDropMatrix)
 	group.Get("/pattern", middlewares.ValidateServerAsQuery, c.GetPatternMatrix)
 	group.Get("/trends", middlewares.ValidateServerAsQuery, c.GetTrends)
-	group.Post("/advanced", limiter.New(limiter.Config{
+	group.Post("/advanced", middlewares.TunableLimiter(limiter.Config{
 		LimitReached: func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"code":    "TOO_MANY_REQUESTS",
-				"message": "Your client is sending requests too frequently. The Penguin Stats advanced query API is limited to 30 requests per 5 minutes.",
-			})
+			return pgerr.ErrTooManyRequests.Msg("Your client is sending requests too frequently. The Penguin Stats advanced query API is limited to 30 requests per 5 minutes.")
 		},
-		Max:        30,
 		Expiration: time.Minute * 5,
+	}, func() int {
+		return c.Tunables.Int(service.TunableAdvancedQueryRateLimit)
 	}), c.AdvancedQuery)
 }
 
@@ -100,11 +156,23 @@ func RegisterResult(v2 *svr.V2, c Result) {
 //	@Param		server				query		string							true	"Server; default to CN"	Enums(CN, US, JP, KR)
 //	@Param		is_personal			query		bool							false	"Whether to query for personal drop matrix or not. If `is_personal` equals to `true`, a valid PenguinID would be required to be provided (PenguinIDAuth)"
 //	@Param		show_closed_zones	query		bool							false	"Whether to show closed stages or not"
-//	@Param		category			query		string							false	"Category; default to all"						Enums(all, automated, manual)
-//	@Param		stageFilter			query		[]string						false	"Comma separated list of stage IDs to filter"	collectionFormat(csv)
-//	@Param		itemFilter			query		[]string						false	"Comma separated list of item IDs to filter"	collectionFormat(csv)
+//	@Param		category			query		string							false	"Category; default to all"																																Enums(all, automated, manual)
+//	@Param		stageFilter			query		[]string						false	"Comma separated list of stage IDs to filter"																											collectionFormat(csv)
+//	@Param		itemFilter			query		[]string						false	"Comma separated list of item IDs to filter"																											collectionFormat(csv)
+//	@Param		format				query		string							false	"Respond with a spreadsheet instead of JSON"																											Enums(csv, xlsx)
+//	@Param		lang				query		string							false	"Language of the stage codes and item names; default to the best match of the Accept-Language header, or zh. Implies `localized` for JSON responses"	Enums(zh, en, ja, ko)
+//	@Param		localized			query		bool							false	"Embed the stage and item names in the language selected by `lang` or the Accept-Language header into the JSON response, as `stageName` and `itemName` fields"
+//	@Param		fields				query		string							false	"Comma separated list of the fields of the matrix elements to respond with, e.g. `times,quantity`; `stageId` and `itemId` are always included"
+//	@Param		as_of				query		string							false	"Respond with the global matrix as it was at the end of this date in the server, e.g. `2023-01-31`, including closed stages. Not available for personal results or categories other than all"
+//	@Param		range				query		string							false	"Respond with the matrix of the reports of the last 7, 30 or 90 days, up to the start of the current hour, rather than of the time ranges of the stages. Not available along with `as_of`"	Enums(7d, 30d, 90d)
 //	@Success	200					{object}	modelv2.DropMatrixQueryResult	"Drop Matrix response"
-//	@Failure	500					{object}	pgerr.PenguinError				"An unexpected error occurred"
+//	@Header		200					{integer}	X-Penguin-Purged-Before			"Set on personal results that leave out the reports moved to the archive before this time, in unix milliseconds. Those may be requested as an export instead"
+//	@Header		200					{integer}	X-Penguin-Snapshot-Recorded-At	"Set on results queried with `as_of`, to the time the snapshot has been recorded at, in unix milliseconds"
+//	@Header		200					{integer}	X-Penguin-Cache-Age				"Set on the cached global results, to the seconds elapsed since the result has been calculated"
+//	@Header		200					{integer}	X-Penguin-Refresh-After			"Set on the cached global results, to the seconds after which the result is due to be calculated again"
+//	@Failure	400					{object}	pgerr.Problem				"Invalid request"
+//	@Failure	404					{object}	pgerr.Problem				"No snapshot has been recorded as of `as_of`"
+//	@Failure	500					{object}	pgerr.Problem				"An unexpected error occurred"
 //	@Security	PenguinIDAuth
 //	@Router		/PenguinStats/api/v2/result/matrix [GET]
 func (c *Result) GetDropMatrix(ctx *fiber.Ctx) error {
@@ -127,32 +195,104 @@ func (c *Result) GetDropMatrix(ctx *fiber.Ctx) error {
 	}
 	stageFilterStr := ctx.Query("stageFilter")
 	itemFilterStr := ctx.Query("itemFilter")
+	params, err := c.parseResultParams(ctx)
+	if err != nil {
+		return err
+	}
+	recentDays, err := parseRecentRange(ctx)
+	if err != nil {
+		return err
+	}
 
-	accountId := null.NewInt(0, false)
-	if isPersonal {
-		account, err := c.AccountService.GetAccountFromRequest(ctx)
+	var shimQueryResult *modelv2.DropMatrixQueryResult
+	if asOfStr := ctx.Query("as_of"); asOfStr != "" {
+		if recentDays != 0 {
+			return rekuest.Violation("as_of", rekuest.ViolationConflict, "`range` and `as_of` cannot be used together")
+		}
+		// the snapshots are of the global matrix of all categories, including the closed zones
+		if isPersonal || sourceCategory != constant.SourceCategoryAll {
+			return rekuest.Violation("as_of", rekuest.ViolationUnsupported, "`as_of` is only available for the global matrix of all categories")
+		}
+		asOf, err := time.ParseInLocation("2006-01-02", asOfStr, constant.LocMap[server])
+		if err != nil {
+			return rekuest.Violation("as_of", rekuest.ViolationMalformed, "`as_of` must be a date in the format of 2006-01-02")
+		}
+
+		var recordedAt time.Time
+		shimQueryResult, recordedAt, err = c.DropMatrixDelta.GetDropMatrixAsOf(ctx.UserContext(), server, asOf, stageFilterStr, itemFilterStr)
 		if err != nil {
 			return err
 		}
-		accountId.Int64 = int64(account.AccountID)
-		accountId.Valid = true
+		showClosedZones = true
+		ctx.Set(HeaderSnapshotRecordedAt, strconv.FormatInt(recordedAt.UnixMilli(), 10))
+	} else {
+		accountId := null.NewInt(0, false)
+		if isPersonal {
+			account, err := c.AccountService.GetAccountFromPenguinId(ctx.UserContext(), pgid.Extract(ctx))
+			if err != nil {
+				return err
+			}
+			accountId.Int64 = int64(account.AccountID)
+			accountId.Valid = true
+		}
+
+		key := coalesce.Key("matrix", server, strconv.FormatBool(showClosedZones), sourceCategory,
+			normalizeFilter(stageFilterStr), normalizeFilter(itemFilterStr), accountKey(accountId), strconv.Itoa(recentDays))
+		shimQueryResult, err = dropMatrixQueries.Do(key, func() (*modelv2.DropMatrixQueryResult, error) {
+			if recentDays != 0 {
+				return c.DropMatrixService.GetShimRecentDropMatrix(ctx.UserContext(), server, recentDays, showClosedZones, stageFilterStr, itemFilterStr, accountId, sourceCategory)
+			}
+			return c.DropMatrixService.GetShimDropMatrix(ctx.UserContext(), server, showClosedZones, stageFilterStr, itemFilterStr, accountId, sourceCategory)
+		})
+		if err != nil {
+			return err
+		}
+		c.hintPurgedReports(ctx, accountId, recentStart(recentDays))
+
+		useCache := !accountId.Valid && stageFilterStr == "" && itemFilterStr == "" && recentDays == 0
+		if useCache {
+			key := server + constant.CacheSep + strconv.FormatBool(showClosedZones) + constant.CacheSep + constant.SourceCategoryAll
+			var lastModifiedTime time.Time
+			if err := cache.LastModifiedTime.Get("[shimGlobalDropMatrix#server|showClosedZones|sourceCategory:"+key+"]", &lastModifiedTime); err != nil {
+				lastModifiedTime = time.Now()
+			}
+			cachectrl.OptIn(ctx, lastModifiedTime)
+			cachectrl.Freshness(ctx, lastModifiedTime, c.DropMatrixService.GlobalCacheTTL(server))
+		}
 	}
 
-	shimQueryResult, err := c.DropMatrixService.GetShimDropMatrix(ctx.UserContext(), server, showClosedZones, stageFilterStr, itemFilterStr, accountId, sourceCategory)
-	if err != nil {
-		return err
+	if params.Format != "" {
+		table, err := c.ExportService.DropMatrixTable(ctx.UserContext(), shimQueryResult, params.Lang)
+		if err != nil {
+			return err
+		}
+		return sendTable(ctx, params.Format, "matrix-"+server, table)
 	}
 
-	useCache := !accountId.Valid && stageFilterStr == "" && itemFilterStr == ""
-	if useCache {
-		key := server + constant.CacheSep + strconv.FormatBool(showClosedZones) + constant.CacheSep + constant.SourceCategoryAll
-		var lastModifiedTime time.Time
-		if err := cache.LastModifiedTime.Get("[shimGlobalDropMatrix#server|showClosedZones|sourceCategory:"+key+"]", &lastModifiedTime); err != nil {
-			lastModifiedTime = time.Now()
+	if params.Localized {
+		localized, err := c.LocalizationService.LocalizeDropMatrix(ctx.UserContext(), shimQueryResult, params.Lang)
+		if err != nil {
+			return err
 		}
-		cachectrl.OptIn(ctx, lastModifiedTime)
+		if params.Fields != "" {
+			return sendSparse(ctx)(service.SparseDropMatrix(localized.Matrix, params.Fields))
+		}
+		if showClosedZones && streamable(ctx) {
+			return sendStream(ctx, func(w io.Writer) error {
+				return jsonstream.Array(w, "matrix", localized.Matrix)
+			})
+		}
+		return ctx.JSON(localized)
 	}
 
+	if params.Fields != "" {
+		return sendSparse(ctx)(service.SparseDropMatrix(shimQueryResult.Matrix, params.Fields))
+	}
+	if showClosedZones && streamable(ctx) {
+		return sendStream(ctx, func(w io.Writer) error {
+			return jsonstream.Array(w, "matrix", shimQueryResult.Matrix)
+		})
+	}
 	return ctx.JSON(shimQueryResult)
 }
 
@@ -162,8 +302,17 @@ func (c *Result) GetDropMatrix(ctx *fiber.Ctx) error {
 //	@Param		server			query		string	true	"Server; default to CN"	Enums(CN, US, JP, KR)
 //	@Param		is_personal		query		bool	false	"Whether to query for personal drop matrix or not. If `is_personal` equals to `true`, a valid PenguinID would be required to be provided (PenguinIDAuth)"
 //	@Param		showAllPatterns	query		bool	false	"Show all patterns; default to false"
+//	@Param		format			query		string	false	"Respond with a spreadsheet instead of JSON"																											Enums(csv, xlsx)
+//	@Param		lang			query		string	false	"Language of the stage codes and item names; default to the best match of the Accept-Language header, or zh. Implies `localized` for JSON responses"	Enums(zh, en, ja, ko)
+//	@Param		localized		query		bool	false	"Embed the stage and item names in the language selected by `lang` or the Accept-Language header into the JSON response, as `stageName` and `itemName` fields"
+//	@Param		fields			query		string	false	"Comma separated list of the fields of the pattern matrix elements to respond with, e.g. `times,quantity`; `stageId` and `pattern` are always included"
+//	@Param		range			query		string	false	"Respond with the pattern matrix of the reports of the last 7, 30 or 90 days, up to the start of the current hour, rather than of the latest time ranges of the stages"	Enums(7d, 30d, 90d)
 //	@Success	200				{object}	modelv2.PatternMatrixQueryResult
-//	@Failure	500				{object}	pgerr.PenguinError	"An unexpected error occurred"
+//	@Header		200				{integer}	X-Penguin-Purged-Before	"Set on personal results that leave out the reports moved to the archive before this time, in unix milliseconds. Those may be requested as an export instead"
+//	@Header		200				{integer}	X-Penguin-Cache-Age		"Set on the cached global results, to the seconds elapsed since the result has been calculated"
+//	@Header		200				{integer}	X-Penguin-Refresh-After	"Set on the cached global results, to the seconds after which the result is due to be calculated again"
+//	@Failure	400				{object}	pgerr.Problem	"Invalid request"
+//	@Failure	500				{object}	pgerr.Problem	"An unexpected error occurred"
 //	@Security	PenguinIDAuth
 //	@Router		/PenguinStats/api/v2/result/pattern [GET]
 func (c *Result) GetPatternMatrix(ctx *fiber.Ctx) error {
@@ -173,6 +322,14 @@ func (c *Result) GetPatternMatrix(ctx *fiber.Ctx) error {
 	}
 
 	showAllPatterns := ctx.Query("show_all_patterns", "false") == "true"
+	params, err := c.parseResultParams(ctx)
+	if err != nil {
+		return err
+	}
+	recentDays, err := parseRecentRange(ctx)
+	if err != nil {
+		return err
+	}
 
 	isPersonal, err := strconv.ParseBool(ctx.Query("is_personal", "false"))
 	if err != nil {
@@ -181,7 +338,7 @@ func (c *Result) GetPatternMatrix(ctx *fiber.Ctx) error {
 
 	accountId := null.NewInt(0, false)
 	if isPersonal {
-		account, err := c.AccountService.GetAccountFromRequest(ctx)
+		account, err := c.AccountService.GetAccountFromPenguinId(ctx.UserContext(), pgid.Extract(ctx))
 		if err != nil {
 			return err
 		}
@@ -189,29 +346,75 @@ func (c *Result) GetPatternMatrix(ctx *fiber.Ctx) error {
 		accountId.Valid = true
 	}
 
-	shimResult, err := c.PatternMatrixService.GetShimPatternMatrix(ctx.UserContext(), server, accountId, constant.SourceCategoryAll, showAllPatterns)
+	key := coalesce.Key("pattern", server, strconv.FormatBool(showAllPatterns), accountKey(accountId), strconv.Itoa(recentDays))
+	shimResult, err := patternMatrixQueries.Do(key, func() (*modelv2.PatternMatrixQueryResult, error) {
+		if recentDays != 0 {
+			return c.PatternMatrixService.GetShimRecentPatternMatrix(ctx.UserContext(), server, recentDays, accountId, constant.SourceCategoryAll, showAllPatterns)
+		}
+		return c.PatternMatrixService.GetShimPatternMatrix(ctx.UserContext(), server, accountId, constant.SourceCategoryAll, showAllPatterns)
+	})
 	if err != nil {
 		return err
 	}
+	c.hintPurgedReports(ctx, accountId, recentStart(recentDays))
 
-	if !accountId.Valid {
+	if !accountId.Valid && recentDays == 0 {
 		key := server + constant.CacheSep + constant.SourceCategoryAll + constant.CacheSep + strconv.FormatBool(showAllPatterns)
 		var lastModifiedTime time.Time
 		if err := cache.LastModifiedTime.Get("[shimGlobalPatternMatrix#server|sourceCategory|showAllPatterns:"+key+"]", &lastModifiedTime); err != nil {
 			lastModifiedTime = time.Now()
 		}
 		cachectrl.OptIn(ctx, lastModifiedTime)
+		cachectrl.Freshness(ctx, lastModifiedTime, c.PatternMatrixService.GlobalCacheTTL(server))
 	}
 
+	if params.Format != "" {
+		table, err := c.ExportService.PatternMatrixTable(ctx.UserContext(), shimResult, params.Lang)
+		if err != nil {
+			return err
+		}
+		return sendTable(ctx, params.Format, "pattern-"+server, table)
+	}
+
+	if params.Localized {
+		localized, err := c.LocalizationService.LocalizePatternMatrix(ctx.UserContext(), shimResult, params.Lang)
+		if err != nil {
+			return err
+		}
+		if params.Fields != "" {
+			return sendSparse(ctx)(service.SparsePatternMatrix(localized.PatternMatrix, params.Fields))
+		}
+		if streamable(ctx) {
+			return sendStream(ctx, func(w io.Writer) error {
+				return jsonstream.Array(w, "pattern_matrix", localized.PatternMatrix)
+			})
+		}
+		return ctx.JSON(localized)
+	}
+
+	if params.Fields != "" {
+		return sendSparse(ctx)(service.SparsePatternMatrix(shimResult.PatternMatrix, params.Fields))
+	}
+	if streamable(ctx) {
+		return sendStream(ctx, func(w io.Writer) error {
+			return jsonstream.Array(w, "pattern_matrix", shimResult.PatternMatrix)
+		})
+	}
 	return ctx.JSON(shimResult)
 }
 
 //	@Summary	Get Trends
 //	@Tags		Result
 //	@Produce	json
-//	@Param		server	query		string	true	"Server; default to CN"	Enums(CN, US, JP, KR)
-//	@Success	200		{object}	modelv2.TrendQueryResult
-//	@Failure	500		{object}	pgerr.PenguinError	"An unexpected error occurred"
+//	@Param		server		query		string	true	"Server; default to CN"																																	Enums(CN, US, JP, KR)
+//	@Param		format		query		string	false	"Respond with a spreadsheet instead of JSON"																											Enums(csv, xlsx)
+//	@Param		lang		query		string	false	"Language of the stage codes and item names; default to the best match of the Accept-Language header, or zh. Implies `localized` for JSON responses"	Enums(zh, en, ja, ko)
+//	@Param		localized	query		bool	false	"Embed the stage and item names in the language selected by `lang` or the Accept-Language header into the JSON response, as `stageName` and `itemName` fields"
+//	@Param		fields		query		string	false	"Comma separated list of the fields of the item trends to respond with, e.g. `quantity`"
+//	@Success	200			{object}	modelv2.TrendQueryResult
+//	@Header		200			{integer}	X-Penguin-Cache-Age		"Set to the seconds elapsed since the trends have been calculated"
+//	@Header		200			{integer}	X-Penguin-Refresh-After	"Set to the seconds after which the trends are due to be calculated again"
+//	@Failure	500			{object}	pgerr.Problem	"An unexpected error occurred"
 //	@Router		/PenguinStats/api/v2/result/trends [GET]
 func (c *Result) GetTrends(ctx *fiber.Ctx) error {
 	server := ctx.Query("server", "CN")
@@ -219,6 +422,11 @@ func (c *Result) GetTrends(ctx *fiber.Ctx) error {
 		return err
 	}
 
+	params, err := c.parseResultParams(ctx)
+	if err != nil {
+		return err
+	}
+
 	shimResult, err := c.TrendService.GetShimTrend(ctx.UserContext(), server)
 	if err != nil {
 		return err
@@ -229,7 +437,40 @@ func (c *Result) GetTrends(ctx *fiber.Ctx) error {
 		lastModifiedTime = time.Now()
 	}
 	cachectrl.OptIn(ctx, lastModifiedTime)
+	cachectrl.Freshness(ctx, lastModifiedTime, c.TrendService.GlobalCacheTTL(server))
 
+	if params.Format != "" {
+		table, err := c.ExportService.TrendTable(ctx.UserContext(), shimResult, params.Lang)
+		if err != nil {
+			return err
+		}
+		return sendTable(ctx, params.Format, "trends-"+server, table)
+	}
+
+	if params.Localized {
+		localized, err := c.LocalizationService.LocalizeTrend(ctx.UserContext(), shimResult, params.Lang)
+		if err != nil {
+			return err
+		}
+		if params.Fields != "" {
+			return sendSparse(ctx)(service.SparseLocalizedTrend(localized, params.Fields))
+		}
+		if streamable(ctx) {
+			return sendStream(ctx, func(w io.Writer) error {
+				return jsonstream.Map(w, "trend", localized.Trend)
+			})
+		}
+		return ctx.JSON(localized)
+	}
+
+	if params.Fields != "" {
+		return sendSparse(ctx)(service.SparseTrend(shimResult, params.Fields))
+	}
+	if streamable(ctx) {
+		return sendStream(ctx, func(w io.Writer) error {
+			return jsonstream.Map(w, "trend", shimResult.Trend)
+		})
+	}
 	return ctx.JSON(shimResult)
 }
 
@@ -238,8 +479,10 @@ func (c *Result) GetTrends(ctx *fiber.Ctx) error {
 //	@Produce	json
 //	@Param		query	body		types.AdvancedQueryRequest														true	"Query"
 //	@Success	200		{object}	modelv2.AdvancedQueryResult{advanced_results=[]modelv2.DropMatrixQueryResult}	"Drop Matrix Response: when `interval` has been left undefined."
+//	@Header		200		{integer}	X-Penguin-Purged-Before	"Set on personal results that leave out the reports moved to the archive before this time, in unix milliseconds. Those may be requested as an export instead"
 //	@Success	202		{object}	modelv2.AdvancedQueryResult{advanced_results=[]modelv2.TrendQueryResult}		"Trend Response: when `interval` has been defined a value greater than `0`. Notice that this response still responds with a status code of `200`, but due to swagger limitations, to denote a different response with the same status code is not possible. Therefore, a status code of `202` is used, only for the purpose of workaround."
-//	@Failure	500		{object}	pgerr.PenguinError																"An unexpected error occurred"
+//	@Failure	400		{object}	pgerr.Problem																"Invalid request, or a query is too costly to run: its time range, stages and items shall be narrowed down"
+//	@Failure	500		{object}	pgerr.Problem																"An unexpected error occurred"
 //	@Router		/PenguinStats/api/v2/result/advanced [POST]
 func (c *Result) AdvancedQuery(ctx *fiber.Ctx) error {
 	var request types.AdvancedQueryRequest
@@ -267,7 +510,7 @@ func (c *Result) handleAdvancedQuery(ctx *fiber.Ctx, query *types.AdvancedQuery)
 	}
 	accountId := null.NewInt(0, false)
 	if isPersonal {
-		account, err := c.AccountService.GetAccountFromRequest(ctx)
+		account, err := c.AccountService.GetAccountFromPenguinId(ctx.UserContext(), pgid.Extract(ctx))
 		if err != nil {
 			return nil, err
 		}
@@ -281,6 +524,13 @@ func (c *Result) handleAdvancedQuery(ctx *fiber.Ctx, query *types.AdvancedQuery)
 		startTimeMilli = query.StartTime
 	}
 	startTime := time.UnixMilli(startTimeMilli)
+	c.hintPurgedReports(ctx, accountId, &startTime)
+	// the global results are rather rejected than calculated from the reports left after the purge
+	if !accountId.Valid {
+		if err := c.RetentionService.CheckNotPurged(startTime); err != nil {
+			return nil, err
+		}
+	}
 
 	// handle end time (might be null)
 	endTimeMilli := time.Now().UnixMilli()
@@ -288,6 +538,9 @@ func (c *Result) handleAdvancedQuery(ctx *fiber.Ctx, query *types.AdvancedQuery)
 		endTimeMilli = query.EndTime
 	}
 	endTime := time.UnixMilli(endTimeMilli)
+	if err := c.QueryCostService.Admit(startTime, endTime, 1, len(query.ItemIDs)); err != nil {
+		return nil, err
+	}
 
 	// handle ark stage id
 	stage, err := c.StageService.GetStageByArkId(ctx.UserContext(), query.StageID)
@@ -296,11 +549,15 @@ func (c *Result) handleAdvancedQuery(ctx *fiber.Ctx, query *types.AdvancedQuery)
 	}
 
 	// handle item ids
-	itemIds := make([]int, 0)
+	itemsMapByArkId, err := c.ItemService.GetItemsByArkIds(ctx.UserContext(), query.ItemIDs)
+	if err != nil {
+		return nil, err
+	}
+	itemIds := make([]int, 0, len(query.ItemIDs))
 	for _, arkItemID := range query.ItemIDs {
-		item, err := c.ItemService.GetItemByArkId(ctx.UserContext(), arkItemID)
-		if err != nil {
-			return nil, err
+		item, ok := itemsMapByArkId[arkItemID]
+		if !ok {
+			return nil, pgerr.ErrNotFound
 		}
 		itemIds = append(itemIds, item.ItemID)
 	}
@@ -311,13 +568,21 @@ func (c *Result) handleAdvancedQuery(ctx *fiber.Ctx, query *types.AdvancedQuery)
 		sourceCategory = constant.SourceCategoryAll
 	}
 
+	// an open end is keyed as is rather than by the time of the request, so that the concurrent ones share the query
+	sortedItemIds := append([]int(nil), itemIds...)
+	sort.Ints(sortedItemIds)
+	key := coalesce.Key("advanced", query.Server, strconv.Itoa(stage.StageID), fmt.Sprint(sortedItemIds), accountKey(accountId),
+		sourceCategory, strconv.FormatInt(startTimeMilli, 10), strconv.FormatInt(query.EndTime, 10), strconv.FormatInt(query.Interval.Int64, 10))
+
 	// if there is no interval, then do drop matrix query, otherwise do trend query
 	if !query.Interval.Valid {
 		timeRange := &model.TimeRange{
 			StartTime: &startTime,
 			EndTime:   &endTime,
 		}
-		return c.DropMatrixService.GetShimCustomizedDropMatrixResults(ctx.UserContext(), query.Server, timeRange, []int{stage.StageID}, itemIds, accountId, sourceCategory)
+		return c.AdvancedQueryService.Cached(key, !accountId.Valid, func() (any, error) {
+			return c.DropMatrixService.GetShimCustomizedDropMatrixResults(ctx.UserContext(), query.Server, timeRange, []int{stage.StageID}, itemIds, accountId, sourceCategory)
+		})
 	} else {
 		// interval originally is in milliseconds, so we need to convert it to nanoseconds
 		intervalLength := time.Duration(query.Interval.Int64 * 1e6).Round(time.Hour)
@@ -326,15 +591,189 @@ func (c *Result) handleAdvancedQuery(ctx *fiber.Ctx, query *types.AdvancedQuery)
 		}
 		intervalNum := c.calcIntervalNum(startTime, endTime, intervalLength)
 		if intervalNum > constant.MaxIntervalNum {
-			return nil, pgerr.ErrInvalidReq.Msg("too many sections: interval number is %d sections, which is larger than %d sections", intervalNum, constant.MaxIntervalNum)
+			return nil, rekuest.Violationf("interval", rekuest.ViolationRange, "too many sections: interval number is %d sections, which is larger than %d sections", intervalNum, constant.MaxIntervalNum)
 		}
 
-		shimTrendQueryResult, err := c.TrendService.GetShimCustomizedTrendResults(ctx.UserContext(), query.Server, &startTime, intervalLength, intervalNum, []int{stage.StageID}, itemIds, accountId, sourceCategory)
+		return c.AdvancedQueryService.Cached(key, !accountId.Valid, func() (any, error) {
+			return c.TrendService.GetShimCustomizedTrendResults(ctx.UserContext(), query.Server, &startTime, intervalLength, intervalNum, []int{stage.StageID}, itemIds, accountId, sourceCategory)
+		})
+	}
+}
+
+// hintPurgedReports hints the personal results that would have counted the reports purged from the database, i.e.
+// those starting before the purged time or having no start, that the reports are left out and may be exported instead
+func (c *Result) hintPurgedReports(ctx *fiber.Ctx, accountId null.Int, start *time.Time) {
+	if !accountId.Valid {
+		return
+	}
+	purgedBefore, ok := c.RetentionService.PurgedBefore()
+	if !ok || (start != nil && !start.Before(purgedBefore)) {
+		return
+	}
+	ctx.Set(HeaderPurgedBefore, strconv.FormatInt(purgedBefore.UnixMilli(), 10))
+	ctx.Set("X-Penguin-Notes", "Reports before "+purgedBefore.UTC().Format(time.RFC3339)+" have been moved to the archive and are left out of personal results. Please request an export of your reports to get them.")
+}
+
+// parseRecentRange parses the `range` shorthand into the number of days of its rolling window, or 0 if absent
+func parseRecentRange(ctx *fiber.Ctx) (int, error) {
+	rangeStr := ctx.Query("range")
+	if rangeStr == "" {
+		return 0, nil
+	}
+	days, ok := recentRangeDays[rangeStr]
+	if !ok {
+		return 0, rekuest.Violation("range", "oneof", "`range` must be one of 7d, 30d and 90d")
+	}
+	return days, nil
+}
+
+// recentStart is the start of the rolling window of the days, or nil for the results without one
+func recentStart(days int) *time.Time {
+	if days == 0 {
+		return nil
+	}
+	return util.RecentTimeRange(time.Now(), days).StartTime
+}
+
+// accountKey is the part of the coalescing keys telling the account of the personal results apart
+func accountKey(accountId null.Int) string {
+	if !accountId.Valid {
+		return ""
+	}
+	return strconv.FormatInt(accountId.Int64, 10)
+}
+
+// normalizeFilter sorts and dedupes the comma separated IDs of a filter, which are matched regardless of their order
+func normalizeFilter(filter string) string {
+	if filter == "" {
+		return ""
+	}
+	ids := lo.Uniq(strings.Split(filter, ","))
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+// resultParams describes how a result should be represented
+type resultParams struct {
+	// Format is the spreadsheet format requested, either with the format query param or the Accept header.
+	// An empty format means JSON is requested.
+	Format string
+	// Lang is the language of the stage and item names, either the lang query param or
+	// the best match of the Accept-Language header
+	Lang string
+	// Localized tells whether the names should be embedded into the JSON response, which is
+	// requested with the localized query param or implied by an explicit lang query param
+	Localized bool
+	// Fields is the comma separated list of the fields of the elements to be kept in the JSON response.
+	// An empty list means all fields are kept.
+	Fields string
+}
+
+func (c *Result) parseResultParams(ctx *fiber.Ctx) (*resultParams, error) {
+	params := &resultParams{
+		Format: ctx.Query("format"),
+		Lang:   ctx.Query("lang"),
+		Fields: ctx.Query("fields"),
+	}
+	if params.Format == "" {
+		switch ctx.Accepts(fiber.MIMEApplicationJSON, tabular.MediaTypeCSV, tabular.ContentTypeXLSX) {
+		case tabular.MediaTypeCSV:
+			params.Format = tabular.FormatCSV
+		case tabular.ContentTypeXLSX:
+			params.Format = tabular.FormatXLSX
+		}
+	}
+	if params.Format != "" && params.Format != tabular.FormatCSV && params.Format != tabular.FormatXLSX {
+		return nil, rekuest.Violation("format", "oneof", "format must be either csv or xlsx")
+	}
+
+	if params.Lang == "" {
+		params.Lang = i18n.MatchLanguage(ctx.Get(fiber.HeaderAcceptLanguage))
+	} else if !i18n.IsLanguage(params.Lang) {
+		return nil, rekuest.Violation("lang", "oneof", "lang must be one of zh, en, ja and ko")
+	} else {
+		params.Localized = true
+	}
+	if ctx.Query("localized") != "" {
+		localized, err := strconv.ParseBool(ctx.Query("localized"))
 		if err != nil {
-			return nil, err
+			return nil, rekuest.Violation("localized", "boolean", "localized must be a boolean")
+		}
+		params.Localized = localized
+	}
+
+	// sparse fieldsets are being rolled out: the fields query param is ignored for the clients not in the rollout
+	if params.Fields != "" && !c.FeatureFlags.Enabled(service.FlagSparseFieldsets, ctx.Query("server", "CN"), util.ExtractIP(ctx)) {
+		params.Fields = ""
+	}
+
+	ctx.Vary(fiber.HeaderAccept, fiber.HeaderAcceptLanguage)
+	return params, nil
+}
+
+// cacheKey identifies the representation among the ones of the same URL
+func (p *resultParams) cacheKey() string {
+	key := p.Format
+	if p.Format != "" || p.Localized {
+		key += constant.CacheSep + p.Lang
+	}
+	if p.Fields != "" {
+		key += constant.CacheSep + "sparse"
+	}
+	return key
+}
+
+// sendSparse responds with the sparse result unless trimming the fields failed
+func sendSparse(ctx *fiber.Ctx) func(result any, err error) error {
+	return func(result any, err error) error {
+		if err != nil {
+			return err
 		}
-		return shimTrendQueryResult, nil
+		return ctx.JSON(result)
+	}
+}
+
+// streamable tells whether the response may be streamed, which is not the case for the requests cached by the cache
+// middleware of the group as it would store the body encoded for the client that happened to fill the cache
+func streamable(ctx *fiber.Ctx) bool {
+	return ctx.Query("itemFilter") == "" && ctx.Query("stageFilter") == ""
+}
+
+// sendStream streams the JSON response written by write, so that the large results are not marshaled as a whole
+// before being sent. The compress middleware leaves streamed bodies as is, hence they are encoded here while being
+// written.
+func sendStream(ctx *fiber.Ctx, write func(w io.Writer) error) error {
+	encoding := middlewares.StreamEncoding(ctx)
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	ctx.Vary(fiber.HeaderAcceptEncoding)
+	if encoding != "" {
+		ctx.Set(fiber.HeaderContentEncoding, encoding)
 	}
+	middlewares.SetBodyStreamWriter(ctx, func(w *bufio.Writer) {
+		enc, err := middlewares.NewStreamEncoder(w, encoding)
+		if err != nil {
+			log.Error().Err(err).Str("encoding", encoding).Msg("failed to create stream encoder")
+			return
+		}
+		if err := write(enc); err != nil {
+			log.Error().Err(err).Msg("failed to write streamed response")
+		}
+		if err := enc.Close(); err != nil {
+			log.Error().Err(err).Str("encoding", encoding).Msg("failed to flush stream encoder")
+		}
+	})
+	return nil
+}
+
+func sendTable(ctx *fiber.Ctx, format string, filename string, table *tabular.Table) error {
+	ctx.Set(fiber.HeaderContentType, tabular.ContentType(format))
+	ctx.Attachment(filename + "." + format)
+	middlewares.SetBodyStreamWriter(ctx, func(w *bufio.Writer) {
+		if err := tabular.Write(w, format, table); err != nil {
+			log.Error().Err(err).Str("format", format).Msg("failed to write exported table")
+		}
+	})
+	return nil
 }
 
 func (c *Result) calcIntervalNum(startTime, endTime time.Time, intervalLength time.Duration) int {
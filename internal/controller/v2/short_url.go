@@ -6,6 +6,7 @@ import (
 
 	"exusiai.dev/backend-next/internal/server/svr"
 	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util"
 )
 
 type ShortURL struct {
@@ -21,5 +22,5 @@ func RegisterShortURL(v2 *svr.V2, c ShortURL) {
 
 func (c *ShortURL) Resolve(ctx *fiber.Ctx) error {
 	word := ctx.Params("word")
-	return ctx.Redirect(c.ShortURLService.Resolve(ctx, word))
+	return ctx.Redirect(c.ShortURLService.Resolve(ctx.UserContext(), util.ExtractIP(ctx), word))
 }
@@ -32,7 +32,7 @@ func RegisterSiteStats(v2 *svr.V2, c SiteStats) {
 //	@Produce	json
 //	@Param		server	query		string	true	"Server; default to CN"	Enums(CN, US, JP, KR)
 //	@Success	200		{array}		modelv2.SiteStats
-//	@Failure	500		{object}	pgerr.PenguinError	"An unexpected error occurred"
+//	@Failure	500		{object}	pgerr.Problem	"An unexpected error occurred"
 //	@Router		/PenguinStats/api/v2/stats [GET]
 func (c *SiteStats) GetSiteStats(ctx *fiber.Ctx) error {
 	server := ctx.Query("server", "CN")
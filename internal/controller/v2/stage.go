@@ -31,7 +31,7 @@ func RegisterStage(v2 *svr.V2, c Stage) {
 //	@Tags		Stage
 //	@Produce	json
 //	@Success	200	{array}		modelv2.Stage{existence=model.Existence,code_i18n=model.I18nString}
-//	@Failure	500	{object}	pgerr.PenguinError	"An unexpected error occurred"
+//	@Failure	500	{object}	pgerr.Problem	"An unexpected error occurred"
 //	@Router		/PenguinStats/api/v2/stages [GET]
 func (c *Stage) GetStages(ctx *fiber.Ctx) error {
 	server := ctx.Query("server", "CN")
@@ -45,6 +45,10 @@ func (c *Stage) GetStages(ctx *fiber.Ctx) error {
 		lastModifiedTime = time.Now()
 	}
 	cachectrl.OptIn(ctx, lastModifiedTime)
+	var contentHash string
+	if err := cache.ContentHash.Get("[shimStages#server:"+server+"]", &contentHash); err == nil && cachectrl.ETag(ctx, contentHash) {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
 	return ctx.JSON(stages)
 }
 
@@ -53,8 +57,8 @@ func (c *Stage) GetStages(ctx *fiber.Ctx) error {
 //	@Produce	json
 //	@Param		stageId	path		int	true	"Stage ID"
 //	@Success	200		{object}	modelv2.Stage{existence=model.Existence,code_i18n=model.I18nString}
-//	@Failure	400		{object}	pgerr.PenguinError	"Invalid or missing stageId. Notice that this shall be the **string ID** of the stage, instead of the internally used numerical ID of the stage."
-//	@Failure	500		{object}	pgerr.PenguinError	"An unexpected error occurred"
+//	@Failure	400		{object}	pgerr.Problem	"Invalid or missing stageId. Notice that this shall be the **string ID** of the stage, instead of the internally used numerical ID of the stage."
+//	@Failure	500		{object}	pgerr.Problem	"An unexpected error occurred"
 //	@Router		/PenguinStats/api/v2/stages/{stageId} [GET]
 func (c *Stage) GetStageByArkId(ctx *fiber.Ctx) error {
 	stageId := ctx.Params("stageId")
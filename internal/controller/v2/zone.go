@@ -30,7 +30,7 @@ func RegisterZone(v2 *svr.V2, c Zone) {
 //	@Tags		Zone
 //	@Produce	json
 //	@Success	200	{array}		modelv2.Zone{existence=model.Existence,zoneName_i18n=model.I18nString}
-//	@Failure	500	{object}	pgerr.PenguinError	"An unexpected error occurred"
+//	@Failure	500	{object}	pgerr.Problem	"An unexpected error occurred"
 //	@Router		/PenguinStats/api/v2/zones [GET]
 func (c *Zone) GetZones(ctx *fiber.Ctx) error {
 	zones, err := c.ZoneService.GetShimZones(ctx.UserContext())
@@ -42,6 +42,10 @@ func (c *Zone) GetZones(ctx *fiber.Ctx) error {
 		lastModifiedTime = time.Now()
 	}
 	cachectrl.OptIn(ctx, lastModifiedTime)
+	var contentHash string
+	if err := cache.ContentHash.Get("[shimZones]", &contentHash); err == nil && cachectrl.ETag(ctx, contentHash) {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
 	return ctx.JSON(zones)
 }
 
@@ -50,8 +54,8 @@ func (c *Zone) GetZones(ctx *fiber.Ctx) error {
 //	@Produce	json
 //	@Param		zoneId	path		int	true	"Zone ID"
 //	@Success	200		{object}	modelv2.Zone{existence=model.Existence,zoneName_i18n=model.I18nString}
-//	@Failure	400		{object}	pgerr.PenguinError	"Invalid or missing zoneId. Notice that this shall be the **string ID** of the zone, instead of the v3 API internally used numerical ID of the zone."
-//	@Failure	500		{object}	pgerr.PenguinError	"An unexpected error occurred"
+//	@Failure	400		{object}	pgerr.Problem	"Invalid or missing zoneId. Notice that this shall be the **string ID** of the zone, instead of the v3 API internally used numerical ID of the zone."
+//	@Failure	500		{object}	pgerr.Problem	"An unexpected error occurred"
 //	@Router		/PenguinStats/api/v2/zones/{zoneId} [GET]
 func (c *Zone) GetZoneByArkId(ctx *fiber.Ctx) error {
 	zoneId := ctx.Params("zoneId")
@@ -7,11 +7,23 @@ import (
 func Module() fx.Option {
 	return fx.Module("controllers.v3", fx.Invoke(
 		RegisterItem,
+		RegisterItemValue,
+		RegisterAccount,
 		RegisterLive,
 		RegisterStage,
 		RegisterZone,
+		RegisterEvent,
 		RegisterDataset,
 		RegisterInit,
 		RegisterIncremental,
+		RegisterSiteStats,
+		RegisterWebhook,
+		RegisterReport,
+		RegisterMeta,
+		RegisterResult,
+		RegisterTool,
+		RegisterRecognition,
+		RegisterNotice,
+		RegisterShortLink,
 	))
 }
@@ -0,0 +1,180 @@
+package v3
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/pkg/pgid"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+var (
+	_ model.AccountAppeal
+	_ modelv3.Dummy
+	_ pgerr.PenguinError
+)
+
+type AccountController struct {
+	fx.In
+
+	AccountService         *service.Account
+	AccountStandingService *service.AccountStanding
+	APIKeyService          *service.APIKey
+	APIUsageService        *service.APIUsage
+}
+
+func RegisterAccount(v3 *svr.V3, c AccountController) {
+	v3.Get("/account/standing", c.GetStanding)
+	v3.Post("/account/appeals", c.SubmitAppeal)
+	v3.Get("/account/leaderboard-settings", c.GetLeaderboardSettings)
+	v3.Put("/account/leaderboard-settings", c.UpdateLeaderboardSettings)
+	v3.Get("/account/api-usage", middlewares.APIKeyAuthentication(c.APIKeyService.AuthenticateAPIKey), c.GetAPIUsage)
+}
+
+// @Summary		Get Account Standing
+// @Description	Get the standing of the account, i.e. how its recent reports have been treated by the verifiers, along with its latest appeal.
+// @Tags			Account
+// @Produce		json
+// @Success		200	{object}	modelv3.AccountStanding
+// @Failure		400	{object}	pgerr.Problem	"PenguinID is missing or invalid"
+// @Failure		500	{object}	pgerr.Problem	"An unexpected error occurred"
+// @Security		PenguinIDAuth
+// @Router			/api/v3alpha/account/standing [GET]
+func (c *AccountController) GetStanding(ctx *fiber.Ctx) error {
+	account, err := c.AccountService.GetAccountFromPenguinId(ctx.UserContext(), pgid.Extract(ctx))
+	if err != nil {
+		return err
+	}
+
+	standing, err := c.AccountStandingService.GetAccountStanding(ctx.UserContext(), account)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(standing)
+}
+
+type SubmitAppealRequest struct {
+	Reason  string `json:"reason" validate:"required,max=2000"`
+	Contact string `json:"contact" validate:"omitempty,max=256"`
+}
+
+// @Summary	Submit an Account Appeal
+// @Tags		Account
+// @Accept		json
+// @Produce	json
+// @Param		appeal	body		v3.SubmitAppealRequest	true	"Appeal request"
+// @Success	201		{object}	model.AccountAppeal
+// @Failure	400		{object}	pgerr.Problem	"Invalid request"
+// @Failure	409		{object}	pgerr.Problem	"An appeal is already pending"
+// @Failure	500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Security	PenguinIDAuth
+// @Router		/api/v3alpha/account/appeals [POST]
+func (c *AccountController) SubmitAppeal(ctx *fiber.Ctx) error {
+	var request SubmitAppealRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	account, err := c.AccountService.GetAccountFromPenguinId(ctx.UserContext(), pgid.Extract(ctx))
+	if err != nil {
+		return err
+	}
+
+	appeal, err := c.AccountStandingService.SubmitAppeal(ctx.UserContext(), account, request.Reason, request.Contact)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(appeal)
+}
+
+// @Summary		Get Leaderboard Settings
+// @Description	Get whether and under which name the account is listed on the contributor leaderboards of the events.
+// @Tags			Account
+// @Produce		json
+// @Success		200	{object}	modelv3.AccountLeaderboardSettings
+// @Failure		400	{object}	pgerr.Problem	"PenguinID is missing or invalid"
+// @Failure		500	{object}	pgerr.Problem	"An unexpected error occurred"
+// @Security		PenguinIDAuth
+// @Router			/api/v3alpha/account/leaderboard-settings [GET]
+func (c *AccountController) GetLeaderboardSettings(ctx *fiber.Ctx) error {
+	account, err := c.AccountService.GetAccountFromPenguinId(ctx.UserContext(), pgid.Extract(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(c.AccountService.GetLeaderboardSettings(account))
+}
+
+type UpdateLeaderboardSettingsRequest struct {
+	OptIn bool `json:"optIn"`
+	// Name is listed instead of an anonymous name; the PenguinID is never listed
+	Name null.String `json:"name" validate:"omitempty,max=32" swaggertype:"string"`
+}
+
+// @Summary		Update Leaderboard Settings
+// @Description	Opt the account in or out of the contributor leaderboards of the events, and set the name it is listed under. Accounts are never listed unless opted in. The leaderboards pick the changes up within a few minutes.
+// @Tags			Account
+// @Accept			json
+// @Produce		json
+// @Param			settings	body		v3.UpdateLeaderboardSettingsRequest	true	"Leaderboard settings"
+// @Success		200			{object}	modelv3.AccountLeaderboardSettings
+// @Failure		400			{object}	pgerr.Problem	"Invalid request"
+// @Failure		500			{object}	pgerr.Problem	"An unexpected error occurred"
+// @Security		PenguinIDAuth
+// @Router			/api/v3alpha/account/leaderboard-settings [PUT]
+func (c *AccountController) UpdateLeaderboardSettings(ctx *fiber.Ctx) error {
+	var request UpdateLeaderboardSettingsRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	account, err := c.AccountService.GetAccountFromPenguinId(ctx.UserContext(), pgid.Extract(ctx))
+	if err != nil {
+		return err
+	}
+
+	name := null.NewString(strings.TrimSpace(request.Name.String), request.Name.Valid)
+	if name.String == "" {
+		name = null.String{}
+	}
+	settings, err := c.AccountService.UpdateLeaderboardSettings(ctx.UserContext(), account, &modelv3.AccountLeaderboardSettings{
+		OptIn: request.OptIn,
+		Name:  name,
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(settings)
+}
+
+// @Summary		Get API Usage
+// @Description	Get the daily quotas of the API key, along with its usage of the recent days (in UTC). Requests made with
+// @Description	an API key carry X-RateLimit-Limit and X-RateLimit-Remaining headers of the daily request quota as well.
+// @Tags			Account
+// @Produce		json
+// @Param			days	query		int	false	"Number of recent days to include, up to 31; default to 7"
+// @Success		200		{object}	modelv3.APIUsage
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		401		"Missing or invalid API key"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Security		APIKeyAuth
+// @Router			/api/v3alpha/account/api-usage [GET]
+func (c *AccountController) GetAPIUsage(ctx *fiber.Ctx) error {
+	usage, err := c.APIUsageService.GetAPIUsage(ctx.UserContext(), middlewares.APIKeyFrom(ctx), ctx.QueryInt("days", 7))
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(usage)
+}
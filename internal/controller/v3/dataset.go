@@ -11,11 +11,15 @@ import (
 
 	modelv2 "exusiai.dev/backend-next/internal/model/v2"
 	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/pkg/pgid"
 	"exusiai.dev/backend-next/internal/server/svr"
 	"exusiai.dev/backend-next/internal/service"
 	"exusiai.dev/backend-next/internal/util/rekuest"
 )
 
+var _ pgerr.PenguinError
+
 type Dataset struct {
 	fx.In
 
@@ -47,7 +51,7 @@ func (c Dataset) aggregateMatrix(ctx *fiber.Ctx) (*modelv2.DropMatrixQueryResult
 
 	accountId := null.NewInt(0, false)
 	if isPersonal {
-		account, err := c.AccountService.GetAccountFromRequest(ctx)
+		account, err := c.AccountService.GetAccountFromPenguinId(ctx.UserContext(), pgid.Extract(ctx))
 		if err != nil {
 			return nil, err
 		}
@@ -87,7 +91,7 @@ func (c Dataset) aggregatePattern(ctx *fiber.Ctx) (*modelv3.PatternMatrixQueryRe
 
 	accountId := null.NewInt(0, false)
 	if isPersonal {
-		account, err := c.AccountService.GetAccountFromRequest(ctx)
+		account, err := c.AccountService.GetAccountFromPenguinId(ctx.UserContext(), pgid.Extract(ctx))
 		if err != nil {
 			return nil, err
 		}
@@ -106,6 +110,18 @@ func (c Dataset) aggregatePattern(ctx *fiber.Ctx) (*modelv3.PatternMatrixQueryRe
 	return &result, nil
 }
 
+// @Summary		Get Aggregated Stats of an Item
+// @Description	Get the drop matrix and trends of an item across stages.
+// @Tags			Dataset
+// @Produce		json
+// @Param			source		path		string	true	"Source; `personal` requires PenguinIDAuth"	Enums(global, personal)
+// @Param			category	path		string	true	"Source category"							Enums(all, automated, manual)
+// @Param			server		path		string	true	"Server"									Enums(CN, US, JP, KR)
+// @Param			itemId		path		string	true	"Item ID"
+// @Success		200			{object}	modelv3.AggregatedItemStats
+// @Failure		400			{object}	pgerr.Problem	"Invalid request"
+// @Failure		500			{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/dataset/aggregated/{source}/{category}/{server}/item/{itemId} [GET]
 func (c Dataset) AggregatedItem(ctx *fiber.Ctx) error {
 	aggregated := &modelv3.AggregatedItemStats{}
 	itemId := ctx.Params("itemId")
@@ -141,6 +157,18 @@ func (c Dataset) AggregatedItem(ctx *fiber.Ctx) error {
 	return ctx.JSON(aggregated)
 }
 
+// @Summary		Get Aggregated Stats of a Stage
+// @Description	Get the drop matrix, trends and patterns of a stage.
+// @Tags			Dataset
+// @Produce		json
+// @Param			source		path		string	true	"Source; `personal` requires PenguinIDAuth"	Enums(global, personal)
+// @Param			category	path		string	true	"Source category"							Enums(all, automated, manual)
+// @Param			server		path		string	true	"Server"									Enums(CN, US, JP, KR)
+// @Param			stageId		path		string	true	"Stage ID"
+// @Success		200			{object}	modelv3.AggregatedStageStats
+// @Failure		400			{object}	pgerr.Problem	"Invalid request"
+// @Failure		500			{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/dataset/aggregated/{source}/{category}/{server}/stage/{stageId} [GET]
 func (c Dataset) AggregatedStage(ctx *fiber.Ctx) error {
 	aggregated := &modelv3.AggregatedStageStats{}
 	stageId := ctx.Params("stageId")
@@ -0,0 +1,141 @@
+package v3
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/cachectrl"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+var _ modelv3.Dummy
+
+type EventController struct {
+	fx.In
+
+	EventService        *service.Event
+	LeaderboardService  *service.Leaderboard
+	EventSummaryService *service.EventSummary
+}
+
+func RegisterEvent(v3 *svr.V3, c EventController) {
+	v3.Get("/events", c.GetEvents)
+	v3.Get("/events/:eventId/leaderboard", c.GetEventLeaderboard)
+	v3.Get("/events/past/:eventId/summary", c.GetEventSummary)
+}
+
+// @Summary		Get All Events
+// @Description	Get the events as scheduled in each server, along with whether they are upcoming, active or past right now. The time ranges of the events and the existence of their zones are derived from these schedules.
+// @Tags			Event
+// @Produce		json
+// @Param			server	query		string	false	"Server"	Enums(CN, US, JP, KR)
+// @Param			status	query		string	false	"Status"	Enums(UPCOMING, ACTIVE, PAST)
+// @Success		200		{array}		modelv3.EventListing
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/events [GET]
+func (c *EventController) GetEvents(ctx *fiber.Ctx) error {
+	server := ctx.Query("server")
+	if server != "" {
+		if err := rekuest.ValidServer(ctx, server); err != nil {
+			return err
+		}
+	}
+	status := strings.ToUpper(ctx.Query("status"))
+	if err := rekuest.ValidVar(ctx, "status", status, "omitempty,oneof="+strings.Join(model.EventStatuses, " ")); err != nil {
+		return err
+	}
+
+	listings, err := c.EventService.GetEventListings(ctx.UserContext(), server, status)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(listings)
+}
+
+// @Summary		Get Event Leaderboard
+// @Description	Get the top contributors of the event in the server, ranked by the number of their accepted reports for the stages of the event, or by the sanity estimated to be spent on them. Only the accounts opted in are listed. The leaderboard is cached for 5 minutes.
+// @Tags			Event
+// @Produce		json
+// @Param			eventId	path		int		true	"Event ID"
+// @Param			server	query		string	true	"Server"	Enums(CN, US, JP, KR)
+// @Param			sortBy	query		string	false	"Ranking metric; default to sanity"	Enums(reports, sanity)
+// @Param			limit	query		int		false	"Number of contributors to list, up to 100; default to 20"
+// @Success		200		{object}	modelv3.EventLeaderboard
+// @Failure		400		{object}	pgerr.Problem	"Invalid request, or the event is not scheduled in the server"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/events/{eventId}/leaderboard [GET]
+func (c *EventController) GetEventLeaderboard(ctx *fiber.Ctx) error {
+	eventId, err := strconv.Atoi(ctx.Params("eventId"))
+	if err != nil {
+		return rekuest.Violation("eventId", rekuest.ViolationMalformed, "eventId must be an integer")
+	}
+	if err := rekuest.ValidVar(ctx, "eventId", eventId, "min=1"); err != nil {
+		return err
+	}
+	server := ctx.Query("server")
+	if err := rekuest.ValidServer(ctx, server); err != nil {
+		return err
+	}
+	sortBy := ctx.Query("sortBy", modelv3.LeaderboardSortBySanity)
+	if err := rekuest.ValidVar(ctx, "sortBy", sortBy, "oneof="+strings.Join(modelv3.LeaderboardSortBys, " ")); err != nil {
+		return err
+	}
+	limit := ctx.QueryInt("limit", 20)
+	if err := rekuest.ValidVar(ctx, "limit", limit, "min=1,max="+strconv.Itoa(service.LeaderboardMaxEntries)); err != nil {
+		return err
+	}
+
+	leaderboard, err := c.LeaderboardService.GetEventLeaderboard(ctx.UserContext(), eventId, server, sortBy)
+	if err != nil {
+		return err
+	}
+	if len(leaderboard.Entries) > limit {
+		leaderboard.Entries = leaderboard.Entries[:limit]
+	}
+
+	return ctx.JSON(leaderboard)
+}
+
+// @Summary		Get Past Event Summary
+// @Description	Get the final statistics of the stages of a past event in the server: the drop matrix, the total number of accepted reports and the most reported drop patterns of each stage, up to 5 of each. The summary is frozen about an hour after the event closes, and never changes afterwards even as the reports of the event are archived.
+// @Tags			Event
+// @Produce		json
+// @Param			eventId	path		int		true	"Event ID"
+// @Param			server	query		string	true	"Server"	Enums(CN, US, JP, KR)
+// @Success		200		{object}	modelv3.EventSummary
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		404		{object}	pgerr.Problem	"The event has not been summarized in the server, as it is not past yet"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/events/past/{eventId}/summary [GET]
+func (c *EventController) GetEventSummary(ctx *fiber.Ctx) error {
+	eventId, err := strconv.Atoi(ctx.Params("eventId"))
+	if err != nil {
+		return rekuest.Violation("eventId", rekuest.ViolationMalformed, "eventId must be an integer")
+	}
+	if err := rekuest.ValidVar(ctx, "eventId", eventId, "min=1"); err != nil {
+		return err
+	}
+	server := ctx.Query("server")
+	if err := rekuest.ValidServer(ctx, server); err != nil {
+		return err
+	}
+
+	summary, err := c.EventSummaryService.GetEventSummary(ctx.UserContext(), eventId, server)
+	if err != nil {
+		return err
+	}
+
+	cachectrl.OptInCustom(ctx, summary.GeneratedAt, time.Hour*24)
+
+	return ctx.JSON(summary)
+}
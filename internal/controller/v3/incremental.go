@@ -10,12 +10,12 @@ import (
 
 	dtov3 "exusiai.dev/backend-next/internal/model/dto/v3"
 	"exusiai.dev/backend-next/internal/pkg/cachectrl"
-	"exusiai.dev/backend-next/internal/pkg/pgerr"
 	"exusiai.dev/backend-next/internal/server/svr"
 	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
 )
 
-var ErrIncrementalInvalidVersions = pgerr.ErrInvalidReq.Msg("invalid versions: `versions` after /patch shall be two `from` and `to` versions, respectively, separated by three dots")
+var ErrIncrementalInvalidVersions = rekuest.Violation("versions", rekuest.ViolationMalformed, "invalid versions: `versions` after /patch shall be two `from` and `to` versions, respectively, separated by three dots")
 
 type IncrementalController struct {
 	fx.In
@@ -29,6 +29,15 @@ func RegisterIncremental(v3 *svr.V3, c IncrementalController) {
 	group.Get("/:server/:realm/patch/:versions", c.GetDiffBetweenVersions)
 }
 
+// @Summary	Get Latest Incremental Version
+// @Tags		Incremental
+// @Produce	json
+// @Param		server	path		string	true	"Server"	Enums(CN, US, JP, KR)
+// @Param		realm	path		string	true	"Realm of the snapshots"
+// @Success	200		{object}	dtov3.GetLatestIncrementalVersionResponse
+// @Failure	404		{object}	pgerr.Problem	"No snapshot found"
+// @Failure	500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router		/api/v3alpha/incremental/{server}/{realm}/latest [GET]
 func (c *IncrementalController) GetLatestIncrementalVersion(ctx *fiber.Ctx) error {
 	snapshot, err := c.SnapshotService.SnapshotRepo.GetLatestSnapshotByKey(ctx.UserContext(), c.GetSnapshotKeyFromPathParams(ctx))
 	if err != nil {
@@ -40,6 +49,18 @@ func (c *IncrementalController) GetLatestIncrementalVersion(ctx *fiber.Ctx) erro
 	})
 }
 
+// @Summary		Get Diff between Versions
+// @Description	Get a bsdiff patch between two versions of a snapshot. Responds with 204 when the versions are identical.
+// @Tags			Incremental
+// @Produce		octet-stream
+// @Param			server		path	string	true	"Server"	Enums(CN, US, JP, KR)
+// @Param			realm		path	string	true	"Realm of the snapshots"
+// @Param			versions	path	string	true	"`from` and `to` versions, separated by three dots"
+// @Success		200			{file}	binary
+// @Success		204			"The versions are identical"
+// @Failure		400			{object}	pgerr.Problem	"Invalid versions"
+// @Failure		500			{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/incremental/{server}/{realm}/patch/{versions} [GET]
 func (c *IncrementalController) GetDiffBetweenVersions(ctx *fiber.Ctx) error {
 	key := c.GetSnapshotKeyFromPathParams(ctx)
 	var fromVersion, toVersion string
@@ -4,10 +4,17 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/fx"
 
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
 	"exusiai.dev/backend-next/internal/server/svr"
 	"exusiai.dev/backend-next/internal/service"
 )
 
+var (
+	_ modelv3.Dummy
+	_ pgerr.PenguinError
+)
+
 type InitController struct {
 	fx.In
 
@@ -18,6 +25,13 @@ func RegisterInit(v3 *svr.V3, c InitController) {
 	v3.Get("/init", c.Init)
 }
 
+// @Summary		Get Init Bundle
+// @Description	Get all items, stages and zones in one request.
+// @Tags			Init
+// @Produce		json
+// @Success		200	{object}	modelv3.Init
+// @Failure		500	{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/init [GET]
 func (c *InitController) Init(ctx *fiber.Ctx) error {
 	inits, err := c.InitService.GetInit(ctx.UserContext())
 	if err != nil {
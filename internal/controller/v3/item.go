@@ -6,20 +6,26 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/fx"
 
-	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/model"
 	"exusiai.dev/backend-next/internal/server/svr"
 	"exusiai.dev/backend-next/internal/service"
 	"exusiai.dev/backend-next/internal/util"
+	"exusiai.dev/backend-next/internal/util/rekuest"
 )
 
+var _ model.Item
+
 type ItemController struct {
 	fx.In
 
-	ItemService *service.Item
+	ItemService     *service.Item
+	ItemIconService *service.ItemIcon
 }
 
 func RegisterItem(v3 *svr.V3, c ItemController) {
 	v3.Get("/items", c.GetItems)
+	v3.Get("/items/search", c.SearchItems)
+	v3.Get("/items/icons", c.GetItemIcons)
 	v3.Get("/items/:itemId", buildSanitizer(util.NonNullString, util.IsInt), c.GetItemById)
 }
 
@@ -29,7 +35,7 @@ func buildSanitizer(sanitizer ...func(string) bool) func(ctx *fiber.Ctx) error {
 
 		for _, sanitizer := range sanitizer {
 			if !sanitizer(itemId) {
-				return pgerr.ErrInvalidReq.Msg("invalid or missing itemId")
+				return rekuest.Violation("itemId", rekuest.ViolationMalformed, "invalid or missing itemId")
 			}
 		}
 
@@ -37,6 +43,12 @@ func buildSanitizer(sanitizer ...func(string) bool) func(ctx *fiber.Ctx) error {
 	}
 }
 
+// @Summary	Get All Items
+// @Tags		Item
+// @Produce	json
+// @Success	200	{array}		model.Item
+// @Failure	500	{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router		/api/v3alpha/items [GET]
 func (c *ItemController) GetItems(ctx *fiber.Ctx) error {
 	items, err := c.ItemService.GetItems(ctx.UserContext())
 	if err != nil {
@@ -46,6 +58,58 @@ func (c *ItemController) GetItems(ctx *fiber.Ctx) error {
 	return ctx.JSON(items)
 }
 
+// @Summary		Search Items
+// @Description	Fuzzy match items by their localized names and community aliases, so that clients can resolve item names to item IDs. Best matches come first.
+// @Tags			Item
+// @Produce		json
+// @Param			q		query		string	true	"Search query, up to 64 characters"
+// @Param			limit	query		int		false	"Maximum number of results, from 1 to 50; default to 10"
+// @Success		200		{array}		model.ItemSearchResult
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/items/search [GET]
+func (c *ItemController) SearchItems(ctx *fiber.Ctx) error {
+	query := strings.TrimSpace(ctx.Query("q"))
+	if err := rekuest.ValidVar(ctx, "q", query, "required,max=64"); err != nil {
+		return err
+	}
+	limit := ctx.QueryInt("limit", 10)
+	if err := rekuest.ValidVar(ctx, "limit", limit, "min=1,max=50"); err != nil {
+		return err
+	}
+
+	results, err := c.ItemService.SearchItems(ctx.UserContext(), query, limit)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(results)
+}
+
+// @Summary		Get Item Icons
+// @Description	Get where the icons of the items are, on the sprite sheet or standalone. The version changes whenever any icon does, so that clients may use it to cache-bust.
+// @Tags			Item
+// @Produce		json
+// @Success		200	{object}	v3.ItemIcons
+// @Failure		500	{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/items/icons [GET]
+func (c *ItemController) GetItemIcons(ctx *fiber.Ctx) error {
+	icons, err := c.ItemIconService.GetItemIcons(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(icons)
+}
+
+// @Summary	Get an Item with ID
+// @Tags		Item
+// @Produce	json
+// @Param		itemId	path		string	true	"Item ID"
+// @Success	200		{object}	model.Item
+// @Failure	404		{object}	pgerr.Problem	"Item not found"
+// @Failure	500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router		/api/v3alpha/items/{itemId} [GET]
 func (c *ItemController) GetItemById(ctx *fiber.Ctx) error {
 	itemId := ctx.Params("itemId")
 
@@ -0,0 +1,47 @@
+package v3
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+var _ modelv3.Dummy
+
+type ItemValueController struct {
+	fx.In
+
+	ItemValueService *service.ItemValue
+}
+
+func RegisterItemValue(v3 *svr.V3, c ItemValueController) {
+	v3.Get("/item-values", c.GetItemValues)
+}
+
+// @Summary		Get Item Values
+// @Description	Get the sanity value of the items in a server, in every preset such as `market` for the prices in the shops or `efficiency` for the sanity spent on the most efficient stages to farm them. The values are maintained from the community calculators.
+// @Tags			Item
+// @Produce		json
+// @Param			server	query		string	true	"Server"	Enums(CN, US, JP, KR)
+// @Param			preset	query		string	false	"Only return the values of the preset"	example(market)
+// @Success		200		{array}		modelv3.ItemValueTable
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/item-values [GET]
+func (c *ItemValueController) GetItemValues(ctx *fiber.Ctx) error {
+	server := ctx.Query("server")
+	if err := rekuest.ValidServer(ctx, server); err != nil {
+		return err
+	}
+
+	tables, err := c.ItemValueService.GetItemValueTables(ctx.UserContext(), server, ctx.Query("preset"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(tables)
+}
@@ -0,0 +1,110 @@
+package v3
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/cachectrl"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+var (
+	_ modelv3.Dummy
+	_ pgerr.PenguinError
+)
+
+type MetaController struct {
+	fx.In
+
+	MetaBundleService *service.MetaBundle
+	UIConfigService   *service.UIConfig
+	FreshnessService  *service.Freshness
+}
+
+func RegisterMeta(v3 *svr.V3, c MetaController) {
+	group := v3.Group("/meta")
+	group.Get("/bundle", c.GetBundle)
+	group.Get("/ui-config", c.GetUIConfig)
+	group.Get("/freshness", c.GetFreshness)
+}
+
+// @Summary		Get Metadata Bundle
+// @Description	Get all items, stages and zones in one response, along with the version of them. Pass the version you have as `since` to receive only the items, stages and zones changed since then, or 304 when nothing has changed. The full bundle is responded when `since` is missing or too old.
+// @Tags			Meta
+// @Produce		json
+// @Param			since	query		int	false	"The version of the metadata bundle the client has"
+// @Success		200		{object}	modelv3.MetaBundle
+// @Success		304		"Nothing has changed since the given version"
+// @Failure		400		{object}	pgerr.Problem	"Invalid version"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/meta/bundle [GET]
+func (c *MetaController) GetBundle(ctx *fiber.Ctx) error {
+	var since int64
+	if s := ctx.Query("since"); s != "" {
+		var err error
+		if since, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return rekuest.Violation("since", rekuest.ViolationMalformed, "since must be an integer")
+		}
+	}
+
+	bundle, err := c.MetaBundleService.GetMetaBundleSince(ctx.UserContext(), since)
+	if err != nil {
+		return err
+	}
+	if bundle == nil {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
+
+	return ctx.JSON(bundle)
+}
+
+// @Summary		Get UI Config
+// @Description	Get what the frontend shows in a server: the zones and their stages existing in it, the events active or upcoming in it, and whether it is open. Pass the version you have as `version` to receive 304 when nothing has changed.
+// @Tags			Meta
+// @Produce		json
+// @Param			server	query		string	true	"Server"	Enums(CN, US, JP, KR)
+// @Param			version	query		string	false	"The version of the UI config the client has"
+// @Success		200		{object}	modelv3.UIConfig
+// @Success		304		"Nothing has changed since the given version"
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/meta/ui-config [GET]
+func (c *MetaController) GetUIConfig(ctx *fiber.Ctx) error {
+	server := ctx.Query("server")
+	if err := rekuest.ValidServer(ctx, server); err != nil {
+		return err
+	}
+
+	uiConfig, err := c.UIConfigService.GetUIConfigByServer(ctx.UserContext(), server)
+	if err != nil {
+		return err
+	}
+	if ctx.Query("version") == uiConfig.Version {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
+
+	cachectrl.OptIn(ctx, uiConfig.GeneratedAt)
+	return ctx.JSON(uiConfig)
+}
+
+// @Summary		Get Data Freshness
+// @Description	Get when the matrix, the trends and the pattern matrix of every server have last been refreshed, along with when the last report accepted in the server has been submitted, to detect stalled pipelines: reports being accepted while an aggregation is not refreshed for long tells the aggregation has stalled.
+// @Tags			Meta
+// @Produce		json
+// @Success		200	{object}	modelv3.Freshness
+// @Failure		500	{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/meta/freshness [GET]
+func (c *MetaController) GetFreshness(ctx *fiber.Ctx) error {
+	freshness, err := c.FreshnessService.GetFreshness(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(freshness)
+}
@@ -0,0 +1,48 @@
+package v3
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+var _ modelv3.Dummy
+
+type NoticeController struct {
+	fx.In
+
+	NoticeService *service.Notice
+}
+
+func RegisterNotice(v3 *svr.V3, c NoticeController) {
+	v3.Get("/notices", c.GetNotices)
+}
+
+// @Summary		Get Active Notices
+// @Description	Get the announcements to show now, such as maintenances or data issues, with their content in every language available. Notices are only returned within their display windows.
+// @Tags			Notice
+// @Produce		json
+// @Param			server	query		string	false	"Only return the notices shown in the server"	Enums(CN, US, JP, KR)
+// @Success		200		{array}		modelv3.Notice
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/notices [GET]
+func (c *NoticeController) GetNotices(ctx *fiber.Ctx) error {
+	server := ctx.Query("server")
+	if server != "" {
+		if err := rekuest.ValidServer(ctx, server); err != nil {
+			return err
+		}
+	}
+
+	notices, err := c.NoticeService.GetV3Notices(ctx.UserContext(), server)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(notices)
+}
@@ -0,0 +1,46 @@
+package v3
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+var _ modelv3.Dummy
+
+type RecognitionController struct {
+	fx.In
+
+	RecognitionStageHashService *service.RecognitionStageHash
+}
+
+func RegisterRecognition(v3 *svr.V3, c RecognitionController) {
+	v3.Get("/recognition/stage-hashes", c.GetRecognitionStageHashes)
+}
+
+// @Summary		Get Recognition Stage Hashes
+// @Description	Get the perceptual hashes of the result screens of the stages in a server, which the recognition clients identify the stages by. The version changes whenever any hash does, so that clients may only refetch the registry then.
+// @Tags			Recognition
+// @Produce		json
+// @Param			server	query		string	true	"Server"	Enums(CN, US, JP, KR)
+// @Success		200		{object}	modelv3.RecognitionStageHashes
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/recognition/stage-hashes [GET]
+func (c *RecognitionController) GetRecognitionStageHashes(ctx *fiber.Ctx) error {
+	server := ctx.Query("server")
+	if err := rekuest.ValidServer(ctx, server); err != nil {
+		return err
+	}
+
+	registry, err := c.RecognitionStageHashService.GetRecognitionStageHashesByServer(ctx.UserContext(), server)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(registry)
+}
@@ -0,0 +1,159 @@
+package v3
+
+import (
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/go-redsync/redsync/v4"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model/types"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/fiberstore"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/pkg/pgid"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util"
+)
+
+var (
+	_ modelv3.Dummy
+	_ pgerr.PenguinError
+)
+
+// reportIdempotencyRedisHashKey keeps the v3 responses apart from the v2 ones, as their bodies differ
+const reportIdempotencyRedisHashKey = constant.ReportIdempotencyRedisHashKey + ":v3"
+
+type ReportController struct {
+	fx.In
+
+	Redis         *redis.Client
+	RedSync       *redsync.Redsync
+	ReportService *service.Report
+
+	APIKeyService            *service.APIKey
+	RecognitionDefectService *service.RecognitionDefect
+	SubmissionPolicy         *service.SubmissionPolicy
+	SubmissionChallenge      *service.SubmissionChallenge
+	Tunables                 *service.Tunables
+}
+
+func RegisterReport(v3 *svr.V3, c ReportController) {
+	submissionPolicy := middlewares.SubmissionPolicy(c.SubmissionPolicy.Flag, func() int {
+		return c.Tunables.Int(service.TunableFlaggedReportRateLimit)
+	})
+
+	v3.Post("/report", submissionPolicy, middlewares.Idempotency(&middlewares.IdempotencyConfig{
+		Lifetime:  constant.ReportIdempotencyLifetime,
+		KeyHeader: constant.IdempotencyKeyHeader,
+		KeepResponseHeaders: []string{
+			fiber.HeaderContentType,
+			fiber.HeaderContentLength,
+			fiber.HeaderSetCookie,
+			constant.PenguinIDSetHeader,
+		},
+		Storage: fiberstore.NewRedis(c.Redis, reportIdempotencyRedisHashKey),
+		RedSync: c.RedSync,
+	}), middlewares.InjectValidBody[types.V3ReportRequest](), c.SubmitReport)
+	v3.Post("/report/challenge", middlewares.TunableLimiter(limiter.Config{
+		KeyGenerator: util.ExtractIP,
+		LimitReached: func(ctx *fiber.Ctx) error {
+			return pgerr.ErrTooManyRequests.Msg("your client is requesting challenges too frequently; please try again later")
+		},
+		Expiration: time.Minute * 5,
+	}, func() int {
+		return c.Tunables.Int(service.TunableChallengeRateLimit)
+	}), c.IssueSubmissionChallenge)
+	v3.Post("/report/recognition-defect",
+		middlewares.APIKeyAuthentication(c.APIKeyService.AuthenticateAPIKey),
+		middlewares.InjectValidBody[types.V3RecognitionDefectRequest](),
+		c.SubmitRecognitionDefect)
+}
+
+// @Summary		Submit a Drop Report
+// @Description	Submit a Drop Report with ark stage and item IDs. The report is rejected with an `INVALID_REPORT` error listing the `issues` found when the stage is not open, or when any drop is unknown, duplicated or not expected from the stage. Unusual drops that do not reject the report are responded as `warnings`. You can use the `reportHash` in the response to recall the report with the v2 API in 24 hours after it has been submitted.
+// @Tags			Report
+// @Accept			json
+// @Produce		json
+// @Param			report							body		types.V3ReportRequest	true	"Report request"
+// @Param			X-Penguin-Challenge-Solution	header		string					false	"The solution to a challenge as `<nonce>:<counter>`, required from the accounts that have never submitted a report should the challenges be enabled"
+// @Success		200		{object}	modelv3.ReportResponse	"Report has been successfully submitted"
+// @Header			200		{string}	X-Penguin-Challenge		"Set to `required` on the reports submitted from the networks flagged for having hosted spam, which are held to a stricter rate limit. The client shall present a challenge should it keep on submitting"
+// @Failure		400		{object}	modelv3.ReportRejection	"Report has been rejected"
+// @Failure		426		{object}	pgerr.Problem		"The version of the client is no longer accepted and has to be upgraded"
+// @Failure		428		{object}	pgerr.Problem		"The account has never submitted a report and a solved challenge is required, see /api/v3alpha/report/challenge"
+// @Failure		429		{object}	pgerr.Problem		"Too many reports submitted from a flagged network"
+// @Failure		500		{object}	pgerr.Problem		"An unexpected error occurred"
+// @Security		PenguinIDAuth
+// @Router			/api/v3alpha/report [POST]
+func (c *ReportController) SubmitReport(ctx *fiber.Ctx) error {
+	req := ctx.Locals("body").(types.V3ReportRequest)
+
+	accountId, createdPenguinId, err := c.ReportService.PipelineAccount(ctx.UserContext(), pgid.Extract(ctx))
+	if err != nil {
+		return err
+	}
+	if createdPenguinId != "" {
+		pgid.Inject(ctx, createdPenguinId)
+	}
+	if err := c.SubmissionChallenge.VerifySubmission(ctx.UserContext(), accountId, createdPenguinId != "", ctx.Get(middlewares.HeaderChallengeSolution)); err != nil {
+		ctx.Set(middlewares.HeaderChallenge, "required")
+		return err
+	}
+
+	origin := &types.ReportOrigin{
+		AccountID: accountId,
+		IP:        util.ExtractIP(ctx),
+		RequestID: middlewares.RequestIDFrom(ctx),
+	}
+	resp, err := c.ReportService.PreprocessAndQueueV3Report(ctx.UserContext(), origin, &req)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(resp)
+}
+
+// @Summary		Issue a Submission Challenge
+// @Description	Issue a proof of work challenge to solve before submitting a report. A counter is to be found such that the SHA-256 digest of `<nonce>:<counter>` starts with at least `difficulty` zero bits, and presented as `<nonce>:<counter>` in the `X-Penguin-Challenge-Solution` header of the submission before `expiresAt`. Each challenge can only be redeemed once. Only the accounts that have never submitted a report are required a challenge, and only while the challenges are enabled.
+// @Tags			Report
+// @Produce		json
+// @Success		200	{object}	modelv3.SubmissionChallenge	"Challenge to solve"
+// @Failure		429	{object}	pgerr.Problem				"Too many challenges requested"
+// @Failure		500	{object}	pgerr.Problem				"An unexpected error occurred"
+// @Router			/api/v3alpha/report/challenge [POST]
+func (c *ReportController) IssueSubmissionChallenge(ctx *fiber.Ctx) error {
+	challenge, err := c.SubmissionChallenge.IssueChallenge(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(challenge)
+}
+
+// @Summary		Submit a Recognition Defect
+// @Description	Upload a case the recognition of an official client was unsure about, for the algorithm team to triage. Only the metadata of the case is accepted: the recognition result must not exceed 64 KiB nor embed any image.
+// @Tags			Report
+// @Accept			json
+// @Produce		json
+// @Param			defect	body		types.V3RecognitionDefectRequest	true	"Recognition defect"
+// @Success		201		{object}	modelv3.RecognitionDefectReceipt	"Defect has been stored"
+// @Failure		400		{object}	pgerr.Problem					"Invalid request"
+// @Failure		401		"Missing or invalid API key"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Security		APIKeyAuth
+// @Router			/api/v3alpha/report/recognition-defect [POST]
+func (c *ReportController) SubmitRecognitionDefect(ctx *fiber.Ctx) error {
+	req := ctx.Locals("body").(types.V3RecognitionDefectRequest)
+
+	defect, err := c.RecognitionDefectService.SubmitDefect(ctx.UserContext(), middlewares.APIKeyFrom(ctx), &req)
+	if err != nil {
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(modelv3.RecognitionDefectReceipt{
+		DefectID: defect.DefectID,
+	})
+}
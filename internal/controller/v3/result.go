@@ -0,0 +1,169 @@
+package v3
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"go.uber.org/fx"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model/types"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/pkg/pgid"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+var (
+	_ modelv3.Dummy
+	_ pgerr.PenguinError
+)
+
+type ResultController struct {
+	fx.In
+
+	DropMatrixDeltaService   *service.DropMatrixDelta
+	PatternMatrixService     *service.PatternMatrix
+	TimesDistributionService *service.TimesDistribution
+	AdvancedQueryService     *service.AdvancedQuery
+	AccountService           *service.Account
+	Tunables                 *service.Tunables
+}
+
+func RegisterResult(v3 *svr.V3, c ResultController) {
+	group := v3.Group("/result")
+	group.Get("/matrix/:server/delta", c.GetDropMatrixDelta)
+	group.Get("/stage-drops/:server", c.GetStageDropStats)
+	group.Get("/times-distribution/:server/:stageId", c.GetTimesDistribution)
+	group.Post("/advanced", middlewares.TunableLimiter(limiter.Config{
+		LimitReached: func(ctx *fiber.Ctx) error {
+			return pgerr.ErrTooManyRequests.Msg("your client is sending advanced queries too frequently; please try again later")
+		},
+		Expiration: time.Minute * 5,
+	}, func() int {
+		return c.Tunables.Int(service.TunableAdvancedQueryRateLimit)
+	}), c.AdvancedQuery)
+}
+
+// @Summary		Get Drop Matrix Delta
+// @Description	Get the elements of the global drop matrix, including closed zones, that have changed since the given time. Pass the `until` of the previous response as `since` to keep a local copy of the matrix up to date; the whole matrix is responded when `since` is missing or older than the snapshots kept. Responds with 304 when the matrix has not been refreshed since then.
+// @Tags			Result
+// @Produce		json
+// @Param			server	path		string	true	"Server"	Enums(CN, US, JP, KR)
+// @Param			since	query		int		false	"Time of the matrix the client has, in milliseconds"
+// @Success		200		{object}	modelv3.DropMatrixDelta
+// @Success		304		"The matrix has not been refreshed since the given time"
+// @Failure		400		{object}	pgerr.Problem	"Invalid server or time"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/result/matrix/{server}/delta [GET]
+func (c *ResultController) GetDropMatrixDelta(ctx *fiber.Ctx) error {
+	server := ctx.Params("server")
+	if err := rekuest.ValidServer(ctx, server); err != nil {
+		return err
+	}
+
+	var since int64
+	if s := ctx.Query("since"); s != "" {
+		var err error
+		if since, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return rekuest.Violation("since", rekuest.ViolationMalformed, "since must be a timestamp in milliseconds")
+		}
+	}
+
+	delta, err := c.DropMatrixDeltaService.GetDropMatrixDelta(ctx.UserContext(), server, since)
+	if err != nil {
+		return err
+	}
+	if delta == nil {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
+
+	return ctx.JSON(delta)
+}
+
+// @Summary		Get Stage Drop Stats
+// @Description	Get the average and the standard deviation of the number of items dropped per run of each stage, computed from the global pattern matrix of all patterns, so that the variance of the drops of a run may be shown along with the drop rates of the drop matrix.
+// @Tags			Result
+// @Produce		json
+// @Param			server	path		string	true	"Server"	Enums(CN, US, JP, KR)
+// @Success		200		{object}	modelv3.StageDropStats
+// @Failure		400		{object}	pgerr.Problem	"Invalid server"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/result/stage-drops/{server} [GET]
+func (c *ResultController) GetStageDropStats(ctx *fiber.Ctx) error {
+	server := ctx.Params("server")
+	if err := rekuest.ValidServer(ctx, server); err != nil {
+		return err
+	}
+
+	stats, err := c.PatternMatrixService.GetStageDropStats(ctx.UserContext(), server)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(stats)
+}
+
+// @Summary		Get Times Distribution of a Stage
+// @Description	Get how many distinct accounts have reported the stage, and a histogram of the number of reliable reports per account in powers of two, as a hint of whether the sample of the stage is dominated by a few accounts. The distribution is refreshed hourly.
+// @Tags			Result
+// @Produce		json
+// @Param			server	path		string	true	"Server"	Enums(CN, US, JP, KR)
+// @Param			stageId	path		string	true	"Stage ID"
+// @Success		200		{object}	modelv3.TimesDistribution
+// @Failure		400		{object}	pgerr.Problem	"Invalid server"
+// @Failure		404		{object}	pgerr.Problem	"Stage not found"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/result/times-distribution/{server}/{stageId} [GET]
+func (c *ResultController) GetTimesDistribution(ctx *fiber.Ctx) error {
+	server := ctx.Params("server")
+	if err := rekuest.ValidServer(ctx, server); err != nil {
+		return err
+	}
+
+	distribution, err := c.TimesDistributionService.GetTimesDistribution(ctx.UserContext(), server, ctx.Params("stageId"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(distribution)
+}
+
+// @Summary		Execute Advanced Queries
+// @Description	Run a batch of drop matrix, trend and pattern matrix queries of custom time ranges concurrently. The results are keyed by the indices of the queries, each being either the result of the query, in the format of the v2 API, or the error it has failed with. Drop matrices with an `interval` are split into segments of the interval, aligned to the days of the server as trends are, the elements of each carrying the start and end of their segment. The queries not completed within the time budget of the batch fail with a `TIMEOUT` error, leaving the others intact. The queries estimated to be too costly, by the days spanned times the items filtered, are rejected with a `QUERY_TOO_COSTLY` error before being run. Personal queries require a PenguinID.
+// @Tags			Result
+// @Accept			json
+// @Produce		json
+// @Param			query	body		types.AdvancedQueryV3Request	true	"Queries"
+// @Success		200		{object}	modelv3.AdvancedQueryResult
+// @Failure		400		{object}	pgerr.Problem	"Invalid request, or PenguinID is missing or invalid for personal queries"
+// @Failure		429		{object}	pgerr.Problem	"Too many advanced queries sent"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Security		PenguinIDAuth
+// @Router			/api/v3alpha/result/advanced [POST]
+func (c *ResultController) AdvancedQuery(ctx *fiber.Ctx) error {
+	var request types.AdvancedQueryV3Request
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	accountId := null.NewInt(0, false)
+	for _, query := range request.Queries {
+		if !query.IsPersonal.Valid || !query.IsPersonal.Bool {
+			continue
+		}
+		account, err := c.AccountService.GetAccountFromPenguinId(ctx.UserContext(), pgid.Extract(ctx))
+		if err != nil {
+			return err
+		}
+		accountId = null.IntFrom(int64(account.AccountID))
+		break
+	}
+
+	return ctx.JSON(c.AdvancedQueryService.RunAdvancedQueries(ctx.UserContext(), request.Queries, accountId))
+}
@@ -0,0 +1,86 @@
+package v3
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model/types"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+var _ modelv3.Dummy
+
+type ShortLinkController struct {
+	fx.In
+
+	ShortLinkService *service.ShortLink
+	Tunables         *service.Tunables
+}
+
+func RegisterShortLink(v3 *svr.V3, c ShortLinkController) {
+	v3.Post("/shortlinks", middlewares.TunableLimiter(limiter.Config{
+		LimitReached: func(ctx *fiber.Ctx) error {
+			return pgerr.ErrTooManyRequests.Msg("your client is creating short links too frequently; please try again later")
+		},
+		Expiration: time.Minute * 5,
+	}, func() int {
+		return c.Tunables.Int(service.TunableShortLinkRateLimit)
+	}), c.CreateShortLink)
+	v3.Get("/shortlinks/:code", c.ResolveShortLink)
+}
+
+// @Summary		Create Short Link
+// @Description	Store an advanced query under a short code, so that the matrices and trends filtered by it can be shared as a permalink. The stages and items queried must exist, and the query is normalized before being stored, so that sharing the same query again returns the same code with its expiry extended. Creations are rate limited per client.
+// @Tags			ShortLink
+// @Accept			json
+// @Produce		json
+// @Param			query	body		types.AdvancedQueryRequest	true	"Advanced query to share"
+// @Success		201		{object}	modelv3.ShortLink
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		429		{object}	pgerr.Problem	"Too many short links created"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/shortlinks [POST]
+func (c *ShortLinkController) CreateShortLink(ctx *fiber.Ctx) error {
+	var request types.AdvancedQueryRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	link, err := c.ShortLinkService.CreateShortLink(ctx.UserContext(), &request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(link)
+}
+
+// @Summary		Resolve Short Link
+// @Description	Get the advanced query stored under a short code, to be sent as is to the advanced query API. Expired links are not found.
+// @Tags			ShortLink
+// @Produce		json
+// @Param			code	path		string	true	"Code of the short link"
+// @Success		200		{object}	modelv3.ShortLink
+// @Failure		400		{object}	pgerr.Problem	"Invalid request, or no short link found under the code"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/shortlinks/{code} [GET]
+func (c *ShortLinkController) ResolveShortLink(ctx *fiber.Ctx) error {
+	code := ctx.Params("code")
+	if err := rekuest.ValidVar(ctx, "code", code, "required,max=32"); err != nil {
+		return err
+	}
+
+	link, err := c.ShortLinkService.ResolveShortLink(ctx.UserContext(), code)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(link)
+}
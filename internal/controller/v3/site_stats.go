@@ -0,0 +1,74 @@
+package v3
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+var (
+	_ modelv3.Dummy
+	_ pgerr.PenguinError
+)
+
+type SiteStatsController struct {
+	fx.In
+
+	SiteCounterService *service.SiteCounter
+}
+
+func RegisterSiteStats(v3 *svr.V3, c SiteStatsController) {
+	v3.Get("/stats/site", c.GetSiteStats)
+	v3.Get("/stats/heatmap/:server", c.GetReportHeatmap)
+}
+
+// @Summary		Get Site Stats
+// @Description	Get the headline numbers of the site, with a per-server breakdown.
+// @Tags			SiteStats
+// @Produce		json
+// @Success		200	{object}	modelv3.SiteStats
+// @Failure		500	{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/stats/site [GET]
+func (c *SiteStatsController) GetSiteStats(ctx *fiber.Ctx) error {
+	stats, err := c.SiteCounterService.GetSiteStats(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(stats)
+}
+
+// @Summary		Get Report Heatmap
+// @Description	Get the number of reports accepted in the server within the last days, by the weekday and the hour they were submitted at in the time zone of the server. The current hour is left out as it is incomplete.
+// @Tags			SiteStats
+// @Produce		json
+// @Param			server	path		string	true	"Server"	Enums(CN, US, JP, KR)
+// @Param			days	query		int		false	"Number of recent days to include, up to 90; default to 28"
+// @Success		200		{object}	modelv3.ReportHeatmap
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/stats/heatmap/{server} [GET]
+func (c *SiteStatsController) GetReportHeatmap(ctx *fiber.Ctx) error {
+	server := ctx.Params("server")
+	if err := rekuest.ValidServer(ctx, server); err != nil {
+		return err
+	}
+	days := ctx.QueryInt("days", 28)
+	if err := rekuest.ValidVar(ctx, "days", days, "min=1,max="+strconv.Itoa(service.ReportHeatmapMaxDays)); err != nil {
+		return err
+	}
+
+	heatmap, err := c.SiteCounterService.GetReportHeatmap(ctx.UserContext(), server, days)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(heatmap)
+}
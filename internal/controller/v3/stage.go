@@ -4,30 +4,79 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/fx"
 
+	"exusiai.dev/backend-next/internal/model"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
 	"exusiai.dev/backend-next/internal/server/svr"
 	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+var (
+	_ model.Stage
+	_ modelv3.Dummy
 )
 
 type StageController struct {
 	fx.In
 
-	StageService *service.Stage
+	StageService          *service.Stage
+	DropInfoService       *service.DropInfo
+	SampleForecastService *service.SampleForecast
 }
 
 func RegisterStage(v3 *svr.V3, c StageController) {
 	v3.Get("/stages", c.GetStages)
 	v3.Get("/stages/:stageId", c.GetStageById)
+	v3.Get("/stages/:stageId/dropinfos", c.GetStageDropInfos)
+	v3.Get("/stages/:stageId/forecast", c.GetStageSampleForecast)
 }
 
+// @Summary		Get All Stages
+// @Description	Get all stages. When `server` is given, only the stages existing in that server are listed, along with whether they are open right now and the time range of their drop infos currently in effect; `open=true` further limits the list to open stages.
+// @Tags			Stage
+// @Produce		json
+// @Param			server	query		string	false	"Server"	Enums(CN, US, JP, KR)
+// @Param			open	query		bool	false	"Only list open stages; requires `server`"
+// @Success		200		{array}		modelv3.StageListing
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/stages [GET]
 func (c *StageController) GetStages(ctx *fiber.Ctx) error {
-	stages, err := c.StageService.GetStages(ctx.UserContext())
+	server := ctx.Query("server")
+	openOnly := ctx.QueryBool("open")
+	if server == "" {
+		if openOnly {
+			return rekuest.Violation("open", rekuest.ViolationDependency, "`open` requires `server` to be specified")
+		}
+
+		stages, err := c.StageService.GetStages(ctx.UserContext())
+		if err != nil {
+			return err
+		}
+
+		return ctx.JSON(stages)
+	}
+
+	if err := rekuest.ValidServer(ctx, server); err != nil {
+		return err
+	}
+
+	listings, err := c.StageService.GetStageListingsByServer(ctx.UserContext(), server, openOnly)
 	if err != nil {
 		return err
 	}
 
-	return ctx.JSON(stages)
+	return ctx.JSON(listings)
 }
 
+// @Summary	Get a Stage with ID
+// @Tags		Stage
+// @Produce	json
+// @Param		stageId	path		string	true	"Stage ID"
+// @Success	200		{object}	model.Stage
+// @Failure	404		{object}	pgerr.Problem	"Stage not found"
+// @Failure	500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router		/api/v3alpha/stages/{stageId} [GET]
 func (c *StageController) GetStageById(ctx *fiber.Ctx) error {
 	stageId := ctx.Params("stageId")
 
@@ -38,3 +87,58 @@ func (c *StageController) GetStageById(ctx *fiber.Ctx) error {
 
 	return ctx.JSON(stage)
 }
+
+// @Summary		Get Drop Infos of a Stage
+// @Description	Get the drop set, drop types and bounds of a stage per time range, latest first, so that recognition clients are able to validate results locally before submitting them.
+// @Tags			Stage
+// @Produce		json
+// @Param			stageId	path		string	true	"Stage ID"
+// @Param			server	query		string	false	"Server; default to CN"	Enums(CN, US, JP, KR)
+// @Success		200		{object}	modelv3.StageDropInfos
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		404		{object}	pgerr.Problem	"Stage not found"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/stages/{stageId}/dropinfos [GET]
+func (c *StageController) GetStageDropInfos(ctx *fiber.Ctx) error {
+	server := ctx.Query("server", "CN")
+	if err := rekuest.ValidServer(ctx, server); err != nil {
+		return err
+	}
+
+	dropInfos, err := c.DropInfoService.GetStageDropInfos(ctx.UserContext(), server, ctx.Params("stageId"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(dropInfos)
+}
+
+// @Summary		Get Sample Forecast of a Stage
+// @Description	Estimate when the sample of a stage in its time range currently in effect reaches `target` times, by extrapolating the rate the stage has been reported at over a rolling window. `estimatedAt` is null when the target has been reached already, no reports have come in over the window, or the time range is estimated to end before then.
+// @Tags			Stage
+// @Produce		json
+// @Param			stageId	path		string	true	"Stage ID"
+// @Param			server	query		string	false	"Server; default to CN"	Enums(CN, US, JP, KR)
+// @Param			target	query		int		false	"Number of times to forecast; default to the configured target"
+// @Success		200		{object}	modelv3.SampleForecast
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		404		{object}	pgerr.Problem	"Stage not found, or has no drop infos in effect in the server"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/stages/{stageId}/forecast [GET]
+func (c *StageController) GetStageSampleForecast(ctx *fiber.Ctx) error {
+	server := ctx.Query("server", "CN")
+	if err := rekuest.ValidServer(ctx, server); err != nil {
+		return err
+	}
+	target := ctx.QueryInt("target")
+	if err := rekuest.ValidVar(ctx, "target", target, "min=0"); err != nil {
+		return err
+	}
+
+	forecast, err := c.SampleForecastService.GetSampleForecast(ctx.UserContext(), server, ctx.Params("stageId"), target)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(forecast)
+}
@@ -0,0 +1,81 @@
+package v3
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model/types"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+var _ modelv3.Dummy
+
+type ToolController struct {
+	fx.In
+
+	SimulationService *service.Simulation
+	PlannerService    *service.Planner
+}
+
+func RegisterTool(v3 *svr.V3, c ToolController) {
+	group := v3.Group("/tools")
+	group.Get("/simulate", c.Simulate)
+	group.Post("/plan", c.Plan)
+}
+
+// @Summary		Simulate Drops
+// @Description	Get the expected quantity of each item dropped from clearing a stage a number of times, along with its variance, from the global drop matrix of the stage in its latest time range. When the pattern matrix of the stage is available, the probability of each pattern and of each item dropping at least once are given too. The runs are assumed to be independent.
+// @Tags			Tool
+// @Produce		json
+// @Param			stageId	query		string	true	"Stage ID"	example(main_01-07)
+// @Param			runs	query		int		true	"Number of runs, up to 1000000"
+// @Param			server	query		string	false	"Server; default to CN"	Enums(CN, US, JP, KR)
+// @Success		200		{object}	modelv3.Simulation
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		404		{object}	pgerr.Problem	"Stage not found, or has no drop matrix in the server"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/tools/simulate [GET]
+func (c *ToolController) Simulate(ctx *fiber.Ctx) error {
+	server := ctx.Query("server", "CN")
+	if err := rekuest.ValidServer(ctx, server); err != nil {
+		return err
+	}
+	stageId := ctx.Query("stageId")
+	if err := rekuest.ValidVar(ctx, "stageId", stageId, "required"); err != nil {
+		return err
+	}
+
+	simulation, err := c.SimulationService.Simulate(ctx.UserContext(), server, stageId, ctx.QueryInt("runs"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(simulation)
+}
+
+// @Summary		Plan Farming
+// @Description	Plan the runs of the open stages spending the least sanity for the requested items to be expected to drop, by solving a linear program over the sanity of the stages and their drop rates in the global drop matrix. Only the stages reported enough times are planned on; the items none of them drops are listed as `unreachable`.
+// @Tags			Tool
+// @Accept		json
+// @Produce		json
+// @Param			request	body		types.PlanRequest	true	"Items to farm"
+// @Success		200		{object}	modelv3.Plan
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/tools/plan [POST]
+func (c *ToolController) Plan(ctx *fiber.Ctx) error {
+	var request types.PlanRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	plan, err := c.PlannerService.Plan(ctx.UserContext(), &request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(plan)
+}
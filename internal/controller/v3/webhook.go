@@ -0,0 +1,124 @@
+package v3
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
+	"exusiai.dev/backend-next/internal/server/svr"
+	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+var (
+	_ model.Webhook
+	_ types.CreateWebhookRequest
+)
+
+type WebhookController struct {
+	fx.In
+
+	APIKeyService  *service.APIKey
+	WebhookService *service.Webhook
+}
+
+func RegisterWebhook(v3 *svr.V3, c WebhookController) {
+	group := v3.Group("/webhooks", middlewares.APIKeyAuthentication(c.APIKeyService.AuthenticateAPIKey))
+	group.Get("/", c.GetWebhooks)
+	group.Post("/", c.CreateWebhook)
+	group.Delete("/:webhookId", c.DeleteWebhook)
+	group.Post("/:webhookId/ping", c.PingWebhook)
+}
+
+// @Summary		Get Webhooks
+// @Description	Get the webhooks subscribed with the API key. Secrets are not included.
+// @Tags			Webhook
+// @Produce		json
+// @Success		200	{array}		model.Webhook
+// @Failure		401	"Missing or invalid API key"
+// @Failure		500	{object}	pgerr.Problem	"An unexpected error occurred"
+// @Security		APIKeyAuth
+// @Router			/api/v3alpha/webhooks [GET]
+func (c *WebhookController) GetWebhooks(ctx *fiber.Ctx) error {
+	webhooks, err := c.WebhookService.GetWebhooks(ctx.UserContext(), middlewares.APIKeyFrom(ctx))
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(webhooks)
+}
+
+// @Summary		Create Webhook
+// @Description	Subscribe a webhook to events. Events are POSTed as JSON, signed in the X-Penguin-Signature header
+// @Description	as `t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>" keyed with the secret>`. The secret is only
+// @Description	included in this response. Failed deliveries (non-2xx or timeouts) are retried with exponential backoff.
+// @Tags			Webhook
+// @Accept			json
+// @Produce		json
+// @Param			request	body		types.CreateWebhookRequest	true	"Webhook"
+// @Success		201		{object}	model.Webhook
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		401		"Missing or invalid API key"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Security		APIKeyAuth
+// @Router			/api/v3alpha/webhooks [POST]
+func (c *WebhookController) CreateWebhook(ctx *fiber.Ctx) error {
+	var request types.CreateWebhookRequest
+	if err := rekuest.ValidBody(ctx, &request); err != nil {
+		return err
+	}
+
+	webhook, err := c.WebhookService.CreateWebhook(ctx.UserContext(), middlewares.APIKeyFrom(ctx), &request)
+	if err != nil {
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(webhook)
+}
+
+// @Summary	Delete Webhook
+// @Tags		Webhook
+// @Param		webhookId	path	int	true	"Webhook ID"
+// @Success	204
+// @Failure	400	{object}	pgerr.Problem	"Invalid request or webhook not found"
+// @Failure	401	"Missing or invalid API key"
+// @Failure	500	{object}	pgerr.Problem	"An unexpected error occurred"
+// @Security	APIKeyAuth
+// @Router		/api/v3alpha/webhooks/{webhookId} [DELETE]
+func (c *WebhookController) DeleteWebhook(ctx *fiber.Ctx) error {
+	webhookId, err := strconv.Atoi(ctx.Params("webhookId"))
+	if err != nil {
+		return rekuest.Violation("webhookId", rekuest.ViolationMalformed, "webhookId must be an integer")
+	}
+
+	if err := c.WebhookService.DeleteWebhook(ctx.UserContext(), middlewares.APIKeyFrom(ctx), webhookId); err != nil {
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// @Summary		Ping Webhook
+// @Description	Deliver a `ping` event to the webhook, to test the receiving endpoint.
+// @Tags			Webhook
+// @Produce		json
+// @Param			webhookId	path		int	true	"Webhook ID"
+// @Success		202			{object}	model.WebhookEvent
+// @Failure		400			{object}	pgerr.Problem	"Invalid request or webhook not found"
+// @Failure		401			"Missing or invalid API key"
+// @Failure		500			{object}	pgerr.Problem	"An unexpected error occurred"
+// @Security		APIKeyAuth
+// @Router			/api/v3alpha/webhooks/{webhookId}/ping [POST]
+func (c *WebhookController) PingWebhook(ctx *fiber.Ctx) error {
+	webhookId, err := strconv.Atoi(ctx.Params("webhookId"))
+	if err != nil {
+		return rekuest.Violation("webhookId", rekuest.ViolationMalformed, "webhookId must be an integer")
+	}
+
+	event, err := c.WebhookService.Ping(ctx.UserContext(), middlewares.APIKeyFrom(ctx), webhookId)
+	if err != nil {
+		return err
+	}
+	return ctx.Status(fiber.StatusAccepted).JSON(event)
+}
@@ -4,8 +4,16 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/fx"
 
+	"exusiai.dev/backend-next/internal/model"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
 	"exusiai.dev/backend-next/internal/server/svr"
 	"exusiai.dev/backend-next/internal/service"
+	"exusiai.dev/backend-next/internal/util/rekuest"
+)
+
+var (
+	_ model.Zone
+	_ modelv3.Dummy
 )
 
 type ZoneController struct {
@@ -19,15 +27,52 @@ func RegisterZone(v3 *svr.V3, c ZoneController) {
 	v3.Get("/zones/:zoneId", c.GetZoneById)
 }
 
+// @Summary		Get All Zones
+// @Description	Get all zones. When `server` is given, only the zones existing in that server are listed, along with whether they are open right now; `open=true` further limits the list to open zones.
+// @Tags			Zone
+// @Produce		json
+// @Param			server	query		string	false	"Server"	Enums(CN, US, JP, KR)
+// @Param			open	query		bool	false	"Only list open zones; requires `server`"
+// @Success		200		{array}		modelv3.ZoneListing
+// @Failure		400		{object}	pgerr.Problem	"Invalid request"
+// @Failure		500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router			/api/v3alpha/zones [GET]
 func (c *ZoneController) GetZones(ctx *fiber.Ctx) error {
-	zones, err := c.ZoneService.GetZones(ctx.UserContext())
+	server := ctx.Query("server")
+	openOnly := ctx.QueryBool("open")
+	if server == "" {
+		if openOnly {
+			return rekuest.Violation("open", rekuest.ViolationDependency, "`open` requires `server` to be specified")
+		}
+
+		zones, err := c.ZoneService.GetZones(ctx.UserContext())
+		if err != nil {
+			return err
+		}
+
+		return ctx.JSON(zones)
+	}
+
+	if err := rekuest.ValidServer(ctx, server); err != nil {
+		return err
+	}
+
+	listings, err := c.ZoneService.GetZoneListingsByServer(ctx.UserContext(), server, openOnly)
 	if err != nil {
 		return err
 	}
 
-	return ctx.JSON(zones)
+	return ctx.JSON(listings)
 }
 
+// @Summary	Get a Zone with ID
+// @Tags		Zone
+// @Produce	json
+// @Param		zoneId	path		string	true	"Zone ID"
+// @Success	200		{object}	model.Zone
+// @Failure	404		{object}	pgerr.Problem	"Zone not found"
+// @Failure	500		{object}	pgerr.Problem	"An unexpected error occurred"
+// @Router		/api/v3alpha/zones/{zoneId} [GET]
 func (c *ZoneController) GetZoneById(ctx *fiber.Ctx) error {
 	zoneId := ctx.Params("zoneId")
 
@@ -7,7 +7,11 @@ func Module() fx.Option {
 		NATS,
 		Redis,
 		RedSync,
+		DBBreaker,
 		Postgres,
+		PostgresPools,
 		GeoIPDatabase,
+		GeoIPASNDatabase,
+		S3,
 	), fx.Invoke(Datadog))
 }
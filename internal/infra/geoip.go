@@ -17,3 +17,16 @@ func GeoIPDatabase(conf *appconfig.Config) (*geoip2.Reader, error) {
 
 	return db, nil
 }
+
+// GeoIPASNDatabase opens the ASN database at GeoIPASNDatabasePath, unless none is configured
+func GeoIPASNDatabase(conf *appconfig.Config) (*geoip.ASNDatabase, error) {
+	if conf.GeoIPASNDatabasePath == "" {
+		return &geoip.ASNDatabase{}, nil
+	}
+	db, err := geoip2.Open(conf.GeoIPASNDatabasePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &geoip.ASNDatabase{Reader: db}, nil
+}
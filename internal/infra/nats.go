@@ -49,5 +49,21 @@ func NATS(conf *appconfig.Config) (*nats.Conn, nats.JetStreamContext, error) {
 		log.Warn().Err(err).Msg("infra: nats: failed to create jetstream stream: is it already created?")
 	}
 
+	// events are published by every instance that observes them, and are deduplicated by their message id
+	_, err = js.AddStream(&nats.StreamConfig{
+		Name: "penguin-webhooks",
+		Subjects: []string{
+			"WEBHOOK.*",
+		},
+		Retention:  nats.WorkQueuePolicy,
+		Discard:    nats.DiscardOld,
+		Storage:    nats.FileStorage,
+		Replicas:   1,
+		Duplicates: time.Minute * 10,
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("infra: nats: failed to create jetstream stream: is it already created?")
+	}
+
 	return nc, js, nil
 }
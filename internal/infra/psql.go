@@ -14,17 +14,60 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 
 	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/pkg/breaker"
+	"exusiai.dev/backend-next/internal/pkg/observability"
+	"exusiai.dev/backend-next/internal/pkg/pgpool"
 )
 
-func Postgres(conf *appconfig.Config) (*bun.DB, error) {
+func Postgres(conf *appconfig.Config, dbBreaker *breaker.Breaker) (*bun.DB, error) {
+	db, err := openPostgres(conf, "penguin-backend", conf.PostgresMaxOpenConns, conf.PostgresMaxIdleConns)
+	if err != nil {
+		return nil, err
+	}
+	// only the pool of the requests feeds the circuit, as the background jobs are expected to run long
+	db.AddQueryHook(&breaker.QueryHook{Breaker: dbBreaker})
+	return db, nil
+}
+
+// DBBreaker is the circuit around the database opened by the latency of the queries of the requests
+func DBBreaker(conf *appconfig.Config) *breaker.Breaker {
+	return breaker.New(breaker.Config{
+		SlowThreshold: conf.DBCircuitSlowThreshold,
+		SlowRatio:     conf.DBCircuitSlowRatio,
+		MinQueries:    conf.DBCircuitMinQueries,
+		Window:        conf.DBCircuitWindow,
+		Cooldown:      conf.DBCircuitCooldown,
+	})
+}
+
+// PostgresPools opens the separate pool of the background jobs alongside db, unless its size is configured to be 0
+func PostgresPools(conf *appconfig.Config, db *bun.DB) (*pgpool.Pools, error) {
+	pools := &pgpool.Pools{Interactive: db, Background: db}
+	if conf.PostgresBackgroundMaxOpenConns <= 0 {
+		return pools, nil
+	}
+	// the application name tells the connections of the pools apart in pg_stat_activity
+	background, err := openPostgres(conf, "penguin-backend-background", conf.PostgresBackgroundMaxOpenConns, conf.PostgresBackgroundMaxIdleConns)
+	if err != nil {
+		return nil, err
+	}
+	pools.Background = background
+	return pools, nil
+}
+
+func openPostgres(conf *appconfig.Config, applicationName string, maxOpenConns int, maxIdleConns int) (*bun.DB, error) {
 	// Open a Postgres database.
-	pgdb := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(conf.PostgresDSN), pgdriver.WithApplicationName("penguin-backend")))
+	pgdb := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(conf.PostgresDSN), pgdriver.WithApplicationName(applicationName)))
 
 	// Create a Bun db on top of it.
 	db := bun.NewDB(pgdb, pgdialect.New())
 	if conf.DevMode {
 		db.AddQueryHook(bundebug.NewQueryHook(bundebug.WithEnabled(true), bundebug.WithVerbose(conf.BunDebugVerbose), bundebug.WithWriter(log.Logger)))
 	}
+	db.AddQueryHook(&observability.QueryHook{})
+	if conf.PostgresSlowQueryThreshold > 0 {
+		db.AddQueryHook(&observability.SlowQueryHook{Threshold: conf.PostgresSlowQueryThreshold, Explain: conf.PostgresSlowQueryExplain})
+	}
 	if conf.TracingEnabled {
 		db.AddQueryHook(bunotel.NewQueryHook(bunotel.WithDBName("penguin-postgres"), bunotel.WithAttributes(semconv.DBSystemPostgreSQL)))
 	}
@@ -33,12 +76,12 @@ func Postgres(conf *appconfig.Config) (*bun.DB, error) {
 	defer cancel()
 
 	if err := db.PingContext(ctx); err != nil {
-		log.Error().Err(err).Msg("infra: postgres: failed to ping database")
+		log.Error().Err(err).Str("application_name", applicationName).Msg("infra: postgres: failed to ping database")
 		return nil, err
 	}
 
-	pgdb.SetMaxOpenConns(conf.PostgresMaxOpenConns)
-	pgdb.SetMaxIdleConns(conf.PostgresMaxIdleConns)
+	pgdb.SetMaxOpenConns(maxOpenConns)
+	pgdb.SetMaxIdleConns(maxIdleConns)
 	pgdb.SetConnMaxLifetime(conf.PostgresConnMaxLifeTime)
 	pgdb.SetConnMaxIdleTime(conf.PostgresConnMaxIdleTime)
 
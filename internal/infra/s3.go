@@ -0,0 +1,24 @@
+package infra
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/pkg/errors"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+)
+
+// S3 is the client shared by the drop report archive and the item icons
+func S3(conf *appconfig.Config) (*s3.Client, error) {
+	cfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithRegion(conf.DropReportArchiveS3Region),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(conf.AWSAccessKey, conf.AWSSecretKey, "")),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load aws config")
+	}
+	return s3.NewFromConfig(cfg), nil
+}
@@ -0,0 +1,5 @@
+DROP INDEX CONCURRENTLY IF EXISTS drop_reports_server_stage_id_created_at_reliability_idx;
+
+--bun:split
+
+DROP INDEX CONCURRENTLY IF EXISTS drop_pattern_elements_drop_pattern_id_item_id_quantity_idx;
@@ -0,0 +1,11 @@
+-- the drop matrix and pattern matrix aggregations filter drop_reports by server, stage and time, and only count the
+-- reliable reports. The indexes are built concurrently so as not to block the reports being written meanwhile; the
+-- partitioning of drop_reports afterwards recreates the index on every partition.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS drop_reports_server_stage_id_created_at_reliability_idx
+ON drop_reports (server, stage_id, created_at, reliability);
+
+--bun:split
+
+-- the aggregations join the elements of the patterns of the reports, of which only item_id and quantity are read
+CREATE INDEX CONCURRENTLY IF NOT EXISTS drop_pattern_elements_drop_pattern_id_item_id_quantity_idx
+ON drop_pattern_elements (drop_pattern_id, item_id, quantity);
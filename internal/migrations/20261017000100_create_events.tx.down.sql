@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS event_schedules;
+
+--bun:split
+
+DROP TABLE IF EXISTS events;
@@ -0,0 +1,21 @@
+-- an event groups the zones released together, and is announced to open and close at different times per server;
+-- the time ranges and the existence of the zones and their stages in each server are derived from its schedules
+CREATE TABLE IF NOT EXISTS events (
+    event_id   SERIAL PRIMARY KEY,
+    name       TEXT        NOT NULL,
+    zone_ids   INTEGER[]   NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS event_schedules (
+    event_id   INTEGER     NOT NULL REFERENCES events (event_id) ON DELETE CASCADE,
+    server     TEXT        NOT NULL,
+    open_time  TIMESTAMPTZ NOT NULL,
+    close_time TIMESTAMPTZ,
+    -- range_id is the time range derived from the schedule, kept when the schedule is updated
+    range_id   INTEGER REFERENCES time_ranges (range_id),
+    PRIMARY KEY (event_id, server)
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS recognition_defects_api_key_id_created_at_idx;
+
+--bun:split
+
+ALTER TABLE recognition_defects DROP COLUMN IF EXISTS api_key_id;
@@ -0,0 +1,7 @@
+-- the defects uploaded by official recognition clients through the v3 API are attributed to their API keys
+ALTER TABLE recognition_defects ADD COLUMN IF NOT EXISTS api_key_id INTEGER REFERENCES api_keys (api_key_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS recognition_defects_api_key_id_created_at_idx
+ON recognition_defects (api_key_id, created_at);
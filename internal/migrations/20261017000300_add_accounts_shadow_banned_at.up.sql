@@ -0,0 +1,2 @@
+-- the reports of shadow-banned accounts are accepted, but excluded from the aggregations
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS shadow_banned_at TIMESTAMPTZ;
@@ -0,0 +1,18 @@
+-- an exclusion rule excludes the accepted reports matching all of its criteria from the aggregations, without
+-- changing the reports themselves; a criterion left null matches every report
+CREATE TABLE IF NOT EXISTS exclusion_rules (
+    rule_id         SERIAL PRIMARY KEY,
+    name            TEXT        NOT NULL,
+    comment         TEXT,
+    enabled         BOOLEAN     NOT NULL DEFAULT TRUE,
+    server          TEXT,
+    account_id      INTEGER,
+    ip_range        CIDR,
+    source_name     TEXT,
+    -- version_pattern is matched against the version of the reports with LIKE
+    version_pattern TEXT,
+    start_time      TIMESTAMPTZ,
+    end_time        TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
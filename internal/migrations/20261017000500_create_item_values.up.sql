@@ -0,0 +1,9 @@
+-- the sanity value of the items per server, in presets such as those of the community calculators
+CREATE TABLE IF NOT EXISTS item_values (
+    preset     TEXT             NOT NULL,
+    server     TEXT             NOT NULL,
+    item_id    INTEGER          NOT NULL REFERENCES items (item_id) ON DELETE CASCADE,
+    value      DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (preset, server, item_id)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE accounts DROP COLUMN IF EXISTS leaderboard_name;
+
+--bun:split
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS leaderboard_opt_in;
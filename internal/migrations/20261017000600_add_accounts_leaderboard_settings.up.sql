@@ -0,0 +1,6 @@
+-- the accounts only appear on the contributor leaderboards once opted in, under the name of their choice
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS leaderboard_opt_in BOOLEAN NOT NULL DEFAULT FALSE;
+
+--bun:split
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS leaderboard_name TEXT;
@@ -0,0 +1,9 @@
+-- the last snapshot of the global drop matrix of each day, for the matrix to be queried as of a past date
+CREATE TABLE IF NOT EXISTS drop_matrix_history (
+    server      TEXT        NOT NULL,
+    day_num     INTEGER     NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- gzipped JSON of the matrix in the v2 format, including the closed zones
+    content     BYTEA       NOT NULL,
+    PRIMARY KEY (server, day_num)
+);
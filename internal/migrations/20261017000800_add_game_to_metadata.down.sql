@@ -0,0 +1,9 @@
+ALTER TABLE items DROP COLUMN IF EXISTS game;
+
+--bun:split
+
+ALTER TABLE zones DROP COLUMN IF EXISTS game;
+
+--bun:split
+
+ALTER TABLE stages DROP COLUMN IF EXISTS game;
@@ -0,0 +1,11 @@
+-- the game of the metadata, for the statistics of another game to be hosted alongside. The ark ids remain unique
+-- across the games until another game is onboarded.
+ALTER TABLE items ADD COLUMN IF NOT EXISTS game TEXT NOT NULL DEFAULT 'arknights';
+
+--bun:split
+
+ALTER TABLE zones ADD COLUMN IF NOT EXISTS game TEXT NOT NULL DEFAULT 'arknights';
+
+--bun:split
+
+ALTER TABLE stages ADD COLUMN IF NOT EXISTS game TEXT NOT NULL DEFAULT 'arknights';
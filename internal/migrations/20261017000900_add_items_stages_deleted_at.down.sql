@@ -0,0 +1,5 @@
+ALTER TABLE items DROP COLUMN IF EXISTS deleted_at;
+
+--bun:split
+
+ALTER TABLE stages DROP COLUMN IF EXISTS deleted_at;
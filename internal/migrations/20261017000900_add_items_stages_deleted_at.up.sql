@@ -0,0 +1,6 @@
+-- the items and stages are soft deleted, so that deleting one by accident orphans none of its drop reports
+ALTER TABLE items ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+--bun:split
+
+ALTER TABLE stages ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
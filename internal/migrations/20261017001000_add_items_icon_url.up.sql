@@ -0,0 +1,2 @@
+-- the standalone icons of the items, apart from their sprites on the sprite sheet
+ALTER TABLE items ADD COLUMN IF NOT EXISTS icon_url TEXT;
@@ -0,0 +1,14 @@
+-- the perceptual hashes of the result screens of the stages, for the recognition clients to identify the stages by
+CREATE TABLE IF NOT EXISTS recognition_stage_hashes (
+    hash_id    SERIAL PRIMARY KEY,
+    server     TEXT        NOT NULL,
+    stage_id   INTEGER     NOT NULL,
+    hash       TEXT        NOT NULL,
+    comment    TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+--bun:split
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recognition_stage_hashes_server_hash ON recognition_stage_hashes (server, hash);
@@ -0,0 +1,12 @@
+-- the versions of the clients, by the source names of their reports, which are allowed to submit reports
+CREATE TABLE IF NOT EXISTS client_versions (
+    source           TEXT PRIMARY KEY,
+    -- min_version is the lowest semantic version accepted; null accepts every version
+    min_version      TEXT,
+    blocked_versions TEXT[]      NOT NULL DEFAULT '{}',
+    -- disabled rejects every version of the client, as a kill switch
+    disabled         BOOLEAN     NOT NULL DEFAULT FALSE,
+    message          TEXT,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
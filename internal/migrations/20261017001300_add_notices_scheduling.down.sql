@@ -0,0 +1,13 @@
+ALTER TABLE notices DROP COLUMN IF EXISTS kind;
+
+--bun:split
+
+ALTER TABLE notices DROP COLUMN IF EXISTS start_time;
+
+--bun:split
+
+ALTER TABLE notices DROP COLUMN IF EXISTS end_time;
+
+--bun:split
+
+ALTER TABLE notices DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,14 @@
+-- the notices are shown within their display windows, a bound left null being open
+ALTER TABLE notices ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'general';
+
+--bun:split
+
+ALTER TABLE notices ADD COLUMN IF NOT EXISTS start_time TIMESTAMPTZ;
+
+--bun:split
+
+ALTER TABLE notices ADD COLUMN IF NOT EXISTS end_time TIMESTAMPTZ;
+
+--bun:split
+
+ALTER TABLE notices ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
@@ -0,0 +1,13 @@
+-- the advanced queries shared as permalinks, normalized so that the same query is always shared under the same code
+CREATE TABLE IF NOT EXISTS short_links (
+    code         TEXT PRIMARY KEY,
+    -- payload_hash is the sha256 of the normalized payload, which deduplicates the links
+    payload_hash TEXT        NOT NULL UNIQUE,
+    payload      JSONB       NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at   TIMESTAMPTZ NOT NULL
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_short_links_expires_at ON short_links (expires_at);
@@ -0,0 +1 @@
+ALTER TABLE drop_matrix_elements DROP COLUMN IF EXISTS reporters;
@@ -0,0 +1,3 @@
+-- the number of distinct accounts having reported the stage within the time range of the element, which is not
+-- additive across the days, hence stored along rather than summed up. The elements calculated before are left as 0.
+ALTER TABLE drop_matrix_elements ADD COLUMN IF NOT EXISTS reporters INTEGER NOT NULL DEFAULT 0;
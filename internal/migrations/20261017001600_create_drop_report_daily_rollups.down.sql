@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS drop_report_daily_reporters;
+
+--bun:split
+
+DROP TABLE IF EXISTS drop_report_daily_rollups;
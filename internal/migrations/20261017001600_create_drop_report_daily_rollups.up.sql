@@ -0,0 +1,30 @@
+-- the reliable reports not excluded by any rule, rolled up by the game day of the server they were created in and by
+-- their source category, which the trends and the drop matrix of the recent days are aggregated from. The rows of
+-- item 0 hold the totals of the stage: its times and the number of distinct accounts having reported it in the day.
+-- The rows of the items hold their total quantity and the number of reports of each of their quantities.
+CREATE TABLE IF NOT EXISTS drop_report_daily_rollups (
+    server           TEXT        NOT NULL,
+    day_start        TIMESTAMPTZ NOT NULL,
+    stage_id         INTEGER     NOT NULL,
+    item_id          INTEGER     NOT NULL,
+    source_category  TEXT        NOT NULL,
+    quantity         BIGINT      NOT NULL DEFAULT 0,
+    quantity_buckets JSONB       NOT NULL DEFAULT '{}',
+    times            BIGINT      NOT NULL DEFAULT 0,
+    reporters        INTEGER     NOT NULL DEFAULT 0,
+    PRIMARY KEY (server, day_start, stage_id, item_id, source_category)
+);
+
+--bun:split
+
+-- the accounts having reported each stage in each day, which tell whether a report adds a reporter upon ingestion, and
+-- count the distinct reporters of more than one day or source category, since the reporters of the rollups are not
+-- additive across them
+CREATE TABLE IF NOT EXISTS drop_report_daily_reporters (
+    server          TEXT        NOT NULL,
+    day_start       TIMESTAMPTZ NOT NULL,
+    stage_id        INTEGER     NOT NULL,
+    source_category TEXT        NOT NULL,
+    account_id      INTEGER     NOT NULL,
+    PRIMARY KEY (server, day_start, stage_id, source_category, account_id)
+);
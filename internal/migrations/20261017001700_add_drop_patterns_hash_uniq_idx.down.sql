@@ -0,0 +1 @@
+DROP INDEX IF EXISTS drop_patterns_hash_uniq_idx;
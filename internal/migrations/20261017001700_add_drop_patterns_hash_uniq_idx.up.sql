@@ -0,0 +1,13 @@
+-- the patterns are resolved by upserting their hash, which takes a unique index on it. The patterns created twice by
+-- concurrent ingestions before are to be merged first, by pointing their reports to the first of them.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM drop_patterns GROUP BY hash HAVING COUNT(*) > 1) THEN
+        RAISE EXCEPTION 'drop_patterns has duplicated hashes, which are to be merged before the unique index is created';
+    END IF;
+END
+$$;
+
+--bun:split
+
+CREATE UNIQUE INDEX IF NOT EXISTS drop_patterns_hash_uniq_idx ON drop_patterns (hash);
@@ -0,0 +1,11 @@
+-- a report review is the verdict of a moderator on a report sampled for inspection, along with the risk signals the
+-- report carried, from which the risk model learns how often the reports carrying each of them are invalid
+CREATE TABLE IF NOT EXISTS report_reviews (
+    report_id           INTEGER          PRIMARY KEY,
+    verdict             TEXT             NOT NULL CHECK (verdict IN ('verified', 'invalid')),
+    signals             TEXT[]           NOT NULL DEFAULT '{}',
+    risk_score          DOUBLE PRECISION NOT NULL,
+    reviewer_account_id INTEGER          NOT NULL,
+    comment             TEXT,
+    reviewed_at         TIMESTAMPTZ      NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,5 @@
+ALTER TABLE exclusion_rules DROP COLUMN IF EXISTS expires_at;
+
+--bun:split
+
+ALTER TABLE exclusion_rules DROP COLUMN IF EXISTS stage_id;
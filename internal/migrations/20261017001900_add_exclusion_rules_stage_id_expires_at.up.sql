@@ -0,0 +1,7 @@
+-- stage_id narrows an exclusion rule down to the reports of a stage; the rules with an expires_at, e.g. those of the
+-- outlier reporters excluded automatically, are disabled once it has passed
+ALTER TABLE exclusion_rules ADD COLUMN IF NOT EXISTS stage_id INTEGER;
+
+--bun:split
+
+ALTER TABLE exclusion_rules ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
@@ -0,0 +1,9 @@
+-- an event summary is the final statistics of an event in a server, frozen upon its close so that the past events
+-- remain queryable without their reports; the summaries are only ever inserted, never updated
+CREATE TABLE IF NOT EXISTS event_summaries (
+    event_id   INTEGER     NOT NULL REFERENCES events (event_id) ON DELETE CASCADE,
+    server     TEXT        NOT NULL,
+    summary    JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, server)
+);
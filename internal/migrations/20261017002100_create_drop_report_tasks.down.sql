@@ -0,0 +1 @@
+DROP TABLE IF EXISTS drop_report_tasks;
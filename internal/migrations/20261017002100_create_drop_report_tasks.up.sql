@@ -0,0 +1,11 @@
+-- a drop report task is a report task of which the reports have been persisted, claimed within the transaction
+-- persisting them so that the tasks redelivered by jetstream are not persisted twice; the claims are pruned once
+-- they have outlived any redelivery
+CREATE TABLE IF NOT EXISTS drop_report_tasks (
+    task_id    TEXT        PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_drop_report_tasks_created_at ON drop_report_tasks (created_at);
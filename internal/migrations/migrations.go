@@ -0,0 +1,24 @@
+// Package migrations holds the schema migrations of the database, which are applied in the order of their names by
+// the migrate command. Each migration is a pair of <timestamp>_<name>.up.sql and <timestamp>_<name>.down.sql files,
+// whose statements are separated by --bun:split and run one by one outside of a transaction, unless the files are
+// named .tx.up.sql and .tx.down.sql instead.
+//
+// The scripts of the run-script command predate the migrations and are still run by hand.
+package migrations
+
+import (
+	"embed"
+
+	"github.com/uptrace/bun/migrate"
+)
+
+//go:embed *.sql
+var sqlMigrations embed.FS
+
+var Migrations = migrate.NewMigrations()
+
+func init() {
+	if err := Migrations.Discover(sqlMigrations); err != nil {
+		panic(err)
+	}
+}
@@ -3,9 +3,22 @@ package model
 import (
 	"time"
 
+	"github.com/samber/lo"
 	"github.com/uptrace/bun"
+	"gopkg.in/guregu/null.v3"
 )
 
+const (
+	// RoleAdmin has access to every admin route, including role management
+	RoleAdmin = "admin"
+	// RoleModerator reviews contributions: rejections, appeals and recognition defects
+	RoleModerator = "moderator"
+	// RoleMaintainer manages game data and operational jobs such as cache purges and recalculations
+	RoleMaintainer = "maintainer"
+)
+
+var Roles = []string{RoleAdmin, RoleModerator, RoleMaintainer}
+
 type Account struct {
 	bun.BaseModel `bun:"accounts"`
 
@@ -13,5 +26,30 @@ type Account struct {
 	PenguinID string  `json:"penguinId"`
 	Weight    float64 `json:"weight"`
 	// Tags      []string `json:"tags"`
-	CreatedAt time.Time `json:"createdAt"`
+	// Roles are the admin roles granted to the account. Empty for normal contributors.
+	Roles []string `bun:",array,nullzero,default:'{}'" json:"roles,omitempty"`
+	// AdminTokenHash is the SHA-256 hex digest of the bearer token used to access the admin API
+	AdminTokenHash string    `bun:",nullzero" json:"-"`
+	CreatedAt      time.Time `json:"createdAt"`
+	// ShadowBannedAt is when the account has been shadow-banned: its reports are still accepted, but excluded from
+	// the aggregations. It is never revealed to the account itself.
+	ShadowBannedAt *time.Time `bun:",nullzero" json:"-"`
+	// LeaderboardOptIn is whether the account is listed on the contributor leaderboards
+	LeaderboardOptIn bool `bun:",notnull" json:"leaderboardOptIn"`
+	// LeaderboardName is the name the account is listed under on the leaderboards
+	LeaderboardName null.String `json:"leaderboardName" swaggertype:"string"`
+}
+
+// HasAnyRole reports whether the account has been granted any of the given roles.
+// RoleAdmin implicitly satisfies every role.
+func (a *Account) HasAnyRole(roles ...string) bool {
+	if lo.Contains(a.Roles, RoleAdmin) {
+		return true
+	}
+	for _, role := range roles {
+		if lo.Contains(a.Roles, role) {
+			return true
+		}
+	}
+	return false
 }
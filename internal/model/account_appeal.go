@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	AccountAppealStatusPending  = "PENDING"
+	AccountAppealStatusApproved = "APPROVED"
+	AccountAppealStatusRejected = "REJECTED"
+)
+
+type AccountAppeal struct {
+	bun.BaseModel `bun:"account_appeals,alias:aa"`
+
+	AppealID  int    `bun:",pk,autoincrement" json:"id"`
+	AccountID int    `bun:",notnull" json:"accountId"`
+	Reason    string `bun:",notnull" json:"reason"`
+	// Contact is an optional way for moderators to reach the appellant, e.g. an email address
+	Contact string `bun:",nullzero" json:"contact,omitempty"`
+	// Status is one of PENDING, APPROVED or REJECTED
+	Status string `bun:",notnull" json:"status"`
+	// Resolution is the note left by the moderator when resolving the appeal
+	Resolution string     `bun:",nullzero" json:"resolution,omitempty"`
+	CreatedAt  time.Time  `bun:",notnull,default:current_timestamp" json:"createdAt"`
+	ResolvedAt *time.Time `bun:",nullzero" json:"resolvedAt,omitempty"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type APIKey struct {
+	bun.BaseModel `bun:"api_keys,alias:ak"`
+
+	APIKeyID int `bun:"api_key_id,pk,autoincrement" json:"id"`
+	// Name identifies the holder of the key, e.g. the name of the community bot
+	Name string `bun:",notnull" json:"name"`
+	// Contact is an optional way for maintainers to reach the holder, e.g. an email address
+	Contact string `bun:",nullzero" json:"contact,omitempty"`
+	// Prefix is the first few characters of the key, kept in plain text for the holder to tell their keys apart
+	Prefix string `bun:",notnull" json:"prefix"`
+	// KeyHash is the SHA-256 hex digest of the key
+	KeyHash string `bun:",notnull,unique" json:"-"`
+	// DailyRequestQuota is the number of requests the key may make per UTC day; 0 means unlimited
+	DailyRequestQuota int `bun:",notnull,default:0" json:"dailyRequestQuota"`
+	// DailyByteQuota is the number of response bytes (before compression) the key may be served per UTC day; 0 means unlimited
+	DailyByteQuota int64      `bun:",notnull,default:0" json:"dailyByteQuota"`
+	CreatedAt      time.Time  `bun:",notnull,default:current_timestamp" json:"createdAt"`
+	RevokedAt      *time.Time `bun:",nullzero" json:"revokedAt,omitempty"`
+}
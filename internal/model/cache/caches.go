@@ -9,25 +9,41 @@ import (
 
 	"exusiai.dev/backend-next/internal/model"
 	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
 	"exusiai.dev/backend-next/internal/pkg/cache"
 )
 
 type Flusher func() error
 
+// customizedResultsMaxItems bounds the number of the results of the customized queries cached
+const customizedResultsMaxItems = 1000
+
 var (
-	AccountByID        *cache.Set[model.Account]
-	AccountByPenguinID *cache.Set[model.Account]
-	AccountExistence   *cache.Set[int]
+	AccountByID             *cache.Set[model.Account]
+	AccountByPenguinID      *cache.Set[model.Account]
+	AccountExistence        *cache.Set[int]
+	AccountShadowBan        *cache.Set[int]
+	AccountByAdminTokenHash *cache.Set[model.Account]
+
+	APIKeyByKeyHash *cache.Set[model.APIKey]
 
 	ItemDropSetByStageIDAndRangeID   *cache.Set[[]int]
 	ItemDropSetByStageIdAndTimeRange *cache.Set[[]int]
 
 	ShimGlobalDropMatrix *cache.Set[modelv2.DropMatrixQueryResult]
 	GlobalDropMatrix     *cache.Set[model.DropMatrixQueryResult]
+	DropMatrixSnapshot   *cache.Set[modelv2.DropMatrixQueryResult]
+	ShimRecentDropMatrix *cache.Set[modelv2.DropMatrixQueryResult]
 
 	ShimTrend *cache.Set[modelv2.TrendQueryResult]
 
 	ShimGlobalPatternMatrix *cache.Set[modelv2.PatternMatrixQueryResult]
+	ShimRecentPatternMatrix *cache.Set[modelv2.PatternMatrixQueryResult]
+
+	CustomizedResults *cache.Set[any]
+
+	EventLeaderboard *cache.Set[modelv3.EventLeaderboard]
+	EventSummary     *cache.Set[modelv3.EventSummary]
 
 	Formula *cache.Singular[json.RawMessage]
 
@@ -39,6 +55,13 @@ var (
 	ShimItemByArkID *cache.Set[modelv2.Item]
 	ItemsMapById    *cache.Singular[map[int]*model.Item]
 	ItemsMapByArkID *cache.Singular[map[string]*model.Item]
+	ItemIcons       *cache.Singular[modelv3.ItemIcons]
+
+	ItemValueTables *cache.Set[[]*modelv3.ItemValueTable]
+
+	RecognitionStageHashes *cache.Set[modelv3.RecognitionStageHashes]
+
+	ClientVersions *cache.Singular[map[string]*model.ClientVersion]
 
 	RecruitTagMap *cache.Singular[map[string]string]
 
@@ -48,14 +71,23 @@ var (
 	ShimActivities *cache.Singular[[]*modelv2.Activity]
 
 	ShimSiteStats *cache.Set[modelv2.SiteStats]
+	SiteStats     *cache.Singular[modelv3.SiteStats]
+	ReportHeatmap *cache.Set[modelv3.ReportHeatmap]
+
+	MetaBundle *cache.Singular[modelv3.MetaBundle]
+
+	UIConfigs *cache.Set[modelv3.UIConfig]
 
 	Stages           *cache.Singular[[]*model.Stage]
 	StageByArkID     *cache.Set[model.Stage]
 	ShimStages       *cache.Set[[]*modelv2.Stage]
 	ShimStageByArkID *cache.Set[modelv2.Stage]
 	StagesMapByID    *cache.Singular[map[int]*model.Stage]
+	SampleForecast   *cache.Set[modelv3.SampleForecast]
 	StagesMapByArkID *cache.Singular[map[string]*model.Stage]
 
+	TimesDistribution *cache.Set[modelv3.TimesDistribution]
+
 	TimeRanges                  *cache.Set[[]*model.TimeRange]
 	TimeRangeByID               *cache.Set[model.TimeRange]
 	TimeRangesMap               *cache.Set[map[int]*model.TimeRange]
@@ -71,6 +103,7 @@ var (
 	DropPatternElementsByPatternID *cache.Set[[]*model.DropPatternElement]
 
 	LastModifiedTime *cache.Set[time.Time]
+	ContentHash      *cache.Set[string]
 
 	once sync.Once
 
@@ -118,6 +151,16 @@ func initializeCaches() {
 	SetMap["account#penguinId"] = AccountByPenguinID.Flush
 	SetMap["accountExistence#accountId"] = AccountExistence.Flush
 
+	AccountShadowBan = cache.NewSet[int]("accountShadowBan#accountId")
+	SetMap["accountShadowBan#accountId"] = AccountShadowBan.Flush
+
+	AccountByAdminTokenHash = cache.NewSet[model.Account]("account#adminTokenHash")
+	SetMap["account#adminTokenHash"] = AccountByAdminTokenHash.Flush
+
+	// api_key
+	APIKeyByKeyHash = cache.NewSet[model.APIKey]("apiKey#keyHash")
+	SetMap["apiKey#keyHash"] = APIKeyByKeyHash.Flush
+
 	// drop_info
 	ItemDropSetByStageIDAndRangeID = cache.NewSet[[]int]("itemDropSet#server|stageId|rangeId")
 	ItemDropSetByStageIdAndTimeRange = cache.NewSet[[]int]("itemDropSet#server|stageId|startTime|endTime")
@@ -128,9 +171,13 @@ func initializeCaches() {
 	// drop_matrix
 	ShimGlobalDropMatrix = cache.NewSet[modelv2.DropMatrixQueryResult]("shimGlobalDropMatrix#server|showClosedZones|sourceCategory")
 	GlobalDropMatrix = cache.NewSet[model.DropMatrixQueryResult]("globalDropMatrix#server|sourceCategory")
+	DropMatrixSnapshot = cache.NewSet[modelv2.DropMatrixQueryResult]("dropMatrixSnapshot#server|time")
+	ShimRecentDropMatrix = cache.NewSet[modelv2.DropMatrixQueryResult]("shimRecentDropMatrix#server|days|showClosedZones|sourceCategory")
 
 	SetMap["shimGlobalDropMatrix#server|showClosedZones|sourceCategory"] = ShimGlobalDropMatrix.Flush
 	SetMap["globalDropMatrix#server|sourceCategory"] = GlobalDropMatrix.Flush
+	SetMap["dropMatrixSnapshot#server|time"] = DropMatrixSnapshot.Flush
+	SetMap["shimRecentDropMatrix#server|days|showClosedZones|sourceCategory"] = ShimRecentDropMatrix.Flush
 
 	// trend
 	ShimTrend = cache.NewSet[modelv2.TrendQueryResult]("shimTrend#server")
@@ -139,8 +186,15 @@ func initializeCaches() {
 
 	// pattern_matrix
 	ShimGlobalPatternMatrix = cache.NewSet[modelv2.PatternMatrixQueryResult]("shimGlobalPatternMatrix#server|sourceCategory|showAllPatterns")
+	ShimRecentPatternMatrix = cache.NewSet[modelv2.PatternMatrixQueryResult]("shimRecentPatternMatrix#server|days|sourceCategory|showAllPatterns")
 
 	SetMap["shimGlobalPatternMatrix#server|sourceCategory|showAllPatterns"] = ShimGlobalPatternMatrix.Flush
+	SetMap["shimRecentPatternMatrix#server|days|sourceCategory|showAllPatterns"] = ShimRecentPatternMatrix.Flush
+
+	// customized results of the advanced queries
+	CustomizedResults = cache.NewBoundedSet[any]("customizedResults#key", customizedResultsMaxItems)
+
+	SetMap["customizedResults#key"] = CustomizedResults.Flush
 
 	// formula
 	Formula = cache.NewSingular[json.RawMessage]("formula")
@@ -157,6 +211,7 @@ func initializeCaches() {
 	ShimItemByArkID = cache.NewSet[modelv2.Item]("shimItem#arkItemId")
 	ItemsMapById = cache.NewSingular[map[int]*model.Item]("itemsMapById")
 	ItemsMapByArkID = cache.NewSingular[map[string]*model.Item]("itemsMapByArkId")
+	ItemIcons = cache.NewSingular[modelv3.ItemIcons]("itemIcons")
 
 	SingularFlusherMap["items"] = Items.Delete
 	SetMap["item#arkItemId"] = ItemByArkID.Flush
@@ -164,6 +219,22 @@ func initializeCaches() {
 	SetMap["shimItem#arkItemId"] = ShimItemByArkID.Flush
 	SingularFlusherMap["itemsMapById"] = ItemsMapById.Delete
 	SingularFlusherMap["itemsMapByArkId"] = ItemsMapByArkID.Delete
+	SingularFlusherMap["itemIcons"] = ItemIcons.Delete
+
+	// item_value
+	ItemValueTables = cache.NewSet[[]*modelv3.ItemValueTable]("itemValueTables#server")
+
+	SetMap["itemValueTables#server"] = ItemValueTables.Flush
+
+	// recognition_stage_hash
+	RecognitionStageHashes = cache.NewSet[modelv3.RecognitionStageHashes]("recognitionStageHashes#server")
+
+	SetMap["recognitionStageHashes#server"] = RecognitionStageHashes.Flush
+
+	// client_version
+	ClientVersions = cache.NewSingular[map[string]*model.ClientVersion]("clientVersions")
+
+	SingularFlusherMap["clientVersions"] = ClientVersions.Delete
 
 	// recruit tag maps (for report)
 	RecruitTagMap = cache.NewSingular[map[string]string]("recruitTagMap#bilingualTagName")
@@ -186,6 +257,23 @@ func initializeCaches() {
 
 	SetMap["shimSiteStats#server"] = ShimSiteStats.Flush
 
+	SiteStats = cache.NewSingular[modelv3.SiteStats]("siteStats")
+
+	SingularFlusherMap["siteStats"] = SiteStats.Delete
+
+	ReportHeatmap = cache.NewSet[modelv3.ReportHeatmap]("reportHeatmap#server|days")
+	SetMap["reportHeatmap#server|days"] = ReportHeatmap.Flush
+
+	// meta_bundle
+	MetaBundle = cache.NewSingular[modelv3.MetaBundle]("metaBundle")
+
+	SingularFlusherMap["metaBundle"] = MetaBundle.Delete
+
+	// ui_config
+	UIConfigs = cache.NewSet[modelv3.UIConfig]("uiConfig#server")
+
+	SetMap["uiConfig#server"] = UIConfigs.Flush
+
 	// stage
 	Stages = cache.NewSingular[[]*model.Stage]("stages")
 	StageByArkID = cache.NewSet[model.Stage]("stage#arkStageId")
@@ -201,6 +289,12 @@ func initializeCaches() {
 	SingularFlusherMap["stagesMapById"] = StagesMapByID.Delete
 	SingularFlusherMap["stagesMapByArkId"] = StagesMapByArkID.Delete
 
+	SampleForecast = cache.NewSet[modelv3.SampleForecast]("sampleForecast#server|arkStageId|target")
+	SetMap["sampleForecast#server|arkStageId|target"] = SampleForecast.Flush
+
+	TimesDistribution = cache.NewSet[modelv3.TimesDistribution]("timesDistribution#server|arkStageId")
+	SetMap["timesDistribution#server|arkStageId"] = TimesDistribution.Flush
+
 	// time_range
 	TimeRanges = cache.NewSet[[]*model.TimeRange]("timeRanges#server")
 	TimeRangeByID = cache.NewSet[model.TimeRange]("timeRange#rangeId")
@@ -232,8 +326,21 @@ func initializeCaches() {
 
 	SetMap["dropPatternElements#patternId"] = DropPatternElementsByPatternID.Flush
 
+	// leaderboard
+	EventLeaderboard = cache.NewSet[modelv3.EventLeaderboard]("eventLeaderboard#eventId|server|sortBy")
+
+	SetMap["eventLeaderboard#eventId|server|sortBy"] = EventLeaderboard.Flush
+
+	// event summary
+	EventSummary = cache.NewSet[modelv3.EventSummary]("eventSummary#eventId|server")
+
+	SetMap["eventSummary#eventId|server"] = EventSummary.Flush
+
 	// others
 	LastModifiedTime = cache.NewSet[time.Time]("lastModifiedTime#key")
 
+	ContentHash = cache.NewSet[string]("contentHash#key")
+
 	SetMap["lastModifiedTime#key"] = LastModifiedTime.Flush
+	SetMap["contentHash#key"] = ContentHash.Flush
 }
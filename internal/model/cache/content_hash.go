@@ -0,0 +1,18 @@
+package cache
+
+import (
+	"strconv"
+
+	"github.com/goccy/go-json"
+	"github.com/zeebo/xxh3"
+)
+
+// RecordContentHash records the hash of the JSON encoding of v under key, which the controllers serve as the ETag
+// of the content. The hash is left as it was when v fails to be encoded.
+func RecordContentHash(key string, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	ContentHash.Set(key, strconv.FormatUint(xxh3.Hash(b), 16), 0)
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+const (
+	CacheTTLOverrideScopeZone  = "zone"
+	CacheTTLOverrideScopeStage = "stage"
+)
+
+// CacheTTLOverride overrides how long the matrices and the trends of the stages of a zone, or of a stage, are cached
+// for, e.g. to refresh those of the zone of an event being launched more often than the others
+type CacheTTLOverride struct {
+	Scope string `json:"scope" example:"zone"`
+	// ArkID is the ID of the zone or the stage in the game
+	ArkID string `json:"arkId" example:"act24side_zone1"`
+	// Server restricts the override to a server, rather than every server
+	Server string `json:"server,omitempty" example:"CN"`
+	TTL    string `json:"ttl" example:"5m"`
+	// ExpiresAt is when the override stops applying, should it be temporary
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// StageIDs are the stages the override applies to, resolved from the zone or the stage upon saving
+	StageIDs []int `json:"stageIds"`
+}
+
+// Active reports whether the override applies at the given time
+func (o *CacheTTLOverride) Active(t time.Time) bool {
+	return o.ExpiresAt == nil || t.Before(*o.ExpiresAt)
+}
+
+// Duration returns the TTL of the override, which is validated upon saving
+func (o *CacheTTLOverride) Duration() time.Duration {
+	d, _ := time.ParseDuration(o.TTL)
+	return d
+}
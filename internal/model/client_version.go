@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"gopkg.in/guregu/null.v3"
+)
+
+// ClientVersion gates the versions of a client, identified by the source name of its reports, allowed to submit
+// reports, so that a buggy release can be blocked without waiting for its fix to be adopted
+type ClientVersion struct {
+	bun.BaseModel `bun:"client_versions,alias:cv"`
+
+	Source string `bun:",pk" json:"source" example:"MeoAssistant"`
+	// MinVersion is the lowest semantic version accepted; null accepts every version
+	MinVersion null.String `json:"minVersion" swaggertype:"string" example:"v4.12.0"`
+	// BlockedVersions are rejected regardless of MinVersion
+	BlockedVersions []string `bun:",array,notnull" json:"blockedVersions"`
+	// Disabled rejects every version of the client
+	Disabled bool `bun:",notnull" json:"disabled"`
+	// Message is shown to the users of the versions rejected, e.g. why and where to upgrade
+	Message   null.String `json:"message" swaggertype:"string"`
+	CreatedAt time.Time   `bun:",notnull,default:current_timestamp" json:"createdAt"`
+	UpdatedAt time.Time   `bun:",notnull,default:current_timestamp" json:"updatedAt"`
+}
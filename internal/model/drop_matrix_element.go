@@ -17,6 +17,7 @@ type DropMatrixElement struct {
 	DayNum          int         `json:"dayNum"`
 	Quantity        int         `json:"quantity"`
 	Times           int         `json:"times"`
+	Reporters       int         `json:"reporters"`
 	QuantityBuckets map[int]int `bun:"type:jsonb" json:"quantityBuckets"`
 	Server          string      `json:"server"`
 	SourceCategory  string      `json:"sourceCategory"` // sourceCategory can be: "automated", "manual", "all"
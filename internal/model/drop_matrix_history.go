@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// DropMatrixHistory is the last snapshot of the global drop matrix of a server recorded in a day
+type DropMatrixHistory struct {
+	bun.BaseModel `bun:"drop_matrix_history,alias:dmh"`
+
+	Server     string    `bun:",pk"`
+	DayNum     int       `bun:",pk"`
+	RecordedAt time.Time `bun:",notnull"`
+	// Content is the gzipped JSON of the matrix in the v2 format
+	Content []byte `bun:",notnull"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// DropReportDailyRollup is the aggregate of the reliable reports of a stage created in a game day of a server from
+// the sources of a category. ItemID is 0 for the totals of the stage, which hold Times and Reporters; the rows of the
+// items hold Quantity and QuantityBuckets.
+type DropReportDailyRollup struct {
+	bun.BaseModel `bun:"drop_report_daily_rollups,alias:drdr"`
+
+	Server         string    `bun:",pk"`
+	DayStart       time.Time `bun:",pk"`
+	StageID        int       `bun:",pk"`
+	ItemID         int       `bun:",pk"`
+	SourceCategory string    `bun:",pk"`
+	Quantity       int
+	// QuantityBuckets counts the reports of each quantity of the item
+	QuantityBuckets map[int]int `bun:",type:jsonb"`
+	Times           int
+	Reporters       int
+}
@@ -1,33 +1,25 @@
 package model
 
-import "time"
+import (
+	"time"
 
-// DropMatrix
-type TotalQuantityResultForDropMatrix struct {
-	StageID       int `json:"stageId" bun:"stage_id"`
-	ItemID        int `json:"itemId" bun:"item_id"`
-	TotalQuantity int `json:"totalQuantity" bun:"total_quantity"`
-}
+	"gopkg.in/guregu/null.v3"
+)
 
+// DropMatrix
 type TotalTimesResult struct {
 	StageID    int `json:"stageId" bun:"stage_id"`
 	TotalTimes int `json:"totalTimes" bun:"total_times"`
 }
 
-type QuantityUniqCountResultForDropMatrix struct {
-	StageID  int `json:"stageId" bun:"stage_id"`
-	ItemID   int `json:"itemId" bun:"item_id"`
-	Quantity int `json:"quantity" bun:"quantity"`
-	Count    int `json:"count" bun:"count"`
-}
-
 type CombinedResultForDropMatrix struct {
-	StageID         int         `json:"stageId"`
-	ItemID          int         `json:"itemId"`
-	Times           int         `json:"times"`
-	Quantity        int         `json:"quantity"`
-	QuantityBuckets map[int]int `json:"quantityBuckets"`
-	TimeRange       *TimeRange  `json:"timeRange"`
+	StageID         int         `json:"stageId" bun:"stage_id"`
+	ItemID          int         `json:"itemId" bun:"item_id"`
+	Times           int         `json:"times" bun:"times"`
+	Reporters       int         `json:"reporters" bun:"reporters"`
+	Quantity        int         `json:"quantity" bun:"quantity"`
+	QuantityBuckets map[int]int `json:"quantityBuckets" bun:"quantity_buckets,type:jsonb"`
+	TimeRange       *TimeRange  `json:"timeRange" bun:"-"`
 }
 
 type DropMatrixQueryResult struct {
@@ -38,6 +30,7 @@ type OneDropMatrixElement struct {
 	StageID   int        `json:"stageId"`
 	ItemID    int        `json:"itemId"`
 	Times     int        `json:"times"`
+	Reporters int        `json:"reporters"`
 	Quantity  int        `json:"quantity"`
 	StdDev    float64    `json:"stdDev"`
 	TimeRange *TimeRange `json:"timeRange"`
@@ -85,6 +78,7 @@ type TotalTimesResultForTrend struct {
 	IntervalEnd   *time.Time `json:"intervalEnd" bun:"interval_end"`
 	StageID       int        `json:"stageId" bun:"stage_id"`
 	TotalTimes    int        `json:"totalTimes" bun:"total_times"`
+	Reporters     int        `json:"reporters" bun:"reporters"`
 }
 
 type CombinedResultForTrend struct {
@@ -94,6 +88,7 @@ type CombinedResultForTrend struct {
 	StageID   int        `json:"stageId"`
 	ItemID    int        `json:"itemId"`
 	Times     int        `json:"times"`
+	Reporters int        `json:"reporters"`
 	Quantity  int        `json:"quantity"`
 }
 
@@ -110,7 +105,60 @@ type ItemTrend struct {
 	ItemID     int        `json:"itemId"`
 	StartTime  *time.Time `json:"startTime"`
 	Times      []int      `json:"times"`
+	Reporters  []int      `json:"reporters"`
 	Quantity   []int      `json:"quantity"`
 	MinGroupID int        `json:"-"`
 	MaxGroupID int        `json:"-"`
 }
+
+// Account standing
+type ReliabilityCountResult struct {
+	Reliability int `json:"reliability" bun:"reliability"`
+	Count       int `json:"count" bun:"count"`
+}
+
+// ReportersResult is the number of distinct accounts having reported a stage
+type ReportersResult struct {
+	StageID   int `json:"stageId" bun:"stage_id"`
+	Reporters int `json:"reporters" bun:"reporters"`
+}
+
+// ReportsPerAccountResult is the number of accounts having a number of reports of a stage
+type ReportsPerAccountResult struct {
+	Reports  int `json:"reports" bun:"reports"`
+	Accounts int `json:"accounts" bun:"accounts"`
+}
+
+// ReporterTimesResult is the number of times an account has cleared a stage in its reports
+type ReporterTimesResult struct {
+	Server    string `json:"server" bun:"server"`
+	StageID   int    `json:"stageId" bun:"stage_id"`
+	AccountID int    `json:"accountId" bun:"account_id"`
+	Times     int    `json:"times" bun:"times"`
+}
+
+// ReporterItemQuantityResult is the quantity of an item an account has reported to drop from a stage
+type ReporterItemQuantityResult struct {
+	AccountID int `json:"accountId" bun:"account_id"`
+	ItemID    int `json:"itemId" bun:"item_id"`
+	Quantity  int `json:"quantity" bun:"quantity"`
+}
+
+// ReliabilityChangeResult is the extent of the reports of a stage of a server of which the reliability, or the
+// inclusion in the aggregations otherwise, has been changed in bulk
+type ReliabilityChangeResult struct {
+	Server  string    `json:"server" bun:"server"`
+	StageID int       `json:"stageId" bun:"stage_id"`
+	Count   int       `json:"count" bun:"count"`
+	MinTime time.Time `json:"minTime" bun:"min_time"`
+	MaxTime time.Time `json:"maxTime" bun:"max_time"`
+}
+
+// ContributionResult is the contribution of an account opted in to the leaderboards
+type ContributionResult struct {
+	AccountID int         `bun:"account_id"`
+	Name      null.String `bun:"name"`
+	Reports   int         `bun:"reports"`
+	Times     int         `bun:"times"`
+	Sanity    int         `bun:"sanity"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// DropReportTask is the claim of a report task of which the reports have been persisted, so that the task is not
+// persisted twice once redelivered
+type DropReportTask struct {
+	bun.BaseModel `bun:"drop_report_tasks,alias:drt"`
+
+	TaskID    string    `bun:",pk" json:"taskId"`
+	CreatedAt time.Time `bun:",notnull,default:current_timestamp" json:"createdAt"`
+}
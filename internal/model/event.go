@@ -0,0 +1,69 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/uptrace/bun"
+	"gopkg.in/guregu/null.v3"
+)
+
+const (
+	EventStatusUpcoming = "UPCOMING"
+	EventStatusActive   = "ACTIVE"
+	EventStatusPast     = "PAST"
+)
+
+var EventStatuses = []string{
+	EventStatusUpcoming,
+	EventStatusActive,
+	EventStatusPast,
+}
+
+// Event is a group of zones released together, announced to open and close at different times per server
+type Event struct {
+	bun.BaseModel `bun:"events,alias:ev"`
+
+	EventID int    `bun:",pk,autoincrement" json:"id"`
+	Name    string `bun:",notnull" json:"name"`
+	// ZoneIDs are the numerical IDs of the zones of the event
+	ZoneIDs   []int            `bun:"zone_ids,array,notnull" json:"zoneIds"`
+	Schedules []*EventSchedule `bun:"rel:has-many,join:event_id=event_id" json:"schedules"`
+	CreatedAt time.Time        `bun:",notnull,default:current_timestamp" json:"createdAt"`
+	UpdatedAt time.Time        `bun:",notnull,default:current_timestamp" json:"updatedAt"`
+}
+
+// EventSchedule is the announced open and close time of an event in a server
+type EventSchedule struct {
+	bun.BaseModel `bun:"event_schedules,alias:es"`
+
+	EventID  int       `bun:",pk" json:"-"`
+	Server   string    `bun:",pk" json:"server"`
+	OpenTime time.Time `bun:",notnull" json:"openTime"`
+	// CloseTime is nil when the event is open-ended
+	CloseTime *time.Time `json:"closeTime"`
+	// RangeID is the time range derived from the schedule
+	RangeID null.Int `json:"rangeId" swaggertype:"integer"`
+}
+
+// Status reports whether the event is upcoming, active or past in the server at t
+func (es *EventSchedule) Status(t time.Time) string {
+	if es.OpenTime.After(t) {
+		return EventStatusUpcoming
+	}
+	if es.CloseTime != nil && !es.CloseTime.After(t) {
+		return EventStatusPast
+	}
+	return EventStatusActive
+}
+
+// EventSummary is the final statistics of an event in a server, frozen upon its close. Summary is the encoded
+// modelv3.EventSummary, kept as it was encoded so that it never changes along with the reports.
+type EventSummary struct {
+	bun.BaseModel `bun:"event_summaries,alias:esu"`
+
+	EventID   int             `bun:",pk" json:"eventId"`
+	Server    string          `bun:",pk" json:"server"`
+	Summary   json.RawMessage `bun:"type:jsonb,notnull" json:"summary"`
+	CreatedAt time.Time       `bun:",notnull,default:current_timestamp" json:"createdAt"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"gopkg.in/guregu/null.v3"
+)
+
+// ExclusionRule excludes the accepted reports matching all of its criteria from the aggregations, so that a batch of
+// bad reports can be excised without deleting them. A criterion left null matches every report.
+type ExclusionRule struct {
+	bun.BaseModel `bun:"exclusion_rules,alias:er"`
+
+	RuleID    int         `bun:",pk,autoincrement" json:"id"`
+	Name      string      `bun:",notnull" json:"name"`
+	Comment   null.String `json:"comment" swaggertype:"string"`
+	Enabled   bool        `bun:",notnull" json:"enabled"`
+	Server    null.String `json:"server" swaggertype:"string"`
+	AccountID null.Int    `json:"accountId" swaggertype:"integer"`
+	// IPRange is a CIDR the IP of the reports falls in
+	IPRange    null.String `bun:"ip_range" json:"ipRange" swaggertype:"string"`
+	SourceName null.String `json:"sourceName" swaggertype:"string"`
+	// VersionPattern is matched against the version of the reports with LIKE
+	VersionPattern null.String `json:"versionPattern" swaggertype:"string"`
+	StageID        null.Int    `json:"stageId" swaggertype:"integer"`
+	StartTime      *time.Time  `json:"startTime"`
+	EndTime        *time.Time  `json:"endTime"`
+	// ExpiresAt is when the rule is disabled, should it be temporary
+	ExpiresAt *time.Time `json:"expiresAt"`
+	CreatedAt time.Time  `bun:",notnull,default:current_timestamp" json:"createdAt"`
+	UpdatedAt time.Time  `bun:",notnull,default:current_timestamp" json:"updatedAt"`
+}
@@ -0,0 +1,19 @@
+package model
+
+// FeatureFlag gates a change being rolled out, e.g. a new aggregation logic, so that it can be enabled for
+// some servers or some percentage of the traffic only and compared against the current logic before full cutover
+type FeatureFlag struct {
+	Key         string `json:"key" example:"report.rare_drop_warnings"`
+	Description string `json:"description,omitempty"`
+	FeatureFlagRollout
+	Overridden bool `json:"overridden"`
+}
+
+// FeatureFlagRollout describes to whom a feature flag is enabled
+type FeatureFlagRollout struct {
+	Enabled bool `json:"enabled"`
+	// Servers restricts the flag to the given servers. An empty list means every server.
+	Servers []string `json:"servers" example:"CN,US"`
+	// Percentage is the percentage, from 0 to 100, of the subjects (accounts or clients) to which the flag is enabled
+	Percentage int `json:"percentage" example:"100"`
+}
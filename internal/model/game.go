@@ -0,0 +1,8 @@
+package model
+
+// GameArknights is the game the statistics have been collected for from the start
+const GameArknights = "arknights"
+
+// DefaultGame is the game of the existing metadata. Only the default game is served as of now: the routes and the
+// caches are not scoped to a game until the metadata queries filter by it.
+const DefaultGame = GameArknights
@@ -0,0 +1,95 @@
+package gamedata
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// ItemTable is the subset of item_table.json used by the game data sync importer
+type ItemTable struct {
+	Items map[string]*ItemTableEntry `json:"items"`
+}
+
+type ItemTableEntry struct {
+	ItemID       string `json:"itemId"`
+	Name         string `json:"name"`
+	Rarity       Rarity `json:"rarity"`
+	SortID       int    `json:"sortId"`
+	ClassifyType string `json:"classifyType"`
+	ItemType     string `json:"itemType"`
+}
+
+// Rarity accepts both the legacy numeric rarity (0-based) and the newer "TIER_n" (1-based) representation
+type Rarity int
+
+func (r *Rarity) UnmarshalJSON(b []byte) error {
+	var n int
+	if err := json.Unmarshal(b, &n); err == nil {
+		*r = Rarity(n)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(s, "TIER_"))
+	if err != nil {
+		return err
+	}
+	*r = Rarity(n - 1)
+	return nil
+}
+
+// StageTable is the subset of stage_table.json used by the game data sync importer
+type StageTable struct {
+	Stages map[string]*StageTableEntry `json:"stages"`
+}
+
+type StageTableEntry struct {
+	StageID   string `json:"stageId"`
+	ZoneID    string `json:"zoneId"`
+	Code      string `json:"code"`
+	StageType string `json:"stageType"`
+	APCost    int    `json:"apCost"`
+}
+
+const (
+	ChangeActionCreate = "CREATE"
+	ChangeActionUpdate = "UPDATE"
+)
+
+// ChangeSet is a reviewable list of changes between the game data tables and our metadata
+type ChangeSet struct {
+	ID        string         `json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Source    string         `json:"source"`
+	Items     []*ItemChange  `json:"items"`
+	Stages    []*StageChange `json:"stages"`
+	// Skipped lists the entries that could not be turned into a change, with a reason
+	Skipped []string `json:"skipped"`
+	// AppliedAt is set once the change set has been applied
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+type FieldDiff struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+type ItemChange struct {
+	Action    string               `json:"action"`
+	ArkItemID string               `json:"itemId"`
+	Entry     *ItemTableEntry      `json:"entry"`
+	Diff      map[string]FieldDiff `json:"diff,omitempty"`
+}
+
+type StageChange struct {
+	Action     string               `json:"action"`
+	ArkStageID string               `json:"stageId"`
+	Entry      *StageTableEntry     `json:"entry"`
+	Diff       map[string]FieldDiff `json:"diff,omitempty"`
+}
@@ -0,0 +1,19 @@
+package model
+
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusUnavailable = "unavailable"
+	HealthStatusWarmingUp   = "warming_up"
+	HealthStatusDraining    = "draining"
+)
+
+// HealthReport is the result of a readiness probe, with the status of every dependency checked
+type HealthReport struct {
+	Status string                  `json:"status" example:"ok"`
+	Checks map[string]*HealthCheck `json:"checks"`
+}
+
+type HealthCheck struct {
+	Status string `json:"status" example:"ok"`
+	Error  string `json:"error,omitempty"`
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/goccy/go-json"
 	"github.com/uptrace/bun"
 	"gopkg.in/guregu/null.v3"
@@ -13,7 +15,9 @@ type Item struct {
 	ItemID int `bun:",pk,autoincrement" json:"penguinItemId"`
 	// ArkItemID (itemId) is the previously used, string form ID of the item; in JSON-representation `itemId` is used as key.
 	ArkItemID string `json:"itemId"`
-	Type      string `json:"type"`
+	// Game is the game the item is from, which defaults to arknights.
+	Game string `bun:",nullzero,notnull,default:'arknights'" json:"game"`
+	Type string `json:"type"`
 	// Name is a map with language code as key and the name of the item in that language as value.
 	Name json.RawMessage `json:"name" swaggertype:"object"`
 	// Existence is a map with server code as key and the existence of the item in that server as value.
@@ -25,6 +29,18 @@ type Item struct {
 	Group null.String `json:"group,omitempty" swaggertype:"string"`
 	// Sprite describes the location of the item's sprite on the sprite image, in a form of Y:X.
 	Sprite null.String `json:"sprite,omitempty" swaggertype:"string"`
+	// IconURL is the URL of the standalone icon of the item, which changes whenever the icon does.
+	IconURL null.String `json:"iconUrl,omitempty" swaggertype:"string"`
 	// Keywords is an arbitrary JSON object containing the keywords of the item, for optimizing the results of the frontend built-in search engine.
 	Keywords json.RawMessage `json:"keywords,omitempty" swaggertype:"object"`
+	// DeletedAt is when the item has been deleted. Deleted items are left out of every query unless asked for,
+	// and may be restored.
+	DeletedAt *time.Time `bun:",soft_delete,nullzero" json:"deletedAt,omitempty"`
+}
+
+// ItemSearchResult is an item matched by a fuzzy search, along with how well it matched
+type ItemSearchResult struct {
+	Item `bun:",extend"`
+	// Score is the trigram word similarity between the query and the names and keywords of the item, from 0 to 1.
+	Score float64 `bun:"score" json:"score"`
 }
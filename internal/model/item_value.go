@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ItemValue is the sanity value of an item in a server, as given by a preset, e.g. market for the prices in the shops
+// or efficiency for the sanity spent on the most efficient stages to farm the item
+type ItemValue struct {
+	bun.BaseModel `bun:"item_values,alias:iv"`
+
+	Preset    string    `bun:",pk" json:"preset"`
+	Server    string    `bun:",pk" json:"server"`
+	ItemID    int       `bun:",pk" json:"itemId"`
+	Value     float64   `bun:",notnull" json:"value"`
+	UpdatedAt time.Time `bun:",notnull,default:current_timestamp" json:"updatedAt"`
+}
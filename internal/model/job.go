@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/uptrace/bun"
+	"gopkg.in/guregu/null.v3"
+)
+
+const (
+	JobKindRefresh        = "refresh"
+	JobKindArchive        = "archive"
+	JobKindCacheWarm      = "cache_warm"
+	JobKindIntegrityCheck = "integrity_check"
+	// JobKindDropMatrixViews refreshes the materialized views of the drop matrix
+	JobKindDropMatrixViews = "drop_matrix_views"
+	// JobKindDropReportPartitions creates the upcoming partitions of drop_reports and prunes the archived ones
+	JobKindDropReportPartitions = "drop_report_partitions"
+	// JobKindPurge purges the archived drop reports from the database
+	JobKindPurge = "purge"
+	// JobKindReportQuarantine revalidates the quarantined reports of which the quarantine window has passed
+	JobKindReportQuarantine = "report_quarantine"
+	// JobKindShortLinkPrune removes the expired short links
+	JobKindShortLinkPrune = "short_link_prune"
+	// JobKindExclusionRuleExpiry disables the exclusion rules of which the expiry has passed
+	JobKindExclusionRuleExpiry = "exclusion_rule_expiry"
+	// JobKindOutlierDetection excludes the reporters of which the drop rates diverge from the other reporters
+	JobKindOutlierDetection = "outlier_detection"
+	// JobKindEventSummary freezes the summaries of the events upon their close
+	JobKindEventSummary = "event_summary"
+	// JobKindDropReportTaskPrune removes the claims of the report tasks that have outlived any redelivery
+	JobKindDropReportTaskPrune = "drop_report_task_prune"
+
+	JobStatusPending   = "PENDING"
+	JobStatusRunning   = "RUNNING"
+	JobStatusRetrying  = "RETRYING"
+	JobStatusSucceeded = "SUCCEEDED"
+	JobStatusFailed    = "FAILED"
+)
+
+// Job is a run of a background task, persisted so that its status survives restarts and can be listed by maintainers.
+// Pending and retrying jobs are claimed by any instance running the job worker once due.
+type Job struct {
+	bun.BaseModel `bun:"jobs,alias:j"`
+
+	JobID string `bun:",pk" json:"id"`
+	Kind  string `bun:",notnull" json:"kind"`
+	// Key deduplicates the jobs of the same task, e.g. the archive of a given day, so that they are enqueued only once
+	Key     null.String     `bun:",unique" json:"key,omitempty" swaggertype:"string"`
+	Server  string          `bun:",nullzero" json:"server,omitempty"`
+	Payload json.RawMessage `bun:"type:jsonb,nullzero" json:"payload,omitempty" swaggertype:"object"`
+	Status  string          `bun:",notnull" json:"status"`
+	// Attempts is the number of times the job has been started
+	Attempts    int         `bun:",notnull,default:0" json:"attempts"`
+	MaxAttempts int         `bun:",notnull,default:1" json:"maxAttempts"`
+	LastError   null.String `json:"lastError,omitempty" swaggertype:"string"`
+	// RunAt is when the job is due, which is pushed back upon retries
+	RunAt time.Time `bun:",notnull,default:current_timestamp" json:"runAt"`
+	// LeaseExpiresAt is when a running job is considered abandoned by its instance and may be claimed again
+	LeaseExpiresAt *time.Time `bun:",nullzero" json:"-"`
+	CreatedAt      time.Time  `bun:",notnull,default:current_timestamp" json:"createdAt"`
+	StartedAt      *time.Time `bun:",nullzero" json:"startedAt,omitempty"`
+	FinishedAt     *time.Time `bun:",nullzero" json:"finishedAt,omitempty"`
+}
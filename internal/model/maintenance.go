@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+const (
+	// MaintenanceSourceConfig is the maintenance switched on by the config of the instances
+	MaintenanceSourceConfig = "config"
+	// MaintenanceSourceAdmin is the maintenance switched on through the admin API
+	MaintenanceSourceAdmin = "admin"
+)
+
+// Maintenance is the state of the maintenance mode, during which the writes to the API are rejected while the reads
+// keep being served, e.g. during the migrations of the database and the cutovers of the events
+type Maintenance struct {
+	Active bool `json:"active"`
+	// Source tells whether the maintenance has been switched on by the config or through the admin API
+	Source string `json:"source,omitempty" example:"admin"`
+	// Reason is shown to the clients whose writes are rejected
+	Reason string `json:"reason,omitempty" example:"database migration"`
+	// Since is the time the maintenance has been switched on at
+	Since *time.Time `json:"since,omitempty"`
+	// ETA is the time the maintenance is expected to end at, if known
+	ETA *time.Time `json:"eta,omitempty"`
+}
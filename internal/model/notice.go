@@ -1,12 +1,20 @@
 package model
 
 import (
+	"time"
+
 	"github.com/goccy/go-json"
 
 	"github.com/uptrace/bun"
 	"gopkg.in/guregu/null.v3"
 )
 
+const (
+	NoticeKindGeneral     = "general"
+	NoticeKindMaintenance = "maintenance"
+	NoticeKindDataIssue   = "data_issue"
+)
+
 type Notice struct {
 	bun.BaseModel `bun:"notices"`
 
@@ -14,4 +22,15 @@ type Notice struct {
 	Existence json.RawMessage `json:"existence" swaggertype:"object"`
 	Severity  null.Int        `json:"severity" swaggertype:"integer"`
 	Content   json.RawMessage `json:"content_i18n"`
+	// Kind is one of general, maintenance and data_issue.
+	Kind string `bun:",notnull,default:'general'" json:"kind"`
+	// StartTime and EndTime are the display window of the notice, a bound left null being open.
+	StartTime *time.Time `json:"startTime"`
+	EndTime   *time.Time `json:"endTime"`
+	UpdatedAt time.Time  `bun:",notnull,default:current_timestamp" json:"updatedAt"`
+}
+
+// IsActiveAt tells whether t falls in the display window of the notice
+func (n *Notice) IsActiveAt(t time.Time) bool {
+	return (n.StartTime == nil || !t.Before(*n.StartTime)) && (n.EndTime == nil || t.Before(*n.EndTime))
 }
@@ -0,0 +1,1116 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        (unknown)
+// source: aggregation.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SourceCategory int32
+
+const (
+	SourceCategory_SOURCE_CATEGORY_ALL       SourceCategory = 0
+	SourceCategory_SOURCE_CATEGORY_AUTOMATED SourceCategory = 1
+	SourceCategory_SOURCE_CATEGORY_MANUAL    SourceCategory = 2
+)
+
+// Enum value maps for SourceCategory.
+var (
+	SourceCategory_name = map[int32]string{
+		0: "SOURCE_CATEGORY_ALL",
+		1: "SOURCE_CATEGORY_AUTOMATED",
+		2: "SOURCE_CATEGORY_MANUAL",
+	}
+	SourceCategory_value = map[string]int32{
+		"SOURCE_CATEGORY_ALL":       0,
+		"SOURCE_CATEGORY_AUTOMATED": 1,
+		"SOURCE_CATEGORY_MANUAL":    2,
+	}
+)
+
+func (x SourceCategory) Enum() *SourceCategory {
+	p := new(SourceCategory)
+	*p = x
+	return p
+}
+
+func (x SourceCategory) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SourceCategory) Descriptor() protoreflect.EnumDescriptor {
+	return file_aggregation_proto_enumTypes[0].Descriptor()
+}
+
+func (SourceCategory) Type() protoreflect.EnumType {
+	return &file_aggregation_proto_enumTypes[0]
+}
+
+func (x SourceCategory) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SourceCategory.Descriptor instead.
+func (SourceCategory) EnumDescriptor() ([]byte, []int) {
+	return file_aggregation_proto_rawDescGZIP(), []int{0}
+}
+
+type DropMatrixRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Server          Server `protobuf:"varint,1,opt,name=server,proto3,enum=Server" json:"server,omitempty"`
+	ShowClosedZones bool   `protobuf:"varint,2,opt,name=show_closed_zones,json=showClosedZones,proto3" json:"show_closed_zones,omitempty"`
+	// ark stage ids to filter the results with; empty for all stages
+	StageIds []string `protobuf:"bytes,3,rep,name=stage_ids,json=stageIds,proto3" json:"stage_ids,omitempty"`
+	// ark item ids to filter the results with; empty for all items
+	ItemIds []string `protobuf:"bytes,4,rep,name=item_ids,json=itemIds,proto3" json:"item_ids,omitempty"`
+	// account id to query the personal drop matrix for; unset for the global one
+	AccountId      *int64         `protobuf:"varint,5,opt,name=account_id,json=accountId,proto3,oneof" json:"account_id,omitempty"`
+	SourceCategory SourceCategory `protobuf:"varint,6,opt,name=source_category,json=sourceCategory,proto3,enum=SourceCategory" json:"source_category,omitempty"`
+}
+
+func (x *DropMatrixRequest) Reset() {
+	*x = DropMatrixRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_aggregation_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DropMatrixRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropMatrixRequest) ProtoMessage() {}
+
+func (x *DropMatrixRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregation_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropMatrixRequest.ProtoReflect.Descriptor instead.
+func (*DropMatrixRequest) Descriptor() ([]byte, []int) {
+	return file_aggregation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DropMatrixRequest) GetServer() Server {
+	if x != nil {
+		return x.Server
+	}
+	return Server_CN
+}
+
+func (x *DropMatrixRequest) GetShowClosedZones() bool {
+	if x != nil {
+		return x.ShowClosedZones
+	}
+	return false
+}
+
+func (x *DropMatrixRequest) GetStageIds() []string {
+	if x != nil {
+		return x.StageIds
+	}
+	return nil
+}
+
+func (x *DropMatrixRequest) GetItemIds() []string {
+	if x != nil {
+		return x.ItemIds
+	}
+	return nil
+}
+
+func (x *DropMatrixRequest) GetAccountId() int64 {
+	if x != nil && x.AccountId != nil {
+		return *x.AccountId
+	}
+	return 0
+}
+
+func (x *DropMatrixRequest) GetSourceCategory() SourceCategory {
+	if x != nil {
+		return x.SourceCategory
+	}
+	return SourceCategory_SOURCE_CATEGORY_ALL
+}
+
+type DropMatrixElement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StageId  string  `protobuf:"bytes,1,opt,name=stage_id,json=stageId,proto3" json:"stage_id,omitempty"`
+	ItemId   string  `protobuf:"bytes,2,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Times    int64   `protobuf:"varint,3,opt,name=times,proto3" json:"times,omitempty"`
+	Quantity int64   `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	StdDev   float64 `protobuf:"fixed64,5,opt,name=std_dev,json=stdDev,proto3" json:"std_dev,omitempty"`
+	// unix milliseconds
+	Start int64 `protobuf:"varint,6,opt,name=start,proto3" json:"start,omitempty"`
+	// unix milliseconds; unset if the time range is still open
+	End *int64 `protobuf:"varint,7,opt,name=end,proto3,oneof" json:"end,omitempty"`
+}
+
+func (x *DropMatrixElement) Reset() {
+	*x = DropMatrixElement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_aggregation_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DropMatrixElement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropMatrixElement) ProtoMessage() {}
+
+func (x *DropMatrixElement) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregation_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropMatrixElement.ProtoReflect.Descriptor instead.
+func (*DropMatrixElement) Descriptor() ([]byte, []int) {
+	return file_aggregation_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DropMatrixElement) GetStageId() string {
+	if x != nil {
+		return x.StageId
+	}
+	return ""
+}
+
+func (x *DropMatrixElement) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *DropMatrixElement) GetTimes() int64 {
+	if x != nil {
+		return x.Times
+	}
+	return 0
+}
+
+func (x *DropMatrixElement) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *DropMatrixElement) GetStdDev() float64 {
+	if x != nil {
+		return x.StdDev
+	}
+	return 0
+}
+
+func (x *DropMatrixElement) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *DropMatrixElement) GetEnd() int64 {
+	if x != nil && x.End != nil {
+		return *x.End
+	}
+	return 0
+}
+
+type DropMatrixResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Matrix []*DropMatrixElement `protobuf:"bytes,1,rep,name=matrix,proto3" json:"matrix,omitempty"`
+}
+
+func (x *DropMatrixResponse) Reset() {
+	*x = DropMatrixResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_aggregation_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DropMatrixResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropMatrixResponse) ProtoMessage() {}
+
+func (x *DropMatrixResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregation_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropMatrixResponse.ProtoReflect.Descriptor instead.
+func (*DropMatrixResponse) Descriptor() ([]byte, []int) {
+	return file_aggregation_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DropMatrixResponse) GetMatrix() []*DropMatrixElement {
+	if x != nil {
+		return x.Matrix
+	}
+	return nil
+}
+
+type PatternMatrixRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Server          Server         `protobuf:"varint,1,opt,name=server,proto3,enum=Server" json:"server,omitempty"`
+	ShowAllPatterns bool           `protobuf:"varint,2,opt,name=show_all_patterns,json=showAllPatterns,proto3" json:"show_all_patterns,omitempty"`
+	AccountId       *int64         `protobuf:"varint,3,opt,name=account_id,json=accountId,proto3,oneof" json:"account_id,omitempty"`
+	SourceCategory  SourceCategory `protobuf:"varint,4,opt,name=source_category,json=sourceCategory,proto3,enum=SourceCategory" json:"source_category,omitempty"`
+}
+
+func (x *PatternMatrixRequest) Reset() {
+	*x = PatternMatrixRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_aggregation_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatternMatrixRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatternMatrixRequest) ProtoMessage() {}
+
+func (x *PatternMatrixRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregation_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatternMatrixRequest.ProtoReflect.Descriptor instead.
+func (*PatternMatrixRequest) Descriptor() ([]byte, []int) {
+	return file_aggregation_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PatternMatrixRequest) GetServer() Server {
+	if x != nil {
+		return x.Server
+	}
+	return Server_CN
+}
+
+func (x *PatternMatrixRequest) GetShowAllPatterns() bool {
+	if x != nil {
+		return x.ShowAllPatterns
+	}
+	return false
+}
+
+func (x *PatternMatrixRequest) GetAccountId() int64 {
+	if x != nil && x.AccountId != nil {
+		return *x.AccountId
+	}
+	return 0
+}
+
+func (x *PatternMatrixRequest) GetSourceCategory() SourceCategory {
+	if x != nil {
+		return x.SourceCategory
+	}
+	return SourceCategory_SOURCE_CATEGORY_ALL
+}
+
+type PatternDrop struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ItemId   string `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Quantity int64  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+}
+
+func (x *PatternDrop) Reset() {
+	*x = PatternDrop{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_aggregation_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatternDrop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatternDrop) ProtoMessage() {}
+
+func (x *PatternDrop) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregation_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatternDrop.ProtoReflect.Descriptor instead.
+func (*PatternDrop) Descriptor() ([]byte, []int) {
+	return file_aggregation_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PatternDrop) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *PatternDrop) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type PatternMatrixElement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StageId  string         `protobuf:"bytes,1,opt,name=stage_id,json=stageId,proto3" json:"stage_id,omitempty"`
+	Drops    []*PatternDrop `protobuf:"bytes,2,rep,name=drops,proto3" json:"drops,omitempty"`
+	Times    int64          `protobuf:"varint,3,opt,name=times,proto3" json:"times,omitempty"`
+	Quantity int64          `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Start    int64          `protobuf:"varint,5,opt,name=start,proto3" json:"start,omitempty"`
+	End      *int64         `protobuf:"varint,6,opt,name=end,proto3,oneof" json:"end,omitempty"`
+}
+
+func (x *PatternMatrixElement) Reset() {
+	*x = PatternMatrixElement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_aggregation_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatternMatrixElement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatternMatrixElement) ProtoMessage() {}
+
+func (x *PatternMatrixElement) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregation_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatternMatrixElement.ProtoReflect.Descriptor instead.
+func (*PatternMatrixElement) Descriptor() ([]byte, []int) {
+	return file_aggregation_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PatternMatrixElement) GetStageId() string {
+	if x != nil {
+		return x.StageId
+	}
+	return ""
+}
+
+func (x *PatternMatrixElement) GetDrops() []*PatternDrop {
+	if x != nil {
+		return x.Drops
+	}
+	return nil
+}
+
+func (x *PatternMatrixElement) GetTimes() int64 {
+	if x != nil {
+		return x.Times
+	}
+	return 0
+}
+
+func (x *PatternMatrixElement) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *PatternMatrixElement) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *PatternMatrixElement) GetEnd() int64 {
+	if x != nil && x.End != nil {
+		return *x.End
+	}
+	return 0
+}
+
+type PatternMatrixResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PatternMatrix []*PatternMatrixElement `protobuf:"bytes,1,rep,name=pattern_matrix,json=patternMatrix,proto3" json:"pattern_matrix,omitempty"`
+}
+
+func (x *PatternMatrixResponse) Reset() {
+	*x = PatternMatrixResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_aggregation_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatternMatrixResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatternMatrixResponse) ProtoMessage() {}
+
+func (x *PatternMatrixResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregation_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatternMatrixResponse.ProtoReflect.Descriptor instead.
+func (*PatternMatrixResponse) Descriptor() ([]byte, []int) {
+	return file_aggregation_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PatternMatrixResponse) GetPatternMatrix() []*PatternMatrixElement {
+	if x != nil {
+		return x.PatternMatrix
+	}
+	return nil
+}
+
+type TrendRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Server Server `protobuf:"varint,1,opt,name=server,proto3,enum=Server" json:"server,omitempty"`
+}
+
+func (x *TrendRequest) Reset() {
+	*x = TrendRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_aggregation_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrendRequest) ProtoMessage() {}
+
+func (x *TrendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregation_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrendRequest.ProtoReflect.Descriptor instead.
+func (*TrendRequest) Descriptor() ([]byte, []int) {
+	return file_aggregation_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TrendRequest) GetServer() Server {
+	if x != nil {
+		return x.Server
+	}
+	return Server_CN
+}
+
+type ItemTrend struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Quantity []int64 `protobuf:"varint,1,rep,packed,name=quantity,proto3" json:"quantity,omitempty"`
+	Times    []int64 `protobuf:"varint,2,rep,packed,name=times,proto3" json:"times,omitempty"`
+}
+
+func (x *ItemTrend) Reset() {
+	*x = ItemTrend{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_aggregation_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ItemTrend) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemTrend) ProtoMessage() {}
+
+func (x *ItemTrend) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregation_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemTrend.ProtoReflect.Descriptor instead.
+func (*ItemTrend) Descriptor() ([]byte, []int) {
+	return file_aggregation_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ItemTrend) GetQuantity() []int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return nil
+}
+
+func (x *ItemTrend) GetTimes() []int64 {
+	if x != nil {
+		return x.Times
+	}
+	return nil
+}
+
+type StageTrend struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// unix milliseconds
+	Start int64 `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	// keyed by ark item id
+	Results map[string]*ItemTrend `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *StageTrend) Reset() {
+	*x = StageTrend{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_aggregation_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StageTrend) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StageTrend) ProtoMessage() {}
+
+func (x *StageTrend) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregation_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StageTrend.ProtoReflect.Descriptor instead.
+func (*StageTrend) Descriptor() ([]byte, []int) {
+	return file_aggregation_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StageTrend) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *StageTrend) GetResults() map[string]*ItemTrend {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type TrendResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// keyed by ark stage id
+	Trend map[string]*StageTrend `protobuf:"bytes,1,rep,name=trend,proto3" json:"trend,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *TrendResponse) Reset() {
+	*x = TrendResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_aggregation_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrendResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrendResponse) ProtoMessage() {}
+
+func (x *TrendResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregation_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrendResponse.ProtoReflect.Descriptor instead.
+func (*TrendResponse) Descriptor() ([]byte, []int) {
+	return file_aggregation_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TrendResponse) GetTrend() map[string]*StageTrend {
+	if x != nil {
+		return x.Trend
+	}
+	return nil
+}
+
+var File_aggregation_proto protoreflect.FileDescriptor
+
+var file_aggregation_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x1a, 0x0c, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0x85, 0x02, 0x0a, 0x11, 0x44, 0x72, 0x6f, 0x70, 0x4d, 0x61, 0x74, 0x72, 0x69, 0x78,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x07, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x52, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x2a, 0x0a, 0x11, 0x73, 0x68, 0x6f, 0x77,
+	0x5f, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x5f, 0x7a, 0x6f, 0x6e, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0f, 0x73, 0x68, 0x6f, 0x77, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x5a,
+	0x6f, 0x6e, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x64,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x61, 0x67, 0x65, 0x49, 0x64,
+	0x73, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x74, 0x65, 0x6d, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x07, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x64, 0x73, 0x12, 0x22, 0x0a, 0x0a,
+	0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03,
+	0x48, 0x00, 0x52, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x88, 0x01, 0x01,
+	0x12, 0x38, 0x0a, 0x0f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x63, 0x61, 0x74, 0x65, 0x67,
+	0x6f, 0x72, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x53, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x52, 0x0e, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x22, 0xc7, 0x01, 0x0a, 0x11, 0x44, 0x72,
+	0x6f, 0x70, 0x4d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x45, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x73, 0x74, 0x61, 0x67, 0x65, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x74,
+	0x65, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x74, 0x65,
+	0x6d, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x71, 0x75, 0x61,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x74, 0x64, 0x5f, 0x64, 0x65, 0x76,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x73, 0x74, 0x64, 0x44, 0x65, 0x76, 0x12, 0x14,
+	0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x12, 0x15, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x03, 0x48, 0x00, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f,
+	0x65, 0x6e, 0x64, 0x22, 0x40, 0x0a, 0x12, 0x44, 0x72, 0x6f, 0x70, 0x4d, 0x61, 0x74, 0x72, 0x69,
+	0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x06, 0x6d, 0x61, 0x74,
+	0x72, 0x69, 0x78, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x44, 0x72, 0x6f, 0x70,
+	0x4d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x45, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x6d,
+	0x61, 0x74, 0x72, 0x69, 0x78, 0x22, 0xd0, 0x01, 0x0a, 0x14, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72,
+	0x6e, 0x4d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f,
+	0x0a, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x07,
+	0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x52, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12,
+	0x2a, 0x0a, 0x11, 0x73, 0x68, 0x6f, 0x77, 0x5f, 0x61, 0x6c, 0x6c, 0x5f, 0x70, 0x61, 0x74, 0x74,
+	0x65, 0x72, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x73, 0x68, 0x6f, 0x77,
+	0x41, 0x6c, 0x6c, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x73, 0x12, 0x22, 0x0a, 0x0a, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48,
+	0x00, 0x52, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12,
+	0x38, 0x0a, 0x0f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f,
+	0x72, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x52, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x61, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x22, 0x42, 0x0a, 0x0b, 0x50, 0x61, 0x74, 0x74,
+	0x65, 0x72, 0x6e, 0x44, 0x72, 0x6f, 0x70, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x74, 0x65, 0x6d, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x64,
+	0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0xbc, 0x01, 0x0a,
+	0x14, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x4d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x45, 0x6c,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x67, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x74, 0x61, 0x67, 0x65, 0x49, 0x64,
+	0x12, 0x22, 0x0a, 0x05, 0x64, 0x72, 0x6f, 0x70, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0c, 0x2e, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x44, 0x72, 0x6f, 0x70, 0x52, 0x05, 0x64,
+	0x72, 0x6f, 0x70, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75,
+	0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x71, 0x75,
+	0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x15, 0x0a, 0x03,
+	0x65, 0x6e, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x03, 0x65, 0x6e, 0x64,
+	0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x65, 0x6e, 0x64, 0x22, 0x55, 0x0a, 0x15, 0x50,
+	0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x4d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x0e, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x5f,
+	0x6d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x50,
+	0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x4d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x45, 0x6c, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x0d, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x4d, 0x61, 0x74, 0x72,
+	0x69, 0x78, 0x22, 0x2f, 0x0a, 0x0c, 0x54, 0x72, 0x65, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1f, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x07, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x52, 0x06, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x22, 0x3d, 0x0a, 0x09, 0x49, 0x74, 0x65, 0x6d, 0x54, 0x72, 0x65, 0x6e, 0x64,
+	0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x03, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x03, 0x52, 0x05, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x22, 0x9e, 0x01, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x67, 0x65, 0x54, 0x72, 0x65, 0x6e,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x32, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x53, 0x74, 0x61, 0x67, 0x65,
+	0x54, 0x72, 0x65, 0x6e, 0x64, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x1a, 0x46, 0x0a, 0x0c, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x20, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x49,
+	0x74, 0x65, 0x6d, 0x54, 0x72, 0x65, 0x6e, 0x64, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0x87, 0x01, 0x0a, 0x0d, 0x54, 0x72, 0x65, 0x6e, 0x64, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x05, 0x74, 0x72, 0x65, 0x6e, 0x64, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x54, 0x72, 0x65, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x54, 0x72, 0x65, 0x6e, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x05, 0x74, 0x72, 0x65, 0x6e, 0x64, 0x1a, 0x45, 0x0a, 0x0a, 0x54, 0x72, 0x65, 0x6e, 0x64, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x21, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x54, 0x72, 0x65,
+	0x6e, 0x64, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x2a, 0x64, 0x0a,
+	0x0e, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12,
+	0x17, 0x0a, 0x13, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f, 0x43, 0x41, 0x54, 0x45, 0x47, 0x4f,
+	0x52, 0x59, 0x5f, 0x41, 0x4c, 0x4c, 0x10, 0x00, 0x12, 0x1d, 0x0a, 0x19, 0x53, 0x4f, 0x55, 0x52,
+	0x43, 0x45, 0x5f, 0x43, 0x41, 0x54, 0x45, 0x47, 0x4f, 0x52, 0x59, 0x5f, 0x41, 0x55, 0x54, 0x4f,
+	0x4d, 0x41, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x1a, 0x0a, 0x16, 0x53, 0x4f, 0x55, 0x52, 0x43,
+	0x45, 0x5f, 0x43, 0x41, 0x54, 0x45, 0x47, 0x4f, 0x52, 0x59, 0x5f, 0x4d, 0x41, 0x4e, 0x55, 0x41,
+	0x4c, 0x10, 0x02, 0x32, 0xb5, 0x01, 0x0a, 0x0b, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x38, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x44, 0x72, 0x6f, 0x70, 0x4d, 0x61,
+	0x74, 0x72, 0x69, 0x78, 0x12, 0x12, 0x2e, 0x44, 0x72, 0x6f, 0x70, 0x4d, 0x61, 0x74, 0x72, 0x69,
+	0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x44, 0x72, 0x6f, 0x70, 0x4d,
+	0x61, 0x74, 0x72, 0x69, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a,
+	0x10, 0x47, 0x65, 0x74, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x4d, 0x61, 0x74, 0x72, 0x69,
+	0x78, 0x12, 0x15, 0x2e, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x4d, 0x61, 0x74, 0x72, 0x69,
+	0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x50, 0x61, 0x74, 0x74, 0x65,
+	0x72, 0x6e, 0x4d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x29, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x54, 0x72, 0x65, 0x6e, 0x64, 0x12, 0x0d, 0x2e, 0x54,
+	0x72, 0x65, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x54, 0x72,
+	0x65, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2c, 0x5a, 0x2a, 0x65,
+	0x78, 0x75, 0x73, 0x69, 0x61, 0x69, 0x2e, 0x64, 0x65, 0x76, 0x2f, 0x62, 0x61, 0x63, 0x6b, 0x65,
+	0x6e, 0x64, 0x2d, 0x6e, 0x65, 0x78, 0x74, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_aggregation_proto_rawDescOnce sync.Once
+	file_aggregation_proto_rawDescData = file_aggregation_proto_rawDesc
+)
+
+func file_aggregation_proto_rawDescGZIP() []byte {
+	file_aggregation_proto_rawDescOnce.Do(func() {
+		file_aggregation_proto_rawDescData = protoimpl.X.CompressGZIP(file_aggregation_proto_rawDescData)
+	})
+	return file_aggregation_proto_rawDescData
+}
+
+var file_aggregation_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_aggregation_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_aggregation_proto_goTypes = []interface{}{
+	(SourceCategory)(0),           // 0: SourceCategory
+	(*DropMatrixRequest)(nil),     // 1: DropMatrixRequest
+	(*DropMatrixElement)(nil),     // 2: DropMatrixElement
+	(*DropMatrixResponse)(nil),    // 3: DropMatrixResponse
+	(*PatternMatrixRequest)(nil),  // 4: PatternMatrixRequest
+	(*PatternDrop)(nil),           // 5: PatternDrop
+	(*PatternMatrixElement)(nil),  // 6: PatternMatrixElement
+	(*PatternMatrixResponse)(nil), // 7: PatternMatrixResponse
+	(*TrendRequest)(nil),          // 8: TrendRequest
+	(*ItemTrend)(nil),             // 9: ItemTrend
+	(*StageTrend)(nil),            // 10: StageTrend
+	(*TrendResponse)(nil),         // 11: TrendResponse
+	nil,                           // 12: StageTrend.ResultsEntry
+	nil,                           // 13: TrendResponse.TrendEntry
+	(Server)(0),                   // 14: Server
+}
+var file_aggregation_proto_depIdxs = []int32{
+	14, // 0: DropMatrixRequest.server:type_name -> Server
+	0,  // 1: DropMatrixRequest.source_category:type_name -> SourceCategory
+	2,  // 2: DropMatrixResponse.matrix:type_name -> DropMatrixElement
+	14, // 3: PatternMatrixRequest.server:type_name -> Server
+	0,  // 4: PatternMatrixRequest.source_category:type_name -> SourceCategory
+	5,  // 5: PatternMatrixElement.drops:type_name -> PatternDrop
+	6,  // 6: PatternMatrixResponse.pattern_matrix:type_name -> PatternMatrixElement
+	14, // 7: TrendRequest.server:type_name -> Server
+	12, // 8: StageTrend.results:type_name -> StageTrend.ResultsEntry
+	13, // 9: TrendResponse.trend:type_name -> TrendResponse.TrendEntry
+	9,  // 10: StageTrend.ResultsEntry.value:type_name -> ItemTrend
+	10, // 11: TrendResponse.TrendEntry.value:type_name -> StageTrend
+	1,  // 12: Aggregation.GetDropMatrix:input_type -> DropMatrixRequest
+	4,  // 13: Aggregation.GetPatternMatrix:input_type -> PatternMatrixRequest
+	8,  // 14: Aggregation.GetTrend:input_type -> TrendRequest
+	3,  // 15: Aggregation.GetDropMatrix:output_type -> DropMatrixResponse
+	7,  // 16: Aggregation.GetPatternMatrix:output_type -> PatternMatrixResponse
+	11, // 17: Aggregation.GetTrend:output_type -> TrendResponse
+	15, // [15:18] is the sub-list for method output_type
+	12, // [12:15] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_aggregation_proto_init() }
+func file_aggregation_proto_init() {
+	if File_aggregation_proto != nil {
+		return
+	}
+	file_shared_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_aggregation_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DropMatrixRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_aggregation_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DropMatrixElement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_aggregation_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DropMatrixResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_aggregation_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatternMatrixRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_aggregation_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatternDrop); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_aggregation_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatternMatrixElement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_aggregation_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatternMatrixResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_aggregation_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrendRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_aggregation_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ItemTrend); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_aggregation_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StageTrend); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_aggregation_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrendResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_aggregation_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	file_aggregation_proto_msgTypes[1].OneofWrappers = []interface{}{}
+	file_aggregation_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	file_aggregation_proto_msgTypes[5].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_aggregation_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_aggregation_proto_goTypes,
+		DependencyIndexes: file_aggregation_proto_depIdxs,
+		EnumInfos:         file_aggregation_proto_enumTypes,
+		MessageInfos:      file_aggregation_proto_msgTypes,
+	}.Build()
+	File_aggregation_proto = out.File
+	file_aggregation_proto_rawDesc = nil
+	file_aggregation_proto_goTypes = nil
+	file_aggregation_proto_depIdxs = nil
+}
@@ -0,0 +1,183 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: aggregation.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Aggregation_GetDropMatrix_FullMethodName    = "/Aggregation/GetDropMatrix"
+	Aggregation_GetPatternMatrix_FullMethodName = "/Aggregation/GetPatternMatrix"
+	Aggregation_GetTrend_FullMethodName         = "/Aggregation/GetTrend"
+)
+
+// AggregationClient is the client API for Aggregation service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AggregationClient interface {
+	GetDropMatrix(ctx context.Context, in *DropMatrixRequest, opts ...grpc.CallOption) (*DropMatrixResponse, error)
+	GetPatternMatrix(ctx context.Context, in *PatternMatrixRequest, opts ...grpc.CallOption) (*PatternMatrixResponse, error)
+	GetTrend(ctx context.Context, in *TrendRequest, opts ...grpc.CallOption) (*TrendResponse, error)
+}
+
+type aggregationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAggregationClient(cc grpc.ClientConnInterface) AggregationClient {
+	return &aggregationClient{cc}
+}
+
+func (c *aggregationClient) GetDropMatrix(ctx context.Context, in *DropMatrixRequest, opts ...grpc.CallOption) (*DropMatrixResponse, error) {
+	out := new(DropMatrixResponse)
+	err := c.cc.Invoke(ctx, Aggregation_GetDropMatrix_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aggregationClient) GetPatternMatrix(ctx context.Context, in *PatternMatrixRequest, opts ...grpc.CallOption) (*PatternMatrixResponse, error) {
+	out := new(PatternMatrixResponse)
+	err := c.cc.Invoke(ctx, Aggregation_GetPatternMatrix_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aggregationClient) GetTrend(ctx context.Context, in *TrendRequest, opts ...grpc.CallOption) (*TrendResponse, error) {
+	out := new(TrendResponse)
+	err := c.cc.Invoke(ctx, Aggregation_GetTrend_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AggregationServer is the server API for Aggregation service.
+// All implementations must embed UnimplementedAggregationServer
+// for forward compatibility
+type AggregationServer interface {
+	GetDropMatrix(context.Context, *DropMatrixRequest) (*DropMatrixResponse, error)
+	GetPatternMatrix(context.Context, *PatternMatrixRequest) (*PatternMatrixResponse, error)
+	GetTrend(context.Context, *TrendRequest) (*TrendResponse, error)
+	mustEmbedUnimplementedAggregationServer()
+}
+
+// UnimplementedAggregationServer must be embedded to have forward compatible implementations.
+type UnimplementedAggregationServer struct {
+}
+
+func (UnimplementedAggregationServer) GetDropMatrix(context.Context, *DropMatrixRequest) (*DropMatrixResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDropMatrix not implemented")
+}
+func (UnimplementedAggregationServer) GetPatternMatrix(context.Context, *PatternMatrixRequest) (*PatternMatrixResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPatternMatrix not implemented")
+}
+func (UnimplementedAggregationServer) GetTrend(context.Context, *TrendRequest) (*TrendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrend not implemented")
+}
+func (UnimplementedAggregationServer) mustEmbedUnimplementedAggregationServer() {}
+
+// UnsafeAggregationServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AggregationServer will
+// result in compilation errors.
+type UnsafeAggregationServer interface {
+	mustEmbedUnimplementedAggregationServer()
+}
+
+func RegisterAggregationServer(s grpc.ServiceRegistrar, srv AggregationServer) {
+	s.RegisterService(&Aggregation_ServiceDesc, srv)
+}
+
+func _Aggregation_GetDropMatrix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DropMatrixRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AggregationServer).GetDropMatrix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Aggregation_GetDropMatrix_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AggregationServer).GetDropMatrix(ctx, req.(*DropMatrixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Aggregation_GetPatternMatrix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatternMatrixRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AggregationServer).GetPatternMatrix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Aggregation_GetPatternMatrix_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AggregationServer).GetPatternMatrix(ctx, req.(*PatternMatrixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Aggregation_GetTrend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AggregationServer).GetTrend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Aggregation_GetTrend_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AggregationServer).GetTrend(ctx, req.(*TrendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Aggregation_ServiceDesc is the grpc.ServiceDesc for Aggregation service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Aggregation_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "Aggregation",
+	HandlerType: (*AggregationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDropMatrix",
+			Handler:    _Aggregation_GetDropMatrix_Handler,
+		},
+		{
+			MethodName: "GetPatternMatrix",
+			Handler:    _Aggregation_GetPatternMatrix_Handler,
+		},
+		{
+			MethodName: "GetTrend",
+			Handler:    _Aggregation_GetTrend_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "aggregation.proto",
+}
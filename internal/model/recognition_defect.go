@@ -19,4 +19,7 @@ type RecognitionDefect struct {
 	ImageURI          string          `bun:"image_uri" json:"imageUrl"`
 	RecognitionResult json.RawMessage `bun:"recognition_result,notnull" json:"recognitionResult"`
 	Environment       json.RawMessage `bun:"environment,notnull" json:"environment"`
+
+	// APIKeyID is the API key of the official recognition client that uploaded the defect through the v3 API
+	APIKeyID int `bun:"api_key_id,nullzero" json:"apiKeyId,omitempty"`
 }
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"gopkg.in/guregu/null.v3"
+)
+
+// RecognitionStageHash maps a perceptual hash of the result screen of a stage to the stage, for the recognition
+// clients to identify the stages by. Hashes are unique per server.
+type RecognitionStageHash struct {
+	bun.BaseModel `bun:"recognition_stage_hashes,alias:rsh"`
+
+	HashID    int         `bun:",pk,autoincrement" json:"id"`
+	Server    string      `bun:",notnull" json:"server"`
+	StageID   int         `bun:",notnull" json:"stageId"`
+	Hash      string      `bun:",notnull" json:"hash"`
+	Comment   null.String `json:"comment" swaggertype:"string"`
+	CreatedAt time.Time   `bun:",notnull,default:current_timestamp" json:"createdAt"`
+	UpdatedAt time.Time   `bun:",notnull,default:current_timestamp" json:"updatedAt"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+const (
+	RefreshJobRealmMatrix  = "matrix"
+	RefreshJobRealmPattern = "pattern"
+	RefreshJobRealmTrend   = "trend"
+	RefreshJobRealmRollup  = "rollup"
+
+	RefreshJobStatusPending   = "PENDING"
+	RefreshJobStatusRunning   = "RUNNING"
+	RefreshJobStatusSucceeded = "SUCCEEDED"
+	RefreshJobStatusFailed    = "FAILED"
+)
+
+// RefreshJob tracks an admin-triggered recalculation of matrix, pattern or trend results.
+// Every day to recalculate counts as one unit towards Total.
+type RefreshJob struct {
+	ID     string   `json:"id"`
+	Realm  string   `json:"realm"`
+	Server string   `json:"server"`
+	Dates  []string `json:"dates,omitempty"`
+	// StageIDs narrows the recalculation of the matrices down to the stages, rather than every stage
+	StageIDs   []int      `json:"stageIds,omitempty"`
+	Status     string     `json:"status"`
+	Done       int        `json:"done"`
+	Total      int        `json:"total"`
+	Errors     []string   `json:"errors"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Finished reports whether the job has reached a terminal status
+func (j *RefreshJob) Finished() bool {
+	return j.Status == RefreshJobStatusSucceeded || j.Status == RefreshJobStatusFailed
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"gopkg.in/guregu/null.v3"
+)
+
+// The verdicts of the report reviews
+const (
+	ReportReviewVerdictVerified = "verified"
+	ReportReviewVerdictInvalid  = "invalid"
+)
+
+// ReportReview is the verdict of a moderator on a report sampled for inspection. The reports reviewed as invalid are
+// excluded from the aggregations.
+type ReportReview struct {
+	bun.BaseModel `bun:"report_reviews,alias:rr"`
+
+	ReportID int    `bun:",pk" json:"reportId"`
+	Verdict  string `bun:",notnull" json:"verdict"`
+	// Signals are the risk signals the report carried when it was reviewed, which the risk model learns from
+	Signals []string `bun:",array" json:"signals"`
+	// RiskScore is the risk the report was scored at when it was reviewed
+	RiskScore         float64     `json:"riskScore"`
+	ReviewerAccountID int         `json:"reviewerAccountId"`
+	Comment           null.String `json:"comment" swaggertype:"string"`
+	ReviewedAt        time.Time   `bun:",notnull,default:current_timestamp" json:"reviewedAt"`
+}
+
+// RiskSignalStats is how many of the reviewed reports carried a risk signal, and how many of them were invalid
+type RiskSignalStats struct {
+	Signal   string `bun:"signal" json:"signal"`
+	Reviewed int    `bun:"reviewed" json:"reviewed"`
+	Invalid  int    `bun:"invalid" json:"invalid"`
+}
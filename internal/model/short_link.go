@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/uptrace/bun"
+)
+
+// ShortLink stores a normalized advanced query under a short code, so that the views filtered by it can be shared.
+// Links expire unless shared again before their expiry.
+type ShortLink struct {
+	bun.BaseModel `bun:"short_links,alias:sl"`
+
+	Code string `bun:",pk" json:"code"`
+	// PayloadHash is the sha256 of Payload, which deduplicates the links
+	PayloadHash string          `bun:",notnull" json:"-"`
+	Payload     json.RawMessage `bun:"type:jsonb,notnull" json:"payload" swaggertype:"object"`
+	CreatedAt   time.Time       `bun:",notnull,default:current_timestamp" json:"createdAt"`
+	ExpiresAt   time.Time       `bun:",notnull" json:"expiresAt"`
+}
@@ -15,3 +15,16 @@ type Snapshot struct {
 	Version    string     `bun:"version" json:"version"`
 	Content    string     `bun:"content" json:"content"`
 }
+
+// MetadataSnapshotKey is the snapshot key under which metadata snapshots are recorded
+const MetadataSnapshotKey = "metadata"
+
+// MetadataSnapshotContent is the content of a metadata snapshot, recorded before metadata
+// is mutated via the admin APIs so that the change can be rolled back.
+type MetadataSnapshotContent struct {
+	Items      []*Item      `json:"items"`
+	Zones      []*Zone      `json:"zones"`
+	Stages     []*Stage     `json:"stages"`
+	TimeRanges []*TimeRange `json:"timeRanges"`
+	DropInfos  []*DropInfo  `json:"dropInfos"`
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/goccy/go-json"
 
 	"github.com/uptrace/bun"
@@ -14,6 +16,8 @@ type Stage struct {
 	StageID int `bun:",pk,autoincrement" json:"penguinStageId"`
 	// ArkStageID (stageId) is the previously used, string form ID of the stage; in JSON-representation `stageId` is used as key.
 	ArkStageID string `json:"stageId"`
+	// Game is the game the stage is from, which defaults to arknights.
+	Game string `bun:",nullzero,notnull,default:'arknights'" json:"game"`
 	// ZoneID is the numerical ID of the zone the stage is in.
 	ZoneID int `json:"zoneId"`
 	// StageType is the type of the stage, e.g. "MAIN", "SUB", "ACTIVITY" and "DAILY".
@@ -21,13 +25,16 @@ type Stage struct {
 	// ExtraProcessType is the type of extra process that is used in the stage, e.g. "GACHABOX".
 	ExtraProcessType null.String `json:"extraProcessType" swaggertype:"string"`
 	// Code is a map with language code as key and the code of the stage in that language as value.
-	Code json.RawMessage `json:"code"`
+	Code json.RawMessage `json:"code" swaggertype:"object"`
 	// Sanity is the sanity requirement for a full clear of the stage.
 	Sanity null.Int `json:"sanity" swaggertype:"integer"`
 	// Existence is a map with server code as key and the existence of the item in that server as value.
 	Existence json.RawMessage `json:"existence" swaggertype:"object"`
 	// MinClearTime is the minimum time (in milliseconds as a duration) it takes to clear the stage, referencing from prts.wiki
 	MinClearTime null.Int `json:"minClearTime" swaggertype:"integer"`
+	// DeletedAt is when the stage has been deleted. Deleted stages are left out of every query unless asked for,
+	// and may be restored.
+	DeletedAt *time.Time `bun:",soft_delete,nullzero" json:"deletedAt,omitempty"`
 }
 
 type StageExtended struct {
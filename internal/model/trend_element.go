@@ -13,6 +13,7 @@ type TrendElement struct {
 	EndTime        *time.Time `json:"endTime"`
 	Quantity       int        `json:"quantity"`
 	Times          int        `json:"times"`
+	Reporters      int        `json:"reporters"`
 	Server         string     `json:"server"`
 	SourceCategory string     `json:"sourceCategory"` // sourceCategory can be: "automated", "manual", "all"
 }
@@ -0,0 +1,10 @@
+package model
+
+// Tunable is a setting that can be changed at runtime, without a redeploy
+type Tunable struct {
+	Key        string `json:"key" example:"ratelimit.result"`
+	Type       string `json:"type" example:"int"`
+	Value      string `json:"value" example:"300"`
+	Default    string `json:"default" example:"300"`
+	Overridden bool   `json:"overridden"`
+}
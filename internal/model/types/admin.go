@@ -70,3 +70,18 @@ type ForeignTimeRangeString struct {
 	Start string `json:"start"`
 	End   string `json:"end"`
 }
+
+type ResolveAccountAppealRequest struct {
+	Approve    *bool  `json:"approve" validate:"required"`
+	Resolution string `json:"resolution" validate:"max=2000"`
+}
+
+type GrantAccountRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=admin moderator maintainer" required:"true"`
+}
+
+type ShadowBanAccountRequest struct {
+	// RetroactiveSince also excludes the accepted reports of the account created since then from the aggregations.
+	// It must be within the last 90 days.
+	RetroactiveSince *time.Time `json:"retroactiveSince"`
+}
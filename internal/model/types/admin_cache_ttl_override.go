@@ -0,0 +1,12 @@
+package types
+
+import "time"
+
+type SetCacheTTLOverrideRequest struct {
+	// Server restricts the override to a server, rather than every server
+	Server string `json:"server" validate:"omitempty,arkserver" example:"CN"`
+	// TTL is a duration such as 5m, at least a second
+	TTL string `json:"ttl" validate:"required" required:"true" example:"5m"`
+	// ExpiresAt stops the override from applying once it has passed, e.g. by the end of the launch of an event
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,14 @@
+package types
+
+import "gopkg.in/guregu/null.v3"
+
+// SaveClientVersionRequest replaces the gate of the versions of a client allowed to submit reports
+type SaveClientVersionRequest struct {
+	// MinVersion is the lowest semantic version accepted; null accepts every version
+	MinVersion null.String `json:"minVersion" validate:"omitempty,max=32,semverprefixed" swaggertype:"string" example:"v4.12.0"`
+	// BlockedVersions are rejected regardless of MinVersion, e.g. a buggy release having no fix yet
+	BlockedVersions []string `json:"blockedVersions" validate:"max=64,dive,required,printascii,max=128" example:"v4.12.3"`
+	// Disabled rejects every version of the client
+	Disabled bool        `json:"disabled"`
+	Message  null.String `json:"message" validate:"omitempty,max=256" swaggertype:"string"`
+}
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"time"
+
+	"gopkg.in/guregu/null.v3"
+)
+
+// CutOverDropInfosRequest versions the drop tables of permanent stages: the drop infos given take effect at CutOverAt
+// in a new time range, while the previous version stays in effect for the reports submitted before.
+type CutOverDropInfosRequest struct {
+	// DryRun validates the cut over and applies it in a transaction that is always rolled back
+	DryRun    bool        `json:"dryRun"`
+	Server    string      `json:"server" validate:"required,arkserver" required:"true" example:"CN"`
+	CutOverAt time.Time   `json:"cutOverAt" validate:"required" required:"true"`
+	Name      null.String `json:"name" validate:"omitempty,max=128" swaggertype:"string"`
+	Comment   null.String `json:"comment" validate:"omitempty,max=256" swaggertype:"string"`
+	// DropInfos are the complete new drop tables of the stages they refer to; their server must be Server
+	DropInfos []OnboardEventDropInfo `json:"dropInfos" validate:"required,min=1,dive" required:"true"`
+}
+
+// ApplyDropInfoBoundsRequest replaces the bounds of the drop infos of a server, usually with those suggested from
+// the patterns observed
+type ApplyDropInfoBoundsRequest struct {
+	Server string                  `json:"server" validate:"required,arkserver" required:"true" example:"CN"`
+	Bounds []*DropInfoBoundsUpdate `json:"bounds" validate:"required,min=1,max=200,dive,required" required:"true"`
+}
+
+type DropInfoBoundsUpdate struct {
+	DropID     int   `json:"dropId" validate:"required,gt=0" required:"true"`
+	Lower      int   `json:"lower" validate:"gte=0"`
+	Upper      int   `json:"upper" validate:"gtefield=Lower"`
+	Exceptions []int `json:"exceptions,omitempty" validate:"omitempty,dive,gte=0"`
+}
@@ -0,0 +1,101 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+
+	"gopkg.in/guregu/null.v3"
+)
+
+type OnboardEventRequest struct {
+	// DryRun validates the bundle and applies it in a transaction that is always rolled back
+	DryRun     bool                   `json:"dryRun"`
+	Zone       OnboardEventZone       `json:"zone" validate:"required" required:"true"`
+	Stages     []OnboardEventStage    `json:"stages" validate:"required,min=1,dive" required:"true"`
+	TimeRanges []OnboardEventRange    `json:"timeRanges" validate:"required,min=1,dive" required:"true"`
+	DropInfos  []OnboardEventDropInfo `json:"dropInfos" validate:"dive"`
+}
+
+type OnboardEventZone struct {
+	ArkZoneID  string      `json:"zoneId" validate:"required,printascii,max=64" required:"true" example:"act24side_zone1"`
+	Index      int         `json:"index"`
+	Category   string      `json:"category" validate:"required,max=32" required:"true" example:"ACTIVITY"`
+	Type       null.String `json:"type" validate:"omitempty,max=32" swaggertype:"string"`
+	Name       I18nName    `json:"name" validate:"required" required:"true"`
+	Existence  Existence   `json:"existence" validate:"required" required:"true"`
+	Background null.String `json:"background" validate:"omitempty,max=256" swaggertype:"string"`
+}
+
+type OnboardEventStage struct {
+	ArkStageID       string      `json:"stageId" validate:"required,printascii,max=64" required:"true" example:"act24side_01"`
+	StageType        string      `json:"stageType" validate:"required,max=32" required:"true" example:"ACTIVITY"`
+	ExtraProcessType null.String `json:"extraProcessType" validate:"omitempty,max=32" swaggertype:"string"`
+	Code             I18nName    `json:"code" validate:"required" required:"true"`
+	Sanity           null.Int    `json:"sanity" validate:"omitempty,gte=0" swaggertype:"integer"`
+	// MinClearTime is in milliseconds
+	MinClearTime null.Int `json:"minClearTime" validate:"omitempty,gte=0" swaggertype:"integer"`
+}
+
+type OnboardEventRange struct {
+	Server    string      `json:"server" validate:"required,arkserver" required:"true" example:"CN"`
+	Name      null.String `json:"name" validate:"omitempty,max=128" swaggertype:"string"`
+	StartTime time.Time   `json:"startTime" validate:"required" required:"true"`
+	// EndTime is optional: when omitted, the time range is open-ended
+	EndTime *time.Time  `json:"endTime"`
+	Comment null.String `json:"comment" validate:"omitempty,max=256" swaggertype:"string"`
+}
+
+type OnboardEventDropInfo struct {
+	Server     string `json:"server" validate:"required,arkserver" required:"true" example:"CN"`
+	ArkStageID string `json:"stageId" validate:"required" required:"true" example:"act24side_01"`
+	// ArkItemID is empty for drop infos describing a drop type as a whole
+	ArkItemID   string              `json:"itemId" example:"30013"`
+	DropType    string              `json:"dropType" validate:"required,oneof=REGULAR SPECIAL EXTRA FURNITURE RECOGNITION_ONLY" required:"true"`
+	Accumulable bool                `json:"accumulable"`
+	Bounds      *OnboardEventBounds `json:"bounds" validate:"required" required:"true"`
+	Extras      json.RawMessage     `json:"extras,omitempty" swaggertype:"object"`
+}
+
+type OnboardEventBounds struct {
+	Upper      int   `json:"upper" validate:"gte=0,gtefield=Lower"`
+	Lower      int   `json:"lower" validate:"gte=0"`
+	Exceptions []int `json:"exceptions,omitempty"`
+}
+
+type OnboardEventViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type CloneRerunEventRequest struct {
+	// DryRun validates the cloned bundle without persisting it
+	DryRun          bool   `json:"dryRun"`
+	SourceArkZoneID string `json:"sourceZoneId" validate:"required,printascii" required:"true" example:"act11d0_zone1"`
+	SourceServer    string `json:"sourceServer" validate:"required,arkserver" required:"true" example:"CN"`
+	// SourceRangeID is the time range to copy the drop infos from. Defaults to the latest time range of the source zone.
+	SourceRangeID int `json:"sourceRangeId" validate:"gte=0"`
+	// TargetArkZoneID defaults to SourceArkZoneID, in which case the stages are reused as-is
+	TargetArkZoneID string `json:"targetZoneId" validate:"omitempty,printascii,max=64" example:"act11d0_rep_zone1"`
+	// TargetZoneName defaults to the name of the source zone
+	TargetZoneName *I18nName `json:"targetZoneName"`
+	// StageIDMap maps source ark stage ids to target ones. When the target zone differs from the source zone,
+	// stages not present in the map receive the rerun suffix `_rep`.
+	StageIDMap map[string]string `json:"stageIdMap"`
+	TimeRange  OnboardEventRange `json:"timeRange" validate:"required" required:"true"`
+}
+
+// SaveEventRequest creates or updates an event, from which the time ranges and the existence of its zones and their
+// stages in each scheduled server are derived
+type SaveEventRequest struct {
+	Name string `json:"name" validate:"required,max=128" required:"true" example:"act24side"`
+	// ArkZoneIDs are the zones released together with the event
+	ArkZoneIDs []string            `json:"zoneIds" validate:"required,min=1,dive,required,printascii" required:"true"`
+	Schedules  []SaveEventSchedule `json:"schedules" validate:"required,min=1,dive" required:"true"`
+}
+
+type SaveEventSchedule struct {
+	Server   string    `json:"server" validate:"required,arkserver" required:"true" example:"CN"`
+	OpenTime time.Time `json:"openTime" validate:"required" required:"true"`
+	// CloseTime is optional: when omitted, the event is open-ended
+	CloseTime *time.Time `json:"closeTime"`
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"time"
+
+	"gopkg.in/guregu/null.v3"
+)
+
+// SaveExclusionRuleRequest creates or updates an exclusion rule. At least one criterion other than the server is
+// required, and a criterion left empty matches every report.
+type SaveExclusionRuleRequest struct {
+	Name    string      `json:"name" validate:"required,max=128" required:"true" example:"bad recognition batch"`
+	Comment null.String `json:"comment" validate:"omitempty,max=1024" swaggertype:"string"`
+	Enabled bool        `json:"enabled"`
+	Server  null.String `json:"server" validate:"omitempty,arkserver" swaggertype:"string" example:"CN"`
+	// PenguinID is the account of which the reports are excluded
+	PenguinID null.String `json:"penguinId" validate:"omitempty,max=32" swaggertype:"string"`
+	// IPRange is a CIDR, or a single IP, the IP of the excluded reports falls in
+	IPRange    null.String `json:"ipRange" validate:"omitempty,max=64" swaggertype:"string" example:"203.0.113.0/24"`
+	SourceName null.String `json:"sourceName" validate:"omitempty,max=64" swaggertype:"string" example:"MeoAssistant"`
+	// VersionPattern is matched against the version of the reports with LIKE, e.g. v4.12.% for every v4.12 release
+	VersionPattern null.String `json:"versionPattern" validate:"omitempty,max=64" swaggertype:"string" example:"v4.12.%"`
+	// ArkStageID only excludes the reports of the stage
+	ArkStageID null.String `json:"arkStageId" validate:"omitempty,max=64" swaggertype:"string" example:"main_01-07"`
+	StartTime  *time.Time  `json:"startTime"`
+	EndTime    *time.Time  `json:"endTime"`
+	// ExpiresAt disables the rule once it has passed
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,7 @@
+package types
+
+type SetFeatureFlagRequest struct {
+	Enabled    bool     `json:"enabled"`
+	Servers    []string `json:"servers" validate:"dive,arkserver" example:"CN"`
+	Percentage int      `json:"percentage" validate:"min=0,max=100" example:"10"`
+}
@@ -0,0 +1,16 @@
+package types
+
+import "gopkg.in/guregu/null.v3"
+
+// UpdateItemIconsRequest sets the sprites and icons of the items listed, leaving the others untouched
+type UpdateItemIconsRequest struct {
+	Icons []*AdminItemIcon `json:"icons" validate:"required,min=1,max=1000,dive,required" required:"true"`
+}
+
+type AdminItemIcon struct {
+	ItemID string `json:"itemId" validate:"required,printascii,max=64" required:"true" example:"30013"`
+	// Sprite is the location of the item's sprite on the sprite image, in a form of Y:X; null removes it
+	Sprite null.String `json:"sprite" validate:"omitempty,max=16" swaggertype:"string" example:"3:5"`
+	// IconURL is the URL of the standalone icon of the item; null removes it
+	IconURL null.String `json:"iconUrl" validate:"omitempty,url,max=512" swaggertype:"string"`
+}
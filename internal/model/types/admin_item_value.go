@@ -0,0 +1,8 @@
+package types
+
+// ReplaceItemValuesRequest replaces every item value of a preset in a server
+type ReplaceItemValuesRequest struct {
+	Server string `json:"server" validate:"required,arkserver" required:"true" example:"CN"`
+	// Values maps the ark item IDs to their sanity values
+	Values map[string]float64 `json:"values" validate:"required,min=1,dive,keys,required,printascii,endkeys,gte=0" required:"true"`
+}
@@ -0,0 +1,11 @@
+package types
+
+type CreateRefreshJobRequest struct {
+	Realm  string `json:"realm" validate:"required,oneof=matrix pattern trend rollup" required:"true" example:"matrix"`
+	Server string `json:"server" validate:"required,arkserver" required:"true" example:"CN"`
+	// Dates to recalculate in a form of 2006-01-02; defaults to today. Ignored for the trend realm.
+	Dates []string `json:"dates" validate:"max=120,dive,datetime=2006-01-02" example:"2022-11-01"`
+	// StageIDs only recalculates the matrices of the stages, rather than every stage. Ignored for the trend and rollup
+	// realms.
+	StageIDs []int `json:"stageIds" validate:"max=500" example:"1"`
+}
@@ -0,0 +1,8 @@
+package types
+
+import "time"
+
+type StartMaintenanceRequest struct {
+	Reason string     `json:"reason" validate:"max=256" example:"database migration"`
+	ETA    *time.Time `json:"eta"`
+}
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"encoding/json"
+
+	"gopkg.in/guregu/null.v3"
+)
+
+// I18nName is the localized name of a metadata object, keyed by language code
+type I18nName struct {
+	ZH string `json:"zh" validate:"required"`
+	EN string `json:"en" validate:"required"`
+	JA string `json:"ja" validate:"required"`
+	KO string `json:"ko" validate:"required"`
+}
+
+type ServerExistence struct {
+	Exist bool `json:"exist"`
+	// OpenTime and CloseTime are in unix milliseconds
+	OpenTime  *int64 `json:"openTime,omitempty" validate:"omitempty,gt=0"`
+	CloseTime *int64 `json:"closeTime,omitempty" validate:"omitempty,gtfield=OpenTime"`
+}
+
+// Existence is the existence of a metadata object per server
+type Existence struct {
+	CN ServerExistence `json:"CN"`
+	US ServerExistence `json:"US"`
+	JP ServerExistence `json:"JP"`
+	KR ServerExistence `json:"KR"`
+}
+
+type AdminItemFields struct {
+	Type      string    `json:"type" validate:"required,max=32" required:"true" example:"MATERIAL"`
+	Name      I18nName  `json:"name" validate:"required" required:"true"`
+	Existence Existence `json:"existence" validate:"required" required:"true"`
+	SortID    int       `json:"sortId" validate:"gte=0"`
+	Rarity    int       `json:"rarity" validate:"gte=0,lte=5"`
+	// Group is an identifier of what the item actually is, e.g. `orirock`
+	Group null.String `json:"group" validate:"omitempty,max=64" swaggertype:"string"`
+	// Sprite is the location of the item's sprite on the sprite image, in a form of Y:X
+	Sprite   null.String     `json:"sprite" validate:"omitempty,max=16" swaggertype:"string" example:"3:5"`
+	Keywords json.RawMessage `json:"keywords" swaggertype:"object"`
+}
+
+type AdminCreateItemRequest struct {
+	ArkItemID string `json:"itemId" validate:"required,printascii,max=64" required:"true" example:"30013"`
+	AdminItemFields
+}
+
+type AdminUpdateItemRequest struct {
+	AdminItemFields
+}
+
+type AdminUpdateStageMinClearTimeRequest struct {
+	// MinClearTime is in milliseconds; null removes the plausibility check of the stage
+	MinClearTime null.Int `json:"minClearTime" swaggertype:"integer" example:"118000"`
+}
@@ -0,0 +1,20 @@
+package types
+
+import (
+	"time"
+
+	"gopkg.in/guregu/null.v3"
+)
+
+// SaveNoticeRequest creates or replaces a notice
+type SaveNoticeRequest struct {
+	Kind     string   `json:"kind" validate:"required,oneof=general maintenance data_issue" required:"true" example:"maintenance"`
+	Severity null.Int `json:"severity" validate:"omitempty,gte=0" swaggertype:"integer"`
+	// Content maps the languages to the content of the notice in them
+	Content map[string]string `json:"content" validate:"required,min=1,dive,keys,oneof=zh en ja ko,endkeys,required,max=4096" required:"true"`
+	// Servers are the servers the notice is shown in; every server when empty
+	Servers []string `json:"servers" validate:"dive,arkserver" example:"CN"`
+	// StartTime and EndTime are the display window of the notice, a bound left null being open
+	StartTime *time.Time `json:"startTime"`
+	EndTime   *time.Time `json:"endTime"`
+}
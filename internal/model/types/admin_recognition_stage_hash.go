@@ -0,0 +1,13 @@
+package types
+
+import "gopkg.in/guregu/null.v3"
+
+// SaveRecognitionStageHashRequest creates or updates a perceptual hash of the result screen of a stage
+type SaveRecognitionStageHashRequest struct {
+	Server string `json:"server" validate:"required,arkserver" required:"true" example:"CN"`
+	// StageID is the ark stage ID the hash identifies
+	StageID string `json:"stageId" validate:"required,printascii,max=64" required:"true" example:"main_01-07"`
+	// Hash is the perceptual hash of the result screen, in hexadecimal
+	Hash    string      `json:"hash" validate:"required,hexadecimal,min=8,max=256" required:"true" example:"f0e1d2c3b4a59687"`
+	Comment null.String `json:"comment" validate:"omitempty,max=256" swaggertype:"string"`
+}
@@ -0,0 +1,16 @@
+package types
+
+import "gopkg.in/guregu/null.v3"
+
+type ReportSamplesQuery struct {
+	Server     string `query:"server" validate:"required,arkserver" required:"true"`
+	ArkStageID string `query:"arkStageId" validate:"required" required:"true" example:"main_01-07"`
+	Limit      int    `query:"limit" validate:"omitempty,gte=1,lte=100"`
+	// Days is how far back the reports are sampled from
+	Days int `query:"days" validate:"omitempty,gte=1,lte=90"`
+}
+
+type ReviewReportRequest struct {
+	Verdict string      `json:"verdict" validate:"required,oneof=verified invalid" required:"true" enums:"verified,invalid"`
+	Comment null.String `json:"comment" validate:"omitempty,max=1024" swaggertype:"string"`
+}
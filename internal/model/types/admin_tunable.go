@@ -0,0 +1,5 @@
+package types
+
+type SetTunableRequest struct {
+	Value string `json:"value" validate:"required" required:"true" example:"600"`
+}
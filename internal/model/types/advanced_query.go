@@ -16,3 +16,21 @@ type AdvancedQuery struct {
 	EndTime        int64     `json:"end" validate:"omitempty,gtfield=StartTime" swaggertype:"integer"`
 	Interval       null.Int  `json:"interval" swaggertype:"integer"`
 }
+
+const (
+	AdvancedQueryTypeMatrix  = "matrix"
+	AdvancedQueryTypeTrend   = "trend"
+	AdvancedQueryTypePattern = "pattern"
+)
+
+// AdvancedQueryV3Request is a batch of advanced queries of different types, which are run concurrently
+type AdvancedQueryV3Request struct {
+	Queries []*AdvancedQueryV3 `json:"queries" validate:"required,max=10,min=1,dive"`
+}
+
+type AdvancedQueryV3 struct {
+	// Type is one of matrix, trend and pattern. Trends require Interval, which splits matrices into segments of
+	// the interval as well, and patterns accept neither Interval nor ItemIDs.
+	Type string `json:"type" validate:"required,oneof=matrix trend pattern" required:"true" example:"trend"`
+	AdvancedQuery
+}
@@ -0,0 +1,27 @@
+package types
+
+type CreateAPIKeyRequest struct {
+	Name    string `json:"name" validate:"required,max=64" required:"true" example:"penguin-bot"`
+	Contact string `json:"contact" validate:"max=256" example:"bot@example.com"`
+	// DailyRequestQuota defaults to the configured quota when left empty; 0 means unlimited
+	DailyRequestQuota *int `json:"dailyRequestQuota" validate:"omitempty,min=0" example:"100000"`
+	// DailyByteQuota defaults to the configured quota when left empty; 0 means unlimited
+	DailyByteQuota *int64 `json:"dailyByteQuota" validate:"omitempty,min=0" example:"10737418240"`
+}
+
+type UpdateAPIKeyQuotaRequest struct {
+	DailyRequestQuota *int   `json:"dailyRequestQuota" validate:"omitempty,min=0" example:"100000"`
+	DailyByteQuota    *int64 `json:"dailyByteQuota" validate:"omitempty,min=0" example:"10737418240"`
+}
+
+type CreateWebhookRequest struct {
+	// URL is where the events are posted to, which must be https and resolve to public addresses only
+	URL    string   `json:"url" validate:"required,url,startswith=https://,max=2048" required:"true" example:"https://example.com/penguin-webhook"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=timerange.opened matrix.refreshed stage.closed anomaly.detected" required:"true" example:"matrix.refreshed"`
+}
+
+type PublishWebhookEventRequest struct {
+	Type string `json:"type" validate:"required,oneof=timerange.opened matrix.refreshed stage.closed anomaly.detected" required:"true" example:"anomaly.detected"`
+	// Data is the event specific payload delivered as-is
+	Data map[string]any `json:"data" validate:"required"`
+}
@@ -0,0 +1,13 @@
+package types
+
+type PlanRequest struct {
+	Server string            `json:"server" validate:"required,arkserver" required:"true" example:"CN"`
+	Items  []PlanRequestItem `json:"items" validate:"required,min=1,max=256,dive" required:"true"`
+	// ExcludeStageIDs are the stages not to be farmed, e.g. those not unlocked yet
+	ExcludeStageIDs []string `json:"excludeStageIds" validate:"max=1024" example:"main_01-07"`
+}
+
+type PlanRequestItem struct {
+	ItemID   string `json:"itemId" validate:"required,printascii,max=64" required:"true" example:"30012"`
+	Quantity int    `json:"quantity" validate:"required,min=1,max=1000000" required:"true" example:"100"`
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"encoding/json"
+)
+
+// V3RecognitionDefectRequest is a case the recognition of an official client was unsure about. Only the metadata of
+// the case is uploaded: screenshots are never part of it.
+type V3RecognitionDefectRequest struct {
+	Server string `json:"server" validate:"required,arkserver" required:"true" example:"CN"`
+	// StageID is the ark stage id recognized, if any
+	StageID   string `json:"stageId" validate:"omitempty,printascii,max=64" example:"main_01-07"`
+	SessionID string `json:"sessionId" validate:"required,printascii,max=64" required:"true"`
+	// Reason describes what the recognition was unsure about
+	Reason string `json:"reason" validate:"required,max=256" required:"true" example:"low confidence on item quantity"`
+	// RecognitionResult is the raw result of the recognizer
+	RecognitionResult json.RawMessage `json:"recognitionResult" validate:"required" required:"true" swaggertype:"object"`
+	Client            V3ReportClient  `json:"client" validate:"required" required:"true"`
+	// Metadata are further details of the environment, e.g. the versions of the recognizer and its assets
+	Metadata map[string]string `json:"metadata" validate:"max=32,dive,keys,max=64,endkeys,max=256"`
+}
+
+type RecognitionDefectsQuery struct {
+	Page  int `query:"page" validate:"gte=0"`
+	Limit int `query:"limit" validate:"omitempty,gte=1,lte=200"`
+	// APIKeyID only lists the defects uploaded with the API key
+	APIKeyID int    `query:"apiKeyId" validate:"gte=0"`
+	Server   string `query:"server" validate:"omitempty,arkserver"`
+}
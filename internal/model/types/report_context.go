@@ -21,3 +21,11 @@ type ReportTask struct {
 	AccountID int    `json:"accountId"`
 	IP        string `json:"ip"`
 }
+
+// ReportOrigin carries the specifics of the HTTP request a report has been submitted with.
+type ReportOrigin struct {
+	AccountID int
+	IP        string
+	// RequestID prefixes the ID of the task the report is queued as.
+	RequestID string
+}
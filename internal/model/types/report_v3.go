@@ -0,0 +1,28 @@
+package types
+
+// V3ReportRequest is the stricter counterpart of SingularReportRequest: stage and item IDs are always the
+// ark IDs, drop types are explicit and each (dropType, itemId) pair is expected to be reported only once.
+type V3ReportRequest struct {
+	Server  string `json:"server" validate:"required,arkserver" required:"true" example:"CN"`
+	StageID string `json:"stageId" validate:"required,printascii,max=64" required:"true" example:"main_01-07"`
+	// Times is the number of times the stage has been cleared with the drops reported
+	Times int            `json:"times" validate:"required,gte=1,lte=6" required:"true" example:"1"`
+	Drops []V3ReportDrop `json:"drops" validate:"max=64,dive"`
+
+	Client   V3ReportClient         `json:"client" validate:"required" required:"true"`
+	Metadata *ReportRequestMetadata `json:"metadata,omitempty" validate:"omitempty"`
+}
+
+type V3ReportDrop struct {
+	DropType string `json:"dropType" validate:"required,oneof=REGULAR_DROP SPECIAL_DROP EXTRA_DROP FURNITURE" required:"true" example:"REGULAR_DROP"`
+	ItemID   string `json:"itemId" validate:"required,printascii,max=64" required:"true" example:"30013"`
+	Quantity int    `json:"quantity" validate:"gte=1,lte=1000" required:"true" example:"1"`
+}
+
+// V3ReportClient identifies the app submitting the report
+type V3ReportClient struct {
+	// Source is the name of the app. Third-party API consumers should change this to their own name.
+	Source string `json:"source" validate:"required,printascii,max=128" required:"true" example:"your-app-name"`
+	// Version is the version of the app
+	Version string `json:"version" validate:"required,printascii,max=128" required:"true" example:"v0.0.0+0000000"`
+}
@@ -0,0 +1,52 @@
+package v2
+
+// Localized results embed the display names of the stages and items in the requested language,
+// so that clients do not have to join the results with the stage and item tables themselves.
+
+// LocalizedDropMatrix
+type LocalizedDropMatrixQueryResult struct {
+	Matrix []*LocalizedDropMatrixElement `json:"matrix"`
+}
+
+type LocalizedDropMatrixElement struct {
+	*OneDropMatrixElement
+	StageName string `json:"stageName" example:"1-7"`
+	ItemName  string `json:"itemName" example:"固源岩"`
+}
+
+// LocalizedPatternMatrix
+type LocalizedPatternMatrixQueryResult struct {
+	PatternMatrix []*LocalizedPatternMatrixElement `json:"pattern_matrix"`
+}
+
+type LocalizedPatternMatrixElement struct {
+	*OnePatternMatrixElement
+	StageName string            `json:"stageName" example:"1-7"`
+	Pattern   *LocalizedPattern `json:"pattern"`
+}
+
+type LocalizedPattern struct {
+	Drops []*LocalizedDrop `json:"drops"`
+}
+
+type LocalizedDrop struct {
+	*OneDrop
+	ItemName string `json:"itemName" example:"固源岩"`
+}
+
+// LocalizedTrend
+type LocalizedTrendQueryResult struct {
+	Trend map[string]*LocalizedStageTrend `json:"trend"`
+}
+
+type LocalizedStageTrend struct {
+	StageName string                            `json:"stageName" example:"1-7"`
+	Results   map[string]*LocalizedOneItemTrend `json:"results"`
+	StartTime int64                             `json:"startTime"`
+	Buckets   []int64                           `json:"buckets"`
+}
+
+type LocalizedOneItemTrend struct {
+	*OneItemTrend
+	ItemName string `json:"itemName" example:"固源岩"`
+}
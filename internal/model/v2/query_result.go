@@ -8,13 +8,18 @@ type DropMatrixQueryResult struct {
 }
 
 type OneDropMatrixElement struct {
-	StageID   string   `json:"stageId" example:"main_01-07"`
-	ItemID    string   `json:"itemId" example:"30012"`
-	Times     int      `json:"times" example:"1061347"`
-	Quantity  int      `json:"quantity" example:"1322056"`
+	StageID  string `json:"stageId" example:"main_01-07"`
+	ItemID   string `json:"itemId" example:"30012"`
+	Times    int    `json:"times" example:"1061347"`
+	Quantity int    `json:"quantity" example:"1322056"`
+	// Rate is quantity / times rounded to 6 decimal places, or 0 when times is 0
+	Rate      float64  `json:"rate" example:"1.245645"`
 	StdDev    float64  `json:"stdDev" example:"0.114514"`
 	StartTime int64    `json:"start" example:"1556676000000"`
 	EndTime   null.Int `json:"end,omitempty" swaggertype:"integer"`
+	// Reporters is the number of distinct accounts having reported the stage within the time range of the element,
+	// as a hint of how widely the sample is spread. It is left out of personal results and where unknown.
+	Reporters int `json:"reporters,omitempty" example:"12345"`
 }
 
 // DropPattern
@@ -23,10 +28,12 @@ type PatternMatrixQueryResult struct {
 }
 
 type OnePatternMatrixElement struct {
-	StageID   string   `json:"stageId" example:"main_01-07"`
-	Pattern   *Pattern `json:"pattern"`
-	Times     int      `json:"times" example:"641734"`
-	Quantity  int      `json:"quantity" example:"159486"`
+	StageID  string   `json:"stageId" example:"main_01-07"`
+	Pattern  *Pattern `json:"pattern"`
+	Times    int      `json:"times" example:"641734"`
+	Quantity int      `json:"quantity" example:"159486"`
+	// Rate is quantity / times rounded to 6 decimal places, or 0 when times is 0
+	Rate      float64  `json:"rate" example:"0.248523"`
 	StartTime int64    `json:"start" example:"1633032000000"`
 	EndTime   null.Int `json:"end,omitempty" swaggertype:"integer" extensions:"x-nullable"`
 }
@@ -49,11 +56,19 @@ type TrendQueryResult struct {
 type StageTrend struct {
 	Results   map[string]*OneItemTrend `json:"results"`
 	StartTime int64                    `json:"startTime"`
+	// Buckets are the boundaries of the intervals of the item trends in unix milliseconds, i.e. the start of each
+	// interval followed by the end of the last one. Daily intervals start at the start of the days of the server.
+	Buckets []int64 `json:"buckets"`
 }
 
 type OneItemTrend struct {
 	Quantity []int `json:"quantity"`
 	Times    []int `json:"times"`
+	// Rates are the quantity / times of each of the intervals rounded to 6 decimal places, or 0 when times is 0
+	Rates []float64 `json:"rates"`
+	// Reporters are the numbers of distinct accounts having reported the stage in each of the intervals. They are
+	// left out of personal results.
+	Reporters []int `json:"reporters,omitempty"`
 }
 
 // Advanced Query
@@ -0,0 +1,41 @@
+package v3
+
+import (
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model"
+)
+
+const (
+	// AccountStandingGood means all contributions of the account are counted normally
+	AccountStandingGood = "GOOD"
+	// AccountStandingLimited means some recent contributions of the account have been rejected by the verifiers
+	AccountStandingLimited = "LIMITED"
+	// AccountStandingExcluded means all recent contributions of the account have been rejected by the verifiers
+	AccountStandingExcluded = "EXCLUDED"
+)
+
+type AccountStanding struct {
+	PenguinID string  `json:"penguinId"`
+	Standing  string  `json:"standing" enums:"GOOD,LIMITED,EXCLUDED"`
+	Weight    float64 `json:"weight"`
+	// Reports is the breakdown of the reports submitted by the account within the evaluation window
+	Reports AccountStandingReports `json:"reports"`
+	// Appeal is the latest appeal submitted by the account, if any
+	Appeal *model.AccountAppeal `json:"appeal,omitempty"`
+}
+
+type AccountStandingReports struct {
+	WindowDays int `json:"windowDays"`
+	Total      int `json:"total"`
+	Accepted   int `json:"accepted"`
+	Rejected   int `json:"rejected"`
+}
+
+// AccountLeaderboardSettings are the privacy settings of the account on the contributor leaderboards
+type AccountLeaderboardSettings struct {
+	// OptIn lists the account on the leaderboards. Accounts are never listed unless opted in.
+	OptIn bool `json:"optIn"`
+	// Name is the name the account is listed under, instead of an anonymous one
+	Name null.String `json:"name" swaggertype:"string"`
+}
@@ -0,0 +1,16 @@
+package v3
+
+import "exusiai.dev/backend-next/internal/pkg/pgerr"
+
+// AdvancedQueryResult holds the results of a batch of advanced queries, keyed by the indices of the queries
+type AdvancedQueryResult struct {
+	Results map[int]*OneAdvancedQueryResult `json:"results"`
+}
+
+// OneAdvancedQueryResult is either the result of a query or why it has failed
+type OneAdvancedQueryResult struct {
+	Type string `json:"type" example:"trend"`
+	// Result is a v2 drop matrix, trend or pattern matrix result by Type
+	Result any                 `json:"result,omitempty" swaggertype:"object"`
+	Error  *pgerr.PenguinError `json:"error,omitempty"`
+}
@@ -0,0 +1,18 @@
+package v3
+
+// APIUsage is the quota and the recent daily usage of an API key. Days are in UTC.
+type APIUsage struct {
+	APIKeyID          int    `json:"apiKeyId"`
+	Name              string `json:"name"`
+	Prefix            string `json:"prefix"`
+	DailyRequestQuota int    `json:"dailyRequestQuota"`
+	DailyByteQuota    int64  `json:"dailyByteQuota"`
+	// Days are the usage of the recent days, with today coming first
+	Days []*DailyAPIUsage `json:"days"`
+}
+
+type DailyAPIUsage struct {
+	Date     string `json:"date" example:"2026-10-17"`
+	Requests int64  `json:"requests"`
+	Bytes    int64  `json:"bytes"`
+}
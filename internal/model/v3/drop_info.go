@@ -0,0 +1,36 @@
+package v3
+
+import (
+	"exusiai.dev/backend-next/internal/model"
+)
+
+// StageDropInfos is the drop info of a stage in a server, grouped by time range
+type StageDropInfos struct {
+	StageID    string                       `json:"stageId"`
+	Server     string                       `json:"server"`
+	TimeRanges []*StageDropInfosInTimeRange `json:"timeRanges"`
+}
+
+type StageDropInfosInTimeRange struct {
+	TimeRange *model.TimeRange `json:"timeRange"`
+	// Active reports whether the time range is in effect at the time of the request
+	Active bool `json:"active"`
+	// DropSet is the item IDs that may drop from the stage within the time range
+	DropSet   []string            `json:"dropSet"`
+	DropTypes []*DropTypeDropInfo `json:"dropTypes"`
+	Items     []*ItemDropInfo     `json:"items"`
+}
+
+// DropTypeDropInfo bounds the count of kinds of items dropped of a drop type
+type DropTypeDropInfo struct {
+	DropType string        `json:"dropType"`
+	Bounds   *model.Bounds `json:"bounds"`
+}
+
+// ItemDropInfo bounds the quantity of an item dropped of a drop type
+type ItemDropInfo struct {
+	ItemID      string        `json:"itemId"`
+	DropType    string        `json:"dropType"`
+	Accumulable bool          `json:"accumulable"`
+	Bounds      *model.Bounds `json:"bounds"`
+}
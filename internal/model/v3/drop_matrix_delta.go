@@ -0,0 +1,23 @@
+package v3
+
+import modelv2 "exusiai.dev/backend-next/internal/model/v2"
+
+// DropMatrixDelta contains the elements of the drop matrix that have changed since the given time.
+// Unless the delta is full, elements that are not present have not changed.
+type DropMatrixDelta struct {
+	Server string `json:"server" example:"CN"`
+	// Since is the time of the snapshot of the matrix the delta has been computed against, in milliseconds.
+	// It is zero when the delta is full.
+	Since int64 `json:"since" example:"1697500800000"`
+	// Until is the time of the latest snapshot of the matrix, in milliseconds. Pass it as `since` on the next request.
+	Until int64 `json:"until" example:"1697501400000"`
+	// Full tells whether the delta contains the whole matrix, as no snapshot old enough is available
+	Full    bool                            `json:"full"`
+	Matrix  []*modelv2.OneDropMatrixElement `json:"matrix"`
+	Removed []*DropMatrixElementKey         `json:"removed"`
+}
+
+type DropMatrixElementKey struct {
+	StageID string `json:"stageId" example:"main_01-07"`
+	ItemID  string `json:"itemId" example:"30012"`
+}
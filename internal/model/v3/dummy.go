@@ -0,0 +1,5 @@
+package v3
+
+// Dummy is for swagger to find the correct package by using an
+// import alias in the used file. See modelv2.Dummy for details.
+type Dummy struct{}
@@ -0,0 +1,25 @@
+package v3
+
+import (
+	"time"
+
+	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+)
+
+// EventSummary is the final statistics of the stages of a past event in a server, frozen upon the close of the event
+// so that it remains queryable without keeping the reports of the event
+type EventSummary struct {
+	EventID   int       `json:"eventId"`
+	Name      string    `json:"name"`
+	Server    string    `json:"server" example:"CN"`
+	OpenTime  time.Time `json:"openTime"`
+	CloseTime time.Time `json:"closeTime"`
+	// TotalReports is the number of the reports accepted for the stages of the event while it was open
+	TotalReports int `json:"totalReports"`
+	// Matrix is the drop matrix of the stages of the event while it was open
+	Matrix []*modelv2.OneDropMatrixElement `json:"matrix"`
+	// TopPatterns are the most reported drop patterns of each stage of the event while it was open
+	TopPatterns []*modelv2.OnePatternMatrixElement `json:"topPatterns"`
+	// GeneratedAt is when the summary was frozen
+	GeneratedAt time.Time `json:"generatedAt"`
+}
@@ -0,0 +1,22 @@
+package v3
+
+import "time"
+
+// Freshness tells when the aggregations of every server have last been refreshed, for the consumers to detect stalled
+// pipelines
+type Freshness struct {
+	Realms      []*RealmFreshness `json:"realms"`
+	GeneratedAt time.Time         `json:"generatedAt"`
+}
+
+type RealmFreshness struct {
+	Server string `json:"server" example:"CN"`
+	// Realm is one of matrix, trend and pattern
+	Realm string `json:"realm" example:"matrix"`
+	// LastRefreshedAt is when the aggregation has last been refreshed, null when it has not been since the freshness
+	// has started being recorded
+	LastRefreshedAt *time.Time `json:"lastRefreshedAt"`
+	// LastAcceptedReportAt is when the last report accepted in the server has been submitted, null when none has been
+	// since the freshness has started being recorded
+	LastAcceptedReportAt *time.Time `json:"lastAcceptedReportAt"`
+}
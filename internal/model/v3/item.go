@@ -1,6 +1,8 @@
 package v3
 
 import (
+	"time"
+
 	"github.com/goccy/go-json"
 	"gopkg.in/guregu/null.v3"
 )
@@ -17,3 +19,27 @@ type Item struct {
 	Sprite    null.String     `json:"sprite,omitempty" swaggertype:"string"`
 	Keywords  json.RawMessage `json:"keywords,omitempty" swaggertype:"object"`
 }
+
+// ItemValueTable is the sanity value of the items in a server as given by a preset
+type ItemValueTable struct {
+	Preset string `json:"preset" example:"market"`
+	Server string `json:"server" example:"CN"`
+	// Values maps the ark item IDs to their sanity values
+	Values map[string]float64 `json:"values"`
+	// UpdatedAt is when the values of the preset were last replaced
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ItemIcons is where the icons of the items are, on the sprite sheet or standalone
+type ItemIcons struct {
+	// Version is a hash of the icons, which changes whenever any of them does so that clients may cache-bust with it
+	Version string      `json:"version" example:"5f2b8c1d9e3a7b46"`
+	Icons   []*ItemIcon `json:"icons"`
+}
+
+type ItemIcon struct {
+	ArkItemID string `json:"arkItemId" example:"30013"`
+	// Sprite is the location of the item's sprite on the sprite image, in a form of Y:X
+	Sprite  null.String `json:"sprite,omitempty" swaggertype:"string" example:"3:5"`
+	IconURL null.String `json:"iconUrl,omitempty" swaggertype:"string"`
+}
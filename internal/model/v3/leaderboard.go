@@ -0,0 +1,37 @@
+package v3
+
+import (
+	"time"
+
+	"gopkg.in/guregu/null.v3"
+)
+
+const (
+	// LeaderboardSortByReports ranks the contributors by the number of their reports
+	LeaderboardSortByReports = "reports"
+	// LeaderboardSortBySanity ranks the contributors by the sanity estimated to be spent on their reports
+	LeaderboardSortBySanity = "sanity"
+)
+
+var LeaderboardSortBys = []string{LeaderboardSortByReports, LeaderboardSortBySanity}
+
+// EventLeaderboard ranks the contributors opted in to the leaderboards by their accepted reports for the stages of an
+// event in a server
+type EventLeaderboard struct {
+	EventID int    `json:"eventId"`
+	Server  string `json:"server"`
+	SortBy  string `json:"sortBy" enums:"reports,sanity"`
+	// GeneratedAt is when the leaderboard was calculated; it is cached for a few minutes
+	GeneratedAt time.Time           `json:"generatedAt"`
+	Entries     []*LeaderboardEntry `json:"entries"`
+}
+
+type LeaderboardEntry struct {
+	Rank int `json:"rank"`
+	// Name is the name chosen by the contributor, or null for the anonymous ones
+	Name    null.String `json:"name" swaggertype:"string"`
+	Reports int         `json:"reports"`
+	Times   int         `json:"times"`
+	// Sanity is estimated from the sanity cost of the stages times the times they are reported to be cleared
+	Sanity int `json:"sanity"`
+}
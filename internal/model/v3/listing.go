@@ -0,0 +1,43 @@
+package v3
+
+import (
+	"time"
+
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model"
+)
+
+// ZoneListing is a zone as listed for a specific server
+type ZoneListing struct {
+	*model.Zone
+	// Open reports whether the zone is open in the server at the time of the request
+	Open bool `json:"open"`
+	// OpenTime and CloseTime are the boundaries of the zone in the server, if any
+	OpenTime  *time.Time `json:"openTime,omitempty"`
+	CloseTime *time.Time `json:"closeTime,omitempty"`
+}
+
+// StageListing is a stage as listed for a specific server
+type StageListing struct {
+	*model.Stage
+	// Open reports whether the stage is open in the server at the time of the request
+	Open bool `json:"open"`
+	// TimeRange is the time range of the drop infos of the stage currently in effect in the server, if any
+	TimeRange *model.TimeRange `json:"timeRange,omitempty"`
+}
+
+// EventListing is an event as scheduled in a specific server
+type EventListing struct {
+	EventID int    `json:"id"`
+	Name    string `json:"name"`
+	// Zones are the ark zone ids of the zones of the event
+	Zones  []string `json:"zones"`
+	Server string   `json:"server"`
+	// Status is one of UPCOMING, ACTIVE and PAST at the time of the request
+	Status    string     `json:"status" example:"ACTIVE"`
+	OpenTime  time.Time  `json:"openTime"`
+	CloseTime *time.Time `json:"closeTime,omitempty"`
+	// RangeID is the time range derived from the schedule of the event in the server
+	RangeID null.Int `json:"rangeId" swaggertype:"integer"`
+}
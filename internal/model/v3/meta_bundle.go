@@ -0,0 +1,21 @@
+package v3
+
+// MetaBundle is a version of the items, stages and zones. Unless the bundle is full, it only contains the items,
+// stages and zones that have been added or changed since the version the client has, along with the ark IDs of
+// the removed ones.
+type MetaBundle struct {
+	// Version increases monotonically whenever any item, stage or zone changes
+	Version int64 `json:"version" example:"42"`
+	// Full tells whether the bundle contains everything, rather than the changes since the version of the client
+	Full    bool               `json:"full"`
+	Items   []*Item            `json:"items"`
+	Stages  []*Stage           `json:"stages"`
+	Zones   []*Zone            `json:"zones"`
+	Removed *MetaBundleRemoved `json:"removed,omitempty"`
+}
+
+type MetaBundleRemoved struct {
+	Items  []string `json:"items"`
+	Stages []string `json:"stages"`
+	Zones  []string `json:"zones"`
+}
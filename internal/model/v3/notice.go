@@ -0,0 +1,20 @@
+package v3
+
+import (
+	"time"
+
+	"github.com/goccy/go-json"
+	"gopkg.in/guregu/null.v3"
+)
+
+// Notice is an announcement shown within its display window, such as a maintenance or a data issue
+type Notice struct {
+	ID       int      `json:"id"`
+	Kind     string   `json:"kind" example:"maintenance"`
+	Severity null.Int `json:"severity" swaggertype:"integer"`
+	// Content maps the languages to the content of the notice in them
+	Content   json.RawMessage `json:"content" swaggertype:"object"`
+	StartTime *time.Time      `json:"startTime"`
+	EndTime   *time.Time      `json:"endTime"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
@@ -0,0 +1,27 @@
+package v3
+
+// Plan is the cheapest runs of the open stages, in sanity, expected to drop the items requested
+type Plan struct {
+	Server string `json:"server"`
+	// Sanity is the sanity spent on the runs of the stages
+	Sanity int             `json:"sanity"`
+	Stages []*PlannedStage `json:"stages"`
+	Items  []*PlannedItem  `json:"items"`
+	// Unreachable are the items requested that no open stage has been reported enough to drop
+	Unreachable []string `json:"unreachable"`
+}
+
+type PlannedStage struct {
+	StageID string `json:"stageId"`
+	// Runs is the number of times the stage is to be cleared, rounded up from the solution
+	Runs   int `json:"runs"`
+	Sanity int `json:"sanity"`
+}
+
+type PlannedItem struct {
+	ItemID string `json:"itemId"`
+	// Quantity is the quantity requested
+	Quantity int `json:"quantity"`
+	// Expected is the quantity expected to drop from the planned runs, byproducts of other items included
+	Expected float64 `json:"expected"`
+}
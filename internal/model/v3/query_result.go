@@ -8,10 +8,12 @@ type PatternMatrixQueryResult struct {
 }
 
 type OnePatternMatrixElement struct {
-	StageID   string   `json:"stageId" example:"main_01-07"`
-	Pattern   *Pattern `json:"pattern"`
-	Times     int      `json:"times" example:"641734"`
-	Quantity  int      `json:"quantity" example:"159486"`
+	StageID  string   `json:"stageId" example:"main_01-07"`
+	Pattern  *Pattern `json:"pattern"`
+	Times    int      `json:"times" example:"641734"`
+	Quantity int      `json:"quantity" example:"159486"`
+	// Rate is quantity / times rounded to 6 decimal places, or 0 when times is 0
+	Rate      float64  `json:"rate" example:"0.248523"`
 	StartTime int64    `json:"start" example:"1633032000000"`
 	EndTime   null.Int `json:"end,omitempty" swaggertype:"integer" extensions:"x-nullable"`
 }
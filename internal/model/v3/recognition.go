@@ -0,0 +1,14 @@
+package v3
+
+// RecognitionStageHashes are the perceptual hashes of the result screens of the stages in a server
+type RecognitionStageHashes struct {
+	Server string `json:"server" example:"CN"`
+	// Version is a hash of the registry, which changes whenever any of the hashes does
+	Version string                  `json:"version" example:"5f2b8c1d9e3a7b46"`
+	Hashes  []*RecognitionStageHash `json:"hashes"`
+}
+
+type RecognitionStageHash struct {
+	Hash    string `json:"hash" example:"f0e1d2c3b4a59687"`
+	StageID string `json:"stageId" example:"main_01-07"`
+}
@@ -0,0 +1,57 @@
+package v3
+
+import "time"
+
+// Codes of the issues that reject a report
+const (
+	ReportIssueStageNotFound  = "STAGE_NOT_FOUND"
+	ReportIssueStageNotOpen   = "STAGE_NOT_OPEN"
+	ReportIssueItemNotFound   = "ITEM_NOT_FOUND"
+	ReportIssueDuplicateDrop  = "DUPLICATE_DROP"
+	ReportIssueUnexpectedDrop = "UNEXPECTED_DROP"
+)
+
+// Codes of the issues that are reported back as warnings, without rejecting the report
+const (
+	// ReportIssueQuantityOutOfBounds means the quantity of an item is out of the bounds known for the stage.
+	// The report is accepted, but will be excluded from the statistics once verified.
+	ReportIssueQuantityOutOfBounds = "QUANTITY_OUT_OF_BOUNDS"
+	// ReportIssueItemRarelyDrops means the item has rarely been reported to drop from the stage
+	ReportIssueItemRarelyDrops = "ITEM_RARELY_DROPS"
+)
+
+// ReportIssue describes what is wrong or unusual about a report
+type ReportIssue struct {
+	Code string `json:"code" example:"ITEM_RARELY_DROPS"`
+	// Field is the path to the offending field of the request, if any
+	Field   string `json:"field,omitempty" example:"drops[0].itemId"`
+	Message string `json:"message" example:"item 30013 has rarely been reported to drop from main_01-07"`
+}
+
+type ReportResponse struct {
+	ReportHash string `json:"reportHash" example:"0522ce0083000000-1wE2I9dvMFXXzBMpSCYM81rJ0T3tLrAQ"`
+	// Warnings are the issues found in the report that did not cause it to be rejected
+	Warnings []*ReportIssue `json:"warnings"`
+}
+
+// ReportRejection is the error responded when a report is rejected
+type ReportRejection struct {
+	Code    string         `json:"code" example:"INVALID_REPORT"`
+	Message string         `json:"message" example:"report has been rejected"`
+	Issues  []*ReportIssue `json:"issues"`
+}
+
+// RecognitionDefectReceipt is responded when a recognition defect has been stored
+type RecognitionDefectReceipt struct {
+	DefectID string `json:"defectId" example:"01hcz4g2q4d4b9s7m6yv3e8k5x"`
+}
+
+// SubmissionChallenge is a proof of work to solve before submitting a report: a counter is to be found such that the
+// SHA-256 digest of `<nonce>:<counter>` starts with at least Difficulty zero bits. The solution is presented as
+// `<nonce>:<counter>` in the X-Penguin-Challenge-Solution header of the submission, and can only be redeemed once.
+type SubmissionChallenge struct {
+	Nonce      string    `json:"nonce" example:"9b1c3f0d7e2a4c6b8d0f1e3a5c7b9d2e"`
+	Algorithm  string    `json:"algorithm" example:"sha256"`
+	Difficulty int       `json:"difficulty" example:"16"`
+	ExpiresAt  time.Time `json:"expiresAt" example:"2026-10-17T08:05:00Z"`
+}
@@ -0,0 +1,16 @@
+package v3
+
+import (
+	"time"
+
+	"exusiai.dev/backend-next/internal/model/types"
+)
+
+// ShortLink is an advanced query shared under a short code, which resolves until it expires
+type ShortLink struct {
+	Code string `json:"code" example:"x7Kq2LmP"`
+	// Query is the normalized advanced query, to be sent as is to the advanced query API
+	Query     *types.AdvancedQueryRequest `json:"query"`
+	CreatedAt time.Time                   `json:"createdAt"`
+	ExpiresAt time.Time                   `json:"expiresAt"`
+}
@@ -0,0 +1,38 @@
+package v3
+
+// Simulation is the expected outcome of clearing a stage a number of times, from the rates of its global drop
+// matrix and pattern matrix in their time ranges currently in effect
+type Simulation struct {
+	StageID string `json:"stageId"`
+	Server  string `json:"server"`
+	Runs    int    `json:"runs"`
+	// SampleTimes is the number of times the stage has been reported to be cleared, which the rates are based on
+	SampleTimes int               `json:"sampleTimes"`
+	Items       []*SimulatedItem  `json:"items"`
+	Patterns    []*SimulatedDrops `json:"patterns"`
+}
+
+type SimulatedItem struct {
+	ItemID string `json:"itemId"`
+	// Expected is the expected total quantity of the item dropped over the runs
+	Expected float64 `json:"expected"`
+	// Variance is the variance of the total quantity over the runs, assuming the runs are independent
+	Variance float64 `json:"variance"`
+	StdDev   float64 `json:"stdDev"`
+	// AtLeastOnce is the probability of the item dropping at least once over the runs, which is only known for the
+	// items of the drop patterns
+	AtLeastOnce *float64 `json:"atLeastOnce,omitempty"`
+}
+
+type SimulatedDrops struct {
+	Drops []*SimulatedDrop `json:"drops"`
+	// Probability is the probability of the pattern in a single run
+	Probability float64 `json:"probability"`
+	// Expected is the expected number of runs of the pattern over the runs
+	Expected float64 `json:"expected"`
+}
+
+type SimulatedDrop struct {
+	ItemID   string `json:"itemId"`
+	Quantity int    `json:"quantity"`
+}
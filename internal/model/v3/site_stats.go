@@ -0,0 +1,31 @@
+package v3
+
+import "time"
+
+// SiteStats is the headline numbers of the site, maintained by incremental counters
+type SiteStats struct {
+	TotalReports    int64 `json:"totalReports"`
+	Reports24H      int64 `json:"reports24h"`
+	UniqueReporters int64 `json:"uniqueReporters"`
+	TotalAccounts   int64 `json:"totalAccounts"`
+	// Servers is the per-server breakdown, keyed by server code
+	Servers map[string]*ServerSiteStats `json:"servers"`
+}
+
+type ServerSiteStats struct {
+	TotalReports    int64 `json:"totalReports"`
+	Reports24H      int64 `json:"reports24h"`
+	UniqueReporters int64 `json:"uniqueReporters"`
+}
+
+// ReportHeatmap is the number of reports accepted in a server within a window, by the weekday and the hour they were
+// submitted at in the time zone of the server, maintained by incremental counters
+type ReportHeatmap struct {
+	Server   string `json:"server"`
+	Timezone string `json:"timezone" example:"Asia/Shanghai"`
+	Days     int    `json:"days"`
+	// Matrix is indexed by the weekday, starting from Sunday, then by the hour
+	Matrix      [][]int64 `json:"matrix"`
+	Total       int64     `json:"total"`
+	GeneratedAt time.Time `json:"generatedAt"`
+}
@@ -1,9 +1,12 @@
 package v3
 
 import (
-	"github.com/goccy/go-json"
+	"time"
 
+	"github.com/goccy/go-json"
 	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model"
 )
 
 type Stage struct {
@@ -12,8 +15,28 @@ type Stage struct {
 	ZoneID           int             `json:"zoneId"`
 	StageType        string          `json:"stageType"`
 	ExtraProcessType null.String     `json:"extraProcessType,omitempty" swaggertype:"string"`
-	Code             json.RawMessage `json:"code"`
+	Code             json.RawMessage `json:"code" swaggertype:"object"`
 	Sanity           null.Int        `json:"sanity" swaggertype:"integer,x-nullable"`
 	Existence        json.RawMessage `json:"existence" swaggertype:"object"`
 	MinClearTime     null.Int        `json:"minClearTime" swaggertype:"integer,x-nullable"`
 }
+
+// SampleForecast estimates when the sample of a stage in its time range currently in effect reaches a number of
+// times, by extrapolating the rate the stage has been reported at over a rolling window
+type SampleForecast struct {
+	StageID   string           `json:"stageId"`
+	Server    string           `json:"server"`
+	TimeRange *model.TimeRange `json:"timeRange"`
+	// Times is the number of times the stage has been reported to be cleared within the time range so far
+	Times  int `json:"times"`
+	Target int `json:"target"`
+	// RatePerHour is the number of times reported per hour over the window
+	RatePerHour float64 `json:"ratePerHour"`
+	WindowHours float64 `json:"windowHours"`
+	// Reached is whether the sample has reached the target already
+	Reached bool `json:"reached"`
+	// EstimatedAt is when the sample is estimated to reach the target, or null when it has been reached already, no
+	// reports have come in over the window, or the time range is estimated to end before then
+	EstimatedAt *time.Time `json:"estimatedAt"`
+	GeneratedAt time.Time  `json:"generatedAt"`
+}
@@ -0,0 +1,19 @@
+package v3
+
+import "gopkg.in/guregu/null.v3"
+
+// StageDropStats contains the number of items dropped per run of each stage in the global pattern matrix
+type StageDropStats struct {
+	Stages []*OneStageDropStats `json:"stages"`
+}
+
+type OneStageDropStats struct {
+	StageID string `json:"stageId" example:"main_01-07"`
+	Times   int    `json:"times" example:"641734"`
+	// Avg is the average number of items dropped per run, counting every item of the drop patterns
+	Avg float64 `json:"avg" example:"1.812504"`
+	// StdDev is the standard deviation of the number of items dropped per run
+	StdDev    float64  `json:"stdDev" example:"0.543217"`
+	StartTime int64    `json:"start" example:"1633032000000"`
+	EndTime   null.Int `json:"end,omitempty" swaggertype:"integer" extensions:"x-nullable"`
+}
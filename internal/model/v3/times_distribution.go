@@ -0,0 +1,27 @@
+package v3
+
+import "time"
+
+// TimesDistribution tells how the reliable reports of a stage are distributed among the accounts having reported it,
+// as a hint of whether its sample is dominated by a few accounts
+type TimesDistribution struct {
+	StageID string `json:"stageId" example:"main_01-07"`
+	Server  string `json:"server" example:"CN"`
+	// Accounts is the number of distinct accounts having reported the stage
+	Accounts int `json:"accounts" example:"12345"`
+	Reports  int `json:"reports" example:"456789"`
+	// TopAccountShare is the share of the reports of the account having reported the stage the most
+	TopAccountShare float64 `json:"topAccountShare" example:"0.012345"`
+	// Histogram buckets the accounts by their number of reports, in powers of two, i.e. 1, 2-3, 4-7 and so on.
+	// Empty buckets are left out.
+	Histogram   []*TimesDistributionBucket `json:"histogram"`
+	GeneratedAt time.Time                  `json:"generatedAt"`
+}
+
+type TimesDistributionBucket struct {
+	// Min and Max are the bounds of the number of reports per account of the bucket, both inclusive
+	Min      int `json:"min" example:"4"`
+	Max      int `json:"max" example:"7"`
+	Accounts int `json:"accounts" example:"1234"`
+	Reports  int `json:"reports" example:"5678"`
+}
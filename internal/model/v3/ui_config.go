@@ -0,0 +1,35 @@
+package v3
+
+import (
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// UIConfig is what the frontend shows in a server, generated from the metadata so that changes to it need no
+// deployment of the frontend
+type UIConfig struct {
+	Server string `json:"server" example:"CN"`
+	// Version is a hash of the config, which changes whenever anything in it does
+	Version     string    `json:"version" example:"5f2b8c1d9e3a7b46"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Open is false while a maintenance notice is active in the server
+	Open bool `json:"open"`
+	// Zones are the zones existing in the server, along with the stages of them to show
+	Zones []*UIConfigZone `json:"zones"`
+	// HighlightedEvents are the events active or upcoming in the server
+	HighlightedEvents []*EventListing `json:"highlightedEvents"`
+	// Config is the frontend config property, passed through as is
+	Config json.RawMessage `json:"config" swaggertype:"object"`
+}
+
+type UIConfigZone struct {
+	ZoneID   string `json:"zoneId" example:"main_1"`
+	Category string `json:"category" example:"MAINLINE"`
+	// Open reports whether the zone is open in the server at the time the config is generated
+	Open      bool       `json:"open"`
+	OpenTime  *time.Time `json:"openTime,omitempty"`
+	CloseTime *time.Time `json:"closeTime,omitempty"`
+	// Stages are the ark stage ids of the stages of the zone existing in the server
+	Stages []string `json:"stages"`
+}
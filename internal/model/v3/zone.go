@@ -12,7 +12,7 @@ type Zone struct {
 	Index      int             `json:"index"`
 	Category   string          `json:"category" example:"MAINLINE"`
 	Type       null.String     `json:"type,omitempty" swaggertype:"string" example:"AWAKENING_HOUR"`
-	Name       json.RawMessage `json:"name"`
+	Name       json.RawMessage `json:"name" swaggertype:"object"`
 	Existence  json.RawMessage `json:"existence" swaggertype:"object"`
 	Background null.String     `json:"background,omitempty" swaggertype:"string"`
 }
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/uptrace/bun"
+)
+
+const (
+	WebhookEventTimeRangeOpened = "timerange.opened"
+	WebhookEventMatrixRefreshed = "matrix.refreshed"
+	WebhookEventStageClosed     = "stage.closed"
+	WebhookEventAnomalyDetected = "anomaly.detected"
+	// WebhookEventPing is only delivered to the webhook it is requested for, to test the endpoint
+	WebhookEventPing = "ping"
+)
+
+var WebhookEvents = []string{
+	WebhookEventTimeRangeOpened,
+	WebhookEventMatrixRefreshed,
+	WebhookEventStageClosed,
+	WebhookEventAnomalyDetected,
+}
+
+type Webhook struct {
+	bun.BaseModel `bun:"webhooks,alias:wh"`
+
+	WebhookID int `bun:",pk,autoincrement" json:"id"`
+	// APIKeyID is the API key the webhook is subscribed by
+	APIKeyID int    `bun:"api_key_id,notnull" json:"-"`
+	URL      string `bun:",notnull" json:"url"`
+	// Events are the event types the webhook is subscribed to
+	Events []string `bun:",array,notnull" json:"events"`
+	// Secret is used to sign the payloads delivered to the webhook. It is only revealed upon subscription.
+	Secret    string    `bun:",notnull" json:"secret,omitempty"`
+	CreatedAt time.Time `bun:",notnull,default:current_timestamp" json:"createdAt"`
+}
+
+// WebhookEvent is the payload delivered to the webhooks subscribed to its type
+type WebhookEvent struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// WebhookDelivery is a pending delivery of an event to a single webhook
+type WebhookDelivery struct {
+	WebhookID int           `json:"webhookId"`
+	Event     *WebhookEvent `json:"event"`
+}
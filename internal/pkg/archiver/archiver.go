@@ -1,6 +1,7 @@
 package archiver
 
 import (
+	"bufio"
 	"compress/gzip"
 	"context"
 	"encoding/json"
@@ -24,9 +25,14 @@ const (
 	FileExtParquet         = ".parquet"
 	LocalTempDirPattern    = "penguin_stats-archiver-*"
 	ArchiverChanBufferSize = 1000
+	// ReadMaxLineSize is the maximum size of a single record read back from the archives
+	ReadMaxLineSize = 1024 * 1024
 )
 
-var ErrFileAlreadyExists = errors.New("file already exists")
+var (
+	ErrFileAlreadyExists = errors.New("file already exists")
+	ErrFileNotFound      = errors.New("file not found")
+)
 
 type Archiver struct {
 	S3Client *s3.Client
@@ -55,11 +61,64 @@ func (a *Archiver) initLogger() {
 }
 
 func (a *Archiver) canonicalFilePath(fileExt string) string {
+	return a.canonicalFilePathOf(a.date, fileExt)
+}
+
+func (a *Archiver) canonicalFilePathOf(date time.Time, fileExt string) string {
 	loc := constant.LocMap["CN"] // we use CN server's day start time as the day start time for all servers for archive
-	localT := a.date.In(loc)
+	localT := date.In(loc)
 	return a.RealmName + "/" + a.RealmName + "_" + localT.Format("2006-01-02") + fileExt
 }
 
+// Exists tells whether the archive of the date has been uploaded to S3. Unlike Prepare, it does not change the
+// date the archiver is working on.
+func (a *Archiver) Exists(ctx context.Context, date time.Time) (bool, error) {
+	_, err := a.S3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(a.S3Bucket),
+		Key:    aws.String(a.S3Prefix + a.canonicalFilePathOf(date, FileExtJsonlGzip)),
+	})
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) && ae.ErrorCode() == "NotFound" {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to invoke HeadObject")
+	}
+	return true, nil
+}
+
+// Read streams the records of the archive of the date, one JSON line at a time. The line passed to fn is only valid
+// until fn returns. ErrFileNotFound is returned when the date has not been archived.
+func (a *Archiver) Read(ctx context.Context, date time.Time, fn func(line []byte) error) error {
+	object, err := a.S3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(a.S3Bucket),
+		Key:    aws.String(a.S3Prefix + a.canonicalFilePathOf(date, FileExtJsonlGzip)),
+	})
+	if err != nil {
+		var nsk *types.NoSuchKey
+		if errors.As(err, &nsk) {
+			return ErrFileNotFound
+		}
+		return errors.Wrap(err, "failed to invoke GetObject")
+	}
+	defer object.Body.Close()
+
+	gz, err := gzip.NewReader(object.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to open gzip stream")
+	}
+	defer gz.Close()
+
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 0, 64*1024), ReadMaxLineSize)
+	for scanner.Scan() {
+		if err := fn(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(scanner.Err(), "failed to read archive")
+}
+
 func (a *Archiver) Prepare(ctx context.Context, date time.Time) error {
 	a.initLogger()
 
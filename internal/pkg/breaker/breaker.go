@@ -0,0 +1,121 @@
+// Package breaker opens a circuit around the database once its latency spikes, so that the requests served during an
+// incident are given up on early rather than piling up behind the queries that are already slow to complete.
+package breaker
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/pkg/observability"
+	"exusiai.dev/backend-next/internal/pkg/pgpool"
+)
+
+type Config struct {
+	// SlowThreshold is the latency beyond which a query counts as slow. Zero disables the breaker.
+	SlowThreshold time.Duration
+	// SlowRatio is the share of the slow queries within a window beyond which the circuit opens
+	SlowRatio float64
+	// MinQueries is the number of the queries a window shall have seen for its share of the slow ones to count
+	MinQueries int
+	// Window is the length of the windows the queries are counted within
+	Window time.Duration
+	// Cooldown is how long the circuit stays open, after which it closes again with a fresh window. The circuit
+	// opens again right away if the database is still slow then.
+	Cooldown time.Duration
+}
+
+// Breaker is the circuit around the database, fed with the latency of the queries by its QueryHook
+type Breaker struct {
+	config Config
+	now    func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	total       int
+	slow        int
+	openUntil   time.Time
+}
+
+func New(config Config) *Breaker {
+	return &Breaker{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Observe counts a query having completed with err after latency. The queries exceeding their deadline count as slow
+// whatever their latency is, while those cancelled, e.g. by the client going away, are not counted.
+func (b *Breaker) Observe(latency time.Duration, err error) {
+	if b.config.SlowThreshold <= 0 || errors.Is(err, context.Canceled) {
+		return
+	}
+	slow := latency >= b.config.SlowThreshold || errors.Is(err, context.DeadlineExceeded)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if now.Sub(b.windowStart) >= b.config.Window {
+		b.windowStart, b.total, b.slow = now, 0, 0
+	}
+	b.total++
+	if slow {
+		b.slow++
+	}
+
+	if now.Before(b.openUntil) || b.total < b.config.MinQueries {
+		return
+	}
+	if float64(b.slow) >= float64(b.total)*b.config.SlowRatio {
+		b.openUntil = now.Add(b.config.Cooldown)
+		// the next window starts once the circuit closes, not to count the queries of the incident twice
+		b.windowStart, b.total, b.slow = b.openUntil, 0, 0
+		observability.DBCircuitOpens.Inc()
+	}
+}
+
+// Open tells whether the circuit is open, along with the time it closes at
+func (b *Breaker) Open() (bool, time.Time) {
+	if b == nil {
+		return false, time.Time{}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	open := b.now().Before(b.openUntil)
+	if open {
+		observability.DBCircuitOpen.Set(1)
+	} else {
+		observability.DBCircuitOpen.Set(0)
+	}
+	return open, b.openUntil
+}
+
+// QueryHook feeds the latency of the queries executed with bun to the Breaker. The queries of the background jobs are
+// left out: they are expected to run long, and are kept off the pool of the requests when configured so.
+type QueryHook struct {
+	Breaker *Breaker
+}
+
+var _ bun.QueryHook = (*QueryHook)(nil)
+
+func (h *QueryHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *QueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	if pgpool.IsBackground(ctx) {
+		return
+	}
+	err := event.Err
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	h.Breaker.Observe(time.Since(event.StartTime), err)
+}
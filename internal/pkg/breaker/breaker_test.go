@@ -0,0 +1,77 @@
+package breaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	b := New(Config{
+		SlowThreshold: time.Second,
+		SlowRatio:     0.5,
+		MinQueries:    4,
+		Window:        time.Second * 10,
+		Cooldown:      time.Second * 30,
+	})
+	b.now = func() time.Time { return now }
+
+	// too few queries have been seen for the slow ones to count
+	b.Observe(time.Second*2, nil)
+	b.Observe(time.Second*2, nil)
+	b.Observe(time.Millisecond, nil)
+	if open, _ := b.Open(); open {
+		t.Fatal("circuit opened before MinQueries")
+	}
+
+	// the cancelled queries are not counted, while the ones exceeding their deadline count as slow
+	b.Observe(time.Millisecond, context.Canceled)
+	if open, _ := b.Open(); open {
+		t.Fatal("circuit opened by a cancelled query")
+	}
+	b.Observe(time.Millisecond, errors.Wrap(context.DeadlineExceeded, "query"))
+	open, closesAt := b.Open()
+	if !open {
+		t.Fatal("circuit not opened by 3 slow queries out of 4")
+	}
+	if want := now.Add(time.Second * 30); !closesAt.Equal(want) {
+		t.Errorf("closesAt = %v, want %v", closesAt, want)
+	}
+
+	// the circuit closes after the cooldown with a fresh window
+	now = now.Add(time.Second * 30)
+	if open, _ := b.Open(); open {
+		t.Fatal("circuit still open after the cooldown")
+	}
+	for i := 0; i < 4; i++ {
+		b.Observe(time.Millisecond, nil)
+	}
+	if open, _ := b.Open(); open {
+		t.Fatal("circuit opened by fast queries")
+	}
+
+	// the windows start over once they have passed
+	now = now.Add(time.Second * 10)
+	for i := 0; i < 3; i++ {
+		b.Observe(time.Second*2, nil)
+	}
+	if open, _ := b.Open(); open {
+		t.Fatal("circuit opened before MinQueries within the window")
+	}
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	b := New(Config{MinQueries: 1, SlowRatio: 0.5, Window: time.Second, Cooldown: time.Second})
+	b.Observe(time.Hour, context.DeadlineExceeded)
+	if open, _ := b.Open(); open {
+		t.Fatal("circuit opened while disabled")
+	}
+
+	var nilBreaker *Breaker
+	if open, _ := nilBreaker.Open(); open {
+		t.Fatal("nil circuit open")
+	}
+}
@@ -1,5 +1,18 @@
 package cache
 
-import "github.com/pkg/errors"
+import (
+	"github.com/pkg/errors"
+
+	"exusiai.dev/backend-next/internal/pkg/observability"
+)
 
 var ErrNotFound = errors.New("cache entry not found")
+
+// observeGet counts a lookup of the named cache as a hit or a miss
+func observeGet(name string, err error) {
+	result := observability.CacheResultHit
+	if err != nil {
+		result = observability.CacheResultMiss
+	}
+	observability.CacheRequests.WithLabelValues(name, result).Inc()
+}
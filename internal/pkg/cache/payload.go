@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"github.com/goccy/go-json"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// The payloads held in redis for the replicas to share are tagged by a header of payloadMagic followed by the codec
+// of the rest of the payload. payloadMagic never starts a JSON document nor a gzip stream, so that the payloads
+// written before the header was introduced, which are left untagged, read as they are.
+const (
+	payloadMagic = 0xfe
+
+	payloadCodecRaw  = 0x00
+	payloadCodecZstd = 0x01
+
+	// payloadCompressionThreshold is the length from which the payloads are compressed, below which the header and
+	// the frame of zstd outweigh what is saved
+	payloadCompressionThreshold = 4 << 10
+)
+
+var (
+	payloadEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	payloadDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+)
+
+// EncodePayload compresses the payload with zstd when it is at least payloadCompressionThreshold long. The shorter
+// payloads are left as they are, unless they start like a tagged payload would.
+func EncodePayload(b []byte) []byte {
+	switch {
+	case len(b) >= payloadCompressionThreshold:
+		return payloadEncoder.EncodeAll(b, []byte{payloadMagic, payloadCodecZstd})
+	case len(b) > 0 && b[0] == payloadMagic:
+		return append([]byte{payloadMagic, payloadCodecRaw}, b...)
+	default:
+		return b
+	}
+}
+
+// DecodePayload returns the payload encoded by EncodePayload as it was. Untagged payloads are returned as they are.
+func DecodePayload(b []byte) ([]byte, error) {
+	if len(b) == 0 || b[0] != payloadMagic {
+		return b, nil
+	}
+	if len(b) < 2 {
+		return nil, errors.New("truncated cache payload header")
+	}
+	switch b[1] {
+	case payloadCodecRaw:
+		return b[2:], nil
+	case payloadCodecZstd:
+		return payloadDecoder.DecodeAll(b[2:], nil)
+	default:
+		return nil, errors.Errorf("unknown cache payload codec %#x", b[1])
+	}
+}
+
+// MarshalPayload marshals v into JSON and encodes it with EncodePayload
+func MarshalPayload(v any) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return EncodePayload(b), nil
+}
+
+// UnmarshalPayload decodes the payload with DecodePayload and unmarshals it from JSON into v
+func UnmarshalPayload(b []byte, v any) error {
+	b, err := DecodePayload(b)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPayloadRoundTrip(t *testing.T) {
+	large := []byte(`{"matrix":[` + strings.Repeat(`{"stageId":"main_01-07","itemId":"30012","quantity":1},`, 200) + `{}]}`)
+	tests := []struct {
+		name       string
+		payload    []byte
+		compressed bool
+	}{
+		{name: "empty", payload: []byte{}},
+		{name: "small", payload: []byte(`{"version":1}`)},
+		{name: "small starting with the magic", payload: []byte{payloadMagic, 'x'}},
+		{name: "large", payload: large, compressed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := EncodePayload(tt.payload)
+			if compressed := len(encoded) > 1 && encoded[0] == payloadMagic && encoded[1] == payloadCodecZstd; compressed != tt.compressed {
+				t.Fatalf("compressed = %v, want %v", compressed, tt.compressed)
+			}
+			if tt.compressed && len(encoded) >= len(tt.payload) {
+				t.Errorf("encoded length %d is not less than %d", len(encoded), len(tt.payload))
+			}
+			decoded, err := DecodePayload(encoded)
+			if err != nil {
+				t.Fatalf("DecodePayload returned error: %v", err)
+			}
+			if !bytes.Equal(decoded, tt.payload) {
+				t.Errorf("DecodePayload = %q, want %q", decoded, tt.payload)
+			}
+		})
+	}
+}
+
+func TestDecodePayloadUntagged(t *testing.T) {
+	// the payloads written before they were tagged are read as they are
+	for _, payload := range [][]byte{[]byte(`{"id":"x"}`), {0x1f, 0x8b, 0x08}} {
+		decoded, err := DecodePayload(payload)
+		if err != nil {
+			t.Fatalf("DecodePayload returned error: %v", err)
+		}
+		if !bytes.Equal(decoded, payload) {
+			t.Errorf("DecodePayload = %q, want %q", decoded, payload)
+		}
+	}
+
+	if _, err := DecodePayload([]byte{payloadMagic, 0x7f}); err == nil {
+		t.Error("DecodePayload of an unknown codec returned no error")
+	}
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
@@ -11,16 +12,29 @@ import (
 
 func NewSet[T any](prefix string) *Set[T] {
 	return &Set[T]{
+		name:   prefix,
 		prefix: prefix + ":",
 		c:      cache.New(cache.NoExpiration, time.Minute*10),
 	}
 }
 
+// NewBoundedSet is NewSet holding at most maxItems items. Once it is full, the expired items are evicted upon setting
+// another one, which is left out when none has expired.
+func NewBoundedSet[T any](prefix string, maxItems int) *Set[T] {
+	s := NewSet[T](prefix)
+	s.maxItems = maxItems
+	return s
+}
+
 type Set[T any] struct {
 	// m is a mutex for MutexGetSet for concurrent prevention
 	m sync.Mutex
 
+	// name labels the hit ratio metrics of the set
+	name   string
 	prefix string
+	// maxItems bounds the number of items in the set; zero leaves it unbounded
+	maxItems int
 
 	c *cache.Cache
 }
@@ -30,6 +44,12 @@ func (c *Set[T]) key(key string) string {
 }
 
 func (c *Set[T]) Get(key string, dest *T) error {
+	err := c.get(key, dest)
+	observeGet(c.name, err)
+	return err
+}
+
+func (c *Set[T]) get(key string, dest *T) error {
 	key = c.key(key)
 	result, ok := c.c.Get(key)
 	if !ok {
@@ -52,6 +72,15 @@ func (c *Set[T]) Get(key string, dest *T) error {
 
 func (c *Set[T]) Set(key string, value T, expire time.Duration) {
 	key = c.key(key)
+	if c.maxItems > 0 && c.c.ItemCount() >= c.maxItems {
+		c.c.DeleteExpired()
+		if c.c.ItemCount() >= c.maxItems {
+			if l := log.Trace(); l.Enabled() {
+				l.Str("key", key).Msg("cache is full, leaving value out")
+			}
+			return
+		}
+	}
 	if l := log.Trace(); l.Enabled() {
 		l.Str("key", key).Msg("setting value to cache")
 	}
@@ -75,7 +104,7 @@ func (c *Set[T]) MutexGetSet(key string, dest *T, valueFunc func() (*T, error),
 func (c *Set[T]) slowMutexGetSet(key string, dest *T, valueFunc func() (*T, error), expire time.Duration) error {
 	c.m.Lock()
 	defer c.m.Unlock()
-	err := c.Get(key, dest)
+	err := c.get(key, dest)
 
 	if err == nil {
 		return nil
@@ -115,7 +144,38 @@ func (c *Set[T]) Delete(key string) error {
 	return nil
 }
 
+// DeletePrefix deletes every value of which the key starts with prefix
+func (c *Set[T]) DeletePrefix(prefix string) error {
+	prefix = c.key(prefix)
+	for key := range c.c.Items() {
+		if strings.HasPrefix(key, prefix) {
+			c.c.Delete(key)
+		}
+	}
+	if l := log.Trace(); l.Enabled() {
+		l.Str("prefix", prefix).Msg("deleting values by prefix from cache")
+	}
+
+	return nil
+}
+
 func (c *Set[T]) Flush() error {
 	c.c.Flush()
 	return nil
 }
+
+// Items returns a copy of every unexpired item in the set, keyed by their keys without prefix
+func (c *Set[T]) Items() map[string]T {
+	items := make(map[string]T)
+	for key, item := range c.c.Items() {
+		if !strings.HasPrefix(key, c.prefix) {
+			continue
+		}
+		value, ok := item.Object.(T)
+		if !ok {
+			continue
+		}
+		items[strings.TrimPrefix(key, c.prefix)] = value
+	}
+	return items
+}
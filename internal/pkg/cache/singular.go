@@ -26,6 +26,12 @@ type Singular[T any] struct {
 }
 
 func (c *Singular[T]) Get(dest *T) error {
+	err := c.get(dest)
+	observeGet(c.key, err)
+	return err
+}
+
+func (c *Singular[T]) get(dest *T) error {
 	result, ok := c.c.Get(c.key)
 	if !ok {
 		return ErrNotFound
@@ -63,7 +69,7 @@ func (c *Singular[T]) MutexGetSet(dest *T, valueFunc func() (T, error), expire t
 func (c *Singular[T]) slowMutexGetSet(dest *T, valueFunc func() (T, error), expire time.Duration) error {
 	c.m.Lock()
 	defer c.m.Unlock()
-	err := c.Get(dest)
+	err := c.get(dest)
 
 	if err == nil {
 		return nil
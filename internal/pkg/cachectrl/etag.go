@@ -0,0 +1,26 @@
+package cachectrl
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ETag sets the ETag of the response from the content hash, and tells whether the If-None-Match of the request
+// already matches it, in which case the content shall be left out with 304 Not Modified
+func ETag(ctx *fiber.Ctx, contentHash string) bool {
+	etag := `"` + contentHash + `"`
+	ctx.Set(fiber.HeaderETag, etag)
+	return matchesETag(ctx.Get(fiber.HeaderIfNoneMatch), etag)
+}
+
+// matchesETag compares the If-None-Match list weakly against etag, as RFC 9110 requires
+func matchesETag(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package cachectrl
+
+import "testing"
+
+func TestMatchesETag(t *testing.T) {
+	tests := []struct {
+		ifNoneMatch string
+		want        bool
+	}{
+		{ifNoneMatch: "", want: false},
+		{ifNoneMatch: `"abc"`, want: true},
+		{ifNoneMatch: `W/"abc"`, want: true},
+		{ifNoneMatch: `"def", "abc"`, want: true},
+		{ifNoneMatch: `"def"`, want: false},
+		{ifNoneMatch: `abc`, want: false},
+		{ifNoneMatch: `*`, want: true},
+	}
+	for _, tt := range tests {
+		if got := matchesETag(tt.ifNoneMatch, `"abc"`); got != tt.want {
+			t.Errorf("matchesETag(%q) = %v, want %v", tt.ifNoneMatch, got, tt.want)
+		}
+	}
+}
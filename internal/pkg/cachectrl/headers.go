@@ -7,6 +7,14 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+const (
+	// HeaderCacheAge is set on the cached aggregate results, to the seconds elapsed since the result has been calculated
+	HeaderCacheAge = "X-Penguin-Cache-Age"
+	// HeaderRefreshAfter is set on the cached aggregate results, to the seconds after which the result is due to be
+	// calculated again, before which requesting it again is of no use
+	HeaderRefreshAfter = "X-Penguin-Refresh-After"
+)
+
 func OptIn(ctx *fiber.Ctx, lastModifiedAt time.Time) {
 	offset := time.Minute * 5
 	OptInCustom(ctx, lastModifiedAt, offset)
@@ -19,6 +27,27 @@ func OptInCustom(ctx *fiber.Ctx, lastModifiedAt time.Time, offset time.Duration)
 	ctx.Response().Header.SetLastModified(lastModifiedAt)
 }
 
+// Freshness hints the age of a result calculated at lastModifiedAt and cached for ttl, and when it is to be refreshed
+func Freshness(ctx *fiber.Ctx, lastModifiedAt time.Time, ttl time.Duration) {
+	age, refreshAfter := freshness(time.Now(), lastModifiedAt, ttl)
+	ctx.Set(HeaderCacheAge, strconv.Itoa(age))
+	ctx.Set(HeaderRefreshAfter, strconv.Itoa(refreshAfter))
+}
+
+// freshness returns the whole seconds elapsed since lastModifiedAt, and those left until it expires rounded up, neither
+// being negative
+func freshness(now time.Time, lastModifiedAt time.Time, ttl time.Duration) (age int, refreshAfter int) {
+	elapsed := now.Sub(lastModifiedAt)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	left := ttl - elapsed
+	if left < 0 {
+		left = 0
+	}
+	return int(elapsed / time.Second), int((left + time.Second - 1) / time.Second)
+}
+
 func OptOut(ctx *fiber.Ctx) {
 	ctx.Set("Cache-Control", "no-cache, no-store, must-revalidate")
 	ctx.Set("Pragma", "no-cache")
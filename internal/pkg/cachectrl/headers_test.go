@@ -0,0 +1,30 @@
+package cachectrl
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFreshness(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		lastModifiedAt   time.Time
+		ttl              time.Duration
+		wantAge          int
+		wantRefreshAfter int
+	}{
+		{lastModifiedAt: now, ttl: time.Hour, wantAge: 0, wantRefreshAfter: 3600},
+		{lastModifiedAt: now.Add(-90 * time.Second), ttl: 5 * time.Minute, wantAge: 90, wantRefreshAfter: 210},
+		{lastModifiedAt: now.Add(-1500 * time.Millisecond), ttl: 5 * time.Second, wantAge: 1, wantRefreshAfter: 4},
+		// expired, yet to be calculated again upon the next request
+		{lastModifiedAt: now.Add(-time.Hour), ttl: time.Minute, wantAge: 3600, wantRefreshAfter: 0},
+		// restored from another instance of which the clock is ahead
+		{lastModifiedAt: now.Add(time.Second), ttl: time.Minute, wantAge: 0, wantRefreshAfter: 60},
+	}
+	for _, tt := range tests {
+		age, refreshAfter := freshness(now, tt.lastModifiedAt, tt.ttl)
+		if age != tt.wantAge || refreshAfter != tt.wantRefreshAfter {
+			t.Errorf("freshness(%s, %s) = %d, %d, want %d, %d", tt.lastModifiedAt, tt.ttl, age, refreshAfter, tt.wantAge, tt.wantRefreshAfter)
+		}
+	}
+}
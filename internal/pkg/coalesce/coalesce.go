@@ -0,0 +1,39 @@
+// Package coalesce shares one computation among the concurrent calls with identical keys, e.g. the identical heavy
+// queries arriving at once after a cached result has expired.
+package coalesce
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Group coalesces the concurrent calls of the same key. The zero value is ready to use.
+type Group[T any] struct {
+	group singleflight.Group
+}
+
+// Do calls fn unless a call of the same key is already in flight, in which case it waits for that call instead and
+// shares its result, error included. The shared result must not be modified by any of the callers.
+func (g *Group[T]) Do(key string, fn func() (T, error)) (T, error) {
+	v, err, _ := g.group.Do(key, func() (any, error) {
+		return fn()
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v.(T), nil
+}
+
+// Key hashes the normalized parameters of a query into a key. The parameters are hashed with sha256 so that the
+// queries of different accounts never share a key.
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,54 @@
+package coalesce
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroupDo(t *testing.T) {
+	var g Group[int]
+	var calls atomic.Int32
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	fn := func() (int, error) {
+		if calls.Add(1) == 1 {
+			close(entered)
+		}
+		<-release
+		return 42, nil
+	}
+
+	const n = 10
+	var done sync.WaitGroup
+	done.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer done.Done()
+			if v, err := g.Do(Key("matrix", "CN"), fn); err != nil || v != 42 {
+				t.Errorf("Do() = %d, %v, want 42, nil", v, err)
+			}
+		}()
+		if i == 0 {
+			<-entered
+		}
+	}
+	// let the other calls join the one in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	if c := calls.Load(); c != 1 {
+		t.Errorf("fn called %d times, want 1", c)
+	}
+}
+
+func TestKey(t *testing.T) {
+	if Key("a", "bc") == Key("ab", "c") {
+		t.Error("Key() does not separate the parts")
+	}
+	if Key("matrix", "CN") != Key("matrix", "CN") {
+		t.Error("Key() is not deterministic")
+	}
+}
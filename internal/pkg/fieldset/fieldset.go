@@ -0,0 +1,143 @@
+// Package fieldset implements sparse fieldsets: marshalling only the requested JSON fields of a struct.
+package fieldset
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+)
+
+// Fieldset is the set of JSON fields of T to be kept when marshalling, in the order they are declared in T
+type Fieldset[T any] struct {
+	fields []field
+}
+
+type field struct {
+	// prefix is the quoted JSON name of the field followed by a colon, with a leading comma unless it is the first field
+	prefix []byte
+	index  []int
+}
+
+// Parse parses a comma separated list of JSON field names of T. The always fields are kept regardless of the list,
+// e.g. the fields identifying an element. Unknown field names are rejected with an error listing the available ones.
+func Parse[T any](list string, always ...string) (*Fieldset[T], error) {
+	available := jsonFields(reflect.TypeOf((*T)(nil)).Elem())
+
+	requested := make(map[string]struct{})
+	for _, name := range append(strings.Split(list, ","), always...) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := available[name]; !ok {
+			names := make([]string, 0, len(available))
+			for n := range available {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return nil, pgerr.ErrInvalidReq.Msg("unknown field '%s': available fields are %s", name, strings.Join(names, ", "))
+		}
+		requested[name] = struct{}{}
+	}
+
+	selected := make([]*jsonField, 0, len(requested))
+	for name := range requested {
+		selected = append(selected, available[name])
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].order < selected[j].order
+	})
+
+	fs := &Fieldset[T]{fields: make([]field, 0, len(selected))}
+	for i, f := range selected {
+		name, err := json.Marshal(f.name)
+		if err != nil {
+			return nil, err
+		}
+		prefix := make([]byte, 0, len(name)+2)
+		if i > 0 {
+			prefix = append(prefix, ',')
+		}
+		prefix = append(append(prefix, name...), ':')
+		fs.fields = append(fs.fields, field{prefix: prefix, index: f.index})
+	}
+	return fs, nil
+}
+
+// Sparse marshals the selected fields of a value only
+type Sparse[T any] struct {
+	value  *T
+	fields []field
+}
+
+func (f *Fieldset[T]) Wrap(value *T) *Sparse[T] {
+	return &Sparse[T]{value: value, fields: f.fields}
+}
+
+func (f *Fieldset[T]) WrapAll(values []*T) []*Sparse[T] {
+	wrapped := make([]*Sparse[T], len(values))
+	for i, value := range values {
+		wrapped[i] = f.Wrap(value)
+	}
+	return wrapped
+}
+
+func (s *Sparse[T]) MarshalJSON() ([]byte, error) {
+	if s.value == nil {
+		return []byte("null"), nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	v := reflect.ValueOf(s.value).Elem()
+	for _, f := range s.fields {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// the field is promoted from a nil embedded struct pointer
+			continue
+		}
+		b, err := json.Marshal(fv.Interface())
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(f.prefix)
+		buf.Write(b)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+type jsonField struct {
+	name  string
+	index []int
+	order int
+}
+
+// jsonFields returns the fields of struct type t by their JSON names, including the ones promoted from embedded
+// structs. As encoding/json does, a field at a shallower depth shadows the ones with the same name deeper.
+func jsonFields(t reflect.Type) map[string]*jsonField {
+	fields := make(map[string]*jsonField)
+	for order, f := range reflect.VisibleFields(t) {
+		if f.Anonymous || !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		if existing, ok := fields[name]; ok && len(existing.index) <= len(f.Index) {
+			continue
+		}
+		fields[name] = &jsonField{name: name, index: f.Index, order: order}
+	}
+	return fields
+}
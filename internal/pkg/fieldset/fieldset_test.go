@@ -0,0 +1,49 @@
+package fieldset
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+type inner struct {
+	ID       string `json:"id"`
+	Quantity int    `json:"quantity"`
+	Times    int    `json:"times"`
+}
+
+type outer struct {
+	*inner
+	Name  string `json:"name"`
+	Times string `json:"times"`
+}
+
+func TestSparse(t *testing.T) {
+	tests := []struct {
+		fields string
+		always []string
+		want   string
+	}{
+		{"quantity,times", []string{"id"}, `{"id":"a","quantity":2,"times":"three"}`},
+		{"name, quantity", nil, `{"quantity":2,"name":"n"}`},
+		{"", []string{"id"}, `{"id":"a"}`},
+	}
+	value := &outer{inner: &inner{ID: "a", Quantity: 2, Times: 3}, Name: "n", Times: "three"}
+	for _, tt := range tests {
+		fs, err := Parse[outer](tt.fields, tt.always...)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.fields, err)
+		}
+		b, err := json.Marshal(fs.Wrap(value))
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("fields %q = %s, want %s", tt.fields, b, tt.want)
+		}
+	}
+
+	if _, err := Parse[outer]("quantity,unknown"); err == nil {
+		t.Error("Parse with an unknown field should have failed")
+	}
+}
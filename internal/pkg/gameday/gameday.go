@@ -28,3 +28,10 @@ func IsStartTime(server string, t time.Time) bool {
 		t.Second() == constant.GameDayStartSecond &&
 		t.Nanosecond() == constant.GameDayStartNano
 }
+
+// NextStartTime returns the start of the game day after the one of t. Unlike EndTime, it follows the days of the
+// server across the changes of its offset, such as those of the daylight saving time.
+func NextStartTime(server string, t time.Time) time.Time {
+	// 26 hours after the start of a day is well within the next one, whichever way the offset changes in between
+	return StartTime(server, StartTime(server, t).Add(time.Hour*26))
+}
@@ -1,6 +1,16 @@
 package geoip
 
-import _ "embed"
+import (
+	_ "embed"
+
+	"github.com/oschwald/geoip2-golang"
+)
 
 //go:embed data/GeoLite2-Country.mmdb
 var Database []byte
+
+// ASNDatabase is the ASN database of MaxMind, which is opened from a path rather than embedded. Reader is nil when
+// none is configured.
+type ASNDatabase struct {
+	Reader *geoip2.Reader
+}
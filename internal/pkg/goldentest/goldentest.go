@@ -0,0 +1,170 @@
+// Package goldentest seeds a throwaway Postgres with known rows and compares the results calculated from them with
+// frozen expected JSON, so that the refactors of the aggregations can be validated against the golden files.
+//
+// Postgres is run in Docker with dockertest. The tests using it are skipped when Docker is not available, or with
+// -short. Run the tests with -update to rewrite the golden files from the current results.
+package goldentest
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files from the current results")
+
+// the image the database is run from
+const (
+	postgresRepository = "postgres"
+	postgresTag        = "15-alpine"
+	postgresImage      = postgresRepository + ":" + postgresTag
+)
+
+// Postgres runs an empty database in Docker, which is purged once the test and its subtests have completed.
+func Postgres(t *testing.T) *bun.DB {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("skipping the golden tests with -short")
+	}
+
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		t.Skipf("skipping the golden tests: docker is not available: %v", err)
+	}
+	if err := pool.Client.Ping(); err != nil {
+		t.Skipf("skipping the golden tests: docker is not available: %v", err)
+	}
+
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: postgresRepository,
+		Tag:        postgresTag,
+		Env:        []string{"POSTGRES_PASSWORD=golden", "POSTGRES_DB=golden", "TZ=UTC"},
+	}, func(hc *docker.HostConfig) {
+		hc.AutoRemove = true
+		hc.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		t.Fatalf("failed to run %s: %v", postgresImage, err)
+	}
+	t.Cleanup(func() {
+		if err := pool.Purge(resource); err != nil {
+			t.Logf("failed to purge %s: %v", postgresImage, err)
+		}
+	})
+	// the container is reaped even when the cleanups never get to run
+	_ = resource.Expire(300)
+
+	dsn := fmt.Sprintf("postgres://postgres:golden@%s/golden?sslmode=disable", resource.GetHostPort("5432/tcp"))
+	db := bun.NewDB(sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn))), pgdialect.New())
+	pool.MaxWait = time.Minute
+	if err := pool.Retry(func() error {
+		return db.PingContext(context.Background())
+	}); err != nil {
+		t.Fatalf("failed to connect to %s: %v", postgresImage, err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// Schema runs the statements of the SQL file at path, which creates the tables to be seeded. The tables are declared
+// by hand rather than created from the models, for their columns to have the types of the production database.
+func Schema(t *testing.T, db *bun.DB, path string) {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read schema %s: %v", path, err)
+	}
+	if _, err := db.ExecContext(context.Background(), string(b)); err != nil {
+		t.Fatalf("failed to create schema %s: %v", path, err)
+	}
+}
+
+// Fixture maps the names of the tables to the JSON arrays of the rows seeded into them. The rows are decoded into the
+// models of the tables, hence keyed by the JSON names of their fields.
+type Fixture map[string]json.RawMessage
+
+// Seed inserts the rows of each table in the fixture at path into it. The models are pointers to the slices of the
+// models of the tables, such as &[]*model.DropReport{}, and each table in the fixture needs one.
+func Seed(t *testing.T, db *bun.DB, path string, models ...any) {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", path, err)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(b, &fixture); err != nil {
+		t.Fatalf("failed to decode fixture %s: %v", path, err)
+	}
+
+	ctx := context.Background()
+	seeded := make(map[string]bool, len(fixture))
+	for _, m := range models {
+		table := db.Table(reflect.TypeOf(m).Elem().Elem()).Name
+		rows, ok := fixture[table]
+		if !ok {
+			continue
+		}
+		seeded[table] = true
+		if err := json.Unmarshal(rows, m); err != nil {
+			t.Fatalf("failed to decode the rows of table %s in fixture %s: %v", table, path, err)
+		}
+		if reflect.ValueOf(m).Elem().Len() == 0 {
+			continue
+		}
+		if _, err := db.NewInsert().Model(m).Exec(ctx); err != nil {
+			t.Fatalf("failed to seed table %s: %v", table, err)
+		}
+	}
+	for table := range fixture {
+		if !seeded[table] {
+			t.Fatalf("fixture %s has rows for table %s, which has no model to seed it with", path, table)
+		}
+	}
+}
+
+// Assert compares the indented JSON of v with the golden file at path, or rewrites the file with it under -update.
+func Assert(t *testing.T, path string, v any) {
+	t.Helper()
+
+	got, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to encode the result for %s: %v", path, err)
+	}
+	got = append(got, '\n')
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create the directory of %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("failed to update %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s, run the test with -update to create it: %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("result differs from golden file %s, run the test with -update to accept it\n--- got\n%s\n--- want\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,98 @@
+// Package jsonstream encodes the JSON objects wrapping a large array or map, e.g. the drop matrix, one element at a
+// time, so that the encoded object is written out as it goes rather than being buffered as a whole.
+package jsonstream
+
+import (
+	"io"
+	"sort"
+
+	"github.com/goccy/go-json"
+)
+
+// Array writes the object {"<field>":[...]} with the elements encoded one at a time. A nil slice is written as null,
+// as json.Marshal does.
+func Array[T any](w io.Writer, field string, elements []T) error {
+	sw := &writer{w: w}
+	if elements == nil {
+		sw.null(field)
+		return sw.err
+	}
+	sw.open(field, '[')
+	for i, element := range elements {
+		if i > 0 {
+			sw.write([]byte{','})
+		}
+		sw.value(element)
+	}
+	sw.close(']')
+	return sw.err
+}
+
+// Map writes the object {"<field>":{...}} with the entries encoded one at a time, in the order of their keys
+// as json.Marshal does. A nil map is written as null.
+func Map[T any](w io.Writer, field string, entries map[string]T) error {
+	sw := &writer{w: w}
+	if entries == nil {
+		sw.null(field)
+		return sw.err
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sw.open(field, '{')
+	for i, key := range keys {
+		if i > 0 {
+			sw.write([]byte{','})
+		}
+		sw.value(key)
+		sw.write([]byte{':'})
+		sw.value(entries[key])
+	}
+	sw.close('}')
+	return sw.err
+}
+
+// writer keeps the first error it runs into, after which all the writes are skipped
+type writer struct {
+	w   io.Writer
+	err error
+}
+
+func (sw *writer) write(p []byte) {
+	if sw.err != nil {
+		return
+	}
+	_, sw.err = sw.w.Write(p)
+}
+
+func (sw *writer) value(v any) {
+	if sw.err != nil {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		sw.err = err
+		return
+	}
+	sw.write(b)
+}
+
+func (sw *writer) open(field string, delim byte) {
+	sw.write([]byte{'{'})
+	sw.value(field)
+	sw.write([]byte{':', delim})
+}
+
+func (sw *writer) null(field string) {
+	sw.write([]byte{'{'})
+	sw.value(field)
+	sw.write([]byte(":null}"))
+}
+
+func (sw *writer) close(delim byte) {
+	sw.write([]byte{delim, '}'})
+}
@@ -0,0 +1,53 @@
+package jsonstream
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+type element struct {
+	StageID  string `json:"stageId"`
+	Quantity int    `json:"quantity"`
+}
+
+func TestArray(t *testing.T) {
+	tests := [][]*element{
+		nil,
+		{},
+		{{StageID: "main_01-07", Quantity: 1}, {StageID: "a\"b", Quantity: 2}},
+	}
+	for _, elements := range tests {
+		var buf bytes.Buffer
+		if err := Array(&buf, "matrix", elements); err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := json.Marshal(struct {
+			Matrix []*element `json:"matrix"`
+		}{elements})
+		if buf.String() != string(expected) {
+			t.Errorf("Array() = %s, want %s", buf.String(), expected)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	tests := []map[string]*element{
+		nil,
+		{},
+		{"main_01-07": {StageID: "main_01-07", Quantity: 1}, "a_001_01": {StageID: "a_001_01"}, "z": nil},
+	}
+	for _, entries := range tests {
+		var buf bytes.Buffer
+		if err := Map(&buf, "trend", entries); err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := json.Marshal(struct {
+			Trend map[string]*element `json:"trend"`
+		}{entries})
+		if buf.String() != string(expected) {
+			t.Errorf("Map() = %s, want %s", buf.String(), expected)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Package lp solves the linear programs of planning, e.g. the cheapest runs of the stages dropping the items needed.
+package lp
+
+import "github.com/pkg/errors"
+
+// eps is the tolerance of the comparisons of the tableau entries against zero
+const eps = 1e-9
+
+// ErrInfeasible is returned when a demand cannot be covered by any of the variables
+var ErrInfeasible = errors.New("lp: demands cannot be covered")
+
+// SolveCovering minimizes costs · x subject to rates · x >= demands and x >= 0, where rates[j][i] is how much of
+// demand i a unit of x[j] covers. The costs must not be negative.
+//
+// The problem is solved through its dual, maximizing demands · y subject to rates[j] · y <= costs[j] and y >= 0,
+// of which the origin is a feasible basis so that no first phase is needed. The optimal x are the shadow prices
+// of the constraints of the dual. Bland's rule is used to pivot, which never cycles.
+func SolveCovering(costs []float64, rates [][]float64, demands []float64) ([]float64, error) {
+	m, n := len(costs), len(demands)
+	if len(rates) != m {
+		return nil, errors.New("lp: rates and costs differ in length")
+	}
+
+	// the rows of the tableau are the constraints of the dual, followed by the objective row. The columns are
+	// the n dual variables, the m slack variables and the right-hand side.
+	width := n + m + 1
+	tableau := make([][]float64, m+1)
+	basis := make([]int, m)
+	for j := 0; j < m; j++ {
+		if len(rates[j]) != n {
+			return nil, errors.New("lp: rates and demands differ in length")
+		}
+		if costs[j] < 0 {
+			return nil, errors.New("lp: costs must not be negative")
+		}
+		row := make([]float64, width)
+		copy(row, rates[j])
+		row[n+j] = 1
+		row[width-1] = costs[j]
+		tableau[j] = row
+		basis[j] = n + j
+	}
+	objective := make([]float64, width)
+	for i, demand := range demands {
+		objective[i] = -demand
+	}
+	tableau[m] = objective
+
+	for {
+		col := -1
+		for c := 0; c < width-1; c++ {
+			if objective[c] < -eps {
+				col = c
+				break
+			}
+		}
+		if col < 0 {
+			break
+		}
+
+		row := -1
+		var bestRatio float64
+		for r := 0; r < m; r++ {
+			if tableau[r][col] <= eps {
+				continue
+			}
+			ratio := tableau[r][width-1] / tableau[r][col]
+			if row < 0 || ratio < bestRatio-eps || (ratio <= bestRatio+eps && basis[r] < basis[row]) {
+				row, bestRatio = r, ratio
+			}
+		}
+		if row < 0 {
+			// the dual is unbounded: some demand is not covered by any variable
+			return nil, ErrInfeasible
+		}
+
+		pivot(tableau, row, col)
+		basis[row] = col
+	}
+
+	x := make([]float64, m)
+	for j := range x {
+		x[j] = objective[n+j]
+	}
+	return x, nil
+}
+
+func pivot(tableau [][]float64, row, col int) {
+	pivotRow := tableau[row]
+	p := pivotRow[col]
+	for c := range pivotRow {
+		pivotRow[c] /= p
+	}
+	for r, other := range tableau {
+		if r == row || other[col] == 0 {
+			continue
+		}
+		f := other[col]
+		for c := range other {
+			other[c] -= f * pivotRow[c]
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package lp
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSolveCovering(t *testing.T) {
+	tests := []struct {
+		name    string
+		costs   []float64
+		rates   [][]float64
+		demands []float64
+		want    []float64
+	}{
+		{
+			name:    "single variable",
+			costs:   []float64{6},
+			rates:   [][]float64{{0.5}},
+			demands: []float64{10},
+			want:    []float64{20},
+		},
+		{
+			name:  "byproduct covers part of the demand",
+			costs: []float64{10, 15},
+			rates: [][]float64{
+				{1, 0},
+				{0.5, 1},
+			},
+			demands: []float64{10, 4},
+			want:    []float64{8, 4},
+		},
+		{
+			name:  "cheaper variable wins",
+			costs: []float64{9, 12, 20},
+			rates: [][]float64{
+				{0.3, 0},
+				{0.5, 0},
+				{0, 0.2},
+			},
+			demands: []float64{5, 1},
+			want:    []float64{0, 10, 5},
+		},
+		{
+			name:    "nothing demanded",
+			costs:   []float64{10},
+			rates:   [][]float64{{1}},
+			demands: []float64{0},
+			want:    []float64{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SolveCovering(tt.costs, tt.rates, tt.demands)
+			if err != nil {
+				t.Fatalf("SolveCovering() error = %v", err)
+			}
+			for j := range tt.want {
+				if math.Abs(got[j]-tt.want[j]) > 1e-6 {
+					t.Fatalf("SolveCovering() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSolveCoveringInfeasible(t *testing.T) {
+	_, err := SolveCovering([]float64{10}, [][]float64{{1, 0}}, []float64{1, 1})
+	if !errors.Is(err, ErrInfeasible) {
+		t.Fatalf("SolveCovering() error = %v, want ErrInfeasible", err)
+	}
+}
@@ -0,0 +1,109 @@
+package middlewares
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+)
+
+const (
+	// HeaderAPIKey is the request header carrying the API key
+	HeaderAPIKey = "X-Penguin-API-Key"
+
+	// LocalsAPIKeyKey is the key of ctx.Locals that holds the authenticated *model.APIKey
+	LocalsAPIKeyKey = "apiKey"
+)
+
+// APIKeyAuthentication only allows requests with a valid, unrevoked API key to proceed
+func APIKeyAuthentication(authn func(ctx context.Context, key string) (*model.APIKey, error)) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		// already authenticated by APIKeyQuota
+		if APIKeyFrom(ctx) != nil {
+			return ctx.Next()
+		}
+
+		key := ctx.Get(HeaderAPIKey)
+		if key == "" {
+			return ctx.SendStatus(fiber.StatusUnauthorized)
+		}
+
+		apiKey, err := authn(ctx.UserContext(), key)
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusUnauthorized)
+		}
+
+		ctx.Locals(LocalsAPIKeyKey, apiKey)
+		return ctx.Next()
+	}
+}
+
+// APIKeyFrom returns the API key authenticated by APIKeyAuthentication
+func APIKeyFrom(ctx *fiber.Ctx) *model.APIKey {
+	apiKey, _ := ctx.Locals(LocalsAPIKeyKey).(*model.APIKey)
+	return apiKey
+}
+
+// APIKeyQuota enforces the daily quotas of the requests carrying an API key, and accounts their usage.
+// Requests without an API key proceed as anonymous requests, while those with an invalid one are rejected.
+func APIKeyQuota(
+	authn func(ctx context.Context, key string) (*model.APIKey, error),
+	admit func(ctx context.Context, apiKey *model.APIKey) (*modelv3.DailyAPIUsage, error),
+	record func(ctx context.Context, apiKey *model.APIKey, bytes int) error,
+) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		key := ctx.Get(HeaderAPIKey)
+		if key == "" {
+			return ctx.Next()
+		}
+
+		apiKey, err := authn(ctx.UserContext(), key)
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusUnauthorized)
+		}
+		ctx.Locals(LocalsAPIKeyKey, apiKey)
+
+		usage, err := admit(ctx.UserContext(), apiKey)
+		if err != nil {
+			return err
+		}
+		if apiKey.DailyRequestQuota > 0 {
+			ctx.Set("X-RateLimit-Limit", strconv.Itoa(apiKey.DailyRequestQuota))
+			ctx.Set("X-RateLimit-Remaining", strconv.FormatInt(int64(apiKey.DailyRequestQuota)-usage.Requests-1, 10))
+		}
+
+		err = ctx.Next()
+
+		// reading a streamed body would read it as a whole, so it is accounted once it has been written instead,
+		// by when the request context is no longer usable
+		if ctx.Response().IsBodyStream() {
+			OnStreamed(ctx, func(written int) {
+				recordAPIKeyUsage(context.Background(), record, apiKey, written)
+			})
+			return err
+		}
+
+		// the error handler has not rendered the error yet, so error responses are accounted as empty
+		bytes := ctx.Response().Header.ContentLength()
+		if bytes < 0 {
+			bytes = len(ctx.Response().Body())
+		}
+		recordAPIKeyUsage(ctx.UserContext(), record, apiKey, bytes)
+
+		return err
+	}
+}
+
+func recordAPIKeyUsage(ctx context.Context, record func(ctx context.Context, apiKey *model.APIKey, bytes int) error, apiKey *model.APIKey, bytes int) {
+	if err := record(ctx, apiKey, bytes); err != nil {
+		log.Error().
+			Err(err).
+			Str("evt.name", "apikey.usage.record").
+			Int("apiKeyId", apiKey.APIKeyID).
+			Msg("failed to record api key usage")
+	}
+}
@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"exusiai.dev/backend-next/internal/model"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+)
+
+func TestAPIKeyQuotaStreamed(t *testing.T) {
+	body := strings.Repeat(`{"stageId":"main_01-07","itemId":"30012","times":100,"quantity":50}`, 1000)
+
+	recorded := make(chan int, 1)
+	app := fiber.New()
+	streamed := make(chan bool, 1)
+	app.Use(func(ctx *fiber.Ctx) error {
+		err := ctx.Next()
+		streamed <- ctx.Response().IsBodyStream()
+		return err
+	})
+	app.Use(APIKeyQuota(
+		func(ctx context.Context, key string) (*model.APIKey, error) {
+			return &model.APIKey{APIKeyID: 1}, nil
+		},
+		func(ctx context.Context, apiKey *model.APIKey) (*modelv3.DailyAPIUsage, error) {
+			return &modelv3.DailyAPIUsage{}, nil
+		},
+		func(ctx context.Context, apiKey *model.APIKey, bytes int) error {
+			recorded <- bytes
+			return nil
+		},
+	))
+	app.Get("/", func(ctx *fiber.Ctx) error {
+		SetBodyStreamWriter(ctx, func(w *bufio.Writer) {
+			_, _ = w.WriteString(body)
+		})
+		return nil
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(HeaderAPIKey, "key")
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(resp.Body); err != nil {
+		t.Fatal(err)
+	}
+	if !<-streamed {
+		t.Errorf("response is no longer a body stream after the quota")
+	}
+	select {
+	case bytes := <-recorded:
+		if bytes != len(body) {
+			t.Errorf("recorded %d bytes, want %d", bytes, len(body))
+		}
+	case <-time.After(time.Second):
+		t.Errorf("usage of the streamed response never recorded")
+	}
+}
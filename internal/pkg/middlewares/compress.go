@@ -0,0 +1,204 @@
+package middlewares
+
+import (
+	"errors"
+	"hash/maphash"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/patrickmn/go-cache"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	EncodingZstd   = "zstd"
+	EncodingBrotli = "br"
+	EncodingGzip   = "gzip"
+
+	// LocalsCompressStreamsKey is the key of ctx.Locals that is set when the streamed bodies are to be encoded by
+	// their handlers, as Compress leaves them as is
+	LocalsCompressStreamsKey = "compressStreams"
+)
+
+var (
+	// compressEncodings are the supported encodings, in the order of preference among equally acceptable ones
+	compressEncodings = []string{EncodingZstd, EncodingBrotli, EncodingGzip}
+
+	// streamEncodings are the encodings the streamed bodies may be encoded with while they are being written
+	streamEncodings = []string{EncodingZstd, EncodingGzip}
+)
+
+type CompressConfig struct {
+	// MinLength is the minimum length of a response body for it to be compressed
+	MinLength int
+
+	// CacheExpiration is how long the compressed variants of shared responses are kept for. Responses are shared
+	// when they are allowed to be cached publicly, e.g. the global matrices, so that an identical body
+	// is only compressed once per encoding rather than per request.
+	CacheExpiration time.Duration
+}
+
+// Compress encodes the response bodies with zstd, brotli or gzip, whichever is the most acceptable to the client.
+// Streamed bodies, already encoded bodies and the content types that do not benefit from it are left as is.
+func Compress(config CompressConfig) fiber.Handler {
+	zstdEncoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	if err != nil {
+		panic(err)
+	}
+	zstdSharedEncoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+	if err != nil {
+		panic(err)
+	}
+	seed := maphash.MakeSeed()
+	variants := cache.New(config.CacheExpiration, config.CacheExpiration)
+
+	compress := func(encoding string, body []byte, shared bool) []byte {
+		// shared bodies are compressed once per cache fill, which is worth a better compression ratio
+		switch encoding {
+		case EncodingZstd:
+			if shared {
+				return zstdSharedEncoder.EncodeAll(body, nil)
+			}
+			return zstdEncoder.EncodeAll(body, nil)
+		case EncodingBrotli:
+			if shared {
+				return fasthttp.AppendBrotliBytesLevel(nil, body, 9)
+			}
+			return fasthttp.AppendBrotliBytesLevel(nil, body, fasthttp.CompressBrotliDefaultCompression)
+		default:
+			if shared {
+				return fasthttp.AppendGzipBytesLevel(nil, body, fasthttp.CompressBestCompression)
+			}
+			return fasthttp.AppendGzipBytesLevel(nil, body, fasthttp.CompressDefaultCompression)
+		}
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		ctx.Locals(LocalsCompressStreamsKey, true)
+		if err := ctx.Next(); err != nil {
+			return err
+		}
+
+		resp := ctx.Response()
+		if resp.IsBodyStream() || len(resp.Header.Peek(fiber.HeaderContentEncoding)) > 0 ||
+			ctx.Method() == fiber.MethodHead || !compressibleContentType(string(resp.Header.ContentType())) {
+			return nil
+		}
+		ctx.Vary(fiber.HeaderAcceptEncoding)
+
+		body := resp.Body()
+		if len(body) < config.MinLength {
+			return nil
+		}
+		encoding := NegotiateEncoding(ctx.Get(fiber.HeaderAcceptEncoding))
+		if encoding == "" {
+			return nil
+		}
+
+		shared := resp.StatusCode() == fiber.StatusOK && strings.HasPrefix(string(resp.Header.Peek(fiber.HeaderCacheControl)), "public")
+		var compressed []byte
+		if shared {
+			key := encoding + ":" + strconv.FormatUint(maphash.Bytes(seed, body), 36) + ":" + strconv.Itoa(len(body))
+			if cached, ok := variants.Get(key); ok {
+				compressed = cached.([]byte)
+			} else {
+				compressed = compress(encoding, body, true)
+				variants.SetDefault(key, compressed)
+			}
+		} else {
+			compressed = compress(encoding, body, false)
+		}
+
+		resp.SetBodyRaw(compressed)
+		resp.Header.Set(fiber.HeaderContentEncoding, encoding)
+		return nil
+	}
+}
+
+// NegotiateEncoding picks the most acceptable supported encoding for the given Accept-Encoding header,
+// or an empty string when the response shall not be encoded
+func NegotiateEncoding(acceptEncoding string) string {
+	return negotiate(acceptEncoding, compressEncodings)
+}
+
+// StreamEncoding picks the encoding to encode the streamed body of the response with, or an empty string when it
+// shall not be encoded, e.g. when Compress is not in use
+func StreamEncoding(ctx *fiber.Ctx) string {
+	if enabled, _ := ctx.Locals(LocalsCompressStreamsKey).(bool); !enabled {
+		return ""
+	}
+	return negotiate(ctx.Get(fiber.HeaderAcceptEncoding), streamEncodings)
+}
+
+// NewStreamEncoder wraps w with an encoder of the encoding picked by StreamEncoding. The encoder must be closed
+// for the encoded body to be flushed to w.
+func NewStreamEncoder(w io.Writer, encoding string) (io.WriteCloser, error) {
+	switch encoding {
+	case EncodingZstd:
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	case EncodingGzip:
+		return gzip.NewWriterLevel(w, gzip.DefaultCompression)
+	case "":
+		return nopWriteCloser{w}, nil
+	default:
+		return nil, errors.New("unsupported stream encoding: " + encoding)
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func negotiate(acceptEncoding string, supported []string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	qualities := make(map[string]float64, len(supported))
+	wildcard := -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		q := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			parsed, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if coding == "*" {
+			wildcard = q
+		} else {
+			qualities[coding] = q
+		}
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range supported {
+		q, ok := qualities[encoding]
+		if !ok {
+			if wildcard < 0 {
+				continue
+			}
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+func compressibleContentType(contentType string) bool {
+	return strings.HasPrefix(contentType, "text/") ||
+		strings.Contains(contentType, "json") ||
+		strings.HasPrefix(contentType, fiber.MIMEApplicationJavaScript)
+}
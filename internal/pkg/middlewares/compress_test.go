@@ -0,0 +1,66 @@
+package middlewares
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/valyala/fasthttp"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		want           string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"gzip, deflate, br", "br"},
+		{"gzip, deflate, br, zstd", "zstd"},
+		{"br;q=0.5, gzip;q=0.8", "gzip"},
+		{"zstd;q=0, br", "br"},
+		{"*", "zstd"},
+		{"*;q=0.1, gzip", "gzip"},
+		{"gzip;q=0", ""},
+	}
+	for _, tt := range tests {
+		if got := NegotiateEncoding(tt.acceptEncoding); got != tt.want {
+			t.Errorf("NegotiateEncoding(%q) = %q, want %q", tt.acceptEncoding, got, tt.want)
+		}
+	}
+}
+
+func TestNewStreamEncoder(t *testing.T) {
+	body := strings.Repeat(`{"stageId":"main_01-07","itemId":"30012","times":100,"quantity":50}`, 100)
+	for _, encoding := range streamEncodings {
+		var buf bytes.Buffer
+		enc, err := NewStreamEncoder(&buf, encoding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(enc, body); err != nil {
+			t.Fatal(err)
+		}
+		if err := enc.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded []byte
+		switch encoding {
+		case EncodingZstd:
+			dec, _ := zstd.NewReader(nil)
+			decoded, err = dec.DecodeAll(buf.Bytes(), nil)
+		case EncodingGzip:
+			decoded, err = fasthttp.AppendGunzipBytes(nil, buf.Bytes())
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(decoded) != body {
+			t.Errorf("%s: decoded body does not match", encoding)
+		}
+	}
+}
@@ -136,6 +136,11 @@ func Idempotency(config *IdempotencyConfig) fiber.Handler {
 			return err
 		}
 
+		// Streamed responses are not saved, as reading them would read the whole stream into memory
+		if c.Response().IsBodyStream() {
+			return nil
+		}
+
 		// Marshal response to bytes
 		responseBytes, err := marshalResponseToBytes(c, config)
 		if err != nil {
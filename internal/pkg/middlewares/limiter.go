@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+// TunableLimiter limits the requests as limiter.New does, but looks up the maximum upon every request so that
+// it can be tuned at runtime. As the limiter is recreated whenever the maximum changes, the counters start over then.
+func TunableLimiter(config limiter.Config, max func() int) fiber.Handler {
+	var (
+		mu         sync.Mutex
+		currentMax int
+		current    fiber.Handler
+	)
+	return func(c *fiber.Ctx) error {
+		m := max()
+
+		mu.Lock()
+		if current == nil || currentMax != m {
+			config.Max = m
+			currentMax, current = m, limiter.New(config)
+		}
+		handler := current
+		mu.Unlock()
+
+		return handler(c)
+	}
+}
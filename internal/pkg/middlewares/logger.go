@@ -28,12 +28,19 @@ func injectLogger() func(ctx *fiber.Ctx) error {
 
 func requestLogger() func(ctx *fiber.Ctx) error {
 	return flog.AccessHandler(func(ctx *fiber.Ctx, duration time.Duration) {
-		flog.InfoFrom(ctx, "http.request").
+		event := flog.InfoFrom(ctx, "http.request").
 			Bytes("http.useragent", ctx.Request().Header.UserAgent()).
 			Int("http.status_code", ctx.Response().StatusCode()).
 			Int("network.bytes_read", len(ctx.Request().Body())).
-			Int("network.bytes_written", len(ctx.Response().Body())).
-			Dur("duration", duration).
-			Msg("received request")
+			Dur("duration", duration)
+
+		// reading a streamed body would read it as a whole, so it is logged once it has been written instead
+		if ctx.Response().IsBodyStream() {
+			OnStreamed(ctx, func(written int) {
+				event.Int("network.bytes_written", written).Msg("received request")
+			})
+			return
+		}
+		event.Int("network.bytes_written", len(ctx.Response().Body())).Msg("received request")
 	})
 }
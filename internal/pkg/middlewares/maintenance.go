@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+)
+
+// HeaderMaintenance is set on the responses served while the API is under maintenance
+const HeaderMaintenance = "X-Penguin-Maintenance"
+
+// Maintenance rejects the writes to the API while current tells it is under maintenance, along with the reason and
+// the ETA of the maintenance. The reads keep being served but are bounded by degraded, as the database may be busy
+// migrating: the aggregates served from the caches still are, while the reads waiting on the database give up early.
+// The requests other than GET, HEAD and OPTIONS are writes, except for those to the paths of reads, which only read
+// despite being POSTed.
+func Maintenance(current func() *model.Maintenance, degraded time.Duration, reads ...string) fiber.Handler {
+	readPaths := make(map[string]struct{}, len(reads))
+	for _, path := range reads {
+		readPaths[path] = struct{}{}
+	}
+	return func(ctx *fiber.Ctx) error {
+		maintenance := current()
+		if !maintenance.Active {
+			return ctx.Next()
+		}
+		ctx.Set(HeaderMaintenance, "1")
+
+		switch ctx.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return QueryTimeout(degraded)(ctx)
+		}
+		if _, ok := readPaths[strings.TrimSuffix(ctx.Path(), "/")]; ok {
+			return QueryTimeout(degraded)(ctx)
+		}
+
+		extras := pgerr.Extras{}
+		if maintenance.Reason != "" {
+			extras["reason"] = maintenance.Reason
+		}
+		if maintenance.ETA != nil {
+			extras["eta"] = maintenance.ETA
+			if retryAfter := int(math.Ceil(time.Until(*maintenance.ETA).Seconds())); retryAfter > 0 {
+				ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+			}
+		}
+		return pgerr.ErrMaintenance.WithExtras(extras)
+	}
+}
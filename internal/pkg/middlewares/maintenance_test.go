@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"exusiai.dev/backend-next/internal/model"
+)
+
+func TestMaintenance(t *testing.T) {
+	current := func() *model.Maintenance { return &model.Maintenance{Active: true} }
+	app := fiber.New()
+	v2 := app.Group("/api/v2", Maintenance(current, time.Second, "/api/v2/result/advanced"))
+	ok := func(ctx *fiber.Ctx) error { return ctx.SendStatus(fiber.StatusOK) }
+	v2.Get("/result/matrix", ok)
+	v2.Post("/result/advanced", ok)
+	v2.Post("/report", ok)
+
+	tests := []struct {
+		method   string
+		path     string
+		wantRead bool
+	}{
+		{fiber.MethodGet, "/api/v2/result/matrix", true},
+		{fiber.MethodPost, "/api/v2/result/advanced", true},
+		{fiber.MethodPost, "/api/v2/result/advanced/", true},
+		{fiber.MethodPost, "/api/v2/report", false},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(tt.method, tt.path, nil))
+		if err != nil {
+			t.Fatalf("%s %s: %v", tt.method, tt.path, err)
+		}
+		if served := resp.StatusCode == fiber.StatusOK; served != tt.wantRead {
+			t.Errorf("%s %s: status = %d, want it served %v", tt.method, tt.path, resp.StatusCode, tt.wantRead)
+		}
+		if resp.Header.Get(HeaderMaintenance) != "1" {
+			t.Errorf("%s %s: %s header missing", tt.method, tt.path, HeaderMaintenance)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/pkg/flog"
+)
+
+// LocalsAdminAccountKey is the key of ctx.Locals that holds the authenticated admin *model.Account
+const LocalsAdminAccountKey = "adminAccount"
+
+// AdminAuthentication resolves the bearer token in the Authorization header into an account
+// using authn, and rejects the request if the account has not been granted any role.
+func AdminAuthentication(authn func(ctx context.Context, token string) (*model.Account, error)) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		token := strings.TrimSpace(strings.TrimPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer"))
+		if token == "" {
+			return ctx.SendStatus(fiber.StatusUnauthorized)
+		}
+
+		account, err := authn(ctx.UserContext(), token)
+		if err != nil || len(account.Roles) == 0 {
+			return ctx.SendStatus(fiber.StatusUnauthorized)
+		}
+
+		ctx.Locals(LocalsAdminAccountKey, account)
+		return ctx.Next()
+	}
+}
+
+// RequireRoles only allows the request to proceed if the authenticated admin account has any of the given roles.
+// It must be used after AdminAuthentication.
+func RequireRoles(roles ...string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		account, ok := ctx.Locals(LocalsAdminAccountKey).(*model.Account)
+		if !ok {
+			return ctx.SendStatus(fiber.StatusUnauthorized)
+		}
+
+		if !account.HasAnyRole(roles...) {
+			flog.WarnFrom(ctx, "admin.authz.denied").
+				Int("accountId", account.AccountID).
+				Strs("requiredRoles", roles).
+				Strs("roles", account.Roles).
+				Msg("admin account lacks required role")
+			return ctx.SendStatus(fiber.StatusForbidden)
+		}
+
+		return ctx.Next()
+	}
+}
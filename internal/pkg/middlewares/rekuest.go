@@ -11,7 +11,7 @@ func InjectValidBody[T any]() func(*fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		var dest T
 		if err := ctx.BodyParser(&dest); err != nil {
-			return pgerr.ErrInvalidReq.Msg("invalid request: %s", err)
+			return rekuest.Violation("", rekuest.ViolationMalformed, "invalid body: "+err.Error())
 		}
 
 		if err := rekuest.ValidateStruct(ctx, dest); err != nil {
@@ -16,3 +16,9 @@ func RequestID() fiber.Handler {
 		return c.Next()
 	}
 }
+
+// RequestIDFrom returns the request ID injected by RequestID
+func RequestIDFrom(ctx *fiber.Ctx) string {
+	id, _ := ctx.Locals(constant.ContextKeyRequestID).(string)
+	return id
+}
@@ -0,0 +1,85 @@
+package middlewares
+
+import (
+	"bufio"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LocalsStreamKey is the key of ctx.Locals that holds the *stream of a response streamed by SetBodyStreamWriter
+const LocalsStreamKey = "stream"
+
+// stream counts the bytes of a streamed response body, which is written after the middlewares have returned, and
+// notifies those accounting the size of the responses once it has been written
+type stream struct {
+	mu       sync.Mutex
+	written  int
+	done     bool
+	onceDone []func(written int)
+}
+
+func (s *stream) finish(written int) {
+	s.mu.Lock()
+	s.written, s.done = written, true
+	callbacks := s.onceDone
+	s.onceDone = nil
+	s.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(written)
+	}
+}
+
+// countingWriter counts the bytes written through it, flushing them to the underlying writer right away so that the
+// flushes of the streams, e.g. of the server-sent events, still reach the client
+type countingWriter struct {
+	w       *bufio.Writer
+	written int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.written += n
+	if err != nil {
+		return n, err
+	}
+	return n, c.w.Flush()
+}
+
+// SetBodyStreamWriter streams the response body written by write in place of ctx.Context().SetBodyStreamWriter,
+// counting the bytes written. The middlewares must not read a streamed body by ctx.Response().Body() after the
+// handler, as it reads the whole stream into memory, but take its size by OnStreamed instead.
+func SetBodyStreamWriter(ctx *fiber.Ctx, write func(w *bufio.Writer)) {
+	s := &stream{}
+	ctx.Locals(LocalsStreamKey, s)
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		counter := &countingWriter{w: w}
+		bw := bufio.NewWriter(counter)
+		defer func() {
+			_ = bw.Flush()
+			s.finish(counter.written)
+		}()
+		write(bw)
+	})
+}
+
+// OnStreamed runs fn with the size of the streamed response body once it has been written, or right away with 0 if
+// the body has not been streamed by SetBodyStreamWriter. fn must not use ctx, which is released by then.
+func OnStreamed(ctx *fiber.Ctx, fn func(written int)) {
+	s, ok := ctx.Locals(LocalsStreamKey).(*stream)
+	if !ok {
+		fn(0)
+		return
+	}
+
+	s.mu.Lock()
+	if !s.done {
+		s.onceDone = append(s.onceDone, fn)
+		s.mu.Unlock()
+		return
+	}
+	written := s.written
+	s.mu.Unlock()
+	fn(written)
+}
@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"bufio"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestStreamedThroughLogger(t *testing.T) {
+	body := strings.Repeat(`{"stageId":"main_01-07","itemId":"30012","times":100,"quantity":50}`, 1000)
+
+	app := fiber.New()
+	streamed := make(chan bool, 1)
+	written := make(chan int, 1)
+	app.Use(func(ctx *fiber.Ctx) error {
+		err := ctx.Next()
+		// the middlewares within must have left the body to be streamed rather than read it as a whole
+		streamed <- ctx.Response().IsBodyStream()
+		OnStreamed(ctx, func(n int) { written <- n })
+		return err
+	})
+	Logger(app)
+	app.Get("/", func(ctx *fiber.Ctx) error {
+		SetBodyStreamWriter(ctx, func(w *bufio.Writer) {
+			_, _ = w.WriteString(body)
+		})
+		return nil
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != body {
+		t.Errorf("body of %d bytes, want %d bytes", len(got), len(body))
+	}
+	if !<-streamed {
+		t.Errorf("response is no longer a body stream after the middleware chain")
+	}
+	select {
+	case n := <-written:
+		if n != len(body) {
+			t.Errorf("streamed %d bytes, want %d", n, len(body))
+		}
+	case <-time.After(time.Second):
+		t.Errorf("size of the streamed body never reported")
+	}
+}
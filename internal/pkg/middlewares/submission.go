@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+
+	"exusiai.dev/backend-next/internal/pkg/observability"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/util"
+)
+
+// HeaderChallenge is set on the responses to the flagged submissions, telling the clients to present a challenge,
+// e.g. a CAPTCHA, should they keep on submitting
+const HeaderChallenge = "X-Penguin-Challenge"
+
+// HeaderChallengeSolution carries the solution to a submission challenge, as `<nonce>:<counter>`
+const HeaderChallengeSolution = "X-Penguin-Challenge-Solution"
+
+// SubmissionPolicy holds the submissions from the IPs flag returns a reason for to flaggedMax submissions per 5
+// minutes per IP, and flags their responses with HeaderChallenge. The other submissions are left as they are.
+func SubmissionPolicy(flag func(ip string) string, flaggedMax func() int) fiber.Handler {
+	flaggedLimiter := TunableLimiter(limiter.Config{
+		KeyGenerator: util.ExtractIP,
+		LimitReached: func(c *fiber.Ctx) error {
+			return pgerr.ErrTooManyRequests.Msg("your network is submitting reports too frequently; please try again later")
+		},
+		Expiration: time.Minute * 5,
+	}, flaggedMax)
+
+	return func(ctx *fiber.Ctx) error {
+		reason := flag(util.ExtractIP(ctx))
+		if reason == "" {
+			return ctx.Next()
+		}
+		observability.ReportSubmissionsFlagged.WithLabelValues(reason).Inc()
+		ctx.Set(HeaderChallenge, "required")
+		return flaggedLimiter(ctx)
+	}
+}
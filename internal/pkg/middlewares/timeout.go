@@ -0,0 +1,58 @@
+package middlewares
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"exusiai.dev/backend-next/internal/pkg/breaker"
+)
+
+// HeaderDegraded is set on the responses served while the circuit around the database is open
+const HeaderDegraded = "X-Penguin-Degraded"
+
+// QueryTimeout bounds the user context of the requests, which the database queries of their handlers run with, by
+// the timeout. A slow query is then cancelled once the timeout has passed rather than holding a connection for as
+// long as it takes. A zero timeout leaves the context as is.
+func QueryTimeout(timeout time.Duration) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if timeout <= 0 {
+			return ctx.Next()
+		}
+		userCtx, cancel := context.WithTimeout(ctx.UserContext(), timeout)
+		defer cancel()
+		ctx.SetUserContext(userCtx)
+		return ctx.Next()
+	}
+}
+
+// RouteTimeout bounds the user context of the requests as QueryTimeout does, by the timeout of their class: GET and
+// HEAD requests are given read, and the others write. While the circuit of dbBreaker is open, every request is given
+// degraded instead, so that the ones served from the caches still are while the ones waiting on the database give up
+// early, and are told when to retry.
+func RouteTimeout(read, write, degraded time.Duration, dbBreaker *breaker.Breaker) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		timeout := write
+		if ctx.Method() == fiber.MethodGet || ctx.Method() == fiber.MethodHead {
+			timeout = read
+		}
+		open, closesAt := dbBreaker.Open()
+		if open {
+			timeout = degraded
+			ctx.Set(HeaderDegraded, "1")
+		}
+
+		err := QueryTimeout(timeout)(ctx)
+		if err != nil && open {
+			retryAfter := int(math.Ceil(time.Until(closesAt).Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+		}
+		return err
+	}
+}
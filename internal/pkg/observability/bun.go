@@ -0,0 +1,28 @@
+package observability
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+// QueryHook observes the duration of every query executed with bun, by the operation of the query
+type QueryHook struct{}
+
+var _ bun.QueryHook = (*QueryHook)(nil)
+
+func (h *QueryHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *QueryHook) AfterQuery(_ context.Context, event *bun.QueryEvent) {
+	err := event.Err
+	if errors.Is(err, sql.ErrNoRows) {
+		// no rows is an expected outcome rather than a failure of the query
+		err = nil
+	}
+	DBQueryDuration.WithLabelValues(event.Operation(), Status(err)).Observe(time.Since(event.StartTime).Seconds())
+}
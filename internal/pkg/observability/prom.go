@@ -1,6 +1,8 @@
 package observability
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -33,4 +35,62 @@ var (
 		Name: prometheus.BuildFQName(ServiceName, "worker", "calc_duration_seconds"),
 		Help: "Duration of last worker calculation in seconds",
 	}, []string{"service", "server"})
+	JobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    prometheus.BuildFQName(ServiceName, "job", "duration_seconds"),
+		Help:    "Duration of background jobs, e.g. result refreshes and archiving, in seconds",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"job", "server", "status"})
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    prometheus.BuildFQName(ServiceName, "db", "query_duration_seconds"),
+		Help:    "Duration of database queries in seconds",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 15),
+	}, []string{"operation", "status"})
+	DBCircuitOpens = promauto.NewCounter(prometheus.CounterOpts{
+		Name: prometheus.BuildFQName(ServiceName, "db", "circuit_opens_total"),
+		Help: "Times the circuit around the database has opened upon its latency spiking",
+	})
+	DBCircuitOpen = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: prometheus.BuildFQName(ServiceName, "db", "circuit_open"),
+		Help: "Whether the circuit around the database is open, 1 if so and 0 otherwise",
+	})
+	ReportSubmissionsFlagged = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: prometheus.BuildFQName(ServiceName, "report", "submissions_flagged_total"),
+		Help: "Report submissions flagged by the submission policy by the reason they are flagged for",
+	}, []string{"reason"})
+	OutlierReportersExcluded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: prometheus.BuildFQName(ServiceName, "report", "outlier_reporters_excluded_total"),
+		Help: "Reporters excluded from the aggregations of a stage for their drop rates diverging from the other reporters",
+	}, []string{"server"})
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: prometheus.BuildFQName(ServiceName, "cache", "requests_total"),
+		Help: "Requests to the in-memory caches by result, of which the hit ratio of each cache is derived",
+	}, []string{"cache", "result"})
+	Leader = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: prometheus.BuildFQName(ServiceName, "jobs", "leader"),
+		Help: "Whether the instance is the leader running the singleton jobs, 1 if so and 0 otherwise",
+	})
+	FeatureFlagEvaluations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: prometheus.BuildFQName(ServiceName, "feature_flag", "evaluations_total"),
+		Help: "Evaluations of the feature flags by server and result, to compare the rolled out variant against the current one",
+	}, []string{"flag", "server", "result"})
 )
+
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+
+	CacheResultHit  = "hit"
+	CacheResultMiss = "miss"
+)
+
+// ObserveJob observes the duration of a background job along with whether it has succeeded
+func ObserveJob(job string, server string, start time.Time, err error) {
+	JobDuration.WithLabelValues(job, server, Status(err)).Observe(time.Since(start).Seconds())
+}
+
+func Status(err error) string {
+	if err != nil {
+		return StatusFailed
+	}
+	return StatusSucceeded
+}
@@ -0,0 +1,78 @@
+package observability
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/uptrace/bun"
+)
+
+// explainTimeout bounds the EXPLAIN of a slow query, which is planned again but never executed
+const explainTimeout = 5 * time.Second
+
+// SlowQueryHook logs the queries executed with bun which take longer than Threshold, along with their bound
+// parameters, so that the aggregations regressing after a schema change can be told apart in the logs.
+//
+// With Explain, the plan of a slow SELECT is captured as well by running EXPLAIN (FORMAT JSON) on it in the
+// background. The plan is that of the planner alone: the actual timings of a query are only known to the auto_explain
+// module of the server, which logs them on its own side when enabled with auto_explain.log_min_duration.
+type SlowQueryHook struct {
+	Threshold time.Duration
+	Explain   bool
+
+	// explaining keeps a single EXPLAIN in flight, not to pile more load onto a database already slow to answer
+	explaining atomic.Bool
+}
+
+var _ bun.QueryHook = (*SlowQueryHook)(nil)
+
+func (h *SlowQueryHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *SlowQueryHook) AfterQuery(_ context.Context, event *bun.QueryEvent) {
+	duration := time.Since(event.StartTime)
+	if duration < h.Threshold {
+		return
+	}
+
+	log.Warn().
+		Str("evt.name", "infra.postgres.slow_query").
+		Str("operation", event.Operation()).
+		Dur("duration", duration).
+		Str("query", event.Query).
+		Err(event.Err).
+		Msg("slow query")
+
+	if h.Explain && event.DB != nil && event.Operation() == "SELECT" && h.explaining.CompareAndSwap(false, true) {
+		go func() {
+			defer h.explaining.Store(false)
+			explain(event.DB, event.Query, duration)
+		}()
+	}
+}
+
+func explain(db *bun.DB, query string, duration time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+	defer cancel()
+
+	// the query has its parameters bound already, and is run on the underlying sql.DB for bun neither to take the
+	// question marks within it for placeholders nor to run the hooks on the EXPLAIN itself
+	var plan string
+	if err := db.DB.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+query).Scan(&plan); err != nil {
+		log.Warn().
+			Str("evt.name", "infra.postgres.slow_query.explain").
+			Err(err).
+			Str("query", query).
+			Msg("failed to explain slow query")
+		return
+	}
+	log.Warn().
+		Str("evt.name", "infra.postgres.slow_query.explain").
+		Dur("duration", duration).
+		Str("query", query).
+		RawJSON("plan", []byte(plan)).
+		Msg("plan of slow query")
+}
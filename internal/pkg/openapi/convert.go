@@ -0,0 +1,242 @@
+// Package openapi converts the Swagger 2.0 documents generated by swaggo/swag into OpenAPI 3 documents.
+//
+// swag only emits Swagger 2.0; the conversion covers the subset of the specification swag actually
+// produces: path, query and body parameters, JSON responses, definitions and API key security schemes.
+package openapi
+
+import (
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	swaggerDefinitionsRefPrefix = "#/definitions/"
+	openapiSchemasRefPrefix     = "#/components/schemas/"
+)
+
+type ConvertOptions struct {
+	// PathPrefix limits the converted paths to those starting with it. Empty converts every path.
+	PathPrefix string
+	// MediaType replaces application/json in the request and response content types, if set
+	MediaType string
+	// Description replaces the description of the API, if set
+	Description string
+}
+
+// Convert converts a Swagger 2.0 document to an OpenAPI 3.0 document. Only the schemas
+// reachable from the converted paths are included.
+func Convert(swaggerDoc []byte, opts ConvertOptions) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(swaggerDoc, &doc); err != nil {
+		return nil, err
+	}
+
+	info, _ := doc["info"].(map[string]any)
+	if info != nil && opts.Description != "" {
+		info["description"] = opts.Description
+	}
+
+	out := map[string]any{
+		"openapi": "3.0.3",
+		"info":    info,
+		"servers": convertServers(doc),
+	}
+
+	paths := map[string]any{}
+	srcPaths, _ := doc["paths"].(map[string]any)
+	for path, item := range srcPaths {
+		if !strings.HasPrefix(path, opts.PathPrefix) {
+			continue
+		}
+		ops, _ := item.(map[string]any)
+		convertedOps := map[string]any{}
+		for method, op := range ops {
+			if operation, ok := op.(map[string]any); ok {
+				convertedOps[method] = convertOperation(doc, operation, opts)
+			}
+		}
+		paths[path] = convertedOps
+	}
+	rewriteRefs(paths)
+	out["paths"] = paths
+
+	components := map[string]any{}
+	definitions, _ := doc["definitions"].(map[string]any)
+	schemas := map[string]any{}
+	collectSchemas(paths, definitions, schemas)
+	rewriteRefs(schemas)
+	components["schemas"] = schemas
+	if securityDefinitions, ok := doc["securityDefinitions"].(map[string]any); ok {
+		components["securitySchemes"] = securityDefinitions
+	}
+	out["components"] = components
+
+	return json.Marshal(out)
+}
+
+func convertServers(doc map[string]any) []any {
+	host, _ := doc["host"].(string)
+	if host == "" {
+		return []any{map[string]any{"url": "/"}}
+	}
+	basePath, _ := doc["basePath"].(string)
+	basePath = strings.TrimSuffix(basePath, "/")
+
+	schemes, _ := doc["schemes"].([]any)
+	if len(schemes) == 0 {
+		schemes = []any{"https"}
+	}
+	servers := make([]any, 0, len(schemes))
+	for _, scheme := range schemes {
+		servers = append(servers, map[string]any{"url": scheme.(string) + "://" + host + basePath})
+	}
+	return servers
+}
+
+func convertOperation(doc map[string]any, op map[string]any, opts ConvertOptions) map[string]any {
+	consumes := mediaTypes(op["consumes"], doc["consumes"], opts)
+	produces := mediaTypes(op["produces"], doc["produces"], opts)
+
+	converted := map[string]any{}
+	for k, v := range op {
+		switch k {
+		case "consumes", "produces", "parameters", "responses":
+		default:
+			converted[k] = v
+		}
+	}
+
+	params, _ := op["parameters"].([]any)
+	convertedParams := make([]any, 0, len(params))
+	for _, p := range params {
+		param, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		if param["in"] == "body" {
+			content := map[string]any{}
+			for _, mediaType := range consumes {
+				content[mediaType] = map[string]any{"schema": param["schema"]}
+			}
+			requestBody := map[string]any{
+				"content":  content,
+				"required": param["required"] == true,
+			}
+			if description, ok := param["description"]; ok {
+				requestBody["description"] = description
+			}
+			converted["requestBody"] = requestBody
+			continue
+		}
+		convertedParams = append(convertedParams, convertParameter(param))
+	}
+	if len(convertedParams) > 0 {
+		converted["parameters"] = convertedParams
+	}
+
+	responses, _ := op["responses"].(map[string]any)
+	convertedResponses := map[string]any{}
+	for code, r := range responses {
+		response, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		convertedResponse := map[string]any{"description": response["description"]}
+		if schema, ok := response["schema"].(map[string]any); ok {
+			if schema["type"] == "file" {
+				schema = map[string]any{"type": "string", "format": "binary"}
+			}
+			content := map[string]any{}
+			for _, mediaType := range produces {
+				content[mediaType] = map[string]any{"schema": schema}
+			}
+			convertedResponse["content"] = content
+		}
+		if headers, ok := response["headers"]; ok {
+			convertedResponse["headers"] = headers
+		}
+		convertedResponses[code] = convertedResponse
+	}
+	converted["responses"] = convertedResponses
+
+	return converted
+}
+
+// convertParameter moves the type related fields of a non-body parameter into its schema
+func convertParameter(param map[string]any) map[string]any {
+	converted := map[string]any{}
+	schema := map[string]any{}
+	for k, v := range param {
+		switch k {
+		case "name", "in", "description", "required":
+			converted[k] = v
+		case "collectionFormat":
+		default:
+			schema[k] = v
+		}
+	}
+	converted["schema"] = schema
+	return converted
+}
+
+func mediaTypes(opLevel any, docLevel any, opts ConvertOptions) []string {
+	raw, _ := opLevel.([]any)
+	if len(raw) == 0 {
+		raw, _ = docLevel.([]any)
+	}
+	types := make([]string, 0, len(raw))
+	for _, t := range raw {
+		mediaType, _ := t.(string)
+		if mediaType == "application/json" && opts.MediaType != "" {
+			mediaType = opts.MediaType
+		}
+		types = append(types, mediaType)
+	}
+	if len(types) == 0 {
+		types = append(types, "application/json")
+	}
+	return types
+}
+
+// collectSchemas copies the definitions referenced from v, transitively, into schemas
+func collectSchemas(v any, definitions map[string]any, schemas map[string]any) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			if ref, ok := child.(string); ok && k == "$ref" {
+				name := strings.TrimPrefix(strings.TrimPrefix(ref, swaggerDefinitionsRefPrefix), openapiSchemasRefPrefix)
+				if _, seen := schemas[name]; seen {
+					continue
+				}
+				if definition, ok := definitions[name]; ok {
+					schemas[name] = definition
+					collectSchemas(definition, definitions, schemas)
+				}
+				continue
+			}
+			collectSchemas(child, definitions, schemas)
+		}
+	case []any:
+		for _, child := range t {
+			collectSchemas(child, definitions, schemas)
+		}
+	}
+}
+
+func rewriteRefs(v any) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			if ref, ok := child.(string); ok && k == "$ref" {
+				t[k] = openapiSchemasRefPrefix + strings.TrimPrefix(ref, swaggerDefinitionsRefPrefix)
+				continue
+			}
+			rewriteRefs(child)
+		}
+	case []any:
+		for _, child := range t {
+			rewriteRefs(child)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+const swaggerDoc = `{
+	"swagger": "2.0",
+	"info": {"title": "Test", "version": "1.0.0"},
+	"host": "example.com",
+	"basePath": "/",
+	"schemes": ["https"],
+	"paths": {
+		"/v2/items": {"get": {"responses": {"200": {"description": "OK"}}}},
+		"/v3/items/{itemId}": {
+			"post": {
+				"consumes": ["application/json"],
+				"produces": ["application/json"],
+				"parameters": [
+					{"type": "string", "name": "itemId", "in": "path", "required": true},
+					{"name": "item", "in": "body", "required": true, "schema": {"$ref": "#/definitions/model.Item"}}
+				],
+				"responses": {"200": {"description": "OK", "schema": {"$ref": "#/definitions/model.Item"}}}
+			}
+		}
+	},
+	"definitions": {
+		"model.Item": {"type": "object", "properties": {"group": {"$ref": "#/definitions/model.Group"}}},
+		"model.Group": {"type": "object"},
+		"model.Unused": {"type": "object"}
+	}
+}`
+
+func TestConvert(t *testing.T) {
+	b, err := Convert([]byte(swaggerDoc), ConvertOptions{PathPrefix: "/v3", MediaType: "application/vnd.test+json"})
+	if err != nil {
+		t.Fatalf("Convert returned error: %v", err)
+	}
+
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths      map[string]map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("failed to decode converted document: %v", err)
+	}
+
+	if doc.OpenAPI != "3.0.3" {
+		t.Errorf("expected openapi 3.0.3, got %q", doc.OpenAPI)
+	}
+	if len(doc.Servers) != 1 || doc.Servers[0].URL != "https://example.com" {
+		t.Errorf("expected a single server https://example.com, got %+v", doc.Servers)
+	}
+	if _, ok := doc.Paths["/v2/items"]; ok {
+		t.Errorf("expected paths outside of the prefix to be dropped")
+	}
+
+	op := doc.Paths["/v3/items/{itemId}"]["post"]
+	if op == nil {
+		t.Fatalf("expected the prefixed path to be converted")
+	}
+	params, _ := op["parameters"].([]any)
+	if len(params) != 1 {
+		t.Fatalf("expected the body parameter to be moved into requestBody, got parameters %v", params)
+	}
+	if _, ok := params[0].(map[string]any)["schema"]; !ok {
+		t.Errorf("expected the path parameter type to be moved into its schema")
+	}
+	requestBody, _ := op["requestBody"].(map[string]any)
+	content, _ := requestBody["content"].(map[string]any)
+	media, _ := content["application/vnd.test+json"].(map[string]any)
+	schema, _ := media["schema"].(map[string]any)
+	if schema["$ref"] != "#/components/schemas/model.Item" {
+		t.Errorf("expected the request body schema to reference components, got %v", schema["$ref"])
+	}
+
+	if len(doc.Components.Schemas) != 2 {
+		t.Errorf("expected only the reachable schemas, got %v", doc.Components.Schemas)
+	}
+	if _, ok := doc.Components.Schemas["model.Group"]; !ok {
+		t.Errorf("expected transitively referenced schemas to be included")
+	}
+}
@@ -10,6 +10,14 @@ const (
 	CodeNotFound       = "NOT_FOUND"
 	CodeInvalidRequest = "INVALID_REQUEST"
 	CodeInternalError  = "INTERNAL_ERROR"
+
+	CodeTooManyRequests   = "TOO_MANY_REQUESTS"
+	CodeInvalidReport     = "INVALID_REPORT"
+	CodeTimeout           = "TIMEOUT"
+	CodeUpgradeRequired   = "UPGRADE_REQUIRED"
+	CodeQueryTooCostly    = "QUERY_TOO_COSTLY"
+	CodeMaintenance       = "MAINTENANCE"
+	CodeChallengeRequired = "CHALLENGE_REQUIRED"
 )
 
 var (
@@ -22,6 +30,21 @@ var (
 	// ErrInternalError is returned when an internal error occurs.
 	ErrInternalError = New(fiber.StatusInternalServerError, CodeInternalError, "internal server error occurred")
 
+	// ErrTooManyRequests is returned when the client has exhausted its quota.
+	ErrTooManyRequests = New(fiber.StatusTooManyRequests, CodeTooManyRequests, "too many requests")
+
+	// ErrTimeout is returned when the request has not completed within its time limit, e.g. a slow query.
+	ErrTimeout = New(fiber.StatusServiceUnavailable, CodeTimeout, "the request took too long to complete; please try again later")
+
+	// ErrUpgradeRequired is returned when the version of the client is no longer accepted.
+	ErrUpgradeRequired = New(fiber.StatusUpgradeRequired, CodeUpgradeRequired, "this version of the client is no longer supported; please upgrade")
+
+	// ErrMaintenance is returned when a write is requested while the API is under maintenance.
+	ErrMaintenance = New(fiber.StatusServiceUnavailable, CodeMaintenance, "the service is under maintenance and does not accept changes for now; please try again later")
+
+	// ErrChallengeRequired is returned when a submission has to come with the solution to a challenge.
+	ErrChallengeRequired = New(fiber.StatusPreconditionRequired, CodeChallengeRequired, "a solved challenge is required to submit reports from this account; please request a challenge and try again")
+
 	ErrInternalErrorImmutable = NewImmutable(fiber.StatusInternalServerError, CodeInternalError, "internal server error occurred")
 )
 
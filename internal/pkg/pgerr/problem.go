@@ -0,0 +1,85 @@
+package pgerr
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MIMEApplicationProblemJSON is the media type of the problem details of RFC 7807 the errors are responded with
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// ProblemTypePrefix prefixes the type of the problem details, which is followed by the error code in kebab case,
+// e.g. urn:penguin-stats:problem:invalid-request
+const ProblemTypePrefix = "urn:penguin-stats:problem:"
+
+// titles are the summaries of the problems of the error codes, which unlike the messages stay the same across the
+// occurrences of the problems as RFC 7807 requires
+var titles = map[string]string{
+	CodeNotFound:          "Resource Not Found",
+	CodeInvalidRequest:    "Invalid Request",
+	CodeInternalError:     "Internal Server Error",
+	CodeTooManyRequests:   "Too Many Requests",
+	CodeInvalidReport:     "Invalid Report",
+	CodeTimeout:           "Request Timed Out",
+	CodeUpgradeRequired:   "Upgrade Required",
+	CodeQueryTooCostly:    "Query Too Costly",
+	CodeMaintenance:       "Under Maintenance",
+	CodeChallengeRequired: "Challenge Required",
+}
+
+// Problem is the problem details of RFC 7807 of a PenguinError. Code and Message are kept beside Type and Detail as
+// extension members for the clients predating the problem details.
+type Problem struct {
+	Type     string `json:"type" example:"urn:penguin-stats:problem:invalid-request"`
+	Title    string `json:"title" example:"Invalid Request"`
+	Status   int    `json:"status" example:"400"`
+	Detail   string `json:"detail" example:"invalid request: some or all request parameters are invalid"`
+	Instance string `json:"instance,omitempty" example:"/api/v3alpha/items/search"`
+	// ErrorID identifies the occurrence of the problem, which is the request ID the logs of the request carry
+	ErrorID string `json:"errorId,omitempty" example:"cn0pqe2s1f2g1bq6cm0g"`
+
+	Code    string `json:"code" example:"INVALID_REQUEST"`
+	Message string `json:"message" example:"invalid request: some or all request parameters are invalid"`
+}
+
+// ProblemType is the type of the problem details of the error code
+func ProblemType(code string) string {
+	return ProblemTypePrefix + strings.ReplaceAll(strings.ToLower(code), "_", "-")
+}
+
+// Problem describes e as the problem details of its occurrence at instance, identified by errorId. The extras of e
+// are left to the caller to be merged into the problem details as further extension members.
+func (e *PenguinError) Problem(instance, errorId string) *Problem {
+	title, ok := titles[e.ErrorCode]
+	if !ok {
+		title = http.StatusText(e.StatusCode)
+	}
+	return &Problem{
+		Type:     ProblemType(e.ErrorCode),
+		Title:    title,
+		Status:   e.StatusCode,
+		Detail:   e.Message,
+		Instance: instance,
+		ErrorID:  errorId,
+		Code:     e.ErrorCode,
+		Message:  e.Message,
+	}
+}
+
+// FromStatus maps an error of fiber, e.g. a route not found or a body too large, to the PenguinError of its status
+func FromStatus(statusCode int, message string) *PenguinError {
+	switch {
+	case statusCode == fiber.StatusNotFound:
+		return New(statusCode, CodeNotFound, message)
+	case statusCode == fiber.StatusTooManyRequests:
+		return New(statusCode, CodeTooManyRequests, message)
+	case statusCode == fiber.StatusRequestTimeout:
+		return New(statusCode, CodeTimeout, message)
+	case statusCode >= fiber.StatusInternalServerError:
+		return New(statusCode, CodeInternalError, message)
+	default:
+		return New(statusCode, CodeInvalidRequest, message)
+	}
+}
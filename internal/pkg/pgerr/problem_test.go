@@ -0,0 +1,43 @@
+package pgerr
+
+import "testing"
+
+func TestProblem(t *testing.T) {
+	p := ErrInvalidReq.Problem("/api/v3alpha/items/search", "cn0pqe2s1f2g1bq6cm0g")
+	if p.Type != "urn:penguin-stats:problem:invalid-request" {
+		t.Errorf("Type = %q, want urn:penguin-stats:problem:invalid-request", p.Type)
+	}
+	if p.Title != "Invalid Request" {
+		t.Errorf("Title = %q, want Invalid Request", p.Title)
+	}
+	if p.Status != 400 || p.Detail != ErrInvalidReq.Message || p.Code != CodeInvalidRequest || p.Message != ErrInvalidReq.Message {
+		t.Errorf("Problem = %+v, which does not describe %+v", p, ErrInvalidReq)
+	}
+	if p.Instance != "/api/v3alpha/items/search" || p.ErrorID != "cn0pqe2s1f2g1bq6cm0g" {
+		t.Errorf("Problem = %+v, which does not identify its occurrence", p)
+	}
+
+	// the codes without their own titles are titled by their status
+	if title := New(418, "TEAPOT", "short and stout").Problem("", "").Title; title != "I'm a teapot" {
+		t.Errorf("Title = %q, want I'm a teapot", title)
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		code   string
+	}{
+		{status: 404, code: CodeNotFound},
+		{status: 405, code: CodeInvalidRequest},
+		{status: 413, code: CodeInvalidRequest},
+		{status: 429, code: CodeTooManyRequests},
+		{status: 503, code: CodeInternalError},
+	}
+	for _, tt := range tests {
+		e := FromStatus(tt.status, "message")
+		if e.StatusCode != tt.status || e.ErrorCode != tt.code {
+			t.Errorf("FromStatus(%d) = %d %s, want %d %s", tt.status, e.StatusCode, e.ErrorCode, tt.status, tt.code)
+		}
+	}
+}
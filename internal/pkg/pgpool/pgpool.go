@@ -0,0 +1,38 @@
+// Package pgpool keeps the connections of the background jobs apart from those serving the requests, so that a refresh
+// or an archive run holding its connections for long cannot exhaust the pool the requests are waiting on.
+package pgpool
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+type backgroundKey struct{}
+
+// WithBackground marks the queries run with ctx as those of a background job
+func WithBackground(ctx context.Context) context.Context {
+	return context.WithValue(ctx, backgroundKey{}, true)
+}
+
+// IsBackground tells whether ctx has been marked by WithBackground
+func IsBackground(ctx context.Context) bool {
+	v, _ := ctx.Value(backgroundKey{}).(bool)
+	return v
+}
+
+// Pools are the databases of the workloads, each with its own pool of connections
+type Pools struct {
+	// Interactive serves the requests to the API, and is the *bun.DB provided to everything else
+	Interactive *bun.DB
+	// Background serves the background jobs. It is Interactive itself when no separate pool is configured.
+	Background *bun.DB
+}
+
+// DB returns the database of the workload ctx belongs to
+func (p *Pools) DB(ctx context.Context) *bun.DB {
+	if IsBackground(ctx) {
+		return p.Background
+	}
+	return p.Interactive
+}
@@ -0,0 +1,22 @@
+package pgpool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+func TestPoolsDB(t *testing.T) {
+	pools := &Pools{Interactive: &bun.DB{}, Background: &bun.DB{}}
+
+	ctx := context.Background()
+	if pools.DB(ctx) != pools.Interactive {
+		t.Error("expected the interactive pool for an unmarked context")
+	}
+	ctx, cancel := context.WithCancel(WithBackground(ctx))
+	defer cancel()
+	if pools.DB(ctx) != pools.Background {
+		t.Error("expected the background pool for a context derived from a background one")
+	}
+}
@@ -1,6 +1,8 @@
 package pgqry
 
 import (
+	"time"
+
 	"github.com/uptrace/bun"
 )
 
@@ -51,3 +53,21 @@ func (pq *pq) DoFilterCurrentTimeRange() *pq {
 	pq.Q = pq.Q.Where("tr.start_time <= NOW() AND tr.end_time > NOW()")
 	return pq
 }
+
+func (pq *pq) DoFilterTimeRangeAt(t time.Time) *pq {
+	pq.Q = pq.Q.Where("tr.start_time <= ? AND tr.end_time > ?", t, t)
+	return pq
+}
+
+// DoFilterLatestDropInfoVersion keeps only the drop infos of the time range of the latest start time among those of
+// the same server and stage including t, i.e. the version of the drop table in effect at t
+func (pq *pq) DoFilterLatestDropInfoVersion(alias string, t time.Time) *pq {
+	pq.Q = pq.Q.Where(alias+`.range_id = (
+		SELECT vdi.range_id FROM drop_infos AS vdi
+		JOIN time_ranges AS vtr ON vtr.range_id = vdi.range_id
+		WHERE vdi.server = `+alias+`.server AND vdi.stage_id = `+alias+`.stage_id AND vtr.start_time <= ? AND vtr.end_time > ?
+		ORDER BY vtr.start_time DESC, vtr.range_id DESC
+		LIMIT 1
+	)`, t, t)
+	return pq
+}
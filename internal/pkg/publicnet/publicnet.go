@@ -0,0 +1,71 @@
+// Package publicnet restricts the outgoing connections made on behalf of the users, e.g. the webhook deliveries, to
+// the public internet, so that they cannot reach the hosts of the internal network.
+package publicnet
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNonPublicAddress is returned for the addresses that are not routable on the public internet
+var ErrNonPublicAddress = errors.New("address is not public")
+
+// nonPublicPrefixes are the special-purpose ranges not covered by the netip.Addr predicates
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, including the broadcast address
+	netip.MustParsePrefix("64:ff9b::/96"),    // IPv4/IPv6 translation, which may map to the internal addresses
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+}
+
+// IsPublic reports whether the address is routable on the public internet, i.e. it is none of the loopback, private,
+// link-local, multicast or other special-purpose addresses
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckHost resolves the host and rejects it unless every address it resolves to is public
+func CheckHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve %s", host)
+	}
+	for _, addr := range addrs {
+		if !IsPublic(addr) {
+			return errors.Wrapf(ErrNonPublicAddress, "%s resolves to %s", host, addr)
+		}
+	}
+	return nil
+}
+
+// Control is a net.Dialer Control hook refusing to connect to the non-public addresses. As it checks the address
+// actually connected to, the hosts resolving to other addresses since they have been checked are refused as well.
+func Control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return errors.Wrapf(err, "invalid address %s", address)
+	}
+	if !IsPublic(addrPort.Addr()) {
+		return errors.Wrapf(ErrNonPublicAddress, "refused to connect to %s", address)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package publicnet
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"1.1.1.1", true},
+		{"93.184.216.34", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"10.0.0.1", false},
+		{"172.16.5.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"255.255.255.255", false},
+		{"224.0.0.1", false},
+		{"::1", false},
+		{"::", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublic(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("IsPublic(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestControl(t *testing.T) {
+	if err := Control("tcp4", "1.1.1.1:443", nil); err != nil {
+		t.Errorf("Control refused a public address: %v", err)
+	}
+	for _, address := range []string{"127.0.0.1:443", "[::1]:443", "10.96.0.1:443", "169.254.169.254:80"} {
+		if err := Control("tcp", address, nil); !errors.Is(err, ErrNonPublicAddress) {
+			t.Errorf("Control(%s) = %v, want ErrNonPublicAddress", address, err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Package stattest implements the statistical tests the reporters are screened with.
+package stattest
+
+import "math"
+
+const (
+	gammaMaxIterations = 1000
+	gammaEpsilon       = 1e-15
+	// gammaTiny stands in for the zeros of the continued fraction, which would otherwise be divided by
+	gammaTiny = 1e-300
+)
+
+// ChiSquareSurvival returns the probability of a chi-square distributed variable of df degrees of freedom being at
+// least x, i.e. the p-value of the statistic x of a chi-square test
+func ChiSquareSurvival(x float64, df int) float64 {
+	if df <= 0 {
+		return math.NaN()
+	}
+	if x <= 0 {
+		return 1
+	}
+	return upperRegularizedGamma(float64(df)/2, x/2)
+}
+
+// upperRegularizedGamma is Q(a, x) = Γ(a, x) / Γ(a), evaluated by its series below a+1 and by its continued fraction
+// above, where each converges quickly
+func upperRegularizedGamma(a, x float64) float64 {
+	lgamma, _ := math.Lgamma(a)
+	prefactor := math.Exp(-x + a*math.Log(x) - lgamma)
+
+	if x < a+1 {
+		ap, del := a, 1/a
+		sum := del
+		for i := 0; i < gammaMaxIterations; i++ {
+			ap++
+			del *= x / ap
+			sum += del
+			if math.Abs(del) < math.Abs(sum)*gammaEpsilon {
+				break
+			}
+		}
+		return 1 - sum*prefactor
+	}
+
+	b := x + 1 - a
+	c := 1 / gammaTiny
+	d := 1 / b
+	h := d
+	for i := 1; i <= gammaMaxIterations; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < gammaTiny {
+			d = gammaTiny
+		}
+		c = b + an/c
+		if math.Abs(c) < gammaTiny {
+			c = gammaTiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < gammaEpsilon {
+			break
+		}
+	}
+	return h * prefactor
+}
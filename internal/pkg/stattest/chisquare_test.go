@@ -0,0 +1,34 @@
+package stattest
+
+import (
+	"math"
+	"testing"
+)
+
+func TestChiSquareSurvival(t *testing.T) {
+	tests := []struct {
+		x    float64
+		df   int
+		want float64
+	}{
+		{x: 0, df: 3, want: 1},
+		// the critical values of the 5% and 0.1% significance levels
+		{x: 3.841459, df: 1, want: 0.05},
+		{x: 18.307038, df: 10, want: 0.05},
+		{x: 10.827566, df: 1, want: 0.001},
+		{x: 29.588298, df: 10, want: 0.001},
+		// with 2 degrees of freedom, the survival is exp(-x/2)
+		{x: 1, df: 2, want: math.Exp(-0.5)},
+		{x: 100, df: 2, want: math.Exp(-50)},
+	}
+	for _, tt := range tests {
+		got := ChiSquareSurvival(tt.x, tt.df)
+		if math.Abs(got-tt.want) > tt.want*1e-5 {
+			t.Errorf("ChiSquareSurvival(%v, %d) = %v, want %v", tt.x, tt.df, got, tt.want)
+		}
+	}
+
+	if got := ChiSquareSurvival(1, 0); !math.IsNaN(got) {
+		t.Errorf("ChiSquareSurvival(1, 0) = %v, want NaN", got)
+	}
+}
@@ -0,0 +1,152 @@
+// Package tabular renders query results as spreadsheets, in either CSV or XLSX.
+package tabular
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+
+	MediaTypeCSV    = "text/csv"
+	ContentTypeCSV  = MediaTypeCSV + "; charset=utf-8"
+	ContentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// utf8BOM is prepended to CSV outputs as Excel would otherwise decode non-ASCII names with the system codepage
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Table is a single sheet of data. Cells can be strings, integers, floats, *time.Time or time.Time;
+// a nil cell is rendered empty.
+type Table struct {
+	Name   string
+	Header []string
+	Rows   [][]any
+}
+
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return ContentTypeXLSX
+	}
+	return ContentTypeCSV
+}
+
+func Write(w io.Writer, format string, table *Table) error {
+	if format == FormatXLSX {
+		return WriteXLSX(w, table)
+	}
+	return WriteCSV(w, table)
+}
+
+func WriteCSV(w io.Writer, table *Table) error {
+	if _, err := w.Write(utf8BOM); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(table.Header); err != nil {
+		return err
+	}
+	record := make([]string, len(table.Header))
+	for _, row := range table.Rows {
+		for i, cell := range row {
+			record[i] = formatCell(cell)
+		}
+		if err := cw.Write(record[:len(row)]); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func WriteXLSX(w io.Writer, table *Table) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := table.Name
+	if sheet == "" {
+		sheet = "Sheet1"
+	}
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		return err
+	}
+
+	timeFormat := "yyyy-mm-dd hh:mm:ss"
+	timeStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &timeFormat})
+	if err != nil {
+		return err
+	}
+
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+	header := make([]any, len(table.Header))
+	for i, h := range table.Header {
+		header[i] = excelize.Cell{Value: h}
+	}
+	if err := sw.SetRow("A1", header); err != nil {
+		return err
+	}
+	for i, row := range table.Rows {
+		cells := make([]any, len(row))
+		for j, cell := range row {
+			switch t := cell.(type) {
+			case *time.Time:
+				if t != nil {
+					cells[j] = excelize.Cell{StyleID: timeStyle, Value: t.UTC()}
+				}
+			case time.Time:
+				cells[j] = excelize.Cell{StyleID: timeStyle, Value: t.UTC()}
+			default:
+				cells[j] = cell
+			}
+		}
+		axis, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		if err := sw.SetRow(axis, cells); err != nil {
+			return err
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+
+	_, err = f.WriteTo(w)
+	return err
+}
+
+func formatCell(cell any) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.UTC().Format(timeLayout)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(timeLayout)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,43 @@
+package tabular
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWriteCSV(t *testing.T) {
+	start := time.Date(2019, 5, 1, 2, 0, 0, 0, time.UTC)
+	table := &Table{
+		Header: []string{"Stage", "Times", "Rate", "Start", "End"},
+		Rows: [][]any{
+			{"1-7, \"hard\"", 10, 0.5, start, (*time.Time)(nil)},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, table); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "\xEF\xBB\xBFStage,Times,Rate,Start,End\n\"1-7, \"\"hard\"\"\",10,0.5,2019-05-01 02:00:00,\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	table := &Table{
+		Name:   "Test",
+		Header: []string{"Stage", "Start"},
+		Rows:   [][]any{{"1-7", time.Now()}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, table); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("PK")) {
+		t.Error("expected a zip archive")
+	}
+}
@@ -1,26 +1,46 @@
 package repo
 
 import (
+	"go.opentelemetry.io/otel"
 	"go.uber.org/fx"
 )
 
+// tracer traces the heavy aggregations, on top of the spans of the queries themselves
+var tracer = otel.Tracer("repo")
+
 func Module() fx.Option {
 	return fx.Module("repo", fx.Provide(
 		NewItem,
+		NewItemValue,
+		NewDropMatrixHistory,
+		NewRecognitionStageHash,
+		NewClientVersion,
+		NewShortLink,
+		NewJob,
 		NewZone,
 		NewAdmin,
 		NewStage,
 		NewNotice,
 		NewAccount,
+		NewAccountAppeal,
+		NewAPIKey,
+		NewWebhook,
 		NewActivity,
 		NewDropInfo,
 		NewProperty,
 		NewSnapshot,
 		NewTimeRange,
+		NewEvent,
+		NewEventSummary,
+		NewExclusionRule,
+		NewReportReview,
 		NewDropReport,
+		NewDropReportDailyRollup,
 		NewRejectRule,
 		NewDropPattern,
 		NewDropReportExtra,
+		NewDropReportTask,
+		NewDropReportPartition,
 		NewDropMatrixElement,
 		NewRecognitionDefect,
 		NewDropPatternElement,
@@ -7,6 +7,8 @@ import (
 
 	"github.com/rs/zerolog/log"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"gopkg.in/guregu/null.v3"
 
 	"exusiai.dev/backend-next/internal/model"
 	"exusiai.dev/backend-next/internal/model/cache"
@@ -75,6 +77,32 @@ func (r *Account) GetAccountByPenguinId(ctx context.Context, penguinId string) (
 	})
 }
 
+func (r *Account) GetAccountByAdminTokenHash(ctx context.Context, tokenHash string) (*model.Account, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("admin_token_hash = ?", tokenHash)
+	})
+}
+
+func (r *Account) UpdateAccountRoles(ctx context.Context, accountId int, roles []string, adminTokenHash string) error {
+	_, err := r.db.NewUpdate().
+		Model((*model.Account)(nil)).
+		Set("roles = ?", pgdialect.Array(roles)).
+		Set("admin_token_hash = NULLIF(?, '')", adminTokenHash).
+		Where("account_id = ?", accountId).
+		Exec(ctx)
+	return err
+}
+
+func (r *Account) UpdateAccountLeaderboardSettings(ctx context.Context, accountId int, optIn bool, name null.String) error {
+	_, err := r.db.NewUpdate().
+		Model((*model.Account)(nil)).
+		Set("leaderboard_opt_in = ?", optIn).
+		Set("leaderboard_name = ?", name).
+		Where("account_id = ?", accountId).
+		Exec(ctx)
+	return err
+}
+
 func (r *Account) IsAccountExistWithId(ctx context.Context, accountId int) bool {
 	var exist int
 	err := cache.AccountExistence.Get(strconv.Itoa(accountId), &exist)
@@ -96,3 +124,47 @@ func (r *Account) IsAccountExistWithId(ctx context.Context, accountId int) bool
 
 	return exists
 }
+
+// UpdateAccountShadowBan shadow-bans the account at the given time, or lifts its shadow ban when at is nil
+func (r *Account) UpdateAccountShadowBan(ctx context.Context, accountId int, at *time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*model.Account)(nil)).
+		Set("shadow_banned_at = ?", at).
+		Where("account_id = ?", accountId).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	cache.AccountShadowBan.Delete(strconv.Itoa(accountId))
+	return nil
+}
+
+func (r *Account) GetShadowBannedAccounts(ctx context.Context) ([]*model.Account, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("shadow_banned_at IS NOT NULL").Order("shadow_banned_at DESC")
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+func (r *Account) IsAccountShadowBanned(ctx context.Context, accountId int) (bool, error) {
+	var banned int
+	err := cache.AccountShadowBan.Get(strconv.Itoa(accountId), &banned)
+	if err == nil {
+		return banned == 1, nil
+	}
+
+	exists, err := r.db.NewSelect().
+		Model((*model.Account)(nil)).
+		Where("account_id = ?", accountId).
+		Where("shadow_banned_at IS NOT NULL").
+		Exists(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	banned = 0
+	if exists {
+		banned = 1
+	}
+	cache.AccountShadowBan.Set(strconv.Itoa(accountId), banned, time.Minute*5)
+	return exists, nil
+}
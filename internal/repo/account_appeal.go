@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo/selector"
+)
+
+type AccountAppeal struct {
+	db  *bun.DB
+	sel selector.S[model.AccountAppeal]
+}
+
+func NewAccountAppeal(db *bun.DB) *AccountAppeal {
+	return &AccountAppeal{db: db, sel: selector.New[model.AccountAppeal](db)}
+}
+
+func (r *AccountAppeal) CreateAppeal(ctx context.Context, appeal *model.AccountAppeal) error {
+	_, err := r.db.NewInsert().
+		Model(appeal).
+		Returning("appeal_id, created_at").
+		Exec(ctx)
+	return err
+}
+
+func (r *AccountAppeal) GetAppeal(ctx context.Context, appealId int) (*model.AccountAppeal, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("appeal_id = ?", appealId)
+	})
+}
+
+func (r *AccountAppeal) GetLatestAppealByAccountId(ctx context.Context, accountId int) (*model.AccountAppeal, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("account_id = ?", accountId).Order("created_at DESC").Limit(1)
+	})
+}
+
+func (r *AccountAppeal) GetAppealsByStatus(ctx context.Context, status string, limit int, page int) ([]*model.AccountAppeal, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("status = ?", status).Order("created_at ASC").Limit(limit).Offset(page * limit)
+	})
+}
+
+func (r *AccountAppeal) ResolveAppeal(ctx context.Context, appealId int, status string, resolution string) error {
+	_, err := r.db.NewUpdate().
+		Model((*model.AccountAppeal)(nil)).
+		Set("status = ?", status).
+		Set("resolution = ?", resolution).
+		Set("resolved_at = ?", time.Now()).
+		Where("appeal_id = ?", appealId).
+		Exec(ctx)
+	return err
+}
@@ -55,3 +55,101 @@ func (r *Admin) SaveDropInfos(ctx context.Context, tx bun.Tx, dropInfos *[]*mode
 		Exec(ctx)
 	return err
 }
+
+func (r *Admin) SaveItems(ctx context.Context, tx bun.Tx, items *[]*model.Item) error {
+	_, err := tx.NewInsert().
+		On("CONFLICT (ark_item_id) DO UPDATE").
+		Model(items).
+		Exec(ctx)
+	return err
+}
+
+// GetMetadata loads every item, zone, stage, time range and drop info
+func (r *Admin) GetMetadata(ctx context.Context) (*model.MetadataSnapshotContent, error) {
+	content := &model.MetadataSnapshotContent{}
+	// the deleted items and stages are kept in the snapshots, so that restoring one does not undo their deletion
+	if err := r.db.NewSelect().Model(&content.Items).WhereAllWithDeleted().Order("item_id").Scan(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.db.NewSelect().Model(&content.Zones).Order("zone_id").Scan(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.db.NewSelect().Model(&content.Stages).WhereAllWithDeleted().Order("stage_id").Scan(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.db.NewSelect().Model(&content.TimeRanges).Order("range_id").Scan(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.db.NewSelect().Model(&content.DropInfos).Order("drop_id").Scan(ctx); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// RestoreMetadata restores the metadata to the given content: rows are upserted by their primary keys,
+// and rows created afterwards are removed, except for stages, zones and items that have been referenced
+// by drop reports since.
+func (r *Admin) RestoreMetadata(ctx context.Context, tx bun.Tx, content *model.MetadataSnapshotContent) error {
+	itemIds := make([]int, 0, len(content.Items))
+	for _, item := range content.Items {
+		itemIds = append(itemIds, item.ItemID)
+	}
+	zoneIds := make([]int, 0, len(content.Zones))
+	for _, zone := range content.Zones {
+		zoneIds = append(zoneIds, zone.ZoneID)
+	}
+	stageIds := make([]int, 0, len(content.Stages))
+	for _, stage := range content.Stages {
+		stageIds = append(stageIds, stage.StageID)
+	}
+	rangeIds := make([]int, 0, len(content.TimeRanges))
+	for _, timeRange := range content.TimeRanges {
+		rangeIds = append(rangeIds, timeRange.RangeID)
+	}
+	dropIds := make([]int, 0, len(content.DropInfos))
+	for _, dropInfo := range content.DropInfos {
+		dropIds = append(dropIds, dropInfo.DropID)
+	}
+
+	// remove rows created after the snapshot, dependents first
+	deletions := []*bun.DeleteQuery{
+		tx.NewDelete().Model((*model.DropInfo)(nil)).Where("drop_id NOT IN (?)", bun.In(append(dropIds, 0))),
+		tx.NewDelete().Model((*model.TimeRange)(nil)).Where("range_id NOT IN (?)", bun.In(append(rangeIds, 0))),
+		tx.NewDelete().Model((*model.Stage)(nil)).ForceDelete().Where("stage_id NOT IN (?)", bun.In(append(stageIds, 0))).
+			Where("NOT EXISTS (SELECT 1 FROM drop_reports AS dr WHERE dr.stage_id = st.stage_id)"),
+		tx.NewDelete().Model((*model.Zone)(nil)).Where("zone_id NOT IN (?)", bun.In(append(zoneIds, 0))).
+			Where("NOT EXISTS (SELECT 1 FROM stages AS s WHERE s.zone_id = zo.zone_id)"),
+		tx.NewDelete().Model((*model.Item)(nil)).ForceDelete().Where("item_id NOT IN (?)", bun.In(append(itemIds, 0))).
+			Where("NOT EXISTS (SELECT 1 FROM drop_pattern_elements AS dpe WHERE dpe.item_id = it.item_id)"),
+	}
+	for _, q := range deletions {
+		if _, err := q.Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	upserts := []struct {
+		pk    string
+		model any
+		empty bool
+	}{
+		{"item_id", &content.Items, len(content.Items) == 0},
+		{"zone_id", &content.Zones, len(content.Zones) == 0},
+		{"stage_id", &content.Stages, len(content.Stages) == 0},
+		{"range_id", &content.TimeRanges, len(content.TimeRanges) == 0},
+		{"drop_id", &content.DropInfos, len(content.DropInfos) == 0},
+	}
+	for _, upsert := range upserts {
+		if upsert.empty {
+			continue
+		}
+		if _, err := tx.NewInsert().
+			Model(upsert.model).
+			On("CONFLICT (" + upsert.pk + ") DO UPDATE").
+			Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
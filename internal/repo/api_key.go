@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo/selector"
+)
+
+type APIKey struct {
+	db  *bun.DB
+	sel selector.S[model.APIKey]
+}
+
+func NewAPIKey(db *bun.DB) *APIKey {
+	return &APIKey{db: db, sel: selector.New[model.APIKey](db)}
+}
+
+func (r *APIKey) CreateAPIKey(ctx context.Context, apiKey *model.APIKey) error {
+	_, err := r.db.NewInsert().
+		Model(apiKey).
+		Returning("api_key_id, created_at").
+		Exec(ctx)
+	return err
+}
+
+func (r *APIKey) GetAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Order("api_key_id ASC")
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+func (r *APIKey) GetAPIKeyById(ctx context.Context, apiKeyId int) (*model.APIKey, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("api_key_id = ?", apiKeyId)
+	})
+}
+
+// GetActiveAPIKeyByHash returns the API key with the given hash, unless it has been revoked
+func (r *APIKey) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("key_hash = ?", keyHash).Where("revoked_at IS NULL")
+	})
+}
+
+func (r *APIKey) RevokeAPIKey(ctx context.Context, apiKeyId int) error {
+	_, err := r.db.NewUpdate().
+		Model((*model.APIKey)(nil)).
+		Set("revoked_at = ?", time.Now()).
+		Where("api_key_id = ?", apiKeyId).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	return err
+}
+
+func (r *APIKey) UpdateAPIKeyQuota(ctx context.Context, apiKeyId int, dailyRequestQuota int, dailyByteQuota int64) error {
+	_, err := r.db.NewUpdate().
+		Model((*model.APIKey)(nil)).
+		Set("daily_request_quota = ?", dailyRequestQuota).
+		Set("daily_byte_quota = ?", dailyByteQuota).
+		Where("api_key_id = ?", apiKeyId).
+		Exec(ctx)
+	return err
+}
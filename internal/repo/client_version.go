@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo/selector"
+)
+
+type ClientVersion struct {
+	db  *bun.DB
+	sel selector.S[model.ClientVersion]
+}
+
+func NewClientVersion(db *bun.DB) *ClientVersion {
+	return &ClientVersion{db: db, sel: selector.New[model.ClientVersion](db)}
+}
+
+func (r *ClientVersion) GetClientVersions(ctx context.Context) ([]*model.ClientVersion, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Order("cv.source ASC")
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+// SaveClientVersion creates the gate of the source, or replaces it when there is one already
+func (r *ClientVersion) SaveClientVersion(ctx context.Context, clientVersion *model.ClientVersion) error {
+	_, err := r.db.NewInsert().
+		Model(clientVersion).
+		Column("source", "min_version", "blocked_versions", "disabled", "message").
+		On("CONFLICT (source) DO UPDATE").
+		Set("min_version = EXCLUDED.min_version").
+		Set("blocked_versions = EXCLUDED.blocked_versions").
+		Set("disabled = EXCLUDED.disabled").
+		Set("message = EXCLUDED.message").
+		Set("updated_at = current_timestamp").
+		Returning("created_at, updated_at").
+		Exec(ctx)
+	return err
+}
+
+// DeleteClientVersion removes the gate of the source, and tells whether there was one
+func (r *ClientVersion) DeleteClientVersion(ctx context.Context, source string) (bool, error) {
+	res, err := r.db.NewDelete().
+		Model((*model.ClientVersion)(nil)).
+		Where("source = ?", source).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
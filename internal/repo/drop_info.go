@@ -32,7 +32,7 @@ func NewDropInfo(db *bun.DB) *DropInfo {
 
 func (r *DropInfo) GetDropInfo(ctx context.Context, id int) (*model.DropInfo, error) {
 	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where("id = ?", id)
+		return q.Where("drop_id = ?", id)
 	})
 }
 
@@ -53,8 +53,14 @@ type DropInfoQuery struct {
 	ArkStageId string
 }
 
-// GetDropInfoByArkId returns a drop info by its ark id.
+// GetForCurrentTimeRange returns the drop infos of the stage in effect right now.
 func (r *DropInfo) GetForCurrentTimeRange(ctx context.Context, query *DropInfoQuery) ([]*model.DropInfo, error) {
+	return r.GetForTimeRangeAt(ctx, query, time.Now())
+}
+
+// GetForTimeRangeAt returns the drop infos of the stage in effect at t. When the drop table of the stage is cut over
+// to a new version, several time ranges of the stage include t, and the version of the latest start time is in effect.
+func (r *DropInfo) GetForTimeRangeAt(ctx context.Context, query *DropInfoQuery, t time.Time) ([]*model.DropInfo, error) {
 	var dropInfo []*model.DropInfo
 	err := pgqry.New(
 		r.db.NewSelect().
@@ -65,7 +71,8 @@ func (r *DropInfo) GetForCurrentTimeRange(ctx context.Context, query *DropInfoQu
 		UseItemById("di.item_id").
 		UseStageById("di.stage_id").
 		UseTimeRange("di.range_id").
-		DoFilterCurrentTimeRange().
+		DoFilterTimeRangeAt(t).
+		DoFilterLatestDropInfoVersion("di", t).
 		Q.Scan(ctx)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -77,6 +84,23 @@ func (r *DropInfo) GetForCurrentTimeRange(ctx context.Context, query *DropInfoQu
 	return dropInfo, nil
 }
 
+// UpdateDropInfoBounds updates the bounds of the drop infos, all at once
+func (r *DropInfo) UpdateDropInfoBounds(ctx context.Context, dropInfos []*model.DropInfo) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, dropInfo := range dropInfos {
+			_, err := tx.NewUpdate().
+				Model(dropInfo).
+				Column("bounds").
+				WherePK().
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (r *DropInfo) GetItemDropSetByStageIdAndRangeId(ctx context.Context, server string, stageId int, rangeId int) ([]int, error) {
 	var results []int
 	err := r.db.NewSelect().
@@ -99,8 +123,9 @@ func (r *DropInfo) GetItemDropSetByStageIdAndRangeId(ctx context.Context, server
 	return results, nil
 }
 
-func (r *DropInfo) GetForCurrentTimeRangeWithDropTypes(ctx context.Context, query *DropInfoQuery) (itemDropInfos, typeDropInfos []*model.DropInfo, err error) {
-	allDropInfos, err := r.GetForCurrentTimeRange(ctx, query)
+// GetForTimeRangeAtWithDropTypes splits the drop infos in effect at t into those of items and those of drop types
+func (r *DropInfo) GetForTimeRangeAtWithDropTypes(ctx context.Context, query *DropInfoQuery, t time.Time) (itemDropInfos, typeDropInfos []*model.DropInfo, err error) {
+	allDropInfos, err := r.GetForTimeRangeAt(ctx, query, t)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -7,6 +7,7 @@ import (
 
 	"exusiai.dev/gommon/constant"
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
 	"github.com/uptrace/bun"
 
 	"exusiai.dev/backend-next/internal/model"
@@ -23,12 +24,47 @@ func NewDropMatrixElement(db *bun.DB) *DropMatrixElement {
 	return &DropMatrixElement{db: db, sel: selector.New[model.DropMatrixElement](db)}
 }
 
-func (s *DropMatrixElement) BatchSaveElements(ctx context.Context, elements []*model.DropMatrixElement, server string) error {
-	_, err := s.db.NewInsert().Model(&elements).Exec(ctx)
-	if err != nil {
+// dropMatrixElementsUpsertChunkSize is the number of elements upserted per statement, which keeps the statements
+// well under the limit of bind parameters of postgres
+const dropMatrixElementsUpsertChunkSize = 1000
+
+// BatchSaveElements replaces the elements of the day of the server with the given ones. The elements are upserted
+// in chunks, and those of the day which are no longer among them are deleted afterwards, all in one transaction so
+// that readers observe either the previous or the refreshed elements of the day, but never an empty or a partially
+// refreshed one in-between. When stageIds is not empty, only the elements of those stages are replaced.
+func (s *DropMatrixElement) BatchSaveElements(ctx context.Context, elements []*model.DropMatrixElement, server string, dayNum int, stageIds []int) error {
+	return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		elementIds := make([]int, 0, len(elements))
+		for _, chunk := range lo.Chunk(elements, dropMatrixElementsUpsertChunkSize) {
+			_, err := tx.NewInsert().Model(&chunk).
+				On("CONFLICT (server, source_category, day_num, stage_id, item_id, start_time) DO UPDATE").
+				Set("end_time = EXCLUDED.end_time").
+				Set("quantity = EXCLUDED.quantity").
+				Set("times = EXCLUDED.times").
+				Set("reporters = EXCLUDED.reporters").
+				Set("quantity_buckets = EXCLUDED.quantity_buckets").
+				Returning("element_id").
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+			for _, el := range chunk {
+				elementIds = append(elementIds, el.ElementID)
+			}
+		}
+
+		query := tx.NewDelete().Model((*model.DropMatrixElement)(nil)).
+			Where("server = ?", server).
+			Where("day_num = ?", dayNum)
+		if len(stageIds) > 0 {
+			query = query.Where("stage_id IN (?)", bun.In(stageIds))
+		}
+		if len(elementIds) > 0 {
+			query = query.Where("element_id NOT IN (?)", bun.In(elementIds))
+		}
+		_, err := query.Exec(ctx)
 		return err
-	}
-	return nil
+	})
 }
 
 func (s *DropMatrixElement) DeleteByServerAndDayNum(ctx context.Context, server string, dayNum int) error {
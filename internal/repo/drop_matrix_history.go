@@ -0,0 +1,40 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo/selector"
+)
+
+type DropMatrixHistory struct {
+	db  *bun.DB
+	sel selector.S[model.DropMatrixHistory]
+}
+
+func NewDropMatrixHistory(db *bun.DB) *DropMatrixHistory {
+	return &DropMatrixHistory{db: db, sel: selector.New[model.DropMatrixHistory](db)}
+}
+
+// SaveDropMatrixHistory records the snapshot of its day, replacing the one recorded earlier in the day
+func (r *DropMatrixHistory) SaveDropMatrixHistory(ctx context.Context, history *model.DropMatrixHistory) error {
+	_, err := r.db.NewInsert().
+		Model(history).
+		On("CONFLICT (server, day_num) DO UPDATE").
+		Set("recorded_at = EXCLUDED.recorded_at").
+		Set("content = EXCLUDED.content").
+		Exec(ctx)
+	return err
+}
+
+// GetDropMatrixHistoryAsOf returns the snapshot of the day, or of the latest day before it with a snapshot
+func (r *DropMatrixHistory) GetDropMatrixHistoryAsOf(ctx context.Context, server string, dayNum int) (*model.DropMatrixHistory, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("dmh.server = ?", server).
+			Where("dmh.day_num <= ?", dayNum).
+			Order("dmh.day_num DESC").
+			Limit(1)
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/zeebo/xxh3"
 
 	"exusiai.dev/backend-next/internal/model"
@@ -44,31 +45,50 @@ func (r *DropPattern) GetDropPatternByHash(ctx context.Context, hash string) (*m
 	})
 }
 
+// GetOrCreateDropPatternFromDrops resolves the pattern of the drops by its hash in one statement, creating it along
+// with its elements unless it exists. created tells whether the pattern has been created by this call.
 func (r *DropPattern) GetOrCreateDropPatternFromDrops(ctx context.Context, tx bun.Tx, drops []*types.Drop) (*model.DropPattern, bool, error) {
 	originalFingerprint, hash := r.calculateDropPatternHash(drops)
-	dropPattern := &model.DropPattern{
-		Hash:                hash,
-		OriginalFingerprint: originalFingerprint,
+	itemIds := make([]int, 0, len(drops))
+	quantities := make([]int, 0, len(drops))
+	for _, drop := range drops {
+		itemIds = append(itemIds, drop.ItemID)
+		quantities = append(quantities, drop.Quantity)
 	}
-	err := tx.NewSelect().
-		Model(dropPattern).
-		Where("hash = ?", hash).
-		Scan(ctx)
 
-	if err == nil {
-		return dropPattern, false, nil
-	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return nil, false, err
+	dropPattern := &model.DropPattern{}
+	var created bool
+	// the existing pattern is selected from the snapshot of the statement, which misses a pattern committed by a
+	// concurrent ingestion while the insertion waited for it; the pattern is selected over again in that case
+	err := tx.NewRaw(`WITH ins AS (
+			INSERT INTO drop_patterns (hash, original_fingerprint) VALUES (?, ?)
+			ON CONFLICT (hash) DO NOTHING
+			RETURNING pattern_id, hash, original_fingerprint
+		), elements AS (
+			INSERT INTO drop_pattern_elements (drop_pattern_id, item_id, quantity)
+			SELECT ins.pattern_id, e.item_id, e.quantity
+			FROM ins, unnest(?::int[], ?::int[]) AS e(item_id, quantity)
+		)
+		SELECT pattern_id, hash, original_fingerprint, TRUE FROM ins
+		UNION ALL
+		SELECT pattern_id, hash, original_fingerprint, FALSE FROM drop_patterns WHERE hash = ?
+		LIMIT 1`,
+		hash, originalFingerprint, pgdialect.Array(itemIds), pgdialect.Array(quantities), hash,
+	).Scan(ctx, &dropPattern.PatternID, &dropPattern.Hash, &dropPattern.OriginalFingerprint, &created)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = tx.NewSelect().
+			Model(dropPattern).
+			Where("hash = ?", hash).
+			Scan(ctx)
 	}
-
-	_, err = tx.NewInsert().
-		Model(dropPattern).
-		Exec(ctx)
 	if err != nil {
 		return nil, false, err
 	}
 
-	return dropPattern, true, nil
+	if dropPattern.OriginalFingerprint != originalFingerprint {
+		return nil, false, errors.Errorf("drop pattern hash %s of %q collides with %q", hash, originalFingerprint, dropPattern.OriginalFingerprint)
+	}
+	return dropPattern, created, nil
 }
 
 func (r *DropPattern) calculateDropPatternHash(drops []*types.Drop) (originalFingerprint, hexHash string) {
@@ -8,29 +8,42 @@ import (
 	"time"
 
 	"exusiai.dev/gommon/constant"
+	"github.com/samber/lo"
 	"github.com/uptrace/bun"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gopkg.in/guregu/null.v3"
 
 	"exusiai.dev/backend-next/internal/model"
 	modelv2 "exusiai.dev/backend-next/internal/model/v2"
 	"exusiai.dev/backend-next/internal/pkg/gameday"
+	"exusiai.dev/backend-next/internal/pkg/observability"
+	"exusiai.dev/backend-next/internal/pkg/pgpool"
 	"exusiai.dev/backend-next/internal/pkg/pgqry"
 	"exusiai.dev/backend-next/internal/repo/selector"
 	"exusiai.dev/backend-next/internal/util"
 )
 
 type DropReport struct {
-	db  *bun.DB
-	sel selector.S[model.DropReport]
+	db    *bun.DB
+	pools *pgpool.Pools
+	sel   selector.S[model.DropReport]
 }
 
-func NewDropReport(db *bun.DB) *DropReport {
+func NewDropReport(db *bun.DB, pools *pgpool.Pools) *DropReport {
 	return &DropReport{
-		db:  db,
-		sel: selector.New[model.DropReport](db),
+		db:    db,
+		pools: pools,
+		sel:   selector.New[model.DropReport](db),
 	}
 }
 
+// conn is the database of the workload of ctx, for the aggregations and the archives run by the background jobs to
+// hold the connections of their own pool
+func (r *DropReport) conn(ctx context.Context) *bun.DB {
+	return r.pools.DB(ctx)
+}
+
 func (r *DropReport) CreateDropReport(ctx context.Context, tx bun.Tx, dropReport *model.DropReport) error {
 	_, err := tx.NewInsert().
 		Model(dropReport).
@@ -38,13 +51,20 @@ func (r *DropReport) CreateDropReport(ctx context.Context, tx bun.Tx, dropReport
 	return err
 }
 
-func (r *DropReport) DeleteDropReport(ctx context.Context, reportId int) error {
-	_, err := r.db.NewUpdate().
-		Model((*model.DropReport)(nil)).
-		Set("reliability = ?", -1).
+// RecallDropReport marks the report as recalled by its reporter, returning it as it was before
+func (r *DropReport) RecallDropReport(ctx context.Context, tx bun.Tx, reportId int) (*model.DropReport, error) {
+	var report model.DropReport
+	if err := tx.NewSelect().
+		Model(&report).
 		Where("report_id = ?", reportId).
-		Exec(ctx)
-	return err
+		For("UPDATE").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.UpdateDropReportReliability(ctx, tx, reportId, -1); err != nil {
+		return nil, err
+	}
+	return &report, nil
 }
 
 func (r *DropReport) UpdateDropReportReliability(ctx context.Context, tx bun.Tx, reportId int, reliability int) error {
@@ -56,36 +76,81 @@ func (r *DropReport) UpdateDropReportReliability(ctx context.Context, tx bun.Tx,
 	return err
 }
 
-// only filtered by stage_id, not item_id, needs post-filtering
-func (r *DropReport) CalcQuantityUniqCount(
-	ctx context.Context, queryCtx *model.DropReportQueryContext,
-) ([]*model.QuantityUniqCountResultForDropMatrix, error) {
-	results := make([]*model.QuantityUniqCountResultForDropMatrix, 0)
-
-	subq1 := r.db.NewSelect().
-		TableExpr("drop_reports AS dr").
-		Column("dr.stage_id", "dpe.item_id", "dpe.quantity").
-		Join("JOIN drop_pattern_elements AS dpe ON dpe.drop_pattern_id = dr.pattern_id")
-	if queryCtx.SourceCategory != constant.SourceCategoryAll {
-		subq1 = subq1.Column("dr.source_name")
+// UpdateAccountReportsReliability changes the reliability of the reports of the account created since the given
+// time (all of them when since is nil) from one value to another, returning the extent of the changed reports per
+// server and stage
+func (r *DropReport) UpdateAccountReportsReliability(ctx context.Context, accountId int, since *time.Time, from int, to int) ([]*model.ReliabilityChangeResult, error) {
+	query := r.conn(ctx).NewUpdate().
+		Model((*model.DropReport)(nil)).
+		Set("reliability = ?", to).
+		Where("account_id = ?", accountId).
+		Where("reliability = ?", from)
+	if since != nil {
+		query = query.Where("created_at >= ?", *since)
 	}
-	r.handleAccountAndReliability(subq1, queryCtx.AccountID)
-	if queryCtx.ExcludeNonOneTimes {
-		r.handleTimes(subq1, 1)
+	return r.scanReliabilityChanges(ctx, query)
+}
+
+// UpdateReportsReliability changes the reliability of the given reports from one value to another, returning the
+// extent of the changed reports per server and stage. The reports of other reliabilities are left as is.
+func (r *DropReport) UpdateReportsReliability(ctx context.Context, reportIds []int, from int, to int) ([]*model.ReliabilityChangeResult, error) {
+	if len(reportIds) == 0 {
+		return []*model.ReliabilityChangeResult{}, nil
 	}
-	r.handleCreatedAtWithTime(subq1, queryCtx.StartTime, queryCtx.EndTime)
-	r.handleServer(subq1, queryCtx.Server)
-	r.handleStages(subq1, queryCtx.GetStageIds())
+	query := r.conn(ctx).NewUpdate().
+		Model((*model.DropReport)(nil)).
+		Set("reliability = ?", to).
+		Where("report_id IN (?)", bun.In(reportIds)).
+		Where("reliability = ?", from)
+	return r.scanReliabilityChanges(ctx, query)
+}
 
-	mainq := r.db.NewSelect().
-		TableExpr("(?) AS a", subq1).
-		Column("stage_id", "item_id", "quantity").
-		ColumnExpr("COUNT(*) AS count")
-	r.handleSourceName(mainq, queryCtx.SourceCategory)
+// GetReportsByReliability returns up to limit reports of the reliability created before the given time, oldest first
+func (r *DropReport) GetReportsByReliability(ctx context.Context, reliability int, before time.Time, limit int) ([]*model.DropReport, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.
+			Where("dr.reliability = ?", reliability).
+			Where("dr.created_at < ?", before).
+			Order("dr.report_id ASC").
+			Limit(limit)
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
 
-	if err := mainq.
-		Group("stage_id", "item_id", "quantity").
-		Scan(ctx, &results); err != nil {
+func (r *DropReport) GetDropReportById(ctx context.Context, reportId int) (*model.DropReport, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("dr.report_id = ?", reportId)
+	})
+}
+
+// SampleRecentReports returns up to limit accepted reports of the stage created since the given time, picked at
+// random among those not reviewed yet
+func (r *DropReport) SampleRecentReports(ctx context.Context, server string, stageId int, since time.Time, limit int) ([]*model.DropReport, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.
+			Where("dr.server = ?", server).
+			Where("dr.stage_id = ?", stageId).
+			Where("dr.created_at >= ?", since).
+			Where("dr.reliability = 0").
+			Where("NOT EXISTS (SELECT 1 FROM report_reviews AS rr WHERE rr.report_id = dr.report_id)").
+			OrderExpr("random()").
+			Limit(limit)
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+// scanReliabilityChanges runs the update of the reliability of the reports, and aggregates the extent of the changed
+// reports per server and stage
+func (r *DropReport) scanReliabilityChanges(ctx context.Context, query *bun.UpdateQuery) ([]*model.ReliabilityChangeResult, error) {
+	results := make([]*model.ReliabilityChangeResult, 0)
+	err := r.conn(ctx).NewSelect().
+		With("changed", query.Returning("server, stage_id, created_at")).
+		TableExpr("changed").
+		Column("server", "stage_id").
+		ColumnExpr("COUNT(*) AS count").
+		ColumnExpr("MIN(created_at) AS min_time").
+		ColumnExpr("MAX(created_at) AS max_time").
+		Group("server", "stage_id").
+		Scan(ctx, &results)
+	if err != nil {
 		return nil, err
 	}
 	return results, nil
@@ -93,10 +158,17 @@ func (r *DropReport) CalcQuantityUniqCount(
 
 func (r *DropReport) CalcTotalTimes(
 	ctx context.Context, queryCtx *model.DropReportQueryContext,
-) ([]*model.TotalTimesResult, error) {
+) (_ []*model.TotalTimesResult, err error) {
+	ctx, span := tracer.Start(ctx, "DropReport.CalcTotalTimes", trace.WithAttributes(
+		attribute.String("server", queryCtx.Server),
+		attribute.String("sourceCategory", queryCtx.SourceCategory),
+		attribute.Int("stages", len(queryCtx.GetStageIds())),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+
 	results := make([]*model.TotalTimesResult, 0)
 
-	subq1 := r.db.NewSelect().
+	subq1 := r.conn(ctx).NewSelect().
 		TableExpr("drop_reports AS dr").
 		Column("dr.source_name", "dr.stage_id", "dr.times")
 	r.handleAccountAndReliability(subq1, queryCtx.AccountID)
@@ -110,7 +182,7 @@ func (r *DropReport) CalcTotalTimes(
 		r.handleStages(subq1, stageIds)
 	}
 
-	mainq := r.db.NewSelect().
+	mainq := r.conn(ctx).NewSelect().
 		TableExpr("(?) AS a", subq1).
 		Column("stage_id").
 		ColumnExpr("SUM(times) AS total_times")
@@ -124,12 +196,208 @@ func (r *DropReport) CalcTotalTimes(
 	return results, nil
 }
 
+// CalcDropMatrixAggregates aggregates the times of each stage along with the total quantity and the quantity buckets
+// of each item dropped from it, which are filtered by both stage and item
+func (r *DropReport) CalcDropMatrixAggregates(
+	ctx context.Context, queryCtx *model.DropReportQueryContext,
+) (_ []*model.CombinedResultForDropMatrix, err error) {
+	ctx, span := tracer.Start(ctx, "DropReport.CalcDropMatrixAggregates", trace.WithAttributes(
+		attribute.String("server", queryCtx.Server),
+		attribute.String("sourceCategory", queryCtx.SourceCategory),
+		attribute.Int("stages", len(queryCtx.GetStageIds())),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+
+	results := make([]*model.CombinedResultForDropMatrix, 0)
+
+	itemFilter, ok := dropMatrixItemFilter(queryCtx.StageItemFilter)
+	if !ok {
+		return results, nil
+	}
+
+	uniqq := r.conn(ctx).NewSelect().
+		TableExpr("drop_reports AS dr").
+		Column("dr.stage_id", "dpe.item_id", "dpe.quantity").
+		ColumnExpr("COUNT(*) AS count").
+		Join("JOIN drop_pattern_elements AS dpe ON dpe.drop_pattern_id = dr.pattern_id")
+	timesq := r.conn(ctx).NewSelect().
+		TableExpr("drop_reports AS dr").
+		Column("dr.stage_id").
+		ColumnExpr("SUM(dr.times) AS times").
+		ColumnExpr("COUNT(DISTINCT dr.account_id) AS reporters")
+	for _, q := range []*bun.SelectQuery{uniqq, timesq} {
+		r.handleAccountAndReliability(q, queryCtx.AccountID)
+		if queryCtx.ExcludeNonOneTimes {
+			r.handleTimes(q, 1)
+		}
+		r.handleCreatedAtWithTime(q, queryCtx.StartTime, queryCtx.EndTime)
+		r.handleServer(q, queryCtx.Server)
+		r.handleSourceName(q, queryCtx.SourceCategory)
+	}
+	r.handleStagesAndItemsOn(uniqq, "dr.stage_id", "dpe.item_id", itemFilter)
+	r.handleStages(timesq, queryCtx.GetStageIds())
+
+	if err := selectDropMatrixAggregates(r.conn(ctx),
+		uniqq.Group("dr.stage_id", "dpe.item_id", "dpe.quantity"),
+		timesq.Group("dr.stage_id"),
+	).Scan(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CalcDropMatrixAggregatesForRange is CalcDropMatrixAggregates for the global reports within a time range in the
+// database, which reads the materialized views instead of the reports. The views are as fresh as their last refresh.
+func (r *DropReport) CalcDropMatrixAggregatesForRange(
+	ctx context.Context, server string, rangeId int, stageItemFilter *map[int][]int, sourceCategory string,
+) (_ []*model.CombinedResultForDropMatrix, err error) {
+	ctx, span := tracer.Start(ctx, "DropReport.CalcDropMatrixAggregatesForRange", trace.WithAttributes(
+		attribute.String("server", server),
+		attribute.Int("rangeId", rangeId),
+		attribute.String("sourceCategory", sourceCategory),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+
+	results := make([]*model.CombinedResultForDropMatrix, 0)
+
+	itemFilter, ok := dropMatrixItemFilter(stageItemFilter)
+	if !ok {
+		return results, nil
+	}
+
+	// the views are aliased as dr to share the stage filters with the queries on the reports
+	uniqq := r.conn(ctx).NewSelect().
+		TableExpr("drop_matrix_range_elements AS dr").
+		Column("dr.stage_id", "dr.item_id", "dr.quantity").
+		ColumnExpr("SUM(dr.count) AS count")
+	// the reporters are not additive across the source names, so they are counted per category in a view of their own
+	timesq := r.conn(ctx).NewSelect().
+		TableExpr("drop_matrix_range_times AS dr").
+		Column("dr.stage_id").
+		ColumnExpr("SUM(dr.times) AS times").
+		ColumnExpr("COALESCE(MAX(rr.?), 0) AS reporters", bun.Ident(rangeReportersColumn(sourceCategory))).
+		Join("LEFT JOIN drop_matrix_range_reporters AS rr ON rr.range_id = dr.range_id AND rr.stage_id = dr.stage_id")
+	for _, q := range []*bun.SelectQuery{uniqq, timesq} {
+		q.Where("dr.range_id = ?", rangeId)
+		r.handleServer(q, server)
+		r.handleSourceName(q, sourceCategory)
+	}
+	r.handleStagesAndItemsOn(uniqq, "dr.stage_id", "dr.item_id", itemFilter)
+	if stageItemFilter != nil {
+		r.handleStages(timesq, lo.Keys(*stageItemFilter))
+	}
+
+	if err := selectDropMatrixAggregates(r.conn(ctx),
+		uniqq.Group("dr.stage_id", "dr.item_id", "dr.quantity"),
+		timesq.Group("dr.stage_id"),
+	).Scan(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CalcReportersForTimeRange counts the distinct accounts having reported each of the stages within the time range
+func (r *DropReport) CalcReportersForTimeRange(
+	ctx context.Context, server string, timeRange *model.TimeRange, stageIds []int, sourceCategory string,
+) (_ []*model.ReportersResult, err error) {
+	ctx, span := tracer.Start(ctx, "DropReport.CalcReportersForTimeRange", trace.WithAttributes(
+		attribute.String("server", server),
+		attribute.String("sourceCategory", sourceCategory),
+		attribute.Int("stages", len(stageIds)),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+
+	results := make([]*model.ReportersResult, 0)
+	if len(stageIds) == 0 {
+		return results, nil
+	}
+
+	query := r.conn(ctx).NewSelect().
+		TableExpr("drop_reports AS dr").
+		Column("dr.stage_id").
+		ColumnExpr("COUNT(DISTINCT dr.account_id) AS reporters")
+	r.handleAccountAndReliability(query, null.NewInt(0, false))
+	r.handleCreatedAtWithTime(query, timeRange.StartTime, timeRange.EndTime)
+	r.handleServer(query, server)
+	r.handleSourceName(query, sourceCategory)
+	r.handleStages(query, stageIds)
+
+	if err := query.
+		Group("dr.stage_id").
+		Scan(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// RefreshDropMatrixViews refreshes the materialized views read by CalcDropMatrixAggregatesForRange, without blocking
+// the reads in the meantime
+func (r *DropReport) RefreshDropMatrixViews(ctx context.Context) (err error) {
+	ctx, span := tracer.Start(ctx, "DropReport.RefreshDropMatrixViews")
+	defer func() { observability.EndSpan(span, err) }()
+
+	for _, view := range []string{"drop_matrix_range_elements", "drop_matrix_range_times", "drop_matrix_range_reporters"} {
+		if _, err := r.conn(ctx).ExecContext(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY ?", bun.Ident(view)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectDropMatrixAggregates joins the counts of each quantity of the items, grouped by stage, item and quantity,
+// with the times of the stages, and folds the counts into the total quantity and the quantity buckets of each item
+func selectDropMatrixAggregates(db bun.IDB, uniqq, timesq *bun.SelectQuery) *bun.SelectQuery {
+	return db.NewSelect().
+		With("uniq", uniqq).
+		With("stage_times", timesq).
+		TableExpr("uniq AS u").
+		Join("JOIN stage_times AS st ON st.stage_id = u.stage_id").
+		Column("u.stage_id", "u.item_id", "st.times", "st.reporters").
+		ColumnExpr("SUM(u.quantity * u.count)::bigint AS quantity").
+		ColumnExpr("jsonb_object_agg(u.quantity, u.count) AS quantity_buckets").
+		Group("u.stage_id", "u.item_id", "st.times", "st.reporters")
+}
+
+// rangeReportersColumn is the column of drop_matrix_range_reporters counting the reporters of the source category
+func rangeReportersColumn(sourceCategory string) string {
+	switch sourceCategory {
+	case constant.SourceCategoryManual:
+		return "manual_reporters"
+	case constant.SourceCategoryAutomated:
+		return "automated_reporters"
+	default:
+		return "reporters"
+	}
+}
+
+// dropMatrixItemFilter leaves out the stages without any item from the filter, since no item is aggregated for them.
+// It returns false when no item is to be aggregated at all.
+func dropMatrixItemFilter(stageItemFilter *map[int][]int) (map[int][]int, bool) {
+	if stageItemFilter == nil {
+		return nil, true
+	}
+	itemFilter := make(map[int][]int, len(*stageItemFilter))
+	for stageId, itemIds := range *stageItemFilter {
+		if len(itemIds) > 0 {
+			itemFilter[stageId] = itemIds
+		}
+	}
+	return itemFilter, len(itemFilter) > 0
+}
+
 func (r *DropReport) CalcTotalQuantityForPatternMatrix(
 	ctx context.Context, queryCtx *model.DropReportQueryContext,
-) ([]*model.TotalQuantityResultForPatternMatrix, error) {
+) (_ []*model.TotalQuantityResultForPatternMatrix, err error) {
+	ctx, span := tracer.Start(ctx, "DropReport.CalcTotalQuantityForPatternMatrix", trace.WithAttributes(
+		attribute.String("server", queryCtx.Server),
+		attribute.String("sourceCategory", queryCtx.SourceCategory),
+		attribute.Int("stages", len(queryCtx.GetStageIds())),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+
 	results := make([]*model.TotalQuantityResultForPatternMatrix, 0)
 
-	subq1 := r.db.NewSelect().
+	subq1 := r.conn(ctx).NewSelect().
 		TableExpr("drop_reports AS dr").
 		Column("dr.source_name", "dr.stage_id", "dr.pattern_id")
 	r.handleAccountAndReliability(subq1, queryCtx.AccountID)
@@ -141,7 +409,7 @@ func (r *DropReport) CalcTotalQuantityForPatternMatrix(
 	r.handleStages(subq1, queryCtx.GetStageIds())
 	r.handleTimes(subq1, 1)
 
-	mainq := r.db.NewSelect().
+	mainq := r.conn(ctx).NewSelect().
 		TableExpr("(?) AS a", subq1).
 		Column("stage_id", "pattern_id").
 		ColumnExpr("COUNT(*) AS total_quantity")
@@ -157,7 +425,14 @@ func (r *DropReport) CalcTotalQuantityForPatternMatrix(
 
 func (r *DropReport) CalcTotalQuantityForTrend(
 	ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIdItemIdMap map[int][]int, accountId null.Int, sourceCategory string,
-) ([]*model.TotalQuantityResultForTrend, error) {
+) (_ []*model.TotalQuantityResultForTrend, err error) {
+	ctx, span := tracer.Start(ctx, "DropReport.CalcTotalQuantityForTrend", trace.WithAttributes(
+		attribute.String("server", server),
+		attribute.Int("intervals", intervalNum),
+		attribute.Int("stages", len(stageIdItemIdMap)),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+
 	results := make([]*model.TotalQuantityResultForTrend, 0)
 	if len(stageIdItemIdMap) == 0 {
 		return results, nil
@@ -166,8 +441,8 @@ func (r *DropReport) CalcTotalQuantityForTrend(
 	gameDayStart := gameday.StartTime(server, *startTime)
 	lastDayEnd := gameDayStart.Add(time.Hour * time.Duration(int(intervalLength.Hours())*(intervalNum+1)))
 
-	subq1 := r.db.NewSelect().
-		With("intervals", r.genSubQueryForTrendSegments(gameDayStart, intervalLength, intervalNum)).
+	subq1 := r.conn(ctx).NewSelect().
+		With("intervals", trendSegments(r.db, gameDayStart, intervalLength, intervalNum)).
 		TableExpr("drop_reports AS dr").
 		Column("dr.source_name", "sub.group_id", "sub.interval_start", "sub.interval_end", "dr.stage_id", "dpe.item_id", "dpe.quantity").
 		Join("JOIN drop_pattern_elements AS dpe ON dpe.drop_pattern_id = dr.pattern_id").
@@ -178,7 +453,7 @@ func (r *DropReport) CalcTotalQuantityForTrend(
 	r.handleServer(subq1, server)
 	r.handleStagesAndItems(subq1, stageIdItemIdMap)
 
-	mainq := r.db.NewSelect().
+	mainq := r.conn(ctx).NewSelect().
 		TableExpr("(?) AS a", subq1).
 		Column("group_id", "interval_start", "interval_end", "stage_id", "item_id").
 		ColumnExpr("SUM(quantity) AS total_quantity")
@@ -194,7 +469,14 @@ func (r *DropReport) CalcTotalQuantityForTrend(
 
 func (r *DropReport) CalcTotalTimesForTrend(
 	ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIds []int, accountId null.Int, sourceCategory string,
-) ([]*model.TotalTimesResultForTrend, error) {
+) (_ []*model.TotalTimesResultForTrend, err error) {
+	ctx, span := tracer.Start(ctx, "DropReport.CalcTotalTimesForTrend", trace.WithAttributes(
+		attribute.String("server", server),
+		attribute.Int("intervals", intervalNum),
+		attribute.Int("stages", len(stageIds)),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+
 	results := make([]*model.TotalTimesResultForTrend, 0)
 	if len(stageIds) == 0 {
 		return results, nil
@@ -203,10 +485,10 @@ func (r *DropReport) CalcTotalTimesForTrend(
 	gameDayStart := gameday.StartTime(server, *startTime)
 	lastDayEnd := gameDayStart.Add(time.Hour * time.Duration(int(intervalLength.Hours())*(intervalNum+1)))
 
-	subq1 := r.db.NewSelect().
-		With("intervals", r.genSubQueryForTrendSegments(gameDayStart, intervalLength, intervalNum)).
+	subq1 := r.conn(ctx).NewSelect().
+		With("intervals", trendSegments(r.db, gameDayStart, intervalLength, intervalNum)).
 		TableExpr("drop_reports AS dr").
-		Column("dr.source_name", "sub.group_id", "sub.interval_start", "sub.interval_end", "dr.stage_id", "dr.times").
+		Column("dr.source_name", "sub.group_id", "sub.interval_start", "sub.interval_end", "dr.stage_id", "dr.times", "dr.account_id").
 		Join("RIGHT JOIN intervals AS sub").
 		JoinOn("dr.created_at >= sub.interval_start AND dr.created_at < sub.interval_end")
 	r.handleAccountAndReliability(subq1, accountId)
@@ -214,10 +496,11 @@ func (r *DropReport) CalcTotalTimesForTrend(
 	r.handleServer(subq1, server)
 	r.handleStages(subq1, stageIds)
 
-	mainq := r.db.NewSelect().
+	mainq := r.conn(ctx).NewSelect().
 		TableExpr("(?) AS a", subq1).
 		Column("group_id", "interval_start", "interval_end", "stage_id").
-		ColumnExpr("SUM(times) AS total_times")
+		ColumnExpr("SUM(times) AS total_times").
+		ColumnExpr("COUNT(DISTINCT account_id) AS reporters")
 	r.handleSourceName(mainq, sourceCategory)
 
 	if err := mainq.
@@ -228,15 +511,161 @@ func (r *DropReport) CalcTotalTimesForTrend(
 	return results, nil
 }
 
+// CalcTotalQuantityForTrendFromRollups is CalcTotalQuantityForTrend for the global reports, which reads the daily
+// rollups instead of the reports. The intervals are to be whole days, each day counting towards the interval it starts
+// in.
+func (r *DropReport) CalcTotalQuantityForTrendFromRollups(
+	ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIdItemIdMap map[int][]int, sourceCategory string,
+) (_ []*model.TotalQuantityResultForTrend, err error) {
+	ctx, span := tracer.Start(ctx, "DropReport.CalcTotalQuantityForTrendFromRollups", trace.WithAttributes(
+		attribute.String("server", server),
+		attribute.Int("intervals", intervalNum),
+		attribute.Int("stages", len(stageIdItemIdMap)),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+
+	results := make([]*model.TotalQuantityResultForTrend, 0)
+	if len(stageIdItemIdMap) == 0 {
+		return results, nil
+	}
+
+	// the rollups are aliased as dr to share the stage filters with the queries on the reports
+	query := r.conn(ctx).NewSelect().
+		With("intervals", trendSegments(r.db, gameday.StartTime(server, *startTime), intervalLength, intervalNum)).
+		TableExpr("drop_report_daily_rollups AS dr").
+		Join("JOIN intervals AS sub ON dr.day_start >= sub.interval_start AND dr.day_start < sub.interval_end").
+		Column("sub.group_id", "sub.interval_start", "sub.interval_end", "dr.stage_id", "dr.item_id").
+		ColumnExpr("SUM(dr.quantity) AS total_quantity").
+		Where("dr.item_id <> 0")
+	r.handleServer(query, server)
+	r.handleRollupSourceCategory(query, sourceCategory)
+	r.handleStagesAndItemsOn(query, "dr.stage_id", "dr.item_id", stageIdItemIdMap)
+
+	if err := query.
+		Group("sub.group_id", "sub.interval_start", "sub.interval_end", "dr.stage_id", "dr.item_id").
+		Scan(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CalcTotalTimesForTrendFromRollups is CalcTotalTimesForTrend for the global reports, which reads the daily rollups
+// instead of the reports. The intervals are to be whole days, each day counting towards the interval it starts in.
+func (r *DropReport) CalcTotalTimesForTrendFromRollups(
+	ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIds []int, sourceCategory string,
+) (_ []*model.TotalTimesResultForTrend, err error) {
+	ctx, span := tracer.Start(ctx, "DropReport.CalcTotalTimesForTrendFromRollups", trace.WithAttributes(
+		attribute.String("server", server),
+		attribute.Int("intervals", intervalNum),
+		attribute.Int("stages", len(stageIds)),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+
+	results := make([]*model.TotalTimesResultForTrend, 0)
+	if len(stageIds) == 0 {
+		return results, nil
+	}
+
+	query := r.conn(ctx).NewSelect().
+		With("intervals", trendSegments(r.db, gameday.StartTime(server, *startTime), intervalLength, intervalNum)).
+		TableExpr("drop_report_daily_rollups AS dr").
+		Join("JOIN intervals AS sub ON dr.day_start >= sub.interval_start AND dr.day_start < sub.interval_end").
+		Column("sub.group_id", "sub.interval_start", "sub.interval_end", "dr.stage_id").
+		ColumnExpr("SUM(dr.times) AS total_times").
+		Where("dr.item_id = 0")
+	if intervalLength == time.Hour*24 && (sourceCategory == constant.SourceCategoryManual || sourceCategory == constant.SourceCategoryAutomated) {
+		// the reporters of a day from the sources of a category are those of its rollup
+		query.ColumnExpr("SUM(dr.reporters) AS reporters")
+	} else {
+		query.ColumnExpr("(?) AS reporters", r.rollupReporters(server, sourceCategory, "sub.interval_start", "sub.interval_end"))
+	}
+	r.handleServer(query, server)
+	r.handleRollupSourceCategory(query, sourceCategory)
+	r.handleStages(query, stageIds)
+
+	if err := query.
+		Group("sub.group_id", "sub.interval_start", "sub.interval_end", "dr.stage_id").
+		Scan(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CalcDropMatrixAggregatesFromRollups is CalcDropMatrixAggregates for the global reports within the game days
+// starting from start until end, which reads the daily rollups instead of the reports
+func (r *DropReport) CalcDropMatrixAggregatesFromRollups(
+	ctx context.Context, server string, start time.Time, end time.Time, stageItemFilter *map[int][]int, sourceCategory string,
+) (_ []*model.CombinedResultForDropMatrix, err error) {
+	ctx, span := tracer.Start(ctx, "DropReport.CalcDropMatrixAggregatesFromRollups", trace.WithAttributes(
+		attribute.String("server", server),
+		attribute.String("sourceCategory", sourceCategory),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+
+	results := make([]*model.CombinedResultForDropMatrix, 0)
+
+	itemFilter, ok := dropMatrixItemFilter(stageItemFilter)
+	if !ok {
+		return results, nil
+	}
+
+	uniqq := r.conn(ctx).NewSelect().
+		TableExpr("drop_report_daily_rollups AS dr").
+		Join("CROSS JOIN LATERAL jsonb_each_text(dr.quantity_buckets) AS b").
+		Column("dr.stage_id", "dr.item_id").
+		ColumnExpr("b.key::int AS quantity").
+		ColumnExpr("SUM(b.value::int) AS count").
+		Where("dr.item_id <> 0")
+	timesq := r.conn(ctx).NewSelect().
+		TableExpr("drop_report_daily_rollups AS dr").
+		Column("dr.stage_id").
+		ColumnExpr("SUM(dr.times) AS times").
+		ColumnExpr("(?) AS reporters", r.rollupReporters(server, sourceCategory, "?", "?", start, end)).
+		Where("dr.item_id = 0")
+	for _, q := range []*bun.SelectQuery{uniqq, timesq} {
+		q.Where("dr.day_start >= ?", start).Where("dr.day_start < ?", end)
+		r.handleServer(q, server)
+		r.handleRollupSourceCategory(q, sourceCategory)
+	}
+	r.handleStagesAndItemsOn(uniqq, "dr.stage_id", "dr.item_id", itemFilter)
+	if stageItemFilter != nil {
+		r.handleStages(timesq, lo.Keys(*stageItemFilter))
+	}
+
+	if err := selectDropMatrixAggregates(r.conn(ctx),
+		uniqq.Group("dr.stage_id", "dr.item_id", "b.key"),
+		timesq.Group("dr.stage_id"),
+	).Scan(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// rollupReporters counts the distinct accounts having reported the stage of the rollups aliased as dr from the days
+// starting from startExpr until endExpr, of which the placeholders are bound to args
+func (r *DropReport) rollupReporters(server string, sourceCategory string, startExpr string, endExpr string, args ...any) *bun.SelectQuery {
+	query := r.db.NewSelect().
+		TableExpr("drop_report_daily_reporters AS rp").
+		ColumnExpr("COUNT(DISTINCT rp.account_id)").
+		Where("rp.server = ?", server).
+		Where("rp.stage_id = dr.stage_id").
+		Where("rp.day_start >= "+startExpr+" AND rp.day_start < "+endExpr, args...)
+	if sourceCategory == constant.SourceCategoryManual || sourceCategory == constant.SourceCategoryAutomated {
+		query.Where("rp.source_category = ?", sourceCategory)
+	}
+	return query
+}
+
 func (r *DropReport) CalcTotalStageQuantityForShimSiteStats(ctx context.Context, server string, isRecent24h bool) ([]*modelv2.TotalStageTime, error) {
 	results := make([]*modelv2.TotalStageTime, 0)
 
 	err := pgqry.New(
-		r.db.NewSelect().
+		r.conn(ctx).NewSelect().
 			TableExpr("drop_reports AS dr").
 			Column("st.ark_stage_id").
 			ColumnExpr("SUM(dr.times) AS total_times").
 			Where("dr.reliability = 0 AND dr.server = ? AND st.ark_stage_id != ?", server, constant.RecruitStageID).
+			Where(NotExcludedByRules).
 			Apply(func(sq *bun.SelectQuery) *bun.SelectQuery {
 				if isRecent24h {
 					return sq.Where("dr.created_at >= now() - interval '24 hours'")
@@ -254,9 +683,63 @@ func (r *DropReport) CalcTotalStageQuantityForShimSiteStats(ctx context.Context,
 	return results, nil
 }
 
+// CalcContributionsByZones ranks the accounts opted in to the leaderboards by their reports of the given reliabilities
+// for the stages of the zones in the server since start, and before end unless nil. orderBy is either reports or sanity.
+func (r *DropReport) CalcContributionsByZones(
+	ctx context.Context, server string, zoneIds []int, start time.Time, end *time.Time, reliabilities []int, orderBy string, limit int,
+) ([]*model.ContributionResult, error) {
+	results := make([]*model.ContributionResult, 0)
+	if len(zoneIds) == 0 {
+		return results, nil
+	}
+
+	query := r.conn(ctx).NewSelect().
+		TableExpr("drop_reports AS dr").
+		Join("JOIN stages AS st ON st.stage_id = dr.stage_id").
+		Join("JOIN accounts AS a ON a.account_id = dr.account_id").
+		Column("dr.account_id").
+		ColumnExpr("a.leaderboard_name AS name").
+		ColumnExpr("COUNT(*) AS reports").
+		ColumnExpr("SUM(dr.times) AS times").
+		ColumnExpr("SUM(dr.times * COALESCE(st.sanity, 0)) AS sanity").
+		Where("dr.reliability IN (?)", bun.In(reliabilities)).
+		Where("st.zone_id IN (?)", bun.In(zoneIds)).
+		Where("a.leaderboard_opt_in").
+		Where("dr.created_at >= ?", start)
+	r.handleServer(query, server)
+	if end != nil {
+		query = query.Where("dr.created_at < ?", *end)
+	}
+
+	if err := query.
+		Group("dr.account_id", "a.leaderboard_name").
+		OrderExpr("? DESC, dr.account_id ASC", bun.Ident(orderBy)).
+		Limit(limit).
+		Scan(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CountAcceptedReports counts the reports of the stages included in the global aggregations, created from start
+// until end
+func (r *DropReport) CountAcceptedReports(ctx context.Context, server string, stageIds []int, start *time.Time, end *time.Time) (int, error) {
+	if len(stageIds) == 0 {
+		return 0, nil
+	}
+
+	query := r.conn(ctx).NewSelect().
+		TableExpr("drop_reports AS dr")
+	r.handleAccountAndReliability(query, null.Int{})
+	r.handleServer(query, server)
+	r.handleStages(query, stageIds)
+	r.handleCreatedAtWithTime(query, start, end)
+	return query.Count(ctx)
+}
+
 func (r *DropReport) CalcRecentUniqueUserCountBySource(ctx context.Context, duration time.Duration) ([]*modelv2.UniqueUserCountBySource, error) {
 	results := make([]*modelv2.UniqueUserCountBySource, 0)
-	subq := r.db.NewSelect().
+	subq := r.conn(ctx).NewSelect().
 		TableExpr("drop_reports AS dr").
 		Column("dr.source_name", "dr.account_id")
 	start := time.Now().Add(-duration)
@@ -264,7 +747,7 @@ func (r *DropReport) CalcRecentUniqueUserCountBySource(ctx context.Context, dura
 	r.handleCreatedAtWithTime(subq, &start, &end)
 	subq = subq.Group("dr.source_name", "dr.account_id")
 
-	mainq := r.db.NewSelect().
+	mainq := r.conn(ctx).NewSelect().
 		TableExpr("(?) AS a", subq).
 		Column("source_name").
 		ColumnExpr("COUNT(*) AS count").
@@ -277,12 +760,142 @@ func (r *DropReport) CalcRecentUniqueUserCountBySource(ctx context.Context, dura
 	return results, nil
 }
 
+// CalcReportCountsPerAccount counts the accounts by the number of reliable reports each has of the stage, i.e. how
+// many accounts have reported the stage once, twice and so on
+func (r *DropReport) CalcReportCountsPerAccount(ctx context.Context, server string, stageId int) ([]*model.ReportsPerAccountResult, error) {
+	results := make([]*model.ReportsPerAccountResult, 0)
+	subq := r.conn(ctx).NewSelect().
+		TableExpr("drop_reports AS dr").
+		Column("dr.account_id").
+		ColumnExpr("COUNT(*) AS reports").
+		Where("dr.stage_id = ?", stageId)
+	r.handleServer(subq, server)
+	r.handleAccountAndReliability(subq, null.NewInt(0, false))
+	subq = subq.Group("dr.account_id")
+
+	mainq := r.conn(ctx).NewSelect().
+		TableExpr("(?) AS a", subq).
+		Column("reports").
+		ColumnExpr("COUNT(*) AS accounts").
+		Group("reports").
+		Order("reports")
+
+	if err := mainq.
+		Scan(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *DropReport) CalcReliabilityCountsByAccountId(ctx context.Context, accountId int, duration time.Duration) ([]*model.ReliabilityCountResult, error) {
+	results := make([]*model.ReliabilityCountResult, 0)
+	query := r.conn(ctx).NewSelect().
+		TableExpr("drop_reports AS dr").
+		Column("dr.reliability").
+		ColumnExpr("COUNT(*) AS count").
+		Where("dr.account_id = ?", accountId).
+		Where("dr.reliability >= 0")
+	start := time.Now().Add(-duration)
+	r.handleCreatedAtWithTime(query, &start, nil)
+
+	if err := query.
+		Group("dr.reliability").
+		Scan(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// HasDropReportsByAccountId tells whether the account has ever submitted a report, recalled or not
+func (r *DropReport) HasDropReportsByAccountId(ctx context.Context, accountId int) (bool, error) {
+	return r.conn(ctx).NewSelect().
+		TableExpr("drop_reports AS dr").
+		Where("dr.account_id = ?", accountId).
+		Exists(ctx)
+}
+
+// CalcHighVolumeReporters finds the accounts that have cleared a stage at least minTimes times in their aggregated
+// reports created since the given time, per server and stage
+func (r *DropReport) CalcHighVolumeReporters(ctx context.Context, since time.Time, minTimes int) ([]*model.ReporterTimesResult, error) {
+	results := make([]*model.ReporterTimesResult, 0)
+	err := r.conn(ctx).NewSelect().
+		TableExpr("drop_reports AS dr").
+		Column("dr.server", "dr.stage_id", "dr.account_id").
+		ColumnExpr("SUM(dr.times) AS times").
+		Where("dr.created_at >= ?", since).
+		Where("dr.account_id IS NOT NULL").
+		Where("dr.reliability = 0").
+		Where(NotExcludedByRules).
+		Group("dr.server", "dr.stage_id", "dr.account_id").
+		Having("SUM(dr.times) >= ?", minTimes).
+		Scan(ctx, &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CalcStageTimesByAccounts sums the times the stage has been cleared in the aggregated reports created since the
+// given time per account of accountIds, and those of every other account together as account 0
+func (r *DropReport) CalcStageTimesByAccounts(ctx context.Context, server string, stageId int, since time.Time, accountIds []int) ([]*model.ReporterTimesResult, error) {
+	results := make([]*model.ReporterTimesResult, 0)
+	err := r.conn(ctx).NewSelect().
+		TableExpr("drop_reports AS dr").
+		ColumnExpr("CASE WHEN dr.account_id IN (?) THEN dr.account_id ELSE 0 END AS account_id", bun.In(accountIds)).
+		ColumnExpr("SUM(dr.times) AS times").
+		Where("dr.server = ?", server).
+		Where("dr.stage_id = ?", stageId).
+		Where("dr.created_at >= ?", since).
+		Where("dr.reliability = 0").
+		Where(NotExcludedByRules).
+		GroupExpr("1").
+		Scan(ctx, &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CalcStageItemQuantitiesByAccounts sums the quantities of the items dropped from the stage in the aggregated reports
+// created since the given time per account of accountIds, and those of every other account together as account 0
+func (r *DropReport) CalcStageItemQuantitiesByAccounts(ctx context.Context, server string, stageId int, since time.Time, accountIds []int) ([]*model.ReporterItemQuantityResult, error) {
+	results := make([]*model.ReporterItemQuantityResult, 0)
+	err := r.conn(ctx).NewSelect().
+		TableExpr("drop_reports AS dr").
+		Join("JOIN drop_pattern_elements AS dpe ON dpe.drop_pattern_id = dr.pattern_id").
+		ColumnExpr("CASE WHEN dr.account_id IN (?) THEN dr.account_id ELSE 0 END AS account_id", bun.In(accountIds)).
+		Column("dpe.item_id").
+		ColumnExpr("SUM(dpe.quantity) AS quantity").
+		Where("dr.server = ?", server).
+		Where("dr.stage_id = ?", stageId).
+		Where("dr.created_at >= ?", since).
+		Where("dr.reliability = 0").
+		Where(NotExcludedByRules).
+		GroupExpr("1, 2").
+		Scan(ctx, &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CountReportsWithoutExtras counts the drop reports created since the given time that have no extras, which are
+// otherwise always created along in the same transaction
+func (r *DropReport) CountReportsWithoutExtras(ctx context.Context, since time.Time) (int, error) {
+	return r.conn(ctx).NewSelect().
+		TableExpr("drop_reports AS dr").
+		Join("LEFT JOIN drop_report_extras AS dre ON dre.report_id = dr.report_id").
+		Where("dre.report_id IS NULL").
+		Where("dr.created_at >= ?", since).
+		Count(ctx)
+}
+
 /**
  * Only return drop reports under one stage.
  */
 func (r *DropReport) GetDropReports(ctx context.Context, queryCtx *model.DropReportQueryContext) ([]*model.DropReport, error) {
 	results := make([]*model.DropReport, 0)
-	query := r.db.NewSelect().
+	query := r.conn(ctx).NewSelect().
 		TableExpr("drop_reports AS dr").
 		Column("pattern_id", "created_at", "account_id", "source_name", "version", "times").
 		Order("created_at")
@@ -316,7 +929,7 @@ func (r *DropReport) GetDropReportsForArchive(ctx context.Context, cursor *model
 	start := time.UnixMilli(util.GetDayStartTime(&date, "CN")) // we use CN server's day start time across all servers for archive
 	end := start.Add(time.Hour * 24)
 	results := make([]*model.DropReport, 0, limit)
-	query := r.db.NewSelect().
+	query := r.conn(ctx).NewSelect().
 		Model(&results).
 		Where("created_at >= to_timestamp(?)", start.Unix()).
 		Where("created_at < to_timestamp(?)", end.Unix()).
@@ -332,6 +945,45 @@ func (r *DropReport) GetDropReportsForArchive(ctx context.Context, cursor *model
 	return results, newCursor(results), nil
 }
 
+// CountDropReportsForArchive counts the drop reports of the date, as archived by GetDropReportsForArchive
+func (r *DropReport) CountDropReportsForArchive(ctx context.Context, date time.Time) (int, error) {
+	start := time.UnixMilli(util.GetDayStartTime(&date, "CN")) // we use CN server's day start time across all servers for archive
+	end := start.Add(time.Hour * 24)
+	return r.conn(ctx).NewSelect().
+		Model((*model.DropReport)(nil)).
+		Where("created_at >= to_timestamp(?)", start.Unix()).
+		Where("created_at < to_timestamp(?)", end.Unix()).
+		Count(ctx)
+}
+
+// GetReportIdRangeForArchive returns the first and the last report ids of the date, both of which are zero when
+// there is no report on the date
+func (r *DropReport) GetReportIdRangeForArchive(ctx context.Context, date time.Time) (first int, last int, err error) {
+	start := time.UnixMilli(util.GetDayStartTime(&date, "CN")) // we use CN server's day start time across all servers for archive
+	end := start.Add(time.Hour * 24)
+	var idRange struct {
+		First null.Int `bun:"first"`
+		Last  null.Int `bun:"last"`
+	}
+	err = r.conn(ctx).NewSelect().
+		Model((*model.DropReport)(nil)).
+		ColumnExpr("MIN(report_id) AS first, MAX(report_id) AS last").
+		Where("created_at >= to_timestamp(?)", start.Unix()).
+		Where("created_at < to_timestamp(?)", end.Unix()).
+		Scan(ctx, &idRange)
+	return int(idRange.First.Int64), int(idRange.Last.Int64), err
+}
+
+// GetOldestDropReportTime returns when the oldest drop report in the database was created
+func (r *DropReport) GetOldestDropReportTime(ctx context.Context) (null.Time, error) {
+	var oldest null.Time
+	err := r.conn(ctx).NewSelect().
+		Model((*model.DropReport)(nil)).
+		ColumnExpr("MIN(created_at)").
+		Scan(ctx, &oldest)
+	return oldest, err
+}
+
 // DeleteDropReportsForArchive deletes drop reports for archive.
 // returns number of rows affected and error
 func (r *DropReport) DeleteDropReportsForArchive(ctx context.Context, tx bun.Tx, date time.Time) (int64, error) {
@@ -349,18 +1001,22 @@ func (r *DropReport) DeleteDropReportsForArchive(ctx context.Context, tx bun.Tx,
 }
 
 func (r *DropReport) handleStagesAndItems(query *bun.SelectQuery, stageIdItemIdMap map[int][]int) {
+	r.handleStagesAndItemsOn(query, "dr.stage_id", "dpe.item_id", stageIdItemIdMap)
+}
+
+func (r *DropReport) handleStagesAndItemsOn(query *bun.SelectQuery, stageColumn, itemColumn string, stageIdItemIdMap map[int][]int) {
 	stageConditions := make([]string, 0)
 	for stageId, itemIds := range stageIdItemIdMap {
 		var stageB strings.Builder
-		fmt.Fprintf(&stageB, "dr.stage_id = %d", stageId)
+		fmt.Fprintf(&stageB, "%s = %d", stageColumn, stageId)
 		if len(itemIds) == 1 {
-			fmt.Fprintf(&stageB, " AND dpe.item_id = %d", itemIds[0])
+			fmt.Fprintf(&stageB, " AND %s = %d", itemColumn, itemIds[0])
 		} else if len(itemIds) > 1 {
 			var itemIdsStr []string
 			for _, itemId := range itemIds {
 				itemIdsStr = append(itemIdsStr, strconv.Itoa(itemId))
 			}
-			fmt.Fprintf(&stageB, " AND dpe.item_id IN (%s)", strings.Join(itemIdsStr, ","))
+			fmt.Fprintf(&stageB, " AND %s IN (%s)", itemColumn, strings.Join(itemIdsStr, ","))
 		}
 		stageConditions = append(stageConditions, stageB.String())
 	}
@@ -391,7 +1047,7 @@ func (r *DropReport) handleAccountAndReliability(query *bun.SelectQuery, account
 	if accountId.Valid {
 		query = query.Where("dr.reliability >= 0 AND dr.account_id = ?", accountId.Int64)
 	} else {
-		query = query.Where("dr.reliability = 0")
+		query = query.Where("dr.reliability = 0").Where(NotExcludedByRules)
 	}
 }
 
@@ -421,6 +1077,14 @@ func (r *DropReport) handleTimes(query *bun.SelectQuery, times int) {
 	query = query.Where("dr.times = ?", times)
 }
 
+// handleRollupSourceCategory is handleSourceName for the daily rollups aliased as dr, which are rolled up by the
+// source category instead of the source name
+func (r *DropReport) handleRollupSourceCategory(query *bun.SelectQuery, sourceCategory string) {
+	if sourceCategory == constant.SourceCategoryManual || sourceCategory == constant.SourceCategoryAutomated {
+		query.Where("dr.source_category = ?", sourceCategory)
+	}
+}
+
 func (r *DropReport) handleSourceName(query *bun.SelectQuery, sourceCategory string) {
 	if sourceCategory == constant.SourceCategoryManual {
 		query = query.Where("source_name IN (?)", bun.In(constant.ManualSources))
@@ -431,12 +1095,13 @@ func (r *DropReport) handleSourceName(query *bun.SelectQuery, sourceCategory str
 	}
 }
 
-func (r *DropReport) genSubQueryForTrendSegments(gameDayStart time.Time, intervalLength time.Duration, intervalNum int) *bun.SelectQuery {
+// trendSegments generates the intervals of a trend starting from gameDayStart, numbered by group_id
+func trendSegments(db bun.IDB, gameDayStart time.Time, intervalLength time.Duration, intervalNum int) *bun.SelectQuery {
 	var subQueryExprBuilder strings.Builder
 	fmt.Fprintf(&subQueryExprBuilder, "to_timestamp(?) + (n || ' hours')::interval AS interval_start, ")
 	fmt.Fprintf(&subQueryExprBuilder, "to_timestamp(?) + ((n + ?) || ' hours')::interval AS interval_end, ")
 	fmt.Fprintf(&subQueryExprBuilder, "(n / ?) AS group_id")
-	return r.db.NewSelect().
+	return db.NewSelect().
 		TableExpr("generate_series(?, ? * ?, ?) AS n", 0, int(intervalLength.Hours()), intervalNum, int(intervalLength.Hours())).
 		ColumnExpr(subQueryExprBuilder.String(),
 			gameDayStart.Unix(),
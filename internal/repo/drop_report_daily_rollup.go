@@ -0,0 +1,243 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/pkg/gameday"
+	"exusiai.dev/backend-next/internal/pkg/pgpool"
+)
+
+// rollupSourceCategoryExpr is the source category of the reports aliased as dr in the rollups, of which the
+// placeholders are the manual sources, the manual category and the automated category
+const rollupSourceCategoryExpr = "CASE WHEN dr.source_name IN (?) THEN ? ELSE ? END"
+
+// rollupConflict is the conflict target of the rows of drop_report_daily_rollups
+const rollupConflict = "(server, day_start, stage_id, item_id, source_category)"
+
+// rollupMergeBuckets adds the counts of the excluded quantity buckets to those of the row aliased as ro, leaving out
+// the quantities no report is left with
+const rollupMergeBuckets = `(SELECT COALESCE(jsonb_object_agg(m.key, m.count), '{}') FROM (
+		SELECT b.key, SUM(b.value::int) AS count
+		FROM (SELECT * FROM jsonb_each_text(ro.quantity_buckets) UNION ALL SELECT * FROM jsonb_each_text(EXCLUDED.quantity_buckets)) AS b
+		GROUP BY b.key
+	) AS m WHERE m.count <> 0)`
+
+// DropReportDailyRollup maintains drop_report_daily_rollups and drop_report_daily_reporters, which roll the reliable
+// reports not excluded by any rule up by the game day of their server. The reports are added upon ingestion and
+// removed upon recall one by one, while the days of the reports of which the reliability or the exclusion changes in
+// bulk are rebuilt from the reports.
+type DropReportDailyRollup struct {
+	pools *pgpool.Pools
+}
+
+func NewDropReportDailyRollup(pools *pgpool.Pools) *DropReportDailyRollup {
+	return &DropReportDailyRollup{pools: pools}
+}
+
+// RollupSourceCategory is the source category the reports of the source are rolled up into
+func RollupSourceCategory(sourceName string) string {
+	if lo.Contains(constant.ManualSources, sourceName) {
+		return constant.SourceCategoryManual
+	}
+	return constant.SourceCategoryAutomated
+}
+
+// AddReports adds the reports created within tx to the rollups of their days, leaving out those which are not
+// reliable or are excluded by a rule. The rows are updated in the order of the stages, for the concurrent ingestions
+// to lock them in the same order.
+func (r *DropReportDailyRollup) AddReports(ctx context.Context, tx bun.Tx, reports []*model.DropReport) error {
+	reports = append([]*model.DropReport(nil), reports...)
+	sort.SliceStable(reports, func(i, j int) bool { return reports[i].StageID < reports[j].StageID })
+	for _, report := range reports {
+		counted, err := r.counted(ctx, tx, report)
+		if err != nil {
+			return err
+		}
+		if !counted {
+			continue
+		}
+
+		res, err := tx.NewRaw(`INSERT INTO drop_report_daily_reporters (server, day_start, stage_id, source_category, account_id)
+			VALUES (?, ?, ?, ?, ?) ON CONFLICT DO NOTHING`,
+			report.Server, rollupDayStart(report), report.StageID, RollupSourceCategory(report.SourceName), report.AccountID,
+		).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		newReporters, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if err := r.apply(ctx, tx, report, 1, int(newReporters)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveReport removes the report from the rollup of its day, unless it was left out of it. report is the report as
+// it was before it stopped being reliable within tx, e.g. by RecallDropReport.
+func (r *DropReportDailyRollup) RemoveReport(ctx context.Context, tx bun.Tx, report *model.DropReport) error {
+	counted, err := r.counted(ctx, tx, report)
+	if err != nil || !counted {
+		return err
+	}
+
+	// the account stays a reporter of the day as long as another report of it is counted
+	dayStart := rollupDayStart(report)
+	category := RollupSourceCategory(report.SourceName)
+	var reported bool
+	if err := tx.NewSelect().
+		TableExpr("drop_reports AS dr").
+		ColumnExpr("1").
+		Where("dr.server = ?", report.Server).
+		Where("dr.stage_id = ?", report.StageID).
+		Where("dr.account_id = ?", report.AccountID).
+		Where("dr.created_at >= ?", dayStart).
+		Where("dr.created_at < ?", gameday.NextStartTime(report.Server, dayStart)).
+		Where("dr.reliability = 0").
+		Where("dr.report_id <> ?", report.ReportID).
+		Where(rollupSourceCategoryExpr+" = ?", bun.In(constant.ManualSources), constant.SourceCategoryManual, constant.SourceCategoryAutomated, category).
+		Where(NotExcludedByRules).
+		Limit(1).
+		Scan(ctx, new(int)); err == nil {
+		reported = true
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	removedReporters := int64(0)
+	if !reported {
+		res, err := tx.NewRaw(`DELETE FROM drop_report_daily_reporters
+			WHERE server = ? AND day_start = ? AND stage_id = ? AND source_category = ? AND account_id = ?`,
+			report.Server, dayStart, report.StageID, category, report.AccountID,
+		).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if removedReporters, err = res.RowsAffected(); err != nil {
+			return err
+		}
+	}
+	return r.apply(ctx, tx, report, -1, -int(removedReporters))
+}
+
+// RebuildDay rolls the reports of the game day of the server starting at dayStart up over again
+func (r *DropReportDailyRollup) RebuildDay(ctx context.Context, server string, dayStart time.Time) error {
+	dayEnd := gameday.NextStartTime(server, dayStart)
+	categoryArgs := []any{bun.In(constant.ManualSources), constant.SourceCategoryManual, constant.SourceCategoryAutomated}
+	where := "dr.server = ? AND dr.created_at >= ? AND dr.created_at < ? AND dr.reliability = 0 AND " + NotExcludedByRules
+	whereArgs := []any{server, dayStart, dayEnd}
+	args := func(groups ...[]any) []any {
+		return lo.Flatten(groups)
+	}
+
+	// the rows are rewritten rather than added to, for the reports ingested in the meantime, which the statements
+	// see as soon as they are committed, not to be counted twice
+	statements := []struct {
+		query string
+		args  []any
+	}{
+		{
+			`DELETE FROM drop_report_daily_reporters WHERE server = ? AND day_start = ?`,
+			[]any{server, dayStart},
+		},
+		{
+			`INSERT INTO drop_report_daily_reporters (server, day_start, stage_id, source_category, account_id)
+			SELECT DISTINCT dr.server, ?::timestamptz, dr.stage_id, ` + rollupSourceCategoryExpr + `, dr.account_id
+			FROM drop_reports AS dr
+			WHERE ` + where + `
+			ON CONFLICT DO NOTHING`,
+			args([]any{dayStart}, categoryArgs, whereArgs),
+		},
+		{
+			`DELETE FROM drop_report_daily_rollups WHERE server = ? AND day_start = ?`,
+			[]any{server, dayStart},
+		},
+		{
+			`INSERT INTO drop_report_daily_rollups AS ro (server, day_start, stage_id, item_id, source_category, times, reporters)
+			SELECT dr.server, ?::timestamptz, dr.stage_id, 0, ` + rollupSourceCategoryExpr + `, SUM(dr.times), COUNT(DISTINCT dr.account_id)
+			FROM drop_reports AS dr
+			WHERE ` + where + `
+			GROUP BY dr.server, dr.stage_id, 5
+			ON CONFLICT ` + rollupConflict + ` DO UPDATE SET times = EXCLUDED.times, reporters = EXCLUDED.reporters`,
+			args([]any{dayStart}, categoryArgs, whereArgs),
+		},
+		{
+			`INSERT INTO drop_report_daily_rollups AS ro (server, day_start, stage_id, item_id, source_category, quantity, quantity_buckets)
+			SELECT u.server, ?::timestamptz, u.stage_id, u.item_id, u.source_category, SUM(u.quantity * u.count), jsonb_object_agg(u.quantity, u.count)
+			FROM (
+				SELECT dr.server, dr.stage_id, dpe.item_id, ` + rollupSourceCategoryExpr + ` AS source_category, dpe.quantity, COUNT(*) AS count
+				FROM drop_reports AS dr
+				JOIN drop_pattern_elements AS dpe ON dpe.drop_pattern_id = dr.pattern_id
+				WHERE ` + where + `
+				GROUP BY dr.server, dr.stage_id, dpe.item_id, 4, dpe.quantity
+			) AS u
+			GROUP BY u.server, u.stage_id, u.item_id, u.source_category
+			ON CONFLICT ` + rollupConflict + ` DO UPDATE SET quantity = EXCLUDED.quantity, quantity_buckets = EXCLUDED.quantity_buckets`,
+			args([]any{dayStart}, categoryArgs, whereArgs),
+		},
+	}
+
+	return r.pools.DB(ctx).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, statement := range statements {
+			if _, err := tx.ExecContext(ctx, statement.query, statement.args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// counted tells whether the report is rolled up, i.e. it is reliable and not excluded by any rule
+func (r *DropReportDailyRollup) counted(ctx context.Context, tx bun.Tx, report *model.DropReport) (bool, error) {
+	if report.Reliability != 0 {
+		return false, nil
+	}
+	var counted bool
+	err := tx.NewSelect().
+		TableExpr("drop_reports AS dr").
+		ColumnExpr(NotExcludedByRules).
+		Where("dr.report_id = ?", report.ReportID).
+		Scan(ctx, &counted)
+	return counted, err
+}
+
+// apply adds the report to the rollup of its day, or removes it with a sign of -1, along with the number of reporters
+// it adds or removes
+func (r *DropReportDailyRollup) apply(ctx context.Context, tx bun.Tx, report *model.DropReport, sign int, reporters int) error {
+	dayStart := rollupDayStart(report)
+	category := RollupSourceCategory(report.SourceName)
+
+	if _, err := tx.NewRaw(`INSERT INTO drop_report_daily_rollups AS ro (server, day_start, stage_id, item_id, source_category, times, reporters)
+		VALUES (?, ?, ?, 0, ?, ?, ?)
+		ON CONFLICT `+rollupConflict+` DO UPDATE SET times = ro.times + EXCLUDED.times, reporters = ro.reporters + EXCLUDED.reporters`,
+		report.Server, dayStart, report.StageID, category, sign*report.Times, reporters,
+	).Exec(ctx); err != nil {
+		return err
+	}
+
+	_, err := tx.NewRaw(`INSERT INTO drop_report_daily_rollups AS ro (server, day_start, stage_id, item_id, source_category, quantity, quantity_buckets)
+		SELECT ?, ?, ?, dpe.item_id, ?, ? * dpe.quantity, jsonb_build_object(dpe.quantity, ?)
+		FROM drop_pattern_elements AS dpe
+		WHERE dpe.drop_pattern_id = ?
+		ORDER BY dpe.item_id
+		ON CONFLICT `+rollupConflict+` DO UPDATE SET quantity = ro.quantity + EXCLUDED.quantity, quantity_buckets = `+rollupMergeBuckets,
+		report.Server, dayStart, report.StageID, category, sign, sign, report.PatternID,
+	).Exec(ctx)
+	return err
+}
+
+// rollupDayStart is the start of the game day the report is rolled up into
+func rollupDayStart(report *model.DropReport) time.Time {
+	return gameday.StartTime(report.Server, *report.CreatedAt)
+}
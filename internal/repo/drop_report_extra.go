@@ -6,16 +6,18 @@ import (
 	"github.com/uptrace/bun"
 
 	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/pkg/pgpool"
 	"exusiai.dev/backend-next/internal/repo/selector"
 )
 
 type DropReportExtra struct {
-	db  *bun.DB
-	sel selector.S[model.DropReportExtra]
+	db    *bun.DB
+	pools *pgpool.Pools
+	sel   selector.S[model.DropReportExtra]
 }
 
-func NewDropReportExtra(db *bun.DB) *DropReportExtra {
-	return &DropReportExtra{db: db, sel: selector.New[model.DropReportExtra](db)}
+func NewDropReportExtra(db *bun.DB, pools *pgpool.Pools) *DropReportExtra {
+	return &DropReportExtra{db: db, pools: pools, sel: selector.New[model.DropReportExtra](db)}
 }
 
 func (r *DropReportExtra) GetDropReportExtraById(ctx context.Context, id int) (*model.DropReportExtra, error) {
@@ -24,10 +26,16 @@ func (r *DropReportExtra) GetDropReportExtraById(ctx context.Context, id int) (*
 	})
 }
 
+func (r *DropReportExtra) GetDropReportExtrasByIds(ctx context.Context, ids []int) ([]*model.DropReportExtra, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("report_id IN (?)", bun.In(ids))
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
 func (c *DropReportExtra) GetDropReportExtraForArchive(ctx context.Context, cursor *model.Cursor, idInclusiveStart int, idInclusiveEnd int, limit int) ([]*model.DropReportExtra, model.Cursor, error) {
 	dropReportExtras := make([]*model.DropReportExtra, 0)
 
-	query := c.db.NewSelect().
+	query := c.pools.DB(ctx).NewSelect().
 		Model(&dropReportExtras).
 		Where("report_id >= ?", idInclusiveStart).
 		Where("report_id <= ?", idInclusiveEnd).
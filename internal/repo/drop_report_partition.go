@@ -0,0 +1,117 @@
+package repo
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// dropReportPartitionPrefix prefixes the monthly partitions of drop_reports, e.g. drop_reports_p202610, each of which
+// is sub-partitioned by server, e.g. drop_reports_p202610_cn
+const dropReportPartitionPrefix = "drop_reports_p"
+
+// DropReportPartition manages the partitions of drop_reports, which is partitioned by the month of created_at in UTC
+// and then by server once the partition_drop_reports script has been run
+type DropReportPartition struct {
+	db *bun.DB
+}
+
+func NewDropReportPartition(db *bun.DB) *DropReportPartition {
+	return &DropReportPartition{db: db}
+}
+
+func DropReportPartitionName(month time.Time) string {
+	return dropReportPartitionPrefix + month.UTC().Format("200601")
+}
+
+func dropReportMonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// IsPartitioned tells whether drop_reports has been partitioned
+func (r *DropReportPartition) IsPartitioned(ctx context.Context) (bool, error) {
+	var partitioned bool
+	err := r.db.NewRaw("SELECT EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = to_regclass('drop_reports'))").
+		Scan(ctx, &partitioned)
+	return partitioned, err
+}
+
+// CreatePartitions creates the partitions of every month from the month of from to the month of to, both inclusive,
+// along with their sub-partitions of the servers. The partitions that already exist are left as is.
+func (r *DropReportPartition) CreatePartitions(ctx context.Context, db bun.IDB, from, to time.Time, servers []string) error {
+	for month := dropReportMonthStart(from); !month.After(to); month = month.AddDate(0, 1, 0) {
+		name := DropReportPartitionName(month)
+		if _, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS ? PARTITION OF drop_reports FOR VALUES FROM (?) TO (?) PARTITION BY LIST (server)",
+			bun.Ident(name), month, month.AddDate(0, 1, 0)); err != nil {
+			return err
+		}
+		for _, server := range servers {
+			if _, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS ? PARTITION OF ? FOR VALUES IN (?)",
+				bun.Ident(name+"_"+strings.ToLower(server)), bun.Ident(name), server); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetPartitionMonths returns the months of the existing partitions of drop_reports in chronological order
+func (r *DropReportPartition) GetPartitionMonths(ctx context.Context) ([]time.Time, error) {
+	var names []string
+	if err := r.db.NewRaw("SELECT c.relname FROM pg_inherits AS i JOIN pg_class AS c ON c.oid = i.inhrelid WHERE i.inhparent = to_regclass('drop_reports')").
+		Scan(ctx, &names); err != nil {
+		return nil, err
+	}
+	months := make([]time.Time, 0, len(names))
+	for _, name := range names {
+		if !strings.HasPrefix(name, dropReportPartitionPrefix) {
+			continue
+		}
+		month, err := time.Parse("200601", strings.TrimPrefix(name, dropReportPartitionPrefix))
+		if err != nil {
+			// not one of the monthly partitions, e.g. created by hand
+			continue
+		}
+		months = append(months, month)
+	}
+	sort.Slice(months, func(i, j int) bool { return months[i].Before(months[j]) })
+	return months, nil
+}
+
+// dropReportReferrers are the tables referencing drop_reports by report_id. As a partitioned drop_reports cannot be
+// referenced by foreign keys on report_id alone, their rows are deleted along with the partitions of their reports
+// instead.
+var dropReportReferrers = []string{"drop_report_extras"}
+
+// IsDropReportReferrer tells whether the rows of the table referencing drop_reports are deleted along with the
+// partitions of their reports
+func IsDropReportReferrer(table string) bool {
+	for _, referrer := range dropReportReferrers {
+		if referrer == table {
+			return true
+		}
+	}
+	return false
+}
+
+// DropPartition detaches the partition of drop_reports of the month and drops it, along with the rows referencing
+// its reports. Dropping a partition is far cheaper than deleting its reports.
+func (r *DropReportPartition) DropPartition(ctx context.Context, month time.Time) error {
+	name := bun.Ident(DropReportPartitionName(month))
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, referrer := range dropReportReferrers {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM ? WHERE report_id IN (SELECT report_id FROM ?)", bun.Ident(referrer), name); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.ExecContext(ctx, "ALTER TABLE drop_reports DETACH PARTITION ?", name); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "DROP TABLE ?", name)
+		return err
+	})
+}
@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+)
+
+type DropReportTask struct {
+	db *bun.DB
+}
+
+func NewDropReportTask(db *bun.DB) *DropReportTask {
+	return &DropReportTask{db: db}
+}
+
+// ClaimDropReportTask claims the task within tx, returning false if it has been claimed already, i.e. its reports
+// have been persisted by an earlier delivery of it. A concurrent delivery waits for the claim of the other to be
+// committed or rolled back.
+func (r *DropReportTask) ClaimDropReportTask(ctx context.Context, tx bun.Tx, taskId string) (bool, error) {
+	res, err := tx.NewInsert().
+		Model(&model.DropReportTask{TaskID: taskId}).
+		On("CONFLICT (task_id) DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	claimed, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return claimed > 0, nil
+}
+
+// DeleteDropReportTasksBefore removes the claims of the tasks claimed before t, and returns how many there were
+func (r *DropReportTask) DeleteDropReportTasksBefore(ctx context.Context, t time.Time) (int64, error) {
+	res, err := r.db.NewDelete().
+		Model((*model.DropReportTask)(nil)).
+		Where("created_at < ?", t).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo/selector"
+)
+
+type Event struct {
+	db  *bun.DB
+	sel selector.S[model.Event]
+}
+
+func NewEvent(db *bun.DB) *Event {
+	return &Event{db: db, sel: selector.New[model.Event](db)}
+}
+
+func (r *Event) GetEvents(ctx context.Context) ([]*model.Event, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Relation("Schedules").Order("ev.event_id ASC")
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+func (r *Event) GetEventById(ctx context.Context, eventId int) (*model.Event, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Relation("Schedules").Where("ev.event_id = ?", eventId)
+	})
+}
+
+// SaveEvent creates the event when it has no ID yet, or updates its name and zones otherwise
+func (r *Event) SaveEvent(ctx context.Context, tx bun.Tx, event *model.Event) error {
+	if event.EventID == 0 {
+		_, err := tx.NewInsert().
+			Model(event).
+			Column("name", "zone_ids").
+			Returning("event_id, created_at, updated_at").
+			Exec(ctx)
+		return err
+	}
+
+	_, err := tx.NewUpdate().
+		Model(event).
+		Column("name", "zone_ids").
+		Set("updated_at = current_timestamp").
+		WherePK().
+		Returning("created_at, updated_at").
+		Exec(ctx)
+	return err
+}
+
+// ReplaceEventSchedules upserts the schedules of the event and deletes those of the servers no longer scheduled
+func (r *Event) ReplaceEventSchedules(ctx context.Context, tx bun.Tx, eventId int, schedules []*model.EventSchedule) error {
+	servers := make([]string, 0, len(schedules))
+	for _, schedule := range schedules {
+		servers = append(servers, schedule.Server)
+	}
+
+	_, err := tx.NewDelete().
+		Model((*model.EventSchedule)(nil)).
+		Where("event_id = ?", eventId).
+		Where("server NOT IN (?)", bun.In(servers)).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewInsert().
+		Model(&schedules).
+		On("CONFLICT (event_id, server) DO UPDATE").
+		Exec(ctx)
+	return err
+}
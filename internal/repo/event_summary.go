@@ -0,0 +1,43 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo/selector"
+)
+
+type EventSummary struct {
+	db  *bun.DB
+	sel selector.S[model.EventSummary]
+}
+
+func NewEventSummary(db *bun.DB) *EventSummary {
+	return &EventSummary{db: db, sel: selector.New[model.EventSummary](db)}
+}
+
+func (r *EventSummary) GetEventSummary(ctx context.Context, eventId int, server string) (*model.EventSummary, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("esu.event_id = ?", eventId).Where("esu.server = ?", server)
+	})
+}
+
+// GetEventSummaryKeys returns the event and the server of every summary, leaving the summaries themselves out
+func (r *EventSummary) GetEventSummaryKeys(ctx context.Context) ([]*model.EventSummary, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Column("esu.event_id", "esu.server", "esu.created_at")
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+// CreateEventSummary inserts the summary unless the event has been summarized in the server already, as the summaries
+// are frozen once created
+func (r *EventSummary) CreateEventSummary(ctx context.Context, summary *model.EventSummary) error {
+	_, err := r.db.NewInsert().
+		Model(summary).
+		Column("event_id", "server", "summary").
+		On("CONFLICT (event_id, server) DO NOTHING").
+		Exec(ctx)
+	return err
+}
@@ -0,0 +1,111 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo/selector"
+)
+
+// exclusionRuleMatches matches the reports aliased as dr against the criteria of the rule aliased as er
+const exclusionRuleMatches = `(er.server IS NULL OR er.server = dr.server)
+	AND (er.account_id IS NULL OR er.account_id = dr.account_id)
+	AND (er.stage_id IS NULL OR er.stage_id = dr.stage_id)
+	AND (er.source_name IS NULL OR er.source_name = dr.source_name)
+	AND (er.version_pattern IS NULL OR dr.version LIKE er.version_pattern)
+	AND (er.start_time IS NULL OR dr.created_at >= er.start_time)
+	AND (er.end_time IS NULL OR dr.created_at < er.end_time)
+	AND (er.ip_range IS NULL OR EXISTS (
+		SELECT 1 FROM drop_report_extras AS dre WHERE dre.report_id = dr.report_id AND dre.ip::inet <<= er.ip_range
+	))`
+
+// NotExcludedByRules is the condition on the reports aliased as dr not excluded by any of the enabled exclusion
+// rules, which every aggregation of the global reports applies
+const NotExcludedByRules = "NOT EXISTS (SELECT 1 FROM exclusion_rules AS er WHERE er.enabled AND " + exclusionRuleMatches + ")"
+
+type ExclusionRule struct {
+	db  *bun.DB
+	sel selector.S[model.ExclusionRule]
+}
+
+func NewExclusionRule(db *bun.DB) *ExclusionRule {
+	return &ExclusionRule{db: db, sel: selector.New[model.ExclusionRule](db)}
+}
+
+func (r *ExclusionRule) GetExclusionRules(ctx context.Context) ([]*model.ExclusionRule, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Order("er.rule_id ASC")
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+func (r *ExclusionRule) GetExclusionRuleById(ctx context.Context, ruleId int) (*model.ExclusionRule, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("er.rule_id = ?", ruleId)
+	})
+}
+
+// GetExpiredExclusionRules returns the enabled rules of which the expiry has passed
+func (r *ExclusionRule) GetExpiredExclusionRules(ctx context.Context) ([]*model.ExclusionRule, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.
+			Where("er.enabled").
+			Where("er.expires_at <= current_timestamp").
+			Order("er.rule_id ASC")
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+// SaveExclusionRule creates the rule when it has no ID yet, or updates it otherwise
+func (r *ExclusionRule) SaveExclusionRule(ctx context.Context, rule *model.ExclusionRule) error {
+	columns := []string{
+		"name", "comment", "enabled", "server", "account_id", "ip_range", "source_name", "version_pattern",
+		"stage_id", "start_time", "end_time", "expires_at",
+	}
+	if rule.RuleID == 0 {
+		_, err := r.db.NewInsert().
+			Model(rule).
+			Column(columns...).
+			Returning("rule_id, created_at, updated_at").
+			Exec(ctx)
+		return err
+	}
+
+	_, err := r.db.NewUpdate().
+		Model(rule).
+		Column(columns...).
+		Set("updated_at = current_timestamp").
+		WherePK().
+		Returning("created_at, updated_at").
+		Exec(ctx)
+	return err
+}
+
+func (r *ExclusionRule) DeleteExclusionRule(ctx context.Context, ruleId int) error {
+	_, err := r.db.NewDelete().
+		Model((*model.ExclusionRule)(nil)).
+		Where("rule_id = ?", ruleId).
+		Exec(ctx)
+	return err
+}
+
+// CountMatchingReports counts the accepted reports matching the criteria of the rule per server and stage, whether the
+// rule is enabled or not
+func (r *ExclusionRule) CountMatchingReports(ctx context.Context, ruleId int) ([]*model.ReliabilityChangeResult, error) {
+	results := make([]*model.ReliabilityChangeResult, 0)
+	err := r.db.NewSelect().
+		TableExpr("drop_reports AS dr").
+		Join("JOIN exclusion_rules AS er ON er.rule_id = ?", ruleId).
+		Column("dr.server", "dr.stage_id").
+		ColumnExpr("COUNT(*) AS count").
+		ColumnExpr("MIN(dr.created_at) AS min_time").
+		ColumnExpr("MAX(dr.created_at) AS max_time").
+		Where("dr.reliability = 0").
+		Where(exclusionRuleMatches).
+		Group("dr.server", "dr.stage_id").
+		Scan(ctx, &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,99 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model"
+	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+)
+
+// The interfaces below describe the repositories the read-side services depend on, so that the aggregation math of the
+// services can be unit tested against the mocks in internal/repo/mocks rather than a live Postgres. Regenerate the
+// mocks with `make mocks`, as configured in .mockery.yaml, after changing any of them.
+
+var (
+	_ DropReportRepo = (*DropReport)(nil)
+	_ DropInfoRepo   = (*DropInfo)(nil)
+	_ TimeRangeRepo  = (*TimeRange)(nil)
+	_ StageRepo      = (*Stage)(nil)
+	_ ItemRepo       = (*Item)(nil)
+)
+
+// DropReportRepo is implemented by DropReport, querying the drop reports for the aggregations and archives.
+type DropReportRepo interface {
+	CalcDropMatrixAggregates(ctx context.Context, queryCtx *model.DropReportQueryContext) ([]*model.CombinedResultForDropMatrix, error)
+	CalcDropMatrixAggregatesForRange(ctx context.Context, server string, rangeId int, stageItemFilter *map[int][]int, sourceCategory string) ([]*model.CombinedResultForDropMatrix, error)
+	CalcDropMatrixAggregatesFromRollups(ctx context.Context, server string, start time.Time, end time.Time, stageItemFilter *map[int][]int, sourceCategory string) ([]*model.CombinedResultForDropMatrix, error)
+	CalcReportersForTimeRange(ctx context.Context, server string, timeRange *model.TimeRange, stageIds []int, sourceCategory string) ([]*model.ReportersResult, error)
+	CalcTotalQuantityForPatternMatrix(ctx context.Context, queryCtx *model.DropReportQueryContext) ([]*model.TotalQuantityResultForPatternMatrix, error)
+	CalcTotalTimes(ctx context.Context, queryCtx *model.DropReportQueryContext) ([]*model.TotalTimesResult, error)
+	CalcTotalQuantityForTrend(ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIdItemIdMap map[int][]int, accountId null.Int, sourceCategory string) ([]*model.TotalQuantityResultForTrend, error)
+	CalcTotalTimesForTrend(ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIds []int, accountId null.Int, sourceCategory string) ([]*model.TotalTimesResultForTrend, error)
+	CalcTotalQuantityForTrendFromRollups(ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIdItemIdMap map[int][]int, sourceCategory string) ([]*model.TotalQuantityResultForTrend, error)
+	CalcTotalTimesForTrendFromRollups(ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIds []int, sourceCategory string) ([]*model.TotalTimesResultForTrend, error)
+	CalcTotalStageQuantityForShimSiteStats(ctx context.Context, server string, isRecent24h bool) ([]*modelv2.TotalStageTime, error)
+	CalcRecentUniqueUserCountBySource(ctx context.Context, duration time.Duration) ([]*modelv2.UniqueUserCountBySource, error)
+	GetDropReports(ctx context.Context, queryCtx *model.DropReportQueryContext) ([]*model.DropReport, error)
+	GetOldestDropReportTime(ctx context.Context) (null.Time, error)
+	CountDropReportsForArchive(ctx context.Context, date time.Time) (int, error)
+	GetReportIdRangeForArchive(ctx context.Context, date time.Time) (first int, last int, err error)
+	GetDropReportsForArchive(ctx context.Context, cursor *model.Cursor, date time.Time, limit int) ([]*model.DropReport, model.Cursor, error)
+	DeleteDropReportsForArchive(ctx context.Context, tx bun.Tx, date time.Time) (int64, error)
+}
+
+// DropInfoRepo is implemented by DropInfo, querying the drop infos of the stages.
+type DropInfoRepo interface {
+	GetDropInfosByServer(ctx context.Context, server string) ([]*model.DropInfo, error)
+	GetDropInfosByServerAndStageId(ctx context.Context, server string, stageId int) ([]*model.DropInfo, error)
+	GetDropInfosByServerAndRangeId(ctx context.Context, server string, rangeId int) ([]*model.DropInfo, error)
+	GetDropInfosWithFilters(ctx context.Context, server string, timeRanges []*model.TimeRange, stageIdFilter []int, itemIdFilter []int) ([]*model.DropInfo, error)
+	GetItemDropSetByStageIdAndRangeId(ctx context.Context, server string, stageId int, rangeId int) ([]int, error)
+	GetForTimeRangeAt(ctx context.Context, query *DropInfoQuery, t time.Time) ([]*model.DropInfo, error)
+	CloneDropInfosFromCN(ctx context.Context, originRangeId int, destRangeId int, server string) error
+}
+
+// TimeRangeRepo is implemented by TimeRange, querying the time ranges.
+type TimeRangeRepo interface {
+	GetTimeRangesByServer(ctx context.Context, server string) ([]*model.TimeRange, error)
+	GetTimeRangeById(ctx context.Context, rangeId int) (*model.TimeRange, error)
+	GetTimeRangeByServerAndName(ctx context.Context, server string, name string) (*model.TimeRange, error)
+}
+
+// StageRepo is implemented by Stage, querying the stages.
+type StageRepo interface {
+	GetStages(ctx context.Context) ([]*model.Stage, error)
+	GetStagesByZoneId(ctx context.Context, zoneId int) ([]*model.Stage, error)
+	GetStageByArkId(ctx context.Context, arkStageId string) (*model.Stage, error)
+	GetStageExtraProcessTypeByArkId(ctx context.Context, arkStageId string) (null.String, error)
+	GetGachaBoxStages(ctx context.Context) ([]*model.Stage, error)
+	SearchStageByCode(ctx context.Context, code string) (*model.Stage, error)
+	GetShimStages(ctx context.Context, server string) ([]*modelv2.Stage, error)
+	GetShimStagesForFakeTime(ctx context.Context, server string, fakeTime time.Time) ([]*modelv2.Stage, error)
+	GetShimStageByArkId(ctx context.Context, arkStageId string, server string) (*modelv2.Stage, error)
+	UpdateMinClearTime(ctx context.Context, stageId int, minClearTime null.Int) error
+	GetDeletedStages(ctx context.Context) ([]*model.Stage, error)
+	GetDeletedStageByArkId(ctx context.Context, arkStageId string) (*model.Stage, error)
+	DeleteStage(ctx context.Context, stageId int) error
+	RestoreStage(ctx context.Context, stageId int) error
+}
+
+// ItemRepo is implemented by Item, querying the items.
+type ItemRepo interface {
+	GetItems(ctx context.Context) ([]*model.Item, error)
+	GetItemByArkId(ctx context.Context, arkItemId string) (*model.Item, error)
+	SearchItemByName(ctx context.Context, name string) (*model.Item, error)
+	SearchItems(ctx context.Context, query string, limit int) ([]*model.ItemSearchResult, error)
+	GetRecruitTagItems(ctx context.Context) ([]*model.Item, error)
+	GetShimItems(ctx context.Context) ([]*modelv2.Item, error)
+	GetShimItemByArkId(ctx context.Context, itemId string) (*modelv2.Item, error)
+	CreateItem(ctx context.Context, item *model.Item) error
+	UpdateItem(ctx context.Context, item *model.Item) error
+	GetDeletedItems(ctx context.Context) ([]*model.Item, error)
+	GetDeletedItemByArkId(ctx context.Context, arkItemId string) (*model.Item, error)
+	DeleteItem(ctx context.Context, itemId int) error
+	RestoreItem(ctx context.Context, itemId int) error
+}
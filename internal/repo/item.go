@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"strings"
 
 	"exusiai.dev/gommon/constant"
 	"github.com/uptrace/bun"
@@ -43,15 +44,81 @@ func (r *Item) GetItemByArkId(ctx context.Context, arkItemId string) (*model.Ite
 	})
 }
 
+func (r *Item) CreateItem(ctx context.Context, item *model.Item) error {
+	_, err := r.db.NewInsert().
+		Model(item).
+		Returning("item_id").
+		Exec(ctx)
+	return err
+}
+
+func (r *Item) UpdateItem(ctx context.Context, item *model.Item) error {
+	_, err := r.db.NewUpdate().
+		Model(item).
+		WherePK().
+		Exec(ctx)
+	return err
+}
+
+// UpdateItemIcons updates the sprites and icon URLs of the items, all at once
+func (r *Item) UpdateItemIcons(ctx context.Context, items []*model.Item) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, item := range items {
+			_, err := tx.NewUpdate().
+				Model(item).
+				Column("sprite", "icon_url").
+				WherePK().
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetDeletedItems returns the items deleted, most recently deleted first
+func (r *Item) GetDeletedItems(ctx context.Context) ([]*model.Item, error) {
+	return r.v3sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.WhereDeleted().Order("deleted_at DESC")
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+func (r *Item) GetDeletedItemByArkId(ctx context.Context, arkItemId string) (*model.Item, error) {
+	return r.v3sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.WhereDeleted().Where("ark_item_id = ?", arkItemId)
+	})
+}
+
+// DeleteItem soft deletes the item, which keeps the drop reports and drop infos of the item intact
+func (r *Item) DeleteItem(ctx context.Context, itemId int) error {
+	_, err := r.db.NewDelete().
+		Model((*model.Item)(nil)).
+		Where("item_id = ?", itemId).
+		Exec(ctx)
+	return err
+}
+
+// RestoreItem undoes the soft deletion of the item
+func (r *Item) RestoreItem(ctx context.Context, itemId int) error {
+	_, err := r.db.NewUpdate().
+		Model((*model.Item)(nil)).
+		WhereAllWithDeleted().
+		Set("deleted_at = NULL").
+		Where("item_id = ?", itemId).
+		Exec(ctx)
+	return err
+}
+
 func (r *Item) GetShimItems(ctx context.Context) ([]*modelv2.Item, error) {
 	return r.v2sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Order("item_id ASC")
+		return q.Where("deleted_at IS NULL").Order("item_id ASC")
 	})
 }
 
 func (r *Item) GetShimItemByArkId(ctx context.Context, itemId string) (*modelv2.Item, error) {
 	return r.v2sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where("ark_item_id = ?", itemId)
+		return q.Where("ark_item_id = ?", itemId).Where("deleted_at IS NULL")
 	})
 }
 
@@ -61,6 +128,33 @@ func (r *Item) SearchItemByName(ctx context.Context, name string) (*model.Item,
 	})
 }
 
+// itemSearchDocument is the text fuzzy item searches are matched against: localized names, plus the
+// aliases and pronunciation hints in keywords. It must stay in sync with the items_search_trgm_idx index.
+const itemSearchDocument = "(\"name\"::TEXT || ' ' || COALESCE(\"keywords\"::TEXT, ''))"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// SearchItems fuzzy matches items by their localized names and aliases, best matches first
+func (r *Item) SearchItems(ctx context.Context, query string, limit int) ([]*model.ItemSearchResult, error) {
+	results := make([]*model.ItemSearchResult, 0)
+	err := r.db.NewSelect().
+		Model(&results).
+		ColumnExpr("it.*").
+		ColumnExpr("word_similarity(?, "+itemSearchDocument+") AS score", query).
+		// grouped, as the deleted items are filtered out with AND
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("? <% "+itemSearchDocument, query).
+				WhereOr(itemSearchDocument+" ILIKE ?", "%"+likeEscaper.Replace(query)+"%")
+		}).
+		OrderExpr("score DESC, sort_id ASC").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 func (r *Item) GetRecruitTagItems(ctx context.Context) ([]*model.Item, error) {
 	return r.v3sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
 		return q.Where("type = ?", constant.RecruitItemType).Order("item_id ASC")
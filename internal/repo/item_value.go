@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo/selector"
+)
+
+type ItemValue struct {
+	db  *bun.DB
+	sel selector.S[model.ItemValue]
+}
+
+func NewItemValue(db *bun.DB) *ItemValue {
+	return &ItemValue{db: db, sel: selector.New[model.ItemValue](db)}
+}
+
+// GetItemValues returns the item values of every preset of the server, or of every server when server is empty
+func (r *ItemValue) GetItemValues(ctx context.Context, server string) ([]*model.ItemValue, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		if server != "" {
+			q = q.Where("iv.server = ?", server)
+		}
+		return q.Order("iv.preset ASC", "iv.server ASC", "iv.item_id ASC")
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+// ReplaceItemValues replaces every item value of the preset in the server with the given ones
+func (r *ItemValue) ReplaceItemValues(ctx context.Context, preset string, server string, values []*model.ItemValue) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*model.ItemValue)(nil)).
+			Where("preset = ?", preset).
+			Where("server = ?", server).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		_, err = tx.NewInsert().
+			Model(&values).
+			Exec(ctx)
+		return err
+	})
+}
+
+// DeleteItemValues deletes the item values of the preset in the server, or in every server when server is empty
+func (r *ItemValue) DeleteItemValues(ctx context.Context, preset string, server string) (int64, error) {
+	query := r.db.NewDelete().
+		Model((*model.ItemValue)(nil)).
+		Where("preset = ?", preset)
+	if server != "" {
+		query = query.Where("server = ?", server)
+	}
+	result, err := query.Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo/selector"
+)
+
+type Job struct {
+	db  *bun.DB
+	sel selector.S[model.Job]
+}
+
+func NewJob(db *bun.DB) *Job {
+	return &Job{db: db, sel: selector.New[model.Job](db)}
+}
+
+// CreateJob inserts the job unless a job with the same key exists, in which case created is false
+func (r *Job) CreateJob(ctx context.Context, job *model.Job) (created bool, err error) {
+	res, err := r.db.NewInsert().
+		Model(job).
+		On("CONFLICT (key) DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *Job) GetJobs(ctx context.Context, kind string, status string, limit int) ([]*model.Job, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		if kind != "" {
+			q = q.Where("kind = ?", kind)
+		}
+		if status != "" {
+			q = q.Where("status = ?", status)
+		}
+		return q.Order("created_at DESC").Limit(limit)
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+// ClaimDueJobs marks as running the due pending and retrying jobs of the kind, as well as the running ones whose
+// lease has expired, and returns them. Jobs being claimed by another instance are skipped.
+func (r *Job) ClaimDueJobs(ctx context.Context, kind string, lease time.Duration, limit int) ([]*model.Job, error) {
+	var jobs []*model.Job
+	err := r.db.NewRaw(`UPDATE jobs SET status = ?, attempts = attempts + 1, started_at = now(), lease_expires_at = now() + ? * interval '1 second'
+		WHERE job_id IN (
+			SELECT job_id FROM jobs
+			WHERE kind = ? AND ((status IN (?, ?) AND run_at <= now()) OR (status = ? AND lease_expires_at < now()))
+			ORDER BY run_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		model.JobStatusRunning, int(lease.Seconds()),
+		kind, model.JobStatusPending, model.JobStatusRetrying, model.JobStatusRunning,
+		limit,
+	).Scan(ctx, &jobs)
+	return jobs, err
+}
+
+// UpdateJobResult records the outcome of an attempt of the job
+func (r *Job) UpdateJobResult(ctx context.Context, job *model.Job) error {
+	_, err := r.db.NewUpdate().
+		Model(job).
+		Column("status", "attempts", "last_error", "run_at", "lease_expires_at", "started_at", "finished_at").
+		WherePK().
+		Exec(ctx)
+	return err
+}
@@ -0,0 +1,584 @@
+// Code generated by mockery v2.38.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	bun "github.com/uptrace/bun"
+
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "exusiai.dev/backend-next/internal/model"
+
+	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+
+	null "gopkg.in/guregu/null.v3"
+
+	time "time"
+)
+
+// DropReportRepo is an autogenerated mock type for the DropReportRepo type
+type DropReportRepo struct {
+	mock.Mock
+}
+
+// CalcDropMatrixAggregates provides a mock function with given fields: ctx, queryCtx
+func (_m *DropReportRepo) CalcDropMatrixAggregates(ctx context.Context, queryCtx *model.DropReportQueryContext) ([]*model.CombinedResultForDropMatrix, error) {
+	ret := _m.Called(ctx, queryCtx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CalcDropMatrixAggregates")
+	}
+
+	var r0 []*model.CombinedResultForDropMatrix
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.DropReportQueryContext) ([]*model.CombinedResultForDropMatrix, error)); ok {
+		return rf(ctx, queryCtx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.DropReportQueryContext) []*model.CombinedResultForDropMatrix); ok {
+		r0 = rf(ctx, queryCtx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.CombinedResultForDropMatrix)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.DropReportQueryContext) error); ok {
+		r1 = rf(ctx, queryCtx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalcDropMatrixAggregatesForRange provides a mock function with given fields: ctx, server, rangeId, stageItemFilter, sourceCategory
+func (_m *DropReportRepo) CalcDropMatrixAggregatesForRange(ctx context.Context, server string, rangeId int, stageItemFilter *map[int][]int, sourceCategory string) ([]*model.CombinedResultForDropMatrix, error) {
+	ret := _m.Called(ctx, server, rangeId, stageItemFilter, sourceCategory)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CalcDropMatrixAggregatesForRange")
+	}
+
+	var r0 []*model.CombinedResultForDropMatrix
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, *map[int][]int, string) ([]*model.CombinedResultForDropMatrix, error)); ok {
+		return rf(ctx, server, rangeId, stageItemFilter, sourceCategory)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, *map[int][]int, string) []*model.CombinedResultForDropMatrix); ok {
+		r0 = rf(ctx, server, rangeId, stageItemFilter, sourceCategory)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.CombinedResultForDropMatrix)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, *map[int][]int, string) error); ok {
+		r1 = rf(ctx, server, rangeId, stageItemFilter, sourceCategory)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalcDropMatrixAggregatesFromRollups provides a mock function with given fields: ctx, server, start, end, stageItemFilter, sourceCategory
+func (_m *DropReportRepo) CalcDropMatrixAggregatesFromRollups(ctx context.Context, server string, start time.Time, end time.Time, stageItemFilter *map[int][]int, sourceCategory string) ([]*model.CombinedResultForDropMatrix, error) {
+	ret := _m.Called(ctx, server, start, end, stageItemFilter, sourceCategory)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CalcDropMatrixAggregatesFromRollups")
+	}
+
+	var r0 []*model.CombinedResultForDropMatrix
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, *map[int][]int, string) ([]*model.CombinedResultForDropMatrix, error)); ok {
+		return rf(ctx, server, start, end, stageItemFilter, sourceCategory)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, *map[int][]int, string) []*model.CombinedResultForDropMatrix); ok {
+		r0 = rf(ctx, server, start, end, stageItemFilter, sourceCategory)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.CombinedResultForDropMatrix)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time, *map[int][]int, string) error); ok {
+		r1 = rf(ctx, server, start, end, stageItemFilter, sourceCategory)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalcRecentUniqueUserCountBySource provides a mock function with given fields: ctx, duration
+func (_m *DropReportRepo) CalcRecentUniqueUserCountBySource(ctx context.Context, duration time.Duration) ([]*modelv2.UniqueUserCountBySource, error) {
+	ret := _m.Called(ctx, duration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CalcRecentUniqueUserCountBySource")
+	}
+
+	var r0 []*modelv2.UniqueUserCountBySource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) ([]*modelv2.UniqueUserCountBySource, error)); ok {
+		return rf(ctx, duration)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) []*modelv2.UniqueUserCountBySource); ok {
+		r0 = rf(ctx, duration)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*modelv2.UniqueUserCountBySource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, duration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalcReportersForTimeRange provides a mock function with given fields: ctx, server, timeRange, stageIds, sourceCategory
+func (_m *DropReportRepo) CalcReportersForTimeRange(ctx context.Context, server string, timeRange *model.TimeRange, stageIds []int, sourceCategory string) ([]*model.ReportersResult, error) {
+	ret := _m.Called(ctx, server, timeRange, stageIds, sourceCategory)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CalcReportersForTimeRange")
+	}
+
+	var r0 []*model.ReportersResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *model.TimeRange, []int, string) ([]*model.ReportersResult, error)); ok {
+		return rf(ctx, server, timeRange, stageIds, sourceCategory)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *model.TimeRange, []int, string) []*model.ReportersResult); ok {
+		r0 = rf(ctx, server, timeRange, stageIds, sourceCategory)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.ReportersResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *model.TimeRange, []int, string) error); ok {
+		r1 = rf(ctx, server, timeRange, stageIds, sourceCategory)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalcTotalQuantityForPatternMatrix provides a mock function with given fields: ctx, queryCtx
+func (_m *DropReportRepo) CalcTotalQuantityForPatternMatrix(ctx context.Context, queryCtx *model.DropReportQueryContext) ([]*model.TotalQuantityResultForPatternMatrix, error) {
+	ret := _m.Called(ctx, queryCtx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CalcTotalQuantityForPatternMatrix")
+	}
+
+	var r0 []*model.TotalQuantityResultForPatternMatrix
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.DropReportQueryContext) ([]*model.TotalQuantityResultForPatternMatrix, error)); ok {
+		return rf(ctx, queryCtx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.DropReportQueryContext) []*model.TotalQuantityResultForPatternMatrix); ok {
+		r0 = rf(ctx, queryCtx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TotalQuantityResultForPatternMatrix)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.DropReportQueryContext) error); ok {
+		r1 = rf(ctx, queryCtx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalcTotalQuantityForTrend provides a mock function with given fields: ctx, server, startTime, intervalLength, intervalNum, stageIdItemIdMap, accountId, sourceCategory
+func (_m *DropReportRepo) CalcTotalQuantityForTrend(ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIdItemIdMap map[int][]int, accountId null.Int, sourceCategory string) ([]*model.TotalQuantityResultForTrend, error) {
+	ret := _m.Called(ctx, server, startTime, intervalLength, intervalNum, stageIdItemIdMap, accountId, sourceCategory)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CalcTotalQuantityForTrend")
+	}
+
+	var r0 []*model.TotalQuantityResultForTrend
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time, time.Duration, int, map[int][]int, null.Int, string) ([]*model.TotalQuantityResultForTrend, error)); ok {
+		return rf(ctx, server, startTime, intervalLength, intervalNum, stageIdItemIdMap, accountId, sourceCategory)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time, time.Duration, int, map[int][]int, null.Int, string) []*model.TotalQuantityResultForTrend); ok {
+		r0 = rf(ctx, server, startTime, intervalLength, intervalNum, stageIdItemIdMap, accountId, sourceCategory)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TotalQuantityResultForTrend)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *time.Time, time.Duration, int, map[int][]int, null.Int, string) error); ok {
+		r1 = rf(ctx, server, startTime, intervalLength, intervalNum, stageIdItemIdMap, accountId, sourceCategory)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalcTotalQuantityForTrendFromRollups provides a mock function with given fields: ctx, server, startTime, intervalLength, intervalNum, stageIdItemIdMap, sourceCategory
+func (_m *DropReportRepo) CalcTotalQuantityForTrendFromRollups(ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIdItemIdMap map[int][]int, sourceCategory string) ([]*model.TotalQuantityResultForTrend, error) {
+	ret := _m.Called(ctx, server, startTime, intervalLength, intervalNum, stageIdItemIdMap, sourceCategory)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CalcTotalQuantityForTrendFromRollups")
+	}
+
+	var r0 []*model.TotalQuantityResultForTrend
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time, time.Duration, int, map[int][]int, string) ([]*model.TotalQuantityResultForTrend, error)); ok {
+		return rf(ctx, server, startTime, intervalLength, intervalNum, stageIdItemIdMap, sourceCategory)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time, time.Duration, int, map[int][]int, string) []*model.TotalQuantityResultForTrend); ok {
+		r0 = rf(ctx, server, startTime, intervalLength, intervalNum, stageIdItemIdMap, sourceCategory)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TotalQuantityResultForTrend)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *time.Time, time.Duration, int, map[int][]int, string) error); ok {
+		r1 = rf(ctx, server, startTime, intervalLength, intervalNum, stageIdItemIdMap, sourceCategory)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalcTotalStageQuantityForShimSiteStats provides a mock function with given fields: ctx, server, isRecent24h
+func (_m *DropReportRepo) CalcTotalStageQuantityForShimSiteStats(ctx context.Context, server string, isRecent24h bool) ([]*modelv2.TotalStageTime, error) {
+	ret := _m.Called(ctx, server, isRecent24h)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CalcTotalStageQuantityForShimSiteStats")
+	}
+
+	var r0 []*modelv2.TotalStageTime
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) ([]*modelv2.TotalStageTime, error)); ok {
+		return rf(ctx, server, isRecent24h)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) []*modelv2.TotalStageTime); ok {
+		r0 = rf(ctx, server, isRecent24h)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*modelv2.TotalStageTime)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
+		r1 = rf(ctx, server, isRecent24h)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalcTotalTimes provides a mock function with given fields: ctx, queryCtx
+func (_m *DropReportRepo) CalcTotalTimes(ctx context.Context, queryCtx *model.DropReportQueryContext) ([]*model.TotalTimesResult, error) {
+	ret := _m.Called(ctx, queryCtx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CalcTotalTimes")
+	}
+
+	var r0 []*model.TotalTimesResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.DropReportQueryContext) ([]*model.TotalTimesResult, error)); ok {
+		return rf(ctx, queryCtx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.DropReportQueryContext) []*model.TotalTimesResult); ok {
+		r0 = rf(ctx, queryCtx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TotalTimesResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.DropReportQueryContext) error); ok {
+		r1 = rf(ctx, queryCtx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalcTotalTimesForTrend provides a mock function with given fields: ctx, server, startTime, intervalLength, intervalNum, stageIds, accountId, sourceCategory
+func (_m *DropReportRepo) CalcTotalTimesForTrend(ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIds []int, accountId null.Int, sourceCategory string) ([]*model.TotalTimesResultForTrend, error) {
+	ret := _m.Called(ctx, server, startTime, intervalLength, intervalNum, stageIds, accountId, sourceCategory)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CalcTotalTimesForTrend")
+	}
+
+	var r0 []*model.TotalTimesResultForTrend
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time, time.Duration, int, []int, null.Int, string) ([]*model.TotalTimesResultForTrend, error)); ok {
+		return rf(ctx, server, startTime, intervalLength, intervalNum, stageIds, accountId, sourceCategory)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time, time.Duration, int, []int, null.Int, string) []*model.TotalTimesResultForTrend); ok {
+		r0 = rf(ctx, server, startTime, intervalLength, intervalNum, stageIds, accountId, sourceCategory)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TotalTimesResultForTrend)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *time.Time, time.Duration, int, []int, null.Int, string) error); ok {
+		r1 = rf(ctx, server, startTime, intervalLength, intervalNum, stageIds, accountId, sourceCategory)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalcTotalTimesForTrendFromRollups provides a mock function with given fields: ctx, server, startTime, intervalLength, intervalNum, stageIds, sourceCategory
+func (_m *DropReportRepo) CalcTotalTimesForTrendFromRollups(ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIds []int, sourceCategory string) ([]*model.TotalTimesResultForTrend, error) {
+	ret := _m.Called(ctx, server, startTime, intervalLength, intervalNum, stageIds, sourceCategory)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CalcTotalTimesForTrendFromRollups")
+	}
+
+	var r0 []*model.TotalTimesResultForTrend
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time, time.Duration, int, []int, string) ([]*model.TotalTimesResultForTrend, error)); ok {
+		return rf(ctx, server, startTime, intervalLength, intervalNum, stageIds, sourceCategory)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time, time.Duration, int, []int, string) []*model.TotalTimesResultForTrend); ok {
+		r0 = rf(ctx, server, startTime, intervalLength, intervalNum, stageIds, sourceCategory)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TotalTimesResultForTrend)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *time.Time, time.Duration, int, []int, string) error); ok {
+		r1 = rf(ctx, server, startTime, intervalLength, intervalNum, stageIds, sourceCategory)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountDropReportsForArchive provides a mock function with given fields: ctx, date
+func (_m *DropReportRepo) CountDropReportsForArchive(ctx context.Context, date time.Time) (int, error) {
+	ret := _m.Called(ctx, date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountDropReportsForArchive")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(ctx, date)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, date)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteDropReportsForArchive provides a mock function with given fields: ctx, tx, date
+func (_m *DropReportRepo) DeleteDropReportsForArchive(ctx context.Context, tx bun.Tx, date time.Time) (int64, error) {
+	ret := _m.Called(ctx, tx, date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDropReportsForArchive")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, bun.Tx, time.Time) (int64, error)); ok {
+		return rf(ctx, tx, date)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, bun.Tx, time.Time) int64); ok {
+		r0 = rf(ctx, tx, date)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, bun.Tx, time.Time) error); ok {
+		r1 = rf(ctx, tx, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDropReports provides a mock function with given fields: ctx, queryCtx
+func (_m *DropReportRepo) GetDropReports(ctx context.Context, queryCtx *model.DropReportQueryContext) ([]*model.DropReport, error) {
+	ret := _m.Called(ctx, queryCtx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDropReports")
+	}
+
+	var r0 []*model.DropReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.DropReportQueryContext) ([]*model.DropReport, error)); ok {
+		return rf(ctx, queryCtx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.DropReportQueryContext) []*model.DropReport); ok {
+		r0 = rf(ctx, queryCtx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.DropReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.DropReportQueryContext) error); ok {
+		r1 = rf(ctx, queryCtx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDropReportsForArchive provides a mock function with given fields: ctx, cursor, date, limit
+func (_m *DropReportRepo) GetDropReportsForArchive(ctx context.Context, cursor *model.Cursor, date time.Time, limit int) ([]*model.DropReport, model.Cursor, error) {
+	ret := _m.Called(ctx, cursor, date, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDropReportsForArchive")
+	}
+
+	var r0 []*model.DropReport
+	var r1 model.Cursor
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Cursor, time.Time, int) ([]*model.DropReport, model.Cursor, error)); ok {
+		return rf(ctx, cursor, date, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Cursor, time.Time, int) []*model.DropReport); ok {
+		r0 = rf(ctx, cursor, date, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.DropReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Cursor, time.Time, int) model.Cursor); ok {
+		r1 = rf(ctx, cursor, date, limit)
+	} else {
+		r1 = ret.Get(1).(model.Cursor)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *model.Cursor, time.Time, int) error); ok {
+		r2 = rf(ctx, cursor, date, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetOldestDropReportTime provides a mock function with given fields: ctx
+func (_m *DropReportRepo) GetOldestDropReportTime(ctx context.Context) (null.Time, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOldestDropReportTime")
+	}
+
+	var r0 null.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (null.Time, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) null.Time); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(null.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReportIdRangeForArchive provides a mock function with given fields: ctx, date
+func (_m *DropReportRepo) GetReportIdRangeForArchive(ctx context.Context, date time.Time) (int, int, error) {
+	ret := _m.Called(ctx, date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReportIdRangeForArchive")
+	}
+
+	var r0 int
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, int, error)); ok {
+		return rf(ctx, date)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, date)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) int); ok {
+		r1 = rf(ctx, date)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, time.Time) error); ok {
+		r2 = rf(ctx, date)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewDropReportRepo creates a new instance of DropReportRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDropReportRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DropReportRepo {
+	mock := &DropReportRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,374 @@
+// Code generated by mockery v2.38.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "exusiai.dev/backend-next/internal/model"
+
+	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+)
+
+// ItemRepo is an autogenerated mock type for the ItemRepo type
+type ItemRepo struct {
+	mock.Mock
+}
+
+// CreateItem provides a mock function with given fields: ctx, item
+func (_m *ItemRepo) CreateItem(ctx context.Context, item *model.Item) error {
+	ret := _m.Called(ctx, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Item) error); ok {
+		r0 = rf(ctx, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteItem provides a mock function with given fields: ctx, itemId
+func (_m *ItemRepo) DeleteItem(ctx context.Context, itemId int) error {
+	ret := _m.Called(ctx, itemId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, itemId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDeletedItemByArkId provides a mock function with given fields: ctx, arkItemId
+func (_m *ItemRepo) GetDeletedItemByArkId(ctx context.Context, arkItemId string) (*model.Item, error) {
+	ret := _m.Called(ctx, arkItemId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeletedItemByArkId")
+	}
+
+	var r0 *model.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Item, error)); ok {
+		return rf(ctx, arkItemId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Item); ok {
+		r0 = rf(ctx, arkItemId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, arkItemId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeletedItems provides a mock function with given fields: ctx
+func (_m *ItemRepo) GetDeletedItems(ctx context.Context) ([]*model.Item, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeletedItems")
+	}
+
+	var r0 []*model.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.Item, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.Item); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetItemByArkId provides a mock function with given fields: ctx, arkItemId
+func (_m *ItemRepo) GetItemByArkId(ctx context.Context, arkItemId string) (*model.Item, error) {
+	ret := _m.Called(ctx, arkItemId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetItemByArkId")
+	}
+
+	var r0 *model.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Item, error)); ok {
+		return rf(ctx, arkItemId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Item); ok {
+		r0 = rf(ctx, arkItemId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, arkItemId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetItems provides a mock function with given fields: ctx
+func (_m *ItemRepo) GetItems(ctx context.Context) ([]*model.Item, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetItems")
+	}
+
+	var r0 []*model.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.Item, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.Item); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRecruitTagItems provides a mock function with given fields: ctx
+func (_m *ItemRepo) GetRecruitTagItems(ctx context.Context) ([]*model.Item, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecruitTagItems")
+	}
+
+	var r0 []*model.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.Item, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.Item); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShimItemByArkId provides a mock function with given fields: ctx, itemId
+func (_m *ItemRepo) GetShimItemByArkId(ctx context.Context, itemId string) (*modelv2.Item, error) {
+	ret := _m.Called(ctx, itemId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShimItemByArkId")
+	}
+
+	var r0 *modelv2.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*modelv2.Item, error)); ok {
+		return rf(ctx, itemId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *modelv2.Item); ok {
+		r0 = rf(ctx, itemId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*modelv2.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, itemId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShimItems provides a mock function with given fields: ctx
+func (_m *ItemRepo) GetShimItems(ctx context.Context) ([]*modelv2.Item, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShimItems")
+	}
+
+	var r0 []*modelv2.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*modelv2.Item, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*modelv2.Item); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*modelv2.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreItem provides a mock function with given fields: ctx, itemId
+func (_m *ItemRepo) RestoreItem(ctx context.Context, itemId int) error {
+	ret := _m.Called(ctx, itemId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, itemId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchItemByName provides a mock function with given fields: ctx, name
+func (_m *ItemRepo) SearchItemByName(ctx context.Context, name string) (*model.Item, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchItemByName")
+	}
+
+	var r0 *model.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Item, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Item); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchItems provides a mock function with given fields: ctx, query, limit
+func (_m *ItemRepo) SearchItems(ctx context.Context, query string, limit int) ([]*model.ItemSearchResult, error) {
+	ret := _m.Called(ctx, query, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchItems")
+	}
+
+	var r0 []*model.ItemSearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*model.ItemSearchResult, error)); ok {
+		return rf(ctx, query, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*model.ItemSearchResult); ok {
+		r0 = rf(ctx, query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.ItemSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, query, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateItem provides a mock function with given fields: ctx, item
+func (_m *ItemRepo) UpdateItem(ctx context.Context, item *model.Item) error {
+	ret := _m.Called(ctx, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Item) error); ok {
+		r0 = rf(ctx, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewItemRepo creates a new instance of ItemRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewItemRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ItemRepo {
+	mock := &ItemRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
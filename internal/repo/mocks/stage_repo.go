@@ -0,0 +1,418 @@
+// Code generated by mockery v2.38.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "exusiai.dev/backend-next/internal/model"
+
+	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+
+	null "gopkg.in/guregu/null.v3"
+
+	time "time"
+)
+
+// StageRepo is an autogenerated mock type for the StageRepo type
+type StageRepo struct {
+	mock.Mock
+}
+
+// DeleteStage provides a mock function with given fields: ctx, stageId
+func (_m *StageRepo) DeleteStage(ctx context.Context, stageId int) error {
+	ret := _m.Called(ctx, stageId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteStage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, stageId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDeletedStageByArkId provides a mock function with given fields: ctx, arkStageId
+func (_m *StageRepo) GetDeletedStageByArkId(ctx context.Context, arkStageId string) (*model.Stage, error) {
+	ret := _m.Called(ctx, arkStageId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeletedStageByArkId")
+	}
+
+	var r0 *model.Stage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Stage, error)); ok {
+		return rf(ctx, arkStageId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Stage); ok {
+		r0 = rf(ctx, arkStageId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Stage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, arkStageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeletedStages provides a mock function with given fields: ctx
+func (_m *StageRepo) GetDeletedStages(ctx context.Context) ([]*model.Stage, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeletedStages")
+	}
+
+	var r0 []*model.Stage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.Stage, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.Stage); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Stage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetGachaBoxStages provides a mock function with given fields: ctx
+func (_m *StageRepo) GetGachaBoxStages(ctx context.Context) ([]*model.Stage, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGachaBoxStages")
+	}
+
+	var r0 []*model.Stage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.Stage, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.Stage); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Stage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShimStageByArkId provides a mock function with given fields: ctx, arkStageId, server
+func (_m *StageRepo) GetShimStageByArkId(ctx context.Context, arkStageId string, server string) (*modelv2.Stage, error) {
+	ret := _m.Called(ctx, arkStageId, server)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShimStageByArkId")
+	}
+
+	var r0 *modelv2.Stage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*modelv2.Stage, error)); ok {
+		return rf(ctx, arkStageId, server)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *modelv2.Stage); ok {
+		r0 = rf(ctx, arkStageId, server)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*modelv2.Stage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, arkStageId, server)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShimStages provides a mock function with given fields: ctx, server
+func (_m *StageRepo) GetShimStages(ctx context.Context, server string) ([]*modelv2.Stage, error) {
+	ret := _m.Called(ctx, server)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShimStages")
+	}
+
+	var r0 []*modelv2.Stage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*modelv2.Stage, error)); ok {
+		return rf(ctx, server)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*modelv2.Stage); ok {
+		r0 = rf(ctx, server)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*modelv2.Stage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, server)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShimStagesForFakeTime provides a mock function with given fields: ctx, server, fakeTime
+func (_m *StageRepo) GetShimStagesForFakeTime(ctx context.Context, server string, fakeTime time.Time) ([]*modelv2.Stage, error) {
+	ret := _m.Called(ctx, server, fakeTime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShimStagesForFakeTime")
+	}
+
+	var r0 []*modelv2.Stage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) ([]*modelv2.Stage, error)); ok {
+		return rf(ctx, server, fakeTime)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) []*modelv2.Stage); ok {
+		r0 = rf(ctx, server, fakeTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*modelv2.Stage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, server, fakeTime)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStageByArkId provides a mock function with given fields: ctx, arkStageId
+func (_m *StageRepo) GetStageByArkId(ctx context.Context, arkStageId string) (*model.Stage, error) {
+	ret := _m.Called(ctx, arkStageId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStageByArkId")
+	}
+
+	var r0 *model.Stage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Stage, error)); ok {
+		return rf(ctx, arkStageId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Stage); ok {
+		r0 = rf(ctx, arkStageId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Stage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, arkStageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStageExtraProcessTypeByArkId provides a mock function with given fields: ctx, arkStageId
+func (_m *StageRepo) GetStageExtraProcessTypeByArkId(ctx context.Context, arkStageId string) (null.String, error) {
+	ret := _m.Called(ctx, arkStageId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStageExtraProcessTypeByArkId")
+	}
+
+	var r0 null.String
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (null.String, error)); ok {
+		return rf(ctx, arkStageId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) null.String); ok {
+		r0 = rf(ctx, arkStageId)
+	} else {
+		r0 = ret.Get(0).(null.String)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, arkStageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStages provides a mock function with given fields: ctx
+func (_m *StageRepo) GetStages(ctx context.Context) ([]*model.Stage, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStages")
+	}
+
+	var r0 []*model.Stage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.Stage, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.Stage); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Stage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStagesByZoneId provides a mock function with given fields: ctx, zoneId
+func (_m *StageRepo) GetStagesByZoneId(ctx context.Context, zoneId int) ([]*model.Stage, error) {
+	ret := _m.Called(ctx, zoneId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStagesByZoneId")
+	}
+
+	var r0 []*model.Stage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*model.Stage, error)); ok {
+		return rf(ctx, zoneId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*model.Stage); ok {
+		r0 = rf(ctx, zoneId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Stage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, zoneId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreStage provides a mock function with given fields: ctx, stageId
+func (_m *StageRepo) RestoreStage(ctx context.Context, stageId int) error {
+	ret := _m.Called(ctx, stageId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreStage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, stageId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchStageByCode provides a mock function with given fields: ctx, code
+func (_m *StageRepo) SearchStageByCode(ctx context.Context, code string) (*model.Stage, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchStageByCode")
+	}
+
+	var r0 *model.Stage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Stage, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Stage); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Stage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateMinClearTime provides a mock function with given fields: ctx, stageId, minClearTime
+func (_m *StageRepo) UpdateMinClearTime(ctx context.Context, stageId int, minClearTime null.Int) error {
+	ret := _m.Called(ctx, stageId, minClearTime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMinClearTime")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, null.Int) error); ok {
+		r0 = rf(ctx, stageId, minClearTime)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewStageRepo creates a new instance of StageRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStageRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StageRepo {
+	mock := &StageRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,120 @@
+// Code generated by mockery v2.38.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "exusiai.dev/backend-next/internal/model"
+)
+
+// TimeRangeRepo is an autogenerated mock type for the TimeRangeRepo type
+type TimeRangeRepo struct {
+	mock.Mock
+}
+
+// GetTimeRangeById provides a mock function with given fields: ctx, rangeId
+func (_m *TimeRangeRepo) GetTimeRangeById(ctx context.Context, rangeId int) (*model.TimeRange, error) {
+	ret := _m.Called(ctx, rangeId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTimeRangeById")
+	}
+
+	var r0 *model.TimeRange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*model.TimeRange, error)); ok {
+		return rf(ctx, rangeId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *model.TimeRange); ok {
+		r0 = rf(ctx, rangeId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TimeRange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, rangeId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTimeRangeByServerAndName provides a mock function with given fields: ctx, server, name
+func (_m *TimeRangeRepo) GetTimeRangeByServerAndName(ctx context.Context, server string, name string) (*model.TimeRange, error) {
+	ret := _m.Called(ctx, server, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTimeRangeByServerAndName")
+	}
+
+	var r0 *model.TimeRange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.TimeRange, error)); ok {
+		return rf(ctx, server, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.TimeRange); ok {
+		r0 = rf(ctx, server, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TimeRange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, server, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTimeRangesByServer provides a mock function with given fields: ctx, server
+func (_m *TimeRangeRepo) GetTimeRangesByServer(ctx context.Context, server string) ([]*model.TimeRange, error) {
+	ret := _m.Called(ctx, server)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTimeRangesByServer")
+	}
+
+	var r0 []*model.TimeRange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.TimeRange, error)); ok {
+		return rf(ctx, server)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.TimeRange); ok {
+		r0 = rf(ctx, server)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TimeRange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, server)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTimeRangeRepo creates a new instance of TimeRangeRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTimeRangeRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TimeRangeRepo {
+	mock := &TimeRangeRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"github.com/uptrace/bun"
 
@@ -23,3 +24,48 @@ func (r *Notice) GetNotices(ctx context.Context) ([]*model.Notice, error) {
 		return q.Order("notice_id ASC")
 	}, selector.OptionUseZeroLenSliceOnNull)
 }
+
+// GetActiveNotices returns the notices of which the display window includes t
+func (r *Notice) GetActiveNotices(ctx context.Context, t time.Time) ([]*model.Notice, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("start_time IS NULL OR start_time <= ?", t).
+			Where("end_time IS NULL OR end_time > ?", t).
+			Order("notice_id ASC")
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+func (r *Notice) GetNoticeById(ctx context.Context, noticeId int) (*model.Notice, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("notice_id = ?", noticeId)
+	})
+}
+
+// SaveNotice creates the notice when it has no ID yet, or updates it otherwise
+func (r *Notice) SaveNotice(ctx context.Context, notice *model.Notice) error {
+	columns := []string{"existence", "severity", "content", "kind", "start_time", "end_time"}
+	if notice.NoticeID == 0 {
+		_, err := r.db.NewInsert().
+			Model(notice).
+			Column(columns...).
+			Returning("notice_id, updated_at").
+			Exec(ctx)
+		return err
+	}
+
+	_, err := r.db.NewUpdate().
+		Model(notice).
+		Column(columns...).
+		Set("updated_at = current_timestamp").
+		WherePK().
+		Returning("updated_at").
+		Exec(ctx)
+	return err
+}
+
+func (r *Notice) DeleteNotice(ctx context.Context, noticeId int) error {
+	_, err := r.db.NewDelete().
+		Model((*model.Notice)(nil)).
+		Where("notice_id = ?", noticeId).
+		Exec(ctx)
+	return err
+}
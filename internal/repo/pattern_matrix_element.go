@@ -32,6 +32,15 @@ func (s *PatternMatrixElement) DeleteByServerAndDayNum(ctx context.Context, serv
 	return err
 }
 
+func (s *PatternMatrixElement) DeleteByServerAndDayNumAndStageIds(ctx context.Context, server string, dayNum int, stageIds []int) error {
+	_, err := s.db.NewDelete().Model((*model.PatternMatrixElement)(nil)).
+		Where("server = ?", server).
+		Where("day_num = ?", dayNum).
+		Where("stage_id IN (?)", bun.In(stageIds)).
+		Exec(ctx)
+	return err
+}
+
 func (s *PatternMatrixElement) IsExistByServerAndDayNum(ctx context.Context, server string, dayNum int) (bool, error) {
 	exists, err := s.db.NewSelect().Model((*model.PatternMatrixElement)(nil)).Where("server = ?", server).Where("day_num = ?", dayNum).Exists(ctx)
 	if err != nil {
@@ -58,3 +58,16 @@ func (r *Property) UpdatePropertyByKey(ctx context.Context, key string, value st
 
 	return &property, nil
 }
+
+func (r *Property) CreateProperty(ctx context.Context, key string, value string) (*model.Property, error) {
+	property := &model.Property{
+		Key:   key,
+		Value: value,
+	}
+	if _, err := r.db.NewInsert().
+		Model(property).
+		Exec(ctx); err != nil {
+		return nil, err
+	}
+	return property, nil
+}
@@ -59,3 +59,41 @@ func (r *RecognitionDefect) GetDefectReport(ctx context.Context, defectId string
 		return q.Where("defect_id = ?", defectId)
 	})
 }
+
+// CreateDefect stores a defect uploaded with its metadata only, which is final upon creation
+func (r *RecognitionDefect) CreateDefect(ctx context.Context, defect *model.RecognitionDefect) error {
+	defect.DefectID = strings.ToLower(ulid.Make().String())
+
+	_, err := r.db.NewInsert().
+		Model(defect).
+		Returning("created_at").
+		Exec(ctx)
+	return err
+}
+
+type RecognitionDefectFilter struct {
+	APIKeyID int
+	Server   string
+}
+
+// GetDefectsPage returns a page of the defects matching the filter, latest first, along with the total number of them
+func (r *RecognitionDefect) GetDefectsPage(ctx context.Context, filter *RecognitionDefectFilter, limit int, page int) ([]*model.RecognitionDefect, int, error) {
+	defects := make([]*model.RecognitionDefect, 0)
+	q := r.db.NewSelect().
+		Model(&defects).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(page * limit)
+	if filter.APIKeyID > 0 {
+		q = q.Where("api_key_id = ?", filter.APIKeyID)
+	}
+	if filter.Server != "" {
+		q = q.Where("environment->>'server' = ?", filter.Server)
+	}
+
+	total, err := q.ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return defects, total, nil
+}
@@ -0,0 +1,71 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo/selector"
+)
+
+type RecognitionStageHash struct {
+	db  *bun.DB
+	sel selector.S[model.RecognitionStageHash]
+}
+
+func NewRecognitionStageHash(db *bun.DB) *RecognitionStageHash {
+	return &RecognitionStageHash{db: db, sel: selector.New[model.RecognitionStageHash](db)}
+}
+
+// GetRecognitionStageHashes returns the hashes of the server, or of every server when server is empty
+func (r *RecognitionStageHash) GetRecognitionStageHashes(ctx context.Context, server string) ([]*model.RecognitionStageHash, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		if server != "" {
+			q = q.Where("rsh.server = ?", server)
+		}
+		return q.Order("rsh.server ASC", "rsh.hash_id ASC")
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+func (r *RecognitionStageHash) GetRecognitionStageHashById(ctx context.Context, hashId int) (*model.RecognitionStageHash, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("rsh.hash_id = ?", hashId)
+	})
+}
+
+func (r *RecognitionStageHash) GetRecognitionStageHashByHash(ctx context.Context, server string, hash string) (*model.RecognitionStageHash, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("rsh.server = ?", server).Where("rsh.hash = ?", hash)
+	})
+}
+
+// SaveRecognitionStageHash creates the hash when it has no ID yet, or updates it otherwise
+func (r *RecognitionStageHash) SaveRecognitionStageHash(ctx context.Context, stageHash *model.RecognitionStageHash) error {
+	columns := []string{"server", "stage_id", "hash", "comment"}
+	if stageHash.HashID == 0 {
+		_, err := r.db.NewInsert().
+			Model(stageHash).
+			Column(columns...).
+			Returning("hash_id, created_at, updated_at").
+			Exec(ctx)
+		return err
+	}
+
+	_, err := r.db.NewUpdate().
+		Model(stageHash).
+		Column(columns...).
+		Set("updated_at = current_timestamp").
+		WherePK().
+		Returning("created_at, updated_at").
+		Exec(ctx)
+	return err
+}
+
+func (r *RecognitionStageHash) DeleteRecognitionStageHash(ctx context.Context, hashId int) error {
+	_, err := r.db.NewDelete().
+		Model((*model.RecognitionStageHash)(nil)).
+		Where("hash_id = ?", hashId).
+		Exec(ctx)
+	return err
+}
@@ -0,0 +1,72 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo/selector"
+)
+
+type ReportReview struct {
+	db  *bun.DB
+	sel selector.S[model.ReportReview]
+}
+
+func NewReportReview(db *bun.DB) *ReportReview {
+	return &ReportReview{db: db, sel: selector.New[model.ReportReview](db)}
+}
+
+func (r *ReportReview) GetReportReviewById(ctx context.Context, reportId int) (*model.ReportReview, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("rr.report_id = ?", reportId)
+	})
+}
+
+// SaveReportReview creates the review of the report, or replaces the verdict it has been given before
+func (r *ReportReview) SaveReportReview(ctx context.Context, review *model.ReportReview) error {
+	_, err := r.db.NewInsert().
+		Model(review).
+		On("CONFLICT (report_id) DO UPDATE").
+		Set("verdict = EXCLUDED.verdict").
+		Set("signals = EXCLUDED.signals").
+		Set("risk_score = EXCLUDED.risk_score").
+		Set("reviewer_account_id = EXCLUDED.reviewer_account_id").
+		Set("comment = EXCLUDED.comment").
+		Set("reviewed_at = current_timestamp").
+		Returning("reviewed_at").
+		Exec(ctx)
+	return err
+}
+
+// CalcRiskSignalStats counts the reviewed reports carrying each of the risk signals, and the invalid ones among them
+func (r *ReportReview) CalcRiskSignalStats(ctx context.Context) ([]*model.RiskSignalStats, error) {
+	results := make([]*model.RiskSignalStats, 0)
+	err := r.db.NewSelect().
+		TableExpr("report_reviews AS rr, unnest(rr.signals) AS signal").
+		ColumnExpr("signal").
+		ColumnExpr("COUNT(*) AS reviewed").
+		ColumnExpr("COUNT(*) FILTER (WHERE rr.verdict = ?) AS invalid", model.ReportReviewVerdictInvalid).
+		Group("signal").
+		Order("signal").
+		Scan(ctx, &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CalcVerdictTotals counts the reviewed reports, and the invalid ones among them
+func (r *ReportReview) CalcVerdictTotals(ctx context.Context) (*model.RiskSignalStats, error) {
+	var result model.RiskSignalStats
+	err := r.db.NewSelect().
+		TableExpr("report_reviews AS rr").
+		ColumnExpr("COUNT(*) AS reviewed").
+		ColumnExpr("COUNT(*) FILTER (WHERE rr.verdict = ?) AS invalid", model.ReportReviewVerdictInvalid).
+		Scan(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,52 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo/selector"
+)
+
+type ShortLink struct {
+	db  *bun.DB
+	sel selector.S[model.ShortLink]
+}
+
+func NewShortLink(db *bun.DB) *ShortLink {
+	return &ShortLink{db: db, sel: selector.New[model.ShortLink](db)}
+}
+
+// GetShortLinkByCode returns the link under the code unless it has expired at t
+func (r *ShortLink) GetShortLinkByCode(ctx context.Context, code string, t time.Time) (*model.ShortLink, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("sl.code = ?", code).Where("sl.expires_at > ?", t)
+	})
+}
+
+// SaveShortLink creates the link, or extends the expiry of the link sharing the same payload when there is one
+// already. The code, creation time and expiry of the link saved are set back on it.
+func (r *ShortLink) SaveShortLink(ctx context.Context, link *model.ShortLink) error {
+	_, err := r.db.NewInsert().
+		Model(link).
+		Column("code", "payload_hash", "payload", "expires_at").
+		On("CONFLICT (payload_hash) DO UPDATE").
+		Set("expires_at = GREATEST(sl.expires_at, EXCLUDED.expires_at)").
+		Returning("code, created_at, expires_at").
+		Exec(ctx)
+	return err
+}
+
+// DeleteExpiredShortLinks removes the links that have expired by t, and returns how many there were
+func (r *ShortLink) DeleteExpiredShortLinks(ctx context.Context, t time.Time) (int64, error) {
+	res, err := r.db.NewDelete().
+		Model((*model.ShortLink)(nil)).
+		Where("expires_at <= ?", t).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -23,13 +23,13 @@ func NewSnapshot(db *bun.DB) *Snapshot {
 
 func (r *Snapshot) GetSnapshotById(ctx context.Context, id int) (*model.Snapshot, error) {
 	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where("id = ?", id)
+		return q.Where("snapshot_id = ?", id)
 	})
 }
 
 func (r *Snapshot) GetSnapshotsByIds(ctx context.Context, ids []int) ([]*model.Snapshot, error) {
 	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
-		return q.Where("id IN (?)", ids)
+		return q.Where("snapshot_id IN (?)", bun.In(ids))
 	})
 }
 
@@ -55,3 +55,12 @@ func (r *Snapshot) SaveSnapshot(ctx context.Context, snapshot *model.Snapshot) (
 
 	return snapshot, err
 }
+
+func (r *Snapshot) GetSnapshotsByKey(ctx context.Context, key string, limit int) ([]*model.Snapshot, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Column("snapshot_id", "created_at", "key", "version").
+			Where("key = ?", key).
+			OrderExpr("snapshot_id DESC").
+			Limit(limit)
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
@@ -65,6 +65,49 @@ func (r *Stage) GetStageByArkId(ctx context.Context, arkStageId string) (*model.
 	})
 }
 
+// GetDeletedStages returns the stages deleted, most recently deleted first
+func (r *Stage) GetDeletedStages(ctx context.Context) ([]*model.Stage, error) {
+	return r.v3sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.WhereDeleted().Order("deleted_at DESC")
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+func (r *Stage) GetDeletedStageByArkId(ctx context.Context, arkStageId string) (*model.Stage, error) {
+	return r.v3sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.WhereDeleted().Where("ark_stage_id = ?", arkStageId)
+	})
+}
+
+// DeleteStage soft deletes the stage, which keeps the drop reports and drop infos of the stage intact
+func (r *Stage) DeleteStage(ctx context.Context, stageId int) error {
+	_, err := r.db.NewDelete().
+		Model((*model.Stage)(nil)).
+		Where("stage_id = ?", stageId).
+		Exec(ctx)
+	return err
+}
+
+// RestoreStage undoes the soft deletion of the stage
+func (r *Stage) RestoreStage(ctx context.Context, stageId int) error {
+	_, err := r.db.NewUpdate().
+		Model((*model.Stage)(nil)).
+		WhereAllWithDeleted().
+		Set("deleted_at = NULL").
+		Where("stage_id = ?", stageId).
+		Exec(ctx)
+	return err
+}
+
+// UpdateMinClearTime sets the minimum time (in milliseconds) it takes to clear the stage; null clears it
+func (r *Stage) UpdateMinClearTime(ctx context.Context, stageId int, minClearTime null.Int) error {
+	_, err := r.db.NewUpdate().
+		Model((*model.Stage)(nil)).
+		Set("min_clear_time = ?", minClearTime).
+		Where("stage_id = ?", stageId).
+		Exec(ctx)
+	return err
+}
+
 func (r *Stage) shimStageQuery(ctx context.Context, server string, stages *[]*modelv2.Stage, t time.Time) error {
 	return r.db.NewSelect().
 		Model(stages).
@@ -84,6 +127,7 @@ func (r *Stage) shimStageQuery(ctx context.Context, server string, stages *[]*mo
 				}).
 				Where("drop_info.server = ?", server)
 		}).
+		Where("stage.deleted_at IS NULL").
 		Order("stage_id ASC").
 		Scan(ctx)
 }
@@ -137,6 +181,7 @@ func (r *Stage) GetShimStageByArkId(ctx context.Context, arkStageId string, serv
 				Where("drop_info.server = ?", server)
 		}).
 		Where("ark_stage_id = ?", arkStageId).
+		Where("stage.deleted_at IS NULL").
 		Scan(ctx)
 
 	if errors.Is(err, sql.ErrNoRows) {
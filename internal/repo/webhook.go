@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo/selector"
+)
+
+type Webhook struct {
+	db  *bun.DB
+	sel selector.S[model.Webhook]
+}
+
+func NewWebhook(db *bun.DB) *Webhook {
+	return &Webhook{db: db, sel: selector.New[model.Webhook](db)}
+}
+
+func (r *Webhook) CreateWebhook(ctx context.Context, webhook *model.Webhook) error {
+	_, err := r.db.NewInsert().
+		Model(webhook).
+		Returning("webhook_id, created_at").
+		Exec(ctx)
+	return err
+}
+
+func (r *Webhook) GetWebhookById(ctx context.Context, webhookId int) (*model.Webhook, error) {
+	return r.sel.SelectOne(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("webhook_id = ?", webhookId)
+	})
+}
+
+func (r *Webhook) GetWebhooksByAPIKeyId(ctx context.Context, apiKeyId int) ([]*model.Webhook, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("api_key_id = ?", apiKeyId).Order("webhook_id ASC")
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+// GetWebhooksByEvent returns the webhooks subscribed to the event, excluding those of revoked API keys
+func (r *Webhook) GetWebhooksByEvent(ctx context.Context, event string) ([]*model.Webhook, error) {
+	return r.sel.SelectMany(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.
+			Join("JOIN api_keys AS ak ON ak.api_key_id = wh.api_key_id").
+			Where("? = ANY(wh.events)", event).
+			Where("ak.revoked_at IS NULL")
+	}, selector.OptionUseZeroLenSliceOnNull)
+}
+
+func (r *Webhook) DeleteWebhook(ctx context.Context, webhookId int) error {
+	_, err := r.db.NewDelete().
+		Model((*model.Webhook)(nil)).
+		Where("webhook_id = ?", webhookId).
+		Exec(ctx)
+	return err
+}
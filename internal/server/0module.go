@@ -1,6 +1,7 @@
 package server
 
 import (
+	"exusiai.dev/backend-next/internal/server/grpcserver"
 	"exusiai.dev/backend-next/internal/server/httpserver"
 	"exusiai.dev/backend-next/internal/server/svr"
 	"go.uber.org/fx"
@@ -9,5 +10,6 @@ import (
 func Module() fx.Option {
 	return fx.Module("server",
 		fx.Provide(httpserver.Create),
+		fx.Provide(grpcserver.NewAggregation, grpcserver.Create),
 		fx.Provide(svr.CreateEndpointGroups))
 }
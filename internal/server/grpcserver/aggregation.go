@@ -0,0 +1,168 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"exusiai.dev/gommon/constant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model/pb"
+	"exusiai.dev/backend-next/internal/service"
+)
+
+// Aggregation serves the aggregated query results over gRPC, sharing the
+// same service layer (and therefore the same caches) as the v2 result APIs.
+type Aggregation struct {
+	pb.UnimplementedAggregationServer
+
+	DropMatrixService    *service.DropMatrix
+	PatternMatrixService *service.PatternMatrix
+	TrendService         *service.Trend
+}
+
+func NewAggregation(dropMatrixService *service.DropMatrix, patternMatrixService *service.PatternMatrix, trendService *service.Trend) *Aggregation {
+	return &Aggregation{
+		DropMatrixService:    dropMatrixService,
+		PatternMatrixService: patternMatrixService,
+		TrendService:         trendService,
+	}
+}
+
+func (s *Aggregation) GetDropMatrix(ctx context.Context, req *pb.DropMatrixRequest) (*pb.DropMatrixResponse, error) {
+	server, err := serverOf(req.Server)
+	if err != nil {
+		return nil, err
+	}
+	sourceCategory, err := sourceCategoryOf(req.SourceCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.DropMatrixService.GetShimDropMatrix(ctx, server, req.ShowClosedZones,
+		strings.Join(req.StageIds, ","), strings.Join(req.ItemIds, ","), accountIdOf(req.AccountId), sourceCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.DropMatrixResponse{
+		Matrix: make([]*pb.DropMatrixElement, 0, len(result.Matrix)),
+	}
+	for _, el := range result.Matrix {
+		resp.Matrix = append(resp.Matrix, &pb.DropMatrixElement{
+			StageId:  el.StageID,
+			ItemId:   el.ItemID,
+			Times:    int64(el.Times),
+			Quantity: int64(el.Quantity),
+			StdDev:   el.StdDev,
+			Start:    el.StartTime,
+			End:      el.EndTime.Ptr(),
+		})
+	}
+	return resp, nil
+}
+
+func (s *Aggregation) GetPatternMatrix(ctx context.Context, req *pb.PatternMatrixRequest) (*pb.PatternMatrixResponse, error) {
+	server, err := serverOf(req.Server)
+	if err != nil {
+		return nil, err
+	}
+	sourceCategory, err := sourceCategoryOf(req.SourceCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.PatternMatrixService.GetShimPatternMatrix(ctx, server, accountIdOf(req.AccountId), sourceCategory, req.ShowAllPatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.PatternMatrixResponse{
+		PatternMatrix: make([]*pb.PatternMatrixElement, 0, len(result.PatternMatrix)),
+	}
+	for _, el := range result.PatternMatrix {
+		element := &pb.PatternMatrixElement{
+			StageId:  el.StageID,
+			Times:    int64(el.Times),
+			Quantity: int64(el.Quantity),
+			Start:    el.StartTime,
+			End:      el.EndTime.Ptr(),
+		}
+		if el.Pattern != nil {
+			element.Drops = make([]*pb.PatternDrop, 0, len(el.Pattern.Drops))
+			for _, drop := range el.Pattern.Drops {
+				element.Drops = append(element.Drops, &pb.PatternDrop{
+					ItemId:   drop.ItemID,
+					Quantity: int64(drop.Quantity),
+				})
+			}
+		}
+		resp.PatternMatrix = append(resp.PatternMatrix, element)
+	}
+	return resp, nil
+}
+
+func (s *Aggregation) GetTrend(ctx context.Context, req *pb.TrendRequest) (*pb.TrendResponse, error) {
+	server, err := serverOf(req.Server)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.TrendService.GetShimTrend(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.TrendResponse{
+		Trend: make(map[string]*pb.StageTrend, len(result.Trend)),
+	}
+	for stageId, stageTrend := range result.Trend {
+		results := make(map[string]*pb.ItemTrend, len(stageTrend.Results))
+		for itemId, itemTrend := range stageTrend.Results {
+			results[itemId] = &pb.ItemTrend{
+				Quantity: toInt64s(itemTrend.Quantity),
+				Times:    toInt64s(itemTrend.Times),
+			}
+		}
+		resp.Trend[stageId] = &pb.StageTrend{
+			Start:   stageTrend.StartTime,
+			Results: results,
+		}
+	}
+	return resp, nil
+}
+
+func serverOf(server pb.Server) (string, error) {
+	name, ok := pb.Server_name[int32(server)]
+	if !ok {
+		return "", status.Errorf(codes.InvalidArgument, "unknown server %d", server)
+	}
+	return name, nil
+}
+
+func sourceCategoryOf(category pb.SourceCategory) (string, error) {
+	switch category {
+	case pb.SourceCategory_SOURCE_CATEGORY_ALL:
+		return constant.SourceCategoryAll, nil
+	case pb.SourceCategory_SOURCE_CATEGORY_AUTOMATED:
+		return constant.SourceCategoryAutomated, nil
+	case pb.SourceCategory_SOURCE_CATEGORY_MANUAL:
+		return constant.SourceCategoryManual, nil
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "unknown source category %d", category)
+	}
+}
+
+func accountIdOf(accountId *int64) null.Int {
+	return null.IntFromPtr(accountId)
+}
+
+func toInt64s(values []int) []int64 {
+	out := make([]int64, len(values))
+	for i, v := range values {
+		out[i] = int64(v)
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"exusiai.dev/backend-next/internal/model/pb"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+)
+
+func Create(aggregation *Aggregation) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(ErrorInterceptor),
+	)
+
+	pb.RegisterAggregationServer(server, aggregation)
+
+	return server
+}
+
+// ErrorInterceptor translates the errors returned by the service layer into gRPC status errors,
+// following the same distinction between expected and unexpected errors as the HTTP error handler.
+func ErrorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err == nil {
+		return resp, nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return nil, err
+	}
+
+	var e *pgerr.PenguinError
+	if errors.As(err, &e) {
+		// ErrNotFound is served as a 400 over HTTP for compatibility reasons, which is not the case for gRPC
+		if e.ErrorCode == pgerr.CodeNotFound {
+			return nil, status.Error(codes.NotFound, e.Message)
+		}
+		return nil, status.Error(codeFromHTTPStatus(e.StatusCode), e.Message)
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return nil, status.Error(codes.Canceled, err.Error())
+	}
+
+	log.Error().
+		Stack().
+		Err(err).
+		Str("method", info.FullMethod).
+		Msg("Internal Server Error")
+
+	return nil, status.Error(codes.Internal, pgerr.ErrInternalErrorImmutable.Message)
+}
+
+func codeFromHTTPStatus(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
@@ -11,17 +11,29 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
 	"exusiai.dev/backend-next/internal/pkg/pgerr"
 	"exusiai.dev/backend-next/internal/pkg/pgid"
 )
 
 var sentryHubKey = "sentry-hub"
 
+// HandleCustomError responds with the problem details of e, in which the extras of e are merged
 func HandleCustomError(ctx *fiber.Ctx, e *pgerr.PenguinError) error {
-	// Provide error code if pgerr.PenguinError type
+	problem := e.Problem(ctx.Path(), middlewares.RequestIDFrom(ctx))
 	body := fiber.Map{
-		"code":    e.ErrorCode,
-		"message": e.Message,
+		"type":    problem.Type,
+		"title":   problem.Title,
+		"status":  problem.Status,
+		"detail":  problem.Detail,
+		"code":    problem.Code,
+		"message": problem.Message,
+	}
+	if problem.Instance != "" {
+		body["instance"] = problem.Instance
+	}
+	if problem.ErrorID != "" {
+		body["errorId"] = problem.ErrorID
 	}
 
 	// Add extra details if needed
@@ -31,7 +43,11 @@ func HandleCustomError(ctx *fiber.Ctx, e *pgerr.PenguinError) error {
 		}
 	}
 
-	return ctx.Status(e.StatusCode).JSON(body)
+	if err := ctx.Status(e.StatusCode).JSON(body); err != nil {
+		return err
+	}
+	ctx.Set(fiber.HeaderContentType, pgerr.MIMEApplicationProblemJSON)
+	return nil
 }
 
 func ErrorHandler(ctx *fiber.Ctx, err error) error {
@@ -44,14 +60,13 @@ func ErrorHandler(ctx *fiber.Ctx, err error) error {
 				Str("recovered", spew.Sdump(r)).
 				Str("method", ctx.Method()).
 				Str("path", ctx.Path()).
+				Str("http.request_id", middlewares.RequestIDFrom(ctx)).
 				Msg("Recovered from panic")
 
 			sentry.CaptureMessage(spew.Sdump(r))
 
-			ctx.Status(500).JSON(fiber.Map{
-				"code":    pgerr.CodeInternalError,
-				"message": pgerr.ErrInternalErrorImmutable.Message,
-			})
+			ctx.Status(500).JSON(pgerr.ErrInternalErrorImmutable.Problem(ctx.Path(), middlewares.RequestIDFrom(ctx)))
+			ctx.Set(fiber.HeaderContentType, pgerr.MIMEApplicationProblemJSON)
 		}
 	}()
 
@@ -60,8 +75,8 @@ func ErrorHandler(ctx *fiber.Ctx, err error) error {
 	re := pgerr.ErrInternalErrorImmutable
 
 	if e, ok := err.(*fiber.Error); ok {
-		// Use default error handler if not a custom error
-		return fiber.DefaultErrorHandler(ctx, e)
+		// errors of fiber itself, e.g. a route not found, are described by the problems of their status
+		return HandleCustomError(ctx, pgerr.FromStatus(e.Code, e.Message))
 	}
 
 	if _, ok := err.(*fs.PathError); ok {
@@ -74,12 +89,24 @@ func ErrorHandler(ctx *fiber.Ctx, err error) error {
 		return HandleCustomError(ctx, e)
 	}
 
+	if errors.Is(err, context.DeadlineExceeded) {
+		// the query timeout of the request has passed
+		log.Warn().
+			Err(err).
+			Str("method", ctx.Method()).
+			Str("path", ctx.Path()).
+			Str("http.request_id", middlewares.RequestIDFrom(ctx)).
+			Msg("request timed out")
+		return HandleCustomError(ctx, pgerr.ErrTimeout)
+	}
+
 	// must be an unexpected runtime error then
 	log.Error().
 		Stack().
 		Err(err).
 		Str("method", ctx.Method()).
 		Str("path", ctx.Path()).
+		Str("http.request_id", middlewares.RequestIDFrom(ctx)).
 		Int("status", re.StatusCode).
 		Msg("Internal Server Error")
 
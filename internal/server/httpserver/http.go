@@ -37,7 +37,10 @@ import (
 	"exusiai.dev/backend-next/internal/pkg/pgerr"
 )
 
-var prometheusRegisterOnce sync.Once
+var (
+	prometheusRegisterOnce sync.Once
+	prometheusMiddleware   *fiberprometheus.FiberPrometheus
+)
 
 type DevOpsApp struct {
 	*fiber.App
@@ -82,16 +85,29 @@ func CreateServiceApp(conf *appconfig.Config) *fiber.App {
 			return true
 		},
 		AllowMethods:     "GET, POST, DELETE, OPTIONS",
-		AllowHeaders:     "Content-Type, Authorization, X-Requested-With, X-Penguin-Variant, sentry-trace",
-		ExposeHeaders:    "Content-Type, X-Penguin-Set-PenguinID, X-Penguin-Upgrade, X-Penguin-Compatible, X-Penguin-Request-ID",
+		AllowHeaders:     "Content-Type, Authorization, X-Requested-With, X-Penguin-Variant, X-Penguin-Challenge-Solution, sentry-trace",
+		ExposeHeaders:    "Content-Type, X-Penguin-Set-PenguinID, X-Penguin-Upgrade, X-Penguin-Compatible, X-Penguin-Request-ID, X-Penguin-Purged-Before, X-Penguin-Notes, X-Penguin-Degraded, X-Penguin-Maintenance, X-Penguin-Challenge, X-Penguin-Cache-Age, X-Penguin-Refresh-After, Retry-After",
 		AllowCredentials: true,
 	}))
+	if conf.HTTPCompressionEnabled {
+		app.Use(middlewares.Compress(middlewares.CompressConfig{
+			MinLength:       1024,
+			CacheExpiration: conf.HTTPCompressionCacheExpiration,
+		}))
+	}
 	// requestid is used by report service to identify requests and generate taskId there afterwards
 	// the logger middleware now injects RequestID into the context
 	middlewares.Logger(app)
 	// then we need an extra middleware to extract it and repopulate it into ctx.Locals
 	app.Use(middlewares.RequestID())
 
+	// the collectors are registered globally, hence shared by every app created
+	prometheusRegisterOnce.Do(func() {
+		prometheusMiddleware = fiberprometheus.New(observability.ServiceName)
+	})
+	// observe the latency per route outside of the custom error handler, so that the status codes of errors are known
+	app.Use(prometheusMiddleware.Middleware)
+
 	app.Use(func(c *fiber.Ctx) error {
 		// Use custom error handler to return customized error responses
 		err := c.Next()
@@ -139,9 +155,7 @@ func CreateServiceApp(conf *appconfig.Config) *fiber.App {
 
 	app.Use(otelfiber.Middleware(otelfiber.WithServerName("pgbackend")))
 
-	prometheusRegisterOnce.Do(func() {
-		fiberprometheus.New(observability.ServiceName).RegisterAt(app, "/metrics")
-	})
+	prometheusMiddleware.RegisterAt(app, "/metrics")
 
 	if conf.DevMode {
 		log.Info().
@@ -205,7 +219,14 @@ func tracingProviderOptions(conf *appconfig.Config) []tracesdk.TracerProviderOpt
 				options = append(options, tracesdk.WithBatcher(exp))
 				optionsstr = append(optionsstr, "jaeger")
 			case "otlpgrpc":
-				exp := lo.Must(otlptrace.New(context.Background(), otlptracegrpc.NewClient()))
+				clientOptions := []otlptracegrpc.Option{}
+				if conf.TracingOTLPEndpoint != "" {
+					clientOptions = append(clientOptions, otlptracegrpc.WithEndpoint(conf.TracingOTLPEndpoint))
+				}
+				if conf.TracingOTLPInsecure {
+					clientOptions = append(clientOptions, otlptracegrpc.WithInsecure())
+				}
+				exp := lo.Must(otlptrace.New(context.Background(), otlptracegrpc.NewClient(clientOptions...)))
 				options = append(options, tracesdk.WithBatcher(exp))
 				optionsstr = append(optionsstr, "otlpgrpc")
 			case "stdout":
@@ -1,15 +1,25 @@
 package svr
 
 import (
-	"crypto/subtle"
 	"strings"
 
 	"exusiai.dev/gommon/constant"
 	"github.com/gofiber/fiber/v2"
-	"github.com/rs/zerolog/log"
 
 	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/pkg/breaker"
+	"exusiai.dev/backend-next/internal/pkg/middlewares"
 	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/service"
+)
+
+const (
+	V2Prefix    = "/PenguinStats/api/v2"
+	V3Prefix    = "/api/v3alpha"
+	V3MediaType = "application/vnd.penguin.v3+json"
+
+	// v3DocsPrefix is where the API documentation of v3 is served, which is browsable without opting-in
+	v3DocsPrefix = V3Prefix + "/swagger"
 )
 
 type V2 struct {
@@ -28,40 +38,49 @@ type Meta struct {
 	fiber.Router
 }
 
-func CreateEndpointGroups(app *fiber.App, conf *appconfig.Config) (*V2, *V3, *Admin, *Meta) {
-	v2 := app.Group("/PenguinStats/api/v2", func(c *fiber.Ctx) error {
+func CreateEndpointGroups(conf *appconfig.Config, app *fiber.App, dbBreaker *breaker.Breaker, accountRoleService *service.AccountRole, apiKeyService *service.APIKey, apiUsageService *service.APIUsage, maintenanceService *service.Maintenance) (*V2, *V3, *Admin, *Meta) {
+	// third-party consumers may identify themselves with an API key to be served within their own quotas
+	apiKeyQuota := middlewares.APIKeyQuota(apiKeyService.AuthenticateAPIKey, apiUsageService.Admit, apiUsageService.Record)
+	// the API is timed out by the class of the requests, and degrades to the caches once the database is slow
+	routeTimeout := middlewares.RouteTimeout(conf.HTTPQueryTimeout, conf.HTTPWriteQueryTimeout, conf.HTTPDegradedQueryTimeout, dbBreaker)
+	// the writes are rejected while under maintenance, e.g. during the migrations of the database; the POST routes
+	// listed only read, and keep being served along with the other reads
+	maintenance := middlewares.Maintenance(maintenanceService.Current, conf.HTTPDegradedQueryTimeout,
+		V2Prefix+"/users",
+		V2Prefix+"/result/advanced",
+		V3Prefix+"/result/advanced",
+		V3Prefix+"/tools/plan",
+	)
+
+	v2 := app.Group(V2Prefix, func(c *fiber.Ctx) error {
 		// add compatibility versioning header for v2 shims
 		c.Set(constant.ShimCompatibilityHeaderKey, constant.ShimCompatibilityHeaderValue)
 		return c.Next()
-	})
+	}, apiKeyQuota, routeTimeout, maintenance)
 
-	v3 := app.Group("/api/v3alpha", func(c *fiber.Ctx) error {
+	v3 := app.Group(V3Prefix, func(c *fiber.Ctx) error {
 		msg := "The v3 API is in alpha and may change in the future. Please report any issues and/or suggestions to https://github.com/penguin-statistics/backend-next/issues."
 		c.Set("X-Penguin-Notes", msg)
 
+		if strings.HasPrefix(c.Path(), v3DocsPrefix) {
+			return c.Next()
+		}
+
 		accepts := c.Get(fiber.HeaderAccept)
-		if !strings.Contains(accepts, "application/vnd.penguin.v3+json") {
+		if !strings.Contains(accepts, V3MediaType) {
 			return pgerr.ErrInvalidReq.Msg(msg + " To use the v3 API, please use the application/vnd.penguin.v3+json Accept header to explicitly opt-in to the alpha version of API.")
 		}
 
 		return c.Next()
-	})
-
-	admin := app.Group("/api/admin", func(c *fiber.Ctx) error {
-		if len(conf.AdminKey) < 64 {
-			log.Error().Msg("admin key is not set or is too short (at least should be 64 chars long), and a request has reached")
-			return c.SendStatus(fiber.StatusInternalServerError)
-		}
-		key := strings.TrimSpace(strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer"))
+	}, apiKeyQuota, routeTimeout, maintenance)
 
-		// use constant time comparison to prevent timing attacks
-		if subtle.ConstantTimeCompare([]byte(key), []byte(conf.AdminKey)) != 1 {
-			return c.SendStatus(fiber.StatusUnauthorized)
-		}
-		return c.Next()
-	})
+	// admin routes are authenticated with per-account admin tokens; each route
+	// is further authorized with middlewares.RequireRoles upon registration
+	// which may trigger refreshes that take far longer than the API reads
+	admin := app.Group("/api/admin", middlewares.AdminAuthentication(accountRoleService.AuthenticateAdminToken),
+		middlewares.QueryTimeout(conf.HTTPAdminQueryTimeout))
 
-	meta := app.Group("/api/_")
+	meta := app.Group("/api/_", routeTimeout)
 
 	return &V2{Router: v2}, &V3{Router: v3}, &Admin{Router: admin}, &Meta{Router: meta}
 }
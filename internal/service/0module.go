@@ -1,16 +1,36 @@
 package service
 
 import (
+	"go.opentelemetry.io/otel"
 	"go.uber.org/fx"
 )
 
+// tracer traces the services that are worth breaking down when slow, e.g. the calculation of the results
+var tracer = otel.Tracer("service")
+
 func Module() fx.Option {
 	return fx.Module("service", fx.Provide(
+		NewTunables,
+		NewFeatureFlags,
+		NewMaintenance,
+		NewCacheTTLOverrides,
+		NewLeader,
+		NewJobs,
+		NewIntegrity,
+		NewDropMatrixViews,
+		NewDropReportDailyRollups,
+		NewDropReportPartitions,
+		NewReportQuarantine,
+		NewRetention,
 		NewItem,
+		NewItemValue,
 		NewInit,
+		NewMetaBundle,
 		NewZone,
 		NewStage,
 		NewGeoIP,
+		NewSubmissionPolicy,
+		NewSubmissionChallenge,
 		NewTrend,
 		NewAdmin,
 		NewUpyun,
@@ -18,23 +38,57 @@ func Module() fx.Option {
 		NewNotice,
 		NewReport,
 		NewAccount,
+		NewAccountStanding,
+		NewShadowBan,
+		NewAccountRole,
+		NewAPIKey,
+		NewAPIUsage,
+		NewWebhook,
 		NewFormula,
 		NewActivity,
 		NewDropInfo,
 		NewShortURL,
+		NewShortLink,
+		NewAdvancedQuery,
+		NewQueryCost,
 		NewSnapshot,
+		NewMetadataSnapshot,
 		NewAnalytics,
 		NewSiteStats,
+		NewSiteCounter,
+		NewFreshness,
 		NewTimeRange,
+		NewRecognitionDefect,
+		NewEvent,
+		NewLeaderboard,
+		NewEventSummary,
+		NewSampleForecast,
+		NewTimesDistribution,
+		NewSimulation,
+		NewPlanner,
+		NewItemIcon,
+		NewDropInfoBounds,
+		NewRecognitionStageHash,
+		NewClientVersion,
+		NewUIConfig,
+		NewBackfill,
+		NewExclusionRule,
+		NewReportReview,
+		NewOutlierDetection,
 		NewDropMatrix,
+		NewDropMatrixDelta,
 		NewDropReport,
+		NewDropReportTask,
 		NewPatternMatrix,
 		NewFrontendConfig,
 		NewDropMatrixElement,
 		NewDropPatternElement,
 		NewPatternMatrixElement,
+		NewLocalization,
 		NewExport,
 		NewDropReportExtra,
 		NewArchive,
+		NewGameDataSync,
+		NewRefreshJob,
 	))
 }
@@ -2,30 +2,39 @@ package service
 
 import (
 	"context"
+	"strconv"
 	"time"
 
-	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
 
 	"exusiai.dev/backend-next/internal/model"
 	"exusiai.dev/backend-next/internal/model/cache"
-	"exusiai.dev/backend-next/internal/pkg/flog"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
 	"exusiai.dev/backend-next/internal/pkg/pgerr"
-	"exusiai.dev/backend-next/internal/pkg/pgid"
 	"exusiai.dev/backend-next/internal/repo"
 )
 
 type Account struct {
-	AccountRepo *repo.Account
+	AccountRepo        *repo.Account
+	SiteCounterService *SiteCounter
 }
 
-func NewAccount(accountRepo *repo.Account) *Account {
+func NewAccount(accountRepo *repo.Account, siteCounterService *SiteCounter) *Account {
 	return &Account{
-		AccountRepo: accountRepo,
+		AccountRepo:        accountRepo,
+		SiteCounterService: siteCounterService,
 	}
 }
 
 func (s *Account) CreateAccountWithRandomPenguinId(ctx context.Context) (*model.Account, error) {
-	return s.AccountRepo.CreateAccountWithRandomPenguinId(ctx)
+	account, err := s.AccountRepo.CreateAccountWithRandomPenguinId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.SiteCounterService.RecordAccountCreated(ctx); err != nil {
+		log.Warn().Err(err).Msg("failed to record account creation in site counters")
+	}
+	return account, nil
 }
 
 // Cache: account#accountId:{accountId}, 1 hr
@@ -60,21 +69,43 @@ func (s *Account) GetAccountByPenguinId(ctx context.Context, penguinId string) (
 	return dbAccount, nil
 }
 
+func (s *Account) GetLeaderboardSettings(account *model.Account) *modelv3.AccountLeaderboardSettings {
+	return &modelv3.AccountLeaderboardSettings{
+		OptIn: account.LeaderboardOptIn,
+		Name:  account.LeaderboardName,
+	}
+}
+
+// UpdateLeaderboardSettings changes whether and under which name the account is listed on the leaderboards. The
+// cached leaderboards pick the changes up once they expire.
+func (s *Account) UpdateLeaderboardSettings(ctx context.Context, account *model.Account, settings *modelv3.AccountLeaderboardSettings) (*modelv3.AccountLeaderboardSettings, error) {
+	if err := s.AccountRepo.UpdateAccountLeaderboardSettings(ctx, account.AccountID, settings.OptIn, settings.Name); err != nil {
+		return nil, err
+	}
+	cache.AccountByID.Delete(strconv.Itoa(account.AccountID))
+	cache.AccountByPenguinID.Delete(account.PenguinID)
+
+	account.LeaderboardOptIn = settings.OptIn
+	account.LeaderboardName = settings.Name
+	return s.GetLeaderboardSettings(account), nil
+}
+
 func (s *Account) IsAccountExistWithId(ctx context.Context, accountId int) bool {
 	return s.AccountRepo.IsAccountExistWithId(ctx, accountId)
 }
 
-func (s *Account) GetAccountFromRequest(ctx *fiber.Ctx) (*model.Account, error) {
-	// get PenguinID from HTTP header in form of Authorization: PenguinID ########
-	penguinId := pgid.Extract(ctx)
+// GetAccountFromPenguinId resolves the PenguinID a request has been authenticated with into its account. The
+// PenguinID is expected to be extracted from the request by the caller, e.g. with pgid.Extract.
+func (s *Account) GetAccountFromPenguinId(ctx context.Context, penguinId string) (*model.Account, error) {
 	if penguinId == "" {
 		return nil, pgerr.ErrInvalidReq.Msg("PenguinID not found in request")
 	}
 
 	// check PenguinID validity
-	account, err := s.GetAccountByPenguinId(ctx.UserContext(), penguinId)
+	account, err := s.GetAccountByPenguinId(ctx, penguinId)
 	if err != nil {
-		flog.WarnFrom(ctx, "account.invalid.notfound").
+		log.Ctx(ctx).Warn().
+			Str("evt.name", "account.invalid.notfound").
 			Err(err).
 			Str("penguinIdProvided", penguinId).
 			Msg("failed to get account from request")
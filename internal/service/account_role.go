@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// adminTokenBytes is the entropy of a generated admin token. Hex-encoded, it yields a 64 chars long token.
+const adminTokenBytes = 32
+
+type AccountRole struct {
+	AccountRepo *repo.Account
+}
+
+func NewAccountRole(accountRepo *repo.Account) *AccountRole {
+	return &AccountRole{
+		AccountRepo: accountRepo,
+	}
+}
+
+type GrantRoleResult struct {
+	Account *model.Account `json:"account"`
+	// Token is only present when a new admin token has been issued for the account.
+	// It is not stored in plain text and cannot be retrieved again.
+	Token string `json:"token,omitempty"`
+}
+
+func hashAdminToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Cache: account#adminTokenHash:{adminTokenHash}, 1 min
+func (s *AccountRole) AuthenticateAdminToken(ctx context.Context, token string) (*model.Account, error) {
+	if len(token) < adminTokenBytes*2 {
+		return nil, pgerr.ErrNotFound
+	}
+	tokenHash := hashAdminToken(token)
+
+	var account model.Account
+	err := cache.AccountByAdminTokenHash.Get(tokenHash, &account)
+	if err == nil {
+		return &account, nil
+	}
+
+	dbAccount, err := s.AccountRepo.GetAccountByAdminTokenHash(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	cache.AccountByAdminTokenHash.Set(tokenHash, *dbAccount, time.Minute)
+	return dbAccount, nil
+}
+
+func (s *AccountRole) GrantRole(ctx context.Context, penguinId string, role string) (*GrantRoleResult, error) {
+	account, err := s.AccountRepo.GetAccountByPenguinId(ctx, penguinId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GrantRoleResult{Account: account}
+	if !lo.Contains(account.Roles, role) {
+		account.Roles = append(account.Roles, role)
+	}
+	if account.AdminTokenHash == "" {
+		result.Token, err = generateAdminToken()
+		if err != nil {
+			return nil, err
+		}
+		account.AdminTokenHash = hashAdminToken(result.Token)
+	}
+
+	if err := s.updateRoles(ctx, account); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("evt.name", "account.role.granted").
+		Int("accountId", account.AccountID).
+		Str("role", role).
+		Bool("tokenIssued", result.Token != "").
+		Msg("granted role to account")
+
+	return result, nil
+}
+
+// RevokeRole removes the role from the account. Once the account has no roles left,
+// its admin token is revoked as well.
+func (s *AccountRole) RevokeRole(ctx context.Context, penguinId string, role string) (*model.Account, error) {
+	account, err := s.AccountRepo.GetAccountByPenguinId(ctx, penguinId)
+	if err != nil {
+		return nil, err
+	}
+	if !lo.Contains(account.Roles, role) {
+		return nil, pgerr.ErrInvalidReq.Msg("account does not have role %s", role)
+	}
+
+	account.Roles = lo.Without(account.Roles, role)
+	if len(account.Roles) == 0 {
+		account.AdminTokenHash = ""
+	}
+
+	if err := s.updateRoles(ctx, account); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("evt.name", "account.role.revoked").
+		Int("accountId", account.AccountID).
+		Str("role", role).
+		Msg("revoked role from account")
+
+	return account, nil
+}
+
+func (s *AccountRole) updateRoles(ctx context.Context, account *model.Account) error {
+	if err := s.AccountRepo.UpdateAccountRoles(ctx, account.AccountID, account.Roles, account.AdminTokenHash); err != nil {
+		return err
+	}
+
+	// the admin token hash cache is keyed by hash, which we may no longer know after a revocation
+	cache.AccountByAdminTokenHash.Flush()
+	cache.AccountByID.Delete(strconv.Itoa(account.AccountID))
+	cache.AccountByPenguinID.Delete(account.PenguinID)
+	return nil
+}
+
+func generateAdminToken() (string, error) {
+	b := make([]byte, adminTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+	"exusiai.dev/backend-next/internal/util/reportverifs"
+)
+
+// ShadowBanMaxRetroactive bounds how far back the reports of an account are excluded upon its shadow ban, so that
+// the recalculation of the affected days stays within a refresh job
+const ShadowBanMaxRetroactive = time.Hour * 24 * 90
+
+// ShadowBan accepts the reports of suspected poisoners as usual while excluding them from the aggregations, so that
+// they are not taught what gets caught
+type ShadowBan struct {
+	AccountRepo     *repo.Account
+	DropReportRepo  *repo.DropReport
+	BackfillService *Backfill
+}
+
+func NewShadowBan(accountRepo *repo.Account, dropReportRepo *repo.DropReport, backfillService *Backfill) *ShadowBan {
+	return &ShadowBan{
+		AccountRepo:     accountRepo,
+		DropReportRepo:  dropReportRepo,
+		BackfillService: backfillService,
+	}
+}
+
+type ShadowBannedAccount struct {
+	AccountID      int        `json:"accountId"`
+	PenguinID      string     `json:"penguinId"`
+	ShadowBannedAt *time.Time `json:"shadowBannedAt"`
+}
+
+type ShadowBanResult struct {
+	Account *ShadowBannedAccount `json:"account"`
+	// Reports are the reports of which the exclusion has changed, per server and stage
+	Reports []*model.ReliabilityChangeResult `json:"reports"`
+	// RefreshJobs recalculate the aggregations of the stages and the days of the reports changed
+	RefreshJobs []*model.RefreshJob `json:"refreshJobs"`
+}
+
+func (s *ShadowBan) GetShadowBannedAccounts(ctx context.Context) ([]*ShadowBannedAccount, error) {
+	accounts, err := s.AccountRepo.GetShadowBannedAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ShadowBannedAccount, 0, len(accounts))
+	for _, account := range accounts {
+		results = append(results, toShadowBannedAccount(account))
+	}
+	return results, nil
+}
+
+// ShadowBanAccount shadow-bans the account, and optionally excludes its reports accepted since req.RetroactiveSince
+func (s *ShadowBan) ShadowBanAccount(ctx context.Context, penguinId string, req *types.ShadowBanAccountRequest) (*ShadowBanResult, error) {
+	if req.RetroactiveSince != nil && time.Since(*req.RetroactiveSince) > ShadowBanMaxRetroactive {
+		return nil, pgerr.ErrInvalidReq.Msg("retroactiveSince must be within the last %d days", int(ShadowBanMaxRetroactive.Hours()/24))
+	}
+
+	account, err := s.AccountRepo.GetAccountByPenguinId(ctx, penguinId)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.ShadowBannedAt == nil {
+		now := time.Now()
+		if err := s.AccountRepo.UpdateAccountShadowBan(ctx, account.AccountID, &now); err != nil {
+			return nil, err
+		}
+		account.ShadowBannedAt = &now
+	}
+
+	result := &ShadowBanResult{
+		Account:     toShadowBannedAccount(account),
+		Reports:     []*model.ReliabilityChangeResult{},
+		RefreshJobs: []*model.RefreshJob{},
+	}
+	if req.RetroactiveSince != nil {
+		result.Reports, err = s.DropReportRepo.UpdateAccountReportsReliability(ctx, account.AccountID, req.RetroactiveSince, 0, reportverifs.ViolationReliabilityShadowBan)
+		if err != nil {
+			return nil, err
+		}
+		result.RefreshJobs, err = s.BackfillService.Track(ctx, result.Reports)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	log.Info().
+		Str("evt.name", "account.shadow_ban.applied").
+		Int("accountId", account.AccountID).
+		Int("stages", len(result.Reports)).
+		Msg("account shadow-banned")
+
+	return result, nil
+}
+
+// LiftShadowBan lifts the shadow ban of the account, and includes all of its reports excluded by the ban again
+func (s *ShadowBan) LiftShadowBan(ctx context.Context, penguinId string) (*ShadowBanResult, error) {
+	account, err := s.AccountRepo.GetAccountByPenguinId(ctx, penguinId)
+	if err != nil {
+		return nil, err
+	}
+	if account.ShadowBannedAt == nil {
+		return nil, pgerr.ErrInvalidReq.Msg("account %s is not shadow-banned", penguinId)
+	}
+
+	if err := s.AccountRepo.UpdateAccountShadowBan(ctx, account.AccountID, nil); err != nil {
+		return nil, err
+	}
+	account.ShadowBannedAt = nil
+
+	result := &ShadowBanResult{
+		Account: toShadowBannedAccount(account),
+	}
+	result.Reports, err = s.DropReportRepo.UpdateAccountReportsReliability(ctx, account.AccountID, nil, reportverifs.ViolationReliabilityShadowBan, 0)
+	if err != nil {
+		return nil, err
+	}
+	result.RefreshJobs, err = s.BackfillService.Track(ctx, result.Reports)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("evt.name", "account.shadow_ban.lifted").
+		Int("accountId", account.AccountID).
+		Int("stages", len(result.Reports)).
+		Msg("account shadow ban lifted")
+
+	return result, nil
+}
+
+func toShadowBannedAccount(account *model.Account) *ShadowBannedAccount {
+	return &ShadowBannedAccount{
+		AccountID:      account.AccountID,
+		PenguinID:      account.PenguinID,
+		ShadowBannedAt: account.ShadowBannedAt,
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+	"exusiai.dev/backend-next/internal/util/reportverifs"
+)
+
+// AccountStandingWindow is how far back the reports of an account are evaluated when computing its standing
+const AccountStandingWindow = time.Hour * 24 * 30
+
+var ErrAppealAlreadyPending = pgerr.New(fiber.StatusConflict, "APPEAL_PENDING", "an appeal for this account is already pending review")
+
+type AccountStanding struct {
+	AccountAppealRepo *repo.AccountAppeal
+	DropReportRepo    *repo.DropReport
+}
+
+func NewAccountStanding(accountAppealRepo *repo.AccountAppeal, dropReportRepo *repo.DropReport) *AccountStanding {
+	return &AccountStanding{
+		AccountAppealRepo: accountAppealRepo,
+		DropReportRepo:    dropReportRepo,
+	}
+}
+
+func (s *AccountStanding) GetAccountStanding(ctx context.Context, account *model.Account) (*modelv3.AccountStanding, error) {
+	counts, err := s.DropReportRepo.CalcReliabilityCountsByAccountId(ctx, account.AccountID, AccountStandingWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := countStandingReports(counts)
+
+	standing := modelv3.AccountStandingGood
+	if reports.Rejected > 0 && reports.Accepted == 0 {
+		standing = modelv3.AccountStandingExcluded
+	} else if reports.Rejected > 0 {
+		standing = modelv3.AccountStandingLimited
+	}
+
+	appeal, err := s.AccountAppealRepo.GetLatestAppealByAccountId(ctx, account.AccountID)
+	if err != nil && !errors.Is(err, pgerr.ErrNotFound) {
+		return nil, err
+	}
+
+	return &modelv3.AccountStanding{
+		PenguinID: account.PenguinID,
+		Standing:  standing,
+		Weight:    account.Weight,
+		Reports:   reports,
+		Appeal:    appeal,
+	}, nil
+}
+
+// countStandingReports sums the reports of an account within AccountStandingWindow as the account is shown them
+func countStandingReports(counts []*model.ReliabilityCountResult) modelv3.AccountStandingReports {
+	reports := modelv3.AccountStandingReports{
+		WindowDays: int(AccountStandingWindow.Hours() / 24),
+	}
+	for _, count := range counts {
+		reports.Total += count.Count
+		// the reports excluded by a shadow ban or pending in quarantine are shown as accepted to the account
+		if count.Reliability == 0 || count.Reliability == reportverifs.ViolationReliabilityShadowBan ||
+			count.Reliability == reportverifs.ViolationReliabilityQuarantine {
+			reports.Accepted += count.Count
+		} else {
+			reports.Rejected += count.Count
+		}
+	}
+	return reports
+}
+
+func (s *AccountStanding) SubmitAppeal(ctx context.Context, account *model.Account, reason string, contact string) (*model.AccountAppeal, error) {
+	latest, err := s.AccountAppealRepo.GetLatestAppealByAccountId(ctx, account.AccountID)
+	if err != nil && !errors.Is(err, pgerr.ErrNotFound) {
+		return nil, err
+	}
+	if latest != nil && latest.Status == model.AccountAppealStatusPending {
+		return nil, ErrAppealAlreadyPending
+	}
+
+	appeal := &model.AccountAppeal{
+		AccountID: account.AccountID,
+		Reason:    reason,
+		Contact:   contact,
+		Status:    model.AccountAppealStatusPending,
+	}
+	if err := s.AccountAppealRepo.CreateAppeal(ctx, appeal); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("evt.name", "account.appeal.submitted").
+		Int("accountId", account.AccountID).
+		Int("appealId", appeal.AppealID).
+		Msg("account appeal submitted and queued for moderation review")
+
+	return appeal, nil
+}
+
+func (s *AccountStanding) GetPendingAppeals(ctx context.Context, limit int, page int) ([]*model.AccountAppeal, error) {
+	return s.AccountAppealRepo.GetAppealsByStatus(ctx, model.AccountAppealStatusPending, limit, page)
+}
+
+// ResolveAppeal marks an appeal as approved or rejected
+func (s *AccountStanding) ResolveAppeal(ctx context.Context, appealId int, approve bool, resolution string) (*model.AccountAppeal, error) {
+	appeal, err := s.AccountAppealRepo.GetAppeal(ctx, appealId)
+	if err != nil {
+		return nil, err
+	}
+	if appeal.Status != model.AccountAppealStatusPending {
+		return nil, pgerr.ErrInvalidReq.Msg("appeal has already been resolved")
+	}
+
+	status := model.AccountAppealStatusRejected
+	if approve {
+		status = model.AccountAppealStatusApproved
+	}
+
+	if err := s.AccountAppealRepo.ResolveAppeal(ctx, appealId, status, resolution); err != nil {
+		return nil, err
+	}
+
+	return s.AccountAppealRepo.GetAppeal(ctx, appealId)
+}
@@ -34,6 +34,9 @@ type Admin struct {
 	ActivityService   *Activity
 	TimeRangeService  *TimeRange
 	DropInfoService   *DropInfo
+	ItemService       *Item
+
+	MetadataSnapshotService *MetadataSnapshot
 }
 
 func NewAdmin(
@@ -46,6 +49,8 @@ func NewAdmin(
 	activityService *Activity,
 	timeRangeService *TimeRange,
 	dropInfoService *DropInfo,
+	itemService *Item,
+	metadataSnapshotService *MetadataSnapshot,
 ) *Admin {
 	return &Admin{
 		DB:                db,
@@ -57,6 +62,9 @@ func NewAdmin(
 		ActivityService:   activityService,
 		TimeRangeService:  timeRangeService,
 		DropInfoService:   dropInfoService,
+		ItemService:       itemService,
+
+		MetadataSnapshotService: metadataSnapshotService,
 	}
 }
 
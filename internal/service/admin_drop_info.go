@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+type CutOverDropInfosResult struct {
+	DryRun    bool              `json:"dryRun"`
+	TimeRange *model.TimeRange  `json:"timeRange"`
+	DropInfos []*model.DropInfo `json:"dropInfos"`
+	// Superseded are the drop infos of the stages in effect right before the cut over, per ark stage id
+	Superseded map[string][]*model.DropInfo `json:"superseded"`
+}
+
+// CutOverDropInfos creates a new version of the drop tables of the stages, effective from req.CutOverAt. The previous
+// versions are kept untouched, as their time ranges are usually shared with other stages, and are superseded by the
+// new version for the reports submitted after the cut over.
+func (s *Admin) CutOverDropInfos(ctx context.Context, req *types.CutOverDropInfosRequest) (*CutOverDropInfosResult, error) {
+	itemsMap, err := s.ItemService.GetItemsMapByArkId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := make([]types.OnboardEventViolation, 0)
+	stageIds := make(map[string]int)
+	for i, dropInfo := range req.DropInfos {
+		if dropInfo.Server != req.Server {
+			violations = append(violations, types.OnboardEventViolation{
+				Field:   fmt.Sprintf("dropInfos[%d].server", i),
+				Message: fmt.Sprintf("server must be %s", req.Server),
+			})
+		}
+		if dropInfo.ArkItemID != "" {
+			if _, ok := itemsMap[dropInfo.ArkItemID]; !ok {
+				violations = append(violations, types.OnboardEventViolation{
+					Field:   fmt.Sprintf("dropInfos[%d].itemId", i),
+					Message: fmt.Sprintf("item %s does not exist", dropInfo.ArkItemID),
+				})
+			}
+		}
+		if _, ok := stageIds[dropInfo.ArkStageID]; ok {
+			continue
+		}
+		stage, err := s.StageService.GetStageByArkId(ctx, dropInfo.ArkStageID)
+		if errors.Is(err, pgerr.ErrNotFound) {
+			violations = append(violations, types.OnboardEventViolation{
+				Field:   fmt.Sprintf("dropInfos[%d].stageId", i),
+				Message: fmt.Sprintf("stage %s does not exist", dropInfo.ArkStageID),
+			})
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		stageIds[dropInfo.ArkStageID] = stage.StageID
+	}
+
+	result := &CutOverDropInfosResult{
+		DryRun:     req.DryRun,
+		Superseded: make(map[string][]*model.DropInfo, len(stageIds)),
+	}
+	if len(violations) == 0 {
+		for arkStageId := range stageIds {
+			previous, err := s.DropInfoService.DropInfoRepo.GetForTimeRangeAt(ctx, &repo.DropInfoQuery{
+				Server:     req.Server,
+				ArkStageId: arkStageId,
+			}, req.CutOverAt)
+			if err != nil && !errors.Is(err, pgerr.ErrNotFound) {
+				return nil, err
+			}
+			if len(previous) == 0 {
+				violations = append(violations, types.OnboardEventViolation{
+					Field:   "cutOverAt",
+					Message: fmt.Sprintf("stage %s has no drop infos in effect at the cut over to supersede", arkStageId),
+				})
+				continue
+			}
+			result.Superseded[arkStageId] = previous
+		}
+	}
+	if len(violations) > 0 {
+		return nil, pgerr.NewInvalidViolations(violations)
+	}
+
+	startTime := req.CutOverAt
+	endTime := time.UnixMilli(constant.FakeEndTimeMilli)
+	result.TimeRange = &model.TimeRange{
+		Name:      req.Name,
+		StartTime: &startTime,
+		EndTime:   &endTime,
+		Comment:   req.Comment,
+		Server:    req.Server,
+	}
+	onboarded, err := buildOnboardEventObjects(&types.OnboardEventRequest{DropInfos: req.DropInfos}, itemsMap)
+	if err != nil {
+		return nil, err
+	}
+	result.DropInfos = onboarded.DropInfos
+
+	if !req.DryRun {
+		if _, err := s.MetadataSnapshotService.Capture(ctx, "cut over drop infos of "+req.Server); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		timeRanges := []*model.TimeRange{result.TimeRange}
+		if err := s.AdminRepo.SaveTimeRanges(ctx, tx, &timeRanges); err != nil {
+			return errors.Wrap(err, "failed to save time range")
+		}
+
+		for i, dropInfo := range result.DropInfos {
+			dropInfo.StageID = stageIds[req.DropInfos[i].ArkStageID]
+			dropInfo.RangeID = result.TimeRange.RangeID
+		}
+		if err := s.AdminRepo.SaveDropInfos(ctx, tx, &result.DropInfos); err != nil {
+			return errors.Wrap(err, "failed to save drop infos")
+		}
+
+		if req.DryRun {
+			return errDryRunRollback
+		}
+		return nil
+	})
+	if req.DryRun && errors.Is(err, errDryRunRollback) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, pgerr.ErrInvalidReq.Msg("failed to cut over drop infos: %s", err.Error())
+	}
+
+	purgeEventMetadataCaches([]string{req.Server})
+
+	log.Info().
+		Str("evt.name", "admin.drop_infos.cut_over").
+		Str("server", req.Server).
+		Time("cutOverAt", req.CutOverAt).
+		Int("rangeId", result.TimeRange.RangeID).
+		Int("stages", len(stageIds)).
+		Int("dropInfos", len(result.DropInfos)).
+		Msg("drop infos cut over")
+
+	return result, nil
+}
@@ -0,0 +1,450 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+)
+
+// errDryRunRollback is returned from within a dry-run transaction to roll it back
+var errDryRunRollback = errors.New("dry run: rolling back transaction")
+
+// ErrEventBundleConflict is returned when an event bundle collides with the existing metadata, e.g. the zone or a
+// stage of it has been onboarded already
+var ErrEventBundleConflict = pgerr.New(fiber.StatusConflict, "EVENT_CONFLICT", "the event bundle conflicts with the existing metadata")
+
+type OnboardEventResult struct {
+	DryRun     bool               `json:"dryRun"`
+	Zone       *model.Zone        `json:"zone"`
+	Stages     []*model.Stage     `json:"stages"`
+	TimeRanges []*model.TimeRange `json:"timeRanges"`
+	DropInfos  []*model.DropInfo  `json:"dropInfos"`
+}
+
+// OnboardEvent applies a full new-event bundle (zone, stages, time ranges and drop infos) in one transaction.
+// When req.DryRun is set, the bundle is validated and written in a transaction that is always rolled back,
+// so that database constraint violations are surfaced as well.
+func (s *Admin) OnboardEvent(ctx context.Context, req *types.OnboardEventRequest) (*OnboardEventResult, error) {
+	itemsMap, err := s.ItemService.GetItemsMapByArkId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateOnboardEvent(req, itemsMap); len(violations) > 0 {
+		return nil, pgerr.NewInvalidViolations(violations)
+	}
+
+	result, err := buildOnboardEventObjects(req, itemsMap)
+	if err != nil {
+		return nil, err
+	}
+	result.DryRun = req.DryRun
+
+	if !req.DryRun {
+		if _, err := s.MetadataSnapshotService.Capture(ctx, "onboard event "+req.Zone.ArkZoneID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		zones := []*model.Zone{result.Zone}
+		if err := s.AdminRepo.SaveZones(ctx, tx, &zones); err != nil {
+			return errors.Wrap(err, "failed to save zone")
+		}
+
+		for _, stage := range result.Stages {
+			stage.ZoneID = result.Zone.ZoneID
+		}
+		if err := s.AdminRepo.SaveStages(ctx, tx, &result.Stages); err != nil {
+			return errors.Wrap(err, "failed to save stages")
+		}
+
+		if err := s.AdminRepo.SaveTimeRanges(ctx, tx, &result.TimeRanges); err != nil {
+			return errors.Wrap(err, "failed to save time ranges")
+		}
+
+		stageIds := make(map[string]int, len(result.Stages))
+		for _, stage := range result.Stages {
+			stageIds[stage.ArkStageID] = stage.StageID
+		}
+		rangeIds := make(map[string]int, len(result.TimeRanges))
+		for _, timeRange := range result.TimeRanges {
+			rangeIds[timeRange.Server] = timeRange.RangeID
+		}
+		for i, dropInfo := range result.DropInfos {
+			dropInfo.StageID = stageIds[req.DropInfos[i].ArkStageID]
+			dropInfo.RangeID = rangeIds[dropInfo.Server]
+		}
+		if len(result.DropInfos) > 0 {
+			if err := s.AdminRepo.SaveDropInfos(ctx, tx, &result.DropInfos); err != nil {
+				return errors.Wrap(err, "failed to save drop infos")
+			}
+		}
+
+		if req.DryRun {
+			return errDryRunRollback
+		}
+		return nil
+	})
+	if req.DryRun && errors.Is(err, errDryRunRollback) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, eventBundleError(err)
+	}
+
+	servers := make([]string, 0, len(result.TimeRanges))
+	for _, timeRange := range result.TimeRanges {
+		servers = append(servers, timeRange.Server)
+	}
+	purgeEventMetadataCaches(servers)
+
+	log.Info().
+		Str("evt.name", "admin.event.onboarded").
+		Str("arkZoneId", result.Zone.ArkZoneID).
+		Int("stages", len(result.Stages)).
+		Int("timeRanges", len(result.TimeRanges)).
+		Int("dropInfos", len(result.DropInfos)).
+		Msg("event onboarded")
+
+	return result, nil
+}
+
+// eventBundleError tells the bundles rejected by the database constraints, which the maintainer has to fix, from the
+// failures of the database, of which only a generic error is returned while the cause is logged
+func eventBundleError(err error) error {
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) {
+		constraint := pgErr.Field('n')
+		switch code := pgErr.Field('C'); {
+		case code == "23505":
+			return ErrEventBundleConflict.Msg("the event bundle conflicts with the existing metadata on %s", constraint)
+		case pgErr.IntegrityViolation():
+			return pgerr.ErrInvalidReq.Msg("the event bundle violates the constraint %s", constraint)
+		case strings.HasPrefix(code, "22"):
+			// data exceptions, e.g. a value out of range or too long for its column
+			return pgerr.ErrInvalidReq.Msg("the event bundle has a value not accepted by the database")
+		}
+	}
+
+	log.Error().
+		Str("evt.name", "admin.event.onboard_failed").
+		Err(err).
+		Msg("failed to apply event bundle")
+	return pgerr.ErrInternalError
+}
+
+func validateOnboardEvent(req *types.OnboardEventRequest, itemsMap map[string]*model.Item) []types.OnboardEventViolation {
+	violations := make([]types.OnboardEventViolation, 0)
+	violate := func(field string, format string, args ...any) {
+		violations = append(violations, types.OnboardEventViolation{
+			Field:   field,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	stages := make(map[string]struct{}, len(req.Stages))
+	for i, stage := range req.Stages {
+		if _, ok := stages[stage.ArkStageID]; ok {
+			violate(fmt.Sprintf("stages[%d].stageId", i), "duplicated stage %s", stage.ArkStageID)
+		}
+		stages[stage.ArkStageID] = struct{}{}
+	}
+
+	servers := make(map[string]struct{}, len(req.TimeRanges))
+	for i, timeRange := range req.TimeRanges {
+		if _, ok := servers[timeRange.Server]; ok {
+			violate(fmt.Sprintf("timeRanges[%d].server", i), "only one time range per server is allowed, found another one for %s", timeRange.Server)
+		}
+		servers[timeRange.Server] = struct{}{}
+
+		if timeRange.EndTime != nil && !timeRange.EndTime.After(timeRange.StartTime) {
+			violate(fmt.Sprintf("timeRanges[%d].endTime", i), "endTime must be after startTime")
+		}
+	}
+
+	for i, dropInfo := range req.DropInfos {
+		if _, ok := stages[dropInfo.ArkStageID]; !ok {
+			violate(fmt.Sprintf("dropInfos[%d].stageId", i), "stage %s is not part of the bundle", dropInfo.ArkStageID)
+		}
+		if _, ok := servers[dropInfo.Server]; !ok {
+			violate(fmt.Sprintf("dropInfos[%d].server", i), "no time range is defined for server %s", dropInfo.Server)
+		}
+		if dropInfo.ArkItemID != "" {
+			if _, ok := itemsMap[dropInfo.ArkItemID]; !ok {
+				violate(fmt.Sprintf("dropInfos[%d].itemId", i), "item %s does not exist", dropInfo.ArkItemID)
+			}
+		}
+	}
+
+	return violations
+}
+
+func buildOnboardEventObjects(req *types.OnboardEventRequest, itemsMap map[string]*model.Item) (*OnboardEventResult, error) {
+	zoneName, err := json.Marshal(req.Zone.Name)
+	if err != nil {
+		return nil, err
+	}
+	existence, err := json.Marshal(req.Zone.Existence)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OnboardEventResult{
+		Zone: &model.Zone{
+			ArkZoneID:  req.Zone.ArkZoneID,
+			Index:      req.Zone.Index,
+			Category:   req.Zone.Category,
+			Type:       req.Zone.Type,
+			Name:       zoneName,
+			Existence:  existence,
+			Background: req.Zone.Background,
+		},
+		Stages:     make([]*model.Stage, 0, len(req.Stages)),
+		TimeRanges: make([]*model.TimeRange, 0, len(req.TimeRanges)),
+		DropInfos:  make([]*model.DropInfo, 0, len(req.DropInfos)),
+	}
+
+	for _, stage := range req.Stages {
+		code, err := json.Marshal(stage.Code)
+		if err != nil {
+			return nil, err
+		}
+		result.Stages = append(result.Stages, &model.Stage{
+			ArkStageID:       stage.ArkStageID,
+			StageType:        stage.StageType,
+			ExtraProcessType: stage.ExtraProcessType,
+			Code:             code,
+			Sanity:           stage.Sanity,
+			Existence:        existence,
+			MinClearTime:     stage.MinClearTime,
+		})
+	}
+
+	for _, timeRange := range req.TimeRanges {
+		startTime := timeRange.StartTime
+		endTime := time.UnixMilli(constant.FakeEndTimeMilli)
+		if timeRange.EndTime != nil {
+			endTime = *timeRange.EndTime
+		}
+		result.TimeRanges = append(result.TimeRanges, &model.TimeRange{
+			Name:      timeRange.Name,
+			StartTime: &startTime,
+			EndTime:   &endTime,
+			Comment:   timeRange.Comment,
+			Server:    timeRange.Server,
+		})
+	}
+
+	for _, dropInfo := range req.DropInfos {
+		itemId := null.Int{}
+		if dropInfo.ArkItemID != "" {
+			itemId = null.IntFrom(int64(itemsMap[dropInfo.ArkItemID].ItemID))
+		}
+		var bounds *model.Bounds
+		if dropInfo.Bounds != nil {
+			bounds = &model.Bounds{
+				Upper:      dropInfo.Bounds.Upper,
+				Lower:      dropInfo.Bounds.Lower,
+				Exceptions: dropInfo.Bounds.Exceptions,
+			}
+		}
+		result.DropInfos = append(result.DropInfos, &model.DropInfo{
+			Server:      dropInfo.Server,
+			ItemID:      itemId,
+			DropType:    dropInfo.DropType,
+			Accumulable: dropInfo.Accumulable,
+			Bounds:      bounds,
+			Extras:      dropInfo.Extras,
+		})
+	}
+
+	return result, nil
+}
+
+// purgeEventMetadataCaches purges the caches of zones, stages and time ranges of the given servers
+func purgeEventMetadataCaches(servers []string) {
+	cache.Zones.Delete()
+	cache.ShimZones.Delete()
+	cache.ZoneByArkID.Flush()
+	cache.ShimZoneByArkID.Flush()
+
+	cache.Stages.Delete()
+	cache.StageByArkID.Flush()
+	cache.ShimStageByArkID.Flush()
+	cache.StagesMapByID.Delete()
+	cache.StagesMapByArkID.Delete()
+	for _, server := range constant.Servers {
+		cache.ShimStages.Delete(server)
+	}
+	cache.MetaBundle.Delete()
+	cache.UIConfigs.Flush()
+	// the registry leaves out the hashes of the deleted stages
+	cache.RecognitionStageHashes.Flush()
+
+	for _, server := range servers {
+		cache.TimeRanges.Delete(server)
+		cache.TimeRangesMap.Delete(server)
+		cache.MaxAccumulableTimeRanges.Delete(server)
+		cache.AllMaxAccumulableTimeRanges.Delete(server)
+		cache.LatestTimeRanges.Delete(server)
+	}
+}
+
+// CloneRerunEvent copies the zone, stages and drop infos of a past event into a new time range of the target server,
+// remapping ark stage ids when the event is cloned into a different zone. The clone is applied via OnboardEvent.
+func (s *Admin) CloneRerunEvent(ctx context.Context, req *types.CloneRerunEventRequest) (*OnboardEventResult, error) {
+	sourceZone, err := s.ZoneService.GetZoneByArkId(ctx, req.SourceArkZoneID)
+	if err != nil {
+		return nil, err
+	}
+	sourceStages, err := s.StageService.GetStagesByZoneId(ctx, sourceZone.ZoneID)
+	if err != nil {
+		return nil, err
+	}
+	if len(sourceStages) == 0 {
+		return nil, pgerr.ErrInvalidReq.Msg("source zone %s has no stages", req.SourceArkZoneID)
+	}
+
+	sourceRangeId := req.SourceRangeID
+	if sourceRangeId == 0 {
+		latestTimeRanges, err := s.TimeRangeService.GetLatestTimeRangesByServer(ctx, req.SourceServer)
+		if err != nil {
+			return nil, err
+		}
+		for _, stage := range sourceStages {
+			if timeRange, ok := latestTimeRanges[stage.StageID]; ok {
+				sourceRangeId = timeRange.RangeID
+				break
+			}
+		}
+		if sourceRangeId == 0 {
+			return nil, pgerr.ErrInvalidReq.Msg("no drop infos found for zone %s in server %s", req.SourceArkZoneID, req.SourceServer)
+		}
+	}
+
+	sourceDropInfos, err := s.DropInfoService.DropInfoRepo.GetDropInfosByServerAndRangeId(ctx, req.SourceServer, sourceRangeId)
+	if err != nil {
+		return nil, err
+	}
+	itemsMap, err := s.ItemService.GetItemsMapById(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	targetArkZoneId := req.TargetArkZoneID
+	if targetArkZoneId == "" {
+		targetArkZoneId = req.SourceArkZoneID
+	}
+	sameZone := targetArkZoneId == req.SourceArkZoneID
+
+	bundle := &types.OnboardEventRequest{
+		DryRun: req.DryRun,
+		Zone: types.OnboardEventZone{
+			ArkZoneID:  targetArkZoneId,
+			Index:      sourceZone.Index,
+			Category:   sourceZone.Category,
+			Type:       sourceZone.Type,
+			Background: sourceZone.Background,
+		},
+		TimeRanges: []types.OnboardEventRange{req.TimeRange},
+	}
+
+	if req.TargetZoneName != nil {
+		bundle.Zone.Name = *req.TargetZoneName
+	} else if err := json.Unmarshal(sourceZone.Name, &bundle.Zone.Name); err != nil {
+		return nil, errors.Wrap(err, "failed to parse source zone name")
+	}
+
+	// a clone into the same zone keeps the existence of other servers untouched
+	if sameZone {
+		if err := json.Unmarshal(sourceZone.Existence, &bundle.Zone.Existence); err != nil {
+			return nil, errors.Wrap(err, "failed to parse source zone existence")
+		}
+	}
+	openTime := req.TimeRange.StartTime.UnixMilli()
+	targetExistence := types.ServerExistence{Exist: true, OpenTime: &openTime}
+	if req.TimeRange.EndTime != nil {
+		closeTime := req.TimeRange.EndTime.UnixMilli()
+		targetExistence.CloseTime = &closeTime
+	}
+	switch req.TimeRange.Server {
+	case "CN":
+		bundle.Zone.Existence.CN = targetExistence
+	case "US":
+		bundle.Zone.Existence.US = targetExistence
+	case "JP":
+		bundle.Zone.Existence.JP = targetExistence
+	case "KR":
+		bundle.Zone.Existence.KR = targetExistence
+	}
+
+	stageIdMap := make(map[int]string, len(sourceStages))
+	for _, stage := range sourceStages {
+		arkStageId := stage.ArkStageID
+		if mapped, ok := req.StageIDMap[arkStageId]; ok {
+			arkStageId = mapped
+		} else if !sameZone {
+			arkStageId += constant.RerunStageIdSuffix
+		}
+		stageIdMap[stage.StageID] = arkStageId
+
+		onboardStage := types.OnboardEventStage{
+			ArkStageID:       arkStageId,
+			StageType:        stage.StageType,
+			ExtraProcessType: stage.ExtraProcessType,
+			Sanity:           stage.Sanity,
+			MinClearTime:     stage.MinClearTime,
+		}
+		if err := json.Unmarshal(stage.Code, &onboardStage.Code); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse code of stage %s", stage.ArkStageID)
+		}
+		bundle.Stages = append(bundle.Stages, onboardStage)
+	}
+
+	for _, dropInfo := range sourceDropInfos {
+		arkStageId, ok := stageIdMap[dropInfo.StageID]
+		if !ok {
+			continue
+		}
+		onboardDropInfo := types.OnboardEventDropInfo{
+			Server:      req.TimeRange.Server,
+			ArkStageID:  arkStageId,
+			DropType:    dropInfo.DropType,
+			Accumulable: dropInfo.Accumulable,
+			Extras:      dropInfo.Extras,
+		}
+		if dropInfo.ItemID.Valid {
+			item, ok := itemsMap[int(dropInfo.ItemID.Int64)]
+			if !ok {
+				return nil, pgerr.ErrInvalidReq.Msg("item %d referenced by drop info %d does not exist", dropInfo.ItemID.Int64, dropInfo.DropID)
+			}
+			onboardDropInfo.ArkItemID = item.ArkItemID
+		}
+		if dropInfo.Bounds != nil {
+			onboardDropInfo.Bounds = &types.OnboardEventBounds{
+				Upper:      dropInfo.Bounds.Upper,
+				Lower:      dropInfo.Bounds.Lower,
+				Exceptions: dropInfo.Bounds.Exceptions,
+			}
+		}
+		bundle.DropInfos = append(bundle.DropInfos, onboardDropInfo)
+	}
+
+	return s.OnboardEvent(ctx, bundle)
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	"exusiai.dev/backend-next/internal/model/types"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/coalesce"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+)
+
+// advancedQueries shares the computation of the concurrent advanced queries with identical normalized parameters
+var advancedQueries coalesce.Group[any]
+
+// AdvancedQuery runs the batches of advanced queries of the v3 API, which mix drop matrices, trends and pattern
+// matrices of custom time ranges, concurrently and within a budget per batch
+type AdvancedQuery struct {
+	Config               *appconfig.Config
+	DropMatrixService    *DropMatrix
+	TrendService         *Trend
+	PatternMatrixService *PatternMatrix
+	StageService         *Stage
+	ItemService          *Item
+	QueryCostService     *QueryCost
+	RetentionService     *Retention
+	Tunables             *Tunables
+}
+
+func NewAdvancedQuery(
+	conf *appconfig.Config,
+	dropMatrixService *DropMatrix,
+	trendService *Trend,
+	patternMatrixService *PatternMatrix,
+	stageService *Stage,
+	itemService *Item,
+	queryCostService *QueryCost,
+	retentionService *Retention,
+	tunables *Tunables,
+) *AdvancedQuery {
+	return &AdvancedQuery{
+		Config:               conf,
+		DropMatrixService:    dropMatrixService,
+		TrendService:         trendService,
+		PatternMatrixService: patternMatrixService,
+		StageService:         stageService,
+		ItemService:          itemService,
+		QueryCostService:     queryCostService,
+		RetentionService:     retentionService,
+		Tunables:             tunables,
+	}
+}
+
+// Cached runs the query of the key, sharing its computation with the concurrent identical ones. The results of the
+// anonymous queries are also cached for a short while, so that the popular ones, e.g. those shared as short links,
+// are not computed again upon every view.
+func (s *AdvancedQuery) Cached(key string, anonymous bool, fn func() (any, error)) (any, error) {
+	if !anonymous {
+		return advancedQueries.Do(key, fn)
+	}
+
+	var cached any
+	if err := cache.CustomizedResults.Get(key, &cached); err == nil {
+		return cached, nil
+	}
+	return advancedQueries.Do(key, func() (any, error) {
+		result, err := fn()
+		if err != nil {
+			return nil, err
+		}
+		cache.CustomizedResults.Set(key, result, s.Tunables.Duration(TunableCustomizedCacheTTL))
+		return result, nil
+	})
+}
+
+// RunAdvancedQueries runs the queries, the personal ones for the account, and keys their results by their indices.
+// A failed query does not fail the batch but is responded with its error, including the queries still running once
+// the budget has been spent.
+func (s *AdvancedQuery) RunAdvancedQueries(ctx context.Context, queries []*types.AdvancedQueryV3, accountId null.Int) *modelv3.AdvancedQueryResult {
+	if s.Config.AdvancedQueryBudget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Config.AdvancedQueryBudget)
+		defer cancel()
+	}
+
+	results := make([]*modelv3.OneAdvancedQueryResult, len(queries))
+	var eg errgroup.Group
+	eg.SetLimit(lo.Max([]int{s.Config.AdvancedQueryConcurrency, 1}))
+	for i, query := range queries {
+		i, query := i, query
+		eg.Go(func() error {
+			queryAccountId := null.NewInt(0, false)
+			if query.IsPersonal.Valid && query.IsPersonal.Bool {
+				queryAccountId = accountId
+			}
+			result := &modelv3.OneAdvancedQueryResult{Type: query.Type}
+			res, err := s.runAdvancedQuery(ctx, query, queryAccountId)
+			if err != nil {
+				result.Error = toPenguinError(err)
+			} else {
+				result.Result = res
+			}
+			results[i] = result
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	keyed := make(map[int]*modelv3.OneAdvancedQueryResult, len(results))
+	for i, result := range results {
+		keyed[i] = result
+	}
+	return &modelv3.AdvancedQueryResult{Results: keyed}
+}
+
+func (s *AdvancedQuery) runAdvancedQuery(ctx context.Context, query *types.AdvancedQueryV3, accountId null.Int) (any, error) {
+	switch {
+	case query.Type == types.AdvancedQueryTypeTrend && !query.Interval.Valid:
+		return nil, pgerr.ErrInvalidReq.Msg("interval is required by trend queries")
+	case query.Type == types.AdvancedQueryTypePattern && query.Interval.Valid:
+		return nil, pgerr.ErrInvalidReq.Msg("interval is not accepted by pattern queries")
+	case query.Type == types.AdvancedQueryTypePattern && len(query.ItemIDs) > 0:
+		return nil, pgerr.ErrInvalidReq.Msg("itemIds are not accepted by pattern queries")
+	}
+
+	startTimeMilli := constant.ServerStartTimeMapMillis[query.Server]
+	if query.StartTime != 0 {
+		startTimeMilli = query.StartTime
+	}
+	startTime := time.UnixMilli(startTimeMilli)
+	endTime := time.Now()
+	if query.EndTime != 0 {
+		endTime = time.UnixMilli(query.EndTime)
+	}
+	if err := s.QueryCostService.Admit(startTime, endTime, 1, len(query.ItemIDs)); err != nil {
+		return nil, err
+	}
+	// the results are rather rejected than calculated from the reports left after the purge
+	if err := s.RetentionService.CheckNotPurged(startTime); err != nil {
+		return nil, err
+	}
+
+	stage, err := s.StageService.GetStageByArkId(ctx, query.StageID)
+	if err != nil {
+		return nil, err
+	}
+	itemsMapByArkId, err := s.ItemService.GetItemsByArkIds(ctx, query.ItemIDs)
+	if err != nil {
+		return nil, err
+	}
+	itemIds := make([]int, 0, len(query.ItemIDs))
+	for _, arkItemId := range query.ItemIDs {
+		item, ok := itemsMapByArkId[arkItemId]
+		if !ok {
+			return nil, pgerr.ErrInvalidReq.Msg("unknown item %q", arkItemId)
+		}
+		itemIds = append(itemIds, item.ItemID)
+	}
+
+	sourceCategory := query.SourceCategory
+	if sourceCategory == "" {
+		sourceCategory = constant.SourceCategoryAll
+	}
+
+	// an open end is keyed as is rather than by the time of the request, so that the concurrent ones share the query
+	sortedItemIds := append([]int(nil), itemIds...)
+	sort.Ints(sortedItemIds)
+	accountKey := ""
+	if accountId.Valid {
+		accountKey = strconv.FormatInt(accountId.Int64, 10)
+	}
+	key := coalesce.Key("advanced", query.Type, query.Server, strconv.Itoa(stage.StageID), fmt.Sprint(sortedItemIds), accountKey,
+		sourceCategory, strconv.FormatInt(startTimeMilli, 10), strconv.FormatInt(query.EndTime, 10), strconv.FormatInt(query.Interval.Int64, 10))
+
+	if query.Type == types.AdvancedQueryTypePattern {
+		timeRange := &model.TimeRange{
+			StartTime: &startTime,
+			EndTime:   &endTime,
+		}
+		return s.Cached(key, !accountId.Valid, func() (any, error) {
+			return s.PatternMatrixService.GetShimCustomizedPatternMatrixResults(ctx, query.Server, timeRange, []int{stage.StageID}, accountId, sourceCategory)
+		})
+	}
+	if query.Type == types.AdvancedQueryTypeMatrix && !query.Interval.Valid {
+		timeRange := &model.TimeRange{
+			StartTime: &startTime,
+			EndTime:   &endTime,
+		}
+		return s.Cached(key, !accountId.Valid, func() (any, error) {
+			return s.DropMatrixService.GetShimCustomizedDropMatrixResults(ctx, query.Server, timeRange, []int{stage.StageID}, itemIds, accountId, sourceCategory)
+		})
+	}
+
+	// interval is in milliseconds
+	intervalLength := time.Duration(query.Interval.Int64 * 1e6).Round(time.Hour)
+	if intervalLength.Hours() < 1 {
+		return nil, pgerr.ErrInvalidReq.Msg("interval length must be greater than 1 hour")
+	}
+	intervalNum := int(endTime.Sub(startTime).Hours()) / int(intervalLength.Hours())
+	if intervalNum > constant.MaxIntervalNum {
+		return nil, pgerr.ErrInvalidReq.Msg("too many sections: interval number is %d sections, which is larger than %d sections", intervalNum, constant.MaxIntervalNum)
+	}
+	if query.Type == types.AdvancedQueryTypeMatrix {
+		return s.Cached(key, !accountId.Valid, func() (any, error) {
+			return s.DropMatrixService.GetShimSegmentedDropMatrixResults(ctx, query.Server, &startTime, intervalLength, intervalNum, []int{stage.StageID}, itemIds, accountId, sourceCategory)
+		})
+	}
+	return s.Cached(key, !accountId.Valid, func() (any, error) {
+		return s.TrendService.GetShimCustomizedTrendResults(ctx, query.Server, &startTime, intervalLength, intervalNum, []int{stage.StageID}, itemIds, accountId, sourceCategory)
+	})
+}
+
+// toPenguinError responds the error of a query as the error handler would have responded it on its own
+func toPenguinError(err error) *pgerr.PenguinError {
+	var penguinErr *pgerr.PenguinError
+	if errors.As(err, &penguinErr) {
+		return penguinErr
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return pgerr.ErrTimeout
+	}
+
+	log.Error().
+		Err(err).
+		Str("evt.name", "advanced.query.failed").
+		Msg("advanced query failed")
+	return pgerr.ErrInternalError
+}
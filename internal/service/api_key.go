@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+const (
+	// apiKeyPrefix marks a string as a Penguin Statistics API key, which helps secret scanners to spot leaked keys
+	apiKeyPrefix = "pgk_"
+	// apiKeyBytes is the entropy of a generated API key
+	apiKeyBytes = 24
+	// apiKeyDisplayPrefixLen is the number of leading characters of a key kept in plain text
+	apiKeyDisplayPrefixLen = len(apiKeyPrefix) + 8
+)
+
+type APIKey struct {
+	Config     *appconfig.Config
+	APIKeyRepo *repo.APIKey
+}
+
+func NewAPIKey(conf *appconfig.Config, apiKeyRepo *repo.APIKey) *APIKey {
+	return &APIKey{
+		Config:     conf,
+		APIKeyRepo: apiKeyRepo,
+	}
+}
+
+type CreateAPIKeyResult struct {
+	APIKey *model.APIKey `json:"apiKey"`
+	// Key is not stored in plain text and cannot be retrieved again
+	Key string `json:"key"`
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *APIKey) CreateAPIKey(ctx context.Context, req *types.CreateAPIKeyRequest) (*CreateAPIKeyResult, error) {
+	b := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	key := apiKeyPrefix + hex.EncodeToString(b)
+
+	apiKey := &model.APIKey{
+		Name:              req.Name,
+		Contact:           req.Contact,
+		Prefix:            key[:apiKeyDisplayPrefixLen],
+		KeyHash:           hashAPIKey(key),
+		DailyRequestQuota: s.Config.APIKeyDefaultDailyRequestQuota,
+		DailyByteQuota:    s.Config.APIKeyDefaultDailyByteQuota,
+	}
+	if req.DailyRequestQuota != nil {
+		apiKey.DailyRequestQuota = *req.DailyRequestQuota
+	}
+	if req.DailyByteQuota != nil {
+		apiKey.DailyByteQuota = *req.DailyByteQuota
+	}
+	if err := s.APIKeyRepo.CreateAPIKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("evt.name", "apikey.created").
+		Int("apiKeyId", apiKey.APIKeyID).
+		Str("name", apiKey.Name).
+		Msg("created api key")
+
+	return &CreateAPIKeyResult{APIKey: apiKey, Key: key}, nil
+}
+
+func (s *APIKey) GetAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	return s.APIKeyRepo.GetAPIKeys(ctx)
+}
+
+func (s *APIKey) GetAPIKeyById(ctx context.Context, apiKeyId int) (*model.APIKey, error) {
+	return s.APIKeyRepo.GetAPIKeyById(ctx, apiKeyId)
+}
+
+func (s *APIKey) RevokeAPIKey(ctx context.Context, apiKeyId int) (*model.APIKey, error) {
+	if _, err := s.APIKeyRepo.GetAPIKeyById(ctx, apiKeyId); err != nil {
+		return nil, err
+	}
+	if err := s.APIKeyRepo.RevokeAPIKey(ctx, apiKeyId); err != nil {
+		return nil, err
+	}
+	// the cache is keyed by hash, which we do not know
+	cache.APIKeyByKeyHash.Flush()
+
+	log.Info().
+		Str("evt.name", "apikey.revoked").
+		Int("apiKeyId", apiKeyId).
+		Msg("revoked api key")
+
+	return s.APIKeyRepo.GetAPIKeyById(ctx, apiKeyId)
+}
+
+// UpdateAPIKeyQuota updates the quotas given in the request, leaving the others unchanged
+func (s *APIKey) UpdateAPIKeyQuota(ctx context.Context, apiKeyId int, req *types.UpdateAPIKeyQuotaRequest) (*model.APIKey, error) {
+	apiKey, err := s.APIKeyRepo.GetAPIKeyById(ctx, apiKeyId)
+	if err != nil {
+		return nil, err
+	}
+	if req.DailyRequestQuota != nil {
+		apiKey.DailyRequestQuota = *req.DailyRequestQuota
+	}
+	if req.DailyByteQuota != nil {
+		apiKey.DailyByteQuota = *req.DailyByteQuota
+	}
+
+	if err := s.APIKeyRepo.UpdateAPIKeyQuota(ctx, apiKeyId, apiKey.DailyRequestQuota, apiKey.DailyByteQuota); err != nil {
+		return nil, err
+	}
+	cache.APIKeyByKeyHash.Flush()
+
+	log.Info().
+		Str("evt.name", "apikey.quota.updated").
+		Int("apiKeyId", apiKeyId).
+		Int("dailyRequestQuota", apiKey.DailyRequestQuota).
+		Int64("dailyByteQuota", apiKey.DailyByteQuota).
+		Msg("updated api key quota")
+
+	return apiKey, nil
+}
+
+// Cache: apiKey#keyHash:{keyHash}, 1 min
+func (s *APIKey) AuthenticateAPIKey(ctx context.Context, key string) (*model.APIKey, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, pgerr.ErrNotFound
+	}
+	keyHash := hashAPIKey(key)
+
+	var apiKey model.APIKey
+	err := cache.APIKeyByKeyHash.Get(keyHash, &apiKey)
+	if err == nil {
+		return &apiKey, nil
+	}
+
+	dbAPIKey, err := s.APIKeyRepo.GetActiveAPIKeyByHash(ctx, keyHash)
+	if err != nil {
+		return nil, err
+	}
+	cache.APIKeyByKeyHash.Set(keyHash, *dbAPIKey, time.Minute)
+	return dbAPIKey, nil
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"exusiai.dev/backend-next/internal/model"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+)
+
+const (
+	apiUsageRedisPrefix = "apiusage:"
+	apiUsageDateLayout  = "2006-01-02"
+
+	// APIUsageRetentionDays is how long the daily usage of API keys is kept for
+	APIUsageRetentionDays = 31
+)
+
+// APIUsage accounts the requests and response bytes of API keys per UTC day in redis, and enforces their quotas.
+// The quotas are checked before a request and accounted after it, therefore concurrent requests may slightly
+// overshoot a quota.
+type APIUsage struct {
+	Redis *redis.Client
+}
+
+func NewAPIUsage(redisClient *redis.Client) *APIUsage {
+	return &APIUsage{
+		Redis: redisClient,
+	}
+}
+
+func apiUsageKey(apiKeyId int, date string) string {
+	return apiUsageRedisPrefix + strconv.Itoa(apiKeyId) + ":" + date
+}
+
+// Admit returns ErrTooManyRequests when the API key has exhausted any of its quotas for today,
+// or otherwise today's usage so far
+func (s *APIUsage) Admit(ctx context.Context, apiKey *model.APIKey) (*modelv3.DailyAPIUsage, error) {
+	usage, err := s.getDailyUsage(ctx, apiKey.APIKeyID, time.Now().UTC().Format(apiUsageDateLayout))
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey.DailyRequestQuota > 0 && usage.Requests >= int64(apiKey.DailyRequestQuota) {
+		return nil, pgerr.ErrTooManyRequests.Msg("daily request quota of %d requests exceeded; the quota resets at 00:00 UTC", apiKey.DailyRequestQuota)
+	}
+	if apiKey.DailyByteQuota > 0 && usage.Bytes >= apiKey.DailyByteQuota {
+		return nil, pgerr.ErrTooManyRequests.Msg("daily byte quota of %d bytes exceeded; the quota resets at 00:00 UTC", apiKey.DailyByteQuota)
+	}
+	return usage, nil
+}
+
+func (s *APIUsage) Record(ctx context.Context, apiKey *model.APIKey, bytes int) error {
+	key := apiUsageKey(apiKey.APIKeyID, time.Now().UTC().Format(apiUsageDateLayout))
+
+	_, err := s.Redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HIncrBy(ctx, key, "requests", 1)
+		pipe.HIncrBy(ctx, key, "bytes", int64(bytes))
+		pipe.Expire(ctx, key, APIUsageRetentionDays*24*time.Hour)
+		return nil
+	})
+	return err
+}
+
+// GetAPIUsage returns the quota of the API key along with its usage of the recent days, up to APIUsageRetentionDays
+func (s *APIUsage) GetAPIUsage(ctx context.Context, apiKey *model.APIKey, days int) (*modelv3.APIUsage, error) {
+	if days <= 0 || days > APIUsageRetentionDays {
+		return nil, pgerr.ErrInvalidReq.Msg("days must be between 1 and %d", APIUsageRetentionDays)
+	}
+
+	today := time.Now().UTC()
+	cmds := make([]*redis.SliceCmd, days)
+	dates := make([]string, days)
+	_, err := s.Redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i := 0; i < days; i++ {
+			dates[i] = today.AddDate(0, 0, -i).Format(apiUsageDateLayout)
+			cmds[i] = pipe.HMGet(ctx, apiUsageKey(apiKey.APIKeyID, dates[i]), "requests", "bytes")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &modelv3.APIUsage{
+		APIKeyID:          apiKey.APIKeyID,
+		Name:              apiKey.Name,
+		Prefix:            apiKey.Prefix,
+		DailyRequestQuota: apiKey.DailyRequestQuota,
+		DailyByteQuota:    apiKey.DailyByteQuota,
+		Days:              make([]*modelv3.DailyAPIUsage, days),
+	}
+	for i, cmd := range cmds {
+		usage.Days[i] = dailyAPIUsageFrom(dates[i], cmd.Val())
+	}
+	return usage, nil
+}
+
+func (s *APIUsage) getDailyUsage(ctx context.Context, apiKeyId int, date string) (*modelv3.DailyAPIUsage, error) {
+	values, err := s.Redis.HMGet(ctx, apiUsageKey(apiKeyId, date), "requests", "bytes").Result()
+	if err != nil {
+		return nil, err
+	}
+	return dailyAPIUsageFrom(date, values), nil
+}
+
+func dailyAPIUsageFrom(date string, values []any) *modelv3.DailyAPIUsage {
+	usage := &modelv3.DailyAPIUsage{Date: date}
+	if len(values) == 2 {
+		usage.Requests = parseRedisInt(values[0])
+		usage.Bytes = parseRedisInt(values[1])
+	}
+	return usage
+}
+
+// parseRedisInt parses the value of a HMGET field, which is nil for missing fields
+func parseRedisInt(v any) int64 {
+	s, ok := v.(string)
+	if !ok {
+		return 0
+	}
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
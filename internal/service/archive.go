@@ -2,20 +2,21 @@ package service
 
 import (
 	"context"
+	"sync"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/go-redsync/redsync/v4"
+	"github.com/goccy/go-json"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
-	"github.com/uptrace/bun"
+	"go.uber.org/fx"
 	"golang.org/x/sync/errgroup"
 
 	"exusiai.dev/backend-next/internal/app/appconfig"
 	"exusiai.dev/backend-next/internal/model"
 	"exusiai.dev/backend-next/internal/pkg/archiver"
+	"exusiai.dev/backend-next/internal/pkg/pgpool"
 )
 
 const (
@@ -25,36 +26,45 @@ const (
 	ArchiveS3Prefix = "v1/"
 )
 
+var (
+	ErrArchiveShuttingDown = errors.New("archive: shutting down")
+	ErrArchiveNotFound     = errors.New("archive: not found")
+)
+
 type Archive struct {
 	DropReportService      *DropReport
 	DropReportExtraService *DropReportExtra
 	Config                 *appconfig.Config
+	Jobs                   *Jobs
 
 	s3Client *s3.Client
 	lock     *redsync.Mutex
-	db       *bun.DB
+	pools    *pgpool.Pools
 
 	dropReportsArchiver      *archiver.Archiver
 	dropReportExtrasArchiver *archiver.Archiver
+
+	// mu guards draining, which refuses new archives upon shutdown, and the addition to running
+	mu       sync.Mutex
+	draining bool
+	// running tracks the archive in progress, so that shutdown does not truncate the files being uploaded
+	running sync.WaitGroup
 }
 
-func NewArchive(dropReportService *DropReport, dropReportExtraService *DropReportExtra, conf *appconfig.Config, lock *redsync.Redsync, db *bun.DB) (*Archive, error) {
-	cfg, err := config.LoadDefaultConfig(context.Background(),
-		config.WithRegion(conf.DropReportArchiveS3Region),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(conf.AWSAccessKey, conf.AWSSecretKey, "")),
-	)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to load aws config")
-	}
-	s3Client := s3.NewFromConfig(cfg)
+// archiveJobPayload is the payload of the archive jobs
+type archiveJobPayload struct {
+	Date string `json:"date"`
+}
 
-	return &Archive{
+func NewArchive(dropReportService *DropReport, dropReportExtraService *DropReportExtra, conf *appconfig.Config, s3Client *s3.Client, lock *redsync.Redsync, pools *pgpool.Pools, jobs *Jobs, lc fx.Lifecycle) (*Archive, error) {
+	s := &Archive{
+		Jobs:                   jobs,
 		DropReportService:      dropReportService,
 		DropReportExtraService: dropReportExtraService,
 		Config:                 conf,
 		s3Client:               s3Client,
 		lock:                   lock.NewMutex("mutex:archiver", redsync.WithExpiry(30*time.Minute), redsync.WithTries(2)),
-		db:                     db,
+		pools:                  pools,
 		dropReportsArchiver: &archiver.Archiver{
 			S3Client:  s3Client,
 			S3Bucket:  conf.DropReportArchiveS3Bucket,
@@ -67,15 +77,138 @@ func NewArchive(dropReportService *DropReport, dropReportExtraService *DropRepor
 			S3Prefix:  ArchiveS3Prefix,
 			RealmName: RealmDropReportExtras,
 		},
-	}, nil
+	}
+	lc.Append(fx.Hook{
+		OnStop: s.drain,
+	})
+	jobs.Register(model.JobKindArchive, JobPolicy{
+		MaxAttempts: 3,
+		Backoff:     time.Minute * 5,
+		// the archive lock expires after 30 minutes
+		Timeout:   time.Minute * 30,
+		Singleton: true,
+	}, s.handle)
+	return s, nil
+}
+
+func (s *Archive) handle(ctx context.Context, job *model.Job) error {
+	var payload archiveJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return err
+	}
+	date, err := time.Parse("2006-01-02", payload.Date)
+	if err != nil {
+		return err
+	}
+	return s.ArchiveByDate(ctx, date, s.Config.DeleteDropReportAfterArchive)
+}
+
+// drain refuses new archives and waits for the one in progress, if any, to finish
+func (s *Archive) drain(ctx context.Context) error {
+	s.mu.Lock()
+	s.draining = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "failed to wait for the archive in progress")
+	}
+}
+
+// Ping checks whether the archive bucket is reachable with the configured credentials
+func (s *Archive) Ping(ctx context.Context) error {
+	_, err := s.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: &s.Config.DropReportArchiveS3Bucket,
+	})
+	return err
+}
+
+// IsArchived tells whether both the drop reports and their extras of the date have been archived
+func (s *Archive) IsArchived(ctx context.Context, date time.Time) (bool, error) {
+	for _, a := range []*archiver.Archiver{s.dropReportsArchiver, s.dropReportExtrasArchiver} {
+		exists, err := a.Exists(ctx, date)
+		if err != nil || !exists {
+			return false, err
+		}
+	}
+	return true, nil
 }
 
-func (s *Archive) ArchiveByGlobalConfig(ctx context.Context) error {
-	targetDay := time.Now().AddDate(0, 0, -1*s.Config.NoArchiveDays)
-	return s.ArchiveByDate(ctx, targetDay, s.Config.DeleteDropReportAfterArchive)
+// VerifyArchive verifies that the archive of the date holds every drop report of the date still in the database,
+// and that the extras of the date have been archived as well
+func (s *Archive) VerifyArchive(ctx context.Context, date time.Time) error {
+	day := date.Format("2006-01-02")
+	exists, err := s.dropReportExtrasArchiver.Exists(ctx, date)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.Errorf("drop report extras of %s have not been archived", day)
+	}
+
+	archived := 0
+	err = s.dropReportsArchiver.Read(ctx, date, func([]byte) error {
+		archived++
+		return nil
+	})
+	if errors.Is(err, archiver.ErrFileNotFound) {
+		return errors.Errorf("drop reports of %s have not been archived", day)
+	} else if err != nil {
+		return err
+	}
+
+	count, err := s.DropReportService.CountDropReportsForArchive(ctx, date)
+	if err != nil {
+		return err
+	}
+	if count > archived {
+		return errors.Errorf("archive of %s holds %d drop reports while the database has %d", day, archived, count)
+	}
+	return nil
+}
+
+// ReadDropReports reads the archived drop reports of the date back. ErrArchiveNotFound is returned when the date has
+// not been archived.
+func (s *Archive) ReadDropReports(ctx context.Context, date time.Time, fn func(report *model.DropReport) error) error {
+	err := s.dropReportsArchiver.Read(ctx, date, func(line []byte) error {
+		var report model.DropReport
+		if err := json.Unmarshal(line, &report); err != nil {
+			return errors.Wrap(err, "failed to decode archived drop report")
+		}
+		return fn(&report)
+	})
+	if errors.Is(err, archiver.ErrFileNotFound) {
+		return ErrArchiveNotFound
+	}
+	return err
+}
+
+// EnqueueArchiveByGlobalConfig enqueues the archive of the day that has just become old enough to be archived.
+// The job is enqueued once per day, however many times it is called.
+func (s *Archive) EnqueueArchiveByGlobalConfig(ctx context.Context) error {
+	targetDay := time.Now().AddDate(0, 0, -1*s.Config.NoArchiveDays).Format("2006-01-02")
+	_, _, err := s.Jobs.Enqueue(ctx, model.JobKindArchive, "", model.JobKindArchive+":"+targetDay, &archiveJobPayload{Date: targetDay})
+	return err
 }
 
 func (s *Archive) ArchiveByDate(ctx context.Context, date time.Time, deleteAfterArchive bool) error {
+	s.mu.Lock()
+	if s.draining {
+		s.mu.Unlock()
+		return ErrArchiveShuttingDown
+	}
+	s.running.Add(1)
+	s.mu.Unlock()
+	defer s.running.Done()
+
 	if err := s.lock.Lock(); err != nil {
 		return errors.Wrap(err, "failed to acquire lock")
 	}
@@ -228,7 +361,7 @@ func (s *Archive) populateDropReportExtrasToArchiver(ctx context.Context, idIncl
 }
 
 func (s *Archive) DeleteReportsAndExtras(ctx context.Context, date time.Time, idInclusiveStart int, idInclusiveEnd int) error {
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.pools.DB(ctx).BeginTx(ctx, nil)
 	if err != nil {
 		return errors.Wrap(err, "failed to start transaction")
 	}
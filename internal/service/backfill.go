@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/util"
+)
+
+// Backfill tracks the aggregations outdated by the retroactive changes to the inclusion of the accepted reports, e.g.
+// by the exclusion rules or the reliabilities, and queues their recalculation. The changed reports are mapped to the
+// days of their stages in every server, so that only the matrices of the stages affected on each day are recalculated,
+// while the rollups of the days and the trends of the servers are rebuilt as a whole.
+type Backfill struct {
+	RefreshJobService      *RefreshJob
+	DropMatrixViewsService *DropMatrixViews
+}
+
+func NewBackfill(refreshJobService *RefreshJob, dropMatrixViewsService *DropMatrixViews) *Backfill {
+	return &Backfill{
+		RefreshJobService:      refreshJobService,
+		DropMatrixViewsService: dropMatrixViewsService,
+	}
+}
+
+// backfillTarget is a set of days of a server of which the same stages are to be recalculated
+type backfillTarget struct {
+	Server   string
+	Dates    []string
+	StageIDs []int
+}
+
+// Track queues the recalculation of the aggregations of the stages and the days the changed reports were created in,
+// including the materialized views of the drop matrix, and returns the refresh jobs queued
+func (s *Backfill) Track(ctx context.Context, changes []*model.ReliabilityChangeResult) ([]*model.RefreshJob, error) {
+	purgedBefore, _ := s.RefreshJobService.RetentionService.PurgedBefore()
+	targets := planBackfill(changes, purgedBefore)
+	jobs := make([]*model.RefreshJob, 0, len(targets)*2)
+	for _, server := range lo.Uniq(lo.Map(targets, func(target *backfillTarget, _ int) string { return target.Server })) {
+		serverTargets := lo.Filter(targets, func(target *backfillTarget, _ int) bool { return target.Server == server })
+
+		// the rollups of the days are rebuilt first, for the trends to be recalculated from them
+		if s.RefreshJobService.RollupsService.Enabled() {
+			dates := lo.Uniq(lo.FlatMap(serverTargets, func(target *backfillTarget, _ int) []string { return target.Dates }))
+			sort.Strings(dates)
+			for _, chunk := range lo.Chunk(dates, refreshJobMaxDates) {
+				job, err := s.RefreshJobService.StartRefreshJob(ctx, &types.CreateRefreshJobRequest{
+					Realm:  model.RefreshJobRealmRollup,
+					Server: server,
+					Dates:  chunk,
+				})
+				if err != nil {
+					return nil, err
+				}
+				jobs = append(jobs, job)
+			}
+		}
+
+		for _, target := range serverTargets {
+			for _, chunk := range lo.Chunk(target.Dates, refreshJobMaxDates) {
+				for _, realm := range []string{model.RefreshJobRealmMatrix, model.RefreshJobRealmPattern} {
+					job, err := s.RefreshJobService.StartRefreshJob(ctx, &types.CreateRefreshJobRequest{
+						Realm:    realm,
+						Server:   server,
+						Dates:    chunk,
+						StageIDs: target.StageIDs,
+					})
+					if err != nil {
+						return nil, err
+					}
+					jobs = append(jobs, job)
+				}
+			}
+		}
+
+		job, err := s.RefreshJobService.StartRefreshJob(ctx, &types.CreateRefreshJobRequest{
+			Realm:  model.RefreshJobRealmTrend,
+			Server: server,
+		})
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	if len(jobs) > 0 {
+		if err := s.DropMatrixViewsService.RequestRefresh(ctx); err != nil {
+			return nil, err
+		}
+
+		log.Info().
+			Str("evt.name", "backfill.queued").
+			Int("targets", len(targets)).
+			Int("refreshJobs", len(jobs)).
+			Msg("recalculation of the aggregations outdated by retroactive changes queued")
+	}
+
+	return jobs, nil
+}
+
+// planBackfill maps the changed reports to the days of the stages of every server to recalculate, and merges the days
+// of a server to recalculate the same stages on into a target. A day is added on both ends of the changes of a stage
+// to cover the day boundaries of the servers. The days are not bounded, as Track chunks them into the refresh jobs,
+// but clipped to those starting from purgedBefore, unless zero, as the matrices of the days with purged reports would
+// be recalculated from the remaining ones only.
+func planBackfill(changes []*model.ReliabilityChangeResult, purgedBefore time.Time) []*backfillTarget {
+	// stages to recalculate per date per server
+	stagesByDate := make(map[string]map[string][]int)
+	for _, change := range changes {
+		if change.Count == 0 {
+			continue
+		}
+
+		last := change.MaxTime.UTC().AddDate(0, 0, 1)
+		first := change.MinTime.UTC().AddDate(0, 0, -1)
+		if stagesByDate[change.Server] == nil {
+			stagesByDate[change.Server] = make(map[string][]int)
+		}
+		lastDate := last.Format("2006-01-02")
+		for day := first; ; day = day.AddDate(0, 0, 1) {
+			date := day.Format("2006-01-02")
+			purged := !purgedBefore.IsZero() && time.UnixMilli(util.GetDayStartTime(&day, change.Server)).Before(purgedBefore)
+			if !purged && !lo.Contains(stagesByDate[change.Server][date], change.StageID) {
+				stagesByDate[change.Server][date] = append(stagesByDate[change.Server][date], change.StageID)
+			}
+			if date >= lastDate {
+				break
+			}
+		}
+	}
+
+	servers := lo.Keys(stagesByDate)
+	sort.Strings(servers)
+	targets := make([]*backfillTarget, 0)
+	for _, server := range servers {
+		dates := lo.Keys(stagesByDate[server])
+		sort.Strings(dates)
+
+		targetsByStages := make(map[string]*backfillTarget)
+		for _, date := range dates {
+			stageIds := stagesByDate[server][date]
+			sort.Ints(stageIds)
+			key := strings.Join(lo.Map(stageIds, func(stageId int, _ int) string { return strconv.Itoa(stageId) }), ",")
+
+			target, ok := targetsByStages[key]
+			if !ok {
+				target = &backfillTarget{Server: server, StageIDs: stageIds}
+				targetsByStages[key] = target
+				targets = append(targets, target)
+			}
+			target.Dates = append(target.Dates, date)
+		}
+	}
+	return targets
+}
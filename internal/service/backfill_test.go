@@ -0,0 +1,70 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+
+	"exusiai.dev/backend-next/internal/model"
+)
+
+func TestPlanBackfill(t *testing.T) {
+	day := func(d int, h int) time.Time {
+		return time.Date(2026, 10, d, h, 0, 0, 0, time.UTC)
+	}
+	changes := []*model.ReliabilityChangeResult{
+		{Server: "CN", StageID: 1, Count: 3, MinTime: day(10, 1), MaxTime: day(11, 23)},
+		{Server: "CN", StageID: 2, Count: 1, MinTime: day(12, 5), MaxTime: day(12, 6)},
+		{Server: "US", StageID: 1, Count: 2, MinTime: day(10, 1), MaxTime: day(10, 2)},
+		// nothing changed, so nothing to recalculate
+		{Server: "JP", StageID: 1, Count: 0, MinTime: day(10, 1), MaxTime: day(10, 2)},
+	}
+
+	got := planBackfill(changes, time.Time{})
+	want := []*backfillTarget{
+		{Server: "CN", Dates: []string{"2026-10-09", "2026-10-10"}, StageIDs: []int{1}},
+		{Server: "CN", Dates: []string{"2026-10-11", "2026-10-12"}, StageIDs: []int{1, 2}},
+		{Server: "CN", Dates: []string{"2026-10-13"}, StageIDs: []int{2}},
+		{Server: "US", Dates: []string{"2026-10-09", "2026-10-10", "2026-10-11"}, StageIDs: []int{1}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		for _, target := range got {
+			t.Logf("got %+v", *target)
+		}
+		t.Fatalf("planBackfill did not plan the targets wanted")
+	}
+}
+
+func TestPlanBackfillLongRange(t *testing.T) {
+	last := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	got := planBackfill([]*model.ReliabilityChangeResult{
+		{Server: "CN", StageID: 1, Count: 1, MinTime: last.AddDate(-1, 0, 0), MaxTime: last},
+	}, time.Time{})
+	// every day is planned, however many refresh jobs they are to be chunked into
+	if len(got) != 1 || len(got[0].Dates) != 368 {
+		t.Fatalf("planned %d targets, want 1 of 368 dates", len(got))
+	}
+	if got[0].Dates[0] != "2025-09-30" || got[0].Dates[len(got[0].Dates)-1] != "2026-10-02" {
+		t.Errorf("dates = %s to %s, want 2025-09-30 to 2026-10-02", got[0].Dates[0], got[0].Dates[len(got[0].Dates)-1])
+	}
+}
+
+func TestPlanBackfillPurged(t *testing.T) {
+	// the days of CN starting before the purged time are left out, including the padding day before the changes
+	purgedBefore := time.Date(2026, 10, 11, 0, 0, 0, 0, constant.LocMap["CN"])
+	got := planBackfill([]*model.ReliabilityChangeResult{
+		{Server: "CN", StageID: 1, Count: 3, MinTime: time.Date(2026, 10, 10, 1, 0, 0, 0, time.UTC), MaxTime: time.Date(2026, 10, 12, 1, 0, 0, 0, time.UTC)},
+		{Server: "CN", StageID: 2, Count: 1, MinTime: time.Date(2026, 10, 1, 1, 0, 0, 0, time.UTC), MaxTime: time.Date(2026, 10, 2, 1, 0, 0, 0, time.UTC)},
+	}, purgedBefore)
+	want := []*backfillTarget{
+		{Server: "CN", Dates: []string{"2026-10-11", "2026-10-12", "2026-10-13"}, StageIDs: []int{1}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		for _, target := range got {
+			t.Logf("got %+v", *target)
+		}
+		t.Fatalf("planBackfill did not clip the purged days")
+	}
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+)
+
+// cacheTTLOverridesRedisKey is the hash of the cache TTL overrides as JSON by their scope, ark ID and server, shared by
+// every instance
+const cacheTTLOverridesRedisKey = "cacheTTLOverrides"
+
+// CacheTTLOverrides override how long the matrices and the trends of the stages of a zone, or of a stage, are cached
+// for, e.g. to refresh those of an event being launched every few minutes. A result is cached for the shortest TTL of
+// the stages it covers, so the global drop matrix is cached in segments keeping the overridden stages apart.
+type CacheTTLOverrides struct {
+	Redis        *redis.Client
+	StageService *Stage
+	ZoneService  *Zone
+
+	// overrides is the latest known list of the overrides, including the expired ones
+	overrides atomic.Pointer[[]*model.CacheTTLOverride]
+	// fingerprint identifies the overrides in effect upon the last refresh, the changes of which flush the results
+	// cached for the TTL of the previous ones
+	fingerprint atomic.Pointer[string]
+}
+
+func NewCacheTTLOverrides(conf *appconfig.Config, redisClient *redis.Client, stageService *Stage, zoneService *Zone, lc fx.Lifecycle) *CacheTTLOverrides {
+	s := &CacheTTLOverrides{
+		Redis:        redisClient,
+		StageService: stageService,
+		ZoneService:  zoneService,
+	}
+	s.overrides.Store(&[]*model.CacheTTLOverride{})
+	fingerprint := ""
+	s.fingerprint.Store(&fingerprint)
+
+	watchEvery(lc, "cachettloverrides", conf.TunablesRefreshInterval, s.refresh)
+	return s
+}
+
+// cacheTTLOverrideField is the field of the override in the hash, of which the server is empty for every server
+func cacheTTLOverrideField(scope string, arkId string, server string) string {
+	if server == "" {
+		return scope + ":" + arkId
+	}
+	return scope + ":" + arkId + ":" + server
+}
+
+func (s *CacheTTLOverrides) refresh(ctx context.Context) error {
+	values, err := s.Redis.HGetAll(ctx, cacheTTLOverridesRedisKey).Result()
+	if err != nil {
+		return err
+	}
+	overrides := make([]*model.CacheTTLOverride, 0, len(values))
+	for field, v := range values {
+		var override model.CacheTTLOverride
+		if err := json.Unmarshal([]byte(v), &override); err != nil {
+			log.Warn().Err(err).Str("field", field).Msg("skipping malformed cache TTL override")
+			continue
+		}
+		overrides = append(overrides, &override)
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		return cacheTTLOverrideField(overrides[i].Scope, overrides[i].ArkID, overrides[i].Server) <
+			cacheTTLOverrideField(overrides[j].Scope, overrides[j].ArkID, overrides[j].Server)
+	})
+	s.overrides.Store(&overrides)
+	s.flushUponChange()
+	return nil
+}
+
+// flushUponChange flushes the results covering every stage of a server once the overrides in effect have changed,
+// e.g. upon their expiry, as those cached before are cached for the TTL of the previous overrides. The segments of
+// the global drop matrix are keyed by the stages overridden instead, and are left as is.
+func (s *CacheTTLOverrides) flushUponChange() {
+	fingerprint := strings.Join(lo.Map(s.active(), func(override *model.CacheTTLOverride, _ int) string {
+		return cacheTTLOverrideField(override.Scope, override.ArkID, override.Server) + "|" + override.TTL
+	}), ",")
+	if previous := s.fingerprint.Swap(&fingerprint); *previous == fingerprint {
+		return
+	}
+
+	for _, flush := range []func() error{cache.ShimGlobalDropMatrix.Flush, cache.ShimGlobalPatternMatrix.Flush, cache.ShimTrend.Flush} {
+		_ = flush()
+	}
+	log.Info().
+		Str("evt.name", "cachettloverrides.changed").
+		Str("overrides", fingerprint).
+		Msg("cache TTL overrides in effect changed, flushed the results cached for the previous ones")
+}
+
+func (s *CacheTTLOverrides) active() []*model.CacheTTLOverride {
+	now := time.Now()
+	return lo.Filter(*s.overrides.Load(), func(override *model.CacheTTLOverride, _ int) bool {
+		return override.Active(now)
+	})
+}
+
+// stageTTLs returns the TTL of every stage of the server overridden, the shortest one should several overrides apply
+func (s *CacheTTLOverrides) stageTTLs(server string) map[int]time.Duration {
+	ttls := make(map[int]time.Duration)
+	for _, override := range s.active() {
+		if override.Server != "" && override.Server != server {
+			continue
+		}
+		ttl := override.Duration()
+		for _, stageId := range override.StageIDs {
+			if current, ok := ttls[stageId]; !ok || ttl < current {
+				ttls[stageId] = ttl
+			}
+		}
+	}
+	return ttls
+}
+
+// TTL returns how long a result of the server covering the stages, or every stage when stageIds is empty, is cached
+// for, which is the shortest TTL of the stages, fallback being that of the stages not overridden
+func (s *CacheTTLOverrides) TTL(server string, stageIds []int, fallback time.Duration) time.Duration {
+	ttls := s.stageTTLs(server)
+	if len(stageIds) == 0 {
+		for _, ttl := range ttls {
+			if ttl < fallback {
+				fallback = ttl
+			}
+		}
+		return fallback
+	}
+
+	shortest := time.Duration(math.MaxInt64)
+	for _, stageId := range stageIds {
+		ttl, ok := ttls[stageId]
+		if !ok {
+			ttl = fallback
+		}
+		if ttl < shortest {
+			shortest = ttl
+		}
+	}
+	return shortest
+}
+
+// TTLByArkStageIds is TTL of the stages of the ark IDs, of which the unknown ones are left out, or of every stage when
+// arkStageIds is empty
+func (s *CacheTTLOverrides) TTLByArkStageIds(ctx context.Context, server string, arkStageIds []string, fallback time.Duration) time.Duration {
+	if len(arkStageIds) == 0 {
+		return s.TTL(server, nil, fallback)
+	}
+	stagesMap, err := s.StageService.GetStagesMapByArkId(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to resolve the stages of the cache TTL, falling back to that of every stage")
+		return s.TTL(server, nil, fallback)
+	}
+	stageIds := make([]int, 0, len(arkStageIds))
+	for _, arkStageId := range arkStageIds {
+		if stage, ok := stagesMap[strings.TrimSpace(arkStageId)]; ok {
+			stageIds = append(stageIds, stage.StageID)
+		}
+	}
+	if len(stageIds) == 0 {
+		return fallback
+	}
+	return s.TTL(server, stageIds, fallback)
+}
+
+// cacheSegment is a part of a result cached for a TTL of its own
+type cacheSegment struct {
+	// Key is suffixed to the key of the result, empty for the only segment of a result of which no stage is overridden
+	Key string
+	// StageIDs are the stages the segment covers, or those it does not cover when Rest is set
+	StageIDs []int
+	Rest     bool
+	TTL      time.Duration
+}
+
+func (seg *cacheSegment) covers(stageId int) bool {
+	return lo.Contains(seg.StageIDs, stageId) != seg.Rest
+}
+
+// segments splits a result of the server covering every stage into the segments cached for the same TTL, the
+// overridden stages apart from the others. The keys of the segments tell the stages overridden, so that the segments
+// cached before the overrides have changed are never served.
+func (s *CacheTTLOverrides) segments(server string, fallback time.Duration) []*cacheSegment {
+	ttls := s.stageTTLs(server)
+	if len(ttls) == 0 {
+		return []*cacheSegment{{Rest: true, TTL: fallback}}
+	}
+
+	stageIdsByTTL := make(map[time.Duration][]int)
+	for stageId, ttl := range ttls {
+		stageIdsByTTL[ttl] = append(stageIdsByTTL[ttl], stageId)
+	}
+	overridden := lo.Keys(ttls)
+	sort.Ints(overridden)
+
+	segments := []*cacheSegment{{Key: "rest:" + joinStageIds(overridden), StageIDs: overridden, Rest: true, TTL: fallback}}
+	ttlsSorted := lo.Keys(stageIdsByTTL)
+	sort.Slice(ttlsSorted, func(i, j int) bool { return ttlsSorted[i] < ttlsSorted[j] })
+	for _, ttl := range ttlsSorted {
+		stageIds := stageIdsByTTL[ttl]
+		sort.Ints(stageIds)
+		segments = append(segments, &cacheSegment{Key: ttl.String() + ":" + joinStageIds(stageIds), StageIDs: stageIds, TTL: ttl})
+	}
+	return segments
+}
+
+func joinStageIds(stageIds []int) string {
+	return strings.Join(lo.Map(stageIds, func(stageId int, _ int) string { return strconv.Itoa(stageId) }), ",")
+}
+
+// GetCacheTTLOverrides lists every override, including the expired ones, as known by this instance
+func (s *CacheTTLOverrides) GetCacheTTLOverrides() []*model.CacheTTLOverride {
+	return *s.overrides.Load()
+}
+
+// SetCacheTTLOverride overrides the TTL of the stages of a zone, or of a stage, in a server or every server on every
+// instance, replacing the previous override of the same server only
+func (s *CacheTTLOverrides) SetCacheTTLOverride(ctx context.Context, scope string, arkId string, req *types.SetCacheTTLOverrideRequest) (*model.CacheTTLOverride, error) {
+	ttl, err := time.ParseDuration(req.TTL)
+	if err != nil || ttl < time.Second {
+		return nil, pgerr.ErrInvalidReq.Msg("ttl must be a duration of at least a second")
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, pgerr.ErrInvalidReq.Msg("expiresAt must be in the future")
+	}
+
+	override := &model.CacheTTLOverride{
+		Scope:     scope,
+		ArkID:     arkId,
+		Server:    req.Server,
+		TTL:       ttl.String(),
+		ExpiresAt: req.ExpiresAt,
+	}
+	switch scope {
+	case model.CacheTTLOverrideScopeZone:
+		zone, err := s.ZoneService.GetZoneByArkId(ctx, arkId)
+		if errors.Is(err, pgerr.ErrNotFound) {
+			return nil, pgerr.ErrInvalidReq.Msg("zone %s does not exist", arkId)
+		} else if err != nil {
+			return nil, err
+		}
+		stages, err := s.StageService.GetStagesByZoneId(ctx, zone.ZoneID)
+		if err != nil {
+			return nil, err
+		}
+		override.StageIDs = lo.Map(stages, func(stage *model.Stage, _ int) int { return stage.StageID })
+	case model.CacheTTLOverrideScopeStage:
+		stage, err := s.StageService.GetStageByArkId(ctx, arkId)
+		if errors.Is(err, pgerr.ErrNotFound) {
+			return nil, pgerr.ErrInvalidReq.Msg("stage %s does not exist", arkId)
+		} else if err != nil {
+			return nil, err
+		}
+		override.StageIDs = []int{stage.StageID}
+	default:
+		return nil, pgerr.ErrInvalidReq.Msg("scope must be either %s or %s", model.CacheTTLOverrideScopeZone, model.CacheTTLOverrideScopeStage)
+	}
+
+	b, err := json.Marshal(override)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Redis.HSet(ctx, cacheTTLOverridesRedisKey, cacheTTLOverrideField(scope, arkId, req.Server), b).Err(); err != nil {
+		return nil, err
+	}
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("evt.name", "admin.cache_ttl_override.set").
+		Str("scope", scope).
+		Str("arkId", arkId).
+		Str("server", req.Server).
+		Str("ttl", override.TTL).
+		Int("stages", len(override.StageIDs)).
+		Msg("cache TTL override set")
+
+	return override, nil
+}
+
+// DeleteCacheTTLOverride restores the default TTL of the stages of a zone, or of a stage, in a server or every server
+// on every instance
+func (s *CacheTTLOverrides) DeleteCacheTTLOverride(ctx context.Context, scope string, arkId string, server string) error {
+	deleted, err := s.Redis.HDel(ctx, cacheTTLOverridesRedisKey, cacheTTLOverrideField(scope, arkId, server)).Result()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		if server == "" {
+			return pgerr.ErrNotFound.Msg("%s %s has no cache TTL override for every server", scope, arkId)
+		}
+		return pgerr.ErrNotFound.Msg("%s %s has no cache TTL override in %s", scope, arkId, server)
+	}
+	return s.refresh(ctx)
+}
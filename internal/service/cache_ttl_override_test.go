@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"exusiai.dev/backend-next/internal/model"
+)
+
+func TestCacheTTLOverrides(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	s := &CacheTTLOverrides{}
+	s.overrides.Store(&[]*model.CacheTTLOverride{
+		{Scope: model.CacheTTLOverrideScopeZone, ArkID: "act1", TTL: "5m", StageIDs: []int{1, 2}},
+		{Scope: model.CacheTTLOverrideScopeStage, ArkID: "main_01-07", Server: "US", TTL: "1m", StageIDs: []int{3}},
+		{Scope: model.CacheTTLOverrideScopeStage, ArkID: "a1-1", TTL: "2m", StageIDs: []int{2}},
+		// expired, so the default TTL applies again
+		{Scope: model.CacheTTLOverrideScopeStage, ArkID: "main_00-01", TTL: "1s", ExpiresAt: &past, StageIDs: []int{4}},
+	})
+
+	fallback := 24 * time.Hour
+	cases := []struct {
+		server   string
+		stageIds []int
+		want     time.Duration
+	}{
+		{"CN", nil, 2 * time.Minute},
+		{"US", nil, time.Minute},
+		{"CN", []int{1}, 5 * time.Minute},
+		{"CN", []int{1, 2}, 2 * time.Minute},
+		{"CN", []int{3}, fallback},
+		{"US", []int{3, 4}, time.Minute},
+		{"CN", []int{4, 5}, fallback},
+	}
+	for _, c := range cases {
+		if got := s.TTL(c.server, c.stageIds, fallback); got != c.want {
+			t.Errorf("TTL(%s, %v) = %s, want %s", c.server, c.stageIds, got, c.want)
+		}
+	}
+
+	segments := s.segments("CN", fallback)
+	wantKeys := []string{"rest:1,2", "2m0s:2", "5m0s:1"}
+	if len(segments) != len(wantKeys) {
+		t.Fatalf("got %d segments, want %d", len(segments), len(wantKeys))
+	}
+	for i, segment := range segments {
+		if segment.Key != wantKeys[i] {
+			t.Errorf("segment %d key = %s, want %s", i, segment.Key, wantKeys[i])
+		}
+	}
+	if !segments[0].covers(4) || segments[0].covers(1) || !segments[2].covers(1) || segments[2].covers(2) {
+		t.Errorf("segments cover the wrong stages")
+	}
+
+	// the stages of a server without overrides are cached as a whole, under the key of the result itself
+	s.overrides.Store(&[]*model.CacheTTLOverride{})
+	segments = s.segments("CN", fallback)
+	if len(segments) != 1 || segments[0].Key != "" || segments[0].TTL != fallback || !segments[0].covers(1) {
+		t.Errorf("segments without overrides = %d, want a single one covering every stage", len(segments))
+	}
+}
+
+func TestCacheTTLOverrideField(t *testing.T) {
+	// the overrides of the same zone in different servers must not replace one another
+	fields := []string{
+		cacheTTLOverrideField(model.CacheTTLOverrideScopeZone, "act24side_zone1", ""),
+		cacheTTLOverrideField(model.CacheTTLOverrideScopeZone, "act24side_zone1", "CN"),
+		cacheTTLOverrideField(model.CacheTTLOverrideScopeZone, "act24side_zone1", "US"),
+	}
+	seen := map[string]bool{}
+	for _, field := range fields {
+		if seen[field] {
+			t.Errorf("field %s is shared by different servers", field)
+		}
+		seen[field] = true
+	}
+}
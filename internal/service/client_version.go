@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/mod/semver"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// ClientVersion gates the versions of the clients allowed to submit reports
+type ClientVersion struct {
+	ClientVersionRepo *repo.ClientVersion
+}
+
+func NewClientVersion(clientVersionRepo *repo.ClientVersion) *ClientVersion {
+	return &ClientVersion{
+		ClientVersionRepo: clientVersionRepo,
+	}
+}
+
+func (s *ClientVersion) GetClientVersions(ctx context.Context) ([]*model.ClientVersion, error) {
+	return s.ClientVersionRepo.GetClientVersions(ctx)
+}
+
+// Cache: (singular) clientVersions, 5 min
+func (s *ClientVersion) getClientVersionsMap(ctx context.Context) (map[string]*model.ClientVersion, error) {
+	var clientVersionsMap map[string]*model.ClientVersion
+	err := cache.ClientVersions.MutexGetSet(&clientVersionsMap, func() (map[string]*model.ClientVersion, error) {
+		clientVersions, err := s.ClientVersionRepo.GetClientVersions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]*model.ClientVersion, len(clientVersions))
+		for _, clientVersion := range clientVersions {
+			m[clientVersion.Source] = clientVersion
+		}
+		return m, nil
+	}, time.Minute*5)
+	if err != nil {
+		return nil, err
+	}
+	return clientVersionsMap, nil
+}
+
+// CheckClientVersion returns pgerr.ErrUpgradeRequired when the version of the client of source is rejected by its
+// gate. Once a minimum version is set, the versions that are not semantic versions are rejected as well, as they
+// cannot be told to be recent enough.
+func (s *ClientVersion) CheckClientVersion(ctx context.Context, source string, version string) error {
+	clientVersionsMap, err := s.getClientVersionsMap(ctx)
+	if err != nil {
+		return err
+	}
+	clientVersion, ok := clientVersionsMap[source]
+	if !ok {
+		return nil
+	}
+
+	normalized := normalizeSemver(version)
+	rejected := clientVersion.Disabled
+	for _, blocked := range clientVersion.BlockedVersions {
+		if version == blocked || (semver.IsValid(normalized) && semver.Compare(normalized, normalizeSemver(blocked)) == 0) {
+			rejected = true
+			break
+		}
+	}
+	if clientVersion.MinVersion.Valid {
+		if !semver.IsValid(normalized) || semver.Compare(normalized, normalizeSemver(clientVersion.MinVersion.String)) < 0 {
+			rejected = true
+		}
+	}
+	if !rejected {
+		return nil
+	}
+
+	e := pgerr.ErrUpgradeRequired
+	if clientVersion.Message.Valid {
+		e = e.Msg("%s", clientVersion.Message.String)
+	}
+	return e.WithExtras(pgerr.Extras{
+		"source":     source,
+		"version":    version,
+		"minVersion": clientVersion.MinVersion,
+		"disabled":   clientVersion.Disabled,
+	})
+}
+
+// normalizeSemver prefixes the version with v as golang.org/x/mod/semver expects, e.g. 4.12.3 to v4.12.3
+func normalizeSemver(version string) string {
+	if strings.HasPrefix(version, "v") {
+		return version
+	}
+	return "v" + version
+}
+
+// SaveClientVersion replaces the gate of the versions of the client of source
+func (s *ClientVersion) SaveClientVersion(ctx context.Context, source string, req *types.SaveClientVersionRequest) (*model.ClientVersion, error) {
+	clientVersion := &model.ClientVersion{
+		Source:          source,
+		MinVersion:      req.MinVersion,
+		BlockedVersions: req.BlockedVersions,
+		Disabled:        req.Disabled,
+		Message:         req.Message,
+	}
+	if clientVersion.BlockedVersions == nil {
+		clientVersion.BlockedVersions = []string{}
+	}
+	if clientVersion.MinVersion.Valid {
+		clientVersion.MinVersion = null.StringFrom(normalizeSemver(clientVersion.MinVersion.String))
+	}
+
+	if err := s.ClientVersionRepo.SaveClientVersion(ctx, clientVersion); err != nil {
+		return nil, err
+	}
+	cache.ClientVersions.Delete()
+
+	log.Info().
+		Str("evt.name", "admin.client_version.saved").
+		Str("source", source).
+		Str("minVersion", clientVersion.MinVersion.String).
+		Strs("blockedVersions", clientVersion.BlockedVersions).
+		Bool("disabled", clientVersion.Disabled).
+		Msg("client version gate saved")
+
+	return clientVersion, nil
+}
+
+// DeleteClientVersion removes the gate of the client of source, accepting every version of it again
+func (s *ClientVersion) DeleteClientVersion(ctx context.Context, source string) error {
+	deleted, err := s.ClientVersionRepo.DeleteClientVersion(ctx, source)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return pgerr.ErrNotFound.Msg("client %s has no version gate", source)
+	}
+	cache.ClientVersions.Delete()
+
+	log.Info().
+		Str("evt.name", "admin.client_version.deleted").
+		Str("source", source).
+		Msg("client version gate deleted")
+
+	return nil
+}
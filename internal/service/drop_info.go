@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"sort"
 	"strconv"
 	"time"
 
@@ -11,18 +12,23 @@ import (
 
 	"exusiai.dev/backend-next/internal/model"
 	"exusiai.dev/backend-next/internal/model/cache"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
 	"exusiai.dev/backend-next/internal/repo"
 )
 
 type DropInfo struct {
-	DropInfoRepo     *repo.DropInfo
+	DropInfoRepo     repo.DropInfoRepo
 	TimeRangeService *TimeRange
+	StageService     *Stage
+	ItemService      *Item
 }
 
-func NewDropInfo(dropInfoRepo *repo.DropInfo, timeRangeService *TimeRange) *DropInfo {
+func NewDropInfo(dropInfoRepo *repo.DropInfo, timeRangeService *TimeRange, stageService *Stage, itemService *Item) *DropInfo {
 	return &DropInfo{
 		DropInfoRepo:     dropInfoRepo,
 		TimeRangeService: timeRangeService,
+		StageService:     stageService,
+		ItemService:      itemService,
 	}
 }
 
@@ -34,6 +40,77 @@ func (s *DropInfo) GetDropInfosWithFilters(ctx context.Context, server string, t
 	return s.DropInfoRepo.GetDropInfosWithFilters(ctx, server, timeRanges, stageIdFilter, itemIdFilter)
 }
 
+// GetStageDropInfos groups the drop infos of a stage in a server by their time ranges, latest first
+func (s *DropInfo) GetStageDropInfos(ctx context.Context, server string, arkStageId string) (*modelv3.StageDropInfos, error) {
+	stage, err := s.StageService.GetStageByArkId(ctx, arkStageId)
+	if err != nil {
+		return nil, err
+	}
+	dropInfos, err := s.DropInfoRepo.GetDropInfosByServerAndStageId(ctx, server, stage.StageID)
+	if err != nil {
+		return nil, err
+	}
+	timeRangesMap, err := s.TimeRangeService.GetTimeRangesMap(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	itemsMapById, err := s.ItemService.GetItemsMapById(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	byRangeId := make(map[int]*modelv3.StageDropInfosInTimeRange)
+	results := make([]*modelv3.StageDropInfosInTimeRange, 0)
+	for _, dropInfo := range dropInfos {
+		group, ok := byRangeId[dropInfo.RangeID]
+		if !ok {
+			timeRange, ok := timeRangesMap[dropInfo.RangeID]
+			if !ok {
+				continue
+			}
+			group = &modelv3.StageDropInfosInTimeRange{
+				TimeRange: timeRange,
+				Active:    timeRange.Includes(now),
+				DropSet:   make([]string, 0),
+				DropTypes: make([]*modelv3.DropTypeDropInfo, 0),
+				Items:     make([]*modelv3.ItemDropInfo, 0),
+			}
+			byRangeId[dropInfo.RangeID] = group
+			results = append(results, group)
+		}
+
+		if !dropInfo.ItemID.Valid {
+			group.DropTypes = append(group.DropTypes, &modelv3.DropTypeDropInfo{
+				DropType: dropInfo.DropType,
+				Bounds:   dropInfo.Bounds,
+			})
+			continue
+		}
+		item, ok := itemsMapById[int(dropInfo.ItemID.Int64)]
+		if !ok {
+			continue
+		}
+		group.DropSet = append(group.DropSet, item.ArkItemID)
+		group.Items = append(group.Items, &modelv3.ItemDropInfo{
+			ItemID:      item.ArkItemID,
+			DropType:    dropInfo.DropType,
+			Accumulable: dropInfo.Accumulable,
+			Bounds:      dropInfo.Bounds,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].TimeRange.StartTime.After(*results[j].TimeRange.StartTime)
+	})
+
+	return &modelv3.StageDropInfos{
+		StageID:    stage.ArkStageID,
+		Server:     server,
+		TimeRanges: results,
+	}, nil
+}
+
 // Cache: itemDropSet#server|stageId|rangeId:{server}|{stageId}|{rangeId}, 24 hrs
 func (s *DropInfo) GetItemDropSetByStageIdAndRangeId(ctx context.Context, server string, stageId int, rangeId int) ([]int, error) {
 	var itemDropSet []int
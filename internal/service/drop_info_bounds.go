@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// DropInfoBoundsSuggestions are the bounds suggested for the drop infos of a stage in effect, from the patterns
+// reported since Since
+type DropInfoBoundsSuggestions struct {
+	Server    string           `json:"server"`
+	StageID   string           `json:"stageId"`
+	TimeRange *model.TimeRange `json:"timeRange"`
+	Since     time.Time        `json:"since"`
+	// Reports is the number of reliable single-run reports the bounds are suggested from
+	Reports int `json:"reports"`
+	// Sufficient tells whether there are enough reports for the bounds to be suggested at all
+	Sufficient  bool                        `json:"sufficient"`
+	Suggestions []*DropInfoBoundsSuggestion `json:"suggestions"`
+	// UnknownItems are the items reported while having no drop info, which bounds cannot help with
+	UnknownItems []string `json:"unknownItems"`
+}
+
+type DropInfoBoundsSuggestion struct {
+	DropID   int           `json:"dropId"`
+	DropType string        `json:"dropType"`
+	ItemID   null.String   `json:"itemId" swaggertype:"string"`
+	Current  *model.Bounds `json:"current"`
+	// Observed are the quantities of the item, or the kinds of the items of the drop type, seen per run
+	Observed []int `json:"observed"`
+	// Suggested is nil when there are not enough reports, or when the quantities cannot be told apart because the
+	// item drops under several drop types
+	Suggested *model.Bounds `json:"suggested"`
+	// Changed tells whether the suggested bounds differ from the current ones
+	Changed bool `json:"changed"`
+}
+
+// DropInfoBounds keeps the bounds of the drop infos in line with the drops actually reported, which change along
+// with the game balance patches
+type DropInfoBounds struct {
+	DropInfoRepo            *repo.DropInfo
+	DropReportRepo          *repo.DropReport
+	DropPatternElementRepo  *repo.DropPatternElement
+	StageService            *Stage
+	ItemService             *Item
+	TimeRangeService        *TimeRange
+	MetadataSnapshotService *MetadataSnapshot
+	Tunables                *Tunables
+}
+
+func NewDropInfoBounds(dropInfoRepo *repo.DropInfo, dropReportRepo *repo.DropReport, dropPatternElementRepo *repo.DropPatternElement, stageService *Stage, itemService *Item, timeRangeService *TimeRange, metadataSnapshotService *MetadataSnapshot, tunables *Tunables) *DropInfoBounds {
+	return &DropInfoBounds{
+		DropInfoRepo:            dropInfoRepo,
+		DropReportRepo:          dropReportRepo,
+		DropPatternElementRepo:  dropPatternElementRepo,
+		StageService:            stageService,
+		ItemService:             itemService,
+		TimeRangeService:        timeRangeService,
+		MetadataSnapshotService: metadataSnapshotService,
+		Tunables:                tunables,
+	}
+}
+
+// SuggestDropInfoBounds suggests the bounds of the drop infos of the stage in effect in the server from the minimum
+// and maximum quantities in the patterns reported over the window, or the tunable default when window is 0. The
+// window is clipped to the time range of the drop infos, so that the patterns of a previous version of the drop
+// table do not count.
+func (s *DropInfoBounds) SuggestDropInfoBounds(ctx context.Context, server string, arkStageId string, window time.Duration) (*DropInfoBoundsSuggestions, error) {
+	if window <= 0 {
+		window = s.Tunables.Duration(TunableBoundsWindow)
+	}
+	stage, err := s.StageService.GetStageByArkId(ctx, arkStageId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dropInfos, err := s.DropInfoRepo.GetForTimeRangeAt(ctx, &repo.DropInfoQuery{
+		Server:     server,
+		ArkStageId: arkStageId,
+	}, now)
+	if err != nil && !errors.Is(err, pgerr.ErrNotFound) {
+		return nil, err
+	}
+	if len(dropInfos) == 0 {
+		return nil, pgerr.ErrNotFound.Msg("stage %s has no drop infos in effect in %s", arkStageId, server)
+	}
+	timeRange, err := s.TimeRangeService.GetTimeRangeById(ctx, dropInfos[0].RangeID)
+	if err != nil {
+		return nil, err
+	}
+	itemsMapById, err := s.ItemService.GetItemsMapById(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	since := now.Add(-window)
+	if timeRange.StartTime != nil && since.Before(*timeRange.StartTime) {
+		since = *timeRange.StartTime
+	}
+	patternCounts, err := s.DropReportRepo.CalcTotalQuantityForPatternMatrix(ctx, &model.DropReportQueryContext{
+		Server:             server,
+		StartTime:          &since,
+		EndTime:            &now,
+		StageItemFilter:    &map[int][]int{stage.StageID: {}},
+		SourceCategory:     constant.SourceCategoryAll,
+		ExcludeNonOneTimes: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	patternIds := make([]int, 0, len(patternCounts))
+	reports := 0
+	for _, patternCount := range patternCounts {
+		patternIds = append(patternIds, patternCount.PatternID)
+		reports += patternCount.TotalQuantity
+	}
+	elementsByPattern := make(map[int]map[int]int, len(patternIds))
+	if len(patternIds) > 0 {
+		elements, err := s.DropPatternElementRepo.GetDropPatternElementsByPatternIds(ctx, patternIds)
+		if err != nil {
+			return nil, err
+		}
+		for _, element := range elements {
+			if elementsByPattern[element.DropPatternID] == nil {
+				elementsByPattern[element.DropPatternID] = make(map[int]int)
+			}
+			elementsByPattern[element.DropPatternID][element.ItemID] += element.Quantity
+		}
+	}
+
+	// the patterns do not record the drop types, so the items under several drop types cannot be told apart
+	itemDropTypes := make(map[int]map[string]struct{})
+	for _, dropInfo := range dropInfos {
+		if !dropInfo.ItemID.Valid {
+			continue
+		}
+		itemId := int(dropInfo.ItemID.Int64)
+		if itemDropTypes[itemId] == nil {
+			itemDropTypes[itemId] = make(map[string]struct{})
+		}
+		itemDropTypes[itemId][dropInfo.DropType] = struct{}{}
+	}
+	ambiguousDropTypes := make(map[string]bool)
+	for _, dropTypes := range itemDropTypes {
+		if len(dropTypes) > 1 {
+			for dropType := range dropTypes {
+				ambiguousDropTypes[dropType] = true
+			}
+		}
+	}
+
+	suggestions := &DropInfoBoundsSuggestions{
+		Server:       server,
+		StageID:      arkStageId,
+		TimeRange:    timeRange,
+		Since:        since,
+		Reports:      reports,
+		Sufficient:   reports >= s.Tunables.Int(TunableBoundsMinReports),
+		Suggestions:  make([]*DropInfoBoundsSuggestion, 0, len(dropInfos)),
+		UnknownItems: make([]string, 0),
+	}
+	unknownItems := make(map[int]struct{})
+	for _, patternCount := range patternCounts {
+		for itemId := range elementsByPattern[patternCount.PatternID] {
+			if _, ok := itemDropTypes[itemId]; !ok {
+				unknownItems[itemId] = struct{}{}
+			}
+		}
+	}
+	for itemId := range unknownItems {
+		if item, ok := itemsMapById[itemId]; ok {
+			suggestions.UnknownItems = append(suggestions.UnknownItems, item.ArkItemID)
+		}
+	}
+	sort.Strings(suggestions.UnknownItems)
+
+	for _, dropInfo := range dropInfos {
+		observed := make(map[int]struct{})
+		for _, patternCount := range patternCounts {
+			quantities := elementsByPattern[patternCount.PatternID]
+			if dropInfo.ItemID.Valid {
+				observed[quantities[int(dropInfo.ItemID.Int64)]] = struct{}{}
+				continue
+			}
+			kinds := 0
+			for itemId := range quantities {
+				if _, ok := itemDropTypes[itemId][dropInfo.DropType]; ok {
+					kinds++
+				}
+			}
+			observed[kinds] = struct{}{}
+		}
+
+		suggestion := &DropInfoBoundsSuggestion{
+			DropID:   dropInfo.DropID,
+			DropType: dropInfo.DropType,
+			Current:  dropInfo.Bounds,
+			Observed: make([]int, 0, len(observed)),
+		}
+		if dropInfo.ItemID.Valid {
+			if item, ok := itemsMapById[int(dropInfo.ItemID.Int64)]; ok {
+				suggestion.ItemID = null.StringFrom(item.ArkItemID)
+			}
+		}
+		for quantity := range observed {
+			suggestion.Observed = append(suggestion.Observed, quantity)
+		}
+		sort.Ints(suggestion.Observed)
+
+		ambiguous := ambiguousDropTypes[dropInfo.DropType]
+		if dropInfo.ItemID.Valid {
+			ambiguous = len(itemDropTypes[int(dropInfo.ItemID.Int64)]) > 1
+		}
+		if suggestions.Sufficient && !ambiguous && len(suggestion.Observed) > 0 {
+			suggestion.Suggested = suggestBounds(dropInfo.Bounds, suggestion.Observed)
+			suggestion.Changed = !boundsEqual(dropInfo.Bounds, suggestion.Suggested)
+		}
+		suggestions.Suggestions = append(suggestions.Suggestions, suggestion)
+	}
+	return suggestions, nil
+}
+
+// suggestBounds spans the bounds over the quantities observed, sorted. The exceptions of the current bounds are kept
+// as long as they stay unobserved within the new bounds.
+func suggestBounds(current *model.Bounds, observed []int) *model.Bounds {
+	bounds := &model.Bounds{
+		Lower: observed[0],
+		Upper: observed[len(observed)-1],
+	}
+	if current == nil {
+		return bounds
+	}
+	for _, exception := range current.Exceptions {
+		if exception < bounds.Lower || exception > bounds.Upper {
+			continue
+		}
+		if i := sort.SearchInts(observed, exception); i < len(observed) && observed[i] == exception {
+			continue
+		}
+		bounds.Exceptions = append(bounds.Exceptions, exception)
+	}
+	return bounds
+}
+
+func boundsEqual(a, b *model.Bounds) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Lower != b.Lower || a.Upper != b.Upper || len(a.Exceptions) != len(b.Exceptions) {
+		return false
+	}
+	for i := range a.Exceptions {
+		if a.Exceptions[i] != b.Exceptions[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyDropInfoBounds replaces the bounds of the drop infos in the request, which must all belong to its server
+func (s *DropInfoBounds) ApplyDropInfoBounds(ctx context.Context, req *types.ApplyDropInfoBoundsRequest) ([]*model.DropInfo, error) {
+	dropInfos := make([]*model.DropInfo, 0, len(req.Bounds))
+	seen := make(map[int]struct{}, len(req.Bounds))
+	for _, update := range req.Bounds {
+		if _, ok := seen[update.DropID]; ok {
+			return nil, pgerr.ErrInvalidReq.Msg("drop info %d is listed more than once", update.DropID)
+		}
+		seen[update.DropID] = struct{}{}
+
+		dropInfo, err := s.DropInfoRepo.GetDropInfo(ctx, update.DropID)
+		if err != nil {
+			return nil, err
+		}
+		if dropInfo.Server != req.Server {
+			return nil, pgerr.ErrInvalidReq.Msg("drop info %d is not of server %s", update.DropID, req.Server)
+		}
+		exceptions := append([]int(nil), update.Exceptions...)
+		sort.Ints(exceptions)
+		dropInfo.Bounds = &model.Bounds{
+			Lower:      update.Lower,
+			Upper:      update.Upper,
+			Exceptions: exceptions,
+		}
+		dropInfos = append(dropInfos, dropInfo)
+	}
+
+	if _, err := s.MetadataSnapshotService.Capture(ctx, "apply drop info bounds of "+req.Server); err != nil {
+		return nil, err
+	}
+	if err := s.DropInfoRepo.UpdateDropInfoBounds(ctx, dropInfos); err != nil {
+		return nil, err
+	}
+	purgeEventMetadataCaches([]string{req.Server})
+
+	log.Info().
+		Str("evt.name", "admin.drop_infos.bounds_applied").
+		Str("server", req.Server).
+		Int("dropInfos", len(dropInfos)).
+		Msg("applied drop info bounds")
+	return dropInfos, nil
+}
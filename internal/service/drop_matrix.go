@@ -2,21 +2,27 @@ package service
 
 import (
 	"context"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"exusiai.dev/gommon/constant"
-	"github.com/ahmetb/go-linq/v3"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
 	"gopkg.in/guregu/null.v3"
 
 	"exusiai.dev/backend-next/internal/app/appconfig"
 	"exusiai.dev/backend-next/internal/model"
 	"exusiai.dev/backend-next/internal/model/cache"
 	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+	"exusiai.dev/backend-next/internal/pkg/async"
+	"exusiai.dev/backend-next/internal/pkg/gameday"
+	"exusiai.dev/backend-next/internal/pkg/observability"
 	"exusiai.dev/backend-next/internal/util"
 )
 
@@ -28,6 +34,11 @@ type DropMatrix struct {
 	DropMatrixElementService *DropMatrixElement
 	StageService             *Stage
 	ItemService              *Item
+	Tunables                 *Tunables
+	DropMatrixViews          *DropMatrixViews
+	DailyRollups             *DropReportDailyRollups
+	CacheTTLOverrides        *CacheTTLOverrides
+	RetentionService         *Retention
 }
 
 func NewDropMatrix(
@@ -38,6 +49,11 @@ func NewDropMatrix(
 	dropMatrixElementService *DropMatrixElement,
 	stageService *Stage,
 	itemService *Item,
+	tunables *Tunables,
+	dropMatrixViews *DropMatrixViews,
+	dailyRollups *DropReportDailyRollups,
+	cacheTTLOverrides *CacheTTLOverrides,
+	retentionService *Retention,
 ) *DropMatrix {
 	return &DropMatrix{
 		Config:                   config,
@@ -47,16 +63,28 @@ func NewDropMatrix(
 		DropMatrixElementService: dropMatrixElementService,
 		StageService:             stageService,
 		ItemService:              itemService,
+		Tunables:                 tunables,
+		DropMatrixViews:          dropMatrixViews,
+		DailyRollups:             dailyRollups,
+		CacheTTLOverrides:        cacheTTLOverrides,
+		RetentionService:         retentionService,
 	}
 }
 
 // =========== Global & Personal, Max Accumulable ===========
 
-// Cache: shimGlobalDropMatrix#server|showClosedZones|sourceCategory:{server}|{showClosedZones}|{sourceCategory}, 24 hrs, records last modified time
+// Cache: shimGlobalDropMatrix#server|showClosedZones|sourceCategory:{server}|{showClosedZones}|{sourceCategory}, 24 hrs (tunable, overridable), records last modified time
 // Called by frontend, used for both global and personal, only for max accumulable results
 func (s *DropMatrix) GetShimDropMatrix(
 	ctx context.Context, server string, showClosedZones bool, stageFilterStr string, itemFilterStr string, accountId null.Int, sourceCategory string,
-) (*modelv2.DropMatrixQueryResult, error) {
+) (_ *modelv2.DropMatrixQueryResult, err error) {
+	ctx, span := tracer.Start(ctx, "DropMatrix.GetShimDropMatrix", trace.WithAttributes(
+		attribute.String("server", server),
+		attribute.Bool("personal", accountId.Valid),
+		attribute.String("sourceCategory", sourceCategory),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+
 	valueFunc := func() (*modelv2.DropMatrixQueryResult, error) {
 		var dropMatrixQueryResult *model.DropMatrixQueryResult
 		var err error
@@ -78,7 +106,7 @@ func (s *DropMatrix) GetShimDropMatrix(
 	var results modelv2.DropMatrixQueryResult
 	if !accountId.Valid && stageFilterStr == "" && itemFilterStr == "" {
 		key := server + constant.CacheSep + strconv.FormatBool(showClosedZones) + constant.CacheSep + sourceCategory
-		calculated, err := cache.ShimGlobalDropMatrix.MutexGetSet(key, &results, valueFunc, 24*time.Hour)
+		calculated, err := cache.ShimGlobalDropMatrix.MutexGetSet(key, &results, valueFunc, s.GlobalCacheTTL(server))
 		if err != nil {
 			return nil, err
 		} else if calculated {
@@ -90,14 +118,22 @@ func (s *DropMatrix) GetShimDropMatrix(
 	return &results, nil
 }
 
+// GlobalCacheTTL returns how long the global drop matrices of the server are cached for
+func (s *DropMatrix) GlobalCacheTTL(server string) time.Duration {
+	return s.CacheTTLOverrides.TTL(server, nil, s.Tunables.Duration(TunableGlobalMatrixCacheTTL))
+}
+
 // =========== Global Max Accumulable ===========
 
 // Calc today's drop matrix elements and save to DB
 // Called by worker
-func (s *DropMatrix) RunCalcDropMatrixJob(ctx context.Context, server string) error {
+func (s *DropMatrix) RunCalcDropMatrixJob(ctx context.Context, server string) (err error) {
+	ctx, span := tracer.Start(ctx, "DropMatrix.RunCalcDropMatrixJob", trace.WithAttributes(attribute.String("server", server)))
+	defer func() { observability.EndSpan(span, err) }()
+
 	date := time.Now()
 	endTime := time.UnixMilli(constant.FakeEndTimeMilli)
-	dropMatrixElements, err := s.calcDropMatrixByGivenDate(ctx, server, &date, &endTime, s.Config.MatrixWorkerSourceCategories)
+	dropMatrixElements, err := s.calcDropMatrixByGivenDate(ctx, server, &date, &endTime, nil, s.Config.MatrixWorkerSourceCategories)
 	if err != nil {
 		return err
 	}
@@ -106,30 +142,35 @@ func (s *DropMatrix) RunCalcDropMatrixJob(ctx context.Context, server string) er
 	if err != nil {
 		return err
 	}
-	s.DropMatrixElementService.DeleteByServerAndDayNum(ctx, server, dayNum)
-
-	if len(dropMatrixElements) != 0 {
-		s.DropMatrixElementService.BatchSaveElements(ctx, dropMatrixElements, server)
+	if err := s.DropMatrixElementService.BatchSaveElements(ctx, dropMatrixElements, server, dayNum, nil); err != nil {
+		return err
 	}
 
 	// If this is the first time we run the job for this server at this day, we need to update the drop matrix for the previous day.
 	// TODO: archive all drop reports for the today-60d and upload to s3
 	if !exists {
 		yesterday := date.Add(time.Hour * -24)
-		dropMatrixElementsForYesterday, err := s.calcDropMatrixByGivenDate(ctx, server, &yesterday, nil, s.Config.MatrixWorkerSourceCategories)
+		dropMatrixElementsForYesterday, err := s.calcDropMatrixByGivenDate(ctx, server, &yesterday, nil, nil, s.Config.MatrixWorkerSourceCategories)
 		if err != nil {
 			return err
 		}
-		s.DropMatrixElementService.DeleteByServerAndDayNum(ctx, server, dayNum-1)
-		if len(dropMatrixElementsForYesterday) != 0 {
-			s.DropMatrixElementService.BatchSaveElements(ctx, dropMatrixElementsForYesterday, server)
+		if err := s.DropMatrixElementService.BatchSaveElements(ctx, dropMatrixElementsForYesterday, server, dayNum-1, nil); err != nil {
+			return err
 		}
 	}
 
+	return s.purgeGlobalDropMatrixCaches(server)
+}
+
+func (s *DropMatrix) purgeGlobalDropMatrixCaches(server string) error {
 	for _, sourceCategory := range s.Config.MatrixWorkerSourceCategories {
 		if err := cache.GlobalDropMatrix.Delete(server + constant.CacheSep + sourceCategory); err != nil {
 			return err
 		}
+		// the segments of the stages of which the cache TTL is overridden
+		if err := cache.GlobalDropMatrix.DeletePrefix(server + constant.CacheSep + sourceCategory + constant.CacheSep); err != nil {
+			return err
+		}
 		if err := cache.ShimGlobalDropMatrix.Delete(server + constant.CacheSep + "true" + constant.CacheSep + sourceCategory); err != nil {
 			return err
 		}
@@ -143,29 +184,44 @@ func (s *DropMatrix) RunCalcDropMatrixJob(ctx context.Context, server string) er
 	return nil
 }
 
-// Update drop matrix elements for a given date (entire day)
-// Called by admin api
-func (s *DropMatrix) UpdateDropMatrixByGivenDate(ctx context.Context, server string, date *time.Time) error {
-	dropMatrixElements, err := s.calcDropMatrixByGivenDate(ctx, server, date, nil, s.Config.MatrixWorkerSourceCategories)
+// Update drop matrix elements for a given date (entire day), only those of stageIds unless it is empty
+// Called by admin api and refresh jobs
+func (s *DropMatrix) UpdateDropMatrixByGivenDate(ctx context.Context, server string, date *time.Time, stageIds []int) (err error) {
+	ctx, span := tracer.Start(ctx, "DropMatrix.UpdateDropMatrixByGivenDate", trace.WithAttributes(
+		attribute.String("server", server),
+		attribute.String("date", date.Format("2006-01-02")),
+		attribute.Int("stages", len(stageIds)),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+
+	// the elements of the day are replaced as a whole, which would lose the counts of the purged reports
+	if err := s.RetentionService.CheckNotPurged(time.UnixMilli(util.GetDayStartTime(date, server))); err != nil {
+		return err
+	}
+	dropMatrixElements, err := s.calcDropMatrixByGivenDate(ctx, server, date, nil, stageIds, s.Config.MatrixWorkerSourceCategories)
 	if err != nil {
 		return err
 	}
 	dayNum := util.GetDayNum(date, server)
-	s.DropMatrixElementService.DeleteByServerAndDayNum(ctx, server, dayNum)
-	if len(dropMatrixElements) != 0 {
-		s.DropMatrixElementService.BatchSaveElements(ctx, dropMatrixElements, server)
-	}
-	return nil
+	return s.DropMatrixElementService.BatchSaveElements(ctx, dropMatrixElements, server, dayNum, stageIds)
 }
 
 /**
  * Calculate drop matrix for a given date
  * date: indicates the date to calculate drop matrix
  * endTime: if nil, the calculation will be done for the entire day; otherwise, the calculation will be done for the partial day
+ * stageIds: if empty, the calculation will be done for every stage; otherwise, only for the given stages
  */
 func (s *DropMatrix) calcDropMatrixByGivenDate(
-	ctx context.Context, server string, date *time.Time, endTime *time.Time, sourceCategories []string,
-) ([]*model.DropMatrixElement, error) {
+	ctx context.Context, server string, date *time.Time, endTime *time.Time, stageIds []int, sourceCategories []string,
+) (_ []*model.DropMatrixElement, err error) {
+	ctx, span := tracer.Start(ctx, "DropMatrix.calcDropMatrixByGivenDate", trace.WithAttributes(
+		attribute.String("server", server),
+		attribute.String("date", date.Format("2006-01-02")),
+		attribute.Bool("partial", endTime != nil),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+
 	dropMatrixElements := make([]*model.DropMatrixElement, 0)
 
 	start := time.UnixMilli(util.GetDayStartTime(date, server))
@@ -183,6 +239,9 @@ func (s *DropMatrix) calcDropMatrixByGivenDate(
 	}
 	stageIdsItemIdsMapByTimeRangeStr := make(map[string]map[int][]int, 0)
 	for stageId, timeRangesMapByItemId := range timeRangesMap {
+		if len(stageIds) > 0 && !lo.Contains(stageIds, stageId) {
+			continue
+		}
 		for itemId, timeRanges := range timeRangesMapByItemId {
 			for _, timeRange := range timeRanges {
 				intersection := util.GetIntersection(timeRange, timeRangeGiven)
@@ -201,55 +260,98 @@ func (s *DropMatrix) calcDropMatrixByGivenDate(
 		}
 	}
 
-	for timeRangeStr, stageIdsItemIdsMap := range stageIdsItemIdsMapByTimeRangeStr {
+	// the time ranges are calculated in a deterministic order, each into a slot of its own, so that neither the order
+	// of the elements nor the errors depend on which of the concurrent calculations finishes first
+	timeRangeStrs := lo.Keys(stageIdsItemIdsMapByTimeRangeStr)
+	sort.Strings(timeRangeStrs)
+	var queryCtxs []*model.DropReportQueryContext
+	var queryTimeRangeStrs []string
+	for _, timeRangeStr := range timeRangeStrs {
 		timeRange := model.TimeRangeFromString(timeRangeStr)
+		stageIdsItemIdsMap := stageIdsItemIdsMapByTimeRangeStr[timeRangeStr]
 		for _, sourceCategory := range sourceCategories {
-			queryCtx := &model.DropReportQueryContext{
+			queryCtxs = append(queryCtxs, &model.DropReportQueryContext{
 				Server:             server,
 				StartTime:          timeRange.StartTime,
 				EndTime:            timeRange.EndTime,
 				SourceCategory:     sourceCategory,
 				ExcludeNonOneTimes: false,
 				StageItemFilter:    &stageIdsItemIdsMap,
-			}
+			})
+			queryTimeRangeStrs = append(queryTimeRangeStrs, timeRangeStr)
+		}
+	}
+
+	results := make([][]*model.DropMatrixElement, len(queryCtxs))
+	errs := make([]error, len(queryCtxs))
+	var eg errgroup.Group
+	eg.SetLimit(lo.Max([]int{s.Config.MatrixWorkerConcurrency, 1}))
+	for i, queryCtx := range queryCtxs {
+		i, queryCtx := i, queryCtx
+		eg.Go(func() error {
+			// a failed time range does not cancel the others, so that all the failed ones are reported at once
 			res, err := s.calcDropMatrix(ctx, queryCtx)
 			if err != nil {
-				return nil, err
+				errs[i] = &DropMatrixRangeError{
+					TimeRange:      queryTimeRangeStrs[i],
+					SourceCategory: queryCtx.SourceCategory,
+					Err:            err,
+				}
+				return nil
 			}
-			dropMatrixElements = append(dropMatrixElements, res...)
-		}
+			results[i] = res
+			return nil
+		})
 	}
-	return dropMatrixElements, nil
-}
+	_ = eg.Wait()
 
-func (s *DropMatrix) calcDropMatrix(ctx context.Context, queryCtx *model.DropReportQueryContext) ([]*model.DropMatrixElement, error) {
-	var combinedResults []*model.CombinedResultForDropMatrix
-	timesResults, err := s.DropReportService.CalcTotalTimesForDropMatrix(ctx, queryCtx)
-	if err != nil {
+	rangeErrs := async.Errors{E: lo.Filter(errs, func(err error, _ int) bool { return err != nil })}
+	if err := rangeErrs.Wrapped(); err != nil {
 		return nil, err
 	}
-	quantityUniqCountResults, err := s.DropReportService.CalcQuantityUniqCount(ctx, queryCtx)
+	for _, res := range results {
+		dropMatrixElements = append(dropMatrixElements, res...)
+	}
+	return dropMatrixElements, nil
+}
+
+// DropMatrixRangeError is the error of calculating the drop matrix of one of the time ranges of a day, which tells
+// the time range and source category that failed
+type DropMatrixRangeError struct {
+	TimeRange      string
+	SourceCategory string
+	Err            error
+}
+
+func (e *DropMatrixRangeError) Error() string {
+	return "drop matrix of time range " + e.TimeRange + " for " + e.SourceCategory + ": " + e.Err.Error()
+}
+
+func (e *DropMatrixRangeError) Unwrap() error {
+	return e.Err
+}
+
+func (s *DropMatrix) calcDropMatrix(ctx context.Context, queryCtx *model.DropReportQueryContext) (_ []*model.DropMatrixElement, err error) {
+	ctx, span := tracer.Start(ctx, "DropMatrix.calcDropMatrix", trace.WithAttributes(
+		attribute.String("server", queryCtx.Server),
+		attribute.String("sourceCategory", queryCtx.SourceCategory),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+
+	combinedResults, err := s.DropReportService.CalcDropMatrixAggregates(ctx, queryCtx)
 	if err != nil {
 		return nil, err
 	}
-	quantityResults := calcTotalQuantityForDropMatrix(quantityUniqCountResults)
-
-	oneBatch := s.combineQuantityAndTimesResults(quantityResults, timesResults, quantityUniqCountResults, nil)
-	combinedResults = append(combinedResults, oneBatch...)
+	s.validateCombinedResults(combinedResults)
 
-	// save stage times for later use
+	// save stage times and reporters for later use
 	stageTimesMap := map[int]int{}
+	stageReportersMap := map[int]int{}
 
 	// grouping results by stage id
-	var groupedResults []linq.Group
-	linq.From(combinedResults).
-		GroupByT(
-			func(el *model.CombinedResultForDropMatrix) int { return el.StageID },
-			func(el *model.CombinedResultForDropMatrix) *model.CombinedResultForDropMatrix { return el }).ToSlice(&groupedResults)
-
 	dropMatrixElements := make([]*model.DropMatrixElement, 0)
-	for _, el := range groupedResults {
-		stageId := el.Key.(int)
+	for _, el := range util.GroupBy(combinedResults, func(el *model.CombinedResultForDropMatrix) int { return el.StageID }) {
+		stageId := el.Key
 		itemIds := (*queryCtx.StageItemFilter)[stageId]
 
 		// get all item ids which are dropped in this stage, save in dropSet
@@ -263,28 +365,30 @@ func (s *DropMatrix) calcDropMatrix(ctx context.Context, queryCtx *model.DropRep
 			return nil, err
 		}
 		var dropItemIds []int
-		linq.From(dropInfos).
-			WhereT(func(el *model.DropInfo) bool { return el.ItemID.Valid }).
-			SelectT(func(el *model.DropInfo) int { return int(el.ItemID.Int64) }).
-			ToSlice(&dropItemIds)
-		linq.From(dropItemIds).WhereT(func(itemId int) bool { return linq.From(itemIds).Contains(itemId) }).ToSlice(&dropItemIds)
+		for _, dropInfo := range dropInfos {
+			if dropInfo.ItemID.Valid && lo.Contains(itemIds, int(dropInfo.ItemID.Int64)) {
+				dropItemIds = append(dropItemIds, int(dropInfo.ItemID.Int64))
+			}
+		}
 		// use a fake hashset to save item ids
 		dropSet := make(map[int]struct{}, len(dropItemIds))
 		for _, itemId := range dropItemIds {
 			dropSet[itemId] = struct{}{}
 		}
 
-		for _, el2 := range el.Group {
-			itemId := el2.(*model.CombinedResultForDropMatrix).ItemID
-			quantity := el2.(*model.CombinedResultForDropMatrix).Quantity
-			times := el2.(*model.CombinedResultForDropMatrix).Times
-			quantityBuckets := el2.(*model.CombinedResultForDropMatrix).QuantityBuckets
+		for _, el2 := range el.Values {
+			itemId := el2.ItemID
+			quantity := el2.Quantity
+			times := el2.Times
+			quantityBuckets := el2.QuantityBuckets
+			reporters := el2.Reporters
 			dropMatrixElement := model.DropMatrixElement{
 				StageID:         stageId,
 				ItemID:          itemId,
 				Quantity:        quantity,
 				QuantityBuckets: quantityBuckets,
 				Times:           times,
+				Reporters:       reporters,
 				Server:          queryCtx.Server,
 				SourceCategory:  queryCtx.SourceCategory,
 				StartTime:       queryCtx.StartTime,
@@ -292,8 +396,9 @@ func (s *DropMatrix) calcDropMatrix(ctx context.Context, queryCtx *model.DropRep
 				DayNum:          util.GetDayNum(queryCtx.StartTime, queryCtx.Server),
 			}
 			dropMatrixElements = append(dropMatrixElements, &dropMatrixElement)
-			delete(dropSet, itemId)        // remove existing item ids from drop set
-			stageTimesMap[stageId] = times // record stage times into a map
+			delete(dropSet, itemId)                // remove existing item ids from drop set
+			stageTimesMap[stageId] = times         // record stage times into a map
+			stageReportersMap[stageId] = reporters // record stage reporters into a map
 		}
 		// add those items which do not show up in the matrix (quantity is 0)
 		for itemId := range dropSet {
@@ -304,6 +409,7 @@ func (s *DropMatrix) calcDropMatrix(ctx context.Context, queryCtx *model.DropRep
 				Quantity:        0,
 				QuantityBuckets: map[int]int{0: times},
 				Times:           times,
+				Reporters:       stageReportersMap[stageId],
 				Server:          queryCtx.Server,
 				SourceCategory:  queryCtx.SourceCategory,
 				StartTime:       queryCtx.StartTime,
@@ -316,98 +422,126 @@ func (s *DropMatrix) calcDropMatrix(ctx context.Context, queryCtx *model.DropRep
 	return dropMatrixElements, nil
 }
 
-// Cache: globalDropMatrix#server|sourceCategory:{server}|{sourceCategory}, 24 hrs
-func (s *DropMatrix) calcGlobalDropMatrix(ctx context.Context, server string, sourceCategory string) (*model.DropMatrixQueryResult, error) {
-	valueFunc := func() (*model.DropMatrixQueryResult, error) {
-		finalResult := &model.DropMatrixQueryResult{
-			Matrix: make([]*model.OneDropMatrixElement, 0),
-		}
+// Cache: globalDropMatrix#server|sourceCategory[|segment]:{server}|{sourceCategory}[|{segment}], 24 hrs (tunable), the
+// stages of which the cache TTL is overridden in segments of their own cached for the TTL overridden
+func (s *DropMatrix) calcGlobalDropMatrix(ctx context.Context, server string, sourceCategory string) (_ *model.DropMatrixQueryResult, err error) {
+	ctx, span := tracer.Start(ctx, "DropMatrix.calcGlobalDropMatrix", trace.WithAttributes(
+		attribute.String("server", server),
+		attribute.String("sourceCategory", sourceCategory),
+	))
+	defer func() { observability.EndSpan(span, err) }()
 
-		maxAccumulableTimeRanges, err := s.TimeRangeService.GetAllMaxAccumulableTimeRangesByServer(ctx, server)
+	finalResult := &model.DropMatrixQueryResult{
+		Matrix: make([]*model.OneDropMatrixElement, 0),
+	}
+	for _, segment := range s.CacheTTLOverrides.segments(server, s.Tunables.Duration(TunableGlobalMatrixCacheTTL)) {
+		var results model.DropMatrixQueryResult
+		key := server + constant.CacheSep + sourceCategory
+		if segment.Key != "" {
+			key += constant.CacheSep + segment.Key
+		}
+		_, err = cache.GlobalDropMatrix.MutexGetSet(key, &results, func() (*model.DropMatrixQueryResult, error) {
+			return s.calcGlobalDropMatrixSegment(ctx, server, sourceCategory, segment)
+		}, segment.TTL)
 		if err != nil {
 			return nil, err
 		}
+		finalResult.Matrix = append(finalResult.Matrix, results.Matrix...)
+	}
+	return finalResult, nil
+}
 
-		// Only consider the latest (max accumulable) time range for each stage
-		latestMaxAccumulableTimeRanges := make(map[int]map[int]*model.TimeRange, 0)
-		for stageId, timeRangesMapByItemId := range maxAccumulableTimeRanges {
-			if _, ok := latestMaxAccumulableTimeRanges[stageId]; !ok {
-				latestMaxAccumulableTimeRanges[stageId] = make(map[int]*model.TimeRange, 0)
-			}
-			for itemId, timeRanges := range timeRangesMapByItemId {
-				latestMaxAccumulableTimeRanges[stageId][itemId] = timeRanges[len(timeRanges)-1]
-			}
-		}
+// calcGlobalDropMatrixSegment calculates the global drop matrix of the stages covered by the segment
+func (s *DropMatrix) calcGlobalDropMatrixSegment(ctx context.Context, server string, sourceCategory string, segment *cacheSegment) (*model.DropMatrixQueryResult, error) {
+	finalResult := &model.DropMatrixQueryResult{
+		Matrix: make([]*model.OneDropMatrixElement, 0),
+	}
 
-		stageIdsItemIdsMapByTimeRangeStr := make(map[string]map[int][]int, 0)
-		for stageId, timeRangeMapByItemId := range latestMaxAccumulableTimeRanges {
-			for itemId, timeRange := range timeRangeMapByItemId {
-				timeRangeStr := timeRange.String()
-				if _, ok := stageIdsItemIdsMapByTimeRangeStr[timeRangeStr]; !ok {
-					stageIdsItemIdsMapByTimeRangeStr[timeRangeStr] = make(map[int][]int, 0)
-				}
-				if _, ok := stageIdsItemIdsMapByTimeRangeStr[timeRangeStr][stageId]; !ok {
-					stageIdsItemIdsMapByTimeRangeStr[timeRangeStr][stageId] = make([]int, 0)
-				}
-				stageIdsItemIdsMapByTimeRangeStr[timeRangeStr][stageId] = append(stageIdsItemIdsMapByTimeRangeStr[timeRangeStr][stageId], itemId)
-			}
+	maxAccumulableTimeRanges, err := s.TimeRangeService.GetAllMaxAccumulableTimeRangesByServer(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only consider the latest (max accumulable) time range for each stage
+	latestMaxAccumulableTimeRanges := make(map[int]map[int]*model.TimeRange, 0)
+	for stageId, timeRangesMapByItemId := range maxAccumulableTimeRanges {
+		if !segment.covers(stageId) {
+			continue
 		}
-		for timeRangeStr, stageIdsItemIdsMap := range stageIdsItemIdsMapByTimeRangeStr {
-			timeRange := model.TimeRangeFromString(timeRangeStr)
-			stageIds := make([]int, 0)
-			for stageId := range stageIdsItemIdsMap {
-				stageIds = append(stageIds, stageId)
-			}
+		if _, ok := latestMaxAccumulableTimeRanges[stageId]; !ok {
+			latestMaxAccumulableTimeRanges[stageId] = make(map[int]*model.TimeRange, 0)
+		}
+		for itemId, timeRanges := range timeRangesMapByItemId {
+			latestMaxAccumulableTimeRanges[stageId][itemId] = timeRanges[len(timeRanges)-1]
+		}
+	}
 
-			timesResults, err := s.DropMatrixElementService.GetAllTimesForGlobalDropMatrixMapByStageIdAndItemId(ctx, server, timeRange, stageIds, sourceCategory)
-			if err != nil {
-				return nil, err
-			}
-			quantityResults, err := s.DropMatrixElementService.GetAllQuantitiesForGlobalDropMatrixMapByStageIdAndItemId(ctx, server, timeRange, stageIds, sourceCategory)
-			if err != nil {
-				return nil, err
+	stageIdsItemIdsMapByTimeRangeStr := make(map[string]map[int][]int, 0)
+	for stageId, timeRangeMapByItemId := range latestMaxAccumulableTimeRanges {
+		for itemId, timeRange := range timeRangeMapByItemId {
+			timeRangeStr := timeRange.String()
+			if _, ok := stageIdsItemIdsMapByTimeRangeStr[timeRangeStr]; !ok {
+				stageIdsItemIdsMapByTimeRangeStr[timeRangeStr] = make(map[int][]int, 0)
 			}
-			quantityUniqCountResults, err := s.DropMatrixElementService.GetAllQuantityBucketsForGlobalDropMatrixMapByStageIdAndItemId(ctx, server, timeRange, stageIds, sourceCategory)
-			if err != nil {
-				return nil, err
+			if _, ok := stageIdsItemIdsMapByTimeRangeStr[timeRangeStr][stageId]; !ok {
+				stageIdsItemIdsMapByTimeRangeStr[timeRangeStr][stageId] = make([]int, 0)
 			}
+			stageIdsItemIdsMapByTimeRangeStr[timeRangeStr][stageId] = append(stageIdsItemIdsMapByTimeRangeStr[timeRangeStr][stageId], itemId)
+		}
+	}
+	for timeRangeStr, stageIdsItemIdsMap := range stageIdsItemIdsMapByTimeRangeStr {
+		timeRange := model.TimeRangeFromString(timeRangeStr)
+		stageIds := make([]int, 0)
+		for stageId := range stageIdsItemIdsMap {
+			stageIds = append(stageIds, stageId)
+		}
 
-			for stageId, itemIds := range stageIdsItemIdsMap {
-				for _, itemId := range itemIds {
-					timesResult, foundTimesResult := timesResults[stageId][itemId]
-					if !foundTimesResult {
-						continue
-					}
-					quantityResult, foundQuantityResult := quantityResults[stageId][itemId]
-					if !foundQuantityResult {
-						continue
-					}
-					quantityUniqCountResult, foundQuantityUniqCountResult := quantityUniqCountResults[stageId][itemId]
-					if !foundQuantityUniqCountResult {
-						continue
-					}
-					oneDropMatrixElement := &model.OneDropMatrixElement{
-						StageID:   stageId,
-						ItemID:    itemId,
-						Times:     timesResult.Times,
-						Quantity:  quantityResult.Quantity,
-						TimeRange: timeRange,
-						StdDev:    util.RoundFloat64(util.CalcStdDevFromQuantityBuckets(quantityUniqCountResult.QuantityBuckets, timesResult.Times, false), constant.StdDevDigits),
-					}
-					finalResult.Matrix = append(finalResult.Matrix, oneDropMatrixElement)
+		timesResults, err := s.DropMatrixElementService.GetAllTimesForGlobalDropMatrixMapByStageIdAndItemId(ctx, server, timeRange, stageIds, sourceCategory)
+		if err != nil {
+			return nil, err
+		}
+		quantityResults, err := s.DropMatrixElementService.GetAllQuantitiesForGlobalDropMatrixMapByStageIdAndItemId(ctx, server, timeRange, stageIds, sourceCategory)
+		if err != nil {
+			return nil, err
+		}
+		quantityUniqCountResults, err := s.DropMatrixElementService.GetAllQuantityBucketsForGlobalDropMatrixMapByStageIdAndItemId(ctx, server, timeRange, stageIds, sourceCategory)
+		if err != nil {
+			return nil, err
+		}
+		// the reporters of the days are not additive, so they are counted over the whole time range instead
+		reportersResults, err := s.DropReportService.CalcReportersForTimeRangeMapByStageId(ctx, server, timeRange, stageIds, sourceCategory)
+		if err != nil {
+			return nil, err
+		}
+
+		for stageId, itemIds := range stageIdsItemIdsMap {
+			for _, itemId := range itemIds {
+				timesResult, foundTimesResult := timesResults[stageId][itemId]
+				if !foundTimesResult {
+					continue
+				}
+				quantityResult, foundQuantityResult := quantityResults[stageId][itemId]
+				if !foundQuantityResult {
+					continue
+				}
+				quantityUniqCountResult, foundQuantityUniqCountResult := quantityUniqCountResults[stageId][itemId]
+				if !foundQuantityUniqCountResult {
+					continue
 				}
+				oneDropMatrixElement := &model.OneDropMatrixElement{
+					StageID:   stageId,
+					ItemID:    itemId,
+					Times:     timesResult.Times,
+					Reporters: reportersResults[stageId],
+					Quantity:  quantityResult.Quantity,
+					TimeRange: timeRange,
+					StdDev:    util.RoundFloat64(util.CalcStdDevFromQuantityBuckets(quantityUniqCountResult.QuantityBuckets, timesResult.Times, false), constant.StdDevDigits),
+				}
+				finalResult.Matrix = append(finalResult.Matrix, oneDropMatrixElement)
 			}
 		}
-		return finalResult, nil
-	}
-
-	var results model.DropMatrixQueryResult
-	key := server + constant.CacheSep + sourceCategory
-	_, err := cache.GlobalDropMatrix.MutexGetSet(key, &results, valueFunc, 24*time.Hour)
-	if err != nil {
-		return nil, err
 	}
-	return &results, nil
+	return finalResult, nil
 }
 
 // =========== Personal Max Accumulable ===========
@@ -520,22 +654,86 @@ func (s *DropMatrix) GetShimCustomizedDropMatrixResults(
 	return s.applyShimForDropMatrixQuery(ctx, server, true, "", "", customizedDropMatrixQueryResult)
 }
 
+// GetShimSegmentedDropMatrixResults splits the time range from startTime into intervalNum segments of intervalLength,
+// aligned to the days of the server as the trends are, and calculates the customized drop matrix of each segment.
+// The elements of the segments are responded together, each carrying the start and end of its segment.
+func (s *DropMatrix) GetShimSegmentedDropMatrixResults(
+	ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIds []int, itemIds []int, accountId null.Int, sourceCategory string,
+) (*modelv2.DropMatrixQueryResult, error) {
+	alignedStartTime := util.AlignToServerDay(*startTime, intervalLength, server)
+
+	segments := make([]*modelv2.DropMatrixQueryResult, intervalNum)
+	var eg errgroup.Group
+	eg.SetLimit(lo.Max([]int{s.Config.MatrixWorkerConcurrency, 1}))
+	for i := 0; i < intervalNum; i++ {
+		i := i
+		eg.Go(func() error {
+			start := alignedStartTime.Add(intervalLength * time.Duration(i))
+			end := start.Add(intervalLength)
+			segment, err := s.GetShimCustomizedDropMatrixResults(ctx, server, &model.TimeRange{StartTime: &start, EndTime: &end}, stageIds, itemIds, accountId, sourceCategory)
+			if err != nil {
+				return err
+			}
+			segments[i] = segment
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	result := &modelv2.DropMatrixQueryResult{
+		Matrix: make([]*modelv2.OneDropMatrixElement, 0),
+	}
+	for _, segment := range segments {
+		result.Matrix = append(result.Matrix, segment.Matrix...)
+	}
+	return result, nil
+}
+
+// Cache: shimRecentDropMatrix#server|days|showClosedZones|sourceCategory:{server}|{days}|{showClosedZones}|{sourceCategory}, 5 mins (tunable)
+// GetShimRecentDropMatrix calculates the drop matrix of the reports of the last days, rather than of the time ranges
+// of the stages. The window ends at the start of the current hour, so that the requests within the hour share it.
+// While the daily rollups are serving, the window is made of the current game day and the days before it instead.
+func (s *DropMatrix) GetShimRecentDropMatrix(
+	ctx context.Context, server string, days int, showClosedZones bool, stageFilterStr string, itemFilterStr string, accountId null.Int, sourceCategory string,
+) (*modelv2.DropMatrixQueryResult, error) {
+	valueFunc := func() (*modelv2.DropMatrixQueryResult, error) {
+		timeRange := util.RecentTimeRange(time.Now(), days)
+		if s.DailyRollups.Serving() {
+			timeRange = util.RecentGameDaysTimeRange(time.Now(), days, server)
+		}
+		dropMatrixElements, err := s.calcDropMatrixForTimeRanges(ctx, server, []*model.TimeRange{timeRange}, nil, nil, accountId, sourceCategory)
+		if err != nil {
+			return nil, err
+		}
+		dropMatrixQueryResult, err := s.convertDropMatrixElementsToDropMatrixQueryResult(ctx, dropMatrixElements)
+		if err != nil {
+			return nil, err
+		}
+		return s.applyShimForDropMatrixQuery(ctx, server, showClosedZones, stageFilterStr, itemFilterStr, dropMatrixQueryResult)
+	}
+
+	if accountId.Valid || stageFilterStr != "" || itemFilterStr != "" {
+		return valueFunc()
+	}
+	var results modelv2.DropMatrixQueryResult
+	key := server + constant.CacheSep + strconv.Itoa(days) + constant.CacheSep + strconv.FormatBool(showClosedZones) + constant.CacheSep + sourceCategory
+	if _, err := cache.ShimRecentDropMatrix.MutexGetSet(key, &results, valueFunc, s.Tunables.Duration(TunableCustomizedCacheTTL)); err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
+
 func (s *DropMatrix) convertDropMatrixElementsToDropMatrixQueryResult(ctx context.Context, dropMatrixElements []*model.DropMatrixElement) (*model.DropMatrixQueryResult, error) {
 	dropMatrixQueryResult := &model.DropMatrixQueryResult{
 		Matrix: make([]*model.OneDropMatrixElement, 0),
 	}
-	var groupedResults []linq.Group
-	linq.From(dropMatrixElements).
-		GroupByT(
-			func(el *model.DropMatrixElement) int { return el.RangeID },
-			func(el *model.DropMatrixElement) *model.DropMatrixElement { return el },
-		).
-		ToSlice(&groupedResults)
-	for _, group := range groupedResults {
-		rangeId := group.Key.(int)
+	for _, group := range util.GroupBy(dropMatrixElements, func(el *model.DropMatrixElement) int { return el.RangeID }) {
+		rangeId := group.Key
 		var timeRange *model.TimeRange
 		if rangeId == 0 {
-			timeRange = group.Group[0].(*model.DropMatrixElement).TimeRange
+			timeRange = group.Values[0].TimeRange
 		} else {
 			tr, err := s.TimeRangeService.GetTimeRangeById(ctx, rangeId)
 			if err != nil {
@@ -544,13 +742,13 @@ func (s *DropMatrix) convertDropMatrixElementsToDropMatrixQueryResult(ctx contex
 			timeRange = tr
 		}
 
-		for _, el := range group.Group {
-			dropMatrixElement := el.(*model.DropMatrixElement)
+		for _, dropMatrixElement := range group.Values {
 			dropMatrixQueryResult.Matrix = append(dropMatrixQueryResult.Matrix, &model.OneDropMatrixElement{
 				StageID:   dropMatrixElement.StageID,
 				ItemID:    dropMatrixElement.ItemID,
 				Quantity:  dropMatrixElement.Quantity,
 				Times:     dropMatrixElement.Times,
+				Reporters: dropMatrixElement.Reporters,
 				StdDev:    util.RoundFloat64(util.CalcStdDevFromQuantityBuckets(dropMatrixElement.QuantityBuckets, dropMatrixElement.Times, false), constant.StdDevDigits),
 				TimeRange: timeRange,
 			})
@@ -564,79 +762,90 @@ func (s *DropMatrix) convertDropMatrixElementsToDropMatrixQueryResult(ctx contex
 // Called in Personal Max Accumulable and Customized
 func (s *DropMatrix) calcDropMatrixForTimeRanges(
 	ctx context.Context, server string, timeRanges []*model.TimeRange, stageIdFilter []int, itemIdFilter []int, accountId null.Int, sourceCategory string,
-) ([]*model.DropMatrixElement, error) {
+) (_ []*model.DropMatrixElement, err error) {
+	ctx, span := tracer.Start(ctx, "DropMatrix.calcDropMatrixForTimeRanges", trace.WithAttributes(
+		attribute.String("server", server),
+		attribute.Int("timeRanges", len(timeRanges)),
+		attribute.Bool("personal", accountId.Valid),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+
 	dropInfos, err := s.DropInfoService.GetDropInfosWithFilters(ctx, server, timeRanges, stageIdFilter, itemIdFilter)
 	if err != nil {
 		return nil, err
 	}
 
+	// the global drop matrix of the time ranges which had ended by the last refresh of the views is read from them
+	refreshedAt, viewsRefreshed := time.Time{}, false
+	if !accountId.Valid {
+		refreshedAt, viewsRefreshed = s.DropMatrixViews.RefreshedAt(ctx)
+	}
+
+	stageItemFilter := util.GetStageIdItemIdMapFromDropInfos(dropInfos)
 	var combinedResults []*model.CombinedResultForDropMatrix
 	for _, timeRange := range timeRanges {
-		stageItemFilter := util.GetStageIdItemIdMapFromDropInfos(dropInfos)
-		queryCtx := &model.DropReportQueryContext{
-			Server:             server,
-			StartTime:          timeRange.StartTime,
-			EndTime:            timeRange.EndTime,
-			AccountID:          accountId,
-			StageItemFilter:    &stageItemFilter,
-			SourceCategory:     sourceCategory,
-			ExcludeNonOneTimes: false,
-		}
-		timesResults, err := s.DropReportService.CalcTotalTimesForDropMatrix(ctx, queryCtx)
-		if err != nil {
-			return nil, err
+		var oneBatch []*model.CombinedResultForDropMatrix
+		if viewsRefreshed && s.DropMatrixViews.Covers(timeRange, refreshedAt) {
+			oneBatch, err = s.DropReportService.CalcDropMatrixAggregatesForRange(ctx, server, timeRange.RangeID, &stageItemFilter, sourceCategory)
+		} else if !accountId.Valid && s.coveredByDailyRollups(server, timeRange) {
+			oneBatch, err = s.DropReportService.CalcDropMatrixAggregatesFromRollups(ctx, server, *timeRange.StartTime, *timeRange.EndTime, &stageItemFilter, sourceCategory)
+		} else {
+			oneBatch, err = s.DropReportService.CalcDropMatrixAggregates(ctx, &model.DropReportQueryContext{
+				Server:             server,
+				StartTime:          timeRange.StartTime,
+				EndTime:            timeRange.EndTime,
+				AccountID:          accountId,
+				StageItemFilter:    &stageItemFilter,
+				SourceCategory:     sourceCategory,
+				ExcludeNonOneTimes: false,
+			})
 		}
-		quantityUniqCountResults, err := s.DropReportService.CalcQuantityUniqCount(ctx, queryCtx)
 		if err != nil {
 			return nil, err
 		}
-		quantityResults := calcTotalQuantityForDropMatrix(quantityUniqCountResults)
-		oneBatch := s.combineQuantityAndTimesResults(quantityResults, timesResults, quantityUniqCountResults, timeRange)
+		s.validateCombinedResults(oneBatch)
+		for _, result := range oneBatch {
+			result.TimeRange = timeRange
+			// the only reporter of the personal results is the account itself
+			if accountId.Valid {
+				result.Reporters = 0
+			}
+		}
 		combinedResults = append(combinedResults, oneBatch...)
 	}
 
-	// save stage times for later use
+	// save stage times and reporters for later use
 	stageTimesMap := map[int]int{}
+	stageReportersMap := map[int]int{}
 
 	// grouping results by stage id
-	var groupedResults []linq.Group
-	linq.From(combinedResults).
-		GroupByT(
-			func(el *model.CombinedResultForDropMatrix) int { return el.StageID },
-			func(el *model.CombinedResultForDropMatrix) *model.CombinedResultForDropMatrix { return el }).ToSlice(&groupedResults)
-
 	dropMatrixElements := make([]*model.DropMatrixElement, 0)
-	for _, el := range groupedResults {
-		stageId := el.Key.(int)
-		var groupedResults2 []linq.Group
-		linq.From(el.Group).
-			GroupByT(
-				func(el *model.CombinedResultForDropMatrix) int { return el.TimeRange.RangeID },
-				func(el *model.CombinedResultForDropMatrix) *model.CombinedResultForDropMatrix { return el }).
-			ToSlice(&groupedResults2)
-		for _, el2 := range groupedResults2 {
-			rangeId := el2.Key.(int)
-			timeRange := el2.Group[0].(*model.CombinedResultForDropMatrix).TimeRange
+	for _, el := range util.GroupBy(combinedResults, func(el *model.CombinedResultForDropMatrix) int { return el.StageID }) {
+		stageId := el.Key
+		for _, el2 := range util.GroupBy(el.Values, func(el *model.CombinedResultForDropMatrix) int { return el.TimeRange.RangeID }) {
+			rangeId := el2.Key
+			timeRange := el2.Values[0].TimeRange
 
 			// get all item ids which are dropped in this stage and in this time range
 			var dropItemIds []int
 			if rangeId == 0 {
 				// rangeId == 0 means it is a customized time range instead of a time range from the database
-				dropInfosForSpecialTimeRange, err := s.DropInfoService.GetDropInfosWithFilters(ctx, server, []*model.TimeRange{el2.Group[0].(*model.CombinedResultForDropMatrix).TimeRange}, []int{stageId}, itemIdFilter)
+				dropInfosForSpecialTimeRange, err := s.DropInfoService.GetDropInfosWithFilters(ctx, server, []*model.TimeRange{timeRange}, []int{stageId}, itemIdFilter)
 				if err != nil {
 					return nil, err
 				}
-				linq.From(dropInfosForSpecialTimeRange).
-					WhereT(func(el *model.DropInfo) bool { return el.ItemID.Valid }).
-					SelectT(func(el *model.DropInfo) int { return int(el.ItemID.Int64) }).
-					ToSlice(&dropItemIds)
+				for _, dropInfo := range dropInfosForSpecialTimeRange {
+					if dropInfo.ItemID.Valid {
+						dropItemIds = append(dropItemIds, int(dropInfo.ItemID.Int64))
+					}
+				}
 			} else {
 				dropItemIds, _ = s.DropInfoService.GetItemDropSetByStageIdAndRangeId(ctx, server, stageId, rangeId)
 			}
 
 			// if item id filter is applied, then filter the drop item ids
 			if len(itemIdFilter) > 0 {
-				linq.From(dropItemIds).WhereT(func(itemId int) bool { return linq.From(itemIdFilter).Contains(itemId) }).ToSlice(&dropItemIds)
+				dropItemIds = lo.Filter(dropItemIds, func(itemId int, _ int) bool { return lo.Contains(itemIdFilter, itemId) })
 			}
 
 			// use a fake hashset to save item ids
@@ -645,11 +854,12 @@ func (s *DropMatrix) calcDropMatrixForTimeRanges(
 				dropSet[itemId] = struct{}{}
 			}
 
-			for _, el3 := range el2.Group {
-				itemId := el3.(*model.CombinedResultForDropMatrix).ItemID
-				quantity := el3.(*model.CombinedResultForDropMatrix).Quantity
-				times := el3.(*model.CombinedResultForDropMatrix).Times
-				quantityBuckets := el3.(*model.CombinedResultForDropMatrix).QuantityBuckets
+			for _, el3 := range el2.Values {
+				itemId := el3.ItemID
+				quantity := el3.Quantity
+				times := el3.Times
+				quantityBuckets := el3.QuantityBuckets
+				reporters := el3.Reporters
 				dropMatrixElement := model.DropMatrixElement{
 					StageID:         stageId,
 					ItemID:          itemId,
@@ -657,6 +867,7 @@ func (s *DropMatrix) calcDropMatrixForTimeRanges(
 					Quantity:        quantity,
 					QuantityBuckets: quantityBuckets,
 					Times:           times,
+					Reporters:       reporters,
 					Server:          server,
 					SourceCategory:  sourceCategory,
 				}
@@ -664,8 +875,9 @@ func (s *DropMatrix) calcDropMatrixForTimeRanges(
 					dropMatrixElement.TimeRange = timeRange
 				}
 				dropMatrixElements = append(dropMatrixElements, &dropMatrixElement)
-				delete(dropSet, itemId)        // remove existing item ids from drop set
-				stageTimesMap[stageId] = times // record stage times into a map
+				delete(dropSet, itemId)                // remove existing item ids from drop set
+				stageTimesMap[stageId] = times         // record stage times into a map
+				stageReportersMap[stageId] = reporters // record stage reporters into a map
 			}
 			// add those items which do not show up in the matrix (quantity is 0)
 			for itemId := range dropSet {
@@ -677,6 +889,7 @@ func (s *DropMatrix) calcDropMatrixForTimeRanges(
 					Quantity:        0,
 					QuantityBuckets: map[int]int{0: times},
 					Times:           times,
+					Reporters:       stageReportersMap[stageId],
 					Server:          server,
 					SourceCategory:  sourceCategory,
 				}
@@ -690,124 +903,19 @@ func (s *DropMatrix) calcDropMatrixForTimeRanges(
 	return dropMatrixElements, nil
 }
 
-func (s *DropMatrix) combineQuantityAndTimesResults(
-	quantityResults []*model.TotalQuantityResultForDropMatrix, timesResults []*model.TotalTimesResult,
-	quantityUniqCountResults []*model.QuantityUniqCountResultForDropMatrix, timeRange *model.TimeRange,
-) []*model.CombinedResultForDropMatrix {
-	combinedResults := make([]*model.CombinedResultForDropMatrix, 0)
-
-	var firstGroupResults []linq.Group
-	linq.From(quantityResults).
-		GroupByT(
-			func(result *model.TotalQuantityResultForDropMatrix) int { return result.StageID },
-			func(result *model.TotalQuantityResultForDropMatrix) *model.TotalQuantityResultForDropMatrix {
-				return result
-			}).
-		ToSlice(&firstGroupResults)
-	quantityResultsMap := make(map[int]map[int]int)
-	for _, firstGroupElements := range firstGroupResults {
-		stageId := firstGroupElements.Key.(int)
-		resultsMap := make(map[int]int)
-		linq.From(firstGroupElements.Group).
-			ToMapByT(&resultsMap,
-				func(el any) int { return el.(*model.TotalQuantityResultForDropMatrix).ItemID },
-				func(el any) int { return el.(*model.TotalQuantityResultForDropMatrix).TotalQuantity })
-		quantityResultsMap[stageId] = resultsMap
-	}
-
-	var quantityUniqCountGroupResultsByStageID []linq.Group
-	linq.From(quantityUniqCountResults).
-		GroupByT(func(result *model.QuantityUniqCountResultForDropMatrix) int { return result.StageID },
-			func(result *model.QuantityUniqCountResultForDropMatrix) *model.QuantityUniqCountResultForDropMatrix {
-				return result
-			}).
-		ToSlice(&quantityUniqCountGroupResultsByStageID)
-	quantityUniqCountResultsMap := make(map[int]map[int]map[int]int)
-	for _, quantityUniqCountGroupElements := range quantityUniqCountGroupResultsByStageID {
-		stageId := quantityUniqCountGroupElements.Key.(int)
-		var quantityUniqCountGroupResultsByItemID []linq.Group
-		linq.From(quantityUniqCountGroupElements.Group).
-			GroupByT(func(result *model.QuantityUniqCountResultForDropMatrix) int { return result.ItemID },
-				func(result *model.QuantityUniqCountResultForDropMatrix) *model.QuantityUniqCountResultForDropMatrix {
-					return result
-				}).ToSlice(&quantityUniqCountGroupResultsByItemID)
-		oneItemResultsMap := make(map[int]map[int]int)
-		for _, quantityUniqCountGroupElements2 := range quantityUniqCountGroupResultsByItemID {
-			itemId := quantityUniqCountGroupElements2.Key.(int)
-			subMap := make(map[int]int)
-			linq.From(quantityUniqCountGroupElements2.Group).
-				ToMapByT(&subMap,
-					func(el any) int { return el.(*model.QuantityUniqCountResultForDropMatrix).Quantity },
-					func(el any) int { return el.(*model.QuantityUniqCountResultForDropMatrix).Count })
-			oneItemResultsMap[itemId] = subMap
-		}
-		quantityUniqCountResultsMap[stageId] = oneItemResultsMap
-	}
-
-	var secondGroupResults []linq.Group
-	linq.From(timesResults).
-		GroupByT(
-			func(result *model.TotalTimesResult) int { return result.StageID },
-			func(result *model.TotalTimesResult) *model.TotalTimesResult { return result }).
-		ToSlice(&secondGroupResults)
-	for _, secondGroupResults := range secondGroupResults {
-		stageId := secondGroupResults.Key.(int)
-		quantityResultsMapForOneStage := quantityResultsMap[stageId]
-		quantityUniqCountResultsMapForOneStage := quantityUniqCountResultsMap[stageId]
-		for _, el := range secondGroupResults.Group {
-			times := el.(*model.TotalTimesResult).TotalTimes
-			for itemId, quantity := range quantityResultsMapForOneStage {
-				quantityBuckets := quantityUniqCountResultsMapForOneStage[itemId]
-				if !s.validateQuantityBucketsAndTimes(quantityBuckets, times) {
-					log.Warn().Msgf("quantity buckets and times are not matched for stage %d, item %d, please check drop pattern", stageId, itemId)
-				}
-				combinedResultForDropMatrix := &model.CombinedResultForDropMatrix{
-					StageID:         stageId,
-					ItemID:          itemId,
-					Quantity:        quantity,
-					QuantityBuckets: quantityBuckets,
-					Times:           times,
-				}
-				if timeRange != nil {
-					combinedResultForDropMatrix.TimeRange = timeRange
-				}
-				combinedResults = append(combinedResults, combinedResultForDropMatrix)
-			}
-		}
-	}
-	return combinedResults
+// coveredByDailyRollups tells whether the global drop matrix of the customized time range is aggregated from the daily
+// rollups, i.e. they are serving and the time range is made of whole game days of the server
+func (s *DropMatrix) coveredByDailyRollups(server string, timeRange *model.TimeRange) bool {
+	return timeRange.RangeID == 0 && s.DailyRollups.Serving() &&
+		gameday.IsStartTime(server, *timeRange.StartTime) && gameday.IsStartTime(server, *timeRange.EndTime)
 }
 
-func calcTotalQuantityForDropMatrix(uniqCountResults []*model.QuantityUniqCountResultForDropMatrix) []*model.TotalQuantityResultForDropMatrix {
-	uniqCountResultsMapByStageIdAndItemId := make(map[int]map[int][]*model.QuantityUniqCountResultForDropMatrix)
-	for _, result := range uniqCountResults {
-		if _, ok := uniqCountResultsMapByStageIdAndItemId[result.StageID]; !ok {
-			uniqCountResultsMapByStageIdAndItemId[result.StageID] = make(map[int][]*model.QuantityUniqCountResultForDropMatrix)
-		}
-		if _, ok := uniqCountResultsMapByStageIdAndItemId[result.StageID][result.ItemID]; !ok {
-			uniqCountResultsMapByStageIdAndItemId[result.StageID][result.ItemID] = make([]*model.QuantityUniqCountResultForDropMatrix, 0)
-		}
-		uniqCountResultsMapByStageIdAndItemId[result.StageID][result.ItemID] = append(uniqCountResultsMapByStageIdAndItemId[result.StageID][result.ItemID], result)
-	}
-	results := make([]*model.TotalQuantityResultForDropMatrix, 0)
-	for _, uniqCountResultsMapByItemId := range uniqCountResultsMapByStageIdAndItemId {
-		for _, uniqCountResults := range uniqCountResultsMapByItemId {
-			results = append(results, convertUniqCountResultsForOneItemToQuantityResult(uniqCountResults))
+func (s *DropMatrix) validateCombinedResults(combinedResults []*model.CombinedResultForDropMatrix) {
+	for _, result := range combinedResults {
+		if !s.validateQuantityBucketsAndTimes(result.QuantityBuckets, result.Times) {
+			log.Warn().Msgf("quantity buckets and times are not matched for stage %d, item %d, please check drop pattern", result.StageID, result.ItemID)
 		}
 	}
-	return results
-}
-
-func convertUniqCountResultsForOneItemToQuantityResult(uniqCountResults []*model.QuantityUniqCountResultForDropMatrix) *model.TotalQuantityResultForDropMatrix {
-	sum := 0
-	for _, result := range uniqCountResults {
-		sum += result.Quantity * result.Count
-	}
-	return &model.TotalQuantityResultForDropMatrix{
-		StageID:       uniqCountResults[0].StageID,
-		ItemID:        uniqCountResults[0].ItemID,
-		TotalQuantity: sum,
-	}
 }
 
 func (s *DropMatrix) validateQuantityBucketsAndTimes(quantityBuckets map[int]int, times int) bool {
@@ -859,24 +967,31 @@ func (s *DropMatrix) convertOneDropMatrixElementToStatsBundle(el *model.OneDropM
 }
 
 func (s *DropMatrix) applyShimForDropMatrixQuery(ctx context.Context, server string, showClosedZones bool, stageFilterStr, itemFilterStr string, queryResult *model.DropMatrixQueryResult) (*modelv2.DropMatrixQueryResult, error) {
-	itemsMapById, err := s.ItemService.GetItemsMapById(ctx)
+	stageIds := make([]int, 0, len(queryResult.Matrix))
+	itemIds := make([]int, 0, len(queryResult.Matrix))
+	for _, el := range queryResult.Matrix {
+		stageIds = append(stageIds, el.StageID)
+		itemIds = append(itemIds, el.ItemID)
+	}
+	stagesMapById, err := s.StageService.GetStagesByIds(ctx, stageIds)
 	if err != nil {
 		return nil, err
 	}
-
-	stagesMapById, err := s.StageService.GetStagesMapById(ctx)
+	itemsMapById, err := s.ItemService.GetItemsByIds(ctx, itemIds)
 	if err != nil {
 		return nil, err
 	}
 
 	// get opening stages from dropinfos
-	var openingStageIds []int
+	openingStageIds := make(map[int]struct{})
 	if !showClosedZones {
 		currentDropInfos, err := s.DropInfoService.GetCurrentDropInfosByServer(ctx, server)
 		if err != nil {
 			return nil, err
 		}
-		linq.From(currentDropInfos).SelectT(func(el *model.DropInfo) int { return el.StageID }).Distinct().ToSlice(&openingStageIds)
+		for _, dropInfo := range currentDropInfos {
+			openingStageIds[dropInfo.StageID] = struct{}{}
+		}
 	}
 
 	// convert comma-splitted stage filter param to a hashset
@@ -903,18 +1018,24 @@ func (s *DropMatrix) applyShimForDropMatrixQuery(ctx context.Context, server str
 		Matrix: make([]*modelv2.OneDropMatrixElement, 0),
 	}
 	for _, el := range queryResult.Matrix {
-		if !showClosedZones && !linq.From(openingStageIds).Contains(el.StageID) {
+		if _, ok := openingStageIds[el.StageID]; !showClosedZones && !ok {
 			continue
 		}
 
-		stage := stagesMapById[el.StageID]
+		stage, ok := stagesMapById[el.StageID]
+		if !ok {
+			continue
+		}
 		if len(stageFilterSet) > 0 {
 			if _, ok := stageFilterSet[stage.ArkStageID]; !ok {
 				continue
 			}
 		}
 
-		item := itemsMapById[el.ItemID]
+		item, ok := itemsMapById[el.ItemID]
+		if !ok {
+			continue
+		}
 		if len(itemFilterSet) > 0 {
 			if _, ok := itemFilterSet[item.ArkItemID]; !ok {
 				continue
@@ -933,9 +1054,11 @@ func (s *DropMatrix) applyShimForDropMatrixQuery(ctx context.Context, server str
 			ItemID:    item.ArkItemID,
 			Quantity:  el.Quantity,
 			Times:     el.Times,
+			Rate:      util.DropRate(el.Quantity, el.Times),
 			StdDev:    el.StdDev,
 			StartTime: el.TimeRange.StartTime.UnixMilli(),
 			EndTime:   endTime,
+			Reporters: el.Reporters,
 		}
 		results.Matrix = append(results.Matrix, &oneDropMatrixElement)
 	}
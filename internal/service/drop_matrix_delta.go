@@ -0,0 +1,281 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/lo"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	pkgcache "exusiai.dev/backend-next/internal/pkg/cache"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+	"exusiai.dev/backend-next/internal/util"
+)
+
+const (
+	// dropMatrixSnapshotsKeyPrefix prefixes the sorted sets of the snapshot times of each server
+	dropMatrixSnapshotsKeyPrefix = "matrixsnapshots:"
+	// dropMatrixSnapshotKeyPrefix prefixes the compressed snapshots, keyed by server and time
+	dropMatrixSnapshotKeyPrefix = "matrixsnapshot:"
+)
+
+var errDropMatrixSnapshotMissing = errors.New("drop matrix snapshot missing")
+
+// DropMatrixDelta records a snapshot of the global drop matrix of a server, including the closed zones, every time the
+// matrix is refreshed, and computes the deltas between those snapshots. Deltas are only computed between snapshots
+// so that every instance responds the same delta for the same time. The last snapshot of each day is also kept
+// in the database for good, for the matrix to be queried as of a past date.
+type DropMatrixDelta struct {
+	Config                *appconfig.Config
+	Redis                 *redis.Client
+	DropMatrixService     *DropMatrix
+	DropMatrixHistoryRepo *repo.DropMatrixHistory
+}
+
+func NewDropMatrixDelta(config *appconfig.Config, redisClient *redis.Client, dropMatrixService *DropMatrix, dropMatrixHistoryRepo *repo.DropMatrixHistory) *DropMatrixDelta {
+	return &DropMatrixDelta{
+		Config:                config,
+		Redis:                 redisClient,
+		DropMatrixService:     dropMatrixService,
+		DropMatrixHistoryRepo: dropMatrixHistoryRepo,
+	}
+}
+
+// RecordSnapshot records the current global drop matrix of the server as the snapshot of the day, and drops the
+// snapshots past retention
+func (s *DropMatrixDelta) RecordSnapshot(ctx context.Context, server string) error {
+	matrix, err := s.DropMatrixService.GetShimDropMatrix(ctx, server, true, "", "", null.NewInt(0, false), constant.SourceCategoryAll)
+	if err != nil {
+		return err
+	}
+
+	content, err := pkgcache.MarshalPayload(matrix)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	t := strconv.FormatInt(now.UnixMilli(), 10)
+	retention := s.Config.MatrixSnapshotRetention
+	_, err = s.Redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, dropMatrixSnapshotKeyPrefix+server+":"+t, content, retention)
+		pipe.ZAdd(ctx, dropMatrixSnapshotsKeyPrefix+server, redis.Z{Score: float64(now.UnixMilli()), Member: t})
+		pipe.ZRemRangeByScore(ctx, dropMatrixSnapshotsKeyPrefix+server, "-inf", "("+strconv.FormatInt(now.Add(-retention).UnixMilli(), 10))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.DropMatrixHistoryRepo.SaveDropMatrixHistory(ctx, &model.DropMatrixHistory{
+		Server:     server,
+		DayNum:     util.GetDayNum(&now, server),
+		RecordedAt: now,
+		Content:    content,
+	})
+}
+
+// GetDropMatrixAsOf returns the last snapshot of the global drop matrix of the server recorded at or before the day
+// of asOf in the server, along with the time it has been recorded at. The filters are comma separated ark ids.
+func (s *DropMatrixDelta) GetDropMatrixAsOf(
+	ctx context.Context, server string, asOf time.Time, stageFilterStr string, itemFilterStr string,
+) (*modelv2.DropMatrixQueryResult, time.Time, error) {
+	history, err := s.DropMatrixHistoryRepo.GetDropMatrixHistoryAsOf(ctx, server, util.GetDayNum(&asOf, server))
+	if errors.Is(err, pgerr.ErrNotFound) {
+		return nil, time.Time{}, pgerr.ErrNotFound.Msg("no snapshot of the drop matrix has been recorded as of %s", asOf.Format("2006-01-02"))
+	} else if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	matrix, err := decodeDropMatrixSnapshot(history.Content)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if stageFilterStr != "" || itemFilterStr != "" {
+		stageFilter := filterSet(stageFilterStr)
+		itemFilter := filterSet(itemFilterStr)
+		matrix.Matrix = lo.Filter(matrix.Matrix, func(el *modelv2.OneDropMatrixElement, _ int) bool {
+			_, stageOk := stageFilter[el.StageID]
+			_, itemOk := itemFilter[el.ItemID]
+			return (stageFilter == nil || stageOk) && (itemFilter == nil || itemOk)
+		})
+	}
+	return matrix, history.RecordedAt, nil
+}
+
+// filterSet returns the set of the comma separated ids, or nil when there are none
+func filterSet(filterStr string) map[string]struct{} {
+	if filterStr == "" {
+		return nil
+	}
+	set := make(map[string]struct{})
+	for _, id := range strings.Split(filterStr, ",") {
+		set[strings.TrimSpace(id)] = struct{}{}
+	}
+	return set
+}
+
+// GetDropMatrixDelta returns the elements of the latest snapshot of the matrix that differ from the latest snapshot
+// taken at or before since. The whole matrix is returned when there is no such snapshot, and nil is returned
+// when nothing has been recorded after since.
+func (s *DropMatrixDelta) GetDropMatrixDelta(ctx context.Context, server string, since int64) (*modelv3.DropMatrixDelta, error) {
+	snapshotsKey := dropMatrixSnapshotsKeyPrefix + server
+	latest, err := s.Redis.ZRevRangeWithScores(ctx, snapshotsKey, 0, 0).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(latest) == 0 {
+		// nothing recorded yet, e.g. right after deployment: respond with the live matrix
+		return s.fullDelta(ctx, server)
+	}
+	until := int64(latest[0].Score)
+	if since >= until {
+		return nil, nil
+	}
+
+	current, err := s.getSnapshot(ctx, server, until)
+	if errors.Is(err, errDropMatrixSnapshotMissing) {
+		return s.fullDelta(ctx, server)
+	} else if err != nil {
+		return nil, err
+	}
+	delta := &modelv3.DropMatrixDelta{
+		Server:  server,
+		Until:   until,
+		Full:    true,
+		Matrix:  current.Matrix,
+		Removed: make([]*modelv3.DropMatrixElementKey, 0),
+	}
+
+	if since <= 0 {
+		return delta, nil
+	}
+	bases, err := s.Redis.ZRevRangeByScore(ctx, snapshotsKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(since, 10),
+		Count: 1,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(bases) == 0 {
+		return delta, nil
+	}
+	baseTime, err := strconv.ParseInt(bases[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	base, err := s.getSnapshot(ctx, server, baseTime)
+	if errors.Is(err, errDropMatrixSnapshotMissing) {
+		return delta, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	delta.Since = baseTime
+	delta.Full = false
+	delta.Matrix, delta.Removed = diffDropMatrix(base, current)
+	return delta, nil
+}
+
+func (s *DropMatrixDelta) fullDelta(ctx context.Context, server string) (*modelv3.DropMatrixDelta, error) {
+	matrix, err := s.DropMatrixService.GetShimDropMatrix(ctx, server, true, "", "", null.NewInt(0, false), constant.SourceCategoryAll)
+	if err != nil {
+		return nil, err
+	}
+	return &modelv3.DropMatrixDelta{
+		Server:  server,
+		Until:   time.Now().UnixMilli(),
+		Full:    true,
+		Matrix:  matrix.Matrix,
+		Removed: make([]*modelv3.DropMatrixElementKey, 0),
+	}, nil
+}
+
+// Cache: dropMatrixSnapshot#server|time:{server}|{time}, 10 min; snapshots never change once recorded
+func (s *DropMatrixDelta) getSnapshot(ctx context.Context, server string, t int64) (*modelv2.DropMatrixQueryResult, error) {
+	var snapshot modelv2.DropMatrixQueryResult
+	key := server + constant.CacheSep + strconv.FormatInt(t, 10)
+	_, err := cache.DropMatrixSnapshot.MutexGetSet(key, &snapshot, func() (*modelv2.DropMatrixQueryResult, error) {
+		b, err := s.Redis.Get(ctx, dropMatrixSnapshotKeyPrefix+server+":"+strconv.FormatInt(t, 10)).Bytes()
+		if errors.Is(err, redis.Nil) {
+			return nil, errDropMatrixSnapshotMissing
+		} else if err != nil {
+			return nil, err
+		}
+		return decodeDropMatrixSnapshot(b)
+	}, time.Minute*10)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// decodeDropMatrixSnapshot decodes the snapshot encoded by RecordSnapshot, or gzipped by the earlier versions of it
+func decodeDropMatrixSnapshot(b []byte) (*modelv2.DropMatrixQueryResult, error) {
+	if !bytes.HasPrefix(b, []byte{0x1f, 0x8b}) {
+		var result modelv2.DropMatrixQueryResult
+		if err := pkgcache.UnmarshalPayload(b, &result); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var result modelv2.DropMatrixQueryResult
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// diffDropMatrix returns the elements of current that are new or differ from the ones of base,
+// and the keys of the elements of base that are no longer present
+func diffDropMatrix(base, current *modelv2.DropMatrixQueryResult) (changed []*modelv2.OneDropMatrixElement, removed []*modelv3.DropMatrixElementKey) {
+	baseByKey := make(map[modelv3.DropMatrixElementKey]*modelv2.OneDropMatrixElement, len(base.Matrix))
+	for _, el := range base.Matrix {
+		baseByKey[modelv3.DropMatrixElementKey{StageID: el.StageID, ItemID: el.ItemID}] = el
+	}
+
+	changed = make([]*modelv2.OneDropMatrixElement, 0)
+	for _, el := range current.Matrix {
+		key := modelv3.DropMatrixElementKey{StageID: el.StageID, ItemID: el.ItemID}
+		if prev, ok := baseByKey[key]; !ok || *prev != *el {
+			changed = append(changed, el)
+		}
+		delete(baseByKey, key)
+	}
+
+	removed = make([]*modelv3.DropMatrixElementKey, 0, len(baseByKey))
+	for _, el := range base.Matrix {
+		key := modelv3.DropMatrixElementKey{StageID: el.StageID, ItemID: el.ItemID}
+		if _, ok := baseByKey[key]; ok {
+			removed = append(removed, &key)
+		}
+	}
+	return changed, removed
+}
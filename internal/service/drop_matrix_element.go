@@ -18,8 +18,10 @@ func NewDropMatrixElement(dropMatrixElementRepo *repo.DropMatrixElement) *DropMa
 	}
 }
 
-func (s *DropMatrixElement) BatchSaveElements(ctx context.Context, elements []*model.DropMatrixElement, server string) error {
-	return s.DropMatrixElementRepo.BatchSaveElements(ctx, elements, server)
+// BatchSaveElements atomically replaces the elements of the day of the server with the given ones, only those of
+// stageIds unless it is empty
+func (s *DropMatrixElement) BatchSaveElements(ctx context.Context, elements []*model.DropMatrixElement, server string, dayNum int, stageIds []int) error {
+	return s.DropMatrixElementRepo.BatchSaveElements(ctx, elements, server, dayNum, stageIds)
 }
 
 func (s *DropMatrixElement) DeleteByServerAndDayNum(ctx context.Context, server string, dayNum int) error {
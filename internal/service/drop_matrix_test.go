@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	"exusiai.dev/backend-next/internal/repo/mocks"
+)
+
+func TestDropMatrixMaxAccumulableMerging(t *testing.T) {
+	cache.Initialize()
+
+	timeRangeRepo := mocks.NewTimeRangeRepo(t)
+	dropInfoRepo := mocks.NewDropInfoRepo(t)
+	s := &DropMatrix{TimeRangeService: &TimeRange{TimeRangeRepo: timeRangeRepo, DropInfoRepo: dropInfoRepo}}
+
+	// the server is only used by this test, so that the cached time ranges of other tests do not get in
+	const server = "KR"
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.AddDate(0, 1, 0)
+	t2 := t1.AddDate(0, 1, 0)
+	t3 := t2.AddDate(0, 1, 0)
+	timeRangeRepo.On("GetTimeRangesByServer", mock.Anything, server).
+		Return([]*model.TimeRange{
+			{RangeID: 1, StartTime: &t0, EndTime: &t1, Server: server},
+			{RangeID: 2, StartTime: &t1, EndTime: &t2, Server: server},
+			{RangeID: 3, StartTime: &t2, EndTime: &t3, Server: server},
+		}, nil)
+	// the drops of range 1 are not accumulable with the later ones, which leaves ranges 2 and 3 to be merged
+	dropInfoRepo.On("GetDropInfosByServer", mock.Anything, server).
+		Return([]*model.DropInfo{
+			{StageID: 1, ItemID: null.IntFrom(10), RangeID: 1, Accumulable: false},
+			{StageID: 1, ItemID: null.IntFrom(10), RangeID: 2, Accumulable: true},
+			{StageID: 1, ItemID: null.IntFrom(10), RangeID: 3, Accumulable: true},
+		}, nil)
+
+	elements := []*model.DropMatrixElement{
+		{StageID: 1, ItemID: 10, RangeID: 1, Quantity: 100, Times: 100, QuantityBuckets: map[int]int{1: 100}},
+		{StageID: 1, ItemID: 10, RangeID: 2, Quantity: 10, Times: 20, QuantityBuckets: map[int]int{1: 10}},
+		{StageID: 1, ItemID: 10, RangeID: 3, Quantity: 30, Times: 40, QuantityBuckets: map[int]int{1: 30}},
+	}
+	result, err := s.convertDropMatrixElementsToMaxAccumulableDropMatrixQueryResult(context.Background(), server, elements)
+	if err != nil {
+		t.Fatalf("convertDropMatrixElementsToMaxAccumulableDropMatrixQueryResult returned error: %v", err)
+	}
+
+	if len(result.Matrix) != 1 {
+		t.Fatalf("expected a single merged element, got %d", len(result.Matrix))
+	}
+	got := result.Matrix[0]
+	if got.StageID != 1 || got.ItemID != 10 {
+		t.Errorf("expected stage 1 and item 10, got stage %d and item %d", got.StageID, got.ItemID)
+	}
+	if got.Quantity != 40 || got.Times != 60 {
+		t.Errorf("expected a quantity of 40 over 60 times, got %d over %d", got.Quantity, got.Times)
+	}
+	// 40 drops of 1 over 60 runs: sqrt(2/3 * 1/3)
+	if wantStdDev := math.Sqrt(2.0 / 9.0); math.Abs(got.StdDev-wantStdDev) > 0.01 {
+		t.Errorf("expected a standard deviation of about %f, got %f", wantStdDev, got.StdDev)
+	}
+	if got.TimeRange == nil || !got.TimeRange.StartTime.Equal(t1) || !got.TimeRange.EndTime.Equal(t3) {
+		t.Errorf("expected the merged time range to span %s to %s, got %+v", t1, t3, got.TimeRange)
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// dropMatrixViewsRefreshedAtRedisKey records when the last successful refresh of the views started
+const dropMatrixViewsRefreshedAtRedisKey = "dropMatrixViews:refreshedAt"
+
+// DropMatrixViews refreshes the materialized views of the drop matrix aggregates of every time range in the database
+// as a singleton background job. The views serve the time ranges which had ended by the time of their last refresh,
+// as no more reports are created within them; the changes to the reliability of their reports show up upon the next
+// refresh.
+type DropMatrixViews struct {
+	Config         *appconfig.Config
+	DropReportRepo *repo.DropReport
+	Redis          *redis.Client
+	Jobs           *Jobs
+}
+
+func NewDropMatrixViews(config *appconfig.Config, dropReportRepo *repo.DropReport, redis *redis.Client, jobs *Jobs) *DropMatrixViews {
+	s := &DropMatrixViews{
+		Config:         config,
+		DropReportRepo: dropReportRepo,
+		Redis:          redis,
+		Jobs:           jobs,
+	}
+	if s.enabled() {
+		jobs.Register(model.JobKindDropMatrixViews, JobPolicy{
+			MaxAttempts: 2,
+			Backoff:     time.Minute * 5,
+			Timeout:     time.Minute * 30,
+			Every:       config.DropMatrixViewsRefreshInterval,
+			Singleton:   true,
+		}, s.refresh)
+	}
+	return s
+}
+
+func (s *DropMatrixViews) enabled() bool {
+	return s.Config.DropMatrixViewsRefreshInterval > 0
+}
+
+func (s *DropMatrixViews) refresh(ctx context.Context, _ *model.Job) error {
+	// every report within the time ranges ended before the refresh starts is included by the refresh
+	startedAt := time.Now()
+	if err := s.DropReportRepo.RefreshDropMatrixViews(ctx); err != nil {
+		return err
+	}
+	return s.Redis.Set(ctx, dropMatrixViewsRefreshedAtRedisKey, startedAt.Format(time.RFC3339Nano), 0).Err()
+}
+
+// RequestRefresh enqueues a refresh of the views, unless the views are disabled
+func (s *DropMatrixViews) RequestRefresh(ctx context.Context) error {
+	if !s.enabled() {
+		return nil
+	}
+	_, _, err := s.Jobs.Enqueue(ctx, model.JobKindDropMatrixViews, "", "", nil)
+	return err
+}
+
+// RefreshedAt returns when the last refresh of the views started. It returns false when the views are disabled or
+// have never been refreshed, in which case no time range is served by them.
+func (s *DropMatrixViews) RefreshedAt(ctx context.Context) (time.Time, bool) {
+	if !s.enabled() {
+		return time.Time{}, false
+	}
+	v, err := s.Redis.Get(ctx, dropMatrixViewsRefreshedAtRedisKey).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Warn().Err(err).Msg("failed to get the last refresh time of the drop matrix views")
+		}
+		return time.Time{}, false
+	}
+	refreshedAt, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		log.Warn().Err(err).Str("value", v).Msg("invalid last refresh time of the drop matrix views")
+		return time.Time{}, false
+	}
+	return refreshedAt, true
+}
+
+// Covers tells whether the global drop matrix of the time range is served by the views refreshed at refreshedAt.
+// Customized time ranges, i.e. those not in the database, are never served.
+func (s *DropMatrixViews) Covers(timeRange *model.TimeRange, refreshedAt time.Time) bool {
+	return timeRange.RangeID != 0 && timeRange.EndTime != nil && !timeRange.EndTime.After(refreshedAt)
+}
@@ -36,6 +36,43 @@ func (s *DropPatternElement) GetDropPatternElementsByPatternId(ctx context.Conte
 	return dbDropPatternElements, nil
 }
 
+// GetDropPatternElementsByPatternIds looks up the elements of the patterns in batch: the patterns not cached yet
+// are fetched with a single query, and cached as GetDropPatternElementsByPatternId does.
+// Cache: dropPatternElements#patternId:{patternId}, 24hrs
+func (s *DropPatternElement) GetDropPatternElementsByPatternIds(ctx context.Context, patternIds []int) (map[int][]*model.DropPatternElement, error) {
+	elementsMap := make(map[int][]*model.DropPatternElement, len(patternIds))
+	missingPatternIds := make([]int, 0)
+	for _, patternId := range patternIds {
+		if _, ok := elementsMap[patternId]; ok {
+			continue
+		}
+		var dropPatternElements []*model.DropPatternElement
+		if err := cache.DropPatternElementsByPatternID.Get(strconv.Itoa(patternId), &dropPatternElements); err == nil {
+			elementsMap[patternId] = dropPatternElements
+		} else {
+			missingPatternIds = append(missingPatternIds, patternId)
+		}
+	}
+	if len(missingPatternIds) == 0 {
+		return elementsMap, nil
+	}
+
+	dbElementsMap, err := s.GetDropPatternElementsMapByPatternIds(ctx, missingPatternIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, patternId := range missingPatternIds {
+		dropPatternElements, ok := dbElementsMap[patternId]
+		if !ok {
+			// the pattern of no drops has no elements
+			dropPatternElements = make([]*model.DropPatternElement, 0)
+		}
+		elementsMap[patternId] = dropPatternElements
+		cache.DropPatternElementsByPatternID.Set(strconv.Itoa(patternId), dropPatternElements, 24*time.Hour)
+	}
+	return elementsMap, nil
+}
+
 func (s *DropPatternElement) GetDropPatternElementsMapByPatternIds(ctx context.Context, patternIds []int) (map[int][]*model.DropPatternElement, error) {
 	elements, err := s.DropPatternElementRepo.GetDropPatternElementsByPatternIds(ctx, patternIds)
 	if err != nil {
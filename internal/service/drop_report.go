@@ -13,7 +13,7 @@ import (
 )
 
 type DropReport struct {
-	DropReportRepo *repo.DropReport
+	DropReportRepo repo.DropReportRepo
 }
 
 func NewDropReport(dropReportRepo *repo.DropReport) *DropReport {
@@ -24,44 +24,40 @@ func NewDropReport(dropReportRepo *repo.DropReport) *DropReport {
 
 // DropMatrix
 
-func (s *DropReport) CalcQuantityUniqCount(
+func (s *DropReport) CalcDropMatrixAggregates(
 	ctx context.Context, queryCtx *model.DropReportQueryContext,
-) ([]*model.QuantityUniqCountResultForDropMatrix, error) {
-	results, err := s.DropReportRepo.CalcQuantityUniqCount(ctx, queryCtx)
+) ([]*model.CombinedResultForDropMatrix, error) {
+	return s.DropReportRepo.CalcDropMatrixAggregates(ctx, queryCtx)
+}
+
+func (s *DropReport) CalcDropMatrixAggregatesForRange(
+	ctx context.Context, server string, rangeId int, stageItemFilter *map[int][]int, sourceCategory string,
+) ([]*model.CombinedResultForDropMatrix, error) {
+	return s.DropReportRepo.CalcDropMatrixAggregatesForRange(ctx, server, rangeId, stageItemFilter, sourceCategory)
+}
+
+// CalcDropMatrixAggregatesFromRollups aggregates the drop matrix of the game days in-between start and end from the
+// daily rollups
+func (s *DropReport) CalcDropMatrixAggregatesFromRollups(
+	ctx context.Context, server string, start time.Time, end time.Time, stageItemFilter *map[int][]int, sourceCategory string,
+) ([]*model.CombinedResultForDropMatrix, error) {
+	return s.DropReportRepo.CalcDropMatrixAggregatesFromRollups(ctx, server, start, end, stageItemFilter, sourceCategory)
+}
+
+// CalcReportersForTimeRangeMapByStageId counts the distinct accounts having reported each of the stages within the
+// time range, keyed by stage
+func (s *DropReport) CalcReportersForTimeRangeMapByStageId(
+	ctx context.Context, server string, timeRange *model.TimeRange, stageIds []int, sourceCategory string,
+) (map[int]int, error) {
+	results, err := s.DropReportRepo.CalcReportersForTimeRange(ctx, server, timeRange, stageIds, sourceCategory)
 	if err != nil {
 		return nil, err
 	}
-	if queryCtx.StageItemFilter == nil {
-		return results, nil
-	}
-	// filter the results by stageIdItemId map, because in repo layer we only filter by stageId
-	filteredResults := make([]*model.QuantityUniqCountResultForDropMatrix, 0)
-	resultsMapByStageId := make(map[int][]*model.QuantityUniqCountResultForDropMatrix)
+	reporters := make(map[int]int, len(results))
 	for _, result := range results {
-		if _, ok := resultsMapByStageId[result.StageID]; !ok {
-			resultsMapByStageId[result.StageID] = make([]*model.QuantityUniqCountResultForDropMatrix, 0)
-		}
-		resultsMapByStageId[result.StageID] = append(resultsMapByStageId[result.StageID], result)
+		reporters[result.StageID] = result.Reporters
 	}
-	for stageId, stageResults := range resultsMapByStageId {
-		itemIdsSet := queryCtx.GetItemIdsSet(stageId)
-		if itemIdsSet == nil {
-			filteredResults = append(filteredResults, stageResults...)
-		} else {
-			for _, result := range stageResults {
-				if _, ok := itemIdsSet[result.ItemID]; ok {
-					filteredResults = append(filteredResults, result)
-				}
-			}
-		}
-	}
-	return filteredResults, nil
-}
-
-func (s *DropReport) CalcTotalTimesForDropMatrix(
-	ctx context.Context, queryCtx *model.DropReportQueryContext,
-) ([]*model.TotalTimesResult, error) {
-	return s.DropReportRepo.CalcTotalTimes(ctx, queryCtx)
+	return reporters, nil
 }
 
 // PatternMatrix
@@ -92,6 +88,18 @@ func (s *DropReport) CalcTotalTimesForTrend(
 	return s.DropReportRepo.CalcTotalTimesForTrend(ctx, server, startTime, intervalLength, intervalNum, stageIds, accountId, sourceCategory)
 }
 
+func (s *DropReport) CalcTotalQuantityForTrendFromRollups(
+	ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIdItemIdMap map[int][]int, sourceCategory string,
+) ([]*model.TotalQuantityResultForTrend, error) {
+	return s.DropReportRepo.CalcTotalQuantityForTrendFromRollups(ctx, server, startTime, intervalLength, intervalNum, stageIdItemIdMap, sourceCategory)
+}
+
+func (s *DropReport) CalcTotalTimesForTrendFromRollups(
+	ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIds []int, sourceCategory string,
+) ([]*model.TotalTimesResultForTrend, error) {
+	return s.DropReportRepo.CalcTotalTimesForTrendFromRollups(ctx, server, startTime, intervalLength, intervalNum, stageIds, sourceCategory)
+}
+
 // Sitestats
 
 func (s *DropReport) CalcTotalStageQuantityForShimSiteStats(ctx context.Context, server string, isRecent24h bool) ([]*modelv2.TotalStageTime, error) {
@@ -112,6 +120,18 @@ func (s *DropReport) GetDropReports(
 
 // Archive
 
+func (s *DropReport) CountDropReportsForArchive(ctx context.Context, date time.Time) (int, error) {
+	return s.DropReportRepo.CountDropReportsForArchive(ctx, date)
+}
+
+func (s *DropReport) GetReportIdRangeForArchive(ctx context.Context, date time.Time) (int, int, error) {
+	return s.DropReportRepo.GetReportIdRangeForArchive(ctx, date)
+}
+
+func (s *DropReport) GetOldestDropReportTime(ctx context.Context) (null.Time, error) {
+	return s.DropReportRepo.GetOldestDropReportTime(ctx)
+}
+
 func (s *DropReport) GetDropReportsForArchive(ctx context.Context, cursor *model.Cursor, date time.Time, limit int) ([]*model.DropReport, model.Cursor, error) {
 	return s.DropReportRepo.GetDropReportsForArchive(ctx, cursor, date, limit)
 }
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/pkg/gameday"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// DropReportDailyRollups keeps the daily rollups of the reports up to date, from which the global trends and the
+// recent drop matrices are served once DropReportDailyRollupsServing is on
+type DropReportDailyRollups struct {
+	Config                    *appconfig.Config
+	DropReportDailyRollupRepo *repo.DropReportDailyRollup
+	DropReportRepo            *repo.DropReport
+}
+
+func NewDropReportDailyRollups(config *appconfig.Config, dropReportDailyRollupRepo *repo.DropReportDailyRollup, dropReportRepo *repo.DropReport) *DropReportDailyRollups {
+	return &DropReportDailyRollups{
+		Config:                    config,
+		DropReportDailyRollupRepo: dropReportDailyRollupRepo,
+		DropReportRepo:            dropReportRepo,
+	}
+}
+
+// Enabled tells whether the rollups are maintained
+func (s *DropReportDailyRollups) Enabled() bool {
+	return s.Config.DropReportDailyRollupsEnabled
+}
+
+// Serving tells whether the global trends and the recent drop matrices are served from the rollups
+func (s *DropReportDailyRollups) Serving() bool {
+	return s.Enabled() && s.Config.DropReportDailyRollupsServing
+}
+
+// AddReports adds the reports created within tx to the rollups, unless the rollups are disabled
+func (s *DropReportDailyRollups) AddReports(ctx context.Context, tx bun.Tx, reports []*model.DropReport) error {
+	if !s.Enabled() || len(reports) == 0 {
+		return nil
+	}
+	return s.DropReportDailyRollupRepo.AddReports(ctx, tx, reports)
+}
+
+// RemoveReport removes the report recalled within tx from the rollups, unless the rollups are disabled
+func (s *DropReportDailyRollups) RemoveReport(ctx context.Context, tx bun.Tx, report *model.DropReport) error {
+	if !s.Enabled() {
+		return nil
+	}
+	return s.DropReportDailyRollupRepo.RemoveReport(ctx, tx, report)
+}
+
+// RebuildDate rebuilds the game days of the server starting within the UTC date. The days starting before the oldest
+// report left in the database are skipped, as their reports may have been purged after being archived.
+func (s *DropReportDailyRollups) RebuildDate(ctx context.Context, server string, date time.Time) error {
+	oldest, err := s.DropReportRepo.GetOldestDropReportTime(ctx)
+	if err != nil || !oldest.Valid {
+		return err
+	}
+
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour * 24)
+	for dayStart := gameday.StartTime(server, start); dayStart.Before(end); dayStart = gameday.NextStartTime(server, dayStart) {
+		if dayStart.Before(start) || dayStart.Before(oldest.Time) {
+			continue
+		}
+		if err := s.DropReportDailyRollupRepo.RebuildDay(ctx, server, dayStart); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/pkg/pgpool"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// DropReportPartitionsAhead is the number of months after the current one of which the partitions of drop_reports
+// are created in advance, so that the reports always have a partition to go to
+const DropReportPartitionsAhead = 2
+
+// DropReportPartitions maintains the monthly partitions of drop_reports as a daily singleton background job. It does
+// nothing until drop_reports has been partitioned by the partition_drop_reports script.
+type DropReportPartitions struct {
+	Config                  *appconfig.Config
+	Pools                   *pgpool.Pools
+	DropReportPartitionRepo *repo.DropReportPartition
+	ArchiveService          *Archive
+}
+
+func NewDropReportPartitions(config *appconfig.Config, pools *pgpool.Pools, dropReportPartitionRepo *repo.DropReportPartition, archiveService *Archive, jobs *Jobs) *DropReportPartitions {
+	s := &DropReportPartitions{
+		Config:                  config,
+		Pools:                   pools,
+		DropReportPartitionRepo: dropReportPartitionRepo,
+		ArchiveService:          archiveService,
+	}
+	jobs.Register(model.JobKindDropReportPartitions, JobPolicy{
+		MaxAttempts: 3,
+		Backoff:     time.Minute * 10,
+		Timeout:     time.Minute * 30,
+		Every:       time.Hour * 24,
+		Singleton:   true,
+	}, s.maintain)
+	return s
+}
+
+func (s *DropReportPartitions) maintain(ctx context.Context, _ *model.Job) error {
+	partitioned, err := s.DropReportPartitionRepo.IsPartitioned(ctx)
+	if err != nil {
+		return err
+	}
+	if !partitioned {
+		log.Debug().Str("evt.name", "partitions.skipped").Msg("drop_reports is not partitioned, skipping")
+		return nil
+	}
+
+	now := time.Now()
+	if err := s.DropReportPartitionRepo.CreatePartitions(ctx, s.Pools.DB(ctx), now, now.AddDate(0, DropReportPartitionsAhead, 0), constant.Servers); err != nil {
+		return err
+	}
+
+	if s.Config.DropReportPartitionPruneEnabled {
+		return s.prune(ctx)
+	}
+	return nil
+}
+
+// prune drops the partitions of which every day has been archived. The days are those of the archives, which start
+// at the day start of the CN server and therefore straddle the months in UTC.
+func (s *DropReportPartitions) prune(ctx context.Context) error {
+	months, err := s.DropReportPartitionRepo.GetPartitionMonths(ctx)
+	if err != nil {
+		return err
+	}
+	loc := constant.LocMap["CN"]
+	for _, month := range months {
+		first, last := archiveDate(month.In(loc)), archiveDate(month.AddDate(0, 1, 0).Add(-time.Nanosecond).In(loc))
+		archived := true
+		for day := first; !day.After(last) && archived; day = day.AddDate(0, 0, 1) {
+			if archived, err = s.ArchiveService.IsArchived(ctx, day); err != nil {
+				return err
+			}
+		}
+		if !archived {
+			// the partitions are archived in chronological order, hence none of the later ones has been either
+			break
+		}
+
+		if err := s.DropReportPartitionRepo.DropPartition(ctx, month); err != nil {
+			return err
+		}
+		log.Info().
+			Str("evt.name", "partitions.pruned").
+			Str("partition", repo.DropReportPartitionName(month)).
+			Msg("dropped archived partition of drop_reports")
+	}
+	return nil
+}
+
+// archiveDate returns the date of the archive covering the time in the CN server, as parsed from the archive jobs
+func archiveDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// dropReportTaskRetention is how long the claims of the report tasks are kept for, way past any redelivery of them
+const dropReportTaskRetention = time.Hour * 24 * 7
+
+// DropReportTask prunes the claims of the report tasks, by which the report worker persists each task only once
+type DropReportTask struct {
+	DropReportTaskRepo *repo.DropReportTask
+}
+
+func NewDropReportTask(dropReportTaskRepo *repo.DropReportTask, jobs *Jobs) *DropReportTask {
+	s := &DropReportTask{
+		DropReportTaskRepo: dropReportTaskRepo,
+	}
+	jobs.Register(model.JobKindDropReportTaskPrune, JobPolicy{
+		MaxAttempts: 2,
+		Backoff:     time.Minute * 10,
+		Timeout:     time.Minute * 10,
+		Every:       time.Hour * 24,
+		Singleton:   true,
+	}, s.prune)
+	return s
+}
+
+func (s *DropReportTask) prune(ctx context.Context, _ *model.Job) error {
+	deleted, err := s.DropReportTaskRepo.DeleteDropReportTasksBefore(ctx, time.Now().Add(-dropReportTaskRetention))
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("evt.name", "dropreporttask.pruned").
+		Int64("deleted", deleted).
+		Msg("pruned claims of report tasks")
+	return nil
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/tidwall/sjson"
+	"github.com/uptrace/bun"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+type Event struct {
+	DB            *bun.DB
+	EventRepo     *repo.Event
+	AdminRepo     *repo.Admin
+	TimeRangeRepo *repo.TimeRange
+	ZoneService   *Zone
+	StageService  *Stage
+}
+
+func NewEvent(db *bun.DB, eventRepo *repo.Event, adminRepo *repo.Admin, timeRangeRepo *repo.TimeRange, zoneService *Zone, stageService *Stage) *Event {
+	return &Event{
+		DB:            db,
+		EventRepo:     eventRepo,
+		AdminRepo:     adminRepo,
+		TimeRangeRepo: timeRangeRepo,
+		ZoneService:   zoneService,
+		StageService:  stageService,
+	}
+}
+
+func (s *Event) GetEvents(ctx context.Context) ([]*model.Event, error) {
+	return s.EventRepo.GetEvents(ctx)
+}
+
+// GetEventListings lists the events as scheduled in each server, optionally only those of the server and the status
+func (s *Event) GetEventListings(ctx context.Context, server string, status string) ([]*modelv3.EventListing, error) {
+	events, err := s.EventRepo.GetEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	zones, err := s.ZoneService.GetZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	arkZoneIds := make(map[int]string, len(zones))
+	for _, zone := range zones {
+		arkZoneIds[zone.ZoneID] = zone.ArkZoneID
+	}
+
+	now := time.Now()
+	listings := make([]*modelv3.EventListing, 0, len(events))
+	for _, event := range events {
+		eventZones := make([]string, 0, len(event.ZoneIDs))
+		for _, zoneId := range event.ZoneIDs {
+			if arkZoneId, ok := arkZoneIds[zoneId]; ok {
+				eventZones = append(eventZones, arkZoneId)
+			}
+		}
+
+		for _, schedule := range event.Schedules {
+			if server != "" && schedule.Server != server {
+				continue
+			}
+			scheduleStatus := schedule.Status(now)
+			if status != "" && scheduleStatus != status {
+				continue
+			}
+			listings = append(listings, &modelv3.EventListing{
+				EventID:   event.EventID,
+				Name:      event.Name,
+				Zones:     eventZones,
+				Server:    schedule.Server,
+				Status:    scheduleStatus,
+				OpenTime:  schedule.OpenTime,
+				CloseTime: schedule.CloseTime,
+				RangeID:   schedule.RangeID,
+			})
+		}
+	}
+	return listings, nil
+}
+
+// SaveEvent creates the event when eventId is 0, or replaces the event of eventId otherwise. For every scheduled
+// server, the time range of the event is created or moved to the schedule, and the zones of the event and their
+// stages are marked to exist in the server from the open time to the close time.
+func (s *Event) SaveEvent(ctx context.Context, eventId int, req *types.SaveEventRequest) (*model.Event, error) {
+	if violations := validateSaveEvent(req); len(violations) > 0 {
+		return nil, pgerr.NewInvalidViolations(violations)
+	}
+
+	event := &model.Event{EventID: eventId, Name: req.Name}
+	previousRangeIds := make(map[string]null.Int)
+	if eventId != 0 {
+		previous, err := s.EventRepo.GetEventById(ctx, eventId)
+		if err != nil {
+			return nil, err
+		}
+		for _, schedule := range previous.Schedules {
+			previousRangeIds[schedule.Server] = schedule.RangeID
+		}
+	}
+
+	zones := make([]*model.Zone, 0, len(req.ArkZoneIDs))
+	for _, arkZoneId := range req.ArkZoneIDs {
+		zone, err := s.ZoneService.GetZoneByArkId(ctx, arkZoneId)
+		if errors.Is(err, pgerr.ErrNotFound) {
+			return nil, pgerr.ErrInvalidReq.Msg("zone %s does not exist", arkZoneId)
+		} else if err != nil {
+			return nil, err
+		}
+		zones = append(zones, zone)
+		event.ZoneIDs = append(event.ZoneIDs, zone.ZoneID)
+	}
+	stages := make([]*model.Stage, 0)
+	for _, zone := range zones {
+		zoneStages, err := s.StageService.GetStagesByZoneId(ctx, zone.ZoneID)
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, zoneStages...)
+	}
+
+	err := s.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := s.EventRepo.SaveEvent(ctx, tx, event); err != nil {
+			return errors.Wrap(err, "failed to save event")
+		}
+
+		event.Schedules = make([]*model.EventSchedule, 0, len(req.Schedules))
+		for _, reqSchedule := range req.Schedules {
+			schedule := &model.EventSchedule{
+				EventID:   event.EventID,
+				Server:    reqSchedule.Server,
+				OpenTime:  reqSchedule.OpenTime,
+				CloseTime: reqSchedule.CloseTime,
+				RangeID:   previousRangeIds[reqSchedule.Server],
+			}
+
+			timeRange, err := s.deriveTimeRange(ctx, tx, event, schedule)
+			if err != nil {
+				return errors.Wrapf(err, "failed to derive time range of %s", schedule.Server)
+			}
+			schedule.RangeID = null.IntFrom(int64(timeRange.RangeID))
+			event.Schedules = append(event.Schedules, schedule)
+
+			for _, zone := range zones {
+				if zone.Existence, err = existenceOfSchedule(zone.Existence, schedule); err != nil {
+					return err
+				}
+			}
+			for _, stage := range stages {
+				if stage.Existence, err = existenceOfSchedule(stage.Existence, schedule); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := s.EventRepo.ReplaceEventSchedules(ctx, tx, event.EventID, event.Schedules); err != nil {
+			return errors.Wrap(err, "failed to save event schedules")
+		}
+		if err := s.AdminRepo.SaveZones(ctx, tx, &zones); err != nil {
+			return errors.Wrap(err, "failed to save zones")
+		}
+		if len(stages) > 0 {
+			if err := s.AdminRepo.SaveStages(ctx, tx, &stages); err != nil {
+				return errors.Wrap(err, "failed to save stages")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, pgerr.ErrInvalidReq.Msg("failed to save event: %s", err.Error())
+	}
+
+	servers := make([]string, 0, len(event.Schedules))
+	for _, schedule := range event.Schedules {
+		servers = append(servers, schedule.Server)
+	}
+	purgeEventMetadataCaches(servers)
+
+	log.Info().
+		Str("evt.name", "admin.event.saved").
+		Int("eventId", event.EventID).
+		Ints("zoneIds", event.ZoneIDs).
+		Strs("servers", servers).
+		Msg("event saved")
+
+	return event, nil
+}
+
+// deriveTimeRange creates the time range of the schedule, or moves the one previously derived from it
+func (s *Event) deriveTimeRange(ctx context.Context, tx bun.Tx, event *model.Event, schedule *model.EventSchedule) (*model.TimeRange, error) {
+	timeRange := &model.TimeRange{}
+	if schedule.RangeID.Valid {
+		var err error
+		timeRange, err = s.TimeRangeRepo.GetTimeRangeById(ctx, int(schedule.RangeID.Int64))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	startTime := schedule.OpenTime
+	endTime := time.UnixMilli(constant.FakeEndTimeMilli)
+	if schedule.CloseTime != nil {
+		endTime = *schedule.CloseTime
+	}
+	timeRange.Name = null.StringFrom(event.Name)
+	timeRange.StartTime = &startTime
+	timeRange.EndTime = &endTime
+	timeRange.Server = schedule.Server
+	timeRange.Comment = null.StringFrom(fmt.Sprintf("derived from event #%d", event.EventID))
+
+	timeRanges := []*model.TimeRange{timeRange}
+	if err := s.AdminRepo.SaveTimeRanges(ctx, tx, &timeRanges); err != nil {
+		return nil, err
+	}
+	return timeRange, nil
+}
+
+func existenceOfSchedule(existence []byte, schedule *model.EventSchedule) ([]byte, error) {
+	openTime := schedule.OpenTime.UnixMilli()
+	serverExistence := types.ServerExistence{Exist: true, OpenTime: &openTime}
+	if schedule.CloseTime != nil {
+		closeTime := schedule.CloseTime.UnixMilli()
+		serverExistence.CloseTime = &closeTime
+	}
+	if len(existence) == 0 {
+		existence = []byte("{}")
+	}
+	return sjson.SetBytes(existence, schedule.Server, serverExistence)
+}
+
+func validateSaveEvent(req *types.SaveEventRequest) []types.OnboardEventViolation {
+	violations := make([]types.OnboardEventViolation, 0)
+	violate := func(field string, format string, args ...any) {
+		violations = append(violations, types.OnboardEventViolation{
+			Field:   field,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	zones := make(map[string]struct{}, len(req.ArkZoneIDs))
+	for i, arkZoneId := range req.ArkZoneIDs {
+		if _, ok := zones[arkZoneId]; ok {
+			violate(fmt.Sprintf("zoneIds[%d]", i), "duplicated zone %s", arkZoneId)
+		}
+		zones[arkZoneId] = struct{}{}
+	}
+
+	servers := make(map[string]struct{}, len(req.Schedules))
+	for i, schedule := range req.Schedules {
+		if _, ok := servers[schedule.Server]; ok {
+			violate(fmt.Sprintf("schedules[%d].server", i), "only one schedule per server is allowed, found another one for %s", schedule.Server)
+		}
+		servers[schedule.Server] = struct{}{}
+
+		if schedule.CloseTime != nil && !schedule.CloseTime.After(schedule.OpenTime) {
+			violate(fmt.Sprintf("schedules[%d].closeTime", i), "closeTime must be after openTime")
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+const (
+	// EventSummaryTopPatterns is the most drop patterns of each stage kept in the summaries of the events
+	EventSummaryTopPatterns = 5
+	// eventSummaryGracePeriod is how long after an event closes it is summarized, for the reports submitted right
+	// before its close to have been processed
+	eventSummaryGracePeriod = time.Hour
+	// eventSummaryCacheTTL is how long the summaries are cached for, which never change once created
+	eventSummaryCacheTTL = time.Hour * 24
+
+	// summarizedSincePropertyKey is the property of the close time from which on the events are summarized by the job,
+	// set upon its first run. The events closed before are left to the backfill script.
+	summarizedSincePropertyKey = "event_summaries_since"
+)
+
+// EventSummary freezes the final statistics of the events upon their close, so that the past events remain queryable
+// without keeping their reports
+type EventSummary struct {
+	EventRepo            *repo.Event
+	EventSummaryRepo     *repo.EventSummary
+	DropReportRepo       *repo.DropReport
+	PropertyRepo         *repo.Property
+	StageService         *Stage
+	DropMatrixService    *DropMatrix
+	PatternMatrixService *PatternMatrix
+	RetentionService     *Retention
+}
+
+func NewEventSummary(eventRepo *repo.Event, eventSummaryRepo *repo.EventSummary, dropReportRepo *repo.DropReport, propertyRepo *repo.Property, stageService *Stage, dropMatrixService *DropMatrix, patternMatrixService *PatternMatrix, retentionService *Retention, jobs *Jobs) *EventSummary {
+	s := &EventSummary{
+		EventRepo:            eventRepo,
+		EventSummaryRepo:     eventSummaryRepo,
+		DropReportRepo:       dropReportRepo,
+		PropertyRepo:         propertyRepo,
+		StageService:         stageService,
+		DropMatrixService:    dropMatrixService,
+		PatternMatrixService: patternMatrixService,
+		RetentionService:     retentionService,
+	}
+	jobs.Register(model.JobKindEventSummary, JobPolicy{
+		MaxAttempts: 2,
+		Backoff:     time.Minute,
+		Timeout:     time.Minute * 30,
+		Every:       time.Minute * 10,
+		Singleton:   true,
+	}, s.summarize)
+	return s
+}
+
+// GetEventSummary returns the summary of the past event in the server, which is not found until the event has been
+// summarized after its close
+// Cache: eventSummary#eventId|server:{eventId}|{server}, 24 hrs
+func (s *EventSummary) GetEventSummary(ctx context.Context, eventId int, server string) (*modelv3.EventSummary, error) {
+	key := strconv.Itoa(eventId) + "|" + server
+	var summary modelv3.EventSummary
+	_, err := cache.EventSummary.MutexGetSet(key, &summary, func() (*modelv3.EventSummary, error) {
+		eventSummary, err := s.EventSummaryRepo.GetEventSummary(ctx, eventId, server)
+		if errors.Is(err, pgerr.ErrNotFound) {
+			return nil, pgerr.ErrNotFound.Msg("event %d has not been summarized in %s; only the past events are", eventId, server)
+		} else if err != nil {
+			return nil, err
+		}
+
+		var decoded modelv3.EventSummary
+		if err := json.Unmarshal(eventSummary.Summary, &decoded); err != nil {
+			return nil, errors.Wrap(err, "failed to decode event summary")
+		}
+		return &decoded, nil
+	}, eventSummaryCacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// summarize freezes the summaries of the events closed for the grace period since the job first ran in every server
+// they have not been summarized in yet
+func (s *EventSummary) summarize(ctx context.Context, _ *model.Job) error {
+	since, err := s.getSummarizedSince(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = s.summarizeClosedBetween(ctx, since, time.Now().Add(-eventSummaryGracePeriod))
+	return err
+}
+
+// Backfill freezes the summaries of the events closed before the job first ran, which the job leaves out, and returns
+// how many have been created. The events with reports purged from the database are skipped.
+func (s *EventSummary) Backfill(ctx context.Context) (int, error) {
+	since, err := s.getSummarizedSince(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return s.summarizeClosedBetween(ctx, time.Time{}, since)
+}
+
+// summarizeClosedBetween summarizes the events closed within [start, end) that have not been summarized yet, logging
+// and skipping those failing to be
+func (s *EventSummary) summarizeClosedBetween(ctx context.Context, start, end time.Time) (int, error) {
+	events, err := s.EventRepo.GetEvents(ctx)
+	if err != nil {
+		return 0, err
+	}
+	keys, err := s.EventSummaryRepo.GetEventSummaryKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+	summarized := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		summarized[strconv.Itoa(key.EventID)+"|"+key.Server] = true
+	}
+	if err := s.RetentionService.refresh(ctx); err != nil {
+		return 0, err
+	}
+	purgedBefore, purged := s.RetentionService.PurgedBefore()
+
+	created := 0
+	for _, event := range events {
+		for _, schedule := range event.Schedules {
+			if schedule.Status(end) != model.EventStatusPast || schedule.CloseTime.Before(start) || summarized[strconv.Itoa(event.EventID)+"|"+schedule.Server] {
+				continue
+			}
+			if purged && schedule.OpenTime.Before(purgedBefore) {
+				log.Warn().
+					Str("evt.name", "job.event_summary.purged").
+					Int("eventId", event.EventID).
+					Str("server", schedule.Server).
+					Msg("event not summarized as its reports have been purged")
+				continue
+			}
+
+			summary, err := s.createEventSummary(ctx, event, schedule)
+			if err != nil {
+				log.Error().
+					Err(err).
+					Str("evt.name", "job.event_summary.failed").
+					Int("eventId", event.EventID).
+					Str("server", schedule.Server).
+					Msg("failed to summarize event")
+				continue
+			}
+			created++
+
+			log.Info().
+				Str("evt.name", "job.event_summary.created").
+				Int("eventId", event.EventID).
+				Str("server", schedule.Server).
+				Int("totalReports", summary.TotalReports).
+				Msg("event summarized")
+		}
+	}
+	return created, nil
+}
+
+func (s *EventSummary) createEventSummary(ctx context.Context, event *model.Event, schedule *model.EventSchedule) (*modelv3.EventSummary, error) {
+	summary, err := s.calcEventSummary(ctx, event, schedule)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(summary)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.EventSummaryRepo.CreateEventSummary(ctx, &model.EventSummary{
+		EventID: event.EventID,
+		Server:  schedule.Server,
+		Summary: encoded,
+	}); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// getSummarizedSince returns the close time from which on the events are summarized by the job, which is the time it
+// first ran at past the grace period
+func (s *EventSummary) getSummarizedSince(ctx context.Context) (time.Time, error) {
+	property, err := s.PropertyRepo.GetPropertyByKey(ctx, summarizedSincePropertyKey)
+	if errors.Is(err, pgerr.ErrNotFound) {
+		since := time.Now().Add(-eventSummaryGracePeriod).Truncate(time.Second)
+		if _, err := s.PropertyRepo.CreateProperty(ctx, summarizedSincePropertyKey, since.Format(time.RFC3339)); err != nil {
+			return time.Time{}, err
+		}
+		return since, nil
+	} else if err != nil {
+		return time.Time{}, err
+	}
+	since, err := time.Parse(time.RFC3339, property.Value)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid event summaries since property")
+	}
+	return since, nil
+}
+
+func (s *EventSummary) calcEventSummary(ctx context.Context, event *model.Event, schedule *model.EventSchedule) (*modelv3.EventSummary, error) {
+	summary := &modelv3.EventSummary{
+		EventID:     event.EventID,
+		Name:        event.Name,
+		Server:      schedule.Server,
+		OpenTime:    schedule.OpenTime,
+		CloseTime:   *schedule.CloseTime,
+		Matrix:      []*modelv2.OneDropMatrixElement{},
+		TopPatterns: []*modelv2.OnePatternMatrixElement{},
+	}
+
+	var stageIds []int
+	for _, zoneId := range event.ZoneIDs {
+		stages, err := s.StageService.GetStagesByZoneId(ctx, zoneId)
+		if errors.Is(err, pgerr.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for _, stage := range stages {
+			stageIds = append(stageIds, stage.StageID)
+		}
+	}
+	if len(stageIds) == 0 {
+		summary.GeneratedAt = time.Now()
+		return summary, nil
+	}
+
+	timeRange := &model.TimeRange{StartTime: &summary.OpenTime, EndTime: &summary.CloseTime, Server: schedule.Server}
+	totalReports, err := s.DropReportRepo.CountAcceptedReports(ctx, schedule.Server, stageIds, timeRange.StartTime, timeRange.EndTime)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count reports")
+	}
+	matrix, err := s.DropMatrixService.GetShimCustomizedDropMatrixResults(ctx, schedule.Server, timeRange, stageIds, nil, null.Int{}, constant.SourceCategoryAll)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to calculate drop matrix")
+	}
+	patternMatrix, err := s.PatternMatrixService.GetShimCustomizedPatternMatrixResults(ctx, schedule.Server, timeRange, stageIds, null.Int{}, constant.SourceCategoryAll)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to calculate pattern matrix")
+	}
+
+	summary.TotalReports = totalReports
+	if matrix.Matrix != nil {
+		summary.Matrix = matrix.Matrix
+	}
+	summary.TopPatterns = topPatterns(patternMatrix.PatternMatrix, EventSummaryTopPatterns)
+	summary.GeneratedAt = time.Now()
+	return summary, nil
+}
+
+// topPatterns keeps the limit most reported patterns of each stage, ordered by stage and then by how often they have
+// been reported
+func topPatterns(elements []*modelv2.OnePatternMatrixElement, limit int) []*modelv2.OnePatternMatrixElement {
+	sorted := make([]*modelv2.OnePatternMatrixElement, len(elements))
+	copy(sorted, elements)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].StageID != sorted[j].StageID {
+			return sorted[i].StageID < sorted[j].StageID
+		}
+		return sorted[i].Quantity > sorted[j].Quantity
+	})
+
+	results := make([]*modelv2.OnePatternMatrixElement, 0, len(sorted))
+	kept := 0
+	for i, element := range sorted {
+		if i > 0 && element.StageID != sorted[i-1].StageID {
+			kept = 0
+		}
+		if kept < limit {
+			results = append(results, element)
+			kept++
+		}
+	}
+	return results
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+)
+
+func TestTopPatterns(t *testing.T) {
+	element := func(stageId string, quantity int) *modelv2.OnePatternMatrixElement {
+		return &modelv2.OnePatternMatrixElement{StageID: stageId, Times: 100, Quantity: quantity}
+	}
+	elements := []*modelv2.OnePatternMatrixElement{
+		element("act1_02", 10),
+		element("act1_01", 5),
+		element("act1_01", 40),
+		element("act1_02", 30),
+		element("act1_01", 20),
+		element("act1_01", 20),
+	}
+
+	got := topPatterns(elements, 2)
+	want := []struct {
+		stageId  string
+		quantity int
+	}{
+		{"act1_01", 40},
+		{"act1_01", 20},
+		{"act1_02", 30},
+		{"act1_02", 10},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("kept %d patterns, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].StageID != w.stageId || got[i].Quantity != w.quantity {
+			t.Errorf("pattern %d = %s %d, want %s %d", i, got[i].StageID, got[i].Quantity, w.stageId, w.quantity)
+		}
+	}
+	if elements[0].StageID != "act1_02" {
+		t.Errorf("topPatterns reordered the elements given")
+	}
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// ExclusionRule manages the rules excluding subsets of the accepted reports from the aggregations post hoc. Every
+// change of the rules recalculates the aggregations of the reports of which the exclusion may have changed.
+type ExclusionRule struct {
+	ExclusionRuleRepo *repo.ExclusionRule
+	AccountRepo       *repo.Account
+	StageService      *Stage
+	BackfillService   *Backfill
+}
+
+func NewExclusionRule(exclusionRuleRepo *repo.ExclusionRule, accountRepo *repo.Account, stageService *Stage, backfillService *Backfill, jobs *Jobs) *ExclusionRule {
+	s := &ExclusionRule{
+		ExclusionRuleRepo: exclusionRuleRepo,
+		AccountRepo:       accountRepo,
+		StageService:      stageService,
+		BackfillService:   backfillService,
+	}
+	jobs.Register(model.JobKindExclusionRuleExpiry, JobPolicy{
+		MaxAttempts: 2,
+		Backoff:     time.Minute,
+		Timeout:     time.Minute * 10,
+		Every:       time.Minute * 10,
+		Singleton:   true,
+	}, s.expire)
+	return s
+}
+
+type ExclusionRuleResult struct {
+	Rule *model.ExclusionRule `json:"rule"`
+	// Reports are the accepted reports of which the exclusion may have changed, per server and stage
+	Reports []*model.ReliabilityChangeResult `json:"reports"`
+	// RefreshJobs recalculate the aggregations of the stages and the days of the reports
+	RefreshJobs []*model.RefreshJob `json:"refreshJobs"`
+}
+
+func (s *ExclusionRule) GetExclusionRules(ctx context.Context) ([]*model.ExclusionRule, error) {
+	return s.ExclusionRuleRepo.GetExclusionRules(ctx)
+}
+
+// SaveExclusionRule creates the rule when ruleId is 0, or replaces the rule of ruleId otherwise
+func (s *ExclusionRule) SaveExclusionRule(ctx context.Context, ruleId int, req *types.SaveExclusionRuleRequest) (*ExclusionRuleResult, error) {
+	rule, err := s.toExclusionRule(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	rule.RuleID = ruleId
+
+	return s.SaveRule(ctx, rule)
+}
+
+// SaveRule creates the rule when it has no ID yet, or replaces the rule of its ID otherwise, as validated already
+func (s *ExclusionRule) SaveRule(ctx context.Context, rule *model.ExclusionRule) (*ExclusionRuleResult, error) {
+	var (
+		before []*model.ReliabilityChangeResult
+		err    error
+	)
+	if rule.RuleID != 0 {
+		if before, err = s.countExcludedReports(ctx, rule.RuleID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.ExclusionRuleRepo.SaveExclusionRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	var after []*model.ReliabilityChangeResult
+	if rule.Enabled {
+		if after, err = s.ExclusionRuleRepo.CountMatchingReports(ctx, rule.RuleID); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ExclusionRuleResult{Rule: rule}
+	if err := s.refreshAggregations(ctx, result, append(before, after...)); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("evt.name", "admin.exclusion_rule.saved").
+		Int("ruleId", rule.RuleID).
+		Bool("enabled", rule.Enabled).
+		Msg("exclusion rule saved")
+
+	return result, nil
+}
+
+// expire disables the enabled rules of which the expiry has passed, including the reports excluded by them again
+func (s *ExclusionRule) expire(ctx context.Context, _ *model.Job) error {
+	rules, err := s.ExclusionRuleRepo.GetExpiredExclusionRules(ctx)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		rule.Enabled = false
+		if _, err := s.SaveRule(ctx, rule); err != nil {
+			return err
+		}
+
+		log.Info().
+			Str("evt.name", "admin.exclusion_rule.expired").
+			Int("ruleId", rule.RuleID).
+			Msg("exclusion rule disabled upon its expiry")
+	}
+	return nil
+}
+
+// DeleteExclusionRule deletes the rule, and includes the reports excluded by it again
+func (s *ExclusionRule) DeleteExclusionRule(ctx context.Context, ruleId int) (*ExclusionRuleResult, error) {
+	before, err := s.countExcludedReports(ctx, ruleId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ExclusionRuleRepo.DeleteExclusionRule(ctx, ruleId); err != nil {
+		return nil, err
+	}
+
+	result := &ExclusionRuleResult{}
+	if err := s.refreshAggregations(ctx, result, before); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("evt.name", "admin.exclusion_rule.deleted").
+		Int("ruleId", ruleId).
+		Msg("exclusion rule deleted")
+
+	return result, nil
+}
+
+// countExcludedReports counts the reports excluded by the rule as it is in the database, which is none when the rule
+// is disabled
+func (s *ExclusionRule) countExcludedReports(ctx context.Context, ruleId int) ([]*model.ReliabilityChangeResult, error) {
+	rule, err := s.ExclusionRuleRepo.GetExclusionRuleById(ctx, ruleId)
+	if err != nil {
+		return nil, err
+	}
+	if !rule.Enabled {
+		return nil, nil
+	}
+	return s.ExclusionRuleRepo.CountMatchingReports(ctx, ruleId)
+}
+
+// refreshAggregations merges the reports of which the exclusion may have changed per server and stage, and
+// recalculates the aggregations of them
+func (s *ExclusionRule) refreshAggregations(ctx context.Context, result *ExclusionRuleResult, changes []*model.ReliabilityChangeResult) error {
+	type serverStage struct {
+		server  string
+		stageId int
+	}
+	merged := make(map[serverStage]*model.ReliabilityChangeResult)
+	result.Reports = make([]*model.ReliabilityChangeResult, 0, len(changes))
+	for _, change := range changes {
+		key := serverStage{server: change.Server, stageId: change.StageID}
+		m, ok := merged[key]
+		if !ok {
+			m = &model.ReliabilityChangeResult{Server: change.Server, StageID: change.StageID, MinTime: change.MinTime, MaxTime: change.MaxTime}
+			merged[key] = m
+			result.Reports = append(result.Reports, m)
+		}
+		m.Count += change.Count
+		if change.MinTime.Before(m.MinTime) {
+			m.MinTime = change.MinTime
+		}
+		if change.MaxTime.After(m.MaxTime) {
+			m.MaxTime = change.MaxTime
+		}
+	}
+
+	var err error
+	result.RefreshJobs, err = s.BackfillService.Track(ctx, result.Reports)
+	return err
+}
+
+func (s *ExclusionRule) toExclusionRule(ctx context.Context, req *types.SaveExclusionRuleRequest) (*model.ExclusionRule, error) {
+	rule := &model.ExclusionRule{
+		Name:           req.Name,
+		Comment:        req.Comment,
+		Enabled:        req.Enabled,
+		Server:         req.Server,
+		SourceName:     req.SourceName,
+		VersionPattern: req.VersionPattern,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		ExpiresAt:      req.ExpiresAt,
+	}
+
+	if req.ArkStageID.Valid {
+		stage, err := s.StageService.GetStageByArkId(ctx, req.ArkStageID.String)
+		if errors.Is(err, pgerr.ErrNotFound) {
+			return nil, pgerr.ErrInvalidReq.Msg("stage %s does not exist", req.ArkStageID.String)
+		} else if err != nil {
+			return nil, err
+		}
+		rule.StageID = null.IntFrom(int64(stage.StageID))
+	}
+
+	if req.PenguinID.Valid {
+		account, err := s.AccountRepo.GetAccountByPenguinId(ctx, req.PenguinID.String)
+		if errors.Is(err, pgerr.ErrNotFound) {
+			return nil, pgerr.ErrInvalidReq.Msg("account %s does not exist", req.PenguinID.String)
+		} else if err != nil {
+			return nil, err
+		}
+		rule.AccountID = null.IntFrom(int64(account.AccountID))
+	}
+
+	if req.IPRange.Valid {
+		ipRange := req.IPRange.String
+		if !strings.Contains(ipRange, "/") {
+			ip := net.ParseIP(ipRange)
+			if ip == nil {
+				return nil, pgerr.ErrInvalidReq.Msg("invalid ipRange %s", ipRange)
+			}
+			if ip.To4() != nil {
+				ipRange += "/32"
+			} else {
+				ipRange += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(ipRange)
+		if err != nil {
+			return nil, pgerr.ErrInvalidReq.Msg("invalid ipRange %s", req.IPRange.String)
+		}
+		rule.IPRange = null.StringFrom(ipNet.String())
+	}
+
+	if !rule.AccountID.Valid && !rule.IPRange.Valid && !rule.SourceName.Valid && !rule.VersionPattern.Valid &&
+		!rule.StageID.Valid && rule.StartTime == nil && rule.EndTime == nil {
+		return nil, pgerr.ErrInvalidReq.Msg("at least one of penguinId, ipRange, sourceName, versionPattern, arkStageId, startTime and endTime is required")
+	}
+	if rule.StartTime != nil && rule.EndTime != nil && !rule.EndTime.After(*rule.StartTime) {
+		return nil, pgerr.ErrInvalidReq.Msg("endTime must be after startTime")
+	}
+
+	return rule, nil
+}
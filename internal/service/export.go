@@ -2,28 +2,42 @@ package service
 
 import (
 	"context"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"exusiai.dev/gommon/constant"
+	"github.com/samber/lo"
 	"gopkg.in/guregu/null.v3"
 
 	"exusiai.dev/backend-next/internal/model"
+	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+	"exusiai.dev/backend-next/internal/pkg/tabular"
+	"exusiai.dev/backend-next/internal/util"
 )
 
 type Export struct {
 	DropReportService         *DropReport
 	DropPatternElementService *DropPatternElement
 	ItemService               *Item
+	LocalizationService       *Localization
+	RetentionService          *Retention
 }
 
 func NewExport(
 	dropReportService *DropReport,
 	dropPatternElementService *DropPatternElement,
 	itemService *Item,
+	localizationService *Localization,
+	retentionService *Retention,
 ) *Export {
 	return &Export{
 		DropReportService:         dropReportService,
 		DropPatternElementService: dropPatternElementService,
 		ItemService:               itemService,
+		LocalizationService:       localizationService,
+		RetentionService:          retentionService,
 	}
 }
 
@@ -47,6 +61,23 @@ func (s *Export) ExportDropReportsAndPatterns(
 		return nil, err
 	}
 
+	// the reports of the days purged from the database are read back from the archives
+	purgedReports, err := s.RetentionService.ReadPurgedDropReports(ctx, startTime, endTime, func(report *model.DropReport) bool {
+		return matchesDropReportQuery(report, queryCtx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(purgedReports) > 0 && len(itemIds) > 0 {
+		purgedReports, err = s.filterDropReportsByItems(ctx, purgedReports, itemIds)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(purgedReports) > 0 {
+		dropReports = append(purgedReports, dropReports...)
+	}
+
 	patternIdsSet := make(map[int]struct{})
 	dropReportForExportList := make([]*model.DropReportForExport, 0)
 	for _, dropReport := range dropReports {
@@ -95,3 +126,141 @@ func (s *Export) ExportDropReportsAndPatterns(
 		DropPatterns: dropPatternsForExportList,
 	}, nil
 }
+
+// matchesDropReportQuery tells whether the report read back from the archives would have been returned by the query
+// on the database, except for the item filter which requires its pattern
+func matchesDropReportQuery(report *model.DropReport, queryCtx *model.DropReportQueryContext) bool {
+	if report.Server != queryCtx.Server {
+		return false
+	}
+	if _, ok := (*queryCtx.StageItemFilter)[report.StageID]; !ok {
+		return false
+	}
+	if queryCtx.AccountID.Valid {
+		if report.Reliability < 0 || report.AccountID != int(queryCtx.AccountID.Int64) {
+			return false
+		}
+	} else if report.Reliability != 0 {
+		return false
+	}
+	if queryCtx.Times.Valid && report.Times != int(queryCtx.Times.Int64) {
+		return false
+	}
+	manual := lo.Contains(constant.ManualSources, report.SourceName)
+	switch queryCtx.SourceCategory {
+	case constant.SourceCategoryManual:
+		return manual
+	case constant.SourceCategoryAutomated:
+		return !manual
+	}
+	return true
+}
+
+// filterDropReportsByItems keeps the reports whose pattern drops any of the items
+func (s *Export) filterDropReportsByItems(ctx context.Context, reports []*model.DropReport, itemIds []int) ([]*model.DropReport, error) {
+	patternIds := lo.Uniq(lo.Map(reports, func(report *model.DropReport, _ int) int { return report.PatternID }))
+	elementsMap, err := s.DropPatternElementService.GetDropPatternElementsByPatternIds(ctx, patternIds)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Filter(reports, func(report *model.DropReport, _ int) bool {
+		return lo.SomeBy(elementsMap[report.PatternID], func(el *model.DropPatternElement) bool {
+			return lo.Contains(itemIds, el.ItemID)
+		})
+	}), nil
+}
+
+// DropMatrixTable lays out a drop matrix as a table, with the stage codes and item names in the given language
+func (s *Export) DropMatrixTable(ctx context.Context, result *modelv2.DropMatrixQueryResult, lang string) (*tabular.Table, error) {
+	names, err := s.LocalizationService.GetNames(ctx, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &tabular.Table{
+		Name:   "Drop Matrix",
+		Header: []string{"Stage ID", "Stage", "Item ID", "Item", "Times", "Quantity", "Drop Rate", "Std Dev", "Start", "End"},
+		Rows:   make([][]any, 0, len(result.Matrix)),
+	}
+	for _, el := range result.Matrix {
+		table.Rows = append(table.Rows, []any{
+			el.StageID, names.Stage(el.StageID), el.ItemID, names.Item(el.ItemID),
+			el.Times, el.Quantity, util.DropRate(el.Quantity, el.Times), el.StdDev,
+			time.UnixMilli(el.StartTime), exportEndTime(el.EndTime),
+		})
+	}
+	return table, nil
+}
+
+// PatternMatrixTable lays out a pattern matrix as a table, one row per pattern, with the drops of the
+// pattern described as "name×quantity" joined by commas
+func (s *Export) PatternMatrixTable(ctx context.Context, result *modelv2.PatternMatrixQueryResult, lang string) (*tabular.Table, error) {
+	names, err := s.LocalizationService.GetNames(ctx, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &tabular.Table{
+		Name:   "Pattern Matrix",
+		Header: []string{"Stage ID", "Stage", "Pattern", "Times", "Quantity", "Rate", "Start", "End"},
+		Rows:   make([][]any, 0, len(result.PatternMatrix)),
+	}
+	for _, el := range result.PatternMatrix {
+		var drops []string
+		if el.Pattern != nil {
+			drops = make([]string, 0, len(el.Pattern.Drops))
+			for _, drop := range el.Pattern.Drops {
+				drops = append(drops, names.Item(drop.ItemID)+"×"+strconv.Itoa(drop.Quantity))
+			}
+		}
+		table.Rows = append(table.Rows, []any{
+			el.StageID, names.Stage(el.StageID), strings.Join(drops, ", "),
+			el.Times, el.Quantity, util.DropRate(el.Quantity, el.Times),
+			time.UnixMilli(el.StartTime), exportEndTime(el.EndTime),
+		})
+	}
+	return table, nil
+}
+
+// TrendTable lays out the trends as a table, one row per stage, item and day
+func (s *Export) TrendTable(ctx context.Context, result *modelv2.TrendQueryResult, lang string) (*tabular.Table, error) {
+	names, err := s.LocalizationService.GetNames(ctx, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &tabular.Table{
+		Name:   "Trends",
+		Header: []string{"Stage ID", "Stage", "Item ID", "Item", "Day", "Times", "Quantity"},
+	}
+	for stageId, stageTrend := range result.Trend {
+		start := time.UnixMilli(stageTrend.StartTime)
+		for itemId, itemTrend := range stageTrend.Results {
+			for i := range itemTrend.Times {
+				table.Rows = append(table.Rows, []any{
+					stageId, names.Stage(stageId), itemId, names.Item(itemId),
+					start.AddDate(0, 0, i), itemTrend.Times[i], itemTrend.Quantity[i],
+				})
+			}
+		}
+	}
+	sort.SliceStable(table.Rows, func(i, j int) bool {
+		a, b := table.Rows[i], table.Rows[j]
+		if a[0] != b[0] {
+			return a[0].(string) < b[0].(string)
+		}
+		if a[2] != b[2] {
+			return a[2].(string) < b[2].(string)
+		}
+		return a[4].(time.Time).Before(b[4].(time.Time))
+	})
+	return table, nil
+}
+
+func exportEndTime(end null.Int) *time.Time {
+	if !end.Valid {
+		return nil
+	}
+	t := time.UnixMilli(end.Int64)
+	return &t
+}
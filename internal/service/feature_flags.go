@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+	"sync/atomic"
+
+	"github.com/goccy/go-json"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/lo"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/observability"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+)
+
+// featureFlagsRedisKey is the hash of the overridden feature flags as JSON, shared by every instance
+const featureFlagsRedisKey = "featureflags"
+
+const (
+	// FlagRareDropWarnings warns the reporters about the items which have rarely been reported to drop from the stage
+	FlagRareDropWarnings = "report.rare_drop_warnings"
+	// FlagSparseFieldsets allows the fields of the result elements to be selected with the fields query param
+	FlagSparseFieldsets = "result.sparse_fieldsets"
+)
+
+type featureFlagSpec struct {
+	description string
+	fallback    model.FeatureFlagRollout
+}
+
+// FeatureFlags decide whether the changes being rolled out are enabled for a given server and subject.
+// Rollouts are kept in redis and picked up by every instance within the refresh interval; the defaults apply otherwise.
+type FeatureFlags struct {
+	Redis *redis.Client
+
+	specs map[string]featureFlagSpec
+	// overrides is the latest known map of the overridden rollouts
+	overrides atomic.Pointer[map[string]model.FeatureFlagRollout]
+}
+
+func NewFeatureFlags(conf *appconfig.Config, redisClient *redis.Client, lc fx.Lifecycle) *FeatureFlags {
+	s := &FeatureFlags{
+		Redis: redisClient,
+		specs: map[string]featureFlagSpec{
+			FlagRareDropWarnings: {
+				description: "warn about rarely dropped items upon v3 reports",
+				fallback:    model.FeatureFlagRollout{Enabled: true, Percentage: 100},
+			},
+			FlagSparseFieldsets: {
+				description: "select the fields of the result elements with the fields query param",
+				fallback:    model.FeatureFlagRollout{Enabled: true, Percentage: 100},
+			},
+		},
+	}
+	s.overrides.Store(&map[string]model.FeatureFlagRollout{})
+
+	watchEvery(lc, "featureflags", conf.FeatureFlagsRefreshInterval, s.refresh)
+	return s
+}
+
+func (s *FeatureFlags) refresh(ctx context.Context) error {
+	values, err := s.Redis.HGetAll(ctx, featureFlagsRedisKey).Result()
+	if err != nil {
+		return err
+	}
+	overrides := make(map[string]model.FeatureFlagRollout, len(values))
+	for key, value := range values {
+		var rollout model.FeatureFlagRollout
+		if err := json.Unmarshal([]byte(value), &rollout); err != nil {
+			// the default applies to the invalid overrides
+			continue
+		}
+		overrides[key] = rollout
+	}
+	s.overrides.Store(&overrides)
+	return nil
+}
+
+func (s *FeatureFlags) rollout(key string) (model.FeatureFlagRollout, bool) {
+	spec, ok := s.specs[key]
+	if !ok {
+		panic("unknown feature flag " + key)
+	}
+	if rollout, ok := (*s.overrides.Load())[key]; ok {
+		return rollout, true
+	}
+	return spec.fallback, false
+}
+
+// Enabled tells whether the flag is enabled for the server and subject. The subject, e.g. an account ID or an IP,
+// is hashed into a stable bucket so that the same subject consistently sees the same variant during a partial rollout.
+// An empty subject is only enabled on full rollouts.
+func (s *FeatureFlags) Enabled(key string, server string, subject string) bool {
+	rollout, _ := s.rollout(key)
+	enabled := rollout.Enabled &&
+		(len(rollout.Servers) == 0 || lo.Contains(rollout.Servers, server)) &&
+		(rollout.Percentage >= 100 || (subject != "" && featureFlagBucket(key, subject) < rollout.Percentage))
+
+	observability.FeatureFlagEvaluations.
+		WithLabelValues(key, server, lo.Ternary(enabled, "on", "off")).
+		Inc()
+	return enabled
+}
+
+// featureFlagBucket is the bucket, from 0 to 99, of the subject for the flag. The key is hashed along so that
+// the subjects in the first percents of a flag are not always the same ones for every flag.
+func featureFlagBucket(key string, subject string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key + ":" + subject))
+	return int(h.Sum32() % 100)
+}
+
+// GetFeatureFlags lists every feature flag along with its current rollout, as known by this instance
+func (s *FeatureFlags) GetFeatureFlags() []*model.FeatureFlag {
+	flags := make([]*model.FeatureFlag, 0, len(s.specs))
+	for key, spec := range s.specs {
+		rollout, overridden := s.rollout(key)
+		flags = append(flags, &model.FeatureFlag{
+			Key:                key,
+			Description:        spec.description,
+			FeatureFlagRollout: rollout,
+			Overridden:         overridden,
+		})
+	}
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].Key < flags[j].Key
+	})
+	return flags
+}
+
+// SetFeatureFlag overrides the rollout of a feature flag on every instance
+func (s *FeatureFlags) SetFeatureFlag(ctx context.Context, key string, req *types.SetFeatureFlagRequest) error {
+	if _, ok := s.specs[key]; !ok {
+		return pgerr.ErrNotFound.Msg("feature flag %s does not exist", key)
+	}
+	b, err := json.Marshal(&model.FeatureFlagRollout{
+		Enabled:    req.Enabled,
+		Servers:    req.Servers,
+		Percentage: req.Percentage,
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.Redis.HSet(ctx, featureFlagsRedisKey, key, b).Err(); err != nil {
+		return err
+	}
+	return s.refresh(ctx)
+}
+
+// ResetFeatureFlag restores the default rollout of a feature flag on every instance
+func (s *FeatureFlags) ResetFeatureFlag(ctx context.Context, key string) error {
+	if _, ok := s.specs[key]; !ok {
+		return pgerr.ErrNotFound.Msg("feature flag %s does not exist", key)
+	}
+	if err := s.Redis.HDel(ctx, featureFlagsRedisKey, key).Err(); err != nil {
+		return err
+	}
+	return s.refresh(ctx)
+}
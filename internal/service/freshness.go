@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/redis/go-redis/v9"
+
+	"exusiai.dev/backend-next/internal/model"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+)
+
+const (
+	// freshnessRefreshedRedisKey scores every server:realm by when it has last been refreshed, in unix milliseconds
+	freshnessRefreshedRedisKey = "freshness:refreshed"
+	// freshnessAcceptedRedisKey scores every server by when its last accepted report has been submitted, in unix
+	// milliseconds
+	freshnessAcceptedRedisKey = "freshness:accepted"
+)
+
+// FreshnessRealms are the aggregations of which the freshness is recorded
+var FreshnessRealms = []string{model.RefreshJobRealmMatrix, model.RefreshJobRealmTrend, model.RefreshJobRealmPattern}
+
+// Freshness records when the aggregations of every server have last been refreshed, and when the last report accepted
+// in every server has been submitted. The records only ever move forward, so that the instances recording them out of
+// order never set them back.
+type Freshness struct {
+	Redis *redis.Client
+}
+
+func NewFreshness(redisClient *redis.Client) *Freshness {
+	return &Freshness{
+		Redis: redisClient,
+	}
+}
+
+func freshnessRealmMember(server string, realm string) string {
+	return server + ":" + realm
+}
+
+// MarkRefreshed records that the realm of the server has been refreshed at the given time
+func (s *Freshness) MarkRefreshed(ctx context.Context, server string, realm string, at time.Time) error {
+	return s.Redis.ZAddGT(ctx, freshnessRefreshedRedisKey, redis.Z{
+		Score:  float64(at.UnixMilli()),
+		Member: freshnessRealmMember(server, realm),
+	}).Err()
+}
+
+// MarkReportAccepted records that a report submitted at the given time has been accepted in the server
+func (s *Freshness) MarkReportAccepted(ctx context.Context, server string, at time.Time) error {
+	return s.Redis.ZAddGT(ctx, freshnessAcceptedRedisKey, redis.Z{
+		Score:  float64(at.UnixMilli()),
+		Member: server,
+	}).Err()
+}
+
+func (s *Freshness) GetFreshness(ctx context.Context) (*modelv3.Freshness, error) {
+	pipe := s.Redis.Pipeline()
+	refreshedCmd := pipe.ZRangeWithScores(ctx, freshnessRefreshedRedisKey, 0, -1)
+	acceptedCmd := pipe.ZRangeWithScores(ctx, freshnessAcceptedRedisKey, 0, -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	refreshed := freshnessTimes(refreshedCmd.Val())
+	accepted := freshnessTimes(acceptedCmd.Val())
+	freshness := &modelv3.Freshness{
+		Realms:      make([]*modelv3.RealmFreshness, 0, len(constant.Servers)*len(FreshnessRealms)),
+		GeneratedAt: time.Now(),
+	}
+	for _, server := range constant.Servers {
+		for _, realm := range FreshnessRealms {
+			freshness.Realms = append(freshness.Realms, &modelv3.RealmFreshness{
+				Server:               server,
+				Realm:                realm,
+				LastRefreshedAt:      refreshed[freshnessRealmMember(server, realm)],
+				LastAcceptedReportAt: accepted[server],
+			})
+		}
+	}
+	return freshness, nil
+}
+
+func freshnessTimes(zs []redis.Z) map[string]*time.Time {
+	times := make(map[string]*time.Time, len(zs))
+	for _, z := range zs {
+		member, ok := z.Member.(string)
+		if !ok {
+			continue
+		}
+		t := time.UnixMilli(int64(z.Score))
+		times[member] = &t
+	}
+	return times
+}
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/oklog/ulid/v2"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+	"github.com/uptrace/bun"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/gamedata"
+	"exusiai.dev/backend-next/internal/pkg/cache"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+const (
+	gameDataChangeSetRedisPrefix = "gamedata-sync:changeset:"
+	gameDataChangeSetLifetime    = time.Hour * 24 * 7
+)
+
+var (
+	// gameDataSyncItemClassifyTypes are the item classify types that are tracked for new items
+	gameDataSyncItemClassifyTypes = map[string]struct{}{"MATERIAL": {}}
+	// gameDataSyncStageTypes are the stage types that are tracked for new stages
+	gameDataSyncStageTypes = map[string]struct{}{"MAIN": {}, "SUB": {}, "ACTIVITY": {}, "DAILY": {}, "CAMPAIGN": {}}
+)
+
+type GameDataSync struct {
+	Config       *appconfig.Config
+	DB           *bun.DB
+	Redis        *redis.Client
+	AdminRepo    *repo.Admin
+	ItemService  *Item
+	StageService *Stage
+	ZoneService  *Zone
+
+	MetadataSnapshotService *MetadataSnapshot
+}
+
+func NewGameDataSync(conf *appconfig.Config, db *bun.DB, redisClient *redis.Client, adminRepo *repo.Admin, itemService *Item, stageService *Stage, zoneService *Zone, metadataSnapshotService *MetadataSnapshot) *GameDataSync {
+	return &GameDataSync{
+		Config:       conf,
+		DB:           db,
+		Redis:        redisClient,
+		AdminRepo:    adminRepo,
+		ItemService:  itemService,
+		StageService: stageService,
+		ZoneService:  zoneService,
+
+		MetadataSnapshotService: metadataSnapshotService,
+	}
+}
+
+// FetchTables downloads item_table.json and stage_table.json from the configured GameDataSyncURL
+func (s *GameDataSync) FetchTables(ctx context.Context) (*gamedata.ItemTable, *gamedata.StageTable, error) {
+	var itemTable gamedata.ItemTable
+	if err := s.fetchTable(ctx, "item_table.json", &itemTable); err != nil {
+		return nil, nil, err
+	}
+	var stageTable gamedata.StageTable
+	if err := s.fetchTable(ctx, "stage_table.json", &stageTable); err != nil {
+		return nil, nil, err
+	}
+	return &itemTable, &stageTable, nil
+}
+
+func (s *GameDataSync) fetchTable(ctx context.Context, name string, dest any) error {
+	ctx, cancel := context.WithTimeout(ctx, s.Config.GameDataSyncTimeout)
+	defer cancel()
+
+	u := strings.TrimSuffix(s.Config.GameDataSyncURL, "/") + "/" + name
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch %s", name)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to fetch %s: unexpected status %d", name, resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", name)
+	}
+	return json.Unmarshal(body, dest)
+}
+
+// CreateChangeSet diffs the game data tables against our items and stages, and stores the
+// resulting change set for review. Nil tables are skipped.
+func (s *GameDataSync) CreateChangeSet(ctx context.Context, source string, itemTable *gamedata.ItemTable, stageTable *gamedata.StageTable) (*gamedata.ChangeSet, error) {
+	changeSet := &gamedata.ChangeSet{
+		ID:        strings.ToLower(ulid.Make().String()),
+		CreatedAt: time.Now(),
+		Source:    source,
+		Items:     make([]*gamedata.ItemChange, 0),
+		Stages:    make([]*gamedata.StageChange, 0),
+		Skipped:   make([]string, 0),
+	}
+
+	if itemTable != nil {
+		if err := s.diffItems(ctx, changeSet, itemTable); err != nil {
+			return nil, err
+		}
+	}
+	if stageTable != nil {
+		if err := s.diffStages(ctx, changeSet, stageTable); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.saveChangeSet(ctx, changeSet); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("evt.name", "gamedata.sync.changeset.created").
+		Str("changeSetId", changeSet.ID).
+		Int("items", len(changeSet.Items)).
+		Int("stages", len(changeSet.Stages)).
+		Msg("game data change set created")
+
+	return changeSet, nil
+}
+
+func (s *GameDataSync) diffItems(ctx context.Context, changeSet *gamedata.ChangeSet, itemTable *gamedata.ItemTable) error {
+	items, err := s.ItemService.GetItemsMapByArkId(ctx)
+	if err != nil {
+		return err
+	}
+
+	arkItemIds := lo.Keys(itemTable.Items)
+	sort.Strings(arkItemIds)
+	for _, arkItemId := range arkItemIds {
+		entry := itemTable.Items[arkItemId]
+		item, ok := items[arkItemId]
+		if !ok {
+			if _, tracked := gameDataSyncItemClassifyTypes[entry.ClassifyType]; tracked {
+				changeSet.Items = append(changeSet.Items, &gamedata.ItemChange{
+					Action:    gamedata.ChangeActionCreate,
+					ArkItemID: arkItemId,
+					Entry:     entry,
+				})
+			}
+			continue
+		}
+
+		diff := map[string]gamedata.FieldDiff{}
+		if name := gjson.GetBytes(item.Name, "zh").String(); name != entry.Name {
+			diff["name.zh"] = gamedata.FieldDiff{From: name, To: entry.Name}
+		}
+		if item.Rarity != int(entry.Rarity) {
+			diff["rarity"] = gamedata.FieldDiff{From: item.Rarity, To: int(entry.Rarity)}
+		}
+		if len(diff) > 0 {
+			changeSet.Items = append(changeSet.Items, &gamedata.ItemChange{
+				Action:    gamedata.ChangeActionUpdate,
+				ArkItemID: arkItemId,
+				Entry:     entry,
+				Diff:      diff,
+			})
+		}
+	}
+	return nil
+}
+
+func (s *GameDataSync) diffStages(ctx context.Context, changeSet *gamedata.ChangeSet, stageTable *gamedata.StageTable) error {
+	stages, err := s.StageService.GetStagesMapByArkId(ctx)
+	if err != nil {
+		return err
+	}
+	zones, err := s.ZoneService.GetZones(ctx)
+	if err != nil {
+		return err
+	}
+	zoneIds := make(map[string]struct{}, len(zones))
+	for _, zone := range zones {
+		zoneIds[zone.ArkZoneID] = struct{}{}
+	}
+
+	arkStageIds := lo.Keys(stageTable.Stages)
+	sort.Strings(arkStageIds)
+	for _, arkStageId := range arkStageIds {
+		entry := stageTable.Stages[arkStageId]
+		stage, ok := stages[arkStageId]
+		if !ok {
+			if _, tracked := gameDataSyncStageTypes[entry.StageType]; !tracked {
+				continue
+			}
+			if _, ok := zoneIds[entry.ZoneID]; !ok {
+				changeSet.Skipped = append(changeSet.Skipped, fmt.Sprintf("stage %s: zone %s does not exist", arkStageId, entry.ZoneID))
+				continue
+			}
+			changeSet.Stages = append(changeSet.Stages, &gamedata.StageChange{
+				Action:     gamedata.ChangeActionCreate,
+				ArkStageID: arkStageId,
+				Entry:      entry,
+			})
+			continue
+		}
+
+		diff := map[string]gamedata.FieldDiff{}
+		if code := gjson.GetBytes(stage.Code, "zh").String(); code != entry.Code {
+			diff["code.zh"] = gamedata.FieldDiff{From: code, To: entry.Code}
+		}
+		if !stage.Sanity.Valid || stage.Sanity.Int64 != int64(entry.APCost) {
+			diff["sanity"] = gamedata.FieldDiff{From: stage.Sanity, To: entry.APCost}
+		}
+		if len(diff) > 0 {
+			changeSet.Stages = append(changeSet.Stages, &gamedata.StageChange{
+				Action:     gamedata.ChangeActionUpdate,
+				ArkStageID: arkStageId,
+				Entry:      entry,
+				Diff:       diff,
+			})
+		}
+	}
+	return nil
+}
+
+func (s *GameDataSync) GetChangeSet(ctx context.Context, id string) (*gamedata.ChangeSet, error) {
+	b, err := s.Redis.Get(ctx, gameDataChangeSetRedisPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, pgerr.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var changeSet gamedata.ChangeSet
+	if err := cache.UnmarshalPayload(b, &changeSet); err != nil {
+		return nil, err
+	}
+	return &changeSet, nil
+}
+
+func (s *GameDataSync) saveChangeSet(ctx context.Context, changeSet *gamedata.ChangeSet) error {
+	b, err := cache.MarshalPayload(changeSet)
+	if err != nil {
+		return err
+	}
+	return s.Redis.Set(ctx, gameDataChangeSetRedisPrefix+changeSet.ID, b, gameDataChangeSetLifetime).Err()
+}
+
+// ApplyChangeSet applies a previously created change set in one transaction. Only the fields
+// tracked by the diff are touched: localized names other than zh are kept as-is.
+func (s *GameDataSync) ApplyChangeSet(ctx context.Context, id string) (*gamedata.ChangeSet, error) {
+	changeSet, err := s.GetChangeSet(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if changeSet.AppliedAt != nil {
+		return nil, pgerr.ErrInvalidReq.Msg("change set %s has already been applied", id)
+	}
+
+	items, err := s.buildItems(ctx, changeSet)
+	if err != nil {
+		return nil, err
+	}
+	stages, err := s.buildStages(ctx, changeSet)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.MetadataSnapshotService.Capture(ctx, "apply game data change set "+id); err != nil {
+		return nil, err
+	}
+
+	err = s.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if len(items) > 0 {
+			if err := s.AdminRepo.SaveItems(ctx, tx, &items); err != nil {
+				return errors.Wrap(err, "failed to save items")
+			}
+		}
+		if len(stages) > 0 {
+			if err := s.AdminRepo.SaveStages(ctx, tx, &stages); err != nil {
+				return errors.Wrap(err, "failed to save stages")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		s.ItemService.invalidateItemCaches(item.ArkItemID)
+	}
+	if len(stages) > 0 {
+		purgeEventMetadataCaches(nil)
+	}
+
+	now := time.Now()
+	changeSet.AppliedAt = &now
+	if err := s.saveChangeSet(ctx, changeSet); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("evt.name", "gamedata.sync.changeset.applied").
+		Str("changeSetId", changeSet.ID).
+		Int("items", len(items)).
+		Int("stages", len(stages)).
+		Msg("game data change set applied")
+
+	return changeSet, nil
+}
+
+func (s *GameDataSync) buildItems(ctx context.Context, changeSet *gamedata.ChangeSet) ([]*model.Item, error) {
+	existing, err := s.ItemService.GetItemsMapByArkId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*model.Item, 0, len(changeSet.Items))
+	for _, change := range changeSet.Items {
+		if change.Action == gamedata.ChangeActionCreate {
+			items = append(items, &model.Item{
+				ArkItemID: change.ArkItemID,
+				Type:      change.Entry.ClassifyType,
+				Name:      json.RawMessage(`{}`),
+				Existence: json.RawMessage(`{"CN":{"exist":true},"US":{"exist":false},"JP":{"exist":false},"KR":{"exist":false}}`),
+				SortID:    change.Entry.SortID,
+				Keywords:  json.RawMessage(`{}`),
+			})
+		} else {
+			item, ok := existing[change.ArkItemID]
+			if !ok {
+				return nil, pgerr.ErrInvalidReq.Msg("item %s no longer exists", change.ArkItemID)
+			}
+			copied := *item
+			items = append(items, &copied)
+		}
+
+		item := items[len(items)-1]
+		name, err := sjson.SetBytes(item.Name, "zh", change.Entry.Name)
+		if err != nil {
+			return nil, err
+		}
+		item.Name = name
+		item.Rarity = int(change.Entry.Rarity)
+	}
+	return items, nil
+}
+
+func (s *GameDataSync) buildStages(ctx context.Context, changeSet *gamedata.ChangeSet) ([]*model.Stage, error) {
+	existing, err := s.StageService.GetStagesMapByArkId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stages := make([]*model.Stage, 0, len(changeSet.Stages))
+	for _, change := range changeSet.Stages {
+		if change.Action == gamedata.ChangeActionCreate {
+			zone, err := s.ZoneService.GetZoneByArkId(ctx, change.Entry.ZoneID)
+			if err != nil {
+				return nil, err
+			}
+			stages = append(stages, &model.Stage{
+				ArkStageID: change.ArkStageID,
+				ZoneID:     zone.ZoneID,
+				StageType:  change.Entry.StageType,
+				Code:       json.RawMessage(`{}`),
+				Existence:  zone.Existence,
+			})
+		} else {
+			stage, ok := existing[change.ArkStageID]
+			if !ok {
+				return nil, pgerr.ErrInvalidReq.Msg("stage %s no longer exists", change.ArkStageID)
+			}
+			copied := *stage
+			stages = append(stages, &copied)
+		}
+
+		stage := stages[len(stages)-1]
+		code, err := sjson.SetBytes(stage.Code, "zh", change.Entry.Code)
+		if err != nil {
+			return nil, err
+		}
+		stage.Code = code
+		stage.Sanity = null.IntFrom(int64(change.Entry.APCost))
+	}
+	return stages, nil
+}
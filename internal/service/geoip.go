@@ -5,15 +5,36 @@ import (
 
 	"github.com/oschwald/geoip2-golang"
 	"github.com/pkg/errors"
+
+	"exusiai.dev/backend-next/internal/pkg/geoip"
 )
 
+// IPNetwork is what is known about the network an IP address belongs to
+type IPNetwork struct {
+	// CountryISOCode is the ISO 3166-1 code of the country of the address, empty if unknown
+	CountryISOCode string
+	// ASN is the number of the autonomous system announcing the address, 0 if unknown
+	ASN uint
+	// Organization is the organization the autonomous system is registered to, empty if unknown
+	Organization string
+}
+
+// IPLookup looks up the networks of the IP addresses, e.g. with the databases of MaxMind
+type IPLookup interface {
+	Lookup(ip net.IP) (*IPNetwork, error)
+}
+
 type GeoIP struct {
-	db *geoip2.Reader
+	db    *geoip2.Reader
+	asnDB *geoip2.Reader
 }
 
-func NewGeoIP(db *geoip2.Reader) *GeoIP {
+var _ IPLookup = (*GeoIP)(nil)
+
+func NewGeoIP(db *geoip2.Reader, asnDB *geoip.ASNDatabase) *GeoIP {
 	return &GeoIP{
-		db: db,
+		db:    db,
+		asnDB: asnDB.Reader,
 	}
 }
 
@@ -32,3 +53,22 @@ func (s *GeoIP) InChinaMainland(ip string) bool {
 	}
 	return country.Country.IsoCode == "CN"
 }
+
+// Lookup looks up the country of ip, along with its autonomous system when the ASN database is configured
+func (s *GeoIP) Lookup(ip net.IP) (*IPNetwork, error) {
+	country, err := s.db.Country(ip)
+	if err != nil {
+		return nil, err
+	}
+	network := &IPNetwork{CountryISOCode: country.Country.IsoCode}
+	if s.asnDB == nil {
+		return network, nil
+	}
+	asn, err := s.asnDB.ASN(ip)
+	if err != nil {
+		return nil, err
+	}
+	network.ASN = asn.AutonomousSystemNumber
+	network.Organization = asn.AutonomousSystemOrganization
+	return network, nil
+}
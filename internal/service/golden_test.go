@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	"exusiai.dev/backend-next/internal/pkg/goldentest"
+	"exusiai.dev/backend-next/internal/pkg/pgpool"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// TestGoldenAggregations calculates the drop matrix, the trend and the pattern matrix from the reports in
+// testdata/golden/fixture.json, and compares them with the golden files next to it. The fixture covers the unreliable
+// reports, the reports excluded by rules, the reports of more than one time and the reports of another server, as
+// well as the items with no drop at all. The trend is calculated from the daily rollups too, which are to agree with
+// the reports.
+func TestGoldenAggregations(t *testing.T) {
+	db := goldentest.Postgres(t)
+	goldentest.Schema(t, db, "testdata/golden/schema.sql")
+	goldentest.Seed(t, db, "testdata/golden/fixture.json",
+		&[]*model.TimeRange{},
+		&[]*model.DropInfo{},
+		&[]*model.DropPatternElement{},
+		&[]*model.DropReport{},
+		&[]*model.ExclusionRule{},
+	)
+
+	cache.Initialize()
+
+	dropInfoRepo := repo.NewDropInfo(db)
+	timeRangeService := &TimeRange{TimeRangeRepo: repo.NewTimeRange(db), DropInfoRepo: dropInfoRepo}
+	pools := &pgpool.Pools{Interactive: db, Background: db}
+	dropReportRepo := repo.NewDropReport(db, pools)
+	dropReportService := &DropReport{DropReportRepo: dropReportRepo}
+	dropInfoService := &DropInfo{DropInfoRepo: dropInfoRepo, TimeRangeService: timeRangeService}
+
+	ctx := context.Background()
+	const server = "CN"
+	timeRange, err := timeRangeService.GetTimeRangeById(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetTimeRangeById returned error: %v", err)
+	}
+
+	t.Run("DropMatrix", func(t *testing.T) {
+		// the views are disabled, so that the matrix is aggregated from the reports
+		s := &DropMatrix{
+			TimeRangeService:  timeRangeService,
+			DropReportService: dropReportService,
+			DropInfoService:   dropInfoService,
+			DropMatrixViews:   &DropMatrixViews{Config: &appconfig.Config{}},
+			DailyRollups:      &DropReportDailyRollups{Config: &appconfig.Config{}},
+		}
+		elements, err := s.calcDropMatrixForTimeRanges(ctx, server, []*model.TimeRange{timeRange}, nil, nil, null.Int{}, constant.SourceCategoryAll)
+		if err != nil {
+			t.Fatalf("calcDropMatrixForTimeRanges returned error: %v", err)
+		}
+		result, err := s.convertDropMatrixElementsToDropMatrixQueryResult(ctx, elements)
+		if err != nil {
+			t.Fatalf("convertDropMatrixElementsToDropMatrixQueryResult returned error: %v", err)
+		}
+		sort.Slice(result.Matrix, func(i, j int) bool {
+			a, b := result.Matrix[i], result.Matrix[j]
+			if a.StageID != b.StageID {
+				return a.StageID < b.StageID
+			}
+			return a.ItemID < b.ItemID
+		})
+		goldentest.Assert(t, "testdata/golden/drop_matrix.json", result)
+	})
+
+	assertTrend := func(t *testing.T, rollups *DropReportDailyRollups) {
+		s := &Trend{DropReportService: dropReportService, DropInfoService: dropInfoService, DailyRollups: rollups}
+		// 04:00 of 2026-01-02 in the CN server, when its game day starts
+		startTime := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+		elements, err := s.calcTrend(ctx, server, &startTime, 24*time.Hour, 3, nil, nil, null.Int{}, constant.SourceCategoryAll)
+		if err != nil {
+			t.Fatalf("calcTrend returned error: %v", err)
+		}
+		result, err := s.convertTrendElementsToTrendQueryResult(elements)
+		if err != nil {
+			t.Fatalf("convertTrendElementsToTrendQueryResult returned error: %v", err)
+		}
+		sort.Slice(result.Trends, func(i, j int) bool { return result.Trends[i].StageID < result.Trends[j].StageID })
+		for _, trend := range result.Trends {
+			results := trend.Results
+			sort.Slice(results, func(i, j int) bool { return results[i].ItemID < results[j].ItemID })
+		}
+		goldentest.Assert(t, "testdata/golden/trend.json", result)
+	}
+
+	t.Run("Trend", func(t *testing.T) {
+		assertTrend(t, &DropReportDailyRollups{Config: &appconfig.Config{}})
+	})
+
+	t.Run("TrendFromRollups", func(t *testing.T) {
+		rollups := &DropReportDailyRollups{
+			Config: &appconfig.Config{ConfigSpec: appconfig.ConfigSpec{
+				DropReportDailyRollupsEnabled: true,
+				DropReportDailyRollupsServing: true,
+			}},
+			DropReportDailyRollupRepo: repo.NewDropReportDailyRollup(pools),
+			DropReportRepo:            dropReportRepo,
+		}
+		for date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC); date.Day() <= 7; date = date.AddDate(0, 0, 1) {
+			if err := rollups.RebuildDate(ctx, server, date); err != nil {
+				t.Fatalf("RebuildDate returned error: %v", err)
+			}
+		}
+		assertTrend(t, rollups)
+	})
+
+	t.Run("PatternMatrix", func(t *testing.T) {
+		s := &PatternMatrix{
+			TimeRangeService:  timeRangeService,
+			DropReportService: dropReportService,
+			DropInfoService:   dropInfoService,
+		}
+		elements, err := s.calcPatternMatrixForTimeRanges(ctx, server, []*model.TimeRange{timeRange}, nil, null.Int{}, constant.SourceCategoryAll)
+		if err != nil {
+			t.Fatalf("calcPatternMatrixForTimeRanges returned error: %v", err)
+		}
+		result, err := s.convertPatternMatrixElementsToDropPatternQueryResult(ctx, server, elements)
+		if err != nil {
+			t.Fatalf("convertPatternMatrixElementsToDropPatternQueryResult returned error: %v", err)
+		}
+		sort.Slice(result.PatternMatrix, func(i, j int) bool {
+			a, b := result.PatternMatrix[i], result.PatternMatrix[j]
+			if a.StageID != b.StageID {
+				return a.StageID < b.StageID
+			}
+			return a.PatternID < b.PatternID
+		})
+		goldentest.Assert(t, "testdata/golden/pattern_matrix.json", result)
+	})
+}
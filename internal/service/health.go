@@ -2,11 +2,15 @@ package service
 
 import (
 	"context"
+	"sync/atomic"
 
 	"github.com/nats-io/nats.go"
 	"github.com/pkg/errors"
 	"github.com/redis/go-redis/v9"
 	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
 )
 
 var (
@@ -15,17 +19,35 @@ var (
 	ErrNATSNotReachable     = errors.New("nats not reachable")
 )
 
+const (
+	HealthCheckPostgres = "postgres"
+	HealthCheckRedis    = "redis"
+	HealthCheckNATS     = "nats"
+	HealthCheckS3       = "s3"
+	HealthCheckCache    = "cache"
+	HealthCheckShutdown = "shutdown"
+)
+
 type Health struct {
-	DB    *bun.DB
-	Redis *redis.Client
-	NATS  *nats.Conn
+	DB             *bun.DB
+	Redis          *redis.Client
+	NATS           *nats.Conn
+	Config         *appconfig.Config
+	ArchiveService *Archive
+
+	// cachesWarm tells whether the caches of the global results have been warmed up since startup
+	cachesWarm atomic.Bool
+	// draining tells whether the instance is shutting down and should no longer receive traffic
+	draining atomic.Bool
 }
 
-func NewHealth(db *bun.DB, redis *redis.Client, nats *nats.Conn) *Health {
+func NewHealth(db *bun.DB, redis *redis.Client, nats *nats.Conn, config *appconfig.Config, archiveService *Archive) *Health {
 	return &Health{
-		DB:    db,
-		Redis: redis,
-		NATS:  nats,
+		DB:             db,
+		Redis:          redis,
+		NATS:           nats,
+		Config:         config,
+		ArchiveService: archiveService,
 	}
 }
 
@@ -38,11 +60,65 @@ func (s *Health) Ping(ctx context.Context) error {
 		return errors.Wrap(ErrRedisNotReachable, err.Error())
 	}
 
+	return s.pingNATS()
+}
+
+func (s *Health) pingNATS() error {
 	// nats does automatic ping for 20 seconds interval (configurated at infra/nats.go)
 	status := s.NATS.Status()
 	if status != nats.CONNECTED && status != nats.DRAINING_PUBS && status != nats.DRAINING_SUBS {
 		return errors.Wrap(ErrNATSNotReachable, status.String())
 	}
-
 	return nil
 }
+
+// MarkCachesWarm marks the caches of the global results as warmed up, after which the instance may report itself ready
+func (s *Health) MarkCachesWarm() {
+	s.cachesWarm.Store(true)
+}
+
+// MarkDraining makes the instance report itself not ready, so that load balancers stop routing traffic to it
+// before it stops accepting connections
+func (s *Health) MarkDraining() {
+	s.draining.Store(true)
+}
+
+// Readiness probes every dependency needed to serve traffic, rather than stopping at the first unreachable one
+// as Ping does, so that the report tells every check that failed. S3 is only probed when enabled by configuration
+// as it is needed by archiving only.
+func (s *Health) Readiness(ctx context.Context) *model.HealthReport {
+	report := &model.HealthReport{
+		Status: model.HealthStatusOK,
+		Checks: make(map[string]*model.HealthCheck),
+	}
+	check := func(name string, err error) {
+		if err != nil {
+			report.Status = model.HealthStatusUnavailable
+			report.Checks[name] = &model.HealthCheck{Status: model.HealthStatusUnavailable, Error: err.Error()}
+			return
+		}
+		report.Checks[name] = &model.HealthCheck{Status: model.HealthStatusOK}
+	}
+
+	check(HealthCheckPostgres, s.DB.PingContext(ctx))
+	check(HealthCheckRedis, s.Redis.Ping(ctx).Err())
+	check(HealthCheckNATS, s.pingNATS())
+
+	if s.Config.HealthS3ProbeEnabled {
+		check(HealthCheckS3, s.ArchiveService.Ping(ctx))
+	}
+
+	if s.cachesWarm.Load() {
+		report.Checks[HealthCheckCache] = &model.HealthCheck{Status: model.HealthStatusOK}
+	} else {
+		report.Status = model.HealthStatusUnavailable
+		report.Checks[HealthCheckCache] = &model.HealthCheck{Status: model.HealthStatusWarmingUp}
+	}
+
+	if s.draining.Load() {
+		report.Status = model.HealthStatusUnavailable
+		report.Checks[HealthCheckShutdown] = &model.HealthCheck{Status: model.HealthStatusDraining}
+	}
+
+	return report
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+const (
+	// integrityCheckInterval is the interval in-between integrity checks, each covering the reports since the previous
+	// one with some overlap
+	integrityCheckInterval = time.Hour * 6
+	integrityCheckWindow   = integrityCheckInterval * 2
+)
+
+// Integrity periodically checks the consistency of the recent reports as a background job. Failed checks are
+// retried and eventually listed as failed jobs for the maintainers to look into.
+type Integrity struct {
+	DropReportRepo *repo.DropReport
+}
+
+func NewIntegrity(dropReportRepo *repo.DropReport, jobs *Jobs) *Integrity {
+	s := &Integrity{
+		DropReportRepo: dropReportRepo,
+	}
+	jobs.Register(model.JobKindIntegrityCheck, JobPolicy{
+		MaxAttempts: 2,
+		Backoff:     time.Minute * 10,
+		Timeout:     time.Minute * 10,
+		Every:       integrityCheckInterval,
+		Singleton:   true,
+	}, s.check)
+	return s
+}
+
+func (s *Integrity) check(ctx context.Context, _ *model.Job) error {
+	count, err := s.DropReportRepo.CountReportsWithoutExtras(ctx, time.Now().Add(-integrityCheckWindow))
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.Errorf("%d drop reports have no extras", count)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -9,23 +10,29 @@ import (
 	"github.com/goccy/go-json"
 
 	"github.com/ahmetb/go-linq/v3"
+	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
 
 	"exusiai.dev/backend-next/internal/model"
 	"exusiai.dev/backend-next/internal/model/cache"
+	"exusiai.dev/backend-next/internal/model/types"
 	modelv2 "exusiai.dev/backend-next/internal/model/v2"
 	"exusiai.dev/backend-next/internal/pkg/pgerr"
 	"exusiai.dev/backend-next/internal/repo"
 	"exusiai.dev/backend-next/internal/util"
 )
 
+var spriteCoordRegex = regexp.MustCompile(`^\d+:\d+$`)
+
 type Item struct {
-	ItemRepo *repo.Item
+	ItemRepo                repo.ItemRepo
+	MetadataSnapshotService *MetadataSnapshot
 }
 
-func NewItem(itemRepo *repo.Item) *Item {
+func NewItem(itemRepo *repo.Item, metadataSnapshotService *MetadataSnapshot) *Item {
 	return &Item{
-		ItemRepo: itemRepo,
+		ItemRepo:                itemRepo,
+		MetadataSnapshotService: metadataSnapshotService,
 	}
 }
 
@@ -73,10 +80,157 @@ func (s *Item) GetItemByArkId(ctx context.Context, arkItemId string) (*model.Ite
 	return dbItem, nil
 }
 
+func (s *Item) CreateItem(ctx context.Context, req *types.AdminCreateItemRequest) (*model.Item, error) {
+	if _, err := s.ItemRepo.GetItemByArkId(ctx, req.ArkItemID); err == nil {
+		return nil, pgerr.ErrInvalidReq.Msg("item with itemId %s already exists", req.ArkItemID)
+	} else if !errors.Is(err, pgerr.ErrNotFound) {
+		return nil, err
+	}
+	if _, err := s.ItemRepo.GetDeletedItemByArkId(ctx, req.ArkItemID); err == nil {
+		return nil, pgerr.ErrInvalidReq.Msg("item with itemId %s has been deleted and may be restored instead", req.ArkItemID)
+	} else if !errors.Is(err, pgerr.ErrNotFound) {
+		return nil, err
+	}
+
+	item := &model.Item{ArkItemID: req.ArkItemID}
+	if err := applyAdminItemFields(item, &req.AdminItemFields); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.MetadataSnapshotService.Capture(ctx, "create item "+item.ArkItemID); err != nil {
+		return nil, err
+	}
+	if err := s.ItemRepo.CreateItem(ctx, item); err != nil {
+		return nil, err
+	}
+	s.invalidateItemCaches(item.ArkItemID)
+	return item, nil
+}
+
+func (s *Item) UpdateItem(ctx context.Context, arkItemId string, req *types.AdminUpdateItemRequest) (*model.Item, error) {
+	item, err := s.ItemRepo.GetItemByArkId(ctx, arkItemId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyAdminItemFields(item, &req.AdminItemFields); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.MetadataSnapshotService.Capture(ctx, "update item "+item.ArkItemID); err != nil {
+		return nil, err
+	}
+	if err := s.ItemRepo.UpdateItem(ctx, item); err != nil {
+		return nil, err
+	}
+	s.invalidateItemCaches(item.ArkItemID)
+	return item, nil
+}
+
+// GetDeletedItems returns the items deleted, which are left out of every other listing
+func (s *Item) GetDeletedItems(ctx context.Context) ([]*model.Item, error) {
+	return s.ItemRepo.GetDeletedItems(ctx)
+}
+
+// DeleteItem soft deletes the item: it disappears from the listings and no longer accepts drops, while its drop
+// reports and drop infos are kept for it to be restored
+func (s *Item) DeleteItem(ctx context.Context, arkItemId string) error {
+	item, err := s.ItemRepo.GetItemByArkId(ctx, arkItemId)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.MetadataSnapshotService.Capture(ctx, "delete item "+item.ArkItemID); err != nil {
+		return err
+	}
+	if err := s.ItemRepo.DeleteItem(ctx, item.ItemID); err != nil {
+		return err
+	}
+	s.invalidateItemCaches(item.ArkItemID)
+	return nil
+}
+
+// RestoreItem undoes the deletion of the item
+func (s *Item) RestoreItem(ctx context.Context, arkItemId string) (*model.Item, error) {
+	item, err := s.ItemRepo.GetDeletedItemByArkId(ctx, arkItemId)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.MetadataSnapshotService.Capture(ctx, "restore item "+item.ArkItemID); err != nil {
+		return nil, err
+	}
+	if err := s.ItemRepo.RestoreItem(ctx, item.ItemID); err != nil {
+		return nil, err
+	}
+	item.DeletedAt = nil
+	s.invalidateItemCaches(item.ArkItemID)
+	return item, nil
+}
+
+func applyAdminItemFields(item *model.Item, fields *types.AdminItemFields) error {
+	if fields.Sprite.Valid && !spriteCoordRegex.MatchString(fields.Sprite.String) {
+		return pgerr.ErrInvalidReq.Msg("sprite must be in a form of Y:X")
+	}
+
+	name, err := json.Marshal(fields.Name)
+	if err != nil {
+		return err
+	}
+	existence, err := json.Marshal(fields.Existence)
+	if err != nil {
+		return err
+	}
+
+	item.Type = fields.Type
+	item.Name = name
+	item.Existence = existence
+	item.SortID = fields.SortID
+	item.Rarity = fields.Rarity
+	item.Group = fields.Group
+	item.Sprite = fields.Sprite
+	item.Keywords = fields.Keywords
+	if item.Keywords == nil {
+		item.Keywords = json.RawMessage("{}")
+	}
+	return nil
+}
+
+// invalidateItemCaches removes every cached representation of the item list, so that
+// changes made via the admin API are visible immediately.
+func (s *Item) invalidateItemCaches(arkItemId string) {
+	cache.Items.Delete()
+	cache.ItemByArkID.Delete(arkItemId)
+	cache.ShimItems.Delete()
+	cache.ShimItemByArkID.Delete(arkItemId)
+	cache.ItemsMapById.Delete()
+	cache.ItemsMapByArkID.Delete()
+	cache.RecruitTagMap.Delete()
+	cache.ItemIcons.Delete()
+	cache.MetaBundle.Delete()
+}
+
+// purgeItemCaches drops every item cache, for changes that may touch any item
+func purgeItemCaches() {
+	cache.Items.Delete()
+	cache.ItemByArkID.Flush()
+	cache.ShimItems.Delete()
+	cache.ShimItemByArkID.Flush()
+	cache.ItemsMapById.Delete()
+	cache.ItemsMapByArkID.Delete()
+	cache.RecruitTagMap.Delete()
+	cache.ItemIcons.Delete()
+	cache.MetaBundle.Delete()
+}
+
 func (s *Item) SearchItemByName(ctx context.Context, name string) (*model.Item, error) {
 	return s.ItemRepo.SearchItemByName(ctx, name)
 }
 
+func (s *Item) SearchItems(ctx context.Context, query string, limit int) ([]*model.ItemSearchResult, error) {
+	return s.ItemRepo.SearchItems(ctx, strings.TrimSpace(query), limit)
+}
+
 // Cache: (singular) shimItems, 1 hr; records last modified time
 func (s *Item) GetShimItems(ctx context.Context) ([]*modelv2.Item, error) {
 	var items []*modelv2.Item
@@ -94,6 +248,7 @@ func (s *Item) GetShimItems(ctx context.Context) ([]*modelv2.Item, error) {
 	}
 	cache.ShimItems.Set(items, time.Minute*5)
 	cache.LastModifiedTime.Set("[shimItems]", time.Now(), 0)
+	cache.RecordContentHash("[shimItems]", items)
 	return items, nil
 }
 
@@ -154,6 +309,37 @@ func (s *Item) GetItemsMapByArkId(ctx context.Context) (map[string]*model.Item,
 	return itemsMapByArkId, nil
 }
 
+// GetItemsByIds looks up the items in batch, from the same cached map as GetItemById.
+// The items not found are left out of the returned map.
+func (s *Item) GetItemsByIds(ctx context.Context, itemIds []int) (map[int]*model.Item, error) {
+	itemsMapById, err := s.GetItemsMapById(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[int]*model.Item, len(itemIds))
+	for _, itemId := range itemIds {
+		if item, ok := itemsMapById[itemId]; ok {
+			items[itemId] = item
+		}
+	}
+	return items, nil
+}
+
+// GetItemsByArkIds looks up the items in batch by their ark IDs. The items not found are left out of the returned map.
+func (s *Item) GetItemsByArkIds(ctx context.Context, arkItemIds []string) (map[string]*model.Item, error) {
+	itemsMapByArkId, err := s.GetItemsMapByArkId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[string]*model.Item, len(arkItemIds))
+	for _, arkItemId := range arkItemIds {
+		if item, ok := itemsMapByArkId[arkItemId]; ok {
+			items[arkItemId] = item
+		}
+	}
+	return items, nil
+}
+
 // Cache: (singular) recruitTagMap, 1 hr
 func (s *Item) GetRecruitTagItemsByBilingualName(ctx context.Context) (map[string]string, error) {
 	var m map[string]string
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	"exusiai.dev/backend-next/internal/model/types"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// ItemIconMaxSize is the largest icon accepted for upload, in bytes
+const ItemIconMaxSize = 1 << 20
+
+// itemIconExtensions are the image types accepted as icons, along with the extensions of their keys
+var itemIconExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// ItemIcon manages where the icons of the items are, on the sprite sheet or standalone in the item icon bucket
+type ItemIcon struct {
+	ItemService             *Item
+	ItemRepo                *repo.Item
+	MetadataSnapshotService *MetadataSnapshot
+	Config                  *appconfig.Config
+
+	s3Client *s3.Client
+}
+
+func NewItemIcon(itemService *Item, itemRepo *repo.Item, metadataSnapshotService *MetadataSnapshot, conf *appconfig.Config, s3Client *s3.Client) *ItemIcon {
+	return &ItemIcon{
+		ItemService:             itemService,
+		ItemRepo:                itemRepo,
+		MetadataSnapshotService: metadataSnapshotService,
+		Config:                  conf,
+		s3Client:                s3Client,
+	}
+}
+
+// GetItemIcons returns the icons of the items having any, versioned by a hash of them all
+// Cache: (singular) itemIcons, 1 hr
+func (s *ItemIcon) GetItemIcons(ctx context.Context) (*modelv3.ItemIcons, error) {
+	var icons modelv3.ItemIcons
+	err := cache.ItemIcons.MutexGetSet(&icons, func() (modelv3.ItemIcons, error) {
+		items, err := s.ItemService.GetItems(ctx)
+		if err != nil {
+			return modelv3.ItemIcons{}, err
+		}
+		return calcItemIcons(items)
+	}, time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	return &icons, nil
+}
+
+func calcItemIcons(items []*model.Item) (modelv3.ItemIcons, error) {
+	icons := make([]*modelv3.ItemIcon, 0, len(items))
+	for _, item := range items {
+		if !item.Sprite.Valid && !item.IconURL.Valid {
+			continue
+		}
+		icons = append(icons, &modelv3.ItemIcon{
+			ArkItemID: item.ArkItemID,
+			Sprite:    item.Sprite,
+			IconURL:   item.IconURL,
+		})
+	}
+	// the version must not depend on the order the items happen to be listed in
+	sort.Slice(icons, func(i, j int) bool {
+		return icons[i].ArkItemID < icons[j].ArkItemID
+	})
+
+	content, err := json.Marshal(icons)
+	if err != nil {
+		return modelv3.ItemIcons{}, err
+	}
+	sum := sha256.Sum256(content)
+	return modelv3.ItemIcons{
+		Version: hex.EncodeToString(sum[:8]),
+		Icons:   icons,
+	}, nil
+}
+
+// UpdateItemIcons sets the sprites and icon URLs of the items in the request at once
+func (s *ItemIcon) UpdateItemIcons(ctx context.Context, req *types.UpdateItemIconsRequest) (*modelv3.ItemIcons, error) {
+	arkItemIds := make([]string, 0, len(req.Icons))
+	seen := make(map[string]struct{}, len(req.Icons))
+	for _, icon := range req.Icons {
+		if _, ok := seen[icon.ItemID]; ok {
+			return nil, pgerr.ErrInvalidReq.Msg("item %s is listed more than once", icon.ItemID)
+		}
+		seen[icon.ItemID] = struct{}{}
+		if icon.Sprite.Valid && !spriteCoordRegex.MatchString(icon.Sprite.String) {
+			return nil, pgerr.ErrInvalidReq.Msg("sprite of item %s must be in a form of Y:X", icon.ItemID)
+		}
+		arkItemIds = append(arkItemIds, icon.ItemID)
+	}
+
+	itemsMapByArkId, err := s.ItemService.GetItemsByArkIds(ctx, arkItemIds)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*model.Item, 0, len(req.Icons))
+	for _, icon := range req.Icons {
+		cached, ok := itemsMapByArkId[icon.ItemID]
+		if !ok {
+			return nil, pgerr.ErrNotFound.Msg("item %s not found", icon.ItemID)
+		}
+		// the items are shared with the cache, so the changes are made on copies of them
+		item := *cached
+		item.Sprite = icon.Sprite
+		item.IconURL = icon.IconURL
+		items = append(items, &item)
+	}
+
+	if _, err := s.MetadataSnapshotService.Capture(ctx, "update item icons"); err != nil {
+		return nil, err
+	}
+	if err := s.ItemRepo.UpdateItemIcons(ctx, items); err != nil {
+		return nil, err
+	}
+	purgeItemCaches()
+	return s.GetItemIcons(ctx)
+}
+
+// UploadItemIcon uploads the standalone icon of the item and points the item at it. The key of the icon contains
+// the hash of its content, so that its URL changes along with it while the objects themselves may be cached forever.
+func (s *ItemIcon) UploadItemIcon(ctx context.Context, arkItemId string, content []byte) (*model.Item, error) {
+	if s.Config.ItemIconBaseURL == "" {
+		return nil, pgerr.ErrInternalError.Msg("the item icon base url is not configured")
+	}
+	if len(content) == 0 || len(content) > ItemIconMaxSize {
+		return nil, pgerr.ErrInvalidReq.Msg("icon must be between 1 and %d bytes", ItemIconMaxSize)
+	}
+	contentType := http.DetectContentType(content)
+	ext, ok := itemIconExtensions[contentType]
+	if !ok {
+		return nil, pgerr.ErrInvalidReq.Msg("icon must be a png or webp image, got %s", contentType)
+	}
+
+	item, err := s.ItemRepo.GetItemByArkId(ctx, arkItemId)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(content)
+	name := url.PathEscape(item.ArkItemID) + "/" + hex.EncodeToString(sum[:8]) + ext
+	key := s.Config.ItemIconS3Prefix + name
+	bucket := s.Config.ItemIconS3Bucket
+	if bucket == "" {
+		bucket = s.Config.DropReportArchiveS3Bucket
+	}
+	_, err = s.s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:       aws.String(bucket),
+		Key:          aws.String(key),
+		Body:         bytes.NewReader(content),
+		ContentType:  aws.String(contentType),
+		CacheControl: aws.String("public, max-age=31536000, immutable"),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to upload item icon")
+	}
+
+	item.IconURL.SetValid(strings.TrimSuffix(s.Config.ItemIconBaseURL, "/") + "/" + key)
+	if _, err := s.MetadataSnapshotService.Capture(ctx, "upload icon of item "+item.ArkItemID); err != nil {
+		return nil, err
+	}
+	if err := s.ItemRepo.UpdateItemIcons(ctx, []*model.Item{item}); err != nil {
+		return nil, err
+	}
+	s.ItemService.invalidateItemCaches(item.ArkItemID)
+	return item, nil
+}
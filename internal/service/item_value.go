@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	"exusiai.dev/backend-next/internal/model/types"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// itemValuePresetPattern restricts the names of the presets, which appear in the admin routes
+var itemValuePresetPattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// ItemValue manages the sanity value of the items per server, in presets such as the market prices and the farming
+// efficiency given by the community calculators
+type ItemValue struct {
+	ItemValueRepo *repo.ItemValue
+	ItemService   *Item
+}
+
+func NewItemValue(itemValueRepo *repo.ItemValue, itemService *Item) *ItemValue {
+	return &ItemValue{
+		ItemValueRepo: itemValueRepo,
+		ItemService:   itemService,
+	}
+}
+
+// GetItemValueTables returns the item values of every preset of the server, optionally only those of the preset
+// Cache: itemValueTables#server:{server}, 1 hr
+func (s *ItemValue) GetItemValueTables(ctx context.Context, server string, preset string) ([]*modelv3.ItemValueTable, error) {
+	var tables []*modelv3.ItemValueTable
+	if err := cache.ItemValueTables.Get(server, &tables); err != nil {
+		values, err := s.ItemValueRepo.GetItemValues(ctx, server)
+		if err != nil {
+			return nil, err
+		}
+		tables, err = s.toItemValueTables(ctx, values)
+		if err != nil {
+			return nil, err
+		}
+		cache.ItemValueTables.Set(server, tables, time.Hour)
+	}
+
+	if preset == "" {
+		return tables, nil
+	}
+	for _, table := range tables {
+		if table.Preset == preset {
+			return []*modelv3.ItemValueTable{table}, nil
+		}
+	}
+	return []*modelv3.ItemValueTable{}, nil
+}
+
+// GetAllItemValueTables returns the item values of every preset of every server, uncached
+func (s *ItemValue) GetAllItemValueTables(ctx context.Context) ([]*modelv3.ItemValueTable, error) {
+	values, err := s.ItemValueRepo.GetItemValues(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return s.toItemValueTables(ctx, values)
+}
+
+// ReplaceItemValues replaces every item value of the preset in the server
+func (s *ItemValue) ReplaceItemValues(ctx context.Context, preset string, req *types.ReplaceItemValuesRequest) (*modelv3.ItemValueTable, error) {
+	if !itemValuePresetPattern.MatchString(preset) {
+		return nil, pgerr.ErrInvalidReq.Msg("invalid preset %s: must consist of 1 to 32 lowercase letters, digits, - or _", preset)
+	}
+
+	itemsMap, err := s.ItemService.GetItemsMapByArkId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]*model.ItemValue, 0, len(req.Values))
+	for arkItemId, value := range req.Values {
+		item, ok := itemsMap[arkItemId]
+		if !ok {
+			return nil, pgerr.ErrInvalidReq.Msg("item %s does not exist", arkItemId)
+		}
+		values = append(values, &model.ItemValue{
+			Preset: preset,
+			Server: req.Server,
+			ItemID: item.ItemID,
+			Value:  value,
+		})
+	}
+
+	if err := s.ItemValueRepo.ReplaceItemValues(ctx, preset, req.Server, values); err != nil {
+		return nil, err
+	}
+	cache.ItemValueTables.Delete(req.Server)
+
+	log.Info().
+		Str("evt.name", "admin.item_values.replaced").
+		Str("preset", preset).
+		Str("server", req.Server).
+		Int("items", len(values)).
+		Msg("item values replaced")
+
+	return &modelv3.ItemValueTable{
+		Preset:    preset,
+		Server:    req.Server,
+		Values:    req.Values,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// DeleteItemValues deletes the item values of the preset in the server, or in every server when server is empty
+func (s *ItemValue) DeleteItemValues(ctx context.Context, preset string, server string) error {
+	deleted, err := s.ItemValueRepo.DeleteItemValues(ctx, preset, server)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return pgerr.ErrNotFound
+	}
+	if server != "" {
+		cache.ItemValueTables.Delete(server)
+	} else if err := cache.ItemValueTables.Flush(); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("evt.name", "admin.item_values.deleted").
+		Str("preset", preset).
+		Str("server", server).
+		Int64("items", deleted).
+		Msg("item values deleted")
+
+	return nil
+}
+
+// toItemValueTables groups the values, ordered by preset and server, into a table per preset and server
+func (s *ItemValue) toItemValueTables(ctx context.Context, values []*model.ItemValue) ([]*modelv3.ItemValueTable, error) {
+	itemsMap, err := s.ItemService.GetItemsMapById(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]*modelv3.ItemValueTable, 0)
+	var table *modelv3.ItemValueTable
+	for _, value := range values {
+		if table == nil || table.Preset != value.Preset || table.Server != value.Server {
+			table = &modelv3.ItemValueTable{
+				Preset: value.Preset,
+				Server: value.Server,
+				Values: make(map[string]float64),
+			}
+			tables = append(tables, table)
+		}
+		if item, ok := itemsMap[value.ItemID]; ok {
+			table.Values[item.ArkItemID] = value.Value
+		}
+		if value.UpdatedAt.After(table.UpdatedAt) {
+			table.UpdatedAt = value.UpdatedAt
+		}
+	}
+	return tables, nil
+}
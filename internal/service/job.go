@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/pkg/observability"
+	"exusiai.dev/backend-next/internal/pkg/pgpool"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// defaultJobTimeout bounds the attempts of the kinds which do not specify a timeout, as running jobs
+// must eventually be claimed again should their instance die
+const defaultJobTimeout = time.Hour
+
+// JobPolicy tells how the jobs of a kind are run and retried
+type JobPolicy struct {
+	// MaxAttempts is the number of times a job is started before it is considered failed
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled upon every following one
+	Backoff time.Duration
+	// Timeout bounds a single attempt. A running job is claimed again by another instance once it is exceeded,
+	// assuming the instance running it has died.
+	Timeout time.Duration
+	// Every enqueues a job of the kind periodically, at most once per period across instances. Zero disables it.
+	Every time.Duration
+	// Singleton restricts the jobs of the kind to the leader, one at a time, e.g. for the jobs that would corrupt
+	// each other's results if run concurrently by several replicas
+	Singleton bool
+}
+
+// JobHandler runs a single attempt of a job
+type JobHandler func(ctx context.Context, job *model.Job) error
+
+type jobKind struct {
+	handler JobHandler
+	policy  JobPolicy
+}
+
+// Jobs runs background tasks as persisted jobs with retries. Services register the handlers of their kinds of
+// jobs upon construction; the job worker then runs the due jobs on whichever instance claims them first.
+type Jobs struct {
+	JobRepo *repo.Job
+	Leader  *Leader
+
+	mu    sync.RWMutex
+	kinds map[string]*jobKind
+}
+
+func NewJobs(jobRepo *repo.Job, leader *Leader) *Jobs {
+	return &Jobs{
+		JobRepo: jobRepo,
+		Leader:  leader,
+		kinds:   make(map[string]*jobKind),
+	}
+}
+
+// Register registers the handler of a kind of jobs
+func (s *Jobs) Register(kind string, policy JobPolicy, handler JobHandler) {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	if policy.Timeout <= 0 {
+		policy.Timeout = defaultJobTimeout
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.kinds[kind] = &jobKind{handler: handler, policy: policy}
+}
+
+func (s *Jobs) kind(kind string) *jobKind {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	k, ok := s.kinds[kind]
+	if !ok {
+		panic("unregistered job kind " + kind)
+	}
+	return k
+}
+
+// Kinds lists the registered kinds of jobs
+func (s *Jobs) Kinds() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	kinds := make([]string, 0, len(s.kinds))
+	for kind := range s.kinds {
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+func newJob(kind string, server string, key string, payload any) (*model.Job, error) {
+	job := &model.Job{
+		JobID:  strings.ToLower(ulid.Make().String()),
+		Kind:   kind,
+		Key:    null.NewString(key, key != ""),
+		Server: server,
+		Status: model.JobStatusPending,
+		RunAt:  time.Now(),
+	}
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		job.Payload = b
+	}
+	return job, nil
+}
+
+// Enqueue persists a job to be run by the job worker. A non-empty key deduplicates the job: nothing is enqueued
+// when a job with the same key already exists, and created is false.
+func (s *Jobs) Enqueue(ctx context.Context, kind string, server string, key string, payload any) (job *model.Job, created bool, err error) {
+	k := s.kind(kind)
+	job, err = newJob(kind, server, key, payload)
+	if err != nil {
+		return nil, false, err
+	}
+	job.MaxAttempts = k.policy.MaxAttempts
+
+	created, err = s.JobRepo.CreateJob(ctx, job)
+	if err != nil {
+		return nil, false, err
+	}
+	return job, created, nil
+}
+
+// EnqueuePeriodic enqueues a job of every periodic kind, unless it has already been enqueued for the current period
+func (s *Jobs) EnqueuePeriodic(ctx context.Context) error {
+	s.mu.RLock()
+	periodic := make(map[string]time.Duration)
+	for kind, k := range s.kinds {
+		if k.policy.Every > 0 {
+			periodic[kind] = k.policy.Every
+		}
+	}
+	s.mu.RUnlock()
+
+	for kind, every := range periodic {
+		key := kind + ":" + time.Now().Truncate(every).UTC().Format(time.RFC3339)
+		if _, _, err := s.Enqueue(ctx, kind, "", key, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Singleton tells whether the jobs of the kind are run by the leader only, one at a time
+func (s *Jobs) Singleton(kind string) bool {
+	return s.kind(kind).policy.Singleton
+}
+
+// ClaimDueJobs claims up to limit due jobs of the kind for this instance to run. Singleton jobs are only
+// claimed by the leader, one at a time.
+func (s *Jobs) ClaimDueJobs(ctx context.Context, kind string, limit int) ([]*model.Job, error) {
+	policy := s.kind(kind).policy
+	if policy.Singleton {
+		if !s.Leader.IsLeader() {
+			return nil, nil
+		}
+		limit = 1
+	}
+	return s.JobRepo.ClaimDueJobs(ctx, kind, policy.Timeout, limit)
+}
+
+// Run runs an attempt of a claimed job and records its outcome: the job either succeeds, is scheduled
+// for a retry with backoff, or fails once it has been attempted MaxAttempts times.
+func (s *Jobs) Run(job *model.Job) {
+	k := s.kind(job.Kind)
+
+	// the jobs hold the connections of the background pool, not to starve the requests of theirs
+	ctx := pgpool.WithBackground(context.Background())
+	if k.policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, k.policy.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := k.handler(ctx, job)
+	observability.ObserveJob(job.Kind, job.Server, start, err)
+	s.finish(job, k.policy, err)
+
+	// the outcome is recorded regardless of whether the attempt ran out of time
+	if err := s.JobRepo.UpdateJobResult(context.Background(), job); err != nil {
+		log.Error().
+			Str("evt.name", "job.update").
+			Str("jobId", job.JobID).
+			Err(err).
+			Msg("failed to record job result")
+	}
+}
+
+// RunInline runs a job of a kind not registered to the worker, in-process and synchronously, e.g. for the tasks
+// that concern this instance only. Its attempts are retried in-process and recorded like any other job.
+func (s *Jobs) RunInline(ctx context.Context, kind string, policy JobPolicy, handler JobHandler) error {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	job, err := newJob(kind, "", "", nil)
+	if err != nil {
+		return err
+	}
+	job.MaxAttempts = policy.MaxAttempts
+	job.Status = model.JobStatusRunning
+	if _, err := s.JobRepo.CreateJob(ctx, job); err != nil {
+		// the job is still run: recording it is not essential
+		log.Warn().Err(err).Str("kind", kind).Msg("failed to record inline job")
+	}
+
+	for {
+		now := time.Now()
+		job.Attempts++
+		job.StartedAt = &now
+
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if policy.Timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, policy.Timeout)
+		}
+		err = handler(attemptCtx, job)
+		cancel()
+		observability.ObserveJob(job.Kind, job.Server, now, err)
+		s.finish(job, policy, err)
+
+		if err := s.JobRepo.UpdateJobResult(context.Background(), job); err != nil {
+			log.Warn().Err(err).Str("jobId", job.JobID).Msg("failed to record inline job result")
+		}
+		if job.Status != model.JobStatusRetrying {
+			return err
+		}
+
+		select {
+		case <-time.After(time.Until(job.RunAt)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Jobs) finish(job *model.Job, policy JobPolicy, err error) {
+	now := time.Now()
+	job.LeaseExpiresAt = nil
+	if err == nil {
+		job.Status = model.JobStatusSucceeded
+		job.LastError = null.String{}
+		job.FinishedAt = &now
+		return
+	}
+
+	job.LastError = null.StringFrom(err.Error())
+	if job.Attempts < job.MaxAttempts {
+		job.Status = model.JobStatusRetrying
+		job.RunAt = now.Add(policy.Backoff << (job.Attempts - 1))
+	} else {
+		job.Status = model.JobStatusFailed
+		job.FinishedAt = &now
+	}
+
+	log.Warn().
+		Str("evt.name", "job.attempt.failed").
+		Str("jobId", job.JobID).
+		Str("kind", job.Kind).
+		Str("server", job.Server).
+		Int("attempt", job.Attempts).
+		Str("status", job.Status).
+		Err(err).
+		Msg("job attempt failed")
+}
+
+func (s *Jobs) GetJobs(ctx context.Context, kind string, status string, limit int) ([]*model.Job, error) {
+	return s.JobRepo.GetJobs(ctx, kind, status, limit)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/model/cache"
+)
+
+// lastModifiedRedisKey is the hash of the last modified times flushed by the instances upon shutdown
+const lastModifiedRedisKey = "lastModifiedTime"
+
+const lastModifiedRestoreTimeout = time.Second * 5
+
+// PersistLastModifiedTime keeps the last modified times of the cached results across deploys: they are
+// flushed to redis once the instance has stopped serving traffic, and restored upon the next startup so that
+// conditional requests from clients are still answered with 304 until the results change.
+// It must be invoked after the caches are initialized and before the workers start.
+func PersistLastModifiedTime(redisClient *redis.Client, lc fx.Lifecycle) {
+	ctx, cancel := context.WithTimeout(context.Background(), lastModifiedRestoreTimeout)
+	defer cancel()
+	if err := restoreLastModifiedTime(ctx, redisClient); err != nil {
+		log.Warn().
+			Str("evt.name", "cache.last_modified.restore").
+			Err(err).
+			Msg("failed to restore last modified times")
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return flushLastModifiedTime(ctx, redisClient)
+		},
+	})
+}
+
+func restoreLastModifiedTime(ctx context.Context, redisClient *redis.Client) error {
+	values, err := redisClient.HGetAll(ctx, lastModifiedRedisKey).Result()
+	if err != nil {
+		return err
+	}
+	local := cache.LastModifiedTime.Items()
+	for key, value := range values {
+		if _, ok := local[key]; ok {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			continue
+		}
+		cache.LastModifiedTime.Set(key, t, 0)
+	}
+	return nil
+}
+
+func flushLastModifiedTime(ctx context.Context, redisClient *redis.Client) error {
+	items := cache.LastModifiedTime.Items()
+	if len(items) == 0 {
+		return nil
+	}
+	values := make(map[string]any, len(items))
+	for key, t := range items {
+		values[key] = t.Format(time.RFC3339Nano)
+	}
+	return redisClient.HSet(ctx, lastModifiedRedisKey, values).Err()
+}
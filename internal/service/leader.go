@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redsync/redsync/v4"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/pkg/observability"
+)
+
+// Leader elects a single instance among the replicas to run the singleton jobs. The leadership is a lease on
+// a redis mutex, renewed at a third of its duration; an instance which fails to renew it steps down at once, and
+// another instance takes over once the lease has expired.
+type Leader struct {
+	lease time.Duration
+	mutex *redsync.Mutex
+
+	leader atomic.Bool
+}
+
+func NewLeader(conf *appconfig.Config, rs *redsync.Redsync, lc fx.Lifecycle) (*Leader, error) {
+	if conf.LeaderLeaseDuration <= 0 {
+		return nil, errors.New("leader lease duration must be positive")
+	}
+
+	s := &Leader{
+		lease: conf.LeaderLeaseDuration,
+		mutex: rs.NewMutex("mutex:leader", redsync.WithExpiry(conf.LeaderLeaseDuration), redsync.WithTries(1)),
+	}
+
+	stop := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go s.campaign(stop)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stop)
+			// step down for another instance to take over without waiting for the lease to expire
+			if s.leader.Load() {
+				s.setLeader(false)
+				if _, err := s.mutex.UnlockContext(ctx); err != nil {
+					log.Warn().Str("evt.name", "leader.resign").Err(err).Msg("failed to resign leadership")
+				}
+			}
+			return nil
+		},
+	})
+	return s, nil
+}
+
+// IsLeader tells whether this instance currently holds the leadership
+func (s *Leader) IsLeader() bool {
+	return s.leader.Load()
+}
+
+func (s *Leader) campaign(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.lease / 3)
+	defer ticker.Stop()
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), s.lease/3)
+		if s.leader.Load() {
+			if ok, err := s.mutex.ExtendContext(ctx); err != nil || !ok {
+				s.setLeader(false)
+				log.Warn().Str("evt.name", "leader.lost").Err(err).Msg("lost leadership")
+			}
+		} else if err := s.mutex.LockContext(ctx); err == nil {
+			s.setLeader(true)
+			log.Info().Str("evt.name", "leader.elected").Msg("elected as leader")
+		}
+		cancel()
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (s *Leader) setLeader(leader bool) {
+	s.leader.Store(leader)
+	if leader {
+		observability.Leader.Set(1)
+	} else {
+		observability.Leader.Set(0)
+	}
+}
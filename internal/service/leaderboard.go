@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"exusiai.dev/backend-next/internal/model/cache"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+	"exusiai.dev/backend-next/internal/util/reportverifs"
+)
+
+const (
+	// LeaderboardMaxEntries is the most contributors listed on a leaderboard
+	LeaderboardMaxEntries = 100
+	// leaderboardCacheTTL is how long the leaderboards are cached for
+	leaderboardCacheTTL = time.Minute * 5
+)
+
+// leaderboardReliabilities are the reliabilities of the reports counted on the leaderboards, which are those shown
+// to the contributors as accepted, so that no contributor learns about the exclusion of its reports from them
+var leaderboardReliabilities = []int{0, reportverifs.ViolationReliabilityShadowBan, reportverifs.ViolationReliabilityQuarantine}
+
+// Leaderboard ranks the contributors of the events who have opted in to be listed
+type Leaderboard struct {
+	EventRepo      *repo.Event
+	DropReportRepo *repo.DropReport
+}
+
+func NewLeaderboard(eventRepo *repo.Event, dropReportRepo *repo.DropReport) *Leaderboard {
+	return &Leaderboard{
+		EventRepo:      eventRepo,
+		DropReportRepo: dropReportRepo,
+	}
+}
+
+// GetEventLeaderboard ranks the contributors of the event in the server, from its open time to its close time
+// Cache: eventLeaderboard#eventId|server|sortBy:{eventId}|{server}|{sortBy}, 5 min
+func (s *Leaderboard) GetEventLeaderboard(ctx context.Context, eventId int, server string, sortBy string) (*modelv3.EventLeaderboard, error) {
+	key := strconv.Itoa(eventId) + "|" + server + "|" + sortBy
+	var leaderboard modelv3.EventLeaderboard
+	_, err := cache.EventLeaderboard.MutexGetSet(key, &leaderboard, func() (*modelv3.EventLeaderboard, error) {
+		return s.calcEventLeaderboard(ctx, eventId, server, sortBy)
+	}, leaderboardCacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &leaderboard, nil
+}
+
+func (s *Leaderboard) calcEventLeaderboard(ctx context.Context, eventId int, server string, sortBy string) (*modelv3.EventLeaderboard, error) {
+	event, err := s.EventRepo.GetEventById(ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	leaderboard := &modelv3.EventLeaderboard{
+		EventID:     event.EventID,
+		Server:      server,
+		SortBy:      sortBy,
+		GeneratedAt: time.Now(),
+		Entries:     []*modelv3.LeaderboardEntry{},
+	}
+	for _, schedule := range event.Schedules {
+		if schedule.Server != server {
+			continue
+		}
+		if schedule.OpenTime.After(leaderboard.GeneratedAt) {
+			return leaderboard, nil
+		}
+
+		contributions, err := s.DropReportRepo.CalcContributionsByZones(ctx, server, event.ZoneIDs, schedule.OpenTime, schedule.CloseTime,
+			leaderboardReliabilities, sortBy, LeaderboardMaxEntries)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to calculate contributions")
+		}
+		for i, contribution := range contributions {
+			leaderboard.Entries = append(leaderboard.Entries, &modelv3.LeaderboardEntry{
+				Rank:    i + 1,
+				Name:    contribution.Name,
+				Reports: contribution.Reports,
+				Times:   contribution.Times,
+				Sanity:  contribution.Sanity,
+			})
+		}
+		return leaderboard, nil
+	}
+	return nil, pgerr.ErrNotFound.Msg("event %d is not scheduled in %s", eventId, server)
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+
+	"github.com/goccy/go-json"
+	"github.com/tidwall/gjson"
+
+	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+	"exusiai.dev/backend-next/internal/util/i18n"
+)
+
+type Localization struct {
+	ItemService  *Item
+	StageService *Stage
+}
+
+func NewLocalization(itemService *Item, stageService *Stage) *Localization {
+	return &Localization{
+		ItemService:  itemService,
+		StageService: stageService,
+	}
+}
+
+// LocalizedNames maps the ark stage and item ids to their display names in a single language.
+// Stages are named after their codes.
+type LocalizedNames struct {
+	stages map[string]string
+	items  map[string]string
+}
+
+// Stage returns the name of the stage, or the id itself when the stage is unknown
+func (n *LocalizedNames) Stage(arkStageId string) string {
+	if name, ok := n.stages[arkStageId]; ok {
+		return name
+	}
+	return arkStageId
+}
+
+// Item returns the name of the item, or the id itself when the item is unknown
+func (n *LocalizedNames) Item(arkItemId string) string {
+	if name, ok := n.items[arkItemId]; ok {
+		return name
+	}
+	return arkItemId
+}
+
+// GetNames returns the stage and item names in lang, falling back to the first of i18n.Languages
+// for the names that are not available in lang
+func (s *Localization) GetNames(ctx context.Context, lang string) (*LocalizedNames, error) {
+	stages, err := s.StageService.GetStages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.ItemService.GetItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := &LocalizedNames{
+		stages: make(map[string]string, len(stages)),
+		items:  make(map[string]string, len(items)),
+	}
+	for _, stage := range stages {
+		if name := localizedName(stage.Code, lang); name != "" {
+			names.stages[stage.ArkStageID] = name
+		}
+	}
+	for _, item := range items {
+		if name := localizedName(item.Name, lang); name != "" {
+			names.items[item.ArkItemID] = name
+		}
+	}
+	return names, nil
+}
+
+// LocalizeDropMatrix embeds the stage and item names in lang into the drop matrix.
+// The elements of result are shared rather than modified, since they may come from the cache.
+func (s *Localization) LocalizeDropMatrix(ctx context.Context, result *modelv2.DropMatrixQueryResult, lang string) (*modelv2.LocalizedDropMatrixQueryResult, error) {
+	names, err := s.GetNames(ctx, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	localized := &modelv2.LocalizedDropMatrixQueryResult{
+		Matrix: make([]*modelv2.LocalizedDropMatrixElement, 0, len(result.Matrix)),
+	}
+	for _, el := range result.Matrix {
+		localized.Matrix = append(localized.Matrix, &modelv2.LocalizedDropMatrixElement{
+			OneDropMatrixElement: el,
+			StageName:            names.Stage(el.StageID),
+			ItemName:             names.Item(el.ItemID),
+		})
+	}
+	return localized, nil
+}
+
+// LocalizePatternMatrix embeds the stage and item names in lang into the pattern matrix
+func (s *Localization) LocalizePatternMatrix(ctx context.Context, result *modelv2.PatternMatrixQueryResult, lang string) (*modelv2.LocalizedPatternMatrixQueryResult, error) {
+	names, err := s.GetNames(ctx, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	localized := &modelv2.LocalizedPatternMatrixQueryResult{
+		PatternMatrix: make([]*modelv2.LocalizedPatternMatrixElement, 0, len(result.PatternMatrix)),
+	}
+	for _, el := range result.PatternMatrix {
+		var pattern *modelv2.LocalizedPattern
+		if el.Pattern != nil {
+			pattern = &modelv2.LocalizedPattern{
+				Drops: make([]*modelv2.LocalizedDrop, 0, len(el.Pattern.Drops)),
+			}
+			for _, drop := range el.Pattern.Drops {
+				pattern.Drops = append(pattern.Drops, &modelv2.LocalizedDrop{
+					OneDrop:  drop,
+					ItemName: names.Item(drop.ItemID),
+				})
+			}
+		}
+		localized.PatternMatrix = append(localized.PatternMatrix, &modelv2.LocalizedPatternMatrixElement{
+			OnePatternMatrixElement: el,
+			StageName:               names.Stage(el.StageID),
+			Pattern:                 pattern,
+		})
+	}
+	return localized, nil
+}
+
+// LocalizeTrend embeds the stage and item names in lang into the trends
+func (s *Localization) LocalizeTrend(ctx context.Context, result *modelv2.TrendQueryResult, lang string) (*modelv2.LocalizedTrendQueryResult, error) {
+	names, err := s.GetNames(ctx, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	localized := &modelv2.LocalizedTrendQueryResult{
+		Trend: make(map[string]*modelv2.LocalizedStageTrend, len(result.Trend)),
+	}
+	for stageId, stageTrend := range result.Trend {
+		results := make(map[string]*modelv2.LocalizedOneItemTrend, len(stageTrend.Results))
+		for itemId, itemTrend := range stageTrend.Results {
+			results[itemId] = &modelv2.LocalizedOneItemTrend{
+				OneItemTrend: itemTrend,
+				ItemName:     names.Item(itemId),
+			}
+		}
+		localized.Trend[stageId] = &modelv2.LocalizedStageTrend{
+			StageName: names.Stage(stageId),
+			Results:   results,
+			StartTime: stageTrend.StartTime,
+			Buckets:   stageTrend.Buckets,
+		}
+	}
+	return localized, nil
+}
+
+func localizedName(names json.RawMessage, lang string) string {
+	parsed := gjson.ParseBytes(names)
+	if name := parsed.Get(lang).String(); name != "" {
+		return name
+	}
+	return parsed.Get(i18n.Languages[0]).String()
+}
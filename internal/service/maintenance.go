@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+)
+
+// maintenanceRedisKey is the maintenance switched on through the admin API as JSON, shared by every instance
+const maintenanceRedisKey = "maintenance"
+
+// Maintenance keeps the state of the maintenance mode. The one switched on by the config takes precedence over the one
+// switched on through the admin API, which is kept in redis and picked up by every instance within the tunables
+// refresh interval.
+type Maintenance struct {
+	Config *appconfig.Config
+	Redis  *redis.Client
+
+	// current is the latest known maintenance switched on through the admin API
+	current atomic.Pointer[model.Maintenance]
+	// startedAt is the time the instance has started at, which the maintenance switched on by the config is since
+	startedAt time.Time
+}
+
+func NewMaintenance(conf *appconfig.Config, redisClient *redis.Client, lc fx.Lifecycle) *Maintenance {
+	s := &Maintenance{
+		Config:    conf,
+		Redis:     redisClient,
+		startedAt: time.Now(),
+	}
+	s.current.Store(&model.Maintenance{})
+
+	watchEvery(lc, "maintenance", conf.TunablesRefreshInterval, s.refresh)
+	return s
+}
+
+func (s *Maintenance) refresh(ctx context.Context) error {
+	b, err := s.Redis.Get(ctx, maintenanceRedisKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		s.current.Store(&model.Maintenance{})
+		return nil
+	} else if err != nil {
+		return err
+	}
+	var maintenance model.Maintenance
+	if err := json.Unmarshal(b, &maintenance); err != nil {
+		return err
+	}
+	s.current.Store(&maintenance)
+	return nil
+}
+
+// Current returns the maintenance in effect, as known by this instance
+func (s *Maintenance) Current() *model.Maintenance {
+	if s.Config.MaintenanceMode {
+		maintenance := &model.Maintenance{
+			Active: true,
+			Source: model.MaintenanceSourceConfig,
+			Reason: s.Config.MaintenanceReason,
+			Since:  &s.startedAt,
+		}
+		if !s.Config.MaintenanceETA.IsZero() {
+			maintenance.ETA = &s.Config.MaintenanceETA
+		}
+		return maintenance
+	}
+	return s.current.Load()
+}
+
+// StartMaintenance switches the maintenance mode on for every instance
+func (s *Maintenance) StartMaintenance(ctx context.Context, request *types.StartMaintenanceRequest) (*model.Maintenance, error) {
+	now := time.Now()
+	b, err := json.Marshal(&model.Maintenance{
+		Active: true,
+		Source: model.MaintenanceSourceAdmin,
+		Reason: request.Reason,
+		Since:  &now,
+		ETA:    request.ETA,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Redis.Set(ctx, maintenanceRedisKey, b, 0).Err(); err != nil {
+		return nil, err
+	}
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s.Current(), nil
+}
+
+// StopMaintenance switches the maintenance mode off for every instance, unless it is switched on by the config
+func (s *Maintenance) StopMaintenance(ctx context.Context) (*model.Maintenance, error) {
+	if err := s.Redis.Del(ctx, maintenanceRedisKey).Err(); err != nil {
+		return nil, err
+	}
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s.Current(), nil
+}
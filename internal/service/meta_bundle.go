@@ -0,0 +1,195 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+
+	"exusiai.dev/backend-next/internal/model/cache"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	pkgcache "exusiai.dev/backend-next/internal/pkg/cache"
+)
+
+const (
+	metaBundleRedisPrefix   = "metabundle:"
+	metaBundleVersionKey    = metaBundleRedisPrefix + "version"
+	metaBundleLatestKey     = metaBundleRedisPrefix + "latest"
+	metaBundleContentPrefix = metaBundleRedisPrefix + "content:"
+
+	// metaBundleRetention is how long the content of a version is kept for deltas to be computed against.
+	// Clients with an older version receive the full bundle.
+	metaBundleRetention = time.Hour * 24 * 30
+)
+
+// metaBundleSetLatest points the latest version to the given one, unless a newer version has been recorded
+// by another instance in the meantime, so that the latest version never goes backwards
+var metaBundleSetLatest = redis.NewScript(`
+local current = tonumber(redis.call('HGET', KEYS[1], 'version') or '0')
+if current < tonumber(ARGV[1]) then
+	redis.call('HSET', KEYS[1], 'version', ARGV[1], 'hash', ARGV[2])
+end
+return 0
+`)
+
+// MetaBundle versions the items, stages and zones as a whole. The versions are shared between the instances in redis,
+// along with the content of each version, so that a delta can be computed for clients having an older version.
+type MetaBundle struct {
+	Redis       *redis.Client
+	InitService *Init
+}
+
+func NewMetaBundle(redisClient *redis.Client, initService *Init) *MetaBundle {
+	return &MetaBundle{
+		Redis:       redisClient,
+		InitService: initService,
+	}
+}
+
+// Cache: metaBundle, 1 min
+func (s *MetaBundle) GetLatestMetaBundle(ctx context.Context) (*modelv3.MetaBundle, error) {
+	var bundle modelv3.MetaBundle
+	err := cache.MetaBundle.MutexGetSet(&bundle, func() (modelv3.MetaBundle, error) {
+		latest, err := s.calcLatestMetaBundle(ctx)
+		if err != nil {
+			return modelv3.MetaBundle{}, err
+		}
+		return *latest, nil
+	}, time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// GetMetaBundleSince returns the changes since the given version, or the full bundle when the version is unknown.
+// A nil bundle is returned when the version is the latest one.
+func (s *MetaBundle) GetMetaBundleSince(ctx context.Context, since int64) (*modelv3.MetaBundle, error) {
+	latest, err := s.GetLatestMetaBundle(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if since == latest.Version {
+		return nil, nil
+	}
+	if since <= 0 || since > latest.Version {
+		return latest, nil
+	}
+
+	content, err := s.Redis.Get(ctx, metaBundleContentPrefix+strconv.FormatInt(since, 10)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return latest, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var previous modelv3.Init
+	if err := pkgcache.UnmarshalPayload(content, &previous); err != nil {
+		return nil, err
+	}
+
+	delta := &modelv3.MetaBundle{
+		Version: latest.Version,
+		Removed: &modelv3.MetaBundleRemoved{},
+	}
+	if delta.Items, delta.Removed.Items, err = metaBundleDelta(previous.Items, latest.Items, func(item *modelv3.Item) string {
+		return item.ArkItemID
+	}); err != nil {
+		return nil, err
+	}
+	if delta.Stages, delta.Removed.Stages, err = metaBundleDelta(previous.Stages, latest.Stages, func(stage *modelv3.Stage) string {
+		return stage.ArkStageID
+	}); err != nil {
+		return nil, err
+	}
+	if delta.Zones, delta.Removed.Zones, err = metaBundleDelta(previous.Zones, latest.Zones, func(zone *modelv3.Zone) string {
+		return zone.ArkZoneID
+	}); err != nil {
+		return nil, err
+	}
+	return delta, nil
+}
+
+// calcLatestMetaBundle compares the current items, stages and zones with the latest version by their hash,
+// and records a new version when they differ
+func (s *MetaBundle) calcLatestMetaBundle(ctx context.Context) (*modelv3.MetaBundle, error) {
+	init, err := s.InitService.GetInit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal(init)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+
+	bundle := &modelv3.MetaBundle{
+		Full:   true,
+		Items:  init.Items,
+		Stages: init.Stages,
+		Zones:  init.Zones,
+	}
+
+	latest, err := s.Redis.HMGet(ctx, metaBundleLatestKey, "version", "hash").Result()
+	if err != nil {
+		return nil, err
+	}
+	if latestHash, ok := latest[1].(string); ok && latestHash == hash {
+		if bundle.Version, err = strconv.ParseInt(latest[0].(string), 10, 64); err != nil {
+			return nil, err
+		}
+		return bundle, nil
+	}
+
+	bundle.Version, err = s.Redis.Incr(ctx, metaBundleVersionKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Redis.Set(ctx, metaBundleContentPrefix+strconv.FormatInt(bundle.Version, 10), pkgcache.EncodePayload(content), metaBundleRetention).Err(); err != nil {
+		return nil, err
+	}
+	if err := metaBundleSetLatest.Run(ctx, s.Redis, []string{metaBundleLatestKey}, bundle.Version, hash).Err(); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// metaBundleDelta returns the elements of current that are new or differ from the ones of previous,
+// and the ids of the elements of previous that are no longer present
+func metaBundleDelta[T any](previous, current []*T, id func(*T) string) (changed []*T, removed []string, err error) {
+	previousById := make(map[string][]byte, len(previous))
+	for _, el := range previous {
+		b, err := json.Marshal(el)
+		if err != nil {
+			return nil, nil, err
+		}
+		previousById[id(el)] = b
+	}
+
+	changed = make([]*T, 0)
+	present := make(map[string]struct{}, len(current))
+	for _, el := range current {
+		present[id(el)] = struct{}{}
+		b, err := json.Marshal(el)
+		if err != nil {
+			return nil, nil, err
+		}
+		if prev, ok := previousById[id(el)]; !ok || !bytes.Equal(prev, b) {
+			changed = append(changed, el)
+		}
+	}
+
+	removed = make([]string, 0)
+	for _, el := range previous {
+		if _, ok := present[id(el)]; !ok {
+			removed = append(removed, id(el))
+		}
+	}
+	return changed, removed, nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/uptrace/bun"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// MetadataSnapshot records versioned snapshots of items, zones, stages, time ranges and drop infos
+// before they are mutated via the admin APIs, and restores them on rollback.
+type MetadataSnapshot struct {
+	DB              *bun.DB
+	AdminRepo       *repo.Admin
+	SnapshotRepo    *repo.Snapshot
+	SnapshotService *Snapshot
+}
+
+func NewMetadataSnapshot(db *bun.DB, adminRepo *repo.Admin, snapshotRepo *repo.Snapshot, snapshotService *Snapshot) *MetadataSnapshot {
+	return &MetadataSnapshot{
+		DB:              db,
+		AdminRepo:       adminRepo,
+		SnapshotRepo:    snapshotRepo,
+		SnapshotService: snapshotService,
+	}
+}
+
+// Capture records the current metadata as a snapshot. Nothing is recorded when the metadata
+// is identical to the latest snapshot, in which case the latest snapshot is returned.
+func (s *MetadataSnapshot) Capture(ctx context.Context, reason string) (*model.Snapshot, error) {
+	content, err := s.AdminRepo.GetMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	contentBytes, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	version := s.SnapshotService.CalculateVersion(string(contentBytes))
+
+	latest, err := s.SnapshotRepo.GetLatestSnapshotByKey(ctx, model.MetadataSnapshotKey)
+	if err == nil && latest.Version == version {
+		return latest, nil
+	} else if err != nil && !errors.Is(err, pgerr.ErrNotFound) {
+		return nil, err
+	}
+
+	snapshot, err := s.SnapshotRepo.SaveSnapshot(ctx, &model.Snapshot{
+		Key:     model.MetadataSnapshotKey,
+		Version: version,
+		Content: string(contentBytes),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("evt.name", "metadata.snapshot.captured").
+		Int("snapshotId", snapshot.SnapshotID).
+		Str("version", version).
+		Str("reason", reason).
+		Msg("captured metadata snapshot")
+
+	return snapshot, nil
+}
+
+func (s *MetadataSnapshot) GetSnapshots(ctx context.Context, limit int) ([]*model.Snapshot, error) {
+	return s.SnapshotRepo.GetSnapshotsByKey(ctx, model.MetadataSnapshotKey, limit)
+}
+
+// Rollback restores the metadata to the given snapshot. The current metadata is captured beforehand,
+// so that a rollback can be rolled back as well.
+func (s *MetadataSnapshot) Rollback(ctx context.Context, snapshotId int) (*model.Snapshot, error) {
+	snapshot, err := s.SnapshotRepo.GetSnapshotById(ctx, snapshotId)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.Key != model.MetadataSnapshotKey {
+		return nil, pgerr.ErrInvalidReq.Msg("snapshot %d is not a metadata snapshot", snapshotId)
+	}
+
+	var content model.MetadataSnapshotContent
+	if err := json.Unmarshal([]byte(snapshot.Content), &content); err != nil {
+		return nil, errors.Wrap(err, "failed to decode metadata snapshot")
+	}
+
+	previous, err := s.Capture(ctx, "before rollback")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to capture metadata before rollback")
+	}
+
+	err = s.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return s.AdminRepo.RestoreMetadata(ctx, tx, &content)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	purgeItemCaches()
+	purgeEventMetadataCaches(constant.Servers)
+
+	log.Info().
+		Str("evt.name", "metadata.snapshot.rolledback").
+		Int("snapshotId", snapshot.SnapshotID).
+		Int("previousSnapshotId", previous.SnapshotID).
+		Msg("rolled back metadata to snapshot")
+
+	return previous, nil
+}
@@ -4,8 +4,16 @@ import (
 	"context"
 	"time"
 
+	"exusiai.dev/gommon/constant"
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+	"github.com/tidwall/gjson"
+
 	"exusiai.dev/backend-next/internal/model"
 	"exusiai.dev/backend-next/internal/model/cache"
+	"exusiai.dev/backend-next/internal/model/types"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
 	"exusiai.dev/backend-next/internal/repo"
 )
 
@@ -19,6 +27,7 @@ func NewNotice(noticeRepo *repo.Notice) *Notice {
 	}
 }
 
+// GetNotices returns the notices of which the display window includes now
 // Cache: (singular) notices, 10 seconds; records last modified time
 func (s *Notice) GetNotices(ctx context.Context) ([]*model.Notice, error) {
 	var noticesFromCache []*model.Notice
@@ -27,7 +36,7 @@ func (s *Notice) GetNotices(ctx context.Context) ([]*model.Notice, error) {
 		return noticesFromCache, nil
 	}
 
-	notices, err := s.NoticeRepo.GetNotices(ctx)
+	notices, err := s.NoticeRepo.GetActiveNotices(ctx, time.Now())
 	if err != nil {
 		return nil, err
 	}
@@ -35,3 +44,112 @@ func (s *Notice) GetNotices(ctx context.Context) ([]*model.Notice, error) {
 	cache.LastModifiedTime.Set("[notices]", time.Now(), 0)
 	return notices, err
 }
+
+// GetV3Notices returns the notices active now in the server, or in any server when server is empty. A notice having
+// no existence is shown in every server.
+func (s *Notice) GetV3Notices(ctx context.Context, server string) ([]*modelv3.Notice, error) {
+	notices, err := s.GetNotices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	results := make([]*modelv3.Notice, 0, len(notices))
+	for _, notice := range notices {
+		// the cache lasts a little after the end of the display window
+		if !notice.IsActiveAt(now) {
+			continue
+		}
+		if server != "" && len(notice.Existence) > 0 && gjson.ValidBytes(notice.Existence) {
+			existence := gjson.ParseBytes(notice.Existence)
+			if existence.IsObject() && !existence.Get(server+".exist").Bool() {
+				continue
+			}
+		}
+		results = append(results, &modelv3.Notice{
+			ID:        notice.NoticeID,
+			Kind:      notice.Kind,
+			Severity:  notice.Severity,
+			Content:   notice.Content,
+			StartTime: notice.StartTime,
+			EndTime:   notice.EndTime,
+			UpdatedAt: notice.UpdatedAt,
+		})
+	}
+	return results, nil
+}
+
+// GetAllNotices returns every notice, including those out of their display windows
+func (s *Notice) GetAllNotices(ctx context.Context) ([]*model.Notice, error) {
+	return s.NoticeRepo.GetNotices(ctx)
+}
+
+// SaveNotice creates the notice when noticeId is 0, or replaces the notice of noticeId otherwise
+func (s *Notice) SaveNotice(ctx context.Context, noticeId int, req *types.SaveNoticeRequest) (*model.Notice, error) {
+	if req.StartTime != nil && req.EndTime != nil && !req.EndTime.After(*req.StartTime) {
+		return nil, pgerr.ErrInvalidReq.Msg("endTime must be after startTime")
+	}
+	if noticeId != 0 {
+		if _, err := s.NoticeRepo.GetNoticeById(ctx, noticeId); err != nil {
+			return nil, err
+		}
+	}
+
+	content, err := json.Marshal(req.Content)
+	if err != nil {
+		return nil, err
+	}
+	// the existence is in the form of the other metadata, e.g. {"CN": {"exist": true}}
+	existence := make(map[string]types.ServerExistence, len(constant.Servers))
+	for _, server := range constant.Servers {
+		existence[server] = types.ServerExistence{Exist: len(req.Servers) == 0}
+	}
+	for _, server := range req.Servers {
+		existence[server] = types.ServerExistence{Exist: true}
+	}
+	existenceJson, err := json.Marshal(existence)
+	if err != nil {
+		return nil, err
+	}
+
+	notice := &model.Notice{
+		NoticeID:  noticeId,
+		Existence: existenceJson,
+		Severity:  req.Severity,
+		Content:   content,
+		Kind:      req.Kind,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+	}
+	if err := s.NoticeRepo.SaveNotice(ctx, notice); err != nil {
+		return nil, err
+	}
+	cache.Notices.Delete()
+	cache.UIConfigs.Flush()
+
+	log.Info().
+		Str("evt.name", "admin.notice.saved").
+		Int("noticeId", notice.NoticeID).
+		Str("kind", notice.Kind).
+		Msg("notice saved")
+
+	return notice, nil
+}
+
+func (s *Notice) DeleteNotice(ctx context.Context, noticeId int) error {
+	if _, err := s.NoticeRepo.GetNoticeById(ctx, noticeId); err != nil {
+		return err
+	}
+	if err := s.NoticeRepo.DeleteNotice(ctx, noticeId); err != nil {
+		return err
+	}
+	cache.Notices.Delete()
+	cache.UIConfigs.Flush()
+
+	log.Info().
+		Str("evt.name", "admin.notice.deleted").
+		Int("noticeId", noticeId).
+		Msg("notice deleted")
+
+	return nil
+}
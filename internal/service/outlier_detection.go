@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/pkg/observability"
+	"exusiai.dev/backend-next/internal/pkg/stattest"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// outlierMinExpected is the quantity of an item a reporter is expected to have reported at least for the item to be
+// tested, below which the chi-square approximation does not hold
+const outlierMinExpected = 5
+
+// OutlierDetection screens the high-volume reporters of every stage for drop rates diverging from those of the other
+// reporters of the stage, as a periodic singleton background job. The quantity of every item a reporter has reported
+// is tested against the quantity expected from the drop rate of the other reporters by a chi-square test, and the
+// reporters diverging beyond the significance level are temporarily excluded from the aggregations of the stage.
+type OutlierDetection struct {
+	Config               *appconfig.Config
+	DropReportRepo       *repo.DropReport
+	StageService         *Stage
+	ExclusionRuleService *ExclusionRule
+}
+
+func NewOutlierDetection(conf *appconfig.Config, dropReportRepo *repo.DropReport, stageService *Stage, exclusionRuleService *ExclusionRule, jobs *Jobs) *OutlierDetection {
+	s := &OutlierDetection{
+		Config:               conf,
+		DropReportRepo:       dropReportRepo,
+		StageService:         stageService,
+		ExclusionRuleService: exclusionRuleService,
+	}
+	if conf.OutlierDetectionInterval > 0 {
+		jobs.Register(model.JobKindOutlierDetection, JobPolicy{
+			MaxAttempts: 2,
+			Backoff:     time.Minute * 10,
+			Timeout:     time.Minute * 30,
+			Every:       conf.OutlierDetectionInterval,
+			Singleton:   true,
+		}, s.detect)
+	}
+	return s
+}
+
+// outlierScreening is the chi-square test of the rates of a reporter of a stage against the other reporters
+type outlierScreening struct {
+	Server    string
+	StageID   int
+	AccountID int
+	Times     int
+	Statistic float64
+	Degrees   int
+	PValue    float64
+}
+
+func (s *OutlierDetection) detect(ctx context.Context, _ *model.Job) error {
+	since := time.Now().Add(-s.Config.OutlierDetectionWindow)
+	reporters, err := s.DropReportRepo.CalcHighVolumeReporters(ctx, since, s.Config.OutlierMinTimes)
+	if err != nil {
+		return err
+	}
+
+	type serverStage struct {
+		server  string
+		stageId int
+	}
+	reportersByStage := lo.GroupBy(reporters, func(reporter *model.ReporterTimesResult) serverStage {
+		return serverStage{server: reporter.Server, stageId: reporter.StageID}
+	})
+
+	screenings := make([]*outlierScreening, 0, len(reporters))
+	for stage, stageReporters := range reportersByStage {
+		accountIds := lo.Map(stageReporters, func(reporter *model.ReporterTimesResult, _ int) int {
+			return reporter.AccountID
+		})
+		times, err := s.DropReportRepo.CalcStageTimesByAccounts(ctx, stage.server, stage.stageId, since, accountIds)
+		if err != nil {
+			return err
+		}
+		quantities, err := s.DropReportRepo.CalcStageItemQuantitiesByAccounts(ctx, stage.server, stage.stageId, since, accountIds)
+		if err != nil {
+			return err
+		}
+
+		for _, screening := range screenReporters(times, quantities) {
+			screening.Server, screening.StageID = stage.server, stage.stageId
+			screenings = append(screenings, screening)
+		}
+	}
+
+	// the significance level is split among the reporters screened, so that it bounds the probability of any of them
+	// being wrongly excluded
+	var threshold float64
+	if len(screenings) > 0 {
+		threshold = s.Config.OutlierSignificance / float64(len(screenings))
+	}
+	outliers := lo.Filter(screenings, func(screening *outlierScreening, _ int) bool {
+		return screening.PValue < threshold
+	})
+	sort.Slice(outliers, func(i, j int) bool {
+		return outliers[i].PValue < outliers[j].PValue
+	})
+
+	if len(outliers) > 0 {
+		stages, err := s.StageService.GetStagesMapById(ctx)
+		if err != nil {
+			return err
+		}
+		for _, outlier := range outliers {
+			if err := s.exclude(ctx, outlier, stages[outlier.StageID], since); err != nil {
+				return err
+			}
+		}
+	}
+
+	log.Info().
+		Str("evt.name", "report.outlier.screened").
+		Int("screened", len(screenings)).
+		Int("excluded", len(outliers)).
+		Float64("threshold", threshold).
+		Msg("high-volume reporters screened for outliers")
+
+	return nil
+}
+
+// exclude excludes the reports of the outlier reporter of the stage created since the start of the screening window
+// from the aggregations for OutlierExclusionDuration, by an exclusion rule that keeps excluding the reports it
+// submits in the meantime
+func (s *OutlierDetection) exclude(ctx context.Context, outlier *outlierScreening, stage *model.Stage, since time.Time) error {
+	arkStageId := fmt.Sprintf("stage %d", outlier.StageID)
+	if stage != nil {
+		arkStageId = stage.ArkStageID
+	}
+	expiresAt := time.Now().Add(s.Config.OutlierExclusionDuration)
+
+	result, err := s.ExclusionRuleService.SaveRule(ctx, &model.ExclusionRule{
+		Name: fmt.Sprintf("outlier: account %d on %s", outlier.AccountID, arkStageId),
+		Comment: null.StringFrom(fmt.Sprintf(
+			"excluded automatically for the drop rates diverging from the other reporters over %d times: chi-square %.1f with %d degrees of freedom, p = %.3g",
+			outlier.Times, outlier.Statistic, outlier.Degrees, outlier.PValue)),
+		Enabled:   true,
+		Server:    null.StringFrom(outlier.Server),
+		AccountID: null.IntFrom(int64(outlier.AccountID)),
+		StageID:   null.IntFrom(int64(outlier.StageID)),
+		StartTime: &since,
+		ExpiresAt: &expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+	observability.OutlierReportersExcluded.WithLabelValues(outlier.Server).Inc()
+
+	log.Warn().
+		Str("evt.name", "report.outlier.excluded").
+		Str("server", outlier.Server).
+		Str("arkStageId", arkStageId).
+		Int("accountId", outlier.AccountID).
+		Int("ruleId", result.Rule.RuleID).
+		Float64("statistic", outlier.Statistic).
+		Int("degrees", outlier.Degrees).
+		Float64("pValue", outlier.PValue).
+		Msg("outlier reporter excluded from the aggregations of the stage pending review")
+
+	return nil
+}
+
+// screenReporters tests the item quantities of every reporter of a stage against those expected from the drop rates
+// of the other reporters, where account 0 holds the times and the quantities of every reporter not screened. Each
+// item a reporter is expected enough of adds a degree of freedom; the reporters with the other reporters having
+// cleared the stage fewer times than themselves are not screened, for lack of a reference.
+func screenReporters(times []*model.ReporterTimesResult, quantities []*model.ReporterItemQuantityResult) []*outlierScreening {
+	totalTimes := 0
+	for _, t := range times {
+		totalTimes += t.Times
+	}
+	totalQuantities := make(map[int]int)
+	quantitiesByAccount := make(map[int]map[int]int)
+	for _, q := range quantities {
+		totalQuantities[q.ItemID] += q.Quantity
+		if quantitiesByAccount[q.AccountID] == nil {
+			quantitiesByAccount[q.AccountID] = make(map[int]int)
+		}
+		quantitiesByAccount[q.AccountID][q.ItemID] = q.Quantity
+	}
+	itemIds := lo.Keys(totalQuantities)
+	sort.Ints(itemIds)
+
+	screenings := make([]*outlierScreening, 0, len(times))
+	for _, t := range times {
+		otherTimes := totalTimes - t.Times
+		if t.AccountID == 0 || otherTimes < t.Times {
+			continue
+		}
+
+		screening := &outlierScreening{AccountID: t.AccountID, Times: t.Times}
+		for _, itemId := range itemIds {
+			observed := quantitiesByAccount[t.AccountID][itemId]
+			expected := float64(totalQuantities[itemId]-observed) / float64(otherTimes) * float64(t.Times)
+			if expected < outlierMinExpected {
+				continue
+			}
+			diff := float64(observed) - expected
+			screening.Statistic += diff * diff / expected
+			screening.Degrees++
+		}
+		if screening.Degrees == 0 {
+			continue
+		}
+		screening.PValue = stattest.ChiSquareSurvival(screening.Statistic, screening.Degrees)
+		screenings = append(screenings, screening)
+	}
+	return screenings
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+
+	"exusiai.dev/backend-next/internal/model"
+)
+
+func TestScreenReporters(t *testing.T) {
+	times := []*model.ReporterTimesResult{
+		{AccountID: 0, Times: 10000},
+		{AccountID: 1, Times: 1000},
+		{AccountID: 2, Times: 1000},
+		// the other reporters have cleared the stage fewer times than this one, which is therefore not screened
+		{AccountID: 3, Times: 20000},
+	}
+	quantities := []*model.ReporterItemQuantityResult{
+		// the others drop item 100 at 0.2 and item 101 at 0.02 per time; item 102 is never expected enough of
+		{AccountID: 0, ItemID: 100, Quantity: 2000},
+		{AccountID: 0, ItemID: 101, Quantity: 200},
+		{AccountID: 0, ItemID: 102, Quantity: 1},
+		// reports in line with the others
+		{AccountID: 1, ItemID: 100, Quantity: 195},
+		{AccountID: 1, ItemID: 101, Quantity: 22},
+		// reports item 100 at twice its rate
+		{AccountID: 2, ItemID: 100, Quantity: 400},
+		{AccountID: 2, ItemID: 101, Quantity: 20},
+		{AccountID: 3, ItemID: 100, Quantity: 4000},
+		{AccountID: 3, ItemID: 101, Quantity: 400},
+	}
+
+	screenings := screenReporters(times, quantities)
+	if len(screenings) != 2 {
+		t.Fatalf("screened %d reporters, want 2", len(screenings))
+	}
+	for _, screening := range screenings {
+		if screening.Degrees != 2 {
+			t.Errorf("account %d: tested %d items, want 2", screening.AccountID, screening.Degrees)
+		}
+		switch screening.AccountID {
+		case 1:
+			if screening.PValue < 0.05 {
+				t.Errorf("account 1: p = %v, want it not significant", screening.PValue)
+			}
+		case 2:
+			if screening.PValue > 1e-12 {
+				t.Errorf("account 2: p = %v, want it highly significant", screening.PValue)
+			}
+		default:
+			t.Errorf("account %d should not have been screened", screening.AccountID)
+		}
+	}
+}
@@ -2,11 +2,12 @@ package service
 
 import (
 	"context"
+	"math"
+	"sort"
 	"strconv"
 	"time"
 
 	"exusiai.dev/gommon/constant"
-	"github.com/ahmetb/go-linq/v3"
 	"github.com/samber/lo"
 	"gopkg.in/guregu/null.v3"
 
@@ -14,6 +15,7 @@ import (
 	"exusiai.dev/backend-next/internal/model"
 	"exusiai.dev/backend-next/internal/model/cache"
 	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
 	"exusiai.dev/backend-next/internal/util"
 )
 
@@ -26,6 +28,9 @@ type PatternMatrix struct {
 	DropPatternElementService   *DropPatternElement
 	StageService                *Stage
 	ItemService                 *Item
+	Tunables                    *Tunables
+	CacheTTLOverrides           *CacheTTLOverrides
+	RetentionService            *Retention
 }
 
 func NewPatternMatrix(
@@ -37,6 +42,9 @@ func NewPatternMatrix(
 	dropPatternElementService *DropPatternElement,
 	stageService *Stage,
 	itemService *Item,
+	tunables *Tunables,
+	cacheTTLOverrides *CacheTTLOverrides,
+	retentionService *Retention,
 ) *PatternMatrix {
 	return &PatternMatrix{
 		Config:                      config,
@@ -47,12 +55,15 @@ func NewPatternMatrix(
 		DropPatternElementService:   dropPatternElementService,
 		StageService:                stageService,
 		ItemService:                 itemService,
+		Tunables:                    tunables,
+		CacheTTLOverrides:           cacheTTLOverrides,
+		RetentionService:            retentionService,
 	}
 }
 
 // =========== Global & Personal, Latest Timeranges ===========
 
-// Cache: shimGlobalPatternMatrix#server|sourceCategory|showAllPatterns:{server}|{sourceCategory}|{showAllPatterns}, 24hrs, records last modified time
+// Cache: shimGlobalPatternMatrix#server|sourceCategory|showAllPatterns:{server}|{sourceCategory}|{showAllPatterns}, 24hrs (tunable, overridable), records last modified time
 // Called by frontend, used for both global and personal, only for latest timeranges
 func (s *PatternMatrix) GetShimPatternMatrix(ctx context.Context, server string, accountId null.Int, sourceCategory string, showAllPatterns bool,
 ) (*modelv2.PatternMatrixQueryResult, error) {
@@ -84,7 +95,7 @@ func (s *PatternMatrix) GetShimPatternMatrix(ctx context.Context, server string,
 	var results modelv2.PatternMatrixQueryResult
 	if !accountId.Valid {
 		key := server + constant.CacheSep + sourceCategory + constant.CacheSep + strconv.FormatBool(showAllPatterns)
-		calculated, err := cache.ShimGlobalPatternMatrix.MutexGetSet(key, &results, valueFunc, 24*time.Hour)
+		calculated, err := cache.ShimGlobalPatternMatrix.MutexGetSet(key, &results, valueFunc, s.GlobalCacheTTL(server))
 		if err != nil {
 			return nil, err
 		} else if calculated {
@@ -96,6 +107,69 @@ func (s *PatternMatrix) GetShimPatternMatrix(ctx context.Context, server string,
 	}
 }
 
+// GlobalCacheTTL returns how long the global pattern matrices of the server are cached for
+func (s *PatternMatrix) GlobalCacheTTL(server string) time.Duration {
+	return s.CacheTTLOverrides.TTL(server, nil, s.Tunables.Duration(TunableGlobalMatrixCacheTTL))
+}
+
+// GetStageDropStats calculates the average and the standard deviation of the number of items dropped per run of each
+// stage from the global pattern matrix of all patterns, as every run is reported with exactly one of the patterns
+func (s *PatternMatrix) GetStageDropStats(ctx context.Context, server string) (*modelv3.StageDropStats, error) {
+	patternMatrix, err := s.GetShimPatternMatrix(ctx, server, null.NewInt(0, false), constant.SourceCategoryAll, true)
+	if err != nil {
+		return nil, err
+	}
+
+	type stageKey struct {
+		stageId   string
+		startTime int64
+	}
+	elementsByStage := make(map[stageKey][]*modelv2.OnePatternMatrixElement)
+	keys := make([]stageKey, 0)
+	for _, el := range patternMatrix.PatternMatrix {
+		key := stageKey{stageId: el.StageID, startTime: el.StartTime}
+		if _, ok := elementsByStage[key]; !ok {
+			keys = append(keys, key)
+		}
+		elementsByStage[key] = append(elementsByStage[key], el)
+	}
+
+	results := &modelv3.StageDropStats{
+		Stages: make([]*modelv3.OneStageDropStats, 0, len(keys)),
+	}
+	for _, key := range keys {
+		elements := elementsByStage[key]
+		times := elements[0].Times
+		if times <= 0 {
+			continue
+		}
+
+		totals := make([]int, len(elements))
+		var sum float64
+		for i, el := range elements {
+			for _, drop := range el.Pattern.Drops {
+				totals[i] += drop.Quantity
+			}
+			sum += float64(totals[i] * el.Quantity)
+		}
+		avg := sum / float64(times)
+		var squares float64
+		for i, el := range elements {
+			squares += float64(el.Quantity) * math.Pow(float64(totals[i])-avg, 2)
+		}
+
+		results.Stages = append(results.Stages, &modelv3.OneStageDropStats{
+			StageID:   key.stageId,
+			Times:     times,
+			Avg:       util.RoundFloat64(avg, util.DropRatePrecision),
+			StdDev:    util.RoundFloat64(math.Sqrt(squares/float64(times)), constant.StdDevDigits),
+			StartTime: key.startTime,
+			EndTime:   elements[0].EndTime,
+		})
+	}
+	return results, nil
+}
+
 // =========== Global ===========
 
 // Calc today's pattern matrix elements and save to DB
@@ -103,7 +177,7 @@ func (s *PatternMatrix) GetShimPatternMatrix(ctx context.Context, server string,
 func (s *PatternMatrix) RunCalcPatternMatrixJob(ctx context.Context, server string) error {
 	date := time.Now()
 	endTime := time.Now()
-	patternMatrixElements, err := s.calcPatternMatrixByGivenDate(ctx, server, &date, &endTime, s.Config.MatrixWorkerSourceCategories)
+	patternMatrixElements, err := s.calcPatternMatrixByGivenDate(ctx, server, &date, &endTime, nil, s.Config.MatrixWorkerSourceCategories)
 	if err != nil {
 		return err
 	}
@@ -121,7 +195,7 @@ func (s *PatternMatrix) RunCalcPatternMatrixJob(ctx context.Context, server stri
 	// If this is the first time we run the job for this server at this day, we need to update the pattern matrix for the previous day.
 	if !exists {
 		yesterday := date.Add(time.Hour * -24)
-		patternMatrixElementsForYesterday, err := s.calcPatternMatrixByGivenDate(ctx, server, &yesterday, nil, s.Config.MatrixWorkerSourceCategories)
+		patternMatrixElementsForYesterday, err := s.calcPatternMatrixByGivenDate(ctx, server, &yesterday, nil, nil, s.Config.MatrixWorkerSourceCategories)
 		if err != nil {
 			return err
 		}
@@ -131,6 +205,10 @@ func (s *PatternMatrix) RunCalcPatternMatrixJob(ctx context.Context, server stri
 		}
 	}
 
+	return s.purgeGlobalPatternMatrixCaches(server)
+}
+
+func (s *PatternMatrix) purgeGlobalPatternMatrixCaches(server string) error {
 	for _, sourceCategory := range s.Config.MatrixWorkerSourceCategories {
 		for _, showAllPatterns := range []bool{true, false} {
 			key := server + constant.CacheSep + sourceCategory + constant.CacheSep + strconv.FormatBool(showAllPatterns)
@@ -145,15 +223,25 @@ func (s *PatternMatrix) RunCalcPatternMatrixJob(ctx context.Context, server stri
 	return nil
 }
 
-// Update pattern matrix elements for a given date (entire day)
-// Called by admin api
-func (s *PatternMatrix) UpdatePatternMatrixByGivenDate(ctx context.Context, server string, date *time.Time) error {
-	patternMatrixElements, err := s.calcPatternMatrixByGivenDate(ctx, server, date, nil, s.Config.MatrixWorkerSourceCategories)
+// Update pattern matrix elements for a given date (entire day), only those of stageIds unless it is empty
+// Called by admin api and refresh jobs
+func (s *PatternMatrix) UpdatePatternMatrixByGivenDate(ctx context.Context, server string, date *time.Time, stageIds []int) error {
+	// the elements of the day are replaced as a whole, which would lose the counts of the purged reports
+	if err := s.RetentionService.CheckNotPurged(time.UnixMilli(util.GetDayStartTime(date, server))); err != nil {
+		return err
+	}
+	patternMatrixElements, err := s.calcPatternMatrixByGivenDate(ctx, server, date, nil, stageIds, s.Config.MatrixWorkerSourceCategories)
 	if err != nil {
 		return err
 	}
 	dayNum := util.GetDayNum(date, server)
-	s.PatternMatrixElementService.DeleteByServerAndDayNum(ctx, server, dayNum)
+	if len(stageIds) > 0 {
+		if err := s.PatternMatrixElementService.DeleteByServerAndDayNumAndStageIds(ctx, server, dayNum, stageIds); err != nil {
+			return err
+		}
+	} else {
+		s.PatternMatrixElementService.DeleteByServerAndDayNum(ctx, server, dayNum)
+	}
 	if len(patternMatrixElements) != 0 {
 		s.PatternMatrixElementService.BatchSaveElements(ctx, patternMatrixElements, server)
 	}
@@ -161,7 +249,7 @@ func (s *PatternMatrix) UpdatePatternMatrixByGivenDate(ctx context.Context, serv
 }
 
 func (s *PatternMatrix) calcPatternMatrixByGivenDate(
-	ctx context.Context, server string, date *time.Time, endTime *time.Time, sourceCategories []string,
+	ctx context.Context, server string, date *time.Time, endTime *time.Time, onlyStageIds []int, sourceCategories []string,
 ) ([]*model.PatternMatrixElement, error) {
 	start := time.UnixMilli(util.GetDayStartTime(date, server))
 	startNextDay := start.Add(time.Hour * 24)
@@ -193,10 +281,10 @@ func (s *PatternMatrix) calcPatternMatrixByGivenDate(
 		}
 
 		// exclude some stages (gachabox, recruit) before calc
-		linq.From(stageIds).WhereT(func(stageId int) bool {
+		stageIds = lo.Filter(stageIds, func(stageId int, _ int) bool {
 			_, ok := excludeStageIdsSet[stageId]
-			return !ok
-		}).ToSlice(&stageIds)
+			return !ok && (len(onlyStageIds) == 0 || lo.Contains(onlyStageIds, stageId))
+		})
 		if len(stageIds) == 0 {
 			continue
 		}
@@ -284,10 +372,10 @@ func (s *PatternMatrix) getLatestPatternMatrixElements(ctx context.Context, serv
 	elements := make([]*model.PatternMatrixElement, 0)
 	for rangeId, stageIds := range stageIdsMap {
 		// exclude some stages (gachabox, recruit) before calc
-		linq.From(stageIds).WhereT(func(stageId int) bool {
+		stageIds = lo.Filter(stageIds, func(stageId int, _ int) bool {
 			_, ok := excludeStageIdsSet[stageId]
 			return !ok
-		}).ToSlice(&stageIds)
+		})
 		if len(stageIds) == 0 {
 			continue
 		}
@@ -324,6 +412,71 @@ func (s *PatternMatrix) convertPatternMatrixElementsToDropPatternQueryResult(ctx
 	return result, nil
 }
 
+// =========== Customized ===========
+
+func (s *PatternMatrix) GetShimCustomizedPatternMatrixResults(
+	ctx context.Context, server string, timeRange *model.TimeRange, stageIds []int, accountId null.Int, sourceCategory string,
+) (*modelv2.PatternMatrixQueryResult, error) {
+	patternMatrixElements, err := s.calcPatternMatrixForTimeRanges(ctx, server, []*model.TimeRange{timeRange}, stageIds, accountId, sourceCategory)
+	if err != nil {
+		return nil, err
+	}
+	result := &model.PatternMatrixQueryResult{
+		PatternMatrix: make([]*model.OnePatternMatrixElement, 0, len(patternMatrixElements)),
+	}
+	for _, patternMatrixElement := range patternMatrixElements {
+		result.PatternMatrix = append(result.PatternMatrix, &model.OnePatternMatrixElement{
+			StageID:   patternMatrixElement.StageID,
+			PatternID: patternMatrixElement.PatternID,
+			Quantity:  patternMatrixElement.Quantity,
+			Times:     patternMatrixElement.Times,
+			TimeRange: timeRange,
+		})
+	}
+	return s.applyShimForPatternMatrixQuery(ctx, result)
+}
+
+// Cache: shimRecentPatternMatrix#server|days|sourceCategory|showAllPatterns:{server}|{days}|{sourceCategory}|{showAllPatterns}, 5 mins (tunable)
+// GetShimRecentPatternMatrix calculates the pattern matrix of the reports of the last days, rather than of the latest
+// time ranges of the stages. The window ends at the start of the current hour, as the one of the drop matrix does.
+func (s *PatternMatrix) GetShimRecentPatternMatrix(ctx context.Context, server string, days int, accountId null.Int, sourceCategory string, showAllPatterns bool,
+) (*modelv2.PatternMatrixQueryResult, error) {
+	valueFunc := func() (*modelv2.PatternMatrixQueryResult, error) {
+		timeRange := util.RecentTimeRange(time.Now(), days)
+		patternMatrixElements, err := s.calcPatternMatrixForTimeRanges(ctx, server, []*model.TimeRange{timeRange}, nil, accountId, sourceCategory)
+		if err != nil {
+			return nil, err
+		}
+		patternMatrix := make([]*model.OnePatternMatrixElement, 0, len(patternMatrixElements))
+		for _, patternMatrixElement := range patternMatrixElements {
+			patternMatrix = append(patternMatrix, &model.OnePatternMatrixElement{
+				StageID:   patternMatrixElement.StageID,
+				PatternID: patternMatrixElement.PatternID,
+				Quantity:  patternMatrixElement.Quantity,
+				Times:     patternMatrixElement.Times,
+				TimeRange: timeRange,
+			})
+		}
+		if !showAllPatterns {
+			patternMatrix, err = s.interceptPatternMatrixResults(patternMatrix, s.Config.PatternMatrixLimit)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return s.applyShimForPatternMatrixQuery(ctx, &model.PatternMatrixQueryResult{PatternMatrix: patternMatrix})
+	}
+
+	if accountId.Valid {
+		return valueFunc()
+	}
+	var results modelv2.PatternMatrixQueryResult
+	key := server + constant.CacheSep + strconv.Itoa(days) + constant.CacheSep + sourceCategory + constant.CacheSep + strconv.FormatBool(showAllPatterns)
+	if _, err := cache.ShimRecentPatternMatrix.MutexGetSet(key, &results, valueFunc, s.Tunables.Duration(TunableCustomizedCacheTTL)); err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
+
 // =========== Helpers ===========
 
 // Called by both global and personal
@@ -382,37 +535,19 @@ func (s *PatternMatrix) calcPatternMatrixForTimeRanges(
 func (s *PatternMatrix) combineQuantityAndTimesResults(
 	quantityResults []*model.TotalQuantityResultForPatternMatrix, timesResults []*model.TotalTimesResult,
 ) []*model.CombinedResultForDropPattern {
-	var firstGroupResults []linq.Group
 	combinedResults := make([]*model.CombinedResultForDropPattern, 0)
-	linq.From(quantityResults).
-		GroupByT(
-			func(result *model.TotalQuantityResultForPatternMatrix) int { return result.StageID },
-			func(result *model.TotalQuantityResultForPatternMatrix) *model.TotalQuantityResultForPatternMatrix {
-				return result
-			}).
-		ToSlice(&firstGroupResults)
 	quantityResultsMap := make(map[int]map[int]int)
-	for _, firstGroupElements := range firstGroupResults {
-		stageId := firstGroupElements.Key.(int)
-		resultsMap := make(map[int]int)
-		linq.From(firstGroupElements.Group).
-			ToMapByT(&resultsMap,
-				func(el any) int { return el.(*model.TotalQuantityResultForPatternMatrix).PatternID },
-				func(el any) int { return el.(*model.TotalQuantityResultForPatternMatrix).TotalQuantity })
-		quantityResultsMap[stageId] = resultsMap
-	}
-
-	var secondGroupResults []linq.Group
-	linq.From(timesResults).
-		GroupByT(
-			func(result *model.TotalTimesResult) int { return result.StageID },
-			func(result *model.TotalTimesResult) *model.TotalTimesResult { return result }).
-		ToSlice(&secondGroupResults)
-	for _, secondGroupResults := range secondGroupResults {
-		stageId := secondGroupResults.Key.(int)
+	for _, firstGroupElements := range util.GroupBy(quantityResults, func(result *model.TotalQuantityResultForPatternMatrix) int { return result.StageID }) {
+		quantityResultsMap[firstGroupElements.Key] = util.ToMap(firstGroupElements.Values,
+			func(el *model.TotalQuantityResultForPatternMatrix) int { return el.PatternID },
+			func(el *model.TotalQuantityResultForPatternMatrix) int { return el.TotalQuantity })
+	}
+
+	for _, secondGroupResults := range util.GroupBy(timesResults, func(result *model.TotalTimesResult) int { return result.StageID }) {
+		stageId := secondGroupResults.Key
 		quantityResultsMapForOneStage := quantityResultsMap[stageId]
-		for _, el := range secondGroupResults.Group {
-			times := el.(*model.TotalTimesResult).TotalTimes
+		for _, el := range secondGroupResults.Values {
+			times := el.TotalTimes
 			for patternId, quantity := range quantityResultsMapForOneStage {
 				combinedResults = append(combinedResults, &model.CombinedResultForDropPattern{
 					StageID:   stageId,
@@ -463,42 +598,52 @@ func (s *PatternMatrix) applyShimForPatternMatrixQuery(ctx context.Context, quer
 		PatternMatrix: make([]*modelv2.OnePatternMatrixElement, 0),
 	}
 
-	itemsMapById, err := s.ItemService.GetItemsMapById(ctx)
+	stageIds := make([]int, 0, len(queryResult.PatternMatrix))
+	patternIds := make([]int, 0, len(queryResult.PatternMatrix))
+	for _, el := range queryResult.PatternMatrix {
+		stageIds = append(stageIds, el.StageID)
+		patternIds = append(patternIds, el.PatternID)
+	}
+	stagesMapById, err := s.StageService.GetStagesByIds(ctx, stageIds)
 	if err != nil {
 		return nil, err
 	}
-
-	stagesMapById, err := s.StageService.GetStagesMapById(ctx)
+	dropPatternElementsMap, err := s.DropPatternElementService.GetDropPatternElementsByPatternIds(ctx, patternIds)
+	if err != nil {
+		return nil, err
+	}
+	itemIds := make([]int, 0)
+	for _, dropPatternElements := range dropPatternElementsMap {
+		for _, el := range dropPatternElements {
+			itemIds = append(itemIds, el.ItemID)
+		}
+	}
+	itemsMapById, err := s.ItemService.GetItemsByIds(ctx, itemIds)
 	if err != nil {
 		return nil, err
 	}
 
-	var groupedResults []linq.Group
-	linq.From(queryResult.PatternMatrix).
-		GroupByT(
-			func(el *model.OnePatternMatrixElement) int { return el.PatternID },
-			func(el *model.OnePatternMatrixElement) *model.OnePatternMatrixElement { return el },
-		).ToSlice(&groupedResults)
-	for _, group := range groupedResults {
-		patternId := group.Key.(int)
-		for _, el := range group.Group {
-			oneDropPattern := el.(*model.OnePatternMatrixElement)
-			stage := stagesMapById[oneDropPattern.StageID]
-			dropPatternElements, err := s.DropPatternElementService.GetDropPatternElementsByPatternId(ctx, patternId)
-			if err != nil {
-				return nil, err
+	groups := util.GroupBy(queryResult.PatternMatrix, func(el *model.OnePatternMatrixElement) int { return el.PatternID })
+	for _, group := range groups {
+		patternId := group.Key
+		for _, oneDropPattern := range group.Values {
+			stage, ok := stagesMapById[oneDropPattern.StageID]
+			if !ok {
+				continue
 			}
+			dropPatternElements := dropPatternElementsMap[patternId]
 			// create pattern object from dropPatternElements
 			pattern := modelv2.Pattern{
 				PatternID: patternId,
 				Drops:     make([]*modelv2.OneDrop, 0),
 			}
-			linq.From(dropPatternElements).SortT(func(el1, el2 *model.DropPatternElement) bool {
-				item1 := itemsMapById[el1.ItemID]
-				item2 := itemsMapById[el2.ItemID]
-				return item1.SortID < item2.SortID
-			}).ToSlice(&dropPatternElements)
-			for _, dropPatternElement := range dropPatternElements {
+			// sorted on a copy, as the elements may come from the cache
+			sortedElements := make([]*model.DropPatternElement, len(dropPatternElements))
+			copy(sortedElements, dropPatternElements)
+			sort.SliceStable(sortedElements, func(i, j int) bool {
+				return itemsMapById[sortedElements[i].ItemID].SortID < itemsMapById[sortedElements[j].ItemID].SortID
+			})
+			for _, dropPatternElement := range sortedElements {
 				item := itemsMapById[dropPatternElement.ItemID]
 				pattern.Drops = append(pattern.Drops, &modelv2.OneDrop{
 					ItemID:   item.ArkItemID,
@@ -517,6 +662,7 @@ func (s *PatternMatrix) applyShimForPatternMatrixQuery(ctx context.Context, quer
 				StageID:   stage.ArkStageID,
 				Times:     oneDropPattern.Times,
 				Quantity:  oneDropPattern.Quantity,
+				Rate:      util.DropRate(oneDropPattern.Quantity, oneDropPattern.Times),
 				StartTime: oneDropPattern.TimeRange.StartTime.UnixMilli(),
 				EndTime:   endTime,
 				Pattern:   &pattern,
@@ -537,7 +683,7 @@ func (s *PatternMatrix) interceptPatternMatrixResults(onePatternMatrixElements [
 	}
 	// sort all elements by times desc, and limit the number of elements
 	for stageId, elements := range elementsMapByStageId {
-		linq.From(elements).OrderByDescendingT(func(el *model.OnePatternMatrixElement) int { return el.Times }).ToSlice(&elements)
+		sort.SliceStable(elements, func(i, j int) bool { return elements[i].Times > elements[j].Times })
 		if len(elements) > limit {
 			elementsMapByStageId[stageId] = elements[:limit]
 		}
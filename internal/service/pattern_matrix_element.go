@@ -25,6 +25,10 @@ func (s *PatternMatrixElement) DeleteByServerAndDayNum(ctx context.Context, serv
 	return s.PatternMatrixElementRepo.DeleteByServerAndDayNum(ctx, server, dayNum)
 }
 
+func (s *PatternMatrixElement) DeleteByServerAndDayNumAndStageIds(ctx context.Context, server string, dayNum int, stageIds []int) error {
+	return s.PatternMatrixElementRepo.DeleteByServerAndDayNumAndStageIds(ctx, server, dayNum, stageIds)
+}
+
 func (s *PatternMatrixElement) IsExistByServerAndDayNum(ctx context.Context, server string, dayNum int) (bool, error) {
 	return s.PatternMatrixElementRepo.IsExistByServerAndDayNum(ctx, server, dayNum)
 }
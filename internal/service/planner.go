@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"exusiai.dev/gommon/constant"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model/types"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/lp"
+)
+
+// planRunsEpsilon is how far below an integer the runs of a solution are rounded down instead of up, so that the
+// rounding errors of the solver do not add a run
+const planRunsEpsilon = 1e-6
+
+// Planner plans the cheapest runs of the stages to farm the items needed, as an authoritative replacement for the
+// solvers of the clients
+type Planner struct {
+	DropMatrixService *DropMatrix
+	StageService      *Stage
+	Tunables          *Tunables
+}
+
+func NewPlanner(dropMatrixService *DropMatrix, stageService *Stage, tunables *Tunables) *Planner {
+	return &Planner{
+		DropMatrixService: dropMatrixService,
+		StageService:      stageService,
+		Tunables:          tunables,
+	}
+}
+
+// Plan minimizes the sanity spent on the open stages of the server for the items requested to be expected to
+// drop, as a linear program over the sanity of the stages and their drop rates in the global drop matrix. Only the
+// stages reported enough times are planned on; the items none of them drops are returned as unreachable.
+func (s *Planner) Plan(ctx context.Context, req *types.PlanRequest) (*modelv3.Plan, error) {
+	matrix, err := s.DropMatrixService.GetShimDropMatrix(ctx, req.Server, false, "", "", null.NewInt(0, false), constant.SourceCategoryAll)
+	if err != nil {
+		return nil, err
+	}
+	stagesMap, err := s.StageService.GetStagesMapByArkId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	demands := make(map[string]int, len(req.Items))
+	for _, item := range req.Items {
+		demands[item.ItemID] += item.Quantity
+	}
+	excluded := make(map[string]struct{}, len(req.ExcludeStageIDs))
+	for _, stageId := range req.ExcludeStageIDs {
+		excluded[stageId] = struct{}{}
+	}
+
+	// the drop rates of the items demanded from each candidate stage, in its latest time range
+	minTimes := s.Tunables.Int(TunablePlanMinTimes)
+	latest := make(map[string]int64)
+	for _, el := range matrix.Matrix {
+		if el.StartTime > latest[el.StageID] {
+			latest[el.StageID] = el.StartTime
+		}
+	}
+	stageRates := make(map[string]map[string]float64)
+	for _, el := range matrix.Matrix {
+		if _, ok := demands[el.ItemID]; !ok || el.StartTime != latest[el.StageID] || el.Times < minTimes || el.Quantity == 0 {
+			continue
+		}
+		if _, ok := excluded[el.StageID]; ok {
+			continue
+		}
+		if stage, ok := stagesMap[el.StageID]; !ok || !stage.Sanity.Valid || stage.Sanity.Int64 <= 0 {
+			continue
+		}
+		if stageRates[el.StageID] == nil {
+			stageRates[el.StageID] = make(map[string]float64)
+		}
+		stageRates[el.StageID][el.ItemID] = float64(el.Quantity) / float64(el.Times)
+	}
+
+	plan := &modelv3.Plan{
+		Server:      req.Server,
+		Stages:      make([]*modelv3.PlannedStage, 0),
+		Items:       make([]*modelv3.PlannedItem, 0, len(demands)),
+		Unreachable: make([]string, 0),
+	}
+	itemIds := make([]string, 0, len(demands))
+	for itemId := range demands {
+		reachable := false
+		for _, rates := range stageRates {
+			if _, ok := rates[itemId]; ok {
+				reachable = true
+				break
+			}
+		}
+		if reachable {
+			itemIds = append(itemIds, itemId)
+		} else {
+			plan.Unreachable = append(plan.Unreachable, itemId)
+		}
+	}
+	sort.Strings(itemIds)
+	sort.Strings(plan.Unreachable)
+	stageIds := make([]string, 0, len(stageRates))
+	for stageId := range stageRates {
+		stageIds = append(stageIds, stageId)
+	}
+	sort.Strings(stageIds)
+
+	costs := make([]float64, len(stageIds))
+	rates := make([][]float64, len(stageIds))
+	for j, stageId := range stageIds {
+		costs[j] = float64(stagesMap[stageId].Sanity.Int64)
+		rates[j] = make([]float64, len(itemIds))
+		for i, itemId := range itemIds {
+			rates[j][i] = stageRates[stageId][itemId]
+		}
+	}
+	quantities := make([]float64, len(itemIds))
+	for i, itemId := range itemIds {
+		quantities[i] = float64(demands[itemId])
+	}
+
+	// every item left is dropped by some stage, so that the program is always feasible
+	runs, err := lp.SolveCovering(costs, rates, quantities)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := make(map[string]float64, len(itemIds))
+	for j, stageId := range stageIds {
+		n := int(math.Ceil(runs[j] - planRunsEpsilon))
+		if n <= 0 {
+			continue
+		}
+		sanity := n * int(costs[j])
+		plan.Sanity += sanity
+		plan.Stages = append(plan.Stages, &modelv3.PlannedStage{
+			StageID: stageId,
+			Runs:    n,
+			Sanity:  sanity,
+		})
+		for itemId, rate := range stageRates[stageId] {
+			expected[itemId] += float64(n) * rate
+		}
+	}
+	sort.Slice(plan.Stages, func(i, j int) bool {
+		return plan.Stages[i].Sanity > plan.Stages[j].Sanity
+	})
+
+	for _, item := range req.Items {
+		if _, ok := demands[item.ItemID]; !ok {
+			// listed already, as the item has been requested more than once
+			continue
+		}
+		plan.Items = append(plan.Items, &modelv3.PlannedItem{
+			ItemID:   item.ItemID,
+			Quantity: demands[item.ItemID],
+			Expected: expected[item.ItemID],
+		})
+		delete(demands, item.ItemID)
+	}
+	return plan, nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+)
+
+// queryCostAllItems is the number of items a query without an item filter is estimated to cost as, which is about
+// the number of items dropped by a stage
+const queryCostAllItems = 20
+
+// ErrQueryTooCostly is returned when the estimated cost of a customized query exceeds the budget. The cost and the
+// budget are attached as extras.
+var ErrQueryTooCostly = pgerr.New(fiber.StatusBadRequest, pgerr.CodeQueryTooCostly, "the query is too costly to run; please narrow down its time range, stages or items")
+
+// QueryCost estimates the cost of the customized queries, i.e. those of custom time ranges, as the number of days
+// spanned times the number of stages times the number of items, and rejects the ones above a budget before they
+// reach the database
+type QueryCost struct {
+	Tunables *Tunables
+}
+
+func NewQueryCost(tunables *Tunables) *QueryCost {
+	return &QueryCost{
+		Tunables: tunables,
+	}
+}
+
+// EstimateQueryCost estimates the cost of a query of the time range over the stages and items. No items stand
+// for a query without an item filter.
+func EstimateQueryCost(start, end time.Time, stageNum, itemNum int) int {
+	days := int(math.Ceil(end.Sub(start).Hours() / 24))
+	if days < 1 {
+		days = 1
+	}
+	if stageNum < 1 {
+		stageNum = 1
+	}
+	if itemNum < 1 {
+		itemNum = queryCostAllItems
+	}
+	return days * stageNum * itemNum
+}
+
+// Admit returns ErrQueryTooCostly when the estimated cost of the query exceeds the budget
+func (s *QueryCost) Admit(start, end time.Time, stageNum, itemNum int) error {
+	cost := EstimateQueryCost(start, end, stageNum, itemNum)
+	budget := s.Tunables.Int(TunableQueryCostBudget)
+	if budget <= 0 || cost <= budget {
+		return nil
+	}
+	return ErrQueryTooCostly.WithExtras(pgerr.Extras{
+		"estimatedCost": cost,
+		"budget":        budget,
+	})
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+const (
+	// RecognitionDefectMaxResultBytes bounds the recognition result of a defect, which is only metadata
+	RecognitionDefectMaxResultBytes = 64 << 10
+
+	recognitionDefectsDefaultLimit = 50
+)
+
+type RecognitionDefect struct {
+	RecognitionDefectRepo *repo.RecognitionDefect
+}
+
+func NewRecognitionDefect(recognitionDefectRepo *repo.RecognitionDefect) *RecognitionDefect {
+	return &RecognitionDefect{
+		RecognitionDefectRepo: recognitionDefectRepo,
+	}
+}
+
+type recognitionDefectEnvironment struct {
+	Server   string               `json:"server"`
+	StageID  string               `json:"stageId,omitempty"`
+	Reason   string               `json:"reason"`
+	Client   types.V3ReportClient `json:"client"`
+	Metadata map[string]string    `json:"metadata,omitempty"`
+}
+
+// SubmitDefect stores a defect uploaded by an official recognition client for the algorithm team to triage
+func (s *RecognitionDefect) SubmitDefect(ctx context.Context, apiKey *model.APIKey, req *types.V3RecognitionDefectRequest) (*model.RecognitionDefect, error) {
+	if len(req.RecognitionResult) > RecognitionDefectMaxResultBytes {
+		return nil, pgerr.ErrInvalidReq.Msg("recognitionResult must not exceed %d bytes", RecognitionDefectMaxResultBytes)
+	}
+	if bytes.Contains(req.RecognitionResult, []byte("data:image/")) {
+		return nil, pgerr.ErrInvalidReq.Msg("recognitionResult must not embed images")
+	}
+
+	environment, err := json.Marshal(recognitionDefectEnvironment{
+		Server:   req.Server,
+		StageID:  req.StageID,
+		Reason:   req.Reason,
+		Client:   req.Client,
+		Metadata: req.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defect := &model.RecognitionDefect{
+		SessionID:         req.SessionID,
+		RecognitionResult: req.RecognitionResult,
+		Environment:       environment,
+		APIKeyID:          apiKey.APIKeyID,
+	}
+	if err := s.RecognitionDefectRepo.CreateDefect(ctx, defect); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("evt.name", "recognition.defect.submitted").
+		Str("defectId", defect.DefectID).
+		Int("apiKeyId", apiKey.APIKeyID).
+		Str("server", req.Server).
+		Msg("recognition defect submitted")
+
+	return defect, nil
+}
+
+// GetDefectsPage lists the defects matching the query for triage, latest first, along with the total number of them
+func (s *RecognitionDefect) GetDefectsPage(ctx context.Context, query *types.RecognitionDefectsQuery) ([]*model.RecognitionDefect, int, error) {
+	if query.Limit == 0 {
+		query.Limit = recognitionDefectsDefaultLimit
+	}
+	return s.RecognitionDefectRepo.GetDefectsPage(ctx, &repo.RecognitionDefectFilter{
+		APIKeyID: query.APIKeyID,
+		Server:   query.Server,
+	}, query.Limit, query.Page)
+}
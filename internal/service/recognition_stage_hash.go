@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	"exusiai.dev/backend-next/internal/model/types"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// RecognitionStageHash manages the registry of the perceptual hashes the recognition clients identify the stages by
+// on the result screens, so that new hashes reach the clients without a release of theirs
+type RecognitionStageHash struct {
+	RecognitionStageHashRepo *repo.RecognitionStageHash
+	StageService             *Stage
+}
+
+func NewRecognitionStageHash(recognitionStageHashRepo *repo.RecognitionStageHash, stageService *Stage) *RecognitionStageHash {
+	return &RecognitionStageHash{
+		RecognitionStageHashRepo: recognitionStageHashRepo,
+		StageService:             stageService,
+	}
+}
+
+// GetRecognitionStageHashes returns the hashes of the server, or of every server when server is empty
+func (s *RecognitionStageHash) GetRecognitionStageHashes(ctx context.Context, server string) ([]*model.RecognitionStageHash, error) {
+	return s.RecognitionStageHashRepo.GetRecognitionStageHashes(ctx, server)
+}
+
+// GetRecognitionStageHashesByServer returns the registry of the server for the clients, versioned by a hash of it.
+// The hashes of the deleted stages are left out.
+// Cache: recognitionStageHashes#server:{server}, 1 hr
+func (s *RecognitionStageHash) GetRecognitionStageHashesByServer(ctx context.Context, server string) (*modelv3.RecognitionStageHashes, error) {
+	var registry modelv3.RecognitionStageHashes
+	_, err := cache.RecognitionStageHashes.MutexGetSet(server, &registry, func() (*modelv3.RecognitionStageHashes, error) {
+		stageHashes, err := s.RecognitionStageHashRepo.GetRecognitionStageHashes(ctx, server)
+		if err != nil {
+			return nil, err
+		}
+		stagesMapById, err := s.StageService.GetStagesMapById(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		hashes := make([]*modelv3.RecognitionStageHash, 0, len(stageHashes))
+		for _, stageHash := range stageHashes {
+			stage, ok := stagesMapById[stageHash.StageID]
+			if !ok {
+				continue
+			}
+			hashes = append(hashes, &modelv3.RecognitionStageHash{
+				Hash:    stageHash.Hash,
+				StageID: stage.ArkStageID,
+			})
+		}
+
+		content, err := json.Marshal(hashes)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(content)
+		return &modelv3.RecognitionStageHashes{
+			Server:  server,
+			Version: hex.EncodeToString(sum[:8]),
+			Hashes:  hashes,
+		}, nil
+	}, time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	return &registry, nil
+}
+
+// SaveRecognitionStageHash creates the hash when hashId is 0, or replaces the hash of hashId otherwise
+func (s *RecognitionStageHash) SaveRecognitionStageHash(ctx context.Context, hashId int, req *types.SaveRecognitionStageHashRequest) (*model.RecognitionStageHash, error) {
+	stage, err := s.StageService.GetStageByArkId(ctx, req.StageID)
+	if errors.Is(err, pgerr.ErrNotFound) {
+		return nil, pgerr.ErrInvalidReq.Msg("stage %s does not exist", req.StageID)
+	} else if err != nil {
+		return nil, err
+	}
+
+	stageHash := &model.RecognitionStageHash{
+		HashID:  hashId,
+		Server:  req.Server,
+		StageID: stage.StageID,
+		Hash:    strings.ToLower(req.Hash),
+		Comment: req.Comment,
+	}
+	var previous *model.RecognitionStageHash
+	if hashId != 0 {
+		if previous, err = s.RecognitionStageHashRepo.GetRecognitionStageHashById(ctx, hashId); err != nil {
+			return nil, err
+		}
+	}
+	if existing, err := s.RecognitionStageHashRepo.GetRecognitionStageHashByHash(ctx, stageHash.Server, stageHash.Hash); err == nil {
+		if existing.HashID != hashId {
+			return nil, pgerr.ErrInvalidReq.Msg("hash %s is already registered for another stage in %s", stageHash.Hash, stageHash.Server)
+		}
+	} else if !errors.Is(err, pgerr.ErrNotFound) {
+		return nil, err
+	}
+
+	if err := s.RecognitionStageHashRepo.SaveRecognitionStageHash(ctx, stageHash); err != nil {
+		return nil, err
+	}
+	cache.RecognitionStageHashes.Delete(stageHash.Server)
+	if previous != nil && previous.Server != stageHash.Server {
+		cache.RecognitionStageHashes.Delete(previous.Server)
+	}
+
+	log.Info().
+		Str("evt.name", "admin.recognition_stage_hash.saved").
+		Int("hashId", stageHash.HashID).
+		Str("server", stageHash.Server).
+		Str("stageId", req.StageID).
+		Msg("recognition stage hash saved")
+
+	return stageHash, nil
+}
+
+func (s *RecognitionStageHash) DeleteRecognitionStageHash(ctx context.Context, hashId int) error {
+	stageHash, err := s.RecognitionStageHashRepo.GetRecognitionStageHashById(ctx, hashId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.RecognitionStageHashRepo.DeleteRecognitionStageHash(ctx, hashId); err != nil {
+		return err
+	}
+	cache.RecognitionStageHashes.Delete(stageHash.Server)
+
+	log.Info().
+		Str("evt.name", "admin.recognition_stage_hash.deleted").
+		Int("hashId", hashId).
+		Str("server", stageHash.Server).
+		Msg("recognition stage hash deleted")
+
+	return nil
+}
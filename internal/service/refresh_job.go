@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/oklog/ulid/v2"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/util"
+)
+
+const (
+	refreshJobRedisPrefix = "refresh-job:"
+	refreshJobLifetime    = time.Hour * 24 * 3
+	refreshJobTimeout     = time.Hour
+	// refreshJobMaxDates is the most dates a refresh job is allowed to recalculate
+	refreshJobMaxDates = 120
+)
+
+// RefreshJob runs rollup, matrix, pattern and trend recalculations as background jobs and records their
+// progress in redis, so that the progress can be queried from any instance.
+type RefreshJob struct {
+	Redis                *redis.Client
+	DropMatrixService    *DropMatrix
+	PatternMatrixService *PatternMatrix
+	TrendService         *Trend
+	RollupsService       *DropReportDailyRollups
+	Freshness            *Freshness
+	RetentionService     *Retention
+	Jobs                 *Jobs
+}
+
+// refreshJobPayload is the payload of the refresh jobs, of which the progress is kept in redis under the same ID
+type refreshJobPayload struct {
+	ProgressID string `json:"progressId"`
+}
+
+func NewRefreshJob(redisClient *redis.Client, dropMatrixService *DropMatrix, patternMatrixService *PatternMatrix, trendService *Trend, rollupsService *DropReportDailyRollups, freshness *Freshness, retentionService *Retention, jobs *Jobs) *RefreshJob {
+	s := &RefreshJob{
+		Redis:                redisClient,
+		DropMatrixService:    dropMatrixService,
+		PatternMatrixService: patternMatrixService,
+		TrendService:         trendService,
+		RollupsService:       rollupsService,
+		Freshness:            freshness,
+		RetentionService:     retentionService,
+		Jobs:                 jobs,
+	}
+	jobs.Register(model.JobKindRefresh, JobPolicy{
+		MaxAttempts: 3,
+		Backoff:     time.Minute,
+		Timeout:     refreshJobTimeout,
+		Singleton:   true,
+	}, s.handle)
+	return s
+}
+
+// StartRefreshJob records a new job and runs it in the background
+func (s *RefreshJob) StartRefreshJob(ctx context.Context, req *types.CreateRefreshJobRequest) (*model.RefreshJob, error) {
+	if req.Realm == model.RefreshJobRealmRollup && !s.RollupsService.Enabled() {
+		return nil, pgerr.ErrInvalidReq.Msg("the daily rollups are disabled")
+	}
+
+	job := &model.RefreshJob{
+		ID:        strings.ToLower(ulid.Make().String()),
+		Realm:     req.Realm,
+		Server:    req.Server,
+		Status:    model.RefreshJobStatusPending,
+		Errors:    []string{},
+		CreatedAt: time.Now(),
+	}
+
+	dates := make([]time.Time, 0, len(req.Dates))
+	if req.Realm != model.RefreshJobRealmTrend {
+		for _, dateStr := range req.Dates {
+			date, err := time.Parse("2006-01-02", dateStr)
+			if err != nil {
+				return nil, pgerr.ErrInvalidReq.Msg("invalid date %s", dateStr)
+			}
+			// the matrices of the days are replaced as a whole, so the days with purged reports are not recalculated
+			if req.Realm != model.RefreshJobRealmRollup {
+				if err := s.RetentionService.CheckNotPurged(time.UnixMilli(util.GetDayStartTime(&date, req.Server))); err != nil {
+					return nil, err
+				}
+			}
+			dates = append(dates, date)
+		}
+		if len(dates) == 0 {
+			dates = append(dates, time.Now())
+		}
+		for _, date := range dates {
+			job.Dates = append(job.Dates, date.Format("2006-01-02"))
+		}
+		job.Total = len(dates)
+		if req.Realm != model.RefreshJobRealmRollup {
+			job.StageIDs = req.StageIDs
+		}
+	} else {
+		job.Total = 1
+	}
+
+	if err := s.saveJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	if _, _, err := s.Jobs.Enqueue(ctx, model.JobKindRefresh, job.Server, job.ID, &refreshJobPayload{ProgressID: job.ID}); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// handle runs an attempt of a refresh job. The progress is started over upon every attempt.
+func (s *RefreshJob) handle(ctx context.Context, j *model.Job) error {
+	var payload refreshJobPayload
+	if err := json.Unmarshal(j.Payload, &payload); err != nil {
+		return err
+	}
+	job, err := s.GetRefreshJob(ctx, payload.ProgressID)
+	if err != nil {
+		return err
+	}
+
+	dates := make([]time.Time, 0, len(job.Dates))
+	for _, dateStr := range job.Dates {
+		date, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return err
+		}
+		dates = append(dates, date)
+	}
+
+	job.Done = 0
+	job.Errors = []string{}
+	s.run(ctx, job, dates)
+	if job.Status == model.RefreshJobStatusFailed {
+		return errors.Errorf("refresh job failed with %d errors: %s", len(job.Errors), strings.Join(job.Errors, "; "))
+	}
+	return nil
+}
+
+func (s *RefreshJob) GetRefreshJob(ctx context.Context, id string) (*model.RefreshJob, error) {
+	b, err := s.Redis.Get(ctx, refreshJobRedisPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, pgerr.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var job model.RefreshJob
+	if err := json.Unmarshal(b, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *RefreshJob) run(ctx context.Context, job *model.RefreshJob, dates []time.Time) {
+	startedAt := time.Now()
+	job.Status = model.RefreshJobStatusRunning
+	job.StartedAt = &startedAt
+	s.saveProgress(ctx, job)
+
+	switch job.Realm {
+	case model.RefreshJobRealmRollup:
+		for i := range dates {
+			s.step(job, s.RollupsService.RebuildDate(ctx, job.Server, dates[i]))
+			s.saveProgress(ctx, job)
+		}
+	case model.RefreshJobRealmMatrix:
+		for i := range dates {
+			s.step(job, s.DropMatrixService.UpdateDropMatrixByGivenDate(ctx, job.Server, &dates[i], job.StageIDs))
+			s.saveProgress(ctx, job)
+		}
+		if err := s.DropMatrixService.purgeGlobalDropMatrixCaches(job.Server); err != nil {
+			job.Errors = append(job.Errors, err.Error())
+		}
+	case model.RefreshJobRealmPattern:
+		for i := range dates {
+			s.step(job, s.PatternMatrixService.UpdatePatternMatrixByGivenDate(ctx, job.Server, &dates[i], job.StageIDs))
+			s.saveProgress(ctx, job)
+		}
+		if err := s.PatternMatrixService.purgeGlobalPatternMatrixCaches(job.Server); err != nil {
+			job.Errors = append(job.Errors, err.Error())
+		}
+	case model.RefreshJobRealmTrend:
+		err := cache.ShimTrend.Delete(job.Server)
+		if err == nil {
+			_, err = s.TrendService.GetShimTrend(ctx, job.Server)
+		}
+		s.step(job, err)
+	}
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	job.Status = model.RefreshJobStatusSucceeded
+	if len(job.Errors) > 0 {
+		job.Status = model.RefreshJobStatusFailed
+	} else if job.Realm == model.RefreshJobRealmMatrix || job.Realm == model.RefreshJobRealmPattern {
+		// the trends record their freshness once calculated
+		if err := s.Freshness.MarkRefreshed(ctx, job.Server, job.Realm, finishedAt); err != nil {
+			log.Warn().Err(err).Str("jobId", job.ID).Msg("failed to record the freshness of the refreshed realm")
+		}
+	}
+	s.saveProgress(ctx, job)
+
+	log.Info().
+		Str("evt.name", "refreshjob.finished").
+		Str("jobId", job.ID).
+		Str("realm", job.Realm).
+		Str("server", job.Server).
+		Str("status", job.Status).
+		Int("done", job.Done).
+		Int("total", job.Total).
+		Dur("took", finishedAt.Sub(startedAt)).
+		Msg("refresh job finished")
+}
+
+func (s *RefreshJob) step(job *model.RefreshJob, err error) {
+	job.Done++
+	if err != nil {
+		job.Errors = append(job.Errors, err.Error())
+	}
+}
+
+// saveProgress persists the job state; failures are only logged, as they must not abort the job itself
+func (s *RefreshJob) saveProgress(ctx context.Context, job *model.RefreshJob) {
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Error().Err(err).Str("jobId", job.ID).Msg("failed to save refresh job progress")
+	}
+}
+
+func (s *RefreshJob) saveJob(ctx context.Context, job *model.RefreshJob) error {
+	b, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return s.Redis.Set(ctx, refreshJobRedisPrefix+job.ID, b, refreshJobLifetime).Err()
+}
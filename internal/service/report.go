@@ -2,13 +2,13 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"strings"
 	"time"
 
 	"exusiai.dev/gommon/constant"
 	"github.com/dchest/uniuri"
 	"github.com/goccy/go-json"
-	"github.com/gofiber/fiber/v2"
 	"github.com/nats-io/nats.go"
 	"github.com/pkg/errors"
 	"github.com/redis/go-redis/v9"
@@ -16,9 +16,7 @@ import (
 
 	"exusiai.dev/backend-next/internal/model/types"
 	"exusiai.dev/backend-next/internal/pkg/pgerr"
-	"exusiai.dev/backend-next/internal/pkg/pgid"
 	"exusiai.dev/backend-next/internal/repo"
-	"exusiai.dev/backend-next/internal/util"
 	"exusiai.dev/backend-next/internal/util/reportutil"
 	"exusiai.dev/backend-next/internal/util/reportverifs"
 )
@@ -44,9 +42,14 @@ type Report struct {
 	DropReportExtraRepo    *repo.DropReportExtra
 	DropPatternElementRepo *repo.DropPatternElement
 	ReportVerifier         *reportverifs.ReportVerifiers
+	DropMatrixService      *DropMatrix
+	Tunables               *Tunables
+	FeatureFlags           *FeatureFlags
+	ClientVersionService   *ClientVersion
+	DailyRollups           *DropReportDailyRollups
 }
 
-func NewReport(db *bun.DB, redisClient *redis.Client, natsJs nats.JetStreamContext, itemService *Item, stageService *Stage, stageRepo *repo.Stage, dropInfoRepo *repo.DropInfo, dropReportRepo *repo.DropReport, dropReportExtraRepo *repo.DropReportExtra, dropPatternRepo *repo.DropPattern, dropPatternElementRepo *repo.DropPatternElement, accountService *Account, timeRangeService *TimeRange, reportVerifier *reportverifs.ReportVerifiers) *Report {
+func NewReport(db *bun.DB, redisClient *redis.Client, natsJs nats.JetStreamContext, itemService *Item, stageService *Stage, stageRepo *repo.Stage, dropInfoRepo *repo.DropInfo, dropReportRepo *repo.DropReport, dropReportExtraRepo *repo.DropReportExtra, dropPatternRepo *repo.DropPattern, dropPatternElementRepo *repo.DropPatternElement, accountService *Account, timeRangeService *TimeRange, reportVerifier *reportverifs.ReportVerifiers, dropMatrixService *DropMatrix, tunables *Tunables, featureFlags *FeatureFlags, clientVersionService *ClientVersion, dailyRollups *DropReportDailyRollups) *Report {
 	service := &Report{
 		DB:                     db,
 		Redis:                  redisClient,
@@ -62,24 +65,28 @@ func NewReport(db *bun.DB, redisClient *redis.Client, natsJs nats.JetStreamConte
 		DropReportExtraRepo:    dropReportExtraRepo,
 		DropPatternElementRepo: dropPatternElementRepo,
 		ReportVerifier:         reportVerifier,
+		DropMatrixService:      dropMatrixService,
+		Tunables:               tunables,
+		FeatureFlags:           featureFlags,
+		ClientVersionService:   clientVersionService,
+		DailyRollups:           dailyRollups,
 	}
 	return service
 }
 
-func (s *Report) PipelineAccount(ctx *fiber.Ctx) (accountId int, err error) {
-	account, err := s.AccountService.GetAccountFromRequest(ctx)
+// PipelineAccount resolves the account of penguinId, creating an account with a random PenguinID when it does not
+// resolve. The PenguinID of the created account is returned as createdPenguinId for the caller to hand it out.
+func (s *Report) PipelineAccount(ctx context.Context, penguinId string) (accountId int, createdPenguinId string, err error) {
+	account, err := s.AccountService.GetAccountFromPenguinId(ctx, penguinId)
 	if err != nil {
-		createdAccount, err := s.AccountService.CreateAccountWithRandomPenguinId(ctx.UserContext())
+		createdAccount, err := s.AccountService.CreateAccountWithRandomPenguinId(ctx)
 		if err != nil {
-			return 0, err
+			return 0, "", err
 		}
-		accountId = createdAccount.AccountID
-		pgid.Inject(ctx, createdAccount.PenguinID)
-	} else {
-		accountId = account.AccountID
+		return createdAccount.AccountID, createdAccount.PenguinID, nil
 	}
 
-	return accountId, nil
+	return account.AccountID, "", nil
 }
 
 func (s *Report) PipelinePreprocessRecruitmentTags(ctx context.Context, req *types.SingularReportRequest) error {
@@ -153,15 +160,20 @@ func (s *Report) PipelinePreprocessRerunStageIdForMaa(ctx context.Context, req *
 }
 
 func (s *Report) PipelineMergeDropsAndMapDropTypes(ctx context.Context, drops []types.ArkDrop) ([]*types.Drop, error) {
+	arkItemIds := make([]string, 0, len(drops))
+	for _, drop := range drops {
+		arkItemIds = append(arkItemIds, drop.ItemID)
+	}
+	itemsMapByArkId, err := s.ItemService.GetItemsByArkIds(ctx, arkItemIds)
+	if err != nil {
+		return nil, err
+	}
+
 	convertedDrops := make([]*types.Drop, 0, len(drops))
 	for _, drop := range drops {
-		item, err := s.ItemService.GetItemByArkId(ctx, drop.ItemID)
-		if err != nil {
-			if !errors.Is(err, pgerr.ErrNotFound) {
-				return nil, err
-			} else {
-				continue
-			}
+		item, ok := itemsMapByArkId[drop.ItemID]
+		if !ok {
+			continue
 		}
 
 		convertedDrops = append(convertedDrops, &types.Drop{
@@ -176,8 +188,8 @@ func (s *Report) PipelineMergeDropsAndMapDropTypes(ctx context.Context, drops []
 	return convertedDrops, nil
 }
 
-func (s *Report) PipelineTaskId(ctx *fiber.Ctx) string {
-	return ctx.Locals(constant.ContextKeyRequestID).(string) + "-" + uniuri.NewLen(16)
+func (s *Report) PipelineTaskId(requestId string) string {
+	return requestId + "-" + uniuri.NewLen(16)
 }
 
 func (s *Report) PipelineAggregateGachaboxDrops(ctx context.Context, singleReport *types.ReportTaskSingleReport) error {
@@ -193,8 +205,13 @@ func (s *Report) PipelineAggregateGachaboxDrops(ctx context.Context, singleRepor
 	return nil
 }
 
-func (s *Report) commitReportTask(ctx *fiber.Ctx, subject string, task *types.ReportTask) (taskId string, err error) {
-	taskId = s.PipelineTaskId(ctx)
+func (s *Report) commitReportTask(ctx context.Context, requestId, subject string, task *types.ReportTask) (taskId string, err error) {
+	// every report path ends up here, so that no path escapes the client version gates
+	if err := s.ClientVersionService.CheckClientVersion(ctx, task.Source, task.Version); err != nil {
+		return "", err
+	}
+
+	taskId = s.PipelineTaskId(requestId)
 	task.TaskID = taskId
 
 	reportTaskJsonBytes, err := json.Marshal(task)
@@ -212,34 +229,33 @@ func (s *Report) commitReportTask(ctx *fiber.Ctx, subject string, task *types.Re
 		return "", err
 	case <-pub.Ok():
 		return taskId, nil
-	case <-ctx.UserContext().Done():
-		return "", ctx.UserContext().Err()
+	case <-ctx.Done():
+		return "", ctx.Err()
 	case <-time.After(time.Second * 10):
 		return "", ErrNatsTimeout
 	}
 }
 
 // returns taskID and error, if any
-func (s *Report) PreprocessAndQueueSingularReport(ctx *fiber.Ctx, req *types.SingularReportRequest) (taskId string, err error) {
-	accountId, ok := ctx.Locals(constant.LocalsAccountIDKey).(int)
-	if !ok {
+func (s *Report) PreprocessAndQueueSingularReport(ctx context.Context, origin *types.ReportOrigin, req *types.SingularReportRequest) (taskId string, err error) {
+	if origin.AccountID == 0 {
 		return "", ErrAccountMissing
 	}
 
-	err = s.PipelinePreprocessRecruitmentTags(ctx.UserContext(), req)
+	err = s.PipelinePreprocessRecruitmentTags(ctx, req)
 	if err != nil {
 		return "", err
 	}
 
 	// If stage id is for a perm stage and it's from MAA, we will try to see if the corresponding rerun stage is available or not.
 	// If available, we will use the rerun stage id instead. (MAA sometimes uses perm stage id for rerun stages)
-	err = s.PipelinePreprocessRerunStageIdForMaa(ctx.UserContext(), req)
+	err = s.PipelinePreprocessRerunStageIdForMaa(ctx, req)
 	if err != nil {
 		return "", err
 	}
 
 	// merge drops with same (dropType, itemId) pair
-	drops, err := s.PipelineMergeDropsAndMapDropTypes(ctx.UserContext(), req.Drops)
+	drops, err := s.PipelineMergeDropsAndMapDropTypes(ctx, req.Drops)
 	if err != nil {
 		return "", err
 	}
@@ -254,8 +270,13 @@ func (s *Report) PreprocessAndQueueSingularReport(ctx *fiber.Ctx, req *types.Sin
 		Metadata:        req.Metadata,
 	}
 
+	return s.queueSingleReport(ctx, origin, req.FragmentReportCommon, singleReport)
+}
+
+// queueSingleReport queues a single report which drops have already been mapped and merged
+func (s *Report) queueSingleReport(ctx context.Context, origin *types.ReportOrigin, common types.FragmentReportCommon, singleReport *types.ReportTaskSingleReport) (taskId string, err error) {
 	// for gachabox drop, we need to aggregate `times` according to `quantity` for report.Drops
-	err = s.PipelineAggregateGachaboxDrops(ctx.UserContext(), singleReport)
+	err = s.PipelineAggregateGachaboxDrops(ctx, singleReport)
 	if err != nil {
 		return "", err
 	}
@@ -264,21 +285,20 @@ func (s *Report) PreprocessAndQueueSingularReport(ctx *fiber.Ctx, req *types.Sin
 	reportTask := &types.ReportTask{
 		CreatedAt: time.Now().UnixMicro(),
 		FragmentReportCommon: types.FragmentReportCommon{
-			Server:  req.Server,
-			Source:  req.Source,
-			Version: req.Version,
+			Server:  common.Server,
+			Source:  common.Source,
+			Version: common.Version,
 		},
 		Reports:   []*types.ReportTaskSingleReport{singleReport},
-		AccountID: accountId,
-		IP:        util.ExtractIP(ctx),
+		AccountID: origin.AccountID,
+		IP:        origin.IP,
 	}
 
-	return s.commitReportTask(ctx, "REPORT.SINGLE", reportTask)
+	return s.commitReportTask(ctx, origin.RequestID, "REPORT.SINGLE", reportTask)
 }
 
-func (s *Report) PreprocessAndQueueBatchReport(ctx *fiber.Ctx, req *types.BatchReportRequest) (taskId string, err error) {
-	accountId, ok := ctx.Locals(constant.LocalsAccountIDKey).(int)
-	if !ok {
+func (s *Report) PreprocessAndQueueBatchReport(ctx context.Context, origin *types.ReportOrigin, req *types.BatchReportRequest) (taskId string, err error) {
+	if origin.AccountID == 0 {
 		return "", ErrAccountMissing
 	}
 
@@ -286,7 +306,7 @@ func (s *Report) PreprocessAndQueueBatchReport(ctx *fiber.Ctx, req *types.BatchR
 
 	for i, drop := range req.BatchDrops {
 		// merge drops with same (dropType, itemId) pair
-		drops, err := s.PipelineMergeDropsAndMapDropTypes(ctx.UserContext(), drop.Drops)
+		drops, err := s.PipelineMergeDropsAndMapDropTypes(ctx, drop.Drops)
 		if err != nil {
 			return "", err
 		}
@@ -300,7 +320,7 @@ func (s *Report) PreprocessAndQueueBatchReport(ctx *fiber.Ctx, req *types.BatchR
 			Metadata:        &metadata,
 		}
 
-		err = s.PipelineAggregateGachaboxDrops(ctx.UserContext(), report)
+		err = s.PipelineAggregateGachaboxDrops(ctx, report)
 		if err != nil {
 			return "", err
 		}
@@ -317,11 +337,11 @@ func (s *Report) PreprocessAndQueueBatchReport(ctx *fiber.Ctx, req *types.BatchR
 			Version: req.Version,
 		},
 		Reports:   reports,
-		AccountID: accountId,
-		IP:        util.ExtractIP(ctx),
+		AccountID: origin.AccountID,
+		IP:        origin.IP,
 	}
 
-	return s.commitReportTask(ctx, "REPORT.BATCH", reportTask)
+	return s.commitReportTask(ctx, origin.RequestID, "REPORT.BATCH", reportTask)
 }
 
 func (s *Report) RecallSingularReport(ctx context.Context, req *types.SingularReportRecallRequest) error {
@@ -339,7 +359,15 @@ func (s *Report) RecallSingularReport(ctx context.Context, req *types.SingularRe
 		return err
 	}
 
-	err = s.DropReportRepo.DeleteDropReport(ctx, reportId)
+	err = s.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		report, err := s.DropReportRepo.RecallDropReport(ctx, tx, reportId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrReportNotFound
+		} else if err != nil {
+			return err
+		}
+		return s.DailyRollups.RemoveReport(ctx, tx, report)
+	})
 	if err != nil {
 		return err
 	}
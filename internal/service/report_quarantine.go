@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo"
+	"exusiai.dev/backend-next/internal/util/reportverifs"
+)
+
+// reportQuarantineBatchSize is the most quarantined reports revalidated by a single run of the job
+const reportQuarantineBatchSize = 1000
+
+// ReportQuarantine revalidates the reports quarantined upon the opening of their stages once the quarantine window
+// has passed, as a periodic singleton background job. The reports are revalidated against the drop infos in effect
+// by then, which have had the window to be corrected, and either released into the aggregations or rejected.
+type ReportQuarantine struct {
+	Config                 *appconfig.Config
+	DropReportRepo         *repo.DropReport
+	DropPatternElementRepo *repo.DropPatternElement
+	DropInfoRepo           *repo.DropInfo
+	StageService           *Stage
+	BackfillService        *Backfill
+}
+
+func NewReportQuarantine(config *appconfig.Config, dropReportRepo *repo.DropReport, dropPatternElementRepo *repo.DropPatternElement, dropInfoRepo *repo.DropInfo, stageService *Stage, backfillService *Backfill, jobs *Jobs) *ReportQuarantine {
+	s := &ReportQuarantine{
+		Config:                 config,
+		DropReportRepo:         dropReportRepo,
+		DropPatternElementRepo: dropPatternElementRepo,
+		DropInfoRepo:           dropInfoRepo,
+		StageService:           stageService,
+		BackfillService:        backfillService,
+	}
+	if config.ReportQuarantineWindow > 0 {
+		jobs.Register(model.JobKindReportQuarantine, JobPolicy{
+			MaxAttempts: 2,
+			Backoff:     time.Minute,
+			Timeout:     time.Minute * 10,
+			Every:       config.ReportQuarantineRevalidationInterval,
+			Singleton:   true,
+		}, s.revalidate)
+	}
+	return s
+}
+
+// revalidate revalidates a batch of the quarantined reports submitted more than the quarantine window ago, by which
+// time the window of their stages has passed, and recalculates the aggregations of those released
+func (s *ReportQuarantine) revalidate(ctx context.Context, _ *model.Job) error {
+	reports, err := s.DropReportRepo.GetReportsByReliability(ctx, reportverifs.ViolationReliabilityQuarantine,
+		time.Now().Add(-s.Config.ReportQuarantineWindow), reportQuarantineBatchSize)
+	if err != nil {
+		return err
+	}
+	if len(reports) == 0 {
+		return nil
+	}
+
+	elements, err := s.DropPatternElementRepo.GetDropPatternElementsByPatternIds(ctx, lo.Uniq(lo.Map(reports, func(report *model.DropReport, _ int) int {
+		return report.PatternID
+	})))
+	if err != nil {
+		return err
+	}
+	elementsByPattern := lo.GroupBy(elements, func(element *model.DropPatternElement) int {
+		return element.DropPatternID
+	})
+
+	released := make([]int, 0, len(reports))
+	rejected := make([]int, 0)
+	for _, report := range reports {
+		violation, err := s.revalidateReport(ctx, report, elementsByPattern[report.PatternID])
+		if err != nil {
+			return err
+		}
+		if violation != "" {
+			log.Info().
+				Str("evt.name", "report.quarantine.rejected").
+				Int("reportId", report.ReportID).
+				Str("violation", violation).
+				Msg("quarantined report failed revalidation")
+			rejected = append(rejected, report.ReportID)
+		} else {
+			released = append(released, report.ReportID)
+		}
+	}
+
+	if _, err := s.DropReportRepo.UpdateReportsReliability(ctx, rejected, reportverifs.ViolationReliabilityQuarantine, constant.ViolationReliabilityDrop); err != nil {
+		return err
+	}
+	changes, err := s.DropReportRepo.UpdateReportsReliability(ctx, released, reportverifs.ViolationReliabilityQuarantine, 0)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.BackfillService.Track(ctx, changes); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("evt.name", "report.quarantine.revalidated").
+		Int("released", len(released)).
+		Int("rejected", len(rejected)).
+		Msg("quarantined reports revalidated")
+
+	return nil
+}
+
+// revalidateReport checks every item dropped in the report against the drop infos of its stage in effect by the time
+// the report was submitted: the item must be known to drop from the stage, within the bounds times the times cleared.
+// The drop types are not stored along with the reports, so the bounds of the item are summed across them. It returns
+// the violation found, if any.
+func (s *ReportQuarantine) revalidateReport(ctx context.Context, report *model.DropReport, elements []*model.DropPatternElement) (string, error) {
+	stage, err := s.StageService.GetStageById(ctx, report.StageID)
+	if err != nil {
+		return "", err
+	}
+	itemDropInfos, _, err := s.DropInfoRepo.GetForTimeRangeAtWithDropTypes(ctx, &repo.DropInfoQuery{
+		Server:     report.Server,
+		ArkStageId: stage.ArkStageID,
+	}, *report.CreatedAt)
+	if err != nil {
+		return "", err
+	}
+
+	upperBounds := make(map[int]int)
+	for _, dropInfo := range itemDropInfos {
+		if dropInfo.Bounds != nil {
+			upperBounds[int(dropInfo.ItemID.Int64)] += dropInfo.Bounds.Upper
+		}
+	}
+	for _, element := range elements {
+		upper, ok := upperBounds[element.ItemID]
+		if !ok {
+			return fmt.Sprintf("item %d is not known to drop from %s", element.ItemID, stage.ArkStageID), nil
+		}
+		if element.Quantity > upper*report.Times {
+			return fmt.Sprintf("item %d: expected at most %d, but got %d", element.ItemID, upper*report.Times, element.Quantity), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/repo"
+	"exusiai.dev/backend-next/internal/util/reportverifs"
+)
+
+const (
+	reportSamplesDefaultLimit = 20
+	reportSamplesDefaultDays  = 7
+
+	// riskPriorWeight is the number of reviews the invalid rate of a signal is pulled towards the overall invalid rate
+	// with, so that the signals seldom reviewed do not swing the scores
+	riskPriorWeight = 10
+)
+
+// ReportReview samples the recent reports of a stage for the moderators to inspect, and records their verdicts. The
+// verdicts train the risk model the samples are scored with: the more often the reviewed reports carrying a signal
+// have been invalid, the riskier the reports carrying it are scored.
+type ReportReview struct {
+	ReportReviewRepo       *repo.ReportReview
+	DropReportRepo         *repo.DropReport
+	DropReportExtraRepo    *repo.DropReportExtra
+	DropPatternElementRepo *repo.DropPatternElement
+	ItemService            *Item
+	StageService           *Stage
+	SubmissionPolicy       *SubmissionPolicy
+	BackfillService        *Backfill
+}
+
+func NewReportReview(reportReviewRepo *repo.ReportReview, dropReportRepo *repo.DropReport, dropReportExtraRepo *repo.DropReportExtra, dropPatternElementRepo *repo.DropPatternElement, itemService *Item, stageService *Stage, submissionPolicy *SubmissionPolicy, backfillService *Backfill) *ReportReview {
+	return &ReportReview{
+		ReportReviewRepo:       reportReviewRepo,
+		DropReportRepo:         dropReportRepo,
+		DropReportExtraRepo:    dropReportExtraRepo,
+		DropPatternElementRepo: dropPatternElementRepo,
+		ItemService:            itemService,
+		StageService:           stageService,
+		SubmissionPolicy:       submissionPolicy,
+		BackfillService:        backfillService,
+	}
+}
+
+type ReportSampleDrop struct {
+	ItemID    int    `json:"itemId"`
+	ArkItemID string `json:"arkItemId"`
+	Quantity  int    `json:"quantity"`
+}
+
+type ReportSample struct {
+	Report     *model.DropReport `json:"report"`
+	ArkStageID string            `json:"arkStageId"`
+	// Drops are the elements of the pattern of the report
+	Drops []*ReportSampleDrop `json:"drops"`
+	// Extra is the IP and the client metadata of the report, or null once it has been archived
+	Extra     *model.DropReportExtra `json:"extra"`
+	RiskScore float64                `json:"riskScore"`
+	// Signals are what the risk of the report is scored from
+	Signals []string `json:"signals"`
+}
+
+type ReportReviewResult struct {
+	Review *model.ReportReview `json:"review"`
+	// Reports are the reports of which the exclusion has changed, per server and stage
+	Reports []*model.ReliabilityChangeResult `json:"reports"`
+	// RefreshJobs recalculate the aggregations of the stages and the days of the reports changed
+	RefreshJobs []*model.RefreshJob `json:"refreshJobs"`
+}
+
+type RiskSignal struct {
+	model.RiskSignalStats
+	// InvalidRate is the estimated rate of the invalid reports among those carrying the signal
+	InvalidRate float64 `json:"invalidRate"`
+}
+
+type RiskModel struct {
+	Reviewed int `json:"reviewed"`
+	Invalid  int `json:"invalid"`
+	// BaseRate is the estimated rate of the invalid reports overall, which the reports carrying no signal ever
+	// reviewed are scored at
+	BaseRate float64       `json:"baseRate"`
+	Signals  []*RiskSignal `json:"signals"`
+}
+
+// SampleReports picks up to query.Limit accepted reports of the stage submitted within the last query.Days days at
+// random among those not reviewed yet, riskiest first
+func (s *ReportReview) SampleReports(ctx context.Context, query *types.ReportSamplesQuery) ([]*ReportSample, error) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = reportSamplesDefaultLimit
+	}
+	days := query.Days
+	if days == 0 {
+		days = reportSamplesDefaultDays
+	}
+
+	stage, err := s.StageService.GetStageByArkId(ctx, query.ArkStageID)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := s.DropReportRepo.SampleRecentReports(ctx, query.Server, stage.StageID, time.Now().AddDate(0, 0, -days), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	samples, err := s.inspect(ctx, reports)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].RiskScore > samples[j].RiskScore
+	})
+	return samples, nil
+}
+
+// ReviewReport records the verdict of the reviewer on the report. The report is excluded from the aggregations when
+// reviewed as invalid, and included again when reviewed as verified afterwards.
+func (s *ReportReview) ReviewReport(ctx context.Context, reviewer *model.Account, reportId int, req *types.ReviewReportRequest) (*ReportReviewResult, error) {
+	report, err := s.DropReportRepo.GetDropReportById(ctx, reportId)
+	if err != nil {
+		return nil, err
+	}
+	samples, err := s.inspect(ctx, []*model.DropReport{report})
+	if err != nil {
+		return nil, err
+	}
+
+	review := &model.ReportReview{
+		ReportID:          reportId,
+		Verdict:           req.Verdict,
+		Signals:           samples[0].Signals,
+		RiskScore:         samples[0].RiskScore,
+		ReviewerAccountID: reviewer.AccountID,
+		Comment:           req.Comment,
+	}
+	if err := s.ReportReviewRepo.SaveReportReview(ctx, review); err != nil {
+		return nil, err
+	}
+
+	from, to := reportverifs.ViolationReliabilityModeration, 0
+	if review.Verdict == model.ReportReviewVerdictInvalid {
+		from, to = 0, reportverifs.ViolationReliabilityModeration
+	}
+	changes, err := s.DropReportRepo.UpdateReportsReliability(ctx, []int{reportId}, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReportReviewResult{Review: review, Reports: changes}
+	result.RefreshJobs, err = s.BackfillService.Track(ctx, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("evt.name", "admin.report.reviewed").
+		Int("reportId", reportId).
+		Int("reviewerAccountId", reviewer.AccountID).
+		Str("verdict", review.Verdict).
+		Float64("riskScore", review.RiskScore).
+		Msg("report reviewed")
+
+	return result, nil
+}
+
+// GetRiskModel returns the invalid rates the reports are scored with, as learnt from the reviews
+func (s *ReportReview) GetRiskModel(ctx context.Context) (*RiskModel, error) {
+	m, err := s.loadRiskModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RiskModel{
+		Reviewed: m.totals.Reviewed,
+		Invalid:  m.totals.Invalid,
+		BaseRate: m.base,
+		Signals:  make([]*RiskSignal, 0, len(m.stats)),
+	}
+	for _, stats := range m.stats {
+		result.Signals = append(result.Signals, &RiskSignal{
+			RiskSignalStats: *stats,
+			InvalidRate:     m.rates[stats.Signal],
+		})
+	}
+	return result, nil
+}
+
+// inspect details the reports and scores their risk, in the order of the reports
+func (s *ReportReview) inspect(ctx context.Context, reports []*model.DropReport) ([]*ReportSample, error) {
+	if len(reports) == 0 {
+		return make([]*ReportSample, 0), nil
+	}
+
+	m, err := s.loadRiskModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stages, err := s.StageService.GetStagesMapById(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.ItemService.GetItemsMapById(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	elements, err := s.DropPatternElementRepo.GetDropPatternElementsByPatternIds(ctx, lo.Uniq(lo.Map(reports, func(report *model.DropReport, _ int) int {
+		return report.PatternID
+	})))
+	if err != nil {
+		return nil, err
+	}
+	elementsByPattern := lo.GroupBy(elements, func(element *model.DropPatternElement) int {
+		return element.DropPatternID
+	})
+
+	extras, err := s.DropReportExtraRepo.GetDropReportExtrasByIds(ctx, lo.Map(reports, func(report *model.DropReport, _ int) int {
+		return report.ReportID
+	}))
+	if err != nil {
+		return nil, err
+	}
+	extrasByReport := lo.KeyBy(extras, func(extra *model.DropReportExtra) int {
+		return extra.ReportID
+	})
+
+	accountSignals := make(map[int][]string)
+	samples := make([]*ReportSample, 0, len(reports))
+	for _, report := range reports {
+		sample := &ReportSample{
+			Report: report,
+			Drops:  make([]*ReportSampleDrop, 0, len(elementsByPattern[report.PatternID])),
+			Extra:  extrasByReport[report.ReportID],
+		}
+		if stage, ok := stages[report.StageID]; ok {
+			sample.ArkStageID = stage.ArkStageID
+		}
+		for _, element := range elementsByPattern[report.PatternID] {
+			drop := &ReportSampleDrop{ItemID: element.ItemID, Quantity: element.Quantity}
+			if item, ok := items[element.ItemID]; ok {
+				drop.ArkItemID = item.ArkItemID
+			}
+			sample.Drops = append(sample.Drops, drop)
+		}
+
+		signals, ok := accountSignals[report.AccountID]
+		if !ok {
+			if signals, err = s.accountSignals(ctx, report.AccountID); err != nil {
+				return nil, err
+			}
+			accountSignals[report.AccountID] = signals
+		}
+		sample.Signals = append(s.reportSignals(report, sample.Extra), signals...)
+		sample.RiskScore = m.score(sample.Signals)
+
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+// reportSignals are the signals of the client and the network the report has been submitted from
+func (s *ReportReview) reportSignals(report *model.DropReport, extra *model.DropReportExtra) []string {
+	signals := []string{
+		"source:" + report.SourceName,
+		"client:" + report.SourceName + "@" + report.Version,
+	}
+	if extra == nil {
+		return signals
+	}
+	if reason := s.SubmissionPolicy.Flag(extra.IP); reason != "" {
+		signals = append(signals, "network:"+reason)
+	}
+	if extra.Metadata != nil && extra.Metadata.RecognizerVersion != "" {
+		signals = append(signals, "input:recognition")
+	} else {
+		signals = append(signals, "input:manual")
+	}
+	return signals
+}
+
+// accountSignals are the signals of the standing of the account the report has been submitted by
+func (s *ReportReview) accountSignals(ctx context.Context, accountId int) ([]string, error) {
+	counts, err := s.DropReportRepo.CalcReliabilityCountsByAccountId(ctx, accountId, AccountStandingWindow)
+	if err != nil {
+		return nil, err
+	}
+	reports := countStandingReports(counts)
+
+	signals := make([]string, 0, 2)
+	if reports.Total <= 1 {
+		signals = append(signals, "account:new")
+	}
+	if reports.Rejected > 0 {
+		signals = append(signals, "account:limited")
+	}
+	return signals, nil
+}
+
+type riskModel struct {
+	totals *model.RiskSignalStats
+	stats  []*model.RiskSignalStats
+	// base is the estimated invalid rate overall
+	base float64
+	// rates are the estimated invalid rates of the reports carrying each of the signals reviewed
+	rates map[string]float64
+}
+
+func (s *ReportReview) loadRiskModel(ctx context.Context) (*riskModel, error) {
+	totals, err := s.ReportReviewRepo.CalcVerdictTotals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.ReportReviewRepo.CalcRiskSignalStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newRiskModel(totals, stats), nil
+}
+
+func newRiskModel(totals *model.RiskSignalStats, stats []*model.RiskSignalStats) *riskModel {
+	// until enough reports have been reviewed, 1 in 10 is presumed invalid
+	base := (float64(totals.Invalid) + 1) / (float64(totals.Reviewed) + 10)
+	m := &riskModel{
+		totals: totals,
+		stats:  stats,
+		base:   base,
+		rates:  make(map[string]float64, len(stats)),
+	}
+	for _, signal := range stats {
+		m.rates[signal.Signal] = (float64(signal.Invalid) + riskPriorWeight*base) / (float64(signal.Reviewed) + riskPriorWeight)
+	}
+	return m
+}
+
+// score combines the invalid rates of the signals as independent evidence, each of them shifting the log odds of the
+// report being invalid from those of the base rate. The signals never reviewed leave them as they are.
+func (m *riskModel) score(signals []string) float64 {
+	logOdds := logit(m.base)
+	for _, signal := range signals {
+		if rate, ok := m.rates[signal]; ok {
+			logOdds += logit(rate) - logit(m.base)
+		}
+	}
+	return 1 / (1 + math.Exp(-logOdds))
+}
+
+func logit(p float64) float64 {
+	return math.Log(p / (1 - p))
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"exusiai.dev/backend-next/internal/model"
+)
+
+func TestRiskModelScore(t *testing.T) {
+	m := newRiskModel(&model.RiskSignalStats{Reviewed: 190, Invalid: 18}, []*model.RiskSignalStats{
+		{Signal: "source:frontend-v2", Reviewed: 150, Invalid: 5},
+		{Signal: "network:asn", Reviewed: 40, Invalid: 13},
+		{Signal: "account:new", Reviewed: 2, Invalid: 2},
+	})
+	if math.Abs(m.base-0.095) > 1e-9 {
+		t.Fatalf("base = %v, want 0.095", m.base)
+	}
+
+	// the signals never reviewed leave the score at the base rate
+	if got := m.score([]string{"source:unknown"}); math.Abs(got-m.base) > 1e-9 {
+		t.Errorf("score of an unreviewed signal = %v, want %v", got, m.base)
+	}
+
+	trusted := m.score([]string{"source:frontend-v2"})
+	flagged := m.score([]string{"source:frontend-v2", "network:asn"})
+	if !(trusted < m.base && flagged > trusted) {
+		t.Errorf("score: trusted = %v, flagged = %v, base = %v", trusted, flagged, m.base)
+	}
+
+	// a signal seldom reviewed is pulled towards the base rate rather than deemed always invalid
+	if rate := m.rates["account:new"]; rate > 0.3 {
+		t.Errorf("rate of a seldom reviewed signal = %v, want it pulled towards the base rate", rate)
+	}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model/types"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// ErrReportRejected is returned when a v3 report is rejected. The issues found are attached as extras.
+var ErrReportRejected = pgerr.New(fiber.StatusBadRequest, pgerr.CodeInvalidReport, "report has been rejected")
+
+type reportDropKey struct {
+	ItemID   int
+	DropType string
+}
+
+// PreprocessAndQueueV3Report verifies the report against the stage and drop infos currently open on the server,
+// and queues it unless any issue that rejects it has been found. The issues that do not reject the report
+// are returned as warnings.
+func (s *Report) PreprocessAndQueueV3Report(ctx context.Context, origin *types.ReportOrigin, req *types.V3ReportRequest) (*modelv3.ReportResponse, error) {
+	if origin.AccountID == 0 {
+		return nil, ErrAccountMissing
+	}
+
+	drops, warnings, err := s.verifyV3Report(ctx, req, origin.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	singleReport := &types.ReportTaskSingleReport{
+		FragmentStageID: types.FragmentStageID{StageID: req.StageID},
+		Drops:           drops,
+		Times:           req.Times,
+		Metadata:        req.Metadata,
+	}
+	common := types.FragmentReportCommon{
+		Server:  req.Server,
+		Source:  req.Client.Source,
+		Version: req.Client.Version,
+	}
+	taskId, err := s.queueSingleReport(ctx, origin, common, singleReport)
+	if err != nil {
+		return nil, err
+	}
+
+	return &modelv3.ReportResponse{
+		ReportHash: taskId,
+		Warnings:   warnings,
+	}, nil
+}
+
+func (s *Report) verifyV3Report(ctx context.Context, req *types.V3ReportRequest, accountId int) ([]*types.Drop, []*modelv3.ReportIssue, error) {
+	stage, err := s.StageService.GetStageByArkId(ctx, req.StageID)
+	if errors.Is(err, pgerr.ErrNotFound) {
+		return nil, nil, rejectReport(&modelv3.ReportIssue{
+			Code:    modelv3.ReportIssueStageNotFound,
+			Field:   "stageId",
+			Message: fmt.Sprintf("stage %s does not exist", req.StageID),
+		})
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	dropInfos, err := s.DropInfoRepo.GetForCurrentTimeRange(ctx, &repo.DropInfoQuery{
+		Server:     req.Server,
+		ArkStageId: req.StageID,
+	})
+	if err != nil && !errors.Is(err, pgerr.ErrNotFound) {
+		return nil, nil, err
+	}
+	if len(dropInfos) == 0 {
+		return nil, nil, rejectReport(&modelv3.ReportIssue{
+			Code:    modelv3.ReportIssueStageNotOpen,
+			Field:   "stageId",
+			Message: fmt.Sprintf("stage %s is not open on server %s", req.StageID, req.Server),
+		})
+	}
+
+	itemsMap, err := s.ItemService.GetItemsMapByArkId(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	expected := make(map[reportDropKey]struct{}, len(dropInfos))
+	for _, dropInfo := range dropInfos {
+		if dropInfo.ItemID.Valid && dropInfo.DropType != constant.DropTypeRecognitionOnly {
+			expected[reportDropKey{ItemID: int(dropInfo.ItemID.Int64), DropType: dropInfo.DropType}] = struct{}{}
+		}
+	}
+
+	var issues, warnings []*modelv3.ReportIssue
+	reported := make(map[reportDropKey]int, len(req.Drops))
+	drops := make([]*types.Drop, 0, len(req.Drops))
+	for i, drop := range req.Drops {
+		field := fmt.Sprintf("drops[%d]", i)
+		item, ok := itemsMap[drop.ItemID]
+		if !ok {
+			issues = append(issues, &modelv3.ReportIssue{
+				Code:    modelv3.ReportIssueItemNotFound,
+				Field:   field + ".itemId",
+				Message: fmt.Sprintf("item %s does not exist", drop.ItemID),
+			})
+			continue
+		}
+
+		key := reportDropKey{ItemID: item.ItemID, DropType: constant.DropTypeMap[drop.DropType]}
+		if _, ok := reported[key]; ok {
+			issues = append(issues, &modelv3.ReportIssue{
+				Code:    modelv3.ReportIssueDuplicateDrop,
+				Field:   field,
+				Message: fmt.Sprintf("item %s has already been reported as %s", drop.ItemID, drop.DropType),
+			})
+			continue
+		}
+		reported[key] = drop.Quantity
+
+		if _, ok := expected[key]; !ok {
+			issues = append(issues, &modelv3.ReportIssue{
+				Code:    modelv3.ReportIssueUnexpectedDrop,
+				Field:   field,
+				Message: fmt.Sprintf("item %s is not expected to drop from %s as %s", drop.ItemID, req.StageID, drop.DropType),
+			})
+			continue
+		}
+
+		drops = append(drops, &types.Drop{
+			DropType: key.DropType,
+			ItemID:   item.ItemID,
+			Quantity: drop.Quantity,
+		})
+	}
+	if len(issues) > 0 {
+		return nil, nil, rejectReport(issues...)
+	}
+
+	// the times of gachabox reports are derived from the quantities later on, so their bounds do not apply here.
+	// Iterate over the drop infos rather than the map to keep the warnings in a stable order.
+	gachabox := stage.ExtraProcessType.Valid && stage.ExtraProcessType.String == constant.ExtraProcessTypeGachaBox
+	for _, dropInfo := range dropInfos {
+		if gachabox || !dropInfo.ItemID.Valid || dropInfo.DropType == constant.DropTypeRecognitionOnly || dropInfo.Bounds == nil {
+			continue
+		}
+		quantity := reported[reportDropKey{ItemID: int(dropInfo.ItemID.Int64), DropType: dropInfo.DropType}]
+		lower, upper := dropInfo.Bounds.Lower*req.Times, dropInfo.Bounds.Upper*req.Times
+		if quantity < lower || quantity > upper {
+			warnings = append(warnings, &modelv3.ReportIssue{
+				Code:    modelv3.ReportIssueQuantityOutOfBounds,
+				Field:   "drops",
+				Message: fmt.Sprintf("item %d dropped as %s is expected to have a quantity between %d and %d, but got %d; the report will be excluded from the statistics", dropInfo.ItemID.Int64, constant.DropTypeReversedMap[dropInfo.DropType], lower, upper, quantity),
+			})
+		}
+	}
+
+	if s.FeatureFlags.Enabled(FlagRareDropWarnings, req.Server, strconv.Itoa(accountId)) {
+		warnings = append(warnings, s.rareDropWarnings(ctx, req)...)
+	}
+	if warnings == nil {
+		warnings = make([]*modelv3.ReportIssue, 0)
+	}
+
+	return drops, warnings, nil
+}
+
+// rareDropWarnings warns about the items that have rarely been reported to drop from the stage. Failing to get
+// the drop matrix only skips the warnings, since they are not essential to the report.
+func (s *Report) rareDropWarnings(ctx context.Context, req *types.V3ReportRequest) []*modelv3.ReportIssue {
+	matrix, err := s.DropMatrixService.GetShimDropMatrix(ctx, req.Server, false, "", "", null.NewInt(0, false), constant.SourceCategoryAll)
+	if err != nil {
+		log.Warn().Err(err).Str("server", req.Server).Msg("failed to get drop matrix for rare drop warnings")
+		return nil
+	}
+
+	minTimes, rareRate := s.Tunables.Int(TunableRareDropMinTimes), s.Tunables.Float(TunableRareDropRate)
+	rates := make(map[string]float64)
+	for _, el := range matrix.Matrix {
+		if el.StageID == req.StageID && el.Times >= minTimes {
+			rates[el.ItemID] = float64(el.Quantity) / float64(el.Times)
+		}
+	}
+
+	var warnings []*modelv3.ReportIssue
+	for i, drop := range req.Drops {
+		if rate, ok := rates[drop.ItemID]; ok && rate < rareRate {
+			warnings = append(warnings, &modelv3.ReportIssue{
+				Code:    modelv3.ReportIssueItemRarelyDrops,
+				Field:   fmt.Sprintf("drops[%d].itemId", i),
+				Message: fmt.Sprintf("item %s has rarely been reported to drop from %s (%.2f%%)", drop.ItemID, req.StageID, rate*100),
+			})
+		}
+	}
+	return warnings
+}
+
+func rejectReport(issues ...*modelv3.ReportIssue) error {
+	return ErrReportRejected.WithExtras(pgerr.Extras{
+		"issues": issues,
+	})
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+	"exusiai.dev/backend-next/internal/util"
+)
+
+const (
+	// purgedBeforePropertyKey is the property of the archive date of the first day that has not been purged
+	purgedBeforePropertyKey = "drop_reports_purged_before"
+
+	// retentionRefreshInterval is the interval in-between the checks of the days purged by the leader
+	retentionRefreshInterval = time.Minute
+)
+
+var ErrArchiveReadbackTooLarge = pgerr.New(fiber.StatusBadRequest, pgerr.CodeInvalidRequest, "the time range covers too many days that have been moved to the archive, please narrow it down")
+
+// ErrPurgedTimeRange is returned for the calculations starting before the reports purged from the database, which would
+// count the remaining reports only
+var ErrPurgedTimeRange = pgerr.New(fiber.StatusBadRequest, pgerr.CodeInvalidRequest, "the time range starts before the reports that have been moved to the archive")
+
+// Retention purges the drop reports of the days that have been archived and verified from the database, oldest day
+// first, as a daily singleton background job. The purged days are then read back from the archives on demand.
+type Retention struct {
+	Config            *appconfig.Config
+	DropReportService *DropReport
+	PropertyRepo      *repo.Property
+	ArchiveService    *Archive
+
+	// purgedBefore is the start of the first day that has not been purged, nil if none has been
+	purgedBefore atomic.Pointer[time.Time]
+}
+
+func NewRetention(conf *appconfig.Config, dropReportService *DropReport, propertyRepo *repo.Property, archiveService *Archive, jobs *Jobs, lc fx.Lifecycle) (*Retention, error) {
+	if conf.DropReportRetentionDays > 0 && conf.DropReportRetentionDays < conf.NoArchiveDays {
+		return nil, errors.Errorf("drop report retention days (%d) cannot be less than the no archive days (%d)", conf.DropReportRetentionDays, conf.NoArchiveDays)
+	}
+
+	s := &Retention{
+		Config:            conf,
+		DropReportService: dropReportService,
+		PropertyRepo:      propertyRepo,
+		ArchiveService:    archiveService,
+	}
+	if conf.DropReportRetentionDays > 0 {
+		jobs.Register(model.JobKindPurge, JobPolicy{
+			MaxAttempts: 3,
+			Backoff:     time.Minute * 30,
+			Timeout:     time.Hour * 2,
+			Every:       time.Hour * 24,
+			Singleton:   true,
+		}, s.purge)
+	}
+	watchEvery(lc, "retention", retentionRefreshInterval, s.refresh)
+	return s, nil
+}
+
+func (s *Retention) refresh(ctx context.Context) error {
+	day, ok, err := s.getPurgedBefore(ctx)
+	if err != nil || !ok {
+		return err
+	}
+	start := time.UnixMilli(archiveDayStart(day))
+	s.purgedBefore.Store(&start)
+	return nil
+}
+
+// PurgedBefore returns the time before which the drop reports have been purged from the database, and false if
+// none has been
+func (s *Retention) PurgedBefore() (time.Time, bool) {
+	t := s.purgedBefore.Load()
+	if t == nil {
+		return time.Time{}, false
+	}
+	return *t, true
+}
+
+// CheckNotPurged rejects the calculations starting before the reports purged from the database, of which the results
+// would be calculated from a part of their reports only, and once saved replace the complete ones
+func (s *Retention) CheckNotPurged(start time.Time) error {
+	purgedBefore, ok := s.PurgedBefore()
+	if ok && start.Before(purgedBefore) {
+		return ErrPurgedTimeRange.Msg("the time range starts before %s, before which the reports have been moved to the archive", purgedBefore.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (s *Retention) purge(ctx context.Context, _ *model.Job) error {
+	next, ok, err := s.getPurgedBefore(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		oldest, err := s.DropReportService.GetOldestDropReportTime(ctx)
+		if err != nil || !oldest.Valid {
+			return err
+		}
+		next = archiveDate(oldest.Time.In(constant.LocMap["CN"]))
+	}
+
+	cutoff := archiveDate(time.Now().In(constant.LocMap["CN"]).AddDate(0, 0, -s.Config.DropReportRetentionDays))
+	for day := next; day.Before(cutoff); day = day.AddDate(0, 0, 1) {
+		if err := s.ArchiveService.VerifyArchive(ctx, day); err != nil {
+			return errors.Wrap(err, "failed to verify archive")
+		}
+
+		first, last, err := s.DropReportService.GetReportIdRangeForArchive(ctx, day)
+		if err != nil {
+			return err
+		}
+		if first != 0 {
+			if err := s.ArchiveService.DeleteReportsAndExtras(ctx, day, first, last); err != nil {
+				return err
+			}
+		}
+
+		if err := s.setPurgedBefore(ctx, day.AddDate(0, 0, 1)); err != nil {
+			return err
+		}
+		log.Info().
+			Str("evt.name", "retention.purged").
+			Str("date", day.Format("2006-01-02")).
+			Msg("purged archived drop reports from the database")
+	}
+	return nil
+}
+
+func (s *Retention) getPurgedBefore(ctx context.Context) (time.Time, bool, error) {
+	property, err := s.PropertyRepo.GetPropertyByKey(ctx, purgedBeforePropertyKey)
+	if errors.Is(err, pgerr.ErrNotFound) {
+		return time.Time{}, false, nil
+	} else if err != nil {
+		return time.Time{}, false, err
+	}
+	day, err := time.Parse("2006-01-02", property.Value)
+	if err != nil {
+		return time.Time{}, false, errors.Wrap(err, "invalid purged before property")
+	}
+	return day, true, nil
+}
+
+func (s *Retention) setPurgedBefore(ctx context.Context, day time.Time) error {
+	value := day.Format("2006-01-02")
+	_, err := s.PropertyRepo.UpdatePropertyByKey(ctx, purgedBeforePropertyKey, value)
+	if errors.Is(err, pgerr.ErrNotFound) {
+		_, err = s.PropertyRepo.CreateProperty(ctx, purgedBeforePropertyKey, value)
+	}
+	if err != nil {
+		return err
+	}
+	start := time.UnixMilli(archiveDayStart(day))
+	s.purgedBefore.Store(&start)
+	return nil
+}
+
+// ReadPurgedDropReports reads the drop reports created within the time range that have been purged from the database
+// back from the archives, keeping those matched by the filter in chronological order. Either bound may be nil, although
+// a time range with no start spans too many days to be read back.
+func (s *Retention) ReadPurgedDropReports(ctx context.Context, start, end *time.Time, filter func(report *model.DropReport) bool) ([]*model.DropReport, error) {
+	purgedBefore, ok := s.PurgedBefore()
+	if !ok || (start != nil && !start.Before(purgedBefore)) {
+		return nil, nil
+	}
+	if start == nil {
+		return nil, ErrArchiveReadbackTooLarge
+	}
+	until := purgedBefore
+	if end != nil && end.Before(until) {
+		until = *end
+	}
+
+	loc := constant.LocMap["CN"]
+	first, last := archiveDate(start.In(loc)), archiveDate(until.Add(-time.Nanosecond).In(loc))
+	if int(last.Sub(first).Hours()/24)+1 > s.Config.ArchiveReadbackMaxDays {
+		return nil, ErrArchiveReadbackTooLarge
+	}
+
+	reports := make([]*model.DropReport, 0)
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		err := s.ArchiveService.ReadDropReports(ctx, day, func(report *model.DropReport) error {
+			if report.CreatedAt != nil && !report.CreatedAt.Before(*start) && report.CreatedAt.Before(until) && filter(report) {
+				reports = append(reports, report)
+			}
+			return nil
+		})
+		if errors.Is(err, ErrArchiveNotFound) {
+			// the days without any report may not have been archived
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return reports, nil
+}
+
+// archiveDayStart returns the start of the day of the archive date in unix milliseconds
+func archiveDayStart(day time.Time) int64 {
+	return util.GetDayStartTime(&day, "CN")
+}
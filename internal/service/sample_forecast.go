@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// sampleForecastCacheTTL is how long the forecasts are cached for, which is short relative to the window
+const sampleForecastCacheTTL = time.Minute * 10
+
+// SampleForecast estimates when the samples of the stages reach a number of times
+type SampleForecast struct {
+	DropReportRepo   *repo.DropReport
+	StageService     *Stage
+	TimeRangeService *TimeRange
+	Tunables         *Tunables
+}
+
+func NewSampleForecast(dropReportRepo *repo.DropReport, stageService *Stage, timeRangeService *TimeRange, tunables *Tunables) *SampleForecast {
+	return &SampleForecast{
+		DropReportRepo:   dropReportRepo,
+		StageService:     stageService,
+		TimeRangeService: timeRangeService,
+		Tunables:         tunables,
+	}
+}
+
+// GetSampleForecast estimates when the sample of the stage in its time range currently in effect in the server
+// reaches target times, or the tunable default when target is 0
+// Cache: sampleForecast#server|arkStageId|target:{server}|{arkStageId}|{target}, 10 min
+func (s *SampleForecast) GetSampleForecast(ctx context.Context, server string, arkStageId string, target int) (*modelv3.SampleForecast, error) {
+	if target <= 0 {
+		target = s.Tunables.Int(TunableForecastTargetTimes)
+	}
+
+	key := server + "|" + arkStageId + "|" + strconv.Itoa(target)
+	var forecast modelv3.SampleForecast
+	_, err := cache.SampleForecast.MutexGetSet(key, &forecast, func() (*modelv3.SampleForecast, error) {
+		return s.calcSampleForecast(ctx, server, arkStageId, target)
+	}, sampleForecastCacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &forecast, nil
+}
+
+func (s *SampleForecast) calcSampleForecast(ctx context.Context, server string, arkStageId string, target int) (*modelv3.SampleForecast, error) {
+	stage, err := s.StageService.GetStageByArkId(ctx, arkStageId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	latestTimeRanges, err := s.TimeRangeService.GetLatestTimeRangesByServer(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	timeRange, ok := latestTimeRanges[stage.StageID]
+	if !ok || !timeRange.Includes(now) {
+		return nil, pgerr.ErrNotFound.Msg("stage %s has no drop infos in effect in %s", arkStageId, server)
+	}
+
+	times, err := s.calcTimesSince(ctx, server, stage.StageID, *timeRange.StartTime, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// the window is clipped to the time range so that a stage opened recently is not underestimated
+	windowStart := now.Add(-s.Tunables.Duration(TunableForecastWindow))
+	if windowStart.Before(*timeRange.StartTime) {
+		windowStart = *timeRange.StartTime
+	}
+	recentTimes, err := s.calcTimesSince(ctx, server, stage.StageID, windowStart, now)
+	if err != nil {
+		return nil, err
+	}
+
+	forecast := &modelv3.SampleForecast{
+		StageID:     arkStageId,
+		Server:      server,
+		TimeRange:   timeRange,
+		Times:       times,
+		Target:      target,
+		WindowHours: now.Sub(windowStart).Hours(),
+		Reached:     times >= target,
+		GeneratedAt: now,
+	}
+	if forecast.WindowHours > 0 {
+		forecast.RatePerHour = float64(recentTimes) / forecast.WindowHours
+	}
+	if forecast.Reached || forecast.RatePerHour <= 0 {
+		return forecast, nil
+	}
+
+	// open-ended time ranges end so far away that the estimate may not fit in a time.Duration
+	remainingHours := float64(target-times) / forecast.RatePerHour
+	if remainingHours > timeRange.EndTime.Sub(now).Hours() || remainingHours > math.MaxInt64/float64(time.Hour) {
+		return forecast, nil
+	}
+	estimatedAt := now.Add(time.Duration(remainingHours * float64(time.Hour)))
+	forecast.EstimatedAt = &estimatedAt
+	return forecast, nil
+}
+
+func (s *SampleForecast) calcTimesSince(ctx context.Context, server string, stageId int, startTime time.Time, endTime time.Time) (int, error) {
+	results, err := s.DropReportRepo.CalcTotalTimes(ctx, &model.DropReportQueryContext{
+		Server:          server,
+		StartTime:       &startTime,
+		EndTime:         &endTime,
+		StageItemFilter: &map[int][]int{stageId: {}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	times := 0
+	for _, result := range results {
+		times += result.TotalTimes
+	}
+	return times, nil
+}
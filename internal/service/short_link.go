@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+const (
+	// shortLinkCodeAlphabet is the alphabet of the codes, which are case sensitive
+	shortLinkCodeAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// shortLinkCodeLen is the length of a generated code
+	shortLinkCodeLen = 8
+	// shortLinkMaxItems bounds the items filtered by a query of a link
+	shortLinkMaxItems = 100
+)
+
+// ShortLink stores the advanced queries under short codes, so that the matrices and trends filtered by them can be
+// shared as permalinks. Queries are validated against the metadata and normalized before being stored, so that
+// the same query is always shared under the same code.
+type ShortLink struct {
+	ShortLinkRepo *repo.ShortLink
+	StageService  *Stage
+	ItemService   *Item
+	Tunables      *Tunables
+}
+
+func NewShortLink(shortLinkRepo *repo.ShortLink, stageService *Stage, itemService *Item, tunables *Tunables, jobs *Jobs) *ShortLink {
+	s := &ShortLink{
+		ShortLinkRepo: shortLinkRepo,
+		StageService:  stageService,
+		ItemService:   itemService,
+		Tunables:      tunables,
+	}
+	jobs.Register(model.JobKindShortLinkPrune, JobPolicy{
+		MaxAttempts: 2,
+		Backoff:     time.Minute * 10,
+		Timeout:     time.Minute * 10,
+		Every:       time.Hour * 24,
+		Singleton:   true,
+	}, s.prune)
+	return s
+}
+
+// CreateShortLink stores the query under a new code, or returns the link of the same query with its expiry extended
+// when it has been shared already
+func (s *ShortLink) CreateShortLink(ctx context.Context, req *types.AdvancedQueryRequest) (*modelv3.ShortLink, error) {
+	if err := s.normalize(ctx, req); err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(payload)
+
+	code, err := generateShortLinkCode()
+	if err != nil {
+		return nil, err
+	}
+	link := &model.ShortLink{
+		Code:        code,
+		PayloadHash: hex.EncodeToString(sum[:]),
+		Payload:     payload,
+		ExpiresAt:   time.Now().Add(s.Tunables.Duration(TunableShortLinkTTL)),
+	}
+	if err := s.ShortLinkRepo.SaveShortLink(ctx, link); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("evt.name", "shortlink.saved").
+		Str("code", link.Code).
+		Time("expiresAt", link.ExpiresAt).
+		Msg("saved short link")
+
+	return toShortLinkV3(link)
+}
+
+// ResolveShortLink returns the query stored under the code, or ErrNotFound when there is none or it has expired
+func (s *ShortLink) ResolveShortLink(ctx context.Context, code string) (*modelv3.ShortLink, error) {
+	link, err := s.ShortLinkRepo.GetShortLinkByCode(ctx, code, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return toShortLinkV3(link)
+}
+
+// normalize checks that the stages and items queried exist, and sorts and deduplicates the items filtered in place
+func (s *ShortLink) normalize(ctx context.Context, req *types.AdvancedQueryRequest) error {
+	for i, query := range req.Queries {
+		if _, err := s.StageService.GetStageByArkId(ctx, query.StageID); err != nil {
+			if errors.Is(err, pgerr.ErrNotFound) {
+				return pgerr.ErrInvalidReq.Msg("queries[%d]: unknown stage %q", i, query.StageID)
+			}
+			return err
+		}
+
+		if len(query.ItemIDs) > shortLinkMaxItems {
+			return pgerr.ErrInvalidReq.Msg("queries[%d]: at most %d items can be filtered", i, shortLinkMaxItems)
+		}
+		itemIds := make([]string, 0, len(query.ItemIDs))
+		seen := make(map[string]struct{}, len(query.ItemIDs))
+		for _, itemId := range query.ItemIDs {
+			if _, ok := seen[itemId]; ok {
+				continue
+			}
+			seen[itemId] = struct{}{}
+			if _, err := s.ItemService.GetItemByArkId(ctx, itemId); err != nil {
+				if errors.Is(err, pgerr.ErrNotFound) {
+					return pgerr.ErrInvalidReq.Msg("queries[%d]: unknown item %q", i, itemId)
+				}
+				return err
+			}
+			itemIds = append(itemIds, itemId)
+		}
+		sort.Strings(itemIds)
+		query.ItemIDs = itemIds
+	}
+	return nil
+}
+
+func (s *ShortLink) prune(ctx context.Context, _ *model.Job) error {
+	deleted, err := s.ShortLinkRepo.DeleteExpiredShortLinks(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("evt.name", "shortlink.pruned").
+		Int64("deleted", deleted).
+		Msg("pruned expired short links")
+	return nil
+}
+
+func generateShortLinkCode() (string, error) {
+	base := big.NewInt(int64(len(shortLinkCodeAlphabet)))
+	b := make([]byte, shortLinkCodeLen)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, base)
+		if err != nil {
+			return "", err
+		}
+		b[i] = shortLinkCodeAlphabet[n.Int64()]
+	}
+	return string(b), nil
+}
+
+func toShortLinkV3(link *model.ShortLink) (*modelv3.ShortLink, error) {
+	var query types.AdvancedQueryRequest
+	if err := json.Unmarshal(link.Payload, &query); err != nil {
+		return nil, err
+	}
+	return &modelv3.ShortLink{
+		Code:      link.Code,
+		Query:     &query,
+		CreatedAt: link.CreatedAt,
+		ExpiresAt: link.ExpiresAt,
+	}, nil
+}
@@ -4,9 +4,6 @@ import (
 	"context"
 	"net/url"
 
-	"github.com/gofiber/fiber/v2"
-
-	"exusiai.dev/backend-next/internal/util"
 	"exusiai.dev/gommon/constant"
 )
 
@@ -28,8 +25,8 @@ func NewShortURL(itemService *Item, stageService *Stage, zoneService *Zone, geoI
 
 // siteURL returns the site URL with path appended from toPath.
 // toPath is expected to always start with a slash.
-func (s *ShortURL) siteURL(ctx *fiber.Ctx, toPath string) string {
-	ip := util.ExtractIP(ctx)
+// The mirror host is picked from the client ip.
+func (s *ShortURL) siteURL(ip, toPath string) string {
 	var host string
 	if s.GeoIPService.InChinaMainland(ip) {
 		host = constant.SiteChinaMainlandMirrorHost
@@ -40,45 +37,45 @@ func (s *ShortURL) siteURL(ctx *fiber.Ctx, toPath string) string {
 	return "https://" + host + toPath
 }
 
-func (s *ShortURL) Resolve(ctx *fiber.Ctx, path string) string {
+func (s *ShortURL) Resolve(ctx context.Context, ip, path string) string {
 	defaultPath := "/?utm_source=exusiai&utm_medium=root&utm_campaign=root"
 	if path == "" || len(path) > 128 {
-		return s.siteURL(ctx, defaultPath)
+		return s.siteURL(ip, defaultPath)
 	}
 
 	escapedPath, err := url.PathUnescape(path)
 	if err != nil {
-		return s.siteURL(ctx, defaultPath)
+		return s.siteURL(ip, defaultPath)
 	}
 	path = escapedPath
 
 	// Simple Keyword Matching
 	if path == "item" {
-		return s.siteURL(ctx, "/result/item")
+		return s.siteURL(ip, "/result/item")
 	}
 	if path == "stage" {
-		return s.siteURL(ctx, "/result/stage")
+		return s.siteURL(ip, "/result/stage")
 	}
 	if path == "planner" {
-		return s.siteURL(ctx, "/planner")
+		return s.siteURL(ip, "/planner")
 	}
 
 	// Item Name Matching
-	if resolved, err := s.resolveByItemName(ctx.UserContext(), path); err == nil {
-		return s.siteURL(ctx, resolved)
+	if resolved, err := s.resolveByItemName(ctx, path); err == nil {
+		return s.siteURL(ip, resolved)
 	}
-	if resolved, err := s.resolveByStageCode(ctx.UserContext(), path); err == nil {
-		return s.siteURL(ctx, resolved)
+	if resolved, err := s.resolveByStageCode(ctx, path); err == nil {
+		return s.siteURL(ip, resolved)
 	}
-	if resolved, err := s.resolveByItemId(ctx.UserContext(), path); err == nil {
-		return s.siteURL(ctx, resolved)
+	if resolved, err := s.resolveByItemId(ctx, path); err == nil {
+		return s.siteURL(ip, resolved)
 	}
-	if resolved, err := s.resolveByStageId(ctx.UserContext(), path); err == nil {
-		return s.siteURL(ctx, resolved)
+	if resolved, err := s.resolveByStageId(ctx, path); err == nil {
+		return s.siteURL(ip, resolved)
 	}
 
 	resolved := s.resolveUnknown(path)
-	return s.siteURL(ctx, resolved)
+	return s.siteURL(ip, resolved)
 }
 
 func (s *ShortURL) resolveByItemName(ctx context.Context, path string) (string, error) {
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"exusiai.dev/gommon/constant"
+	"gopkg.in/guregu/null.v3"
+
+	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+)
+
+// SimulationMaxRuns is the most runs simulated at once
+const SimulationMaxRuns = 1_000_000
+
+// Simulation computes the expected drops of clearing a stage a number of times, so that the planners need not
+// each re-implement the math
+type Simulation struct {
+	DropMatrixService    *DropMatrix
+	PatternMatrixService *PatternMatrix
+	StageService         *Stage
+}
+
+func NewSimulation(dropMatrixService *DropMatrix, patternMatrixService *PatternMatrix, stageService *Stage) *Simulation {
+	return &Simulation{
+		DropMatrixService:    dropMatrixService,
+		PatternMatrixService: patternMatrixService,
+		StageService:         stageService,
+	}
+}
+
+// Simulate returns the expected quantity and the variance of each item dropped from the stage over the runs, from
+// the latest elements of the stage in the global matrices of the server. The runs are assumed to be independent,
+// so that the variance of the total is runs times the variance of a single run.
+func (s *Simulation) Simulate(ctx context.Context, server string, arkStageId string, runs int) (*modelv3.Simulation, error) {
+	if runs <= 0 || runs > SimulationMaxRuns {
+		return nil, pgerr.ErrInvalidReq.Msg("runs must be between 1 and %d", SimulationMaxRuns)
+	}
+	if _, err := s.StageService.GetStageByArkId(ctx, arkStageId); err != nil {
+		return nil, err
+	}
+
+	// the global matrices are cached as a whole: filtering them in place is cheaper than querying them filtered
+	dropMatrix, err := s.DropMatrixService.GetShimDropMatrix(ctx, server, true, "", "", null.NewInt(0, false), constant.SourceCategoryAll)
+	if err != nil {
+		return nil, err
+	}
+	patternMatrix, err := s.PatternMatrixService.GetShimPatternMatrix(ctx, server, null.NewInt(0, false), constant.SourceCategoryAll, true)
+	if err != nil {
+		return nil, err
+	}
+
+	simulation := &modelv3.Simulation{
+		StageID:  arkStageId,
+		Server:   server,
+		Runs:     runs,
+		Items:    make([]*modelv3.SimulatedItem, 0),
+		Patterns: make([]*modelv3.SimulatedDrops, 0),
+	}
+
+	elements := latestDropMatrixElements(dropMatrix.Matrix, arkStageId)
+	if len(elements) == 0 {
+		return nil, pgerr.ErrNotFound.Msg("stage %s has no drop matrix in %s", arkStageId, server)
+	}
+	n := float64(runs)
+	for _, el := range elements {
+		if el.Times > simulation.SampleTimes {
+			simulation.SampleTimes = el.Times
+		}
+		variance := n * el.StdDev * el.StdDev
+		simulation.Items = append(simulation.Items, &modelv3.SimulatedItem{
+			ItemID:   el.ItemID,
+			Expected: n * float64(el.Quantity) / float64(el.Times),
+			Variance: variance,
+			StdDev:   math.Sqrt(variance),
+		})
+	}
+
+	// the probability of an item dropping in a single run is the sum of those of the patterns it is dropped in
+	runProbabilities := make(map[string]float64)
+	for _, el := range latestPatternMatrixElements(patternMatrix.PatternMatrix, arkStageId) {
+		probability := float64(el.Quantity) / float64(el.Times)
+		drops := make([]*modelv3.SimulatedDrop, 0, len(el.Pattern.Drops))
+		for _, drop := range el.Pattern.Drops {
+			drops = append(drops, &modelv3.SimulatedDrop{ItemID: drop.ItemID, Quantity: drop.Quantity})
+			if drop.Quantity > 0 {
+				runProbabilities[drop.ItemID] += probability
+			}
+		}
+		simulation.Patterns = append(simulation.Patterns, &modelv3.SimulatedDrops{
+			Drops:       drops,
+			Probability: probability,
+			Expected:    n * probability,
+		})
+	}
+	if len(simulation.Patterns) > 0 {
+		for _, item := range simulation.Items {
+			if p, ok := runProbabilities[item.ItemID]; ok {
+				atLeastOnce := 1 - math.Pow(1-math.Min(p, 1), n)
+				item.AtLeastOnce = &atLeastOnce
+			}
+		}
+	}
+
+	sort.Slice(simulation.Items, func(i, j int) bool {
+		return simulation.Items[i].Expected > simulation.Items[j].Expected
+	})
+	sort.Slice(simulation.Patterns, func(i, j int) bool {
+		return simulation.Patterns[i].Probability > simulation.Patterns[j].Probability
+	})
+	return simulation, nil
+}
+
+// latestDropMatrixElements returns the elements of the stage in the time range it has been opened in the latest
+func latestDropMatrixElements(matrix []*modelv2.OneDropMatrixElement, arkStageId string) []*modelv2.OneDropMatrixElement {
+	var latest int64
+	for _, el := range matrix {
+		if el.StageID == arkStageId && el.StartTime > latest {
+			latest = el.StartTime
+		}
+	}
+	elements := make([]*modelv2.OneDropMatrixElement, 0)
+	for _, el := range matrix {
+		if el.StageID == arkStageId && el.StartTime == latest && el.Times > 0 {
+			elements = append(elements, el)
+		}
+	}
+	return elements
+}
+
+// latestPatternMatrixElements returns the elements of the stage in the time range it has been opened in the latest
+func latestPatternMatrixElements(matrix []*modelv2.OnePatternMatrixElement, arkStageId string) []*modelv2.OnePatternMatrixElement {
+	var latest int64
+	for _, el := range matrix {
+		if el.StageID == arkStageId && el.StartTime > latest {
+			latest = el.StartTime
+		}
+	}
+	elements := make([]*modelv2.OnePatternMatrixElement, 0)
+	for _, el := range matrix {
+		if el.StageID == arkStageId && el.StartTime == latest && el.Times > 0 && el.Pattern != nil {
+			elements = append(elements, el)
+		}
+	}
+	return elements
+}
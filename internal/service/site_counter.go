@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+
+	"exusiai.dev/backend-next/internal/model/cache"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+)
+
+const (
+	siteCounterRedisPrefix = "sitestats:"
+
+	// reports are bucketed by hour for the rolling 24h count; buckets outlive the window by an hour
+	siteCounterHourlyBucketLifetime = time.Hour * 25
+
+	// ReportHeatmapMaxDays is the longest window of the report heatmaps, which the accepted reports are bucketed by
+	// hour for
+	ReportHeatmapMaxDays = 90
+	// accepted reports are bucketed by hour for the heatmaps; buckets outlive the longest window by an hour
+	siteCounterAcceptedHourlyBucketLifetime = time.Hour * (24*ReportHeatmapMaxDays + 1)
+)
+
+// SiteCounter maintains incremental counters in redis for the sitewide stats, so that the stats
+// never need to scan the drop reports table on request
+type SiteCounter struct {
+	Redis *redis.Client
+}
+
+func NewSiteCounter(redisClient *redis.Client) *SiteCounter {
+	return &SiteCounter{
+		Redis: redisClient,
+	}
+}
+
+func siteCounterReportsKey(server string) string {
+	return siteCounterRedisPrefix + "reports:" + server
+}
+
+func siteCounterHourlyReportsKey(server string, t time.Time) string {
+	return siteCounterRedisPrefix + "reports-hourly:" + server + ":" + strconv.FormatInt(t.Unix()/3600, 10)
+}
+
+func siteCounterAcceptedHourlyReportsKey(server string, hour int64) string {
+	return siteCounterRedisPrefix + "accepted-hourly:" + server + ":" + strconv.FormatInt(hour, 10)
+}
+
+func siteCounterReportersKey(server string) string {
+	return siteCounterRedisPrefix + "reporters:" + server
+}
+
+func siteCounterAccountsKey() string {
+	return siteCounterRedisPrefix + "accounts"
+}
+
+// RecordReports counts reports persisted for an account in a server, of which accepted have been accepted
+func (s *SiteCounter) RecordReports(ctx context.Context, server string, accountId int, count int, accepted int, at time.Time) error {
+	hourlyKey := siteCounterHourlyReportsKey(server, at)
+	acceptedHourlyKey := siteCounterAcceptedHourlyReportsKey(server, at.Unix()/3600)
+
+	_, err := s.Redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.IncrBy(ctx, siteCounterReportsKey(server), int64(count))
+		pipe.IncrBy(ctx, hourlyKey, int64(count))
+		pipe.Expire(ctx, hourlyKey, siteCounterHourlyBucketLifetime)
+		if accepted > 0 {
+			pipe.IncrBy(ctx, acceptedHourlyKey, int64(accepted))
+			pipe.Expire(ctx, acceptedHourlyKey, siteCounterAcceptedHourlyBucketLifetime)
+		}
+		pipe.PFAdd(ctx, siteCounterReportersKey(server), accountId)
+		return nil
+	})
+	return err
+}
+
+func (s *SiteCounter) RecordAccountCreated(ctx context.Context) error {
+	return s.Redis.Incr(ctx, siteCounterAccountsKey()).Err()
+}
+
+// SeedReports overwrites the all-time report count of a server and adds the given reporters,
+// for bootstrapping the counters from the database
+func (s *SiteCounter) SeedReports(ctx context.Context, server string, total int64, accountIds []int) error {
+	if err := s.Redis.Set(ctx, siteCounterReportsKey(server), total, 0).Err(); err != nil {
+		return err
+	}
+	return s.AddReporters(ctx, server, accountIds)
+}
+
+func (s *SiteCounter) AddReporters(ctx context.Context, server string, accountIds []int) error {
+	if len(accountIds) == 0 {
+		return nil
+	}
+	members := make([]any, 0, len(accountIds))
+	for _, accountId := range accountIds {
+		members = append(members, accountId)
+	}
+	return s.Redis.PFAdd(ctx, siteCounterReportersKey(server), members...).Err()
+}
+
+func (s *SiteCounter) SeedAccounts(ctx context.Context, total int64) error {
+	return s.Redis.Set(ctx, siteCounterAccountsKey(), total, 0).Err()
+}
+
+// Cache: (singular) siteStats, 1 min
+func (s *SiteCounter) GetSiteStats(ctx context.Context) (*modelv3.SiteStats, error) {
+	var results modelv3.SiteStats
+	err := cache.SiteStats.Get(&results)
+	if err == nil {
+		return &results, nil
+	}
+
+	stats, err := s.calcSiteStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cache.SiteStats.Set(*stats, time.Minute)
+	return stats, nil
+}
+
+func (s *SiteCounter) calcSiteStats(ctx context.Context) (*modelv3.SiteStats, error) {
+	now := time.Now()
+
+	pipe := s.Redis.Pipeline()
+	totals := make(map[string]*redis.StringCmd, len(constant.Servers))
+	hourlies := make(map[string][]*redis.StringCmd, len(constant.Servers))
+	reporters := make(map[string]*redis.IntCmd, len(constant.Servers))
+	reportersKeys := make([]string, 0, len(constant.Servers))
+	for _, server := range constant.Servers {
+		totals[server] = pipe.Get(ctx, siteCounterReportsKey(server))
+		for i := 0; i < 24; i++ {
+			hourlies[server] = append(hourlies[server], pipe.Get(ctx, siteCounterHourlyReportsKey(server, now.Add(-time.Duration(i)*time.Hour))))
+		}
+		reporters[server] = pipe.PFCount(ctx, siteCounterReportersKey(server))
+		reportersKeys = append(reportersKeys, siteCounterReportersKey(server))
+	}
+	allReporters := pipe.PFCount(ctx, reportersKeys...)
+	accounts := pipe.Get(ctx, siteCounterAccountsKey())
+	// missing counters are reported as redis.Nil per command, and are treated as zero below
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	stats := &modelv3.SiteStats{
+		UniqueReporters: allReporters.Val(),
+		TotalAccounts:   counterValue(accounts),
+		Servers:         make(map[string]*modelv3.ServerSiteStats, len(constant.Servers)),
+	}
+	for _, server := range constant.Servers {
+		serverStats := &modelv3.ServerSiteStats{
+			TotalReports:    counterValue(totals[server]),
+			UniqueReporters: reporters[server].Val(),
+		}
+		for _, hourly := range hourlies[server] {
+			serverStats.Reports24H += counterValue(hourly)
+		}
+		stats.TotalReports += serverStats.TotalReports
+		stats.Reports24H += serverStats.Reports24H
+		stats.Servers[server] = serverStats
+	}
+	return stats, nil
+}
+
+// GetReportHeatmap counts the reports accepted in the server within the last days into a weekday by hour matrix, in
+// the time zone of the server
+// Cache: reportHeatmap#server|days:{server}|{days}, 10 min
+func (s *SiteCounter) GetReportHeatmap(ctx context.Context, server string, days int) (*modelv3.ReportHeatmap, error) {
+	var heatmap modelv3.ReportHeatmap
+	key := server + "|" + strconv.Itoa(days)
+	err := cache.ReportHeatmap.Get(key, &heatmap)
+	if err == nil {
+		return &heatmap, nil
+	}
+
+	now := time.Now()
+	loc := constant.LocMap[server]
+	// the current hour is incomplete, and is left out
+	last := now.Unix()/3600 - 1
+	hours := make([]int64, 0, days*24)
+	keys := make([]string, 0, days*24)
+	for hour := last - int64(days*24) + 1; hour <= last; hour++ {
+		hours = append(hours, hour)
+		keys = append(keys, siteCounterAcceptedHourlyReportsKey(server, hour))
+	}
+	values, err := s.Redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	heatmap = modelv3.ReportHeatmap{
+		Server:      server,
+		Timezone:    loc.String(),
+		Days:        days,
+		Matrix:      make([][]int64, 7),
+		GeneratedAt: now,
+	}
+	for weekday := range heatmap.Matrix {
+		heatmap.Matrix[weekday] = make([]int64, 24)
+	}
+	for i, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		count, err := strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			continue
+		}
+		t := time.Unix(hours[i]*3600, 0).In(loc)
+		heatmap.Matrix[t.Weekday()][t.Hour()] += count
+		heatmap.Total += count
+	}
+
+	cache.ReportHeatmap.Set(key, heatmap, time.Minute*10)
+	return &heatmap, nil
+}
+
+func counterValue(cmd *redis.StringCmd) int64 {
+	v, err := cmd.Int64()
+	if err != nil {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+	"exusiai.dev/backend-next/internal/pkg/fieldset"
+)
+
+// Sparse results only carry the fields requested by the client, e.g. only quantity and times, to reduce the size of
+// the large aggregate responses. The elements are wrapped rather than copied as the shim results are shared by cache.
+
+type SparseDropMatrixQueryResult[E any] struct {
+	Matrix []*fieldset.Sparse[E] `json:"matrix"`
+}
+
+type SparsePatternMatrixQueryResult[E any] struct {
+	PatternMatrix []*fieldset.Sparse[E] `json:"pattern_matrix"`
+}
+
+type SparseTrendQueryResult[E any] struct {
+	Trend map[string]*SparseStageTrend[E] `json:"trend"`
+}
+
+type SparseStageTrend[E any] struct {
+	StageName string                         `json:"stageName,omitempty"`
+	Results   map[string]*fieldset.Sparse[E] `json:"results"`
+	StartTime int64                          `json:"startTime"`
+	Buckets   []int64                        `json:"buckets"`
+}
+
+// SparseDropMatrix trims the elements of a shim drop matrix, either localized or not, to the listed fields.
+// The stage and item ids are always kept so that the elements can still be told apart.
+func SparseDropMatrix[E any](matrix []*E, fields string) (*SparseDropMatrixQueryResult[E], error) {
+	fs, err := fieldset.Parse[E](fields, "stageId", "itemId")
+	if err != nil {
+		return nil, err
+	}
+	return &SparseDropMatrixQueryResult[E]{Matrix: fs.WrapAll(matrix)}, nil
+}
+
+// SparsePatternMatrix trims the elements of a shim pattern matrix, either localized or not, to the listed fields.
+// The stage id and the pattern are always kept so that the elements can still be told apart.
+func SparsePatternMatrix[E any](patternMatrix []*E, fields string) (*SparsePatternMatrixQueryResult[E], error) {
+	fs, err := fieldset.Parse[E](fields, "stageId", "pattern")
+	if err != nil {
+		return nil, err
+	}
+	return &SparsePatternMatrixQueryResult[E]{PatternMatrix: fs.WrapAll(patternMatrix)}, nil
+}
+
+// SparseTrend trims the item trends of a shim trend to the listed fields
+func SparseTrend(result *modelv2.TrendQueryResult, fields string) (*SparseTrendQueryResult[modelv2.OneItemTrend], error) {
+	fs, err := fieldset.Parse[modelv2.OneItemTrend](fields)
+	if err != nil {
+		return nil, err
+	}
+	sparse := &SparseTrendQueryResult[modelv2.OneItemTrend]{Trend: make(map[string]*SparseStageTrend[modelv2.OneItemTrend], len(result.Trend))}
+	for stageId, stageTrend := range result.Trend {
+		sparse.Trend[stageId] = &SparseStageTrend[modelv2.OneItemTrend]{
+			Results:   sparseItemTrends(fs, stageTrend.Results),
+			StartTime: stageTrend.StartTime,
+			Buckets:   stageTrend.Buckets,
+		}
+	}
+	return sparse, nil
+}
+
+// SparseLocalizedTrend trims the item trends of a localized shim trend to the listed fields
+func SparseLocalizedTrend(result *modelv2.LocalizedTrendQueryResult, fields string) (*SparseTrendQueryResult[modelv2.LocalizedOneItemTrend], error) {
+	fs, err := fieldset.Parse[modelv2.LocalizedOneItemTrend](fields)
+	if err != nil {
+		return nil, err
+	}
+	sparse := &SparseTrendQueryResult[modelv2.LocalizedOneItemTrend]{Trend: make(map[string]*SparseStageTrend[modelv2.LocalizedOneItemTrend], len(result.Trend))}
+	for stageId, stageTrend := range result.Trend {
+		sparse.Trend[stageId] = &SparseStageTrend[modelv2.LocalizedOneItemTrend]{
+			StageName: stageTrend.StageName,
+			Results:   sparseItemTrends(fs, stageTrend.Results),
+			StartTime: stageTrend.StartTime,
+			Buckets:   stageTrend.Buckets,
+		}
+	}
+	return sparse, nil
+}
+
+func sparseItemTrends[E any](fs *fieldset.Fieldset[E], results map[string]*E) map[string]*fieldset.Sparse[E] {
+	sparse := make(map[string]*fieldset.Sparse[E], len(results))
+	for itemId, itemTrend := range results {
+		sparse[itemId] = fs.Wrap(itemTrend)
+	}
+	return sparse
+}
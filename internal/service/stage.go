@@ -12,17 +12,20 @@ import (
 	"exusiai.dev/backend-next/internal/model"
 	"exusiai.dev/backend-next/internal/model/cache"
 	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
 	"exusiai.dev/backend-next/internal/pkg/pgerr"
 	"exusiai.dev/backend-next/internal/repo"
 )
 
 type Stage struct {
-	StageRepo *repo.Stage
+	StageRepo        repo.StageRepo
+	TimeRangeService *TimeRange
 }
 
-func NewStage(stageRepo *repo.Stage) *Stage {
+func NewStage(stageRepo *repo.Stage, timeRangeService *TimeRange) *Stage {
 	return &Stage{
-		StageRepo: stageRepo,
+		StageRepo:        stageRepo,
+		TimeRangeService: timeRangeService,
 	}
 }
 
@@ -39,6 +42,41 @@ func (s *Stage) GetStages(ctx context.Context) ([]*model.Stage, error) {
 	return stages, err
 }
 
+// GetStageListingsByServer lists the stages existing in the server, optionally only those open right now,
+// along with the time range of their drop infos currently in effect
+func (s *Stage) GetStageListingsByServer(ctx context.Context, server string, openOnly bool) ([]*modelv3.StageListing, error) {
+	stages, err := s.GetStages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	latestTimeRanges, err := s.TimeRangeService.GetLatestTimeRangesByServer(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	listings := make([]*modelv3.StageListing, 0, len(stages))
+	for _, stage := range stages {
+		exist, openTime, closeTime := existenceInServer(stage.Existence, server)
+		if !exist {
+			continue
+		}
+		open := isWithin(now, openTime, closeTime)
+		if openOnly && !open {
+			continue
+		}
+		listing := &modelv3.StageListing{
+			Stage: stage,
+			Open:  open,
+		}
+		if timeRange, ok := latestTimeRanges[stage.StageID]; ok && timeRange.Includes(now) {
+			listing.TimeRange = timeRange
+		}
+		listings = append(listings, listing)
+	}
+	return listings, nil
+}
+
 func (s *Stage) GetStageById(ctx context.Context, stageId int) (*model.Stage, error) {
 	stagesMapById, err := s.GetStagesMapById(ctx)
 	if err != nil {
@@ -67,6 +105,61 @@ func (s *Stage) GetStageByArkId(ctx context.Context, arkStageId string) (*model.
 	return dbStage, nil
 }
 
+// UpdateMinClearTime sets the minimum time (in milliseconds) it takes to clear the stage, against which the
+// intervals between the reports of an account are checked for plausibility
+func (s *Stage) UpdateMinClearTime(ctx context.Context, arkStageId string, minClearTime null.Int) (*model.Stage, error) {
+	if minClearTime.Valid && minClearTime.Int64 < 0 {
+		return nil, pgerr.ErrInvalidReq.Msg("minClearTime must not be negative")
+	}
+
+	stage, err := s.StageRepo.GetStageByArkId(ctx, arkStageId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.StageRepo.UpdateMinClearTime(ctx, stage.StageID, minClearTime); err != nil {
+		return nil, err
+	}
+	stage.MinClearTime = minClearTime
+
+	purgeEventMetadataCaches(nil)
+	return stage, nil
+}
+
+// GetDeletedStages returns the stages deleted, which are left out of every other listing
+func (s *Stage) GetDeletedStages(ctx context.Context) ([]*model.Stage, error) {
+	return s.StageRepo.GetDeletedStages(ctx)
+}
+
+// DeleteStage soft deletes the stage: it disappears from the listings and no longer accepts reports, while its
+// drop reports and drop infos are kept for it to be restored
+func (s *Stage) DeleteStage(ctx context.Context, arkStageId string) error {
+	stage, err := s.StageRepo.GetStageByArkId(ctx, arkStageId)
+	if err != nil {
+		return err
+	}
+	if err := s.StageRepo.DeleteStage(ctx, stage.StageID); err != nil {
+		return err
+	}
+
+	purgeEventMetadataCaches(nil)
+	return nil
+}
+
+// RestoreStage undoes the deletion of the stage
+func (s *Stage) RestoreStage(ctx context.Context, arkStageId string) (*model.Stage, error) {
+	stage, err := s.StageRepo.GetDeletedStageByArkId(ctx, arkStageId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.StageRepo.RestoreStage(ctx, stage.StageID); err != nil {
+		return nil, err
+	}
+	stage.DeletedAt = nil
+
+	purgeEventMetadataCaches(nil)
+	return stage, nil
+}
+
 func (s *Stage) GetStagesByZoneId(ctx context.Context, zoneId int) ([]*model.Stage, error) {
 	return s.StageRepo.GetStagesByZoneId(ctx, zoneId)
 }
@@ -92,6 +185,7 @@ func (s *Stage) GetShimStages(ctx context.Context, server string) ([]*modelv2.St
 	}
 	cache.ShimStages.Set(server, stages, time.Minute*5)
 	cache.LastModifiedTime.Set("[shimStages#server:"+server+"]", time.Now(), 0)
+	cache.RecordContentHash("[shimStages#server:"+server+"]", stages)
 	return stages, nil
 }
 
@@ -168,6 +262,22 @@ func (s *Stage) GetStagesMapByArkId(ctx context.Context) (map[string]*model.Stag
 	return stagesMapByArkId, nil
 }
 
+// GetStagesByIds looks up the stages in batch, from the same cached map as GetStageById.
+// The stages not found are left out of the returned map.
+func (s *Stage) GetStagesByIds(ctx context.Context, stageIds []int) (map[int]*model.Stage, error) {
+	stagesMapById, err := s.GetStagesMapById(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stages := make(map[int]*model.Stage, len(stageIds))
+	for _, stageId := range stageIds {
+		if stage, ok := stagesMapById[stageId]; ok {
+			stages[stageId] = stage
+		}
+	}
+	return stages, nil
+}
+
 func (s *Stage) GetGachaBoxStages(ctx context.Context) ([]*model.Stage, error) {
 	stages, err := s.StageRepo.GetGachaBoxStages(ctx)
 	if err == pgerr.ErrNotFound {
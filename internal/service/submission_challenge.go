@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/bits"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+const (
+	// submissionChallengeRedisKeyPrefix prefixes the nonces of the challenges pending redemption, which are kept with
+	// the difficulty they have been issued with
+	submissionChallengeRedisKeyPrefix = "challenge:"
+	submissionChallengeNonceBytes     = 16
+	submissionChallengeAlgorithm      = "sha256"
+)
+
+// SubmissionChallenge issues and redeems the proof of work challenges the accounts that have never submitted a report
+// are required to solve before submitting one, should SubmissionChallengeEnabled be set.
+type SubmissionChallenge struct {
+	Config         *appconfig.Config
+	Redis          *redis.Client
+	DropReportRepo *repo.DropReport
+	Tunables       *Tunables
+}
+
+func NewSubmissionChallenge(conf *appconfig.Config, redisClient *redis.Client, dropReportRepo *repo.DropReport, tunables *Tunables) *SubmissionChallenge {
+	return &SubmissionChallenge{
+		Config:         conf,
+		Redis:          redisClient,
+		DropReportRepo: dropReportRepo,
+		Tunables:       tunables,
+	}
+}
+
+// IssueChallenge issues a challenge to be solved and redeemed within SubmissionChallengeLifetime
+func (s *SubmissionChallenge) IssueChallenge(ctx context.Context) (*modelv3.SubmissionChallenge, error) {
+	b := make([]byte, submissionChallengeNonceBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	challenge := &modelv3.SubmissionChallenge{
+		Nonce:      hex.EncodeToString(b),
+		Algorithm:  submissionChallengeAlgorithm,
+		Difficulty: s.Tunables.Int(TunableChallengeDifficulty),
+		ExpiresAt:  time.Now().Add(s.Config.SubmissionChallengeLifetime),
+	}
+	err := s.Redis.Set(ctx, submissionChallengeRedisKeyPrefix+challenge.Nonce, challenge.Difficulty, s.Config.SubmissionChallengeLifetime).Err()
+	if err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// VerifySubmission checks the submission of a report from the account, which has just been created if created is
+// set, against the solution presented along, if any. The solution is redeemed whether it is required or not; only the
+// accounts that have never submitted a report are required one.
+func (s *SubmissionChallenge) VerifySubmission(ctx context.Context, accountId int, created bool, solution string) error {
+	if !s.Config.SubmissionChallengeEnabled {
+		return nil
+	}
+	if solution != "" {
+		return s.redeem(ctx, solution)
+	}
+	if !created {
+		hasReports, err := s.DropReportRepo.HasDropReportsByAccountId(ctx, accountId)
+		if err != nil {
+			return err
+		}
+		if hasReports {
+			return nil
+		}
+	}
+	return pgerr.ErrChallengeRequired
+}
+
+func (s *SubmissionChallenge) redeem(ctx context.Context, solution string) error {
+	nonce, counter, ok := strings.Cut(solution, ":")
+	if !ok || nonce == "" || counter == "" {
+		return pgerr.ErrChallengeRequired.Msg("the challenge solution is malformed: expected <nonce>:<counter>")
+	}
+
+	difficulty, err := s.Redis.GetDel(ctx, submissionChallengeRedisKeyPrefix+nonce).Int()
+	if errors.Is(err, redis.Nil) {
+		return pgerr.ErrChallengeRequired.Msg("the challenge has expired or has already been redeemed; please request another one")
+	} else if err != nil {
+		return err
+	}
+
+	if leadingZeroBits(sha256.Sum256([]byte(solution))) < difficulty {
+		return pgerr.ErrChallengeRequired.Msg("the challenge solution is incorrect: the digest of %q has less than %d leading zero bits", solution, difficulty)
+	}
+	return nil
+}
+
+func leadingZeroBits(digest [sha256.Size]byte) int {
+	n := 0
+	for _, b := range digest {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+	return n
+}
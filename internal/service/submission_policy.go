@@ -0,0 +1,85 @@
+package service
+
+import (
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+)
+
+// The reasons the submissions are flagged for
+const (
+	SubmissionFlagNetwork = "network"
+	SubmissionFlagASN     = "asn"
+	SubmissionFlagCountry = "country"
+)
+
+// SubmissionPolicy flags the reports submitted from the networks configured as suspicious, e.g. the datacenters that
+// have historically hosted waves of spam. The flagged submissions are held to a stricter rate limit and their clients
+// are told to present a challenge.
+type SubmissionPolicy struct {
+	IPLookup IPLookup
+
+	networks  []*net.IPNet
+	asns      map[uint]struct{}
+	countries map[string]struct{}
+}
+
+func NewSubmissionPolicy(conf *appconfig.Config, geoIPService *GeoIP) (*SubmissionPolicy, error) {
+	s := &SubmissionPolicy{
+		IPLookup:  geoIPService,
+		asns:      make(map[uint]struct{}, len(conf.SubmissionFlaggedASNs)),
+		countries: make(map[string]struct{}, len(conf.SubmissionFlaggedCountries)),
+	}
+	for _, cidr := range conf.SubmissionFlaggedNetworks {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid flagged network %q", cidr)
+		}
+		s.networks = append(s.networks, network)
+	}
+	for _, asn := range conf.SubmissionFlaggedASNs {
+		s.asns[uint(asn)] = struct{}{}
+	}
+	for _, country := range conf.SubmissionFlaggedCountries {
+		s.countries[strings.ToUpper(strings.TrimSpace(country))] = struct{}{}
+	}
+	return s, nil
+}
+
+// Flag returns the reason the submissions from ip are flagged for, or an empty string if they are not. The addresses
+// failing to be looked up are not flagged.
+func (s *SubmissionPolicy) Flag(ip string) string {
+	netIP := net.ParseIP(ip)
+	if netIP == nil {
+		return ""
+	}
+	for _, network := range s.networks {
+		if network.Contains(netIP) {
+			return SubmissionFlagNetwork
+		}
+	}
+	if len(s.asns) == 0 && len(s.countries) == 0 {
+		return ""
+	}
+
+	network, err := s.IPLookup.Lookup(netIP)
+	if err != nil {
+		log.Debug().
+			Str("evt.name", "submission.policy.lookup").
+			Err(err).
+			Str("ip", ip).
+			Msg("failed to look up the network of the submission")
+		return ""
+	}
+	if _, ok := s.asns[network.ASN]; ok && network.ASN != 0 {
+		return SubmissionFlagASN
+	}
+	if _, ok := s.countries[network.CountryISOCode]; ok && network.CountryISOCode != "" {
+		return SubmissionFlagCountry
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+)
+
+type fakeIPLookup map[string]*IPNetwork
+
+func (l fakeIPLookup) Lookup(ip net.IP) (*IPNetwork, error) {
+	network, ok := l[ip.String()]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return network, nil
+}
+
+func TestSubmissionPolicyFlag(t *testing.T) {
+	conf := &appconfig.Config{}
+	conf.SubmissionFlaggedNetworks = []string{"203.0.113.0/24", "2001:db8::/32"}
+	conf.SubmissionFlaggedASNs = []int{64500}
+	conf.SubmissionFlaggedCountries = []string{" zz "}
+	s, err := NewSubmissionPolicy(conf, &GeoIP{})
+	if err != nil {
+		t.Fatalf("NewSubmissionPolicy returned error: %v", err)
+	}
+	s.IPLookup = fakeIPLookup{
+		"198.51.100.1": {CountryISOCode: "JP", ASN: 64500},
+		"198.51.100.2": {CountryISOCode: "ZZ", ASN: 64501},
+		"198.51.100.3": {CountryISOCode: "JP", ASN: 64501},
+	}
+
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{ip: "203.0.113.7", want: SubmissionFlagNetwork},
+		{ip: "2001:db8::1", want: SubmissionFlagNetwork},
+		{ip: "198.51.100.1", want: SubmissionFlagASN},
+		{ip: "198.51.100.2", want: SubmissionFlagCountry},
+		{ip: "198.51.100.3", want: ""},
+		// the addresses failing to be looked up are not flagged
+		{ip: "198.51.100.4", want: ""},
+		{ip: "not an ip", want: ""},
+	}
+	for _, tt := range tests {
+		if got := s.Flag(tt.ip); got != tt.want {
+			t.Errorf("Flag(%q) = %q, want %q", tt.ip, got, tt.want)
+		}
+	}
+
+	conf.SubmissionFlaggedNetworks = []string{"203.0.113.0"}
+	if _, err := NewSubmissionPolicy(conf, &GeoIP{}); err == nil {
+		t.Error("NewSubmissionPolicy accepted an invalid network")
+	}
+}
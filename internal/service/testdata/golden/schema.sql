@@ -0,0 +1,88 @@
+-- the tables read by the aggregations, with the columns of the production database
+CREATE TABLE time_ranges (
+    range_id   SERIAL PRIMARY KEY,
+    name       TEXT,
+    start_time TIMESTAMPTZ NOT NULL,
+    end_time   TIMESTAMPTZ NOT NULL,
+    comment    TEXT,
+    server     TEXT        NOT NULL
+);
+
+CREATE TABLE drop_infos (
+    drop_id     SERIAL PRIMARY KEY,
+    server      TEXT    NOT NULL,
+    stage_id    INTEGER NOT NULL,
+    item_id     INTEGER,
+    drop_type   TEXT    NOT NULL,
+    range_id    INTEGER NOT NULL,
+    accumulable BOOLEAN NOT NULL,
+    bounds      JSONB,
+    extras      JSONB
+);
+
+CREATE TABLE drop_pattern_elements (
+    element_id      SERIAL PRIMARY KEY,
+    drop_pattern_id INTEGER NOT NULL,
+    item_id         INTEGER NOT NULL,
+    quantity        INTEGER NOT NULL
+);
+
+CREATE TABLE drop_reports (
+    report_id   SERIAL PRIMARY KEY,
+    stage_id    INTEGER     NOT NULL,
+    pattern_id  INTEGER     NOT NULL,
+    times       INTEGER     NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL,
+    reliability INTEGER     NOT NULL,
+    server      TEXT        NOT NULL,
+    account_id  INTEGER     NOT NULL,
+    source_name TEXT,
+    version     TEXT
+);
+
+CREATE TABLE drop_report_extras (
+    report_id INTEGER PRIMARY KEY,
+    ip        TEXT,
+    metadata  JSONB,
+    md5       TEXT
+);
+
+CREATE TABLE exclusion_rules (
+    rule_id         SERIAL PRIMARY KEY,
+    name            TEXT        NOT NULL,
+    comment         TEXT,
+    enabled         BOOLEAN     NOT NULL DEFAULT TRUE,
+    server          TEXT,
+    account_id      INTEGER,
+    ip_range        CIDR,
+    source_name     TEXT,
+    version_pattern TEXT,
+    stage_id        INTEGER,
+    start_time      TIMESTAMPTZ,
+    end_time        TIMESTAMPTZ,
+    expires_at      TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE drop_report_daily_rollups (
+    server           TEXT        NOT NULL,
+    day_start        TIMESTAMPTZ NOT NULL,
+    stage_id         INTEGER     NOT NULL,
+    item_id          INTEGER     NOT NULL,
+    source_category  TEXT        NOT NULL,
+    quantity         BIGINT      NOT NULL DEFAULT 0,
+    quantity_buckets JSONB       NOT NULL DEFAULT '{}',
+    times            BIGINT      NOT NULL DEFAULT 0,
+    reporters        INTEGER     NOT NULL DEFAULT 0,
+    PRIMARY KEY (server, day_start, stage_id, item_id, source_category)
+);
+
+CREATE TABLE drop_report_daily_reporters (
+    server          TEXT        NOT NULL,
+    day_start       TIMESTAMPTZ NOT NULL,
+    stage_id        INTEGER     NOT NULL,
+    source_category TEXT        NOT NULL,
+    account_id      INTEGER     NOT NULL,
+    PRIMARY KEY (server, day_start, stage_id, source_category, account_id)
+);
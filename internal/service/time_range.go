@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"exusiai.dev/gommon/constant"
 	"github.com/ahmetb/go-linq/v3"
 
 	"exusiai.dev/backend-next/internal/model"
@@ -13,8 +14,8 @@ import (
 )
 
 type TimeRange struct {
-	TimeRangeRepo *repo.TimeRange
-	DropInfoRepo  *repo.DropInfo
+	TimeRangeRepo repo.TimeRangeRepo
+	DropInfoRepo  repo.DropInfoRepo
 }
 
 func NewTimeRange(timeRangeRepo *repo.TimeRange, dropInfoRepo *repo.DropInfo) *TimeRange {
@@ -290,3 +291,38 @@ func (s *TimeRange) GetLatestTimeRangesByServer(ctx context.Context, server stri
 func (s *TimeRange) GetTimeRangeByServerAndName(ctx context.Context, server string, name string) (*model.TimeRange, error) {
 	return s.TimeRangeRepo.GetTimeRangeByServerAndName(ctx, server, name)
 }
+
+// ActivateScheduledTimeRanges purges the caches of every server that has a time range starting or ending
+// within (since, until], so that time ranges created in advance become current (or stop being current)
+// right at their boundaries. It returns the time ranges that crossed a boundary.
+func (s *TimeRange) ActivateScheduledTimeRanges(ctx context.Context, since time.Time, until time.Time) ([]*model.TimeRange, error) {
+	crossed := func(t *time.Time) bool {
+		return t != nil && t.After(since) && !t.After(until)
+	}
+
+	activated := make([]*model.TimeRange, 0)
+	servers := make([]string, 0)
+	for _, server := range constant.Servers {
+		timeRanges, err := s.TimeRangeRepo.GetTimeRangesByServer(ctx, server)
+		if err != nil {
+			return nil, err
+		}
+
+		serverCrossed := false
+		for _, timeRange := range timeRanges {
+			if crossed(timeRange.StartTime) || crossed(timeRange.EndTime) {
+				activated = append(activated, timeRange)
+				serverCrossed = true
+			}
+		}
+		if serverCrossed {
+			servers = append(servers, server)
+		}
+	}
+
+	if len(servers) > 0 {
+		purgeEventMetadataCaches(servers)
+	}
+
+	return activated, nil
+}
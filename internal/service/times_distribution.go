@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"exusiai.dev/backend-next/internal/model/cache"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+	"exusiai.dev/backend-next/internal/repo"
+	"exusiai.dev/backend-next/internal/util"
+)
+
+// timesDistributionCacheTTL is how long the distributions are cached for, as they change slowly
+const timesDistributionCacheTTL = time.Hour
+
+// TimesDistribution tells how the reports of the stages are distributed among the accounts having reported them
+type TimesDistribution struct {
+	DropReportRepo *repo.DropReport
+	StageService   *Stage
+}
+
+func NewTimesDistribution(dropReportRepo *repo.DropReport, stageService *Stage) *TimesDistribution {
+	return &TimesDistribution{
+		DropReportRepo: dropReportRepo,
+		StageService:   stageService,
+	}
+}
+
+// GetTimesDistribution counts the distinct accounts having reported the stage in the server and buckets them by
+// their number of reliable reports
+// Cache: timesDistribution#server|arkStageId:{server}|{arkStageId}, 1 hr
+func (s *TimesDistribution) GetTimesDistribution(ctx context.Context, server string, arkStageId string) (*modelv3.TimesDistribution, error) {
+	key := server + "|" + arkStageId
+	var distribution modelv3.TimesDistribution
+	_, err := cache.TimesDistribution.MutexGetSet(key, &distribution, func() (*modelv3.TimesDistribution, error) {
+		return s.calcTimesDistribution(ctx, server, arkStageId)
+	}, timesDistributionCacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &distribution, nil
+}
+
+func (s *TimesDistribution) calcTimesDistribution(ctx context.Context, server string, arkStageId string) (*modelv3.TimesDistribution, error) {
+	stage, err := s.StageService.GetStageByArkId(ctx, arkStageId)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.DropReportRepo.CalcReportCountsPerAccount(ctx, server, stage.StageID)
+	if err != nil {
+		return nil, err
+	}
+
+	distribution := &modelv3.TimesDistribution{
+		StageID:     arkStageId,
+		Server:      server,
+		Histogram:   make([]*modelv3.TimesDistributionBucket, 0),
+		GeneratedAt: time.Now(),
+	}
+	var bucket *modelv3.TimesDistributionBucket
+	topReports := 0
+	// counts are ordered by the number of reports, so the buckets are filled in order
+	for _, count := range counts {
+		if count.Reports <= 0 {
+			continue
+		}
+		if bucket == nil || count.Reports > bucket.Max {
+			lower := 1
+			for lower*2 <= count.Reports {
+				lower *= 2
+			}
+			bucket = &modelv3.TimesDistributionBucket{Min: lower, Max: lower*2 - 1}
+			distribution.Histogram = append(distribution.Histogram, bucket)
+		}
+		bucket.Accounts += count.Accounts
+		bucket.Reports += count.Reports * count.Accounts
+		distribution.Accounts += count.Accounts
+		distribution.Reports += count.Reports * count.Accounts
+		topReports = count.Reports
+	}
+	distribution.TopAccountShare = util.DropRate(topReports, distribution.Reports)
+	return distribution, nil
+}
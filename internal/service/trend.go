@@ -2,11 +2,12 @@ package service
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"exusiai.dev/gommon/constant"
-	"github.com/ahmetb/go-linq/v3"
 	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
 	"gopkg.in/guregu/null.v3"
 
 	"exusiai.dev/backend-next/internal/model"
@@ -21,6 +22,9 @@ type Trend struct {
 	StageService             *Stage
 	ItemService              *Item
 	DropMatrixElementService *DropMatrixElement
+	DailyRollups             *DropReportDailyRollups
+	CacheTTLOverrides        *CacheTTLOverrides
+	Freshness                *Freshness
 }
 
 func NewTrend(
@@ -29,6 +33,9 @@ func NewTrend(
 	stageService *Stage,
 	itemService *Item,
 	dropMatrixElementService *DropMatrixElement,
+	dailyRollups *DropReportDailyRollups,
+	cacheTTLOverrides *CacheTTLOverrides,
+	freshness *Freshness,
 ) *Trend {
 	return &Trend{
 		DropReportService:        dropReportService,
@@ -36,12 +43,15 @@ func NewTrend(
 		StageService:             stageService,
 		ItemService:              itemService,
 		DropMatrixElementService: dropMatrixElementService,
+		DailyRollups:             dailyRollups,
+		CacheTTLOverrides:        cacheTTLOverrides,
+		Freshness:                freshness,
 	}
 }
 
 // =========== Global ===========
 
-// Cache: shimTrend#server:{server}, 24hrs, records last modified time
+// Cache: shimTrend#server:{server}, 24hrs (overridable), records last modified time
 // Called by frontend, only for global
 func (s *Trend) GetShimTrend(ctx context.Context, server string) (*modelv2.TrendQueryResult, error) {
 	valueFunc := func() (*modelv2.TrendQueryResult, error) {
@@ -49,7 +59,7 @@ func (s *Trend) GetShimTrend(ctx context.Context, server string) (*modelv2.Trend
 		if err != nil {
 			return nil, err
 		}
-		slowShimResult, err := s.applyShimForTrendQuery(ctx, queryResult, nil)
+		slowShimResult, err := s.applyShimForTrendQuery(ctx, queryResult, nil, 24*time.Hour)
 		if err != nil {
 			return nil, err
 		}
@@ -58,15 +68,23 @@ func (s *Trend) GetShimTrend(ctx context.Context, server string) (*modelv2.Trend
 
 	var shimResult modelv2.TrendQueryResult
 	key := server
-	calculated, err := cache.ShimTrend.MutexGetSet(key, &shimResult, valueFunc, 24*time.Hour)
+	calculated, err := cache.ShimTrend.MutexGetSet(key, &shimResult, valueFunc, s.GlobalCacheTTL(server))
 	if err != nil {
 		return nil, err
 	} else if calculated {
 		cache.LastModifiedTime.Set("[shimTrend#server:"+key+"]", time.Now(), 0)
+		if err := s.Freshness.MarkRefreshed(ctx, server, model.RefreshJobRealmTrend, time.Now()); err != nil {
+			log.Warn().Err(err).Str("server", server).Msg("failed to record the freshness of the trends")
+		}
 	}
 	return &shimResult, nil
 }
 
+// GlobalCacheTTL returns how long the trends of the server are cached for
+func (s *Trend) GlobalCacheTTL(server string) time.Duration {
+	return s.CacheTTLOverrides.TTL(server, nil, 24*time.Hour)
+}
+
 func (s *Trend) calcTrendFromDropMatrixElements(ctx context.Context, server string) (*model.TrendQueryResult, error) {
 	trendQueryResult := &model.TrendQueryResult{
 		Trends: make([]*model.StageTrend, 0),
@@ -84,19 +102,27 @@ func (s *Trend) calcTrendFromDropMatrixElements(ctx context.Context, server stri
 			StageID: stageId,
 			Results: make([]*model.ItemTrend, 0),
 		}
+		// the trends of the items of a stage start from the same day, so that they share the buckets of the stage
+		minDayNum := endDayNum
+		for _, elementsByDayNum := range elementsMapByItemId {
+			for dayNum := range elementsByDayNum {
+				if dayNum < minDayNum {
+					minDayNum = dayNum
+				}
+			}
+		}
 		for itemId, elementsByDayNum := range elementsMapByItemId {
 			times := make([]int, constant.DefaultIntervalNum)
+			reporters := make([]int, constant.DefaultIntervalNum)
 			quantity := make([]int, constant.DefaultIntervalNum)
-			minDayNum := endDayNum
 			for dayNum, element := range elementsByDayNum {
-				if dayNum < minDayNum {
-					minDayNum = dayNum
-				}
 				times[dayNum-startDayNum] = element.Times
+				reporters[dayNum-startDayNum] = element.Reporters
 				quantity[dayNum-startDayNum] = element.Quantity
 			}
 			// remove heading zeros, totally (minDayNum - startDayNum) zeros
 			times = times[minDayNum-startDayNum:]
+			reporters = reporters[minDayNum-startDayNum:]
 			quantity = quantity[minDayNum-startDayNum:]
 
 			startTime := time.UnixMilli(util.GetDayStartTimestampFromDayNum(minDayNum, server))
@@ -104,6 +130,7 @@ func (s *Trend) calcTrendFromDropMatrixElements(ctx context.Context, server stri
 				ItemID:    itemId,
 				StartTime: &startTime,
 				Times:     times,
+				Reporters: reporters,
 				Quantity:  quantity,
 			}
 			stageTrend.Results = append(stageTrend.Results, itemTrend)
@@ -118,11 +145,13 @@ func (s *Trend) calcTrendFromDropMatrixElements(ctx context.Context, server stri
 func (s *Trend) GetShimCustomizedTrendResults(
 	ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, stageIds []int, itemIds []int, accountId null.Int, sourceCategory string,
 ) (*modelv2.TrendQueryResult, error) {
-	trendQueryResult, err := s.queryTrend(ctx, server, startTime, intervalLength, intervalNum, stageIds, itemIds, accountId, sourceCategory)
+	// daily intervals follow the days of the server, rather than starting from whatever time has been asked for
+	alignedStartTime := util.AlignToServerDay(*startTime, intervalLength, server)
+	trendQueryResult, err := s.queryTrend(ctx, server, &alignedStartTime, intervalLength, intervalNum, stageIds, itemIds, accountId, sourceCategory)
 	if err != nil {
 		return nil, err
 	}
-	return s.applyShimForTrendQuery(ctx, trendQueryResult, startTime)
+	return s.applyShimForTrendQuery(ctx, trendQueryResult, &alignedStartTime, intervalLength)
 }
 
 func (s *Trend) queryTrend(
@@ -156,11 +185,7 @@ func (s *Trend) calcTrend(
 		return nil, err
 	}
 
-	quantityResults, err := s.DropReportService.CalcTotalQuantityForTrend(ctx, server, startTime, intervalLength, intervalNum, util.GetStageIdItemIdMapFromDropInfos(dropInfos), accountId, sourceCategory)
-	if err != nil {
-		return nil, err
-	}
-	timesResults, err := s.DropReportService.CalcTotalTimesForTrend(ctx, server, startTime, intervalLength, intervalNum, util.GetStageIdsFromDropInfos(dropInfos), accountId, sourceCategory)
+	quantityResults, timesResults, err := s.calcTotalQuantityAndTimesForTrend(ctx, server, startTime, intervalLength, intervalNum, dropInfos, accountId, sourceCategory)
 	if err != nil {
 		return nil, err
 	}
@@ -171,11 +196,16 @@ func (s *Trend) calcTrend(
 
 	finalResults := make([]*model.TrendElement, 0, len(combinedResults))
 	for _, result := range combinedResults {
+		// the only reporter of the personal results is the account itself
+		if accountId.Valid {
+			result.Reporters = 0
+		}
 		finalResults = append(finalResults, &model.TrendElement{
 			StageID:        result.StageID,
 			ItemID:         result.ItemID,
 			Quantity:       result.Quantity,
 			Times:          result.Times,
+			Reporters:      result.Reporters,
 			Server:         server,
 			StartTime:      result.StartTime,
 			EndTime:        result.EndTime,
@@ -186,85 +216,86 @@ func (s *Trend) calcTrend(
 	return finalResults, nil
 }
 
+// calcTotalQuantityAndTimesForTrend sums the quantities and the times of every interval up. The global trends of which
+// the intervals are made of whole days are summed up from the daily rollups when they are serving, as the intervals
+// start from the game day of startTime either way.
+func (s *Trend) calcTotalQuantityAndTimesForTrend(
+	ctx context.Context, server string, startTime *time.Time, intervalLength time.Duration, intervalNum int, dropInfos []*model.DropInfo, accountId null.Int, sourceCategory string,
+) ([]*model.TotalQuantityResultForTrend, []*model.TotalTimesResultForTrend, error) {
+	stageIdItemIdMap := util.GetStageIdItemIdMapFromDropInfos(dropInfos)
+	stageIds := util.GetStageIdsFromDropInfos(dropInfos)
+
+	if s.DailyRollups.Serving() && !accountId.Valid && intervalLength%(time.Hour*24) == 0 {
+		quantityResults, err := s.DropReportService.CalcTotalQuantityForTrendFromRollups(ctx, server, startTime, intervalLength, intervalNum, stageIdItemIdMap, sourceCategory)
+		if err != nil {
+			return nil, nil, err
+		}
+		timesResults, err := s.DropReportService.CalcTotalTimesForTrendFromRollups(ctx, server, startTime, intervalLength, intervalNum, stageIds, sourceCategory)
+		if err != nil {
+			return nil, nil, err
+		}
+		return quantityResults, timesResults, nil
+	}
+
+	quantityResults, err := s.DropReportService.CalcTotalQuantityForTrend(ctx, server, startTime, intervalLength, intervalNum, stageIdItemIdMap, accountId, sourceCategory)
+	if err != nil {
+		return nil, nil, err
+	}
+	timesResults, err := s.DropReportService.CalcTotalTimesForTrend(ctx, server, startTime, intervalLength, intervalNum, stageIds, accountId, sourceCategory)
+	if err != nil {
+		return nil, nil, err
+	}
+	return quantityResults, timesResults, nil
+}
+
 func (s *Trend) combineQuantityAndTimesResults(
 	ctx context.Context, server string, itemIdFilter []int, quantityResults []*model.TotalQuantityResultForTrend, timesResults []*model.TotalTimesResultForTrend,
 ) ([]*model.CombinedResultForTrend, error) {
-	var firstGroupResultsForQuantity []linq.Group
 	combinedResults := make([]*model.CombinedResultForTrend, 0)
-	linq.From(quantityResults).
-		GroupByT(
-			func(result *model.TotalQuantityResultForTrend) int { return result.GroupID },
-			func(result *model.TotalQuantityResultForTrend) *model.TotalQuantityResultForTrend { return result }).
-		ToSlice(&firstGroupResultsForQuantity)
 	groupResultsMap := make(map[int]map[int]map[int]int)
-	for _, firstGroupElements := range firstGroupResultsForQuantity {
-		groupId := firstGroupElements.Key.(int)
-		var secondGroupResultsForQuantity []linq.Group
-		linq.From(firstGroupElements.Group).
-			GroupByT(
-				func(result *model.TotalQuantityResultForTrend) int { return result.StageID },
-				func(result *model.TotalQuantityResultForTrend) *model.TotalQuantityResultForTrend { return result }).
-			ToSlice(&secondGroupResultsForQuantity)
+	for _, firstGroupElements := range util.GroupBy(quantityResults, func(result *model.TotalQuantityResultForTrend) int { return result.GroupID }) {
 		quantityResultsMap := make(map[int]map[int]int)
-		for _, secondGroupElements := range secondGroupResultsForQuantity {
-			stageId := secondGroupElements.Key.(int)
-			resultsMap := make(map[int]int)
-			linq.From(secondGroupElements.Group).
-				ToMapByT(&resultsMap,
-					func(el any) int { return el.(*model.TotalQuantityResultForTrend).ItemID },
-					func(el any) int { return el.(*model.TotalQuantityResultForTrend).TotalQuantity })
-			quantityResultsMap[stageId] = resultsMap
+		for _, secondGroupElements := range util.GroupBy(firstGroupElements.Values, func(result *model.TotalQuantityResultForTrend) int { return result.StageID }) {
+			quantityResultsMap[secondGroupElements.Key] = util.ToMap(secondGroupElements.Values,
+				func(el *model.TotalQuantityResultForTrend) int { return el.ItemID },
+				func(el *model.TotalQuantityResultForTrend) int { return el.TotalQuantity })
 		}
-		groupResultsMap[groupId] = quantityResultsMap
+		groupResultsMap[firstGroupElements.Key] = quantityResultsMap
 	}
 
-	var firstGroupResultsForTimes []linq.Group
-	linq.From(timesResults).
-		GroupByT(
-			func(result *model.TotalTimesResultForTrend) int { return result.GroupID },
-			func(result *model.TotalTimesResultForTrend) *model.TotalTimesResultForTrend { return result }).
-		ToSlice(&firstGroupResultsForTimes)
-	for _, firstGroupElements := range firstGroupResultsForTimes {
-		groupId := firstGroupElements.Key.(int)
+	for _, firstGroupElements := range util.GroupBy(timesResults, func(result *model.TotalTimesResultForTrend) int { return result.GroupID }) {
+		groupId := firstGroupElements.Key
 		quantityResultsMapForOneGroup := groupResultsMap[groupId]
-		var secondGroupResultsForTimes []linq.Group
-		linq.From(firstGroupElements.Group).
-			GroupByT(
-				func(result *model.TotalTimesResultForTrend) int { return result.StageID },
-				func(result *model.TotalTimesResultForTrend) *model.TotalTimesResultForTrend { return result }).
-			ToSlice(&secondGroupResultsForTimes)
-		for _, secondGroupElements := range secondGroupResultsForTimes {
-			stageId := secondGroupElements.Key.(int)
+		for _, secondGroupElements := range util.GroupBy(firstGroupElements.Values, func(result *model.TotalTimesResultForTrend) int { return result.StageID }) {
+			stageId := secondGroupElements.Key
 
 			if quantityResultsMapForOneGroup == nil {
 				// it means no items were dropped in this group, we need to find all dropable items and set their quantity to 0
 				dropInfosForSpecialTimeRange, err := s.DropInfoService.GetDropInfosWithFilters(ctx, server, []*model.TimeRange{
 					{
-						StartTime: secondGroupElements.Group[0].(*model.TotalTimesResultForTrend).IntervalStart,
-						EndTime:   secondGroupElements.Group[0].(*model.TotalTimesResultForTrend).IntervalEnd,
+						StartTime: secondGroupElements.Values[0].IntervalStart,
+						EndTime:   secondGroupElements.Values[0].IntervalEnd,
 					},
 				}, []int{stageId}, itemIdFilter)
 				if err != nil {
 					return nil, err
 				}
-				var dropItemIds []int
-				linq.From(dropInfosForSpecialTimeRange).
-					WhereT(func(el *model.DropInfo) bool { return el.ItemID.Valid }).
-					SelectT(func(el *model.DropInfo) int { return int(el.ItemID.Int64) }).
-					ToSlice(&dropItemIds)
 				quantityResultsMapForOneGroup = make(map[int]map[int]int)
 				subMap := make(map[int]int)
-				for _, dropItemId := range dropItemIds {
-					subMap[dropItemId] = 0
+				for _, dropInfo := range dropInfosForSpecialTimeRange {
+					if dropInfo.ItemID.Valid {
+						subMap[int(dropInfo.ItemID.Int64)] = 0
+					}
 				}
 				quantityResultsMapForOneGroup[stageId] = subMap
 			}
 
 			resultsMap := quantityResultsMapForOneGroup[stageId]
-			for _, el := range secondGroupElements.Group {
-				times := el.(*model.TotalTimesResultForTrend).TotalTimes
-				startTime := el.(*model.TotalTimesResultForTrend).IntervalStart
-				endTime := el.(*model.TotalTimesResultForTrend).IntervalEnd
+			for _, el := range secondGroupElements.Values {
+				times := el.TotalTimes
+				reporters := el.Reporters
+				startTime := el.IntervalStart
+				endTime := el.IntervalEnd
 				for itemId, quantity := range resultsMap {
 					combinedResults = append(combinedResults, &model.CombinedResultForTrend{
 						GroupID:   groupId,
@@ -272,6 +303,7 @@ func (s *Trend) combineQuantityAndTimesResults(
 						ItemID:    itemId,
 						Quantity:  quantity,
 						Times:     times,
+						Reporters: reporters,
 						StartTime: startTime,
 						EndTime:   endTime,
 					})
@@ -283,48 +315,35 @@ func (s *Trend) combineQuantityAndTimesResults(
 }
 
 func (s *Trend) convertTrendElementsToTrendQueryResult(trendElements []*model.TrendElement) (*model.TrendQueryResult, error) {
-	var groupedResults []linq.Group
-	linq.From(trendElements).
-		GroupByT(
-			func(el *model.TrendElement) int { return el.StageID },
-			func(el *model.TrendElement) *model.TrendElement { return el },
-		).
-		ToSlice(&groupedResults)
 	trendQueryResult := &model.TrendQueryResult{
 		Trends: make([]*model.StageTrend, 0),
 	}
-	for _, el := range groupedResults {
-		stageId := el.Key.(int)
+	for _, el := range util.GroupBy(trendElements, func(el *model.TrendElement) int { return el.StageID }) {
+		stageId := el.Key
 		stageTrend := &model.StageTrend{
 			StageID: stageId,
 			Results: make([]*model.ItemTrend, 0),
 		}
-		var groupedResults2 []linq.Group
-		linq.From(el.Group).
-			GroupByT(
-				func(el *model.TrendElement) int { return el.ItemID },
-				func(el *model.TrendElement) *model.TrendElement { return el },
-			).
-			ToSlice(&groupedResults2)
 		var startTime *time.Time
-		for _, el2 := range groupedResults2 {
-			itemId := el2.Key.(int)
-			var sortedElements []*model.TrendElement
-			linq.From(el2.Group).
-				SortT(func(el1, el2 *model.TrendElement) bool { return el1.GroupID < el2.GroupID }).
-				ToSlice(&sortedElements)
+		for _, el2 := range util.GroupBy(el.Values, func(el *model.TrendElement) int { return el.ItemID }) {
+			itemId := el2.Key
+			sortedElements := el2.Values
+			sort.Slice(sortedElements, func(i, j int) bool { return sortedElements[i].GroupID < sortedElements[j].GroupID })
 			startTime = sortedElements[0].StartTime
-			minGroupId := linq.From(sortedElements).SelectT(func(el *model.TrendElement) int { return el.GroupID }).Min().(int)
-			maxGroupId := linq.From(sortedElements).SelectT(func(el *model.TrendElement) int { return el.GroupID }).Max().(int)
+			minGroupId := sortedElements[0].GroupID
+			maxGroupId := sortedElements[len(sortedElements)-1].GroupID
 			timesArray := make([]int, maxGroupId+1)
+			reportersArray := make([]int, maxGroupId+1)
 			quantityArray := make([]int, maxGroupId+1)
 			for _, el3 := range sortedElements {
 				timesArray[el3.GroupID] = el3.Times
+				reportersArray[el3.GroupID] = el3.Reporters
 				quantityArray[el3.GroupID] = el3.Quantity
 			}
 			stageTrend.Results = append(stageTrend.Results, &model.ItemTrend{
 				ItemID:     itemId,
 				Times:      timesArray,
+				Reporters:  reportersArray,
 				Quantity:   quantityArray,
 				StartTime:  startTime,
 				MinGroupID: minGroupId,
@@ -338,13 +357,22 @@ func (s *Trend) convertTrendElementsToTrendQueryResult(trendElements []*model.Tr
 
 // =========== Helpers ===========
 
-func (s *Trend) applyShimForTrendQuery(ctx context.Context, queryResult *model.TrendQueryResult, startTime *time.Time) (*modelv2.TrendQueryResult, error) {
-	itemsMapById, err := s.ItemService.GetItemsMapById(ctx)
+func (s *Trend) applyShimForTrendQuery(
+	ctx context.Context, queryResult *model.TrendQueryResult, startTime *time.Time, intervalLength time.Duration,
+) (*modelv2.TrendQueryResult, error) {
+	stageIds := make([]int, 0, len(queryResult.Trends))
+	itemIds := make([]int, 0)
+	for _, stageTrend := range queryResult.Trends {
+		stageIds = append(stageIds, stageTrend.StageID)
+		for _, itemTrend := range stageTrend.Results {
+			itemIds = append(itemIds, itemTrend.ItemID)
+		}
+	}
+	stagesMapById, err := s.StageService.GetStagesByIds(ctx, stageIds)
 	if err != nil {
 		return nil, err
 	}
-
-	stagesMapById, err := s.StageService.GetStagesMapById(ctx)
+	itemsMapById, err := s.ItemService.GetItemsByIds(ctx, itemIds)
 	if err != nil {
 		return nil, err
 	}
@@ -353,7 +381,10 @@ func (s *Trend) applyShimForTrendQuery(ctx context.Context, queryResult *model.T
 		Trend: make(map[string]*modelv2.StageTrend),
 	}
 	for _, stageTrend := range queryResult.Trends {
-		stage := stagesMapById[stageTrend.StageID]
+		stage, ok := stagesMapById[stageTrend.StageID]
+		if !ok {
+			continue
+		}
 		shimStageTrend := modelv2.StageTrend{
 			Results: make(map[string]*modelv2.OneItemTrend),
 		}
@@ -362,19 +393,38 @@ func (s *Trend) applyShimForTrendQuery(ctx context.Context, queryResult *model.T
 		}
 
 		var minStartTime *time.Time
+		bucketNum := 0
 		for _, itemTrend := range stageTrend.Results {
-			item := itemsMapById[itemTrend.ItemID]
+			item, ok := itemsMapById[itemTrend.ItemID]
+			if !ok {
+				continue
+			}
+			rates := make([]float64, len(itemTrend.Quantity))
+			for i, quantity := range itemTrend.Quantity {
+				if i < len(itemTrend.Times) {
+					rates[i] = util.DropRate(quantity, itemTrend.Times[i])
+				}
+			}
 			shimStageTrend.Results[item.ArkItemID] = &modelv2.OneItemTrend{
 				Quantity: itemTrend.Quantity,
 				Times:    itemTrend.Times,
+				Rates:    rates,
+			}
+			// the reporters are left out of personal results, and of the days calculated before they were counted
+			if lo.SomeBy(itemTrend.Reporters, func(reporters int) bool { return reporters > 0 }) {
+				shimStageTrend.Results[item.ArkItemID].Reporters = itemTrend.Reporters
 			}
 			if minStartTime == nil || itemTrend.StartTime.Before(*minStartTime) {
 				minStartTime = itemTrend.StartTime
 			}
+			if len(itemTrend.Times) > bucketNum {
+				bucketNum = len(itemTrend.Times)
+			}
 		}
-		if startTime == nil {
+		if startTime == nil && minStartTime != nil {
 			shimStageTrend.StartTime = minStartTime.UnixMilli()
 		}
+		shimStageTrend.Buckets = util.GetBucketBoundaries(time.UnixMilli(shimStageTrend.StartTime), intervalLength, bucketNum)
 		if len(shimStageTrend.Results) > 0 {
 			results.Trend[stage.ArkStageID] = &shimStageTrend
 		}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo/mocks"
+)
+
+func TestTrendCombineQuantityAndTimesResults(t *testing.T) {
+	dropInfoRepo := mocks.NewDropInfoRepo(t)
+	s := &Trend{DropInfoService: &DropInfo{DropInfoRepo: dropInfoRepo}}
+
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	nextDay := day.Add(24 * time.Hour)
+	dayAfter := nextDay.Add(24 * time.Hour)
+
+	quantityResults := []*model.TotalQuantityResultForTrend{
+		{GroupID: 0, IntervalStart: &day, IntervalEnd: &nextDay, StageID: 1, ItemID: 10, TotalQuantity: 5},
+		{GroupID: 0, IntervalStart: &day, IntervalEnd: &nextDay, StageID: 1, ItemID: 11, TotalQuantity: 2},
+	}
+	timesResults := []*model.TotalTimesResultForTrend{
+		{GroupID: 0, IntervalStart: &day, IntervalEnd: &nextDay, StageID: 1, TotalTimes: 20, Reporters: 3},
+		// nothing dropped in the second group, so every item droppable within it is expected at a quantity of 0
+		{GroupID: 1, IntervalStart: &nextDay, IntervalEnd: &dayAfter, StageID: 1, TotalTimes: 7, Reporters: 2},
+	}
+	dropInfoRepo.On("GetDropInfosWithFilters", mock.Anything, "CN", mock.Anything, []int{1}, []int(nil)).
+		Return([]*model.DropInfo{
+			{StageID: 1, ItemID: null.IntFrom(10)},
+			{StageID: 1, ItemID: null.IntFrom(12)},
+			{StageID: 1},
+		}, nil).
+		Once()
+
+	results, err := s.combineQuantityAndTimesResults(context.Background(), "CN", nil, quantityResults, timesResults)
+	if err != nil {
+		t.Fatalf("combineQuantityAndTimesResults returned error: %v", err)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].GroupID != results[j].GroupID {
+			return results[i].GroupID < results[j].GroupID
+		}
+		return results[i].ItemID < results[j].ItemID
+	})
+	want := []model.CombinedResultForTrend{
+		{GroupID: 0, StartTime: &day, EndTime: &nextDay, StageID: 1, ItemID: 10, Times: 20, Reporters: 3, Quantity: 5},
+		{GroupID: 0, StartTime: &day, EndTime: &nextDay, StageID: 1, ItemID: 11, Times: 20, Reporters: 3, Quantity: 2},
+		{GroupID: 1, StartTime: &nextDay, EndTime: &dayAfter, StageID: 1, ItemID: 10, Times: 7, Reporters: 2, Quantity: 0},
+		{GroupID: 1, StartTime: &nextDay, EndTime: &dayAfter, StageID: 1, ItemID: 12, Times: 7, Reporters: 2, Quantity: 0},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(results))
+	}
+	for i, got := range results {
+		if *got != want[i] {
+			t.Errorf("result %d: expected %+v, got %+v", i, want[i], *got)
+		}
+	}
+}
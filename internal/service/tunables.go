@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+)
+
+// tunablesRedisKey is the hash of the overridden tunables, shared by every instance
+const tunablesRedisKey = "tunables"
+
+const (
+	// TunableResultRateLimit is the number of requests with stage or item filters allowed to /result per 5 minutes
+	TunableResultRateLimit = "ratelimit.result"
+	// TunableAdvancedQueryRateLimit is the number of advanced queries allowed per 5 minutes
+	TunableAdvancedQueryRateLimit = "ratelimit.advanced"
+	// TunableResultCacheTTL is how long the responses to /result with stage or item filters are cached for
+	TunableResultCacheTTL = "cache.result.ttl"
+	// TunableGlobalMatrixCacheTTL is how long the global drop and pattern matrices are cached for in between refreshes
+	TunableGlobalMatrixCacheTTL = "cache.global_matrix.ttl"
+	// TunableCustomizedCacheTTL is how long the results of the anonymous customized queries are cached for
+	TunableCustomizedCacheTTL = "cache.customized.ttl"
+	// TunableWorkerSeparation is the time the calculation worker sleeps in between its microtasks
+	TunableWorkerSeparation = "worker.separation"
+	// TunableRareDropMinTimes is the number of times a stage must have been reported before an item is considered
+	// to rarely drop from it
+	TunableRareDropMinTimes = "report.rare_drop.min_times"
+	// TunableRareDropRate is the drop rate under which an item is considered to rarely drop
+	TunableRareDropRate = "report.rare_drop.rate"
+	// TunableForecastTargetTimes is the number of times a stage is considered reliably sampled at by the forecasts,
+	// unless a target is requested
+	TunableForecastTargetTimes = "forecast.target_times"
+	// TunableForecastWindow is the rolling window the report rate of a stage is measured over by the forecasts
+	TunableForecastWindow = "forecast.window"
+	// TunablePlanMinTimes is the number of times a stage must have been reported before its drop rates are planned on
+	TunablePlanMinTimes = "plan.min_times"
+	// TunableBoundsWindow is how far back the patterns are scanned for when suggesting the bounds of the drop infos
+	TunableBoundsWindow = "bounds.window"
+	// TunableBoundsMinReports is the number of reports the bounds of the drop infos are suggested from at least
+	TunableBoundsMinReports = "bounds.min_reports"
+	// TunableShortLinkRateLimit is the number of short links allowed to be created per 5 minutes
+	TunableShortLinkRateLimit = "ratelimit.shortlink"
+	// TunableShortLinkTTL is how long the short links are kept for since they have last been created
+	TunableShortLinkTTL = "shortlink.ttl"
+	// TunableFlaggedReportRateLimit is the number of reports allowed per 5 minutes from an IP flagged by the
+	// SubmissionPolicy
+	TunableFlaggedReportRateLimit = "ratelimit.report.flagged"
+	// TunableChallengeRateLimit is the number of submission challenges allowed to be issued per 5 minutes
+	TunableChallengeRateLimit = "ratelimit.challenge"
+	// TunableChallengeDifficulty is the number of leading zero bits the proof of work of a submission challenge has to
+	// have, each of which doubles the work of solving it
+	TunableChallengeDifficulty = "challenge.difficulty"
+	// TunableQueryCostBudget is the estimated cost above which a customized query is rejected, see QueryCost
+	TunableQueryCostBudget = "query.cost.budget"
+)
+
+const (
+	tunableTypeInt      = "int"
+	tunableTypeFloat    = "float"
+	tunableTypeDuration = "duration"
+)
+
+type tunableSpec struct {
+	typ      string
+	fallback string
+}
+
+// Tunables are the settings that can be changed at runtime without a redeploy, e.g. during live events. Overrides
+// are kept in redis and picked up by every instance within the refresh interval; the defaults apply otherwise.
+type Tunables struct {
+	Redis *redis.Client
+
+	specs map[string]tunableSpec
+	// overrides is the latest known map of the overridden values
+	overrides atomic.Pointer[map[string]string]
+}
+
+func NewTunables(conf *appconfig.Config, redisClient *redis.Client, lc fx.Lifecycle) *Tunables {
+	s := &Tunables{
+		Redis: redisClient,
+		specs: map[string]tunableSpec{
+			TunableResultRateLimit:        {typ: tunableTypeInt, fallback: "300"},
+			TunableAdvancedQueryRateLimit: {typ: tunableTypeInt, fallback: "30"},
+			TunableResultCacheTTL:         {typ: tunableTypeDuration, fallback: "5m"},
+			TunableGlobalMatrixCacheTTL:   {typ: tunableTypeDuration, fallback: "24h"},
+			TunableCustomizedCacheTTL:     {typ: tunableTypeDuration, fallback: "5m"},
+			TunableWorkerSeparation:       {typ: tunableTypeDuration, fallback: conf.WorkerSeparation.String()},
+			TunableRareDropMinTimes:       {typ: tunableTypeInt, fallback: "100"},
+			TunableRareDropRate:           {typ: tunableTypeFloat, fallback: "0.01"},
+			TunableForecastTargetTimes:    {typ: tunableTypeInt, fallback: "1000"},
+			TunableForecastWindow:         {typ: tunableTypeDuration, fallback: "24h"},
+			TunablePlanMinTimes:           {typ: tunableTypeInt, fallback: "100"},
+			TunableBoundsWindow:           {typ: tunableTypeDuration, fallback: "720h"},
+			TunableBoundsMinReports:       {typ: tunableTypeInt, fallback: "500"},
+			TunableShortLinkRateLimit:     {typ: tunableTypeInt, fallback: "10"},
+			TunableShortLinkTTL:           {typ: tunableTypeDuration, fallback: "2160h"},
+			TunableQueryCostBudget:        {typ: tunableTypeInt, fallback: "200000"},
+			TunableFlaggedReportRateLimit: {typ: tunableTypeInt, fallback: "20"},
+			TunableChallengeRateLimit:     {typ: tunableTypeInt, fallback: "30"},
+			TunableChallengeDifficulty:    {typ: tunableTypeInt, fallback: "16"},
+		},
+	}
+	s.overrides.Store(&map[string]string{})
+
+	watchEvery(lc, "tunables", conf.TunablesRefreshInterval, s.refresh)
+	return s
+}
+
+// watchEvery calls refresh every interval while the app is running, keeping the last known state upon failures.
+// A non-positive interval disables the refreshes.
+func watchEvery(lc fx.Lifecycle, name string, interval time.Duration, refresh func(ctx context.Context) error) {
+	if interval <= 0 {
+		return
+	}
+	stop := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					ctx, cancel := context.WithTimeout(context.Background(), interval)
+					if err := refresh(ctx); err != nil {
+						log.Warn().
+							Str("evt.name", name+".refresh").
+							Err(err).
+							Msg("failed to refresh " + name)
+					}
+					cancel()
+
+					select {
+					case <-ticker.C:
+					case <-stop:
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stop)
+			return nil
+		},
+	})
+}
+
+func (s *Tunables) refresh(ctx context.Context) error {
+	overrides, err := s.Redis.HGetAll(ctx, tunablesRedisKey).Result()
+	if err != nil {
+		return err
+	}
+	s.overrides.Store(&overrides)
+	return nil
+}
+
+// value returns the overridden value of a tunable, or its default when it is not overridden or has become invalid
+func (s *Tunables) value(key string) string {
+	spec, ok := s.specs[key]
+	if !ok {
+		panic("unknown tunable " + key)
+	}
+	if v, ok := (*s.overrides.Load())[key]; ok && validTunable(spec.typ, v) {
+		return v
+	}
+	return spec.fallback
+}
+
+func (s *Tunables) Int(key string) int {
+	v, _ := strconv.Atoi(s.value(key))
+	return v
+}
+
+func (s *Tunables) Float(key string) float64 {
+	v, _ := strconv.ParseFloat(s.value(key), 64)
+	return v
+}
+
+func (s *Tunables) Duration(key string) time.Duration {
+	v, _ := time.ParseDuration(s.value(key))
+	return v
+}
+
+// GetTunables lists every tunable along with its current value, as known by this instance
+func (s *Tunables) GetTunables() []*model.Tunable {
+	overrides := *s.overrides.Load()
+	tunables := make([]*model.Tunable, 0, len(s.specs))
+	for key, spec := range s.specs {
+		_, overridden := overrides[key]
+		tunables = append(tunables, &model.Tunable{
+			Key:        key,
+			Type:       spec.typ,
+			Value:      s.value(key),
+			Default:    spec.fallback,
+			Overridden: overridden,
+		})
+	}
+	sort.Slice(tunables, func(i, j int) bool {
+		return tunables[i].Key < tunables[j].Key
+	})
+	return tunables
+}
+
+// SetTunable overrides a tunable on every instance
+func (s *Tunables) SetTunable(ctx context.Context, key string, value string) error {
+	spec, ok := s.specs[key]
+	if !ok {
+		return pgerr.ErrNotFound.Msg("tunable %s does not exist", key)
+	}
+	if !validTunable(spec.typ, value) {
+		return pgerr.ErrInvalidReq.Msg("tunable %s must be a valid %s", key, spec.typ)
+	}
+	if err := s.Redis.HSet(ctx, tunablesRedisKey, key, value).Err(); err != nil {
+		return err
+	}
+	return s.refresh(ctx)
+}
+
+// ResetTunable restores the default value of a tunable on every instance
+func (s *Tunables) ResetTunable(ctx context.Context, key string) error {
+	if _, ok := s.specs[key]; !ok {
+		return pgerr.ErrNotFound.Msg("tunable %s does not exist", key)
+	}
+	if err := s.Redis.HDel(ctx, tunablesRedisKey, key).Err(); err != nil {
+		return err
+	}
+	return s.refresh(ctx)
+}
+
+func validTunable(typ string, value string) bool {
+	var err error
+	switch typ {
+	case tunableTypeInt:
+		var v int
+		v, err = strconv.Atoi(value)
+		if err == nil && v < 0 {
+			return false
+		}
+	case tunableTypeFloat:
+		var v float64
+		v, err = strconv.ParseFloat(value, 64)
+		if err == nil && v < 0 {
+			return false
+		}
+	case tunableTypeDuration:
+		var v time.Duration
+		v, err = time.ParseDuration(value)
+		if err == nil && v < 0 {
+			return false
+		}
+	}
+	return err == nil
+}
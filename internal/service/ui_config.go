@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
+)
+
+// UIConfig generates what the frontend shows in each server from the zones, stages, events and notices
+type UIConfig struct {
+	ZoneService           *Zone
+	StageService          *Stage
+	EventService          *Event
+	NoticeService         *Notice
+	FrontendConfigService *FrontendConfig
+}
+
+func NewUIConfig(zoneService *Zone, stageService *Stage, eventService *Event, noticeService *Notice, frontendConfigService *FrontendConfig) *UIConfig {
+	return &UIConfig{
+		ZoneService:           zoneService,
+		StageService:          stageService,
+		EventService:          eventService,
+		NoticeService:         noticeService,
+		FrontendConfigService: frontendConfigService,
+	}
+}
+
+// GetUIConfigByServer returns the UI config of the server, versioned by a hash of it
+// Cache: uiConfig#server:{server}, 5 min
+func (s *UIConfig) GetUIConfigByServer(ctx context.Context, server string) (*modelv3.UIConfig, error) {
+	var uiConfig modelv3.UIConfig
+	_, err := cache.UIConfigs.MutexGetSet(server, &uiConfig, func() (*modelv3.UIConfig, error) {
+		return s.calcUIConfig(ctx, server)
+	}, time.Minute*5)
+	if err != nil {
+		return nil, err
+	}
+	return &uiConfig, nil
+}
+
+func (s *UIConfig) calcUIConfig(ctx context.Context, server string) (*modelv3.UIConfig, error) {
+	zoneListings, err := s.ZoneService.GetZoneListingsByServer(ctx, server, false)
+	if err != nil {
+		return nil, err
+	}
+	stageListings, err := s.StageService.GetStageListingsByServer(ctx, server, false)
+	if err != nil {
+		return nil, err
+	}
+	eventListings, err := s.EventService.GetEventListings(ctx, server, "")
+	if err != nil {
+		return nil, err
+	}
+	notices, err := s.NoticeService.GetV3Notices(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	frontendConfig, err := s.FrontendConfigService.GetFrontendConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stagesByZone := make(map[int][]string, len(zoneListings))
+	for _, stage := range stageListings {
+		stagesByZone[stage.ZoneID] = append(stagesByZone[stage.ZoneID], stage.ArkStageID)
+	}
+	zones := make([]*modelv3.UIConfigZone, 0, len(zoneListings))
+	for _, zone := range zoneListings {
+		stages := stagesByZone[zone.ZoneID]
+		if stages == nil {
+			stages = []string{}
+		}
+		zones = append(zones, &modelv3.UIConfigZone{
+			ZoneID:    zone.ArkZoneID,
+			Category:  zone.Category,
+			Open:      zone.Open,
+			OpenTime:  zone.OpenTime,
+			CloseTime: zone.CloseTime,
+			Stages:    stages,
+		})
+	}
+
+	highlightedEvents := make([]*modelv3.EventListing, 0, len(eventListings))
+	for _, event := range eventListings {
+		if event.Status != model.EventStatusPast {
+			highlightedEvents = append(highlightedEvents, event)
+		}
+	}
+
+	open := true
+	for _, notice := range notices {
+		if notice.Kind == model.NoticeKindMaintenance {
+			open = false
+			break
+		}
+	}
+
+	uiConfig := &modelv3.UIConfig{
+		Server:            server,
+		GeneratedAt:       time.Now(),
+		Open:              open,
+		Zones:             zones,
+		HighlightedEvents: highlightedEvents,
+		Config:            frontendConfig,
+	}
+	// the version must not change along with the time the config happens to be generated at
+	content, err := json.Marshal([]any{uiConfig.Open, uiConfig.Zones, uiConfig.HighlightedEvents, uiConfig.Config})
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+	uiConfig.Version = hex.EncodeToString(sum[:8])
+	return uiConfig, nil
+}
@@ -13,7 +13,6 @@ import (
 	"time"
 
 	"github.com/goccy/go-json"
-	"github.com/gofiber/fiber/v2"
 	"github.com/pkg/errors"
 
 	"exusiai.dev/backend-next/internal/app/appconfig"
@@ -100,8 +99,9 @@ func (c *Upyun) calculate(policyObj upyunPolicy) (authorization, policy string,
 	return authorization, policyBase64, nil
 }
 
-func (c *Upyun) VerifyImageUploadCallback(ctx *fiber.Ctx) (path string, err error) {
-	body := ctx.Body()
+// VerifyImageUploadCallback verifies the callback request Upyun sends once an image has been uploaded, from the
+// path, the Authorization and Date headers and the body of the request.
+func (c *Upyun) VerifyImageUploadCallback(requestPath, authorization, date string, body []byte) (path string, err error) {
 	v, err := url.ParseQuery(string(body))
 	if err != nil {
 		return "", errors.Wrap(err, "failed to parse body")
@@ -121,8 +121,6 @@ func (c *Upyun) VerifyImageUploadCallback(ctx *fiber.Ctx) (path string, err erro
 		return "", errors.Errorf("upyun upload time expired")
 	}
 
-	authorization := ctx.Get("Authorization")
-
 	if !strings.HasPrefix(authorization, UpyunAuthorizationHeaderRealm) {
 		return "", errors.Errorf("invalid authorization: missing correct header realm in Authorization header")
 	}
@@ -146,7 +144,7 @@ func (c *Upyun) VerifyImageUploadCallback(ctx *fiber.Ctx) (path string, err erro
 	md5Body := md5.Sum(body)
 	md5HexBody := hex.EncodeToString(md5Body[:])
 
-	signatureString := "POST" + "&" + ctx.Path() + "&" + ctx.Get("Date") + "&" + md5HexBody
+	signatureString := "POST" + "&" + requestPath + "&" + date + "&" + md5HexBody
 
 	hmacSha1 := hmac.New(sha1.New, []byte(md5HexPassword))
 	hmacSha1.Write([]byte(signatureString))
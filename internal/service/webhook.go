@@ -0,0 +1,271 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/nats-io/nats.go"
+	"github.com/oklog/ulid/v2"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+	"exusiai.dev/backend-next/internal/pkg/publicnet"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+const (
+	WebhookEventSubject    = "WEBHOOK.EVENT"
+	WebhookDeliverySubject = "WEBHOOK.DELIVERY"
+
+	WebhookSignatureHeader = "X-Penguin-Signature"
+	WebhookEventHeader     = "X-Penguin-Event"
+	WebhookDeliveryHeader  = "X-Penguin-Delivery"
+
+	// maxWebhooksPerAPIKey limits the fan-out of a single API key holder
+	maxWebhooksPerAPIKey = 10
+	webhookSecretBytes   = 24
+)
+
+// ErrWebhookDeliveryRejected is returned when the receiving end of a webhook does not respond with a 2xx status
+var ErrWebhookDeliveryRejected = errors.New("webhook delivery rejected")
+
+// Webhook manages the webhooks subscribed by API key holders, and publishes and delivers the events to them.
+// Events are published to NATS JetStream, so that the delivery happens asynchronously and survives restarts;
+// see internal/workers/webhookwkr for the consumers.
+type Webhook struct {
+	Config      *appconfig.Config
+	NatsJS      nats.JetStreamContext
+	WebhookRepo *repo.Webhook
+
+	client *http.Client
+}
+
+func NewWebhook(conf *appconfig.Config, natsJs nats.JetStreamContext, webhookRepo *repo.Webhook) *Webhook {
+	return &Webhook{
+		Config:      conf,
+		NatsJS:      natsJs,
+		WebhookRepo: webhookRepo,
+		client: &http.Client{
+			Timeout: conf.WebhookDeliveryTimeout,
+			// the deliveries connect to the public addresses only, however the hosts of the webhooks resolve by then,
+			// and never through a proxy which would connect on their behalf
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout: conf.WebhookDeliveryTimeout,
+					Control: publicnet.Control,
+				}).DialContext,
+				ForceAttemptHTTP2:   true,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+				TLSHandshakeTimeout: 10 * time.Second,
+			},
+			// deliveries are not supposed to be redirected elsewhere than the subscribed URL
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (s *Webhook) CreateWebhook(ctx context.Context, apiKey *model.APIKey, req *types.CreateWebhookRequest) (*model.Webhook, error) {
+	webhooks, err := s.WebhookRepo.GetWebhooksByAPIKeyId(ctx, apiKey.APIKeyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) >= maxWebhooksPerAPIKey {
+		return nil, pgerr.ErrInvalidReq.Msg("an api key can subscribe at most %d webhooks", maxWebhooksPerAPIKey)
+	}
+
+	// the webhooks are delivered to the public internet only
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, pgerr.ErrInvalidReq.Msg("invalid webhook url")
+	}
+	if err := publicnet.CheckHost(ctx, u.Hostname()); err != nil {
+		return nil, pgerr.ErrInvalidReq.Msg("webhook url must resolve to public addresses only")
+	}
+
+	secret := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	webhook := &model.Webhook{
+		APIKeyID: apiKey.APIKeyID,
+		URL:      req.URL,
+		Events:   req.Events,
+		Secret:   "whsec_" + hex.EncodeToString(secret),
+	}
+	if err := s.WebhookRepo.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("evt.name", "webhook.created").
+		Int("apiKeyId", apiKey.APIKeyID).
+		Int("webhookId", webhook.WebhookID).
+		Strs("events", webhook.Events).
+		Msg("created webhook")
+
+	return webhook, nil
+}
+
+// GetWebhooks returns the webhooks of the API key, with their secrets redacted
+func (s *Webhook) GetWebhooks(ctx context.Context, apiKey *model.APIKey) ([]*model.Webhook, error) {
+	webhooks, err := s.WebhookRepo.GetWebhooksByAPIKeyId(ctx, apiKey.APIKeyID)
+	if err != nil {
+		return nil, err
+	}
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (s *Webhook) DeleteWebhook(ctx context.Context, apiKey *model.APIKey, webhookId int) error {
+	webhook, err := s.getOwnedWebhook(ctx, apiKey, webhookId)
+	if err != nil {
+		return err
+	}
+	return s.WebhookRepo.DeleteWebhook(ctx, webhook.WebhookID)
+}
+
+// Ping delivers a ping event to the webhook only, so that the holder can verify their endpoint
+func (s *Webhook) Ping(ctx context.Context, apiKey *model.APIKey, webhookId int) (*model.WebhookEvent, error) {
+	webhook, err := s.getOwnedWebhook(ctx, apiKey, webhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := newWebhookEvent(model.WebhookEventPing, map[string]any{"webhookId": webhook.WebhookID})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.publish(ctx, WebhookDeliverySubject, &model.WebhookDelivery{WebhookID: webhook.WebhookID, Event: event}, event.ID); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Publish publishes an event to the webhooks subscribed to its type. As the same event may be observed by
+// multiple instances, dedupKey identifies the occurrence of the event: events of the same type and dedupKey
+// published within 10 minutes are delivered only once.
+func (s *Webhook) Publish(ctx context.Context, typ string, dedupKey string, data any) error {
+	event, err := newWebhookEvent(typ, data)
+	if err != nil {
+		return err
+	}
+	return s.publish(ctx, WebhookEventSubject, event, typ+":"+dedupKey)
+}
+
+// FanOut schedules a delivery of the event to every webhook subscribed to it
+func (s *Webhook) FanOut(ctx context.Context, event *model.WebhookEvent) error {
+	webhooks, err := s.WebhookRepo.GetWebhooksByEvent(ctx, event.Type)
+	if err != nil {
+		return err
+	}
+	for _, webhook := range webhooks {
+		delivery := &model.WebhookDelivery{WebhookID: webhook.WebhookID, Event: event}
+		if err := s.publish(ctx, WebhookDeliverySubject, delivery, event.ID+":"+strconv.Itoa(webhook.WebhookID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deliver posts the event to the webhook. Deliveries to webhooks that have since been deleted are dropped silently.
+func (s *Webhook) Deliver(ctx context.Context, delivery *model.WebhookDelivery) error {
+	webhook, err := s.WebhookRepo.GetWebhookById(ctx, delivery.WebhookID)
+	if errors.Is(err, pgerr.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PenguinStats-Webhook/1.0")
+	req.Header.Set(WebhookEventHeader, delivery.Event.Type)
+	req.Header.Set(WebhookDeliveryHeader, delivery.Event.ID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, time.Now(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Wrapf(ErrWebhookDeliveryRejected, "webhook %d responded with status %d", webhook.WebhookID, resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhookPayload signs the payload in the form of `t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<payload>">`.
+// Receivers should recompute the signature with their webhook secret and reject stale timestamps to prevent replays.
+func SignWebhookPayload(secret string, t time.Time, payload []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return fmt.Sprintf("t=%s,v1=%s", ts, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func (s *Webhook) getOwnedWebhook(ctx context.Context, apiKey *model.APIKey, webhookId int) (*model.Webhook, error) {
+	webhook, err := s.WebhookRepo.GetWebhookById(ctx, webhookId)
+	if err != nil {
+		return nil, err
+	}
+	// do not reveal the existence of webhooks of other holders
+	if webhook.APIKeyID != apiKey.APIKeyID {
+		return nil, pgerr.ErrNotFound
+	}
+	return webhook, nil
+}
+
+func (s *Webhook) publish(ctx context.Context, subject string, v any, msgId string) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = s.NatsJS.Publish(subject, b, nats.Context(ctx), nats.MsgId(msgId))
+	return err
+}
+
+func newWebhookEvent(typ string, data any) (*model.WebhookEvent, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &model.WebhookEvent{
+		ID:        strings.ToLower(ulid.Make().String()),
+		Type:      typ,
+		CreatedAt: time.Now(),
+		Data:      b,
+	}, nil
+}
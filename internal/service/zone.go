@@ -4,11 +4,14 @@ import (
 	"context"
 	"time"
 
+	"github.com/goccy/go-json"
+	"github.com/samber/lo"
 	"github.com/tidwall/gjson"
 
 	"exusiai.dev/backend-next/internal/model"
 	"exusiai.dev/backend-next/internal/model/cache"
 	modelv2 "exusiai.dev/backend-next/internal/model/v2"
+	modelv3 "exusiai.dev/backend-next/internal/model/v3"
 	"exusiai.dev/backend-next/internal/repo"
 )
 
@@ -38,6 +41,34 @@ func (s *Zone) GetZones(ctx context.Context) ([]*model.Zone, error) {
 	return zones, nil
 }
 
+// GetZoneListingsByServer lists the zones existing in the server, optionally only those open right now
+func (s *Zone) GetZoneListingsByServer(ctx context.Context, server string, openOnly bool) ([]*modelv3.ZoneListing, error) {
+	zones, err := s.GetZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	listings := make([]*modelv3.ZoneListing, 0, len(zones))
+	for _, zone := range zones {
+		exist, openTime, closeTime := existenceInServer(zone.Existence, server)
+		if !exist {
+			continue
+		}
+		open := isWithin(now, openTime, closeTime)
+		if openOnly && !open {
+			continue
+		}
+		listings = append(listings, &modelv3.ZoneListing{
+			Zone:      zone,
+			Open:      open,
+			OpenTime:  openTime,
+			CloseTime: closeTime,
+		})
+	}
+	return listings, nil
+}
+
 func (s *Zone) GetZoneById(ctx context.Context, id int) (*model.Zone, error) {
 	return s.ZoneRepo.GetZoneById(ctx, id)
 }
@@ -75,6 +106,7 @@ func (s *Zone) GetShimZones(ctx context.Context) ([]*modelv2.Zone, error) {
 	}
 	cache.ShimZones.Set(zones, time.Minute*5)
 	cache.LastModifiedTime.Set("[shimZones]", time.Now(), 0)
+	cache.RecordContentHash("[shimZones]", zones)
 	return zones, nil
 }
 
@@ -105,3 +137,28 @@ func (s *Zone) applyShim(zone *modelv2.Zone) {
 		}
 	}
 }
+
+// existenceInServer parses the server entry of an existence map
+func existenceInServer(existence json.RawMessage, server string) (exist bool, openTime *time.Time, closeTime *time.Time) {
+	serverExistence := gjson.GetBytes(existence, server)
+	if !serverExistence.Get("exist").Bool() {
+		return false, nil, nil
+	}
+	if t := serverExistence.Get("openTime"); t.Exists() && t.Type == gjson.Number {
+		openTime = lo.ToPtr(time.UnixMilli(t.Int()))
+	}
+	if t := serverExistence.Get("closeTime"); t.Exists() && t.Type == gjson.Number {
+		closeTime = lo.ToPtr(time.UnixMilli(t.Int()))
+	}
+	return true, openTime, closeTime
+}
+
+func isWithin(t time.Time, start *time.Time, end *time.Time) bool {
+	if start != nil && start.After(t) {
+		return false
+	}
+	if end != nil && !end.After(t) {
+		return false
+	}
+	return true
+}
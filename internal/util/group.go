@@ -0,0 +1,35 @@
+package util
+
+// Group is the elements sharing a key, in the order they appear in the grouped slice
+type Group[K comparable, T any] struct {
+	Key    K
+	Values []T
+}
+
+// GroupBy groups the elements of s by their keys. Unlike linq's GroupBy, the groups come in the order their keys
+// first appear in, and neither the keys nor the elements are boxed into interface{}.
+func GroupBy[T any, K comparable](s []T, key func(T) K) []Group[K, T] {
+	groups := make([]Group[K, T], 0)
+	indexes := make(map[K]int)
+	for _, el := range s {
+		k := key(el)
+		i, ok := indexes[k]
+		if !ok {
+			i = len(groups)
+			indexes[k] = i
+			groups = append(groups, Group[K, T]{Key: k})
+		}
+		groups[i].Values = append(groups[i].Values, el)
+	}
+	return groups
+}
+
+// ToMap maps the keys of the elements of s to their values. The value of a key shared by more than one element is
+// that of the last one.
+func ToMap[T any, K comparable, V any](s []T, key func(T) K, value func(T) V) map[K]V {
+	m := make(map[K]V, len(s))
+	for _, el := range s {
+		m[key(el)] = value(el)
+	}
+	return m
+}
@@ -0,0 +1,88 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ahmetb/go-linq/v3"
+)
+
+type groupedResult struct {
+	StageID  int
+	ItemID   int
+	Quantity int
+}
+
+func TestGroupByKeepsTheOrderOfKeys(t *testing.T) {
+	results := []*groupedResult{
+		{StageID: 2, ItemID: 1},
+		{StageID: 1, ItemID: 2},
+		{StageID: 2, ItemID: 3},
+		{StageID: 3, ItemID: 4},
+		{StageID: 1, ItemID: 5},
+	}
+	groups := GroupBy(results, func(el *groupedResult) int { return el.StageID })
+
+	var keys []int
+	var itemIds [][]int
+	for _, group := range groups {
+		keys = append(keys, group.Key)
+		var ids []int
+		for _, el := range group.Values {
+			ids = append(ids, el.ItemID)
+		}
+		itemIds = append(itemIds, ids)
+	}
+	if want := []int{2, 1, 3}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("expected keys %v, got %v", want, keys)
+	}
+	if want := [][]int{{1, 3}, {2, 5}, {4}}; !reflect.DeepEqual(itemIds, want) {
+		t.Errorf("expected item ids %v, got %v", want, itemIds)
+	}
+}
+
+// benchmarkResults are shaped like the aggregates of a drop matrix: a few hundred stages dropping a dozen items each
+func benchmarkResults() []*groupedResult {
+	results := make([]*groupedResult, 0, 300*12)
+	for itemId := 0; itemId < 12; itemId++ {
+		for stageId := 0; stageId < 300; stageId++ {
+			results = append(results, &groupedResult{StageID: stageId, ItemID: itemId, Quantity: stageId * itemId})
+		}
+	}
+	return results
+}
+
+func BenchmarkGroupBy(b *testing.B) {
+	results := benchmarkResults()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, group := range GroupBy(results, func(el *groupedResult) int { return el.StageID }) {
+			_ = ToMap(group.Values,
+				func(el *groupedResult) int { return el.ItemID },
+				func(el *groupedResult) int { return el.Quantity })
+		}
+	}
+}
+
+// BenchmarkLinqGroupBy is BenchmarkGroupBy done the way the aggregations did before GroupBy, for comparison
+func BenchmarkLinqGroupBy(b *testing.B) {
+	results := benchmarkResults()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var groups []linq.Group
+		linq.From(results).
+			GroupByT(
+				func(el *groupedResult) int { return el.StageID },
+				func(el *groupedResult) *groupedResult { return el }).
+			ToSlice(&groups)
+		for _, group := range groups {
+			m := make(map[int]int)
+			linq.From(group.Group).
+				ToMapByT(&m,
+					func(el any) int { return el.(*groupedResult).ItemID },
+					func(el any) int { return el.(*groupedResult).Quantity })
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package i18n
+
+import "golang.org/x/text/language"
+
+// Languages are the languages item and stage names are available in, with the fallback one first
+var Languages = []string{"zh", "en", "ja", "ko"}
+
+var languageMatcher = language.NewMatcher([]language.Tag{
+	language.Chinese,
+	language.English,
+	language.Japanese,
+	language.Korean,
+})
+
+// IsLanguage reports whether item and stage names are available in lang
+func IsLanguage(lang string) bool {
+	for _, l := range Languages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchLanguage picks the language item and stage names should be in for the given Accept-Language header,
+// falling back to zh when none of the languages are acceptable
+func MatchLanguage(acceptLanguage string) string {
+	_, index := language.MatchStrings(languageMatcher, acceptLanguage)
+	return Languages[index]
+}
@@ -0,0 +1,27 @@
+package i18n_test
+
+import (
+	"testing"
+
+	"exusiai.dev/backend-next/internal/util/i18n"
+)
+
+func TestMatchLanguage(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"", "zh"},
+		{"en-US,en;q=0.9", "en"},
+		{"ja-JP", "ja"},
+		{"ko", "ko"},
+		{"zh-CN,zh;q=0.9,en;q=0.8", "zh"},
+		{"fr-FR,en;q=0.5", "en"},
+		{"de", "zh"},
+	}
+	for _, tt := range tests {
+		if got := i18n.MatchLanguage(tt.acceptLanguage); got != tt.want {
+			t.Errorf("MatchLanguage(%q) = %q, want %q", tt.acceptLanguage, got, tt.want)
+		}
+	}
+}
@@ -74,6 +74,18 @@ func CalcTScore(bundle1, bundle2 *StatsBundle) float64 {
 	return math.Abs((bundle1.Avg - bundle2.Avg) / SE)
 }
 
+// DropRatePrecision is the number of decimal places the drop rates of the responses are rounded to
+const DropRatePrecision = 6
+
+// DropRate is the quantity per time, rounded half away from zero to DropRatePrecision decimal places, or 0 when
+// there are no times. The responses carry it so that the clients need not divide and round on their own.
+func DropRate(quantity, times int) float64 {
+	if times <= 0 {
+		return 0
+	}
+	return RoundFloat64(float64(quantity)/float64(times), DropRatePrecision)
+}
+
 func RoundFloat64(f float64, n int) float64 {
 	pow := math.Pow10(n)
 	return math.Round(f*pow) / pow
@@ -1,6 +1,7 @@
 package rekuest
 
 import (
+	"reflect"
 	"strings"
 
 	"exusiai.dev/gommon/constant"
@@ -21,6 +22,9 @@ import (
 var Validate = util.NewValidator()
 
 func init() {
+	// the violations name the fields as the clients send them, rather than by the Go fields they are parsed into
+	Validate.RegisterTagNameFunc(fieldName)
+
 	var err error
 	entr, _ := i18n.UT.GetTranslator("en")
 	err = enTranslations.RegisterDefaultTranslations(Validate, entr)
@@ -78,12 +82,38 @@ func init() {
 	}
 }
 
+// ErrorResponse is a violation of the request, which is served among the `violations` of the INVALID_REQUEST errors.
+// Violation is machine-readable: it is either the validator tag violated, e.g. required or oneof, or one of the
+// Violation constants for the rules the tags can't express.
 type ErrorResponse struct {
 	Field     string `json:"field,omitempty"`
 	Violation string `json:"violation"`
 	Message   string `json:"message"`
 }
 
+// fieldName names the field by the first of its json, query and params tags
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "query", "params"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// fieldPath is the path of the field violated within the request, without the name of the request struct itself
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if _, path, ok := strings.Cut(ns, "."); ok {
+		return path
+	}
+	return ns
+}
+
 // Translate translates errors into ErrorResponses
 func translate(utt ut.Translator, ve validator.ValidationErrors) []*ErrorResponse {
 	trans := []*ErrorResponse{}
@@ -97,7 +127,7 @@ func translate(utt ut.Translator, ve validator.ValidationErrors) []*ErrorRespons
 		message = util.AddSpace(message)
 
 		trans = append(trans, &ErrorResponse{
-			Field:     fe.Namespace(),
+			Field:     fieldPath(fe),
 			Violation: fe.Tag(),
 			Message:   message,
 		})
@@ -106,12 +136,16 @@ func translate(utt ut.Translator, ve validator.ValidationErrors) []*ErrorRespons
 	return trans
 }
 
-func validateVar(ctx *fiber.Ctx, s any, tag string) []*ErrorResponse {
+func validateVar(ctx *fiber.Ctx, name string, s any, tag string) []*ErrorResponse {
 	tr := TranslatorFromCtx(ctx)
-	err := Validate.Var(s, tag)
+	err := Validate.VarCtx(ctx.UserContext(), s, tag)
 	if err != nil {
 		errs := err.(validator.ValidationErrors)
-		return translate(tr, errs)
+		violations := translate(tr, errs)
+		for _, violation := range violations {
+			violation.Field = name
+		}
+		return violations
 	}
 	return nil
 }
@@ -135,7 +169,7 @@ func ValidateStruct(ctx *fiber.Ctx, s any) []*ErrorResponse {
 // always be a pointer.
 func ValidBody(ctx *fiber.Ctx, dest any) error {
 	if err := ctx.BodyParser(dest); err != nil {
-		return pgerr.ErrInvalidReq.Msg("invalid request: %s", err)
+		return Violation("", ViolationMalformed, "invalid body: "+err.Error())
 	}
 
 	if err := ValidateStruct(ctx, dest); err != nil {
@@ -151,7 +185,21 @@ func ValidBody(ctx *fiber.Ctx, dest any) error {
 // always be a pointer.
 func ValidQuery(ctx *fiber.Ctx, dest any) error {
 	if err := ctx.QueryParser(dest); err != nil {
-		return pgerr.ErrInvalidReq.Msg("invalid request: %s", err)
+		return Violation("", ViolationMalformed, "invalid query: "+err.Error())
+	}
+
+	if err := ValidateStruct(ctx, dest); err != nil {
+		return pgerr.NewInvalidViolations(err)
+	}
+
+	return nil
+}
+
+// ValidParams will get the route params from *fiber.Ctx using fiber#ParamsParser(), and validate them the same way
+// as ValidQuery does. Notice that dest shall always be a pointer.
+func ValidParams(ctx *fiber.Ctx, dest any) error {
+	if err := ctx.ParamsParser(dest); err != nil {
+		return Violation("", ViolationMalformed, "invalid params: "+err.Error())
 	}
 
 	if err := ValidateStruct(ctx, dest); err != nil {
@@ -169,8 +217,9 @@ func ValidStruct(ctx *fiber.Ctx, dest any) error {
 	return nil
 }
 
-func ValidVar(ctx *fiber.Ctx, field any, tag string) error {
-	if err := validateVar(ctx, field, tag); err != nil {
+// ValidVar validates a single field of the request named by name against the validator tag
+func ValidVar(ctx *fiber.Ctx, name string, field any, tag string) error {
+	if err := validateVar(ctx, name, field, tag); err != nil {
 		return pgerr.NewInvalidViolations(err)
 	}
 
@@ -179,7 +228,7 @@ func ValidVar(ctx *fiber.Ctx, field any, tag string) error {
 
 func ValidServer(ctx *fiber.Ctx, server string) error {
 	type request struct {
-		Server string `validate:"required,arkserver"`
+		Server string `json:"server" validate:"required,arkserver"`
 	}
 
 	if err := ValidStruct(ctx, request{server}); err != nil {
@@ -191,7 +240,7 @@ func ValidServer(ctx *fiber.Ctx, server string) error {
 
 func ValidCategory(ctx *fiber.Ctx, category string) error {
 	type request struct {
-		Category string `validate:"oneof=all automated manual"`
+		Category string `json:"category" validate:"oneof=all automated manual"`
 	}
 
 	if err := ValidStruct(ctx, request{category}); err != nil {
@@ -0,0 +1,39 @@
+package rekuest
+
+import (
+	"fmt"
+
+	"exusiai.dev/backend-next/internal/pkg/pgerr"
+)
+
+// The violations of the rules that can't be expressed by the validator tags. The ones that can are reported by the
+// name of their tags instead, e.g. required, oneof, min and max.
+const (
+	// ViolationMalformed is the input that can't be parsed into what it is expected to be
+	ViolationMalformed = "malformed"
+	// ViolationConflict is the input given alongside another one that it can't be combined with
+	ViolationConflict = "conflict"
+	// ViolationDependency is the input that requires another one to be given as well
+	ViolationDependency = "dependency"
+	// ViolationUnsupported is the input that is valid by itself but isn't supported in its context
+	ViolationUnsupported = "unsupported"
+	// ViolationRange is the input out of the range it is allowed within
+	ViolationRange = "range"
+	// ViolationUnverified is the input whose signature or encryption fails to be verified
+	ViolationUnverified = "unverified"
+)
+
+// Violation returns the INVALID_REQUEST error of the field of the request violating the rule, for the rules checked
+// by hand. field may be left empty for the request as a whole.
+func Violation(field, violation, message string) *pgerr.PenguinError {
+	return pgerr.NewInvalidViolations([]*ErrorResponse{{
+		Field:     field,
+		Violation: violation,
+		Message:   message,
+	}})
+}
+
+// Violationf is Violation with the message formatted
+func Violationf(field, violation, format string, parts ...any) *pgerr.PenguinError {
+	return Violation(field, violation, fmt.Sprintf(format, parts...))
+}
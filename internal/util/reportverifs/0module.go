@@ -7,7 +7,10 @@ func Module() fx.Option {
 		NewMD5Verifier,
 		NewUserVerifier,
 		NewDropVerifier,
+		NewClearTimeVerifier,
 		NewReportVerifier,
 		NewRejectRuleVerifier,
+		NewShadowBanVerifier,
+		NewQuarantineVerifier,
 	))
 }
@@ -19,12 +19,15 @@ type Verifier interface {
 
 type ReportVerifiers []Verifier
 
-func NewReportVerifier(userVerifier *UserVerifier, dropVerifier *DropVerifier, md5Verifier *MD5Verifier, rejectRuleVerifier *RejectRuleVerifier) *ReportVerifiers {
+func NewReportVerifier(userVerifier *UserVerifier, dropVerifier *DropVerifier, md5Verifier *MD5Verifier, clearTimeVerifier *ClearTimeVerifier, rejectRuleVerifier *RejectRuleVerifier, shadowBanVerifier *ShadowBanVerifier, quarantineVerifier *QuarantineVerifier) *ReportVerifiers {
 	return &ReportVerifiers{
 		userVerifier,
 		md5Verifier,
 		dropVerifier,
+		clearTimeVerifier,
 		rejectRuleVerifier,
+		shadowBanVerifier,
+		quarantineVerifier,
 	}
 }
 
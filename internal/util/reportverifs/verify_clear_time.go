@@ -0,0 +1,121 @@
+package reportverifs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/model/cache"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+const (
+	// ViolationReliabilityClearTime is the reliability of the reports submitted for a stage sooner after the previous
+	// report of the account for the stage than it takes to clear the stage
+	ViolationReliabilityClearTime = 14
+
+	// clearTimeTolerance leaves room for the minimum clear times being slightly overestimated, and for the clocks
+	// of the instances creating the report tasks drifting apart
+	clearTimeTolerance = 0.9
+
+	// lastClearRedisKeyPrefix prefixes the keys of the task id and time of the latest report of an account for a stage
+	lastClearRedisKeyPrefix = "lastclear:"
+	// lastClearTTL is long enough to cover the longest stages cleared with the most times
+	lastClearTTL = time.Hour * 2
+)
+
+var ErrClearTimeImplausible = errors.New("reported sooner than the stage can be cleared")
+
+// ClearTimeVerifier rejects the reports of an account submitted for a stage sooner after its previous report for
+// the same stage than the minimum clear time of the stage times the number of times it is reported to be cleared.
+// The reports of the same task are not checked against each other, as a task may batch clears from the past.
+type ClearTimeVerifier struct {
+	Redis     *redis.Client
+	StageRepo *repo.Stage
+}
+
+// ensure ClearTimeVerifier conforms to Verifier
+var _ Verifier = (*ClearTimeVerifier)(nil)
+
+func NewClearTimeVerifier(redisClient *redis.Client, stageRepo *repo.Stage) *ClearTimeVerifier {
+	return &ClearTimeVerifier{
+		Redis:     redisClient,
+		StageRepo: stageRepo,
+	}
+}
+
+func (v *ClearTimeVerifier) Name() string {
+	return "clear_time"
+}
+
+func (v *ClearTimeVerifier) Verify(ctx context.Context, report *types.ReportTaskSingleReport, reportTask *types.ReportTask) *Rejection {
+	if reportTask.AccountID == 0 || report.Times <= 0 {
+		return nil
+	}
+
+	stage, err := getStage(ctx, v.StageRepo, report.StageID)
+	if err != nil || !stage.MinClearTime.Valid || stage.MinClearTime.Int64 <= 0 {
+		return nil
+	}
+
+	reportedAt := time.UnixMicro(reportTask.CreatedAt)
+	key := lastClearRedisKeyPrefix + strconv.Itoa(reportTask.AccountID) + ":" + reportTask.Server + ":" + report.StageID
+	previous, err := v.Redis.SetArgs(ctx, key, reportTask.TaskID+"|"+strconv.FormatInt(reportedAt.UnixMilli(), 10), redis.SetArgs{
+		TTL: lastClearTTL,
+		Get: true,
+	}).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		// the check is best-effort: an unavailable redis does not reject the report
+		log.Warn().
+			Err(err).
+			Str("evt.name", "reportverifs.clear_time.track").
+			Int("accountId", reportTask.AccountID).
+			Msg("failed to track the report time of the account")
+		return nil
+	}
+
+	previousTaskId, previousMilli, ok := strings.Cut(previous, "|")
+	if !ok || previousTaskId == reportTask.TaskID {
+		return nil
+	}
+	previousAt, err := strconv.ParseInt(previousMilli, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	interval := reportedAt.Sub(time.UnixMilli(previousAt))
+	expected := time.Duration(float64(stage.MinClearTime.Int64)*float64(report.Times)*clearTimeTolerance) * time.Millisecond
+	if interval >= expected {
+		return nil
+	}
+
+	return &Rejection{
+		Reliability: ViolationReliabilityClearTime,
+		Message: errors.Wrap(ErrClearTimeImplausible,
+			fmt.Sprintf("%s cleared %d times in %s since the previous report, expected at least %s",
+				report.StageID, report.Times, interval.Round(time.Millisecond), expected.Round(time.Millisecond))).Error(),
+	}
+}
+
+// getStage returns the stage from the cache shared with the stage service, falling back to the database
+func getStage(ctx context.Context, stageRepo *repo.Stage, arkStageId string) (*model.Stage, error) {
+	var stage model.Stage
+	if err := cache.StageByArkID.Get(arkStageId, &stage); err == nil {
+		return &stage, nil
+	}
+
+	dbStage, err := stageRepo.GetStageByArkId(ctx, arkStageId)
+	if err != nil {
+		return nil, err
+	}
+	cache.StageByArkID.Set(arkStageId, *dbStage, time.Minute*5)
+	return dbStage, nil
+}
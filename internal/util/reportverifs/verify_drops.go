@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"exusiai.dev/gommon/constant"
 	"github.com/pkg/errors"
@@ -41,10 +42,13 @@ func (d *DropVerifier) Name() string {
 }
 
 func (d *DropVerifier) Verify(ctx context.Context, report *types.ReportTaskSingleReport, reportTask *types.ReportTask) *Rejection {
-	itemDropInfos, typeDropInfos, err := d.DropInfoRepo.GetForCurrentTimeRangeWithDropTypes(ctx, &repo.DropInfoQuery{
+	// the drop infos are those in effect when the report is submitted, not when the task is consumed, so that
+	// reports queued across a cut over of the drop table are verified against their own version
+	reportedAt := time.UnixMicro(reportTask.CreatedAt)
+	itemDropInfos, typeDropInfos, err := d.DropInfoRepo.GetForTimeRangeAtWithDropTypes(ctx, &repo.DropInfoQuery{
 		Server:     reportTask.Server,
 		ArkStageId: report.StageID,
-	})
+	}, reportedAt)
 	if err != nil {
 		return &Rejection{
 			Reliability: constant.ViolationReliabilityDrop,
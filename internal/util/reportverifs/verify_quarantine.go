@@ -0,0 +1,62 @@
+package reportverifs
+
+import (
+	"context"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// ViolationReliabilityQuarantine is the reliability of the otherwise valid reports submitted within the quarantine
+// window after their stage opens, which are excluded from the aggregations until revalidated
+const ViolationReliabilityQuarantine = 16
+
+// QuarantineVerifier quarantines the reports of the stages which opened in the server less than the quarantine
+// window ago. It runs last, so that the reports it marks are exactly those to be revalidated after the window.
+type QuarantineVerifier struct {
+	Config    *appconfig.Config
+	StageRepo *repo.Stage
+}
+
+// ensure QuarantineVerifier conforms to Verifier
+var _ Verifier = (*QuarantineVerifier)(nil)
+
+func NewQuarantineVerifier(conf *appconfig.Config, stageRepo *repo.Stage) *QuarantineVerifier {
+	return &QuarantineVerifier{
+		Config:    conf,
+		StageRepo: stageRepo,
+	}
+}
+
+func (v *QuarantineVerifier) Name() string {
+	return "quarantine"
+}
+
+func (v *QuarantineVerifier) Verify(ctx context.Context, report *types.ReportTaskSingleReport, reportTask *types.ReportTask) *Rejection {
+	if v.Config.ReportQuarantineWindow <= 0 {
+		return nil
+	}
+
+	stage, err := getStage(ctx, v.StageRepo, report.StageID)
+	if err != nil {
+		return nil
+	}
+	openTime := gjson.GetBytes(stage.Existence, reportTask.Server+".openTime")
+	if !openTime.Exists() {
+		return nil
+	}
+
+	reportedAt := time.UnixMicro(reportTask.CreatedAt)
+	if reportedAt.Sub(time.UnixMilli(openTime.Int())) >= v.Config.ReportQuarantineWindow {
+		return nil
+	}
+
+	return &Rejection{
+		Reliability: ViolationReliabilityQuarantine,
+		Message:     "stage opened less than the quarantine window ago",
+	}
+}
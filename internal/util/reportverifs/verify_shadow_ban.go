@@ -0,0 +1,57 @@
+package reportverifs
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"exusiai.dev/backend-next/internal/model/types"
+	"exusiai.dev/backend-next/internal/repo"
+)
+
+// ViolationReliabilityShadowBan is the reliability of the otherwise valid reports of shadow-banned accounts, which
+// are accepted as usual but excluded from the aggregations, as only the reports of reliability 0 are aggregated
+const ViolationReliabilityShadowBan = 15
+
+// ShadowBanVerifier marks the reports of shadow-banned accounts. It runs after every verifier rejecting invalid reports,
+// so that the reports it marks are exactly those to be restored when the shadow ban is lifted.
+type ShadowBanVerifier struct {
+	AccountRepo *repo.Account
+}
+
+// ensure ShadowBanVerifier conforms to Verifier
+var _ Verifier = (*ShadowBanVerifier)(nil)
+
+func NewShadowBanVerifier(accountRepo *repo.Account) *ShadowBanVerifier {
+	return &ShadowBanVerifier{
+		AccountRepo: accountRepo,
+	}
+}
+
+func (v *ShadowBanVerifier) Name() string {
+	return "shadow_ban"
+}
+
+func (v *ShadowBanVerifier) Verify(ctx context.Context, report *types.ReportTaskSingleReport, reportTask *types.ReportTask) *Rejection {
+	if reportTask.AccountID == 0 {
+		return nil
+	}
+
+	banned, err := v.AccountRepo.IsAccountShadowBanned(ctx, reportTask.AccountID)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("evt.name", "reportverifs.shadow_ban.check").
+			Int("accountId", reportTask.AccountID).
+			Msg("failed to check whether the account is shadow-banned")
+		return nil
+	}
+	if !banned {
+		return nil
+	}
+
+	return &Rejection{
+		Reliability: ViolationReliabilityShadowBan,
+		Message:     "account is shadow-banned",
+	}
+}
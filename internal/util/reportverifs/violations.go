@@ -34,3 +34,7 @@ type Rejection struct {
 	Reliability int    `json:"reliability"`
 	Message     string `json:"message"`
 }
+
+// ViolationReliabilityModeration is the reliability of the reports a moderator has reviewed as invalid, which are
+// excluded from the aggregations
+const ViolationReliabilityModeration = 17
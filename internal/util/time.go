@@ -26,6 +26,25 @@ func GetDayStartTimestampFromDayNum(dayNum int, server string) int64 {
 	return GetDayStartTime(&serverOpenStartTime, server) + int64(dayNum)*86400000
 }
 
+// AlignToServerDay floors t to the start of its day in the server when the interval is a whole number of days, so
+// that the intervals of a trend starting from it line up with the days of the server. t is returned as is otherwise.
+func AlignToServerDay(t time.Time, interval time.Duration, server string) time.Time {
+	if interval <= 0 || interval%(24*time.Hour) != 0 {
+		return t
+	}
+	return time.UnixMilli(GetDayStartTime(&t, server))
+}
+
+// GetBucketBoundaries returns the boundaries of n consecutive intervals from start in unix milliseconds, i.e. the
+// start of each of the intervals followed by the end of the last one
+func GetBucketBoundaries(start time.Time, interval time.Duration, n int) []int64 {
+	boundaries := make([]int64, 0, n+1)
+	for i := 0; i <= n; i++ {
+		boundaries = append(boundaries, start.Add(interval*time.Duration(i)).UnixMilli())
+	}
+	return boundaries
+}
+
 func GetTimeStampInServer(t *time.Time, server string) int64 {
 	loc := constant.LocMap[server]
 	localT := t.In(loc)
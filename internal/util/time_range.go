@@ -1,9 +1,37 @@
 package util
 
 import (
+	"time"
+
 	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/pkg/gameday"
 )
 
+// RecentTimeRange returns the rolling window of the last days before now. The window ends at the start of the hour of
+// now rather than now itself, so that the queries within the hour share the window.
+func RecentTimeRange(now time.Time, days int) *model.TimeRange {
+	endTime := now.Truncate(time.Hour)
+	startTime := endTime.AddDate(0, 0, -days)
+	return &model.TimeRange{
+		StartTime: &startTime,
+		EndTime:   &endTime,
+	}
+}
+
+// RecentGameDaysTimeRange returns the window made of the game day of now in the server and the days before it, days in
+// total. The window ends with the game day of now, so that it is made of whole days.
+func RecentGameDaysTimeRange(now time.Time, days int, server string) *model.TimeRange {
+	startTime := gameday.StartTime(server, now)
+	endTime := gameday.NextStartTime(server, startTime)
+	for i := 1; i < days; i++ {
+		startTime = gameday.StartTime(server, startTime.Add(-time.Hour))
+	}
+	return &model.TimeRange{
+		StartTime: &startTime,
+		EndTime:   &endTime,
+	}
+}
+
 func GetIntersection(timeRange1 *model.TimeRange, timeRange2 *model.TimeRange) *model.TimeRange {
 	if timeRange1 == nil || timeRange2 == nil {
 		return nil
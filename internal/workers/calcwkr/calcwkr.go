@@ -3,6 +3,7 @@ package calcwkr
 import (
 	"context"
 	"net/http"
+	"strconv"
 	"time"
 
 	"exusiai.dev/gommon/constant"
@@ -10,21 +11,33 @@ import (
 	"github.com/go-redsync/redsync/v4"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 
 	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/pkg/observability"
+	"exusiai.dev/backend-next/internal/pkg/pgpool"
 	"exusiai.dev/backend-next/internal/service"
 )
 
+var tracer = otel.Tracer("calcwkr")
+
 type WorkerDeps struct {
 	fx.In
 
 	Config               *appconfig.Config
 	DropMatrixService    *service.DropMatrix
+	DropMatrixDelta      *service.DropMatrixDelta
 	PatternMatrixService *service.PatternMatrix
 	TrendService         *service.Trend
 	SiteStatsService     *service.SiteStats
 	ArchiveService       *service.Archive
+	WebhookService       *service.Webhook
+	Freshness            *service.Freshness
+	Tunables             *service.Tunables
 	RedSync              *redsync.Redsync
 }
 
@@ -100,28 +113,37 @@ func (w *Worker) checkConfig() {
 	}
 }
 
+// separation is the separation time in-between microtasks, which defaults to sep but can be tuned at runtime
+func (w *Worker) separation() time.Duration {
+	return w.Tunables.Duration(service.TunableWorkerSeparation)
+}
+
 func (w *Worker) doMainCalc(sourceCategories []string) {
-	w.task(context.Background(), WorkerCalcTypeStatsCalc, func(ctx context.Context, server string) error {
+	w.task(pgpool.WithBackground(context.Background()), WorkerCalcTypeStatsCalc, func(ctx context.Context, server string) error {
 		var err error
 
 		// DropMatrixService
-		if err = w.microtask(ctx, "dropMatrix", server, func() error {
+		if err = w.microtask(ctx, "dropMatrix", server, func(ctx context.Context) error {
 			return w.DropMatrixService.RunCalcDropMatrixJob(ctx, server)
 		}); err != nil {
 			return err
 		}
-		time.Sleep(w.sep)
+		w.markRefreshed(ctx, server, model.RefreshJobRealmMatrix)
+		w.publishMatrixRefreshed(ctx, server)
+		w.recordMatrixSnapshot(ctx, server)
+		time.Sleep(w.separation())
 
 		// PatternMatrixService
-		if err = w.microtask(ctx, "patternMatrix", server, func() error {
+		if err = w.microtask(ctx, "patternMatrix", server, func(ctx context.Context) error {
 			return w.PatternMatrixService.RunCalcPatternMatrixJob(ctx, server)
 		}); err != nil {
 			return err
 		}
-		time.Sleep(w.sep)
+		w.markRefreshed(ctx, server, model.RefreshJobRealmPattern)
+		time.Sleep(w.separation())
 
 		// SiteStatsService
-		if err = w.microtask(ctx, "siteStats", server, func() error {
+		if err = w.microtask(ctx, "siteStats", server, func(ctx context.Context) error {
 			_, err := w.SiteStatsService.RefreshShimSiteStats(ctx, server)
 			return err
 		}); err != nil {
@@ -130,9 +152,9 @@ func (w *Worker) doMainCalc(sourceCategories []string) {
 
 		// server == "CN": we only run archive job on a singular server
 		if w.Config.DropReportArchiveEnabled && server == "CN" {
-			// Archive
-			if err = w.microtask(ctx, "archive", server, func() error {
-				err := w.ArchiveService.ArchiveByGlobalConfig(ctx)
+			// Archive: the archive itself is run by the job worker
+			if err = w.microtask(ctx, "archive", server, func(ctx context.Context) error {
+				err := w.ArchiveService.EnqueueArchiveByGlobalConfig(ctx)
 				return err
 			}); err != nil {
 				return err
@@ -143,6 +165,40 @@ func (w *Worker) doMainCalc(sourceCategories []string) {
 	})
 }
 
+type matrixRefreshedEvent struct {
+	Server      string    `json:"server"`
+	RefreshedAt time.Time `json:"refreshedAt"`
+}
+
+// publishMatrixRefreshed notifies the webhooks that the drop matrix of the server has been refreshed.
+// A failure to do so is not considered as a failure of the batch.
+func (w *Worker) publishMatrixRefreshed(ctx context.Context, server string) {
+	now := time.Now()
+	err := w.WebhookService.Publish(ctx, model.WebhookEventMatrixRefreshed, server+":"+strconv.FormatInt(now.Unix(), 10), &matrixRefreshedEvent{
+		Server:      server,
+		RefreshedAt: now,
+	})
+	if err != nil {
+		log.Ctx(ctx).Warn().Str("evt.name", "worker.calcwkr.webhook").Str("server", server).Err(err).Msg("failed to publish matrix refreshed event")
+	}
+}
+
+// markRefreshed records the freshness of the realm of the server refreshed.
+// A failure to do so is not considered as a failure of the batch.
+func (w *Worker) markRefreshed(ctx context.Context, server string, realm string) {
+	if err := w.Freshness.MarkRefreshed(ctx, server, realm, time.Now()); err != nil {
+		log.Ctx(ctx).Warn().Str("evt.name", "worker.calcwkr.freshness").Str("server", server).Str("realm", realm).Err(err).Msg("failed to record freshness")
+	}
+}
+
+// recordMatrixSnapshot records the refreshed drop matrix of the server for the deltas to be computed against.
+// A failure to do so is not considered as a failure of the batch.
+func (w *Worker) recordMatrixSnapshot(ctx context.Context, server string) {
+	if err := w.DropMatrixDelta.RecordSnapshot(ctx, server); err != nil {
+		log.Ctx(ctx).Warn().Str("evt.name", "worker.calcwkr.snapshot").Str("server", server).Err(err).Msg("failed to record drop matrix snapshot")
+	}
+}
+
 func (w *Worker) lock() error {
 	return w.syncMutex.Lock()
 }
@@ -229,7 +285,7 @@ func (w *Worker) task(ctx context.Context, typ WorkerCalcType, f func(ctx contex
 	}()
 }
 
-func (w *Worker) microtask(ctx context.Context, service, server string, f func() error) error {
+func (w *Worker) microtask(ctx context.Context, service, server string, f func(ctx context.Context) error) (err error) {
 	mutexNotifierTicker := time.NewTicker(time.Second * 10)
 	defer func() {
 		mutexNotifierTicker.Stop()
@@ -247,8 +303,13 @@ func (w *Worker) microtask(ctx context.Context, service, server string, f func()
 		}
 	}()
 
+	spanCtx, span := tracer.Start(ctx, "calcwkr."+service, trace.WithAttributes(attribute.String("server", server)))
+	defer func() { observability.EndSpan(span, err) }()
+
 	log.Ctx(ctx).Info().Str("evt.name", "worker.calcwkr."+service).Str("server", server).Msg("worker microtask started calculating")
-	if err := observeCalcDuration(service, server, f); err != nil {
+	if err := observeCalcDuration(service, server, func() error {
+		return f(spanCtx)
+	}); err != nil {
 		log.Ctx(ctx).Error().Str("evt.name", "worker.calcwkr."+service).Str("server", server).Err(err).Msg("worker microtask failed")
 		return err
 	}
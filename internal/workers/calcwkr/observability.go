@@ -12,5 +12,7 @@ func observeCalcDuration(service string, server string, f func() error) error {
 		dur := time.Since(start)
 		observability.WorkerCalcDuration.WithLabelValues(service, server).Set(float64(dur.Seconds()))
 	}()
-	return f()
+	err := f()
+	observability.ObserveJob(service, server, start, err)
+	return err
 }
@@ -0,0 +1,154 @@
+package jobwkr
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/service"
+)
+
+type WorkerDeps struct {
+	fx.In
+
+	Jobs *service.Jobs
+
+	// the services register the handlers of their kinds of jobs upon construction, and are therefore required
+	// for their jobs to be run even if they are not used otherwise
+	RefreshJobService    *service.RefreshJob
+	ArchiveService       *service.Archive
+	IntegrityService     *service.Integrity
+	DropReportPartitions *service.DropReportPartitions
+	RetentionService     *service.Retention
+	ReportQuarantine     *service.ReportQuarantine
+	ExclusionRule        *service.ExclusionRule
+	OutlierDetection     *service.OutlierDetection
+	EventSummary         *service.EventSummary
+	DropReportTask       *service.DropReportTask
+}
+
+// Worker polls the due jobs of every registered kind and runs them. It runs on every instance; jobs are
+// claimed atomically so that each attempt is run by a single instance.
+type Worker struct {
+	interval    time.Duration
+	concurrency int
+
+	stop chan struct{}
+	// running tracks the jobs in progress, which are waited for upon shutdown
+	running sync.WaitGroup
+	// runningKinds counts the jobs in progress by kind, as singleton jobs are run one at a time
+	runningKinds sync.Map
+
+	WorkerDeps
+}
+
+func Start(conf *appconfig.Config, deps WorkerDeps, lc fx.Lifecycle) {
+	if conf.JobPollInterval <= 0 {
+		log.Info().
+			Str("evt.name", "worker.jobwkr.disabled").
+			Msg("job worker is disabled due to configuration")
+		return
+	}
+
+	w := &Worker{
+		interval:    conf.JobPollInterval,
+		concurrency: conf.JobConcurrency,
+		stop:        make(chan struct{}),
+		WorkerDeps:  deps,
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go w.run()
+			return nil
+		},
+		OnStop: w.drain,
+	})
+}
+
+func (w *Worker) run() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.poll()
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+func (w *Worker) poll() {
+	ctx, cancel := context.WithTimeout(context.Background(), w.interval)
+	defer cancel()
+
+	if err := w.Jobs.EnqueuePeriodic(ctx); err != nil {
+		log.Warn().
+			Str("evt.name", "worker.jobwkr.periodic").
+			Err(err).
+			Msg("failed to enqueue periodic jobs")
+	}
+
+	for _, kind := range w.Jobs.Kinds() {
+		if w.Jobs.Singleton(kind) && w.runningCount(kind) > 0 {
+			continue
+		}
+		jobs, err := w.Jobs.ClaimDueJobs(ctx, kind, w.concurrency)
+		if err != nil {
+			log.Error().
+				Str("evt.name", "worker.jobwkr.claim").
+				Str("kind", kind).
+				Err(err).
+				Msg("failed to claim due jobs")
+			continue
+		}
+		for _, job := range jobs {
+			job := job
+			w.running.Add(1)
+			w.addRunning(job.Kind, 1)
+			go func() {
+				defer w.running.Done()
+				defer w.addRunning(job.Kind, -1)
+				w.Jobs.Run(job)
+			}()
+		}
+	}
+}
+
+func (w *Worker) runningCount(kind string) int32 {
+	count, ok := w.runningKinds.Load(kind)
+	if !ok {
+		return 0
+	}
+	return count.(*atomic.Int32).Load()
+}
+
+func (w *Worker) addRunning(kind string, delta int32) {
+	count, _ := w.runningKinds.LoadOrStore(kind, new(atomic.Int32))
+	count.(*atomic.Int32).Add(delta)
+}
+
+// drain stops claiming jobs and waits for the ones in progress. Should it time out, the jobs left are
+// claimed again by another instance once their lease expires.
+func (w *Worker) drain(ctx context.Context) error {
+	close(w.stop)
+
+	done := make(chan struct{})
+	go func() {
+		w.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "failed to wait for the jobs in progress")
+	}
+}
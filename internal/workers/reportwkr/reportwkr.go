@@ -2,8 +2,10 @@ package reportwkr
 
 import (
 	"context"
+	"fmt"
 	"runtime"
 	"strconv"
+	"sync"
 	"time"
 
 	"exusiai.dev/gommon/constant"
@@ -14,6 +16,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/uptrace/bun"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 	"go.opentelemetry.io/otel/trace"
@@ -35,25 +38,79 @@ var tracer = otel.Tracer("reportwkr")
 
 type WorkerDeps struct {
 	fx.In
-	DB                     *bun.DB
-	Redis                  *redis.Client
-	NatsJS                 nats.JetStreamContext
-	StageService           *service.Stage
-	DropReportRepo         *repo.DropReport
-	DropPatternRepo        *repo.DropPattern
-	DropReportExtraRepo    *repo.DropReportExtra
-	DropPatternElementRepo *repo.DropPatternElement
-	ReportVerifier         *reportverifs.ReportVerifiers
+	DB                  *bun.DB
+	Redis               *redis.Client
+	NatsJS              nats.JetStreamContext
+	StageService        *service.Stage
+	DropReportRepo      *repo.DropReport
+	DropPatternRepo     *repo.DropPattern
+	DropReportExtraRepo *repo.DropReportExtra
+	DropReportTaskRepo  *repo.DropReportTask
+	ReportVerifier      *reportverifs.ReportVerifiers
+	SiteCounter         *service.SiteCounter
+	Freshness           *service.Freshness
+	DailyRollups        *service.DropReportDailyRollups
 }
 
+const (
+	// maxAckPending is the most report tasks a consumer holds without having acked them, which bounds the batches
+	maxAckPending = 128
+	// ackWait is how long the server waits for a report task to be acked before redelivering it
+	ackWait = time.Second * 10
+	// inProgressInterval is how often the server is told the tasks are still being worked on, well within ackWait
+	inProgressInterval = ackWait / 2
+)
+
 type Worker struct {
 	// count is the number of workers
 	count int
 
+	// batchSize is the most report tasks written within one transaction
+	batchSize int
+	// batchWindow is how long the first task of a batch waits for the others before the batch is written
+	batchWindow time.Duration
+
+	// stop is closed upon shutdown for the consumers to drain the report tasks already received
+	stop chan struct{}
+	// consumers tracks the consumers which have not finished draining yet
+	consumers sync.WaitGroup
+
 	WorkerDeps
 }
 
-func Start(conf *appconfig.Config, deps WorkerDeps) {
+// pendingTask is a report task received by a consumer, which waits for the batch it is in to be written
+type pendingTask struct {
+	msg        *nats.Msg
+	task       *types.ReportTask
+	violations reportverifs.Violations
+	start      time.Time
+
+	// span traces the task from its receipt until its batch is written
+	span trace.Span
+	// done is closed once the task is finished, which stops telling the server it is still being worked on
+	done chan struct{}
+	// duplicate is set when the task had been persisted by an earlier delivery of it already
+	duplicate bool
+}
+
+// keepInProgress tells the server the task is still being worked on until it is finished, for it not to be
+// redelivered however long its batch, and the batch written over again task by task upon failure, take
+func (t *pendingTask) keepInProgress() {
+	ticker := time.NewTicker(inProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := t.msg.InProgress(); err != nil {
+				log.Error().Err(err).Str("taskId", t.task.TaskID).Msg("failed to set msg InProgress")
+			}
+		case <-t.done:
+			return
+		}
+	}
+}
+
+func Start(conf *appconfig.Config, deps WorkerDeps, lc fx.Lifecycle) {
 	ch := make(chan error)
 	// handle & dump errors from workers
 	go func() {
@@ -66,13 +123,19 @@ func Start(conf *appconfig.Config, deps WorkerDeps) {
 	}()
 	// works like a consumer factory
 	reportWorkers := &Worker{
-		count:      0,
-		WorkerDeps: deps,
+		count:       0,
+		batchSize:   conf.ReportBatchSize,
+		batchWindow: conf.ReportBatchWindow,
+		stop:        make(chan struct{}),
+		WorkerDeps:  deps,
 	}
+	reportWorkers.checkConfig()
 	// spawn workers
 	// maybe we should specify the number of worker in appconfig.Config ?
 	for i := 0; i < runtime.NumCPU(); i++ {
+		reportWorkers.consumers.Add(1)
 		go func() {
+			defer reportWorkers.consumers.Done()
 			err := reportWorkers.Consumer(context.Background(), ch)
 			if err != nil {
 				ch <- err
@@ -81,24 +144,103 @@ func Start(conf *appconfig.Config, deps WorkerDeps) {
 		// update current worker count
 		reportWorkers.count += 1
 	}
+
+	lc.Append(fx.Hook{
+		OnStop: reportWorkers.drain,
+	})
+}
+
+func (w *Worker) checkConfig() {
+	if w.batchSize < 1 || w.batchSize > maxAckPending {
+		panic(fmt.Sprintf("report batch size must be within 1 and %d", maxAckPending))
+	}
+	if w.batchWindow < 0 {
+		panic("report batch window cannot be negative")
+	}
 }
 
+// drain stops the consumers from receiving new report tasks and waits for the ones already received to be
+// processed, which would otherwise only be redelivered to another instance after their ack wait.
+func (w *Worker) drain(ctx context.Context) error {
+	close(w.stop)
+
+	drained := make(chan struct{})
+	go func() {
+		w.consumers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		log.Info().
+			Str("evt.name", "reportwkr.drained").
+			Msg("report tasks drained")
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "failed to drain report tasks")
+	}
+}
+
+// Consumer receives the report tasks and writes them in batches, each within one transaction. A batch is written
+// once it is full or once batchWindow has passed since its first task was received, whichever comes first.
 func (w *Worker) Consumer(ctx context.Context, ch chan error) error {
 	msgChan := make(chan *nats.Msg, 512)
 
-	_, err := w.NatsJS.ChanQueueSubscribe("REPORT.*", "penguin-reports", msgChan, nats.AckWait(time.Second*10), nats.MaxAckPending(128))
+	sub, err := w.NatsJS.ChanQueueSubscribe("REPORT.*", "penguin-reports", msgChan, nats.AckWait(ackWait), nats.MaxAckPending(maxAckPending))
 	if err != nil {
 		log.Err(err).Msg("failed to subscribe to REPORT.*")
 		return err
 	}
 
+	batch := make([]*pendingTask, 0, w.batchSize)
+	window := time.NewTimer(w.batchWindow)
+	window.Stop()
+	flush := func() {
+		if !window.Stop() {
+			// the window may have passed while the batch got full
+			select {
+			case <-window.C:
+			default:
+			}
+		}
+		if err := w.flush(ctx, batch); err != nil {
+			ch <- err
+		}
+		batch = make([]*pendingTask, 0, w.batchSize)
+	}
+	add := func(msg *nats.Msg) {
+		task, err := w.receive(ctx, msg)
+		if err != nil {
+			log.Err(err).Msg("failed to receive report task")
+			ch <- err
+			return
+		}
+		batch = append(batch, task)
+		if len(batch) >= w.batchSize {
+			flush()
+		} else if len(batch) == 1 {
+			window.Reset(w.batchWindow)
+		}
+	}
+
 	for {
 		select {
 		case msg := <-msgChan:
-			err = w.ingestPreprocess(ctx, msg)
-			if err != nil {
-				log.Err(err).Msg("failed to ingest preprocess")
-				ch <- err
+			add(msg)
+		case <-window.C:
+			flush()
+		case <-w.stop:
+			if err := sub.Unsubscribe(); err != nil {
+				log.Warn().Err(err).Msg("failed to unsubscribe from REPORT.*")
+			}
+			for {
+				select {
+				case msg := <-msgChan:
+					add(msg)
+				default:
+					flush()
+					return nil
+				}
 			}
 		case <-ctx.Done():
 			return ctx.Err()
@@ -106,43 +248,26 @@ func (w *Worker) Consumer(ctx context.Context, ch chan error) error {
 	}
 }
 
-func (w *Worker) ingestPreprocess(ctx context.Context, msg *nats.Msg) error {
-	defer func() {
-		if err := msg.Ack(); err != nil {
-			log.Error().Err(err).Msg("failed to ack")
-		}
-	}()
-
-	taskCtx, cancelTask := context.WithTimeout(ctx, time.Second*10)
-	defer cancelTask()
-
-	inprogressInformer := time.AfterFunc(time.Second*5, func() {
-		err := msg.InProgress()
-		if err != nil {
-			log.Error().Err(err).Msg("failed to set msg InProgress")
-		}
-	})
-	defer inprogressInformer.Stop()
+// receive verifies the report task of the message and starts tracing it, for it to be written along with its batch.
+// A message of which the task cannot be read is acked right away.
+func (w *Worker) receive(ctx context.Context, msg *nats.Msg) (*pendingTask, error) {
+	start := time.Now()
 
 	reportTask := &types.ReportTask{}
 	if err := json.Unmarshal(msg.Data, reportTask); err != nil {
-		return err
+		ack(msg)
+		return nil, err
 	}
 
-	start := time.Now()
-	defer observability.ReportConsumeDuration.
-		WithLabelValues().
-		Observe(time.Since(start).Seconds())
-
 	metadata, err := msg.Metadata()
 	if err != nil {
 		// should not happen: the message should be always a jetstream message
-		return err
+		ack(msg)
+		return nil, err
 	}
 
-	var span trace.Span
-	taskCtx, span = tracer.
-		Start(taskCtx, "reportwkr.ConsumeTask",
+	taskCtx, span := tracer.
+		Start(ctx, "reportwkr.ConsumeTask",
 			trace.WithSpanKind(trace.SpanKindConsumer),
 			trace.WithAttributes(
 				semconv.MessagingSystemKey.String("nats"),
@@ -151,32 +276,6 @@ func (w *Worker) ingestPreprocess(ctx context.Context, msg *nats.Msg) error {
 				semconv.MessagingMessagePayloadSizeBytesKey.Int(len(msg.Data)),
 			))
 
-	err = w.process(taskCtx, reportTask)
-	if err != nil {
-		log.Error().
-			Err(err).
-			Str("taskId", reportTask.TaskID).
-			Interface("reportTask", reportTask).
-			Msg("failed to consume report task")
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		span.End()
-		return err
-	}
-	span.SetStatus(codes.Ok, "")
-	span.End()
-
-	log.Info().
-		Str("evt.name", "reportwkr.processed").
-		Str("taskId", reportTask.TaskID).
-		Interface("task", reportTask).
-		Dur("duration", time.Since(start)).
-		Msg("report task processed successfully")
-
-	return nil
-}
-
-func (w *Worker) process(ctx context.Context, reportTask *types.ReportTask) error {
 	L := log.With().
 		Interface("task", reportTask).
 		Logger()
@@ -186,14 +285,14 @@ func (w *Worker) process(ctx context.Context, reportTask *types.ReportTask) erro
 		Msg("received report task: processing")
 
 	// reportTask.CreatedAt is in microseconds
-	taskCreatedAt := time.UnixMicro(reportTask.CreatedAt)
-
 	observability.ReportConsumeMessagingLatency.
 		WithLabelValues().
-		Observe(time.Since(taskCreatedAt).Seconds())
+		Observe(time.Since(time.UnixMicro(reportTask.CreatedAt)).Seconds())
 
+	verifyCtx, cancelVerify := context.WithTimeout(taskCtx, time.Second*10)
+	defer cancelVerify()
 	verifyCtx, verifySpan := tracer.
-		Start(ctx, "reportwkr.process.Verify",
+		Start(verifyCtx, "reportwkr.process.Verify",
 			trace.WithSpanKind(trace.SpanKindInternal))
 
 	violations := w.ReportVerifier.Verify(verifyCtx, reportTask)
@@ -206,9 +305,101 @@ func (w *Worker) process(ctx context.Context, reportTask *types.ReportTask) erro
 
 	verifySpan.End()
 
+	task := &pendingTask{
+		msg:        msg,
+		task:       reportTask,
+		violations: violations,
+		start:      start,
+		span:       span,
+		done:       make(chan struct{}),
+	}
+	go task.keepInProgress()
+	return task, nil
+}
+
+// flush writes the batch within one transaction, and acks its tasks. As a single task failing rolls the whole batch
+// back, the tasks of a failed batch are written over again one by one, for the others not to be lost along with it.
+func (w *Worker) flush(ctx context.Context, batch []*pendingTask) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := w.write(ctx, batch)
+	if err == nil || len(batch) == 1 {
+		for _, task := range batch {
+			w.finish(task, err)
+		}
+		return err
+	}
+
+	log.Warn().
+		Err(err).
+		Str("evt.name", "reportwkr.batch.failed").
+		Int("tasks", len(batch)).
+		Msg("failed to write report batch: writing the tasks one by one")
+
+	var lastErr error
+	for _, task := range batch {
+		err := w.write(ctx, []*pendingTask{task})
+		w.finish(task, err)
+		if err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// finish records the outcome of the task and acks it, whether it has been written or not
+func (w *Worker) finish(task *pendingTask, err error) {
+	defer ack(task.msg)
+	close(task.done)
+
+	observability.ReportConsumeDuration.
+		WithLabelValues().
+		Observe(time.Since(task.start).Seconds())
+
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("taskId", task.task.TaskID).
+			Interface("reportTask", task.task).
+			Msg("failed to consume report task")
+		task.span.RecordError(err)
+		task.span.SetStatus(codes.Error, err.Error())
+		task.span.End()
+		return
+	}
+	task.span.SetStatus(codes.Ok, "")
+	task.span.End()
+
+	log.Info().
+		Str("evt.name", "reportwkr.processed").
+		Str("taskId", task.task.TaskID).
+		Interface("task", task.task).
+		Dur("duration", time.Since(task.start)).
+		Msg("report task processed successfully")
+}
+
+func ack(msg *nats.Msg) {
+	if err := msg.Ack(); err != nil {
+		log.Error().Err(err).Msg("failed to ack")
+	}
+}
+
+// write persists the reports of the tasks within one transaction
+func (w *Worker) write(ctx context.Context, batch []*pendingTask) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	links := make([]trace.Link, 0, len(batch))
+	for _, task := range batch {
+		links = append(links, trace.Link{SpanContext: task.span.SpanContext()})
+	}
 	pstCtx, pstSpan := tracer.
 		Start(ctx, "reportwkr.process.Persistence",
-			trace.WithSpanKind(trace.SpanKindInternal))
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithLinks(links...),
+			trace.WithAttributes(attribute.Int("tasks", len(batch))))
 	defer pstSpan.End()
 
 	tx, err := w.DB.BeginTx(pstCtx, nil)
@@ -218,31 +409,87 @@ func (w *Worker) process(ctx context.Context, reportTask *types.ReportTask) erro
 	intendedCommit := false
 	defer func() {
 		if !intendedCommit {
-			L.Warn().Msg("rolling back transaction due to error")
+			log.Warn().Int("tasks", len(batch)).Msg("rolling back transaction due to error")
 			if err := tx.Rollback(); err != nil {
-				L.Error().Err(err).Msg("failed to rollback transaction")
+				log.Error().Err(err).Msg("failed to rollback transaction")
 			}
 		}
 	}()
 
+	dropReports := make([]*model.DropReport, 0, len(batch))
+	for _, task := range batch {
+		created, claimed, err := w.persist(pstCtx, tx, task.task, task.violations)
+		if err != nil {
+			return errors.Wrapf(err, "failed to persist report task %s", task.task.TaskID)
+		}
+		task.duplicate = !claimed
+		if task.duplicate {
+			log.Warn().
+				Str("evt.name", "reportwkr.duplicate").
+				Str("taskId", task.task.TaskID).
+				Msg("report task redelivered after being persisted: skipping")
+			continue
+		}
+		dropReports = append(dropReports, created...)
+	}
+
+	if err := w.DailyRollups.AddReports(pstCtx, tx, dropReports); err != nil {
+		return errors.Wrap(err, "failed to add drop reports to daily rollups")
+	}
+
+	intendedCommit = true
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	// counters are best-effort: the reports have already been persisted at this point
+	for _, task := range batch {
+		if task.duplicate {
+			continue
+		}
+		reportTask := task.task
+		accepted := len(reportTask.Reports) - len(task.violations)
+		if err := w.SiteCounter.RecordReports(ctx, reportTask.Server, reportTask.AccountID, len(reportTask.Reports), accepted, time.UnixMicro(reportTask.CreatedAt)); err != nil {
+			log.Warn().Err(err).Str("taskId", reportTask.TaskID).Msg("failed to record reports in site counters")
+		}
+		if accepted > 0 {
+			if err := w.Freshness.MarkReportAccepted(ctx, reportTask.Server, time.UnixMicro(reportTask.CreatedAt)); err != nil {
+				log.Warn().Err(err).Str("taskId", reportTask.TaskID).Msg("failed to record the freshness of the accepted reports")
+			}
+		}
+	}
+
+	return nil
+}
+
+// persist creates the reports of the task within tx, returning them. The task is claimed by its ID first, and not
+// persisted again should it have been claimed by an earlier delivery of it, in which case false is returned.
+func (w *Worker) persist(ctx context.Context, tx bun.Tx, reportTask *types.ReportTask, violations reportverifs.Violations) ([]*model.DropReport, bool, error) {
+	claimed, err := w.DropReportTaskRepo.ClaimDropReportTask(ctx, tx, reportTask.TaskID)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to claim report task")
+	}
+	if !claimed {
+		return nil, false, nil
+	}
+
+	// reportTask.CreatedAt is in microseconds
+	taskCreatedAt := time.UnixMicro(reportTask.CreatedAt)
+
+	dropReports := make([]*model.DropReport, 0, len(reportTask.Reports))
+
 	// calculate drop pattern hash for each report
 	for idx, report := range reportTask.Reports {
 		report.Drops = reportutil.MergeDropsByItemID(report.Drops)
 
-		dropPattern, created, err := w.DropPatternRepo.GetOrCreateDropPatternFromDrops(pstCtx, tx, report.Drops)
+		dropPattern, _, err := w.DropPatternRepo.GetOrCreateDropPatternFromDrops(ctx, tx, report.Drops)
 		if err != nil {
-			return errors.Wrap(err, "failed to calculate drop pattern hash")
-		}
-		if created {
-			_, err := w.DropPatternElementRepo.CreateDropPatternElements(pstCtx, tx, dropPattern.PatternID, report.Drops)
-			if err != nil {
-				return errors.Wrap(err, "failed to create drop pattern elements")
-			}
+			return nil, false, errors.Wrap(err, "failed to resolve drop pattern")
 		}
 
-		stage, err := w.StageService.GetStageByArkId(pstCtx, report.StageID)
+		stage, err := w.StageService.GetStageByArkId(ctx, report.StageID)
 		if err != nil {
-			return errors.Wrap(err, "failed to get stage")
+			return nil, false, errors.Wrap(err, "failed to get stage")
 		}
 
 		reliability := violations.Reliability(idx)
@@ -258,9 +505,10 @@ func (w *Worker) process(ctx context.Context, reportTask *types.ReportTask) erro
 			SourceName:  reportTask.Source,
 			Version:     reportTask.Version,
 		}
-		if err = w.DropReportRepo.CreateDropReport(pstCtx, tx, dropReport); err != nil {
-			return errors.Wrap(err, "failed to create drop report")
+		if err = w.DropReportRepo.CreateDropReport(ctx, tx, dropReport); err != nil {
+			return nil, false, errors.Wrap(err, "failed to create drop report")
 		}
+		dropReports = append(dropReports, dropReport)
 
 		observability.ReportReliability.WithLabelValues(strconv.Itoa(reliability), reportTask.Source).Inc()
 
@@ -278,24 +526,19 @@ func (w *Worker) process(ctx context.Context, reportTask *types.ReportTask) erro
 
 			reportTask.IP = "127.0.0.1"
 		}
-		if err = w.DropReportExtraRepo.CreateDropReportExtra(pstCtx, tx, &model.DropReportExtra{
+		if err = w.DropReportExtraRepo.CreateDropReportExtra(ctx, tx, &model.DropReportExtra{
 			ReportID: dropReport.ReportID,
 			IP:       reportTask.IP,
 			Metadata: report.Metadata,
 			MD5:      null.NewString(md5, md5 != ""),
 		}); err != nil {
-			return errors.Wrap(err, "failed to create drop report extra")
+			return nil, false, errors.Wrap(err, "failed to create drop report extra")
 		}
 
-		if err := w.Redis.Set(pstCtx, constant.ReportRedisPrefix+reportTask.TaskID, dropReport.ReportID, time.Hour*24).Err(); err != nil {
-			return errors.Wrap(err, "failed to set report id in redis")
+		if err := w.Redis.Set(ctx, constant.ReportRedisPrefix+reportTask.TaskID, dropReport.ReportID, time.Hour*24).Err(); err != nil {
+			return nil, false, errors.Wrap(err, "failed to set report id in redis")
 		}
 	}
 
-	intendedCommit = true
-	if err := tx.Commit(); err != nil {
-		return errors.Wrap(err, "failed to commit transaction")
-	}
-
-	return nil
+	return dropReports, true, nil
 }
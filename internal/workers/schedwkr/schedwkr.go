@@ -0,0 +1,217 @@
+package schedwkr
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/repo"
+	"exusiai.dev/backend-next/internal/service"
+)
+
+type WorkerDeps struct {
+	fx.In
+
+	TimeRangeService *service.TimeRange
+	DropInfoService  *service.DropInfo
+	StageService     *service.Stage
+	WebhookService   *service.Webhook
+	DropInfoRepo     *repo.DropInfo
+}
+
+// Worker activates scheduled time ranges. As caches are in-memory, it runs on every instance
+// and therefore does not take any distributed lock. The webhook events it publishes are
+// deduplicated by the time range, so subscribers receive them only once.
+type Worker struct {
+	interval time.Duration
+
+	// stop cancels the context of the activations upon shutdown, after which done is closed once run has returned
+	stop context.CancelFunc
+	done chan struct{}
+
+	WorkerDeps
+}
+
+func Start(lc fx.Lifecycle, conf *appconfig.Config, deps WorkerDeps) {
+	if conf.TimeRangeActivationInterval <= 0 {
+		log.Info().
+			Str("evt.name", "worker.schedwkr.disabled").
+			Msg("scheduled time range activation is disabled due to configuration")
+		return
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	w := &Worker{
+		interval:   conf.TimeRangeActivationInterval,
+		stop:       stop,
+		done:       make(chan struct{}),
+		WorkerDeps: deps,
+	}
+	go w.run(ctx)
+
+	lc.Append(fx.Hook{
+		OnStop: w.shutdown,
+	})
+}
+
+// shutdown stops the worker and waits for the activation in progress, if any, to return
+func (w *Worker) shutdown(ctx context.Context) error {
+	w.stop()
+
+	select {
+	case <-w.done:
+		log.Info().
+			Str("evt.name", "worker.schedwkr.stopped").
+			Msg("scheduled time range activation stopped")
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "failed to stop scheduled time range activation")
+	}
+}
+
+func (w *Worker) run(baseCtx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	since := time.Now()
+	for {
+		var until time.Time
+		select {
+		case <-baseCtx.Done():
+			return
+		case until = <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(baseCtx, w.interval)
+		activated, err := w.TimeRangeService.ActivateScheduledTimeRanges(ctx, since, until)
+		cancel()
+		if err != nil {
+			// keep since unchanged so that the boundaries are checked again on the next tick
+			log.Error().
+				Str("evt.name", "worker.schedwkr.activate").
+				Err(err).
+				Msg("failed to activate scheduled time ranges")
+			continue
+		}
+		since = until
+
+		for _, timeRange := range activated {
+			log.Info().
+				Str("evt.name", "worker.schedwkr.activate").
+				Int("rangeId", timeRange.RangeID).
+				Str("server", timeRange.Server).
+				Str("timeRange", timeRange.String()).
+				Msg("time range boundary reached; caches purged")
+		}
+
+		if len(activated) > 0 {
+			ctx, cancel := context.WithTimeout(baseCtx, w.interval)
+			if err := w.publishWebhookEvents(ctx, until, activated); err != nil {
+				log.Error().
+					Str("evt.name", "worker.schedwkr.webhook").
+					Err(err).
+					Msg("failed to publish webhook events for time range boundaries")
+			}
+			cancel()
+		}
+	}
+}
+
+type timeRangeOpenedEvent struct {
+	RangeID   int        `json:"rangeId"`
+	Server    string     `json:"server"`
+	Name      string     `json:"name,omitempty"`
+	StartTime *time.Time `json:"startTime"`
+	EndTime   *time.Time `json:"endTime"`
+	// StageIDs are the ark stage ids of the stages that drop within the time range
+	StageIDs []string `json:"stageIds"`
+}
+
+type stageClosedEvent struct {
+	Server   string     `json:"server"`
+	StageID  string     `json:"stageId"`
+	RangeID  int        `json:"rangeId"`
+	ClosedAt *time.Time `json:"closedAt"`
+}
+
+// publishWebhookEvents publishes timerange.opened for the time ranges that started, and stage.closed
+// for the stages of the time ranges that ended and are not open in any other time range.
+func (w *Worker) publishWebhookEvents(ctx context.Context, now time.Time, activated []*model.TimeRange) error {
+	stagesMap, err := w.StageService.GetStagesMapById(ctx)
+	if err != nil {
+		return err
+	}
+	arkStageIdsOf := func(dropInfos []*model.DropInfo) []string {
+		ids := make([]string, 0)
+		seen := make(map[int]struct{})
+		for _, dropInfo := range dropInfos {
+			if _, ok := seen[dropInfo.StageID]; ok {
+				continue
+			}
+			seen[dropInfo.StageID] = struct{}{}
+			if stage, ok := stagesMap[dropInfo.StageID]; ok {
+				ids = append(ids, stage.ArkStageID)
+			}
+		}
+		return ids
+	}
+
+	for _, timeRange := range activated {
+		dropInfos, err := w.DropInfoRepo.GetDropInfosByServerAndRangeId(ctx, timeRange.Server, timeRange.RangeID)
+		if err != nil {
+			return err
+		}
+
+		// a time range still including now has crossed its start, otherwise its end
+		if timeRange.Includes(now) {
+			err := w.WebhookService.Publish(ctx, model.WebhookEventTimeRangeOpened, strconv.Itoa(timeRange.RangeID), &timeRangeOpenedEvent{
+				RangeID:   timeRange.RangeID,
+				Server:    timeRange.Server,
+				Name:      timeRange.Name.String,
+				StartTime: timeRange.StartTime,
+				EndTime:   timeRange.EndTime,
+				StageIDs:  arkStageIdsOf(dropInfos),
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		currentDropInfos, err := w.DropInfoService.GetCurrentDropInfosByServer(ctx, timeRange.Server)
+		if err != nil {
+			return err
+		}
+		open := make(map[int]struct{}, len(currentDropInfos))
+		for _, dropInfo := range currentDropInfos {
+			open[dropInfo.StageID] = struct{}{}
+		}
+
+		closedDropInfos := make([]*model.DropInfo, 0)
+		for _, dropInfo := range dropInfos {
+			if _, ok := open[dropInfo.StageID]; !ok {
+				closedDropInfos = append(closedDropInfos, dropInfo)
+			}
+		}
+		for _, stageId := range arkStageIdsOf(closedDropInfos) {
+			err := w.WebhookService.Publish(ctx, model.WebhookEventStageClosed, timeRange.Server+":"+stageId+":"+strconv.Itoa(timeRange.RangeID), &stageClosedEvent{
+				Server:   timeRange.Server,
+				StageID:  stageId,
+				RangeID:  timeRange.RangeID,
+				ClosedAt: timeRange.EndTime,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package warmwkr
+
+import (
+	"context"
+	"time"
+
+	"exusiai.dev/gommon/constant"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+	"gopkg.in/guregu/null.v3"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/service"
+)
+
+type WorkerDeps struct {
+	fx.In
+
+	HealthService        *service.Health
+	Jobs                 *service.Jobs
+	DropMatrixService    *service.DropMatrix
+	PatternMatrixService *service.PatternMatrix
+	TrendService         *service.Trend
+	ItemService          *service.Item
+	StageService         *service.Stage
+	ZoneService          *service.Zone
+}
+
+// Worker warms up the in-memory caches of the global results upon startup, which would otherwise take
+// the first requests tens of seconds to fill. The instance reports itself ready once it is done.
+type Worker struct {
+	timeout time.Duration
+
+	WorkerDeps
+}
+
+func Start(conf *appconfig.Config, deps WorkerDeps) {
+	if !conf.CacheWarmUpEnabled {
+		log.Info().
+			Str("evt.name", "worker.warmwkr.disabled").
+			Msg("cache warm up is disabled due to configuration")
+		deps.HealthService.MarkCachesWarm()
+		return
+	}
+
+	w := &Worker{
+		timeout:    conf.CacheWarmUpTimeout,
+		WorkerDeps: deps,
+	}
+	go w.run()
+}
+
+func (w *Worker) run() {
+	// the instance becomes ready even if warming up fails, as the caches are filled upon requests anyway
+	defer w.HealthService.MarkCachesWarm()
+
+	// the caches are those of this instance: the job is run inline rather than by the job worker
+	err := w.Jobs.RunInline(context.Background(), model.JobKindCacheWarm, service.JobPolicy{
+		MaxAttempts: 1,
+		Timeout:     w.timeout,
+	}, w.warm)
+	if err != nil {
+		log.Warn().
+			Str("evt.name", "worker.warmwkr.failed").
+			Err(err).
+			Msg("failed to warm up some caches")
+	}
+}
+
+func (w *Worker) warm(ctx context.Context, _ *model.Job) error {
+	start := time.Now()
+	failed := 0
+	warm := func(name string, server string, f func() error) {
+		if err := f(); err != nil {
+			failed++
+			log.Warn().
+				Str("evt.name", "worker.warmwkr.warm").
+				Str("cache", name).
+				Str("server", server).
+				Err(err).
+				Msg("failed to warm up cache")
+		}
+	}
+
+	warm("items", "", func() error {
+		_, err := w.ItemService.GetShimItems(ctx)
+		return err
+	})
+	warm("zones", "", func() error {
+		_, err := w.ZoneService.GetShimZones(ctx)
+		return err
+	})
+
+	// servers are warmed up one after another not to flood the database upon deployments
+	for _, server := range constant.Servers {
+		server := server
+		warm("stages", server, func() error {
+			_, err := w.StageService.GetShimStages(ctx, server)
+			return err
+		})
+		for _, showClosedZones := range []bool{false, true} {
+			showClosedZones := showClosedZones
+			warm("dropMatrix", server, func() error {
+				_, err := w.DropMatrixService.GetShimDropMatrix(ctx, server, showClosedZones, "", "", null.NewInt(0, false), constant.SourceCategoryAll)
+				return err
+			})
+		}
+		warm("patternMatrix", server, func() error {
+			_, err := w.PatternMatrixService.GetShimPatternMatrix(ctx, server, null.NewInt(0, false), constant.SourceCategoryAll, false)
+			return err
+		})
+		warm("trend", server, func() error {
+			_, err := w.TrendService.GetShimTrend(ctx, server)
+			return err
+		})
+	}
+
+	log.Info().
+		Str("evt.name", "worker.warmwkr.done").
+		Dur("took", time.Since(start)).
+		Msg("caches warmed up")
+
+	if failed > 0 {
+		return errors.Errorf("%d caches failed to warm up", failed)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package webhookwkr
+
+import (
+	"context"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+
+	"exusiai.dev/backend-next/internal/app/appconfig"
+	"exusiai.dev/backend-next/internal/model"
+	"exusiai.dev/backend-next/internal/service"
+)
+
+const (
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = time.Hour
+)
+
+type WorkerDeps struct {
+	fx.In
+
+	NatsJS         nats.JetStreamContext
+	WebhookService *service.Webhook
+}
+
+// Worker fans out the published events to the subscribed webhooks, and delivers them with exponential
+// backoff on failures. It runs on every instance, as the JetStream queue groups ensure each message is
+// handled by a single instance.
+type Worker struct {
+	timeout     time.Duration
+	maxAttempts int
+
+	WorkerDeps
+}
+
+func Start(conf *appconfig.Config, deps WorkerDeps) error {
+	w := &Worker{
+		timeout:     conf.WebhookDeliveryTimeout,
+		maxAttempts: conf.WebhookDeliveryMaxAttempts,
+		WorkerDeps:  deps,
+	}
+
+	_, err := w.NatsJS.QueueSubscribe(service.WebhookEventSubject, "penguin-webhook-events", w.fanOut,
+		nats.ManualAck(), nats.AckWait(time.Minute), nats.MaxDeliver(w.maxAttempts))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to subscribe to " + service.WebhookEventSubject)
+		return err
+	}
+
+	_, err = w.NatsJS.QueueSubscribe(service.WebhookDeliverySubject, "penguin-webhook-deliveries", w.deliver,
+		nats.ManualAck(), nats.AckWait(w.timeout*2), nats.MaxDeliver(w.maxAttempts))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to subscribe to " + service.WebhookDeliverySubject)
+		return err
+	}
+
+	return nil
+}
+
+func (w *Worker) fanOut(msg *nats.Msg) {
+	var event model.WebhookEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		log.Error().Err(err).Str("evt.name", "worker.webhookwkr.fanout").Msg("malformed webhook event; dropping")
+		w.term(msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := w.WebhookService.FanOut(ctx, &event); err != nil {
+		log.Error().
+			Err(err).
+			Str("evt.name", "worker.webhookwkr.fanout").
+			Str("eventId", event.ID).
+			Str("eventType", event.Type).
+			Msg("failed to fan out webhook event")
+		w.retry(msg)
+		return
+	}
+
+	if err := msg.Ack(); err != nil {
+		log.Error().Err(err).Msg("failed to ack")
+	}
+}
+
+func (w *Worker) deliver(msg *nats.Msg) {
+	var delivery model.WebhookDelivery
+	if err := json.Unmarshal(msg.Data, &delivery); err != nil || delivery.Event == nil {
+		log.Error().Err(err).Str("evt.name", "worker.webhookwkr.deliver").Msg("malformed webhook delivery; dropping")
+		w.term(msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	if err := w.WebhookService.Deliver(ctx, &delivery); err != nil {
+		log.Warn().
+			Err(err).
+			Str("evt.name", "worker.webhookwkr.deliver").
+			Int("webhookId", delivery.WebhookID).
+			Str("eventId", delivery.Event.ID).
+			Msg("failed to deliver webhook event")
+		w.retry(msg)
+		return
+	}
+
+	if err := msg.Ack(); err != nil {
+		log.Error().Err(err).Msg("failed to ack")
+	}
+}
+
+// retry redelivers the message with an exponential backoff, until the attempts are exhausted
+func (w *Worker) retry(msg *nats.Msg) {
+	metadata, err := msg.Metadata()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get msg metadata")
+		w.term(msg)
+		return
+	}
+
+	attempts := int(metadata.NumDelivered)
+	if attempts >= w.maxAttempts {
+		log.Error().
+			Str("evt.name", "worker.webhookwkr.exhausted").
+			Str("subject", msg.Subject).
+			Int("attempts", attempts).
+			Msg("giving up on webhook message after exhausting attempts")
+		w.term(msg)
+		return
+	}
+
+	delay := retryBaseDelay << (attempts - 1)
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+	if err := msg.NakWithDelay(delay); err != nil {
+		log.Error().Err(err).Msg("failed to nak")
+	}
+}
+
+func (w *Worker) term(msg *nats.Msg) {
+	if err := msg.Term(); err != nil {
+		log.Error().Err(err).Msg("failed to term")
+	}
+}
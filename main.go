@@ -23,6 +23,10 @@ import (
 //	@in							header
 //	@name						Authorization
 
+//	@securityDefinitions.apikey	APIKeyAuth
+//	@in							header
+//	@name						X-Penguin-API-Key
+
 func main() {
 	app.Run()
 }
@@ -2,25 +2,61 @@ package test
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"exusiai.dev/backend-next/internal/model"
 )
 
+// adminToken creates an account granted the role, and returns the admin token issued to it
+func adminToken(t *testing.T, role string) string {
+	t.Helper()
+
+	account, err := gAccountRepo.CreateAccountWithRandomPenguinId(context.Background())
+	require.NoError(t, err, "failed to create account")
+	result, err := gAccountRoleService.GrantRole(context.Background(), account.PenguinID, role)
+	require.NoError(t, err, "failed to grant role")
+	require.NotEmpty(t, result.Token, "no admin token issued")
+
+	return result.Token
+}
+
 // TestAdminAPI tests the admin API.
 func TestAdminAPI(t *testing.T) {
 	startup(t)
 	t.Parallel()
 
+	maintainerToken := adminToken(t, model.RoleMaintainer)
+	moderatorToken := adminToken(t, model.RoleModerator)
+
 	t.Run("items resources updated", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api/admin/recognition/items-resources/updated", bytes.NewBufferString(`{"server":"CN","prefix":"CN/testv0.0.1"}`))
-		req.Header.Set("Authorization", "Bearer "+os.Getenv("PENGUIN_V3_ADMIN_KEY"))
+		req.Header.Set("Authorization", "Bearer "+maintainerToken)
 		req.Header.Set("Content-Type", "application/json")
 
 		resp := request(t, req)
 		assert.Equal(t, http.StatusOK, resp.StatusCode, bodyString(resp))
 	})
+
+	t.Run("items resources updated without the maintainer role", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/recognition/items-resources/updated", bytes.NewBufferString(`{"server":"CN","prefix":"CN/testv0.0.1"}`))
+		req.Header.Set("Authorization", "Bearer "+moderatorToken)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp := request(t, req)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, bodyString(resp))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/recognition/items-resources/updated", bytes.NewBufferString(`{"server":"CN","prefix":"CN/testv0.0.1"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp := request(t, req)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, bodyString(resp))
+	})
 }
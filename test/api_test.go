@@ -16,6 +16,8 @@ import (
 
 	"exusiai.dev/backend-next/internal/app"
 	"exusiai.dev/backend-next/internal/app/appcontext"
+	"exusiai.dev/backend-next/internal/repo"
+	"exusiai.dev/backend-next/internal/service"
 )
 
 // testing hooks: https://pkg.go.dev/testing#hdr-Subtests_and_Sub_benchmarks
@@ -23,6 +25,9 @@ import (
 var (
 	gMu       sync.Mutex
 	gFiberApp *fiber.App
+
+	gAccountRepo        *repo.Account
+	gAccountRoleService *service.AccountRole
 )
 
 func startup(t *testing.T) {
@@ -37,7 +42,7 @@ func startup(t *testing.T) {
 
 	var fiberApp *fiber.App
 	fxApp := fxtest.New(t,
-		append(app.Options(appcontext.Declare(appcontext.EnvServer)), fx.Populate(&fiberApp))...,
+		append(app.Options(appcontext.Declare(appcontext.EnvServer)), fx.Populate(&fiberApp, &gAccountRepo, &gAccountRoleService))...,
 	)
 	fxApp.RequireStart()
 